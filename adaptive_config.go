@@ -31,6 +31,24 @@ type EnhancedAdaptiveConfig struct {
 	PersistenceFile  string // Optional: save/load state
 	IdleStateTTL     time.Duration
 	MaxModelStates   int
+
+	// Load shedding while degraded (disabled by default)
+	Shedding SheddingPolicy
+}
+
+// SheddingPolicy drains low-priority traffic first when a provider slows
+// down. A state counts as degraded while its average latency over the
+// sampling window exceeds its target latency. Request priority is read from
+// the context (see middleware.WithPriority).
+type SheddingPolicy struct {
+	Enabled bool
+	// ShedBelow is the lowest priority still admitted while degraded. The
+	// zero value (PriorityNormal) sheds only PriorityLow traffic.
+	ShedBelow middleware.Priority
+	// Defer makes low-priority requests wait instead of failing: they are
+	// admitted once the state recovers or fewer than half its slots are
+	// in use, and give up when their context ends.
+	Defer bool
 }
 
 // ProviderSetting holds provider-specific configuration
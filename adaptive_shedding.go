@@ -0,0 +1,116 @@
+package wormhole
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+)
+
+// deferPollInterval is how often a deferred request re-checks its state.
+const deferPollInterval = 10 * time.Millisecond
+
+// sheddingStats counts requests the shedding policy rejected or deferred,
+// keyed by request priority.
+type sheddingStats struct {
+	mu       sync.Mutex
+	shed     map[middleware.Priority]int64
+	deferred map[middleware.Priority]int64
+}
+
+func (s *sheddingStats) recordShed(p middleware.Priority) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shed == nil {
+		s.shed = make(map[middleware.Priority]int64)
+	}
+	s.shed[p]++
+}
+
+func (s *sheddingStats) recordDeferred(p middleware.Priority) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deferred == nil {
+		s.deferred = make(map[middleware.Priority]int64)
+	}
+	s.deferred[p]++
+}
+
+// snapshot copies the counters so callers can read them without the lock.
+func (s *sheddingStats) snapshot() (shed, deferred map[middleware.Priority]int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shed = make(map[middleware.Priority]int64, len(s.shed))
+	for p, n := range s.shed {
+		shed[p] = n
+	}
+	deferred = make(map[middleware.Priority]int64, len(s.deferred))
+	for p, n := range s.deferred {
+		deferred[p] = n
+	}
+	return shed, deferred
+}
+
+// admit applies the shedding policy before a slot is acquired from state.
+// It returns false when the request was shed or its context ended while
+// deferred.
+func (l *EnhancedAdaptiveLimiter) admit(ctx context.Context, state *ProviderAdaptiveState) bool {
+	policy := l.config.Shedding
+	if !policy.Enabled {
+		return true
+	}
+	priority := middleware.PriorityFromContext(ctx)
+	if priority >= policy.ShedBelow || !state.Degraded() {
+		return true
+	}
+	if !policy.Defer {
+		l.shedding.recordShed(priority)
+		return false
+	}
+
+	if sheddingHeadroom(state) {
+		return true
+	}
+	l.shedding.recordDeferred(priority)
+	ticker := time.NewTicker(deferPollInterval)
+	defer ticker.Stop()
+	for {
+		if !state.Degraded() || sheddingHeadroom(state) {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// sheddingHeadroom reports whether fewer than half of state's slots are in
+// use, leaving room for deferred traffic without starving interactive calls.
+func sheddingHeadroom(state *ProviderAdaptiveState) bool {
+	return state.InUse()*2 < state.Capacity()
+}
+
+// ShedCounts returns how many requests the shedding policy rejected, by
+// priority. Deferred requests that were later admitted are not included.
+func (l *EnhancedAdaptiveLimiter) ShedCounts() map[middleware.Priority]int64 {
+	shed, _ := l.shedding.snapshot()
+	return shed
+}
+
+// DeferredCounts returns how many requests the shedding policy held back
+// while degraded, by priority.
+func (l *EnhancedAdaptiveLimiter) DeferredCounts() map[middleware.Priority]int64 {
+	_, deferred := l.shedding.snapshot()
+	return deferred
+}
+
+func priorityCountsByName(counts map[middleware.Priority]int64) map[string]int64 {
+	out := make(map[string]int64, len(counts))
+	for p, n := range counts {
+		out[p.String()] = n
+	}
+	return out
+}
@@ -0,0 +1,135 @@
+package wormhole
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func newSheddingLimiter(t *testing.T, policy SheddingPolicy) *EnhancedAdaptiveLimiter {
+	t.Helper()
+	config := DefaultEnhancedAdaptiveConfig()
+	config.TargetLatency = 100 * time.Millisecond
+	config.InitialCapacity = 4
+	config.QueryInterval = 0
+	config.Shedding = policy
+	limiter := NewEnhancedAdaptiveLimiter(config)
+	t.Cleanup(limiter.Stop)
+	return limiter
+}
+
+func TestSheddingRejectsLowPriorityWhileDegraded(t *testing.T) {
+	t.Parallel()
+	limiter := newSheddingLimiter(t, SheddingPolicy{Enabled: true})
+	low := middleware.WithPriority(context.Background(), middleware.PriorityLow)
+	high := middleware.WithPriority(context.Background(), middleware.PriorityHigh)
+
+	release, ok := limiter.AcquireTokenWithProvider(low, "openai", "gpt-4o")
+	require.True(t, ok, "healthy provider admits low priority")
+	release()
+
+	limiter.RecordLatencyWithProvider(time.Second, "openai", "gpt-4o", nil)
+
+	_, ok = limiter.AcquireTokenWithProvider(low, "openai", "gpt-4o")
+	assert.False(t, ok, "degraded provider sheds low priority")
+	release, ok = limiter.AcquireTokenWithProvider(high, "openai", "gpt-4o")
+	require.True(t, ok, "interactive traffic keeps flowing")
+	release()
+	release, ok = limiter.AcquireTokenWithProvider(context.Background(), "openai", "gpt-4o")
+	require.True(t, ok, "normal priority is at the default threshold")
+	release()
+
+	release, ok = limiter.AcquireTokenWithProvider(low, "anthropic", "claude")
+	require.True(t, ok, "other providers are judged on their own latency")
+	release()
+
+	assert.Equal(t, map[middleware.Priority]int64{middleware.PriorityLow: 1}, limiter.ShedCounts())
+	assert.Equal(t, map[string]int64{"low": 1}, limiter.GetStats()["shed"])
+}
+
+func TestSheddingDefersUntilHeadroom(t *testing.T) {
+	t.Parallel()
+	limiter := newSheddingLimiter(t, SheddingPolicy{Enabled: true, Defer: true})
+	limiter.RecordLatency(time.Second)
+
+	var held []func()
+	for range 2 {
+		release, ok := limiter.AcquireToken(context.Background())
+		require.True(t, ok)
+		held = append(held, release)
+	}
+
+	low := middleware.WithPriority(context.Background(), middleware.PriorityLow)
+	admitted := make(chan bool, 1)
+	go func() {
+		release, ok := limiter.AcquireToken(low)
+		if ok {
+			release()
+		}
+		admitted <- ok
+	}()
+
+	select {
+	case <-admitted:
+		t.Fatal("low priority should wait while half the slots are busy")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	held[0]()
+	select {
+	case ok := <-admitted:
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("deferred request was never admitted")
+	}
+	held[1]()
+
+	assert.Empty(t, limiter.ShedCounts())
+	assert.Equal(t, int64(1), limiter.DeferredCounts()[middleware.PriorityLow])
+}
+
+func TestSheddingDeferGivesUpWithContext(t *testing.T) {
+	t.Parallel()
+	limiter := newSheddingLimiter(t, SheddingPolicy{Enabled: true, Defer: true})
+	limiter.RecordLatency(time.Second)
+	release, ok := limiter.AcquireToken(context.Background())
+	require.True(t, ok)
+	defer release()
+	release2, ok := limiter.AcquireToken(context.Background())
+	require.True(t, ok)
+	defer release2()
+
+	ctx, cancel := context.WithTimeout(middleware.WithPriority(context.Background(), middleware.PriorityLow), 30*time.Millisecond)
+	defer cancel()
+	_, ok = limiter.AcquireToken(ctx)
+	assert.False(t, ok)
+	assert.Error(t, ctx.Err())
+}
+
+func TestConcurrencyMiddlewareReturnsLoadShed(t *testing.T) {
+	t.Parallel()
+	limiter := newSheddingLimiter(t, SheddingPolicy{Enabled: true, ShedBelow: middleware.PriorityHigh})
+	limiter.RecordLatency(time.Second)
+
+	called := false
+	handler := middleware.ProviderAwareConcurrencyLimitMiddleware(limiter)(func(ctx context.Context, req any) (any, error) {
+		called = true
+		return "ok", nil
+	})
+
+	_, err := handler(context.Background(), nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, types.ErrLoadShed))
+	assert.False(t, called)
+
+	resp, err := handler(middleware.WithPriority(context.Background(), middleware.PriorityHigh), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
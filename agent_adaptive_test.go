@@ -3,6 +3,8 @@ package wormhole
 import (
 	"context"
 	"errors"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -161,6 +163,134 @@ func TestAgentAddTool(t *testing.T) {
 	}
 }
 
+func newAgentTestClient(provider *mockToolProvider, opts ...Option) *Wormhole {
+	base := []Option{
+		WithDefaultProvider("mock"),
+		WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) {
+			return provider, nil
+		}),
+		WithProviderConfig("mock", types.ProviderConfig{}),
+		WithDiscovery(false),
+	}
+	return New(append(base, opts...)...)
+}
+
+func agentToolStep(id string, usage types.Usage) *types.TextResponse {
+	return &types.TextResponse{
+		ToolCalls: []types.ToolCall{{ID: id, Name: "lookup", Arguments: map[string]any{}}},
+		Usage:     &usage,
+	}
+}
+
+func TestAgentBuilderTranscriptAndUsage(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockToolProvider{responses: []*types.TextResponse{
+		agentToolStep("call_1", types.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120}),
+		{Text: "done", Usage: &types.Usage{PromptTokens: 150, CompletionTokens: 30, TotalTokens: 180}},
+	}}
+	client := newAgentTestClient(provider, withTestModels(&types.ModelInfo{
+		ID:           "mock-model",
+		Provider:     "mock",
+		Capabilities: []types.ModelCapability{types.CapabilityText, types.CapabilityChat, types.CapabilityFunctions},
+		Cost:         &types.ModelCost{InputTokens: 0.01, OutputTokens: 0.1},
+	}))
+
+	result, err := client.Agent().Using("mock").Model("mock-model").System("system").
+		AddTool("lookup", "Lookup data", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+			return "ok", nil
+		}).
+		Run(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if result.Usage.TotalTokens != 300 || result.Usage.PromptTokens != 250 || result.Usage.CompletionTokens != 50 {
+		t.Fatalf("usage = %#v", result.Usage)
+	}
+	wantCost := 0.25*0.01 + 0.05*0.1
+	if math.Abs(result.Cost-wantCost) > 1e-12 || math.Abs(result.Steps[0].Cost+result.Steps[1].Cost-wantCost) > 1e-12 {
+		t.Fatalf("cost = %v, steps = %#v, want %v", result.Cost, result.Steps, wantCost)
+	}
+
+	// system, user, assistant tool call, tool result, final assistant
+	if len(result.Messages) != 5 {
+		t.Fatalf("transcript = %#v", result.Messages)
+	}
+	if _, ok := result.Messages[3].(*types.ToolResultMessage); !ok {
+		t.Fatalf("transcript[3] = %#v, want tool result", result.Messages[3])
+	}
+	if final, ok := result.Messages[4].(*types.AssistantMessage); !ok || final.Content != "done" {
+		t.Fatalf("transcript[4] = %#v, want final answer", result.Messages[4])
+	}
+}
+
+func TestAgentBuilderStopsAtLimits(t *testing.T) {
+	t.Parallel()
+
+	model := &types.ModelInfo{
+		ID:           "mock-model",
+		Provider:     "mock",
+		Capabilities: []types.ModelCapability{types.CapabilityText, types.CapabilityChat, types.CapabilityFunctions},
+		Cost:         &types.ModelCost{InputTokens: 1, OutputTokens: 1},
+	}
+	tests := []struct {
+		name      string
+		configure func(*AgentBuilder)
+		wantErr   error
+		wantSteps int
+		wantCalls int
+	}{
+		{"max steps", func(b *AgentBuilder) { b.MaxSteps(2) }, ErrAgentMaxSteps, 2, 2},
+		{"token budget", func(b *AgentBuilder) { b.TokenBudget(150) }, ErrAgentBudgetExceeded, 2, 1},
+		{"cost budget", func(b *AgentBuilder) { b.CostBudget(0.1) }, ErrAgentBudgetExceeded, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &mockToolProvider{responses: []*types.TextResponse{
+				agentToolStep("call_1", types.Usage{PromptTokens: 80, CompletionTokens: 20, TotalTokens: 100}),
+				agentToolStep("call_2", types.Usage{PromptTokens: 80, CompletionTokens: 20, TotalTokens: 100}),
+				agentToolStep("call_3", types.Usage{PromptTokens: 80, CompletionTokens: 20, TotalTokens: 100}),
+			}}
+			client := newAgentTestClient(provider, withTestModels(model))
+
+			toolCalls := 0
+			builder := client.Agent().Using("mock").Model("mock-model").MaxSteps(5).
+				AddTool("lookup", "Lookup data", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+					toolCalls++
+					return "ok", nil
+				})
+			tt.configure(builder)
+
+			result, err := builder.Run(context.Background(), "loop")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run error = %v, want %v", err, tt.wantErr)
+			}
+			if result == nil || result.TotalSteps != tt.wantSteps || len(result.Steps) != tt.wantSteps {
+				t.Fatalf("partial result = %#v, want %d steps", result, tt.wantSteps)
+			}
+			if toolCalls != tt.wantCalls {
+				t.Fatalf("tool calls = %d, want %d", toolCalls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestAgentBuilderCostBudgetNeedsPricing(t *testing.T) {
+	t.Parallel()
+
+	client := newAgentTestClient(&mockToolProvider{})
+	_, err := client.Agent().Using("mock").Model("mock-model").CostBudget(1).
+		AddTool("lookup", "Lookup data", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+			return "ok", nil
+		}).
+		Run(context.Background(), "hello")
+	if err == nil || !strings.Contains(err.Error(), "cost budget needs pricing") {
+		t.Fatalf("Run error = %v, want missing pricing", err)
+	}
+}
+
 func TestAdaptiveLimiter(t *testing.T) {
 	t.Parallel()
 
@@ -1,9 +1,20 @@
 package wormhole
 
 import (
+	"errors"
+
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
+var (
+	// ErrAgentMaxSteps is returned by AgentBuilder.Run when MaxSteps model
+	// calls end without a final response.
+	ErrAgentMaxSteps = errors.New("agent: max steps reached without final response")
+	// ErrAgentBudgetExceeded is returned by AgentBuilder.Run when the token
+	// or cost budget is spent before a final response.
+	ErrAgentBudgetExceeded = errors.New("agent: budget exceeded without final response")
+)
+
 // StepEvent provides information about each step in the agent loop.
 type StepEvent struct {
 	// Step is the 1-based step number.
@@ -15,9 +26,14 @@ type StepEvent struct {
 	// ToolCalls contains the tool calls the model wants to make (empty on final step).
 	ToolCalls []types.ToolCall
 
-	// ToolResults contains the results of tool executions (empty on final step).
+	// ToolResults contains the results of tool executions (empty on final step,
+	// and on a step that spent the budget).
 	ToolResults []types.ToolResult
 
+	// Cost is the estimated cost of this step's model call, zero when the
+	// model has no pricing in the client's registry.
+	Cost float64
+
 	// Done is true when this is the final step (no more tool calls).
 	Done bool
 }
@@ -32,6 +48,19 @@ type AgentResult struct {
 
 	// TotalSteps is the number of LLM calls made.
 	TotalSteps int
+
+	// Messages is the full transcript: the system prompt and user prompt,
+	// each assistant turn and its tool results, and the final response.
+	Messages []types.Message
+
+	// Usage sums token usage across all steps, including sub-agent runs.
+	Usage types.Usage
+
+	// Cost sums the estimated cost of all steps, including sub-agent runs.
+	Cost float64
+
+	// SubAgents lists the calls made to agents added with AddAgent.
+	SubAgents []SubAgentRun
 }
 
 // AgentBuilder builds and runs an agentic tool-calling loop.
@@ -40,7 +69,8 @@ type AgentResult struct {
 //  1. Sends the prompt to the LLM with available tools
 //  2. If the LLM returns tool calls, executes them
 //  3. Sends results back to the LLM
-//  4. Repeats until the LLM produces a final text response (or max steps)
+//  4. Repeats until the LLM produces a final text response, or stops early
+//     at MaxSteps, TokenBudget, or CostBudget
 //
 // Example:
 //
@@ -61,7 +91,17 @@ type AgentBuilder struct {
 	maxSteps     int
 	temperature  *float32
 	maxTokens    *int
+	tokenBudget  int
+	costBudget   float64
 	onStep       func(StepEvent)
+
+	allowedTools   map[string]bool
+	approvals      *Approvals
+	memory         AgentMemory
+	inheritMemory  bool
+	recallLimit    int
+	summarizer     TextRoute
+	summarizeAfter int
 }
 
 // Model sets the LLM model to use.
@@ -88,6 +128,64 @@ func (b *AgentBuilder) MaxSteps(n int) *AgentBuilder {
 	return b
 }
 
+// TokenBudget caps the total tokens the run may spend across all steps,
+// sub-agents included. Once a step brings the total to n, the run stops
+// without executing that step's tool calls and Run returns
+// ErrAgentBudgetExceeded with the partial result. Zero means no cap.
+func (b *AgentBuilder) TokenBudget(n int) *AgentBuilder {
+	b.tokenBudget = n
+	return b
+}
+
+// CostBudget caps the estimated cost in USD the run may spend, priced with
+// the client's model registry, and stops the run like TokenBudget. The model
+// must have pricing in the registry. Zero means no cap.
+func (b *AgentBuilder) CostBudget(usd float64) *AgentBuilder {
+	b.costBudget = usd
+	return b
+}
+
+// Memory gives the agent a memory. Facts recalled for the prompt are added
+// to the conversation before the first step, the model gets memory_save and
+// memory_search tools, and tools reach it with AgentMemoryFromContext.
+func (b *AgentBuilder) Memory(memory AgentMemory) *AgentBuilder {
+	b.memory = memory
+	return b
+}
+
+// InheritMemory lets a sub-agent without its own Memory use the memory of
+// the agent that called it, with the same recall and tools. Without it a
+// sub-agent's tools get nil from AgentMemoryFromContext.
+func (b *AgentBuilder) InheritMemory() *AgentBuilder {
+	b.inheritMemory = true
+	return b
+}
+
+// RecallLimit sets how many facts are recalled from memory at a time
+// (default: 5).
+func (b *AgentBuilder) RecallLimit(n int) *AgentBuilder {
+	b.recallLimit = n
+	return b
+}
+
+// SummarizeAfter condenses older steps with the summarizer model once the
+// conversation passes about tokens prompt tokens. The prompt and the two
+// latest steps are kept as they are; AgentResult.Messages still holds the
+// full transcript.
+func (b *AgentBuilder) SummarizeAfter(tokens int, summarizer TextRoute) *AgentBuilder {
+	b.summarizeAfter = tokens
+	b.summarizer = summarizer
+	return b
+}
+
+// Approvals routes calls to tools marked RequireApproval through approvals.
+// The run pauses on such a call until approvals.Approve or approvals.Reject
+// is called for it; a rejected call reaches the model as a failed tool call.
+func (b *AgentBuilder) Approvals(approvals *Approvals) *AgentBuilder {
+	b.approvals = approvals
+	return b
+}
+
 // Temperature sets the sampling temperature.
 func (b *AgentBuilder) Temperature(t float32) *AgentBuilder {
 	b.temperature = &t
@@ -115,8 +213,8 @@ func (b *AgentBuilder) fireStepEvent(e StepEvent) {
 
 // AddTool registers a tool with the agent using a raw handler.
 // Tools added here are scoped to this agent — they don't affect the global client registry.
-func (b *AgentBuilder) AddTool(name, description string, schema map[string]any, handler types.ToolHandler) *AgentBuilder {
-	b.tools.Register(name, &types.ToolDefinition{
+func (b *AgentBuilder) AddTool(name, description string, schema map[string]any, handler types.ToolHandler, opts ...ToolOption) *AgentBuilder {
+	definition := &types.ToolDefinition{
 		Tool: types.Tool{
 			Type:        "function",
 			Name:        name,
@@ -129,6 +227,8 @@ func (b *AgentBuilder) AddTool(name, description string, schema map[string]any,
 			},
 		},
 		Handler: handler,
-	})
+	}
+	applyToolOptions(definition, opts)
+	b.tools.Register(name, definition)
 	return b
 }
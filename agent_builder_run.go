@@ -3,16 +3,43 @@ package wormhole
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 // Run executes the agent loop with the given prompt.
 // It returns the final result after all tool executions complete, or an error.
+// When the loop stops at MaxSteps or a budget, Run returns the partial result
+// together with ErrAgentMaxSteps or ErrAgentBudgetExceeded.
 func (b *AgentBuilder) Run(ctx context.Context, prompt string) (*AgentResult, error) {
 	if b.model == "" {
 		return nil, fmt.Errorf("agent: model is required")
 	}
+	// A sub-agent without memory of its own uses its supervisor's only when
+	// it opts in with InheritMemory.
+	if b.memory == nil && b.inheritMemory {
+		if inherited := AgentMemoryFromContext(ctx); inherited != nil {
+			run := *b
+			run.memory = inherited
+			b = &run
+		}
+	}
+	spend, err := b.newAgentSpend(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if spend.pricesCost() {
+		priced := []string{b.model}
+		if b.summarizeAfter > 0 {
+			priced = append(priced, b.summarizer.Model)
+		}
+		for _, name := range priced {
+			if model, ok := b.wormhole.modelRegistry.Get(name); !ok || model.Cost == nil {
+				return nil, fmt.Errorf("agent: cost budget needs pricing for model %q in the model registry", name)
+			}
+		}
+	}
 
 	maxSteps := b.maxSteps
 
@@ -53,19 +80,47 @@ func (b *AgentBuilder) Run(ctx context.Context, prompt string) (*AgentResult, er
 		request.MaxTokens = b.maxTokens
 	}
 
+	// Facts recalled from memory go after the system prompt, just ahead of
+	// the user prompt. Tools see this run's memory, or none, never a
+	// supervisor's the run did not inherit.
+	var recalled types.Message
+	if b.memory != nil {
+		recalled, err = b.recallMessage(ctx, prompt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ctx = context.WithValue(ctx, agentMemoryContextKey{}, b.memory)
+
 	// Prepare messages (inject system prompt)
 	request.Messages = prepareExecutionMessages(request.SystemPrompt, request.Messages)
+	if recalled != nil {
+		request.Messages = slices.Insert(request.Messages, len(request.Messages)-1, recalled)
+	}
+	// The transcript keeps every turn; request.Messages may be summarized.
+	transcript := append([]types.Message(nil), request.Messages...)
 
 	// Create executor for tool calls
 	executor := NewToolExecutor(mergedRegistry)
+	compactor := b.newCompactor()
 
-	var steps []StepEvent
+	result := &AgentResult{}
 	ctx = contextWithProviderOperation(ctx, provider, "agent")
+	ctx = ensureToolMemory(ctx)
+	ctx = context.WithValue(ctx, agentSpendContextKey{}, spend)
+	if b.approvals != nil {
+		ctx = WithApprovals(ctx, b.approvals)
+	}
 
 	for step := 1; step <= maxSteps; step++ {
 		if err := ctx.Err(); err != nil {
 			return nil, fmt.Errorf("agent step %d: %w", step, err)
 		}
+		// Sub-agents may have spent the budget during the last step's tools.
+		if step > 1 && spend.exhausted() {
+			return result, fmt.Errorf("%w (%d tokens, $%.4f before step %d)", ErrAgentBudgetExceeded, usedTokens(result.Usage), result.Cost, step)
+		}
+		request.Messages = b.compactTranscript(ctx, compactor, spend, request.Messages)
 
 		// Call the LLM (through middleware if configured)
 		var resp *types.TextResponse
@@ -78,49 +133,97 @@ func (b *AgentBuilder) Run(ctx context.Context, prompt string) (*AgentResult, er
 		if err != nil {
 			return nil, fmt.Errorf("agent step %d: %w", step, err)
 		}
+		cost := b.chargeStep(spend, resp)
+		result.Response = resp
+		result.TotalSteps = step
+
+		// Build conversation continuation. Thinking carries the signed reasoning
+		// block so Anthropic extended-thinking + tool_use replay doesn't hard-400.
+		assistantMsg := &types.AssistantMessage{
+			Content:   resp.Text,
+			ToolCalls: resp.ToolCalls,
+			Thinking:  resp.Thinking,
+		}
+		request.Messages = append(request.Messages, assistantMsg)
+		transcript = append(transcript, assistantMsg)
 
 		// No tool calls — final response
 		if len(resp.ToolCalls) == 0 {
-			event := StepEvent{
+			b.recordStep(result, spend, StepEvent{
 				Step:     step,
 				Response: resp,
+				Cost:     cost,
 				Done:     true,
-			}
-			steps = append(steps, event)
-			b.fireStepEvent(event)
-			return &AgentResult{
-				Response:   resp,
-				Steps:      steps,
-				TotalSteps: step,
-			}, nil
+			}, transcript)
+			return result, nil
+		}
+
+		// A spent budget stops before running tools whose results would never
+		// be sent back.
+		if spend.exhausted() {
+			b.recordStep(result, spend, StepEvent{
+				Step:      step,
+				Response:  resp,
+				ToolCalls: resp.ToolCalls,
+				Cost:      cost,
+			}, transcript)
+			return result, fmt.Errorf("%w (%d tokens, $%.4f after step %d)", ErrAgentBudgetExceeded, usedTokens(result.Usage), result.Cost, step)
 		}
 
 		// Execute tool calls
 		toolResults := executor.ExecuteAll(ctx, resp.ToolCalls)
+		for _, toolResultMsg := range executor.BuildToolResultMessages(toolResults) {
+			request.Messages = append(request.Messages, toolResultMsg)
+			transcript = append(transcript, toolResultMsg)
+		}
 
-		event := StepEvent{
+		b.recordStep(result, spend, StepEvent{
 			Step:        step,
 			Response:    resp,
 			ToolCalls:   resp.ToolCalls,
 			ToolResults: toolResults,
+			Cost:        cost,
 			Done:        false,
-		}
-		steps = append(steps, event)
-		b.fireStepEvent(event)
+		}, transcript)
+	}
 
-		// Build conversation continuation. Thinking carries the signed reasoning
-		// block so Anthropic extended-thinking + tool_use replay doesn't hard-400.
-		assistantMsg := &types.AssistantMessage{
-			Content:   resp.Text,
-			ToolCalls: resp.ToolCalls,
-			Thinking:  resp.Thinking,
-		}
+	return result, fmt.Errorf("%w (%d)", ErrAgentMaxSteps, maxSteps)
+}
 
-		request.Messages = append(request.Messages, assistantMsg)
-		for _, toolResultMsg := range executor.BuildToolResultMessages(toolResults) {
-			request.Messages = append(request.Messages, toolResultMsg)
-		}
+// chargeStep charges the step's usage and estimated cost to the run and the
+// runs above it, and returns the step's cost.
+func (b *AgentBuilder) chargeStep(spend *agentSpend, resp *types.TextResponse) float64 {
+	if resp.Usage == nil {
+		return 0
 	}
+	return b.chargeUsage(spend, b.model, *resp.Usage)
+}
 
-	return nil, fmt.Errorf("agent: max steps (%d) reached without final response", maxSteps)
+// chargeUsage charges usage of model, priced with the model registry, to the
+// run and the runs above it, and returns its cost.
+func (b *AgentBuilder) chargeUsage(spend *agentSpend, model string, usage types.Usage) float64 {
+	cost, err := b.wormhole.modelRegistry.EstimateCost(model, usage.PromptTokens, usage.CompletionTokens)
+	if err != nil {
+		cost = 0
+	}
+	spend.charge(usage, cost)
+	return cost
+}
+
+// recordStep appends the step to result, snapshots the transcript and spend,
+// and fires the OnStep callback.
+func (b *AgentBuilder) recordStep(result *AgentResult, spend *agentSpend, event StepEvent, messages []types.Message) {
+	result.Steps = append(result.Steps, event)
+	result.Messages = append([]types.Message(nil), messages...)
+	spend.snapshot(result)
+	b.fireStepEvent(event)
+}
+
+// usedTokens is the usage total, summed from its parts for providers that
+// leave TotalTokens unset.
+func usedTokens(usage types.Usage) int {
+	if usage.TotalTokens > 0 {
+		return usage.TotalTokens
+	}
+	return usage.PromptTokens + usage.CompletionTokens
 }
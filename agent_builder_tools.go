@@ -6,20 +6,28 @@ import (
 	"fmt"
 )
 
-// mergeTools creates a merged registry with global + agent-scoped tools.
-// Agent tools override global tools with the same name.
+// mergeTools creates a merged registry with the allowed global tools, the
+// memory tools when the agent has memory, and agent-scoped tools. Later
+// sources override earlier ones with the same name.
 func (b *AgentBuilder) mergeTools() *ToolRegistry {
 	merged := NewToolRegistry()
 
 	// Copy global tools first
 	globalTools := b.wormhole.toolRegistry
 	for _, name := range globalTools.ListNames() {
+		if b.allowedTools != nil && !b.allowedTools[name] {
+			continue
+		}
 		def := globalTools.Get(name)
 		if def != nil {
 			merged.Register(name, def)
 		}
 	}
 
+	if b.memory != nil {
+		b.registerMemoryTools(merged)
+	}
+
 	// Override with agent-scoped tools
 	for _, name := range b.tools.ListNames() {
 		def := b.tools.Get(name)
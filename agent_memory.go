@@ -0,0 +1,283 @@
+package wormhole
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// AgentMemory stores facts an agent can recall in later steps and later
+// runs. Attach one with AgentBuilder.Memory. Scratchpad keeps a run's recent
+// facts; VectorMemory recalls by meaning. Implement AgentMemory over your own
+// database for memory that outlives the process.
+type AgentMemory interface {
+	// Remember stores fact.
+	Remember(ctx context.Context, fact string) error
+	// Recall returns up to limit stored facts, most relevant to query first.
+	Recall(ctx context.Context, query string, limit int) ([]string, error)
+}
+
+// Scratchpad is short-term AgentMemory: it keeps the latest facts in order
+// and recalls the most recent ones, ignoring the query.
+type Scratchpad struct {
+	mu       sync.Mutex
+	capacity int
+	facts    []string
+}
+
+// NewScratchpad creates a scratchpad holding at most capacity facts
+// (default 100), dropping the oldest first.
+func NewScratchpad(capacity int) *Scratchpad {
+	if capacity <= 0 {
+		capacity = 100
+	}
+	return &Scratchpad{capacity: capacity}
+}
+
+// Remember appends fact.
+func (s *Scratchpad) Remember(_ context.Context, fact string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.facts) >= s.capacity {
+		s.facts = append(s.facts[:0], s.facts[len(s.facts)-s.capacity+1:]...)
+	}
+	s.facts = append(s.facts, fact)
+	return nil
+}
+
+// Recall returns up to limit facts, newest first. A limit of zero or less
+// returns them all.
+func (s *Scratchpad) Recall(_ context.Context, _ string, limit int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit <= 0 || limit > len(s.facts) {
+		limit = len(s.facts)
+	}
+	recalled := make([]string, 0, limit)
+	for i := len(s.facts) - 1; i >= len(s.facts)-limit; i-- {
+		recalled = append(recalled, s.facts[i])
+	}
+	return recalled, nil
+}
+
+// VectorMemory is long-term AgentMemory that recalls facts by cosine
+// similarity of their embeddings. Vectors are held in process; implement
+// AgentMemory over a vector database to persist them.
+//
+// Example:
+//
+//	memory := wormhole.NewVectorMemory(client.Embedder("openai", "text-embedding-3-small"), 0)
+type VectorMemory struct {
+	embed    middleware.Embedder
+	capacity int
+
+	mu      sync.Mutex
+	facts   []string
+	vectors [][]float64
+}
+
+// NewVectorMemory creates a vector memory holding at most capacity facts
+// (default 1000), dropping the oldest first.
+func NewVectorMemory(embed middleware.Embedder, capacity int) *VectorMemory {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &VectorMemory{embed: embed, capacity: capacity}
+}
+
+// Remember embeds and stores fact.
+func (m *VectorMemory) Remember(ctx context.Context, fact string) error {
+	vector, err := m.embed(ctx, fact)
+	if err != nil {
+		return fmt.Errorf("agent memory: embed fact: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.facts) >= m.capacity {
+		drop := len(m.facts) - m.capacity + 1
+		m.facts = append(m.facts[:0], m.facts[drop:]...)
+		m.vectors = append(m.vectors[:0], m.vectors[drop:]...)
+	}
+	m.facts = append(m.facts, fact)
+	m.vectors = append(m.vectors, vector)
+	return nil
+}
+
+// Recall returns up to limit facts ranked by similarity to query.
+func (m *VectorMemory) Recall(ctx context.Context, query string, limit int) ([]string, error) {
+	m.mu.Lock()
+	empty := len(m.facts) == 0
+	m.mu.Unlock()
+	if empty {
+		return nil, nil
+	}
+	vector, err := m.embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("agent memory: embed query: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	matches := types.TopK(vector, m.vectors, limit)
+	recalled := make([]string, len(matches))
+	for i, match := range matches {
+		recalled[i] = m.facts[match.Index]
+	}
+	return recalled, nil
+}
+
+// Len returns the number of stored facts.
+func (m *VectorMemory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.facts)
+}
+
+type agentMemoryContextKey struct{}
+
+// AgentMemoryFromContext returns the memory of the agent run executing a
+// tool, or nil outside an agent run or without AgentBuilder.Memory.
+func AgentMemoryFromContext(ctx context.Context) AgentMemory {
+	memory, _ := ctx.Value(agentMemoryContextKey{}).(AgentMemory)
+	return memory
+}
+
+// Names of the tools an agent with memory offers the model.
+const (
+	MemorySaveTool   = "memory_save"
+	MemorySearchTool = "memory_search"
+)
+
+// defaultRecallLimit is how many facts are recalled when no limit is set.
+const defaultRecallLimit = 5
+
+// registerMemoryTools adds the memory_save and memory_search tools to
+// registry.
+func (b *AgentBuilder) registerMemoryTools(registry *ToolRegistry) {
+	memory := b.memory
+	limit := b.recallLimit
+	factSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fact": map[string]any{"type": "string", "description": "A self-contained fact worth keeping"},
+		},
+		"required": []string{"fact"},
+	}
+	querySchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{"type": "string", "description": "What to look up"},
+		},
+		"required": []string{"query"},
+	}
+	registry.Register(MemorySaveTool, memoryToolDefinition(MemorySaveTool,
+		"Save a fact to memory so later steps can look it up.", factSchema,
+		func(ctx context.Context, args map[string]any) (any, error) {
+			fact, _ := args["fact"].(string)
+			if strings.TrimSpace(fact) == "" {
+				return nil, fmt.Errorf("fact is required")
+			}
+			if err := memory.Remember(ctx, fact); err != nil {
+				return nil, err
+			}
+			return "saved", nil
+		}))
+	registry.Register(MemorySearchTool, memoryToolDefinition(MemorySearchTool,
+		"Search memory for facts saved earlier.", querySchema,
+		func(ctx context.Context, args map[string]any) (any, error) {
+			query, _ := args["query"].(string)
+			return memory.Recall(ctx, query, limit)
+		}))
+}
+
+func memoryToolDefinition(name, description string, schema map[string]any, handler types.ToolHandler) *types.ToolDefinition {
+	return &types.ToolDefinition{
+		Tool: types.Tool{
+			Type:        "function",
+			Name:        name,
+			Description: description,
+			InputSchema: schema,
+			Function: &types.ToolFunction{
+				Name:        name,
+				Description: description,
+				Parameters:  schema,
+			},
+		},
+		Handler: handler,
+	}
+}
+
+// recallMessage returns a system message listing the facts memory holds for
+// prompt, or nil when it holds none. Run places it after the system prompt,
+// just ahead of the user prompt.
+func (b *AgentBuilder) recallMessage(ctx context.Context, prompt string) (types.Message, error) {
+	facts, err := b.memory.Recall(ctx, prompt, b.recallLimit)
+	if err != nil {
+		return nil, fmt.Errorf("agent: recall memory: %w", err)
+	}
+	if len(facts) == 0 {
+		return nil, nil
+	}
+	return types.NewSystemMessage("Facts from memory:\n- " + strings.Join(facts, "\n- ")), nil
+}
+
+// agentKeepTurns is how many of the latest assistant turns compactTranscript
+// never summarizes.
+const agentKeepTurns = 2
+
+// newCompactor returns the context manager compactTranscript summarizes
+// with, or nil when the agent does not summarize.
+func (b *AgentBuilder) newCompactor() *ContextManager {
+	if b.summarizeAfter <= 0 {
+		return nil
+	}
+	return newContextManager(b.wormhole, ContextManagerConfig{Summarizer: b.summarizer})
+}
+
+// compactTranscript summarizes the oldest assistant turns and their tool
+// results, and any earlier summary, once messages exceed the summarize
+// threshold. Everything through the user prompt and the latest turns is
+// kept. The summarizer's usage is charged to spend, and the summary is also
+// remembered when the agent has memory. A failed summary leaves messages as
+// they are.
+func (b *AgentBuilder) compactTranscript(ctx context.Context, manager *ContextManager, spend *agentSpend, messages []types.Message) []types.Message {
+	if manager == nil || manager.cost(messages) <= b.summarizeAfter {
+		return messages
+	}
+
+	// Keep everything through the user prompt, and cut on an assistant turn
+	// so tool results stay with their calls.
+	head := 0
+	for head < len(messages) && messages[head].GetRole() != types.RoleUser {
+		head++
+	}
+	head++
+	var turns []int
+	for i := head; i < len(messages); i++ {
+		if messages[i].GetRole() == types.RoleAssistant {
+			turns = append(turns, i)
+		}
+	}
+	if len(turns) <= agentKeepTurns {
+		return messages
+	}
+	cut := turns[len(turns)-agentKeepTurns]
+
+	summary, usage, err := manager.summarize(ctx, messages[head:cut])
+	if usage != nil {
+		b.chargeUsage(spend, b.summarizer.Model, *usage)
+	}
+	if err != nil {
+		return messages
+	}
+	if b.memory != nil {
+		_ = b.memory.Remember(ctx, summary) // best effort; the summary is also kept inline
+	}
+	compacted := make([]types.Message, 0, head+1+len(messages)-cut)
+	compacted = append(compacted, messages[:head]...)
+	compacted = append(compacted, types.NewSystemMessage("Summary of the earlier steps:\n"+summary))
+	return append(compacted, messages[cut:]...)
+}
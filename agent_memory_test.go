@@ -0,0 +1,213 @@
+package wormhole
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestScratchpadKeepsLatestFacts(t *testing.T) {
+	t.Parallel()
+
+	pad := NewScratchpad(2)
+	ctx := context.Background()
+	for _, fact := range []string{"a", "b", "c"} {
+		require.NoError(t, pad.Remember(ctx, fact))
+	}
+
+	facts, err := pad.Recall(ctx, "ignored", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "b"}, facts)
+
+	facts, err = pad.Recall(ctx, "ignored", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, facts)
+}
+
+// keywordEmbedder embeds text as one dimension per keyword it mentions.
+func keywordEmbedder(keywords ...string) func(context.Context, string) ([]float64, error) {
+	return func(_ context.Context, text string) ([]float64, error) {
+		vector := make([]float64, len(keywords))
+		for i, keyword := range keywords {
+			if strings.Contains(strings.ToLower(text), keyword) {
+				vector[i] = 1
+			}
+		}
+		return vector, nil
+	}
+}
+
+func TestVectorMemoryRecallsBySimilarity(t *testing.T) {
+	t.Parallel()
+
+	memory := NewVectorMemory(keywordEmbedder("billing", "deploy", "oncall"), 2)
+	ctx := context.Background()
+	require.NoError(t, memory.Remember(ctx, "Invoices are generated by the billing service"))
+	require.NoError(t, memory.Remember(ctx, "Deploys go out on Tuesdays"))
+	require.NoError(t, memory.Remember(ctx, "Alice is oncall this week"))
+	assert.Equal(t, 2, memory.Len(), "oldest fact dropped at capacity")
+
+	facts, err := memory.Recall(ctx, "who is oncall?", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice is oncall this week"}, facts)
+
+	failing := NewVectorMemory(func(context.Context, string) ([]float64, error) {
+		return nil, fmt.Errorf("embedder down")
+	}, 0)
+	assert.ErrorContains(t, failing.Remember(ctx, "fact"), "embedder down")
+}
+
+func TestAgentMemoryRecallToolsAndContext(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockToolProvider{responses: []*types.TextResponse{
+		{ToolCalls: []types.ToolCall{
+			{ID: "call_1", Name: MemorySaveTool, Arguments: map[string]any{"fact": "The release branch is release/2.4"}},
+			{ID: "call_2", Name: "note", Arguments: map[string]any{}},
+		}},
+		{Text: "done"},
+	}}
+	client := newAgentTestClient(provider)
+	memory := NewScratchpad(0)
+	require.NoError(t, memory.Remember(context.Background(), "The repo uses trunk-based development"))
+
+	_, err := client.Agent().Using("mock").Model("mock-model").Memory(memory).
+		AddTool("note", "Note something", map[string]any{"type": "object"}, func(ctx context.Context, _ map[string]any) (any, error) {
+			return "noted", AgentMemoryFromContext(ctx).Remember(ctx, "A tool wrote this")
+		}).
+		Run(context.Background(), "Which branch do we release from?")
+	require.NoError(t, err)
+
+	first := provider.requests[0]
+	require.Len(t, first.Messages, 2)
+	assert.Equal(t, types.RoleSystem, first.Messages[0].GetRole())
+	assert.Contains(t, first.Messages[0].GetContent(), "The repo uses trunk-based development")
+	var toolNames []string
+	for _, tool := range first.Tools {
+		toolNames = append(toolNames, tool.Name)
+	}
+	assert.Subset(t, toolNames, []string{MemorySaveTool, MemorySearchTool, "note"})
+
+	facts, err := memory.Recall(context.Background(), "", 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"A tool wrote this", "The release branch is release/2.4", "The repo uses trunk-based development"}, facts)
+}
+
+func TestAgentSummarizesLongTranscripts(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockToolProvider{responses: []*types.TextResponse{
+		agentToolStep("call_1", types.Usage{}),
+		agentToolStep("call_2", types.Usage{}),
+		agentToolStep("call_3", types.Usage{}),
+		{Text: "Looked up three records."},
+		{Text: "done"},
+	}}
+	client := newAgentTestClient(provider)
+	memory := NewScratchpad(0)
+
+	result, err := client.Agent().Using("mock").Model("mock-model").Memory(memory).
+		SummarizeAfter(1, TextRoute{Provider: "mock", Model: "mock-model"}).
+		AddTool("lookup", "Lookup data", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+			return "ok", nil
+		}).
+		Run(context.Background(), "Look things up")
+	require.NoError(t, err)
+	require.Len(t, provider.requests, 5)
+
+	summaryRequest := provider.requests[3]
+	assert.Empty(t, summaryRequest.Tools)
+	assert.Contains(t, summaryRequest.Messages[0].GetContent(), "Summarize the conversation")
+
+	final := provider.requests[4].Messages
+	require.Len(t, final, 6, "prompt, summary, and the two latest steps")
+	assert.Equal(t, types.RoleUser, final[0].GetRole())
+	assert.Equal(t, "Summary of the earlier steps:\nLooked up three records.", final[1].GetContent())
+	assert.Equal(t, "call_2", final[2].(*types.AssistantMessage).ToolCalls[0].ID)
+
+	assert.Len(t, result.Messages, 8, "the transcript keeps every step")
+	facts, err := memory.Recall(context.Background(), "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Looked up three records."}, facts)
+}
+
+func TestAgentRecallFollowsSystemPrompt(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockToolProvider{responses: []*types.TextResponse{{Text: "done"}}}
+	client := newAgentTestClient(provider)
+	memory := NewScratchpad(0)
+	require.NoError(t, memory.Remember(context.Background(), "Deploys happen on Tuesdays"))
+
+	_, err := client.Agent().Using("mock").Model("mock-model").System("You are the release assistant.").Memory(memory).
+		AddTool("note", "Note something", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+			return "noted", nil
+		}).
+		Run(context.Background(), "When do we deploy?")
+	require.NoError(t, err)
+
+	messages := provider.requests[0].Messages
+	require.Len(t, messages, 3)
+	assert.Equal(t, "You are the release assistant.", messages[0].GetContent())
+	assert.Contains(t, messages[1].GetContent(), "Deploys happen on Tuesdays")
+	assert.Equal(t, types.RoleUser, messages[2].GetRole())
+}
+
+func TestAgentChargesSummarizerUsage(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockToolProvider{responses: []*types.TextResponse{
+		agentToolStep("call_1", types.Usage{TotalTokens: 10}),
+		agentToolStep("call_2", types.Usage{TotalTokens: 10}),
+		agentToolStep("call_3", types.Usage{TotalTokens: 10}),
+		{Text: "Looked up three records.", Usage: &types.Usage{TotalTokens: 25}},
+		{Text: "done", Usage: &types.Usage{TotalTokens: 10}},
+	}}
+	client := newAgentTestClient(provider)
+
+	result, err := client.Agent().Using("mock").Model("mock-model").
+		SummarizeAfter(1, TextRoute{Provider: "mock", Model: "mock-model"}).
+		AddTool("lookup", "Lookup data", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+			return "ok", nil
+		}).
+		Run(context.Background(), "Look things up")
+	require.NoError(t, err)
+	assert.Equal(t, 65, result.Usage.TotalTokens, "the summary call counts against the run")
+}
+
+func TestSubAgentMemoryIsOptIn(t *testing.T) {
+	t.Parallel()
+
+	run := func(inherit bool) AgentMemory {
+		provider := &mockToolProvider{responses: []*types.TextResponse{
+			delegateStep("Check memory", types.Usage{}),
+			{ToolCalls: []types.ToolCall{{ID: "call_probe", Name: "probe", Arguments: map[string]any{}}}},
+			{Text: "checked"},
+			{Text: "done"},
+		}}
+		client := newAgentTestClient(provider)
+		var seen AgentMemory
+		researcher := client.Agent().Using("mock").Model("mock-model").
+			AddTool("probe", "Probe memory", map[string]any{"type": "object"}, func(ctx context.Context, _ map[string]any) (any, error) {
+				seen = AgentMemoryFromContext(ctx)
+				return "ok", nil
+			})
+		if inherit {
+			researcher.InheritMemory()
+		}
+		_, err := client.Agent().Using("mock").Model("mock-model").Memory(NewScratchpad(0)).
+			AddAgent("researcher", "Research a question", researcher).
+			Run(context.Background(), "Delegate")
+		require.NoError(t, err)
+		return seen
+	}
+
+	assert.Nil(t, run(false), "a sub-agent without memory must not see its supervisor's")
+	assert.NotNil(t, run(true), "InheritMemory shares the supervisor's memory")
+}
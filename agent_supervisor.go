@@ -0,0 +1,172 @@
+package wormhole
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// maxAgentDepth bounds how deeply agents may call one another, so an agent
+// added to itself fails instead of recursing forever.
+const maxAgentDepth = 8
+
+// SubAgentRun records one call a supervisor made to a sub-agent.
+type SubAgentRun struct {
+	// Name is the tool name the sub-agent was added under.
+	Name string
+	// Task is the task the supervisor gave it.
+	Task string
+	// Result is the sub-agent's result, partial when it stopped at a limit.
+	Result *AgentResult
+	// Err is the error the sub-agent's run returned.
+	Err error
+}
+
+// AddAgent adds sub as a tool named name, making b its supervisor. The model
+// calls the tool with a task; sub runs it as a full agent loop and its final
+// text is the tool result. sub keeps its own model, system prompt, tools, and
+// step limit. What it spends counts against b's token and cost budgets, and
+// against those of every agent above b. Sub-agent tools run without the
+// executor's per-tool timeout; bound them with MaxSteps and budgets.
+//
+// Example:
+//
+//	researcher := client.Agent().
+//	    Model("gpt-5-mini").
+//	    System("You research one question and report facts with sources.").
+//	    AllowTools("web_search")
+//
+//	result, err := client.Agent().
+//	    Model("gpt-5.2").
+//	    System("You plan the work and delegate research.").
+//	    AddAgent("researcher", "Research a single question", researcher).
+//	    CostBudget(1.00).
+//	    Run(ctx, "Compare the three largest CDN providers.")
+func (b *AgentBuilder) AddAgent(name, description string, sub *AgentBuilder) *AgentBuilder {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"task": map[string]any{"type": "string", "description": "The task, with everything the agent needs to know"},
+		},
+		"required": []string{"task"},
+	}
+	handler := func(ctx context.Context, args map[string]any) (any, error) {
+		task, _ := args["task"].(string)
+		result, err := sub.Run(ctx, task)
+		if parent := agentSpendFromContext(ctx); parent != nil {
+			parent.recordSubRun(SubAgentRun{Name: name, Task: task, Result: result, Err: err})
+		}
+		if err != nil {
+			return nil, err
+		}
+		return result.Response.Text, nil
+	}
+	return b.AddTool(name, description, schema, handler, ToolTimeout(-1))
+}
+
+// AllowTools limits which of the client's registered tools the agent sees to
+// names. Tools added to the agent itself, sub-agents, and memory tools are
+// always available. Calling it with no names hides every client tool.
+func (b *AgentBuilder) AllowTools(names ...string) *AgentBuilder {
+	b.allowedTools = make(map[string]bool, len(names))
+	for _, name := range names {
+		b.allowedTools[name] = true
+	}
+	return b
+}
+
+// agentSpend tracks what one agent run has spent, including its sub-agents.
+// Charges propagate to every ancestor, so a supervisor's budgets cover the
+// whole hierarchy below it.
+type agentSpend struct {
+	parent      *agentSpend
+	depth       int
+	tokenBudget int
+	costBudget  float64
+
+	mu      sync.Mutex
+	usage   types.Usage
+	cost    float64
+	subRuns []SubAgentRun
+}
+
+type agentSpendContextKey struct{}
+
+func agentSpendFromContext(ctx context.Context) *agentSpend {
+	spend, _ := ctx.Value(agentSpendContextKey{}).(*agentSpend)
+	return spend
+}
+
+// newAgentSpend starts tracking a run under the run in ctx, if any.
+func (b *AgentBuilder) newAgentSpend(ctx context.Context) (*agentSpend, error) {
+	parent := agentSpendFromContext(ctx)
+	spend := &agentSpend{parent: parent, tokenBudget: b.tokenBudget, costBudget: b.costBudget}
+	if parent != nil {
+		spend.depth = parent.depth + 1
+	}
+	if spend.depth >= maxAgentDepth {
+		return nil, fmt.Errorf("agent: sub-agents nested more than %d deep", maxAgentDepth)
+	}
+	return spend, nil
+}
+
+// charge adds a model call's usage and cost to s and its ancestors.
+func (s *agentSpend) charge(usage types.Usage, cost float64) {
+	for t := s; t != nil; t = t.parent {
+		t.mu.Lock()
+		addUsage(&t.usage, usage)
+		t.cost += cost
+		t.mu.Unlock()
+	}
+}
+
+// exhausted reports whether s or any ancestor has spent its budget.
+func (s *agentSpend) exhausted() bool {
+	for t := s; t != nil; t = t.parent {
+		t.mu.Lock()
+		spent := t.tokenBudget > 0 && usedTokens(t.usage) >= t.tokenBudget ||
+			t.costBudget > 0 && t.cost >= t.costBudget
+		t.mu.Unlock()
+		if spent {
+			return true
+		}
+	}
+	return false
+}
+
+// pricesCost reports whether s or an ancestor has a cost budget, which needs
+// every model in the hierarchy priced.
+func (s *agentSpend) pricesCost() bool {
+	for t := s; t != nil; t = t.parent {
+		if t.costBudget > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *agentSpend) recordSubRun(run SubAgentRun) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subRuns = append(s.subRuns, run)
+}
+
+// snapshot copies the totals and sub-agent runs into result.
+func (s *agentSpend) snapshot(result *AgentResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result.Usage = s.usage
+	result.Cost = s.cost
+	result.SubAgents = append([]SubAgentRun(nil), s.subRuns...)
+}
+
+func addUsage(total *types.Usage, usage types.Usage) {
+	total.PromptTokens += usage.PromptTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+	total.CacheReadTokens += usage.CacheReadTokens
+	total.CacheWriteTokens += usage.CacheWriteTokens
+	total.ReasoningTokens += usage.ReasoningTokens
+}
@@ -0,0 +1,96 @@
+package wormhole
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func delegateStep(task string, usage types.Usage) *types.TextResponse {
+	return &types.TextResponse{
+		ToolCalls: []types.ToolCall{{ID: "call_delegate", Name: "researcher", Arguments: map[string]any{"task": task}}},
+		Usage:     &usage,
+	}
+}
+
+func TestAgentDelegatesToSubAgent(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockToolProvider{responses: []*types.TextResponse{
+		delegateStep("Find the CDN market leaders", types.Usage{TotalTokens: 100}),
+		{Text: "Cloudflare, Akamai, Fastly", Usage: &types.Usage{TotalTokens: 40}},
+		{Text: "done", Usage: &types.Usage{TotalTokens: 60}},
+	}}
+	client := newAgentTestClient(provider)
+	client.RegisterTool("deploy", "Deploy to production", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+		return "deployed", nil
+	})
+	client.RegisterTool("search", "Search the web", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+		return "results", nil
+	})
+
+	researcher := client.Agent().Using("mock").Model("mock-model").System("You research.").AllowTools("search")
+	result, err := client.Agent().Using("mock").Model("mock-model").System("You plan.").
+		AddAgent("researcher", "Research a question", researcher).
+		Run(context.Background(), "Compare CDNs")
+	require.NoError(t, err)
+	assert.Equal(t, "done", result.Response.Text)
+
+	subRequest := provider.requests[1]
+	assert.Equal(t, "You research.", subRequest.SystemPrompt)
+	require.Len(t, subRequest.Tools, 1, "the sub-agent sees only its allowed tools")
+	assert.Equal(t, "search", subRequest.Tools[0].Name)
+
+	require.Len(t, result.SubAgents, 1)
+	run := result.SubAgents[0]
+	assert.Equal(t, "researcher", run.Name)
+	assert.Equal(t, "Find the CDN market leaders", run.Task)
+	require.NoError(t, run.Err)
+	assert.Equal(t, "Cloudflare, Akamai, Fastly", run.Result.Response.Text)
+	assert.Equal(t, "Cloudflare, Akamai, Fastly", result.Steps[0].ToolResults[0].Result)
+
+	assert.Equal(t, 200, result.Usage.TotalTokens, "supervisor totals include the sub-agent")
+	assert.Equal(t, 40, run.Result.Usage.TotalTokens)
+}
+
+func TestAgentBudgetCoversSubAgents(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockToolProvider{responses: []*types.TextResponse{
+		delegateStep("Dig deep", types.Usage{TotalTokens: 50}),
+		{Text: "a long report", Usage: &types.Usage{TotalTokens: 500}},
+		{Text: "never reached"},
+	}}
+	client := newAgentTestClient(provider)
+	researcher := client.Agent().Using("mock").Model("mock-model").
+		AddTool("search", "Search", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+			return "results", nil
+		})
+
+	result, err := client.Agent().Using("mock").Model("mock-model").
+		AddAgent("researcher", "Research a question", researcher).
+		TokenBudget(300).
+		Run(context.Background(), "Research everything")
+	require.ErrorIs(t, err, ErrAgentBudgetExceeded)
+	assert.Len(t, provider.requests, 2, "the supervisor stops before another model call")
+	assert.Equal(t, 550, result.Usage.TotalTokens)
+	assert.Len(t, result.SubAgents, 1)
+}
+
+func TestAgentNestingDepthIsBounded(t *testing.T) {
+	t.Parallel()
+
+	builder := New(WithDiscovery(false)).Agent()
+	ctx := context.Background()
+	for range maxAgentDepth {
+		spend, err := builder.newAgentSpend(ctx)
+		require.NoError(t, err)
+		ctx = context.WithValue(ctx, agentSpendContextKey{}, spend)
+	}
+	_, err := builder.newAgentSpend(ctx)
+	assert.ErrorContains(t, err, "nested more than")
+}
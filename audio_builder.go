@@ -14,13 +14,21 @@ type AudioRequestBuilder struct {
 }
 
 func audioResponseToSTT(resp types.AudioResponse) *types.SpeechToTextResponse {
-	return &types.SpeechToTextResponse{
+	stt := &types.SpeechToTextResponse{
 		ID:       resp.ID,
 		Model:    resp.Model,
 		Text:     resp.Text,
+		Language: resp.Language,
+		Segments: resp.Segments,
+		Words:    resp.Words,
 		Created:  resp.Created,
 		Metadata: resp.Metadata,
 	}
+	if resp.Duration > 0 {
+		duration := resp.Duration
+		stt.Duration = &duration
+	}
+	return stt
 }
 
 func audioResponseToTTS(resp types.AudioResponse) *types.TextToSpeechResponse {
@@ -47,6 +55,7 @@ func executeAudioProviderRequest[T any](
 	providerName string,
 	trackingName string,
 	audioRequest types.AudioRequest,
+	scopedOptions map[string]map[string]any,
 	convert func(types.AudioResponse) *T,
 ) (*T, error) {
 	if err := w.validateModelAttempt(providerName, audioRequest.Model, nil, []types.ModelCapability{types.CapabilityAudio}); err != nil {
@@ -60,16 +69,18 @@ func executeAudioProviderRequest[T any](
 		defer release()
 
 		ctx = contextWithProviderOperation(ctx, provider, "audio")
+		scoped := audioRequest
+		scoped.ProviderOptions = mergeScopedOptions(scopedOptions[provider.Name()], scoped.ProviderOptions)
 		if w.providerMiddleware != nil {
 			handler := w.providerMiddleware.ApplyAudio(provider.Audio)
-			audioResp, err := handler(ctx, audioRequest)
+			audioResp, err := handler(ctx, scoped)
 			if err != nil {
 				return nil, err
 			}
 			return convert(*audioResp), nil
 		}
 
-		audioResp, err := provider.Audio(ctx, audioRequest)
+		audioResp, err := provider.Audio(ctx, scoped)
 		if err != nil {
 			return nil, err
 		}
@@ -103,9 +114,10 @@ func (b *AudioRequestBuilder) TextToSpeech() *TextToSpeechBuilder {
 
 // SpeechToTextBuilder builds speech-to-text requests
 type SpeechToTextBuilder struct {
-	wormhole *Wormhole
-	provider string
-	request  *types.SpeechToTextRequest
+	wormhole      *Wormhole
+	provider      string
+	request       *types.SpeechToTextRequest
+	scopedOptions map[string]map[string]any // ProviderOption values by provider name
 }
 
 // Model sets the model to use
@@ -139,36 +151,175 @@ func (b *SpeechToTextBuilder) Temperature(temp float32) *SpeechToTextBuilder {
 	return b
 }
 
-// Transcribe executes the request and returns transcribed text
-func (b *SpeechToTextBuilder) Transcribe(ctx context.Context) (*types.SpeechToTextResponse, error) {
-	// Validate request
+// ResponseFormat sets the transcript format, such as "json", "verbose_json",
+// "text", "srt", or "vtt". Subtitle and text formats come back verbatim in
+// Text.
+func (b *SpeechToTextBuilder) ResponseFormat(format string) *SpeechToTextBuilder {
+	b.request.ResponseFormat = format
+	return b
+}
+
+// TimestampGranularities asks for segment and/or word timings, returned in
+// the response's Segments and Words. On OpenAI this selects verbose_json.
+//
+// Example:
+//
+//	resp, err := client.Audio().
+//	    SpeechToText().
+//	    Model("whisper-1").
+//	    Audio(mp3Bytes, "mp3").
+//	    TimestampGranularities(types.TimestampGranularityWord).
+//	    Transcribe(ctx)
+//	for _, w := range resp.Words {
+//	    fmt.Printf("%6.2fs %s\n", w.Start, w.Word)
+//	}
+func (b *SpeechToTextBuilder) TimestampGranularities(granularities ...types.TimestampGranularity) *SpeechToTextBuilder {
+	b.request.TimestampGranularities = append([]types.TimestampGranularity(nil), granularities...)
+	return b
+}
+
+// Translate transcribes the speech into English text, whatever language is
+// spoken. Language and timestamp granularities do not apply to translations.
+func (b *SpeechToTextBuilder) Translate() *SpeechToTextBuilder {
+	b.request.Translate = true
+	return b
+}
+
+// ProviderOptions sets provider-specific options, sent as extra form fields
+// by OpenAI-compatible providers.
+func (b *SpeechToTextBuilder) ProviderOptions(options map[string]any) *SpeechToTextBuilder {
+	b.request.ProviderOptions = types.CloneMap(options)
+	return b
+}
+
+// ProviderOption sets one provider-specific option sent only to provider, as
+// TextRequestBuilder.ProviderOption does.
+func (b *SpeechToTextBuilder) ProviderOption(provider, key string, value any) *SpeechToTextBuilder {
+	b.scopedOptions = withScopedOption(b.scopedOptions, provider, key, value)
+	return b
+}
+
+// audioRequest validates the builder and converts it to an AudioRequest,
+// with the tracking operation for a transcription or a translation.
+func (b *SpeechToTextBuilder) audioRequest() (types.AudioRequest, string, error) {
 	if len(b.request.Audio) == 0 {
-		return nil, fmt.Errorf("no audio data provided")
+		return types.AudioRequest{}, "", fmt.Errorf("no audio data provided")
 	}
 	if b.request.Model == "" {
-		return nil, fmt.Errorf("no model specified")
+		return types.AudioRequest{}, "", fmt.Errorf("no model specified")
 	}
 
-	// Convert to AudioRequest
 	audioRequest := types.AudioRequest{
-		Type:        types.AudioRequestTypeSTT,
-		Model:       b.request.Model,
-		Input:       append([]byte(nil), b.request.Audio...),
-		Language:    b.request.Language,
-		Prompt:      b.request.Prompt,
-		Temperature: b.request.Temperature,
+		Type:                   types.AudioRequestTypeSTT,
+		Model:                  b.request.Model,
+		Input:                  append([]byte(nil), b.request.Audio...),
+		AudioFormat:            b.request.AudioFormat,
+		Language:               b.request.Language,
+		Prompt:                 b.request.Prompt,
+		Temperature:            b.request.Temperature,
+		ResponseFormat:         b.request.ResponseFormat,
+		TimestampGranularities: append([]types.TimestampGranularity(nil), b.request.TimestampGranularities...),
+		ProviderOptions:        b.request.ProviderOptions,
+	}
+	trackingName := "audio.stt:"
+	if b.request.Translate {
+		audioRequest.Type = types.AudioRequestTypeTranslation
+		trackingName = "audio.translation:"
+	}
+	return audioRequest, trackingName, nil
+}
+
+// Transcribe executes the request and returns transcribed text
+func (b *SpeechToTextBuilder) Transcribe(ctx context.Context) (*types.SpeechToTextResponse, error) {
+	audioRequest, trackingName, err := b.audioRequest()
+	if err != nil {
+		return nil, err
 	}
 
 	providerScope := resolveAudioProvider(b.provider, b.wormhole)
 
-	return executeAudioProviderRequest(ctx, b.wormhole, b.provider, "audio.stt:"+providerScope, audioRequest, audioResponseToSTT)
+	return executeAudioProviderRequest(ctx, b.wormhole, b.provider, trackingName+providerScope, audioRequest, b.scopedOptions, audioResponseToSTT)
+}
+
+// Stream transcribes the audio and streams the transcript text as it is
+// produced. Each chunk carries a text delta; the last has a finish reason and
+// usage. The provider must implement types.TranscriptionStreamer, as OpenAI
+// does for its gpt-4o-transcribe models; translations cannot be streamed.
+// Provider middleware implementing types.AudioStreamMiddleware wraps the
+// stream.
+//
+// Example:
+//
+//	chunks, err := client.Audio().
+//	    SpeechToText().
+//	    Model("gpt-4o-mini-transcribe").
+//	    Audio(wavBytes, "wav").
+//	    Stream(ctx)
+//	for chunk := range chunks {
+//	    fmt.Print(chunk.Content())
+//	}
+func (b *SpeechToTextBuilder) Stream(ctx context.Context) (<-chan types.TextChunk, error) {
+	audioRequest, _, err := b.audioRequest()
+	if err != nil {
+		return nil, err
+	}
+	if audioRequest.Type == types.AudioRequestTypeTranslation {
+		return nil, types.ErrInvalidRequest.WithDetails("translations cannot be streamed; use Transcribe")
+	}
+	w := b.wormhole
+	if err := w.validateModelAttempt(b.provider, audioRequest.Model, nil, []types.ModelCapability{types.CapabilityAudio}); err != nil {
+		return nil, err
+	}
+
+	if !w.trackRequest() {
+		return nil, fmt.Errorf("client is shutting down")
+	}
+	provider, release, err := w.leaseProvider(b.provider)
+	if err != nil {
+		w.untrackRequest()
+		return nil, err
+	}
+	stream, err := b.openTranscriptionStream(ctx, provider, audioRequest)
+	if err != nil {
+		release()
+		w.untrackRequest()
+		return nil, err
+	}
+
+	out := make(chan types.TextChunk)
+	go func() {
+		defer close(out)
+		defer w.untrackRequest()
+		defer release()
+		for chunk := range stream {
+			if !sendStreamChunk(ctx, out, chunk) {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (b *SpeechToTextBuilder) openTranscriptionStream(ctx context.Context, provider types.Provider, request types.AudioRequest) (<-chan types.TextChunk, error) {
+	streamer, ok := provider.(types.TranscriptionStreamer)
+	if !ok {
+		return nil, types.NewWormholeError(types.ErrorCodeProvider, fmt.Sprintf("%s provider does not support streamed transcription", provider.Name()), false)
+	}
+	ctx = contextWithProviderOperation(ctx, provider, "audio")
+	request.ProviderOptions = mergeScopedOptions(b.scopedOptions[provider.Name()], request.ProviderOptions)
+	handler := types.AudioStreamHandler(streamer.StreamTranscription)
+	if b.wormhole.providerMiddleware != nil {
+		handler = b.wormhole.providerMiddleware.ApplyAudioStream(handler)
+	}
+	return handler(ctx, request)
 }
 
 // TextToSpeechBuilder builds text-to-speech requests
 type TextToSpeechBuilder struct {
-	wormhole *Wormhole
-	provider string
-	request  *types.TextToSpeechRequest
+	wormhole      *Wormhole
+	provider      string
+	request       *types.TextToSpeechRequest
+	scopedOptions map[string]map[string]any // ProviderOption values by provider name
 }
 
 // Model sets the model to use
@@ -201,6 +352,19 @@ func (b *TextToSpeechBuilder) ResponseFormat(format string) *TextToSpeechBuilder
 	return b
 }
 
+// ProviderOptions sets provider-specific options
+func (b *TextToSpeechBuilder) ProviderOptions(options map[string]any) *TextToSpeechBuilder {
+	b.request.ProviderOptions = types.CloneMap(options)
+	return b
+}
+
+// ProviderOption sets one provider-specific option sent only to provider, as
+// TextRequestBuilder.ProviderOption does.
+func (b *TextToSpeechBuilder) ProviderOption(provider, key string, value any) *TextToSpeechBuilder {
+	b.scopedOptions = withScopedOption(b.scopedOptions, provider, key, value)
+	return b
+}
+
 // Generate executes the request and returns audio
 func (b *TextToSpeechBuilder) Generate(ctx context.Context) (*types.TextToSpeechResponse, error) {
 	// Validate request
@@ -227,5 +391,5 @@ func (b *TextToSpeechBuilder) Generate(ctx context.Context) (*types.TextToSpeech
 
 	providerScope := resolveAudioProvider(b.provider, b.wormhole)
 
-	return executeAudioProviderRequest(ctx, b.wormhole, b.provider, "audio.tts:"+providerScope, audioRequest, audioResponseToTTS)
+	return executeAudioProviderRequest(ctx, b.wormhole, b.provider, "audio.tts:"+providerScope, audioRequest, b.scopedOptions, audioResponseToTTS)
 }
@@ -2,12 +2,18 @@ package wormhole_test
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/middleware"
 	"github.com/garyblankenship/wormhole/v2/types"
 	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
 )
@@ -68,6 +74,34 @@ func TestSpeechToTextBuilder(t *testing.T) {
 		assert.Equal(t, "mock-stt", resp.ID)
 	})
 
+	t.Run("translation with timestamps passes options through", func(t *testing.T) {
+		t.Parallel()
+		recorder := mocktesting.NewMockProvider("recorder")
+		recClient := wormhole.New(
+			wormhole.WithDefaultProvider("recorder"),
+			wormhole.WithCustomProvider("recorder", mocktesting.MockProviderFactory(recorder)),
+			wormhole.WithProviderConfig("recorder", types.ProviderConfig{}),
+		)
+
+		_, err := recClient.Audio().
+			SpeechToText().
+			Model("whisper-1").
+			Audio([]byte("data"), "mp3").
+			ResponseFormat("verbose_json").
+			TimestampGranularities(types.TimestampGranularityWord).
+			Translate().
+			Transcribe(ctx)
+		require.NoError(t, err)
+
+		calls := recorder.Calls()
+		require.Len(t, calls, 1)
+		request := calls[0].Request.(types.AudioRequest)
+		assert.Equal(t, types.AudioRequestTypeTranslation, request.Type)
+		assert.Equal(t, "mp3", request.AudioFormat)
+		assert.Equal(t, "verbose_json", request.ResponseFormat)
+		assert.Equal(t, []types.TimestampGranularity{types.TimestampGranularityWord}, request.TimestampGranularities)
+	})
+
 	t.Run("provider error", func(t *testing.T) {
 		t.Parallel()
 		errProvider := mocktesting.NewMockProvider("err-provider").WithError("stt provider failure")
@@ -181,3 +215,103 @@ func TestTextToSpeechBuilder(t *testing.T) {
 		assert.Contains(t, err.Error(), "tts failed")
 	})
 }
+
+func TestAudioBuildersSendProviderOptions(t *testing.T) {
+	t.Parallel()
+	var form map[string][]string
+	var speech map[string]any
+	server := testutil.MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/audio/transcriptions":
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			form = r.MultipartForm.Value
+			_, _ = io.WriteString(w, `{"text":"hello"}`)
+		case "/audio/speech":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&speech))
+			_, _ = w.Write([]byte("mp3"))
+		}
+	})
+	client := wormhole.New(wormhole.WithOpenAI("test-key", types.ProviderConfig{BaseURL: server.URL}))
+	ctx := context.Background()
+
+	_, err := client.Audio().SpeechToText().
+		Model("gpt-4o-transcribe").
+		Audio([]byte("wav"), "wav").
+		ProviderOptions(map[string]any{"chunking_strategy": "auto"}).
+		ProviderOption("openai", "include", []string{"logprobs"}).
+		ProviderOption("groq", "ignored", true).
+		Transcribe(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auto"}, form["chunking_strategy"])
+	assert.Equal(t, []string{`["logprobs"]`}, form["include"])
+	assert.NotContains(t, form, "ignored", "options scoped to another provider are dropped")
+
+	_, err = client.Audio().TextToSpeech().
+		Model("gpt-4o-mini-tts").
+		Input("Hello").
+		Voice("alloy").
+		ProviderOption("openai", "instructions", "Speak cheerfully").
+		Generate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Speak cheerfully", speech["instructions"])
+}
+
+func TestSpeechToTextStreamRunsProviderMiddleware(t *testing.T) {
+	t.Parallel()
+	var form map[string][]string
+	server := testutil.MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		form = r.MultipartForm.Value
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\"Hello\"}\n\n"+
+			"data: {\"type\":\"transcript.text.done\",\"text\":\"Hello\",\"usage\":{\"input_tokens\":4,\"output_tokens\":1,\"total_tokens\":5}}\n\n")
+	})
+	var hooked types.AudioRequest
+	hooks := middleware.Hooks{
+		AudioStream: func(ctx context.Context, req types.AudioRequest, next func(context.Context, types.AudioRequest) (<-chan types.TextChunk, error)) (<-chan types.TextChunk, error) {
+			hooked = req
+			return next(ctx, req)
+		},
+	}
+	capture := mocktesting.CaptureRequests()
+	client := wormhole.New(
+		wormhole.WithOpenAI("test-key", types.ProviderConfig{BaseURL: server.URL}),
+		wormhole.WithProviderMiddleware(hooks, capture),
+	)
+
+	stream, err := client.Audio().SpeechToText().
+		Model("gpt-4o-mini-transcribe").
+		Audio([]byte("wav"), "wav").
+		ProviderOption("openai", "chunking_strategy", "auto").
+		Stream(context.Background())
+	require.NoError(t, err)
+	chunks, ok := testutil.DrainStream(stream, 5*time.Second)
+	require.True(t, ok, "stream did not close")
+	assert.Equal(t, "Hello", testutil.MergeTextChunks(chunks).Text)
+	assert.Equal(t, "gpt-4o-mini-transcribe", hooked.Model)
+	assert.Equal(t, "auto", hooked.ProviderOptions["chunking_strategy"])
+	assert.Len(t, capture.Requests(), 1)
+	assert.Equal(t, []string{"true"}, form["stream"])
+
+	_, err = client.Audio().SpeechToText().
+		Model("whisper-1").
+		Audio([]byte("wav"), "wav").
+		Translate().
+		Stream(context.Background())
+	assert.ErrorContains(t, err, "cannot be streamed")
+}
+
+func TestSpeechToTextStreamRequiresTranscriptionStreamer(t *testing.T) {
+	t.Parallel()
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(mocktesting.NewMockProvider("mock"))),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+	)
+
+	_, err := client.Audio().SpeechToText().
+		Model("whisper-1").
+		Audio([]byte("wav"), "wav").
+		Stream(context.Background())
+	assert.ErrorContains(t, err, "does not support streamed transcription")
+}
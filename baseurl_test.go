@@ -216,3 +216,33 @@ func TestBaseURLValidation(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+type countingTransport struct {
+	next  http.RoundTripper
+	count *atomic.Int32
+}
+
+func (c countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.count.Add(1)
+	return c.next.RoundTrip(req)
+}
+
+func TestBaseURLKeepsTransportWrapper(t *testing.T) {
+	t.Parallel()
+	defaultServer := newBaseURLTestServer(t)
+	overrideServer := newBaseURLTestServer(t)
+	var wrapped atomic.Int32
+	client := New(
+		WithDefaultProvider("openai"),
+		WithOpenAICompatible("openai", defaultServer.URL, types.ProviderConfig{APIKey: "test-key"}.
+			WithTransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+				return countingTransport{next: next, count: &wrapped}
+			})),
+	)
+
+	_, err := client.Text().Model("test-model").Prompt("test").Generate(context.Background())
+	require.NoError(t, err)
+	_, err = client.Text().BaseURL(overrideServer.URL).Model("test-model").Prompt("test").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), wrapped.Load())
+}
@@ -148,6 +148,9 @@ func executeRequestWithLimiter(ctx context.Context, req *TextRequestBuilder, lim
 
 	release, ok := limiter.AcquireTokenWithProvider(ctx, provider, model)
 	if !ok {
+		if ctx.Err() == nil {
+			return nil, types.ErrLoadShed
+		}
 		return nil, ctx.Err()
 	}
 	defer release()
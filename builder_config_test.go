@@ -26,6 +26,13 @@ func TestTextRequestBuilderConfiguration(t *testing.T) {
 		PresencePenalty(-0.3).
 		Seed(42).
 		ParallelToolCalls(false).
+		TopLogprobs(5).
+		N(3).
+		WebSearch(types.WebSearch{MaxUses: 2}).
+		TopK(40).
+		MinP(0.05).
+		RepetitionPenalty(1.1).
+		LogitBias(map[int]int{50256: -100}).
 		MaxTokens(128).
 		Reasoning(types.Reasoning{Effort: "high"}).
 		Stop("END").
@@ -49,6 +56,18 @@ func TestTextRequestBuilderConfiguration(t *testing.T) {
 	if *builder.request.FrequencyPenalty != 0.4 || *builder.request.PresencePenalty != -0.3 || *builder.request.Seed != 42 || *builder.request.ParallelToolCalls {
 		t.Fatalf("extended sampling config = %#v", builder.request.BaseRequest)
 	}
+	if !builder.request.Logprobs || builder.request.TopLogprobs != 5 {
+		t.Fatalf("logprobs config = logprobs:%v top:%d", builder.request.Logprobs, builder.request.TopLogprobs)
+	}
+	if builder.request.N != 3 {
+		t.Fatalf("n = %d, want 3", builder.request.N)
+	}
+	if builder.request.WebSearch == nil || builder.EnableWebSearch().request.WebSearch.MaxUses != 2 {
+		t.Fatalf("web search config = %#v", builder.request.WebSearch)
+	}
+	if *builder.request.TopK != 40 || *builder.request.MinP != 0.05 || *builder.request.RepetitionPenalty != 1.1 || builder.request.LogitBias[50256] != -100 {
+		t.Fatalf("provider sampling config = %#v", builder.request.BaseRequest)
+	}
 	if *builder.request.MaxTokens != 128 || builder.request.Stop[0] != "END" {
 		t.Fatalf("limit config = %#v", builder.request)
 	}
@@ -78,8 +97,11 @@ func TestTextRequestBuilderCloneDetachesNestedState(t *testing.T) {
 	options := map[string]any{"nested": map[string]any{"value": "original"}}
 	format := map[string]any{"schema": map[string]any{"type": "object"}}
 
-	builder := client.Text().Messages(message).Tools(tool).ProviderOptions(options).ResponseFormat(format)
+	builder := client.Text().Messages(message).Tools(tool).ProviderOptions(options).ResponseFormat(format).LogitBias(map[int]int{1: 5}).
+		WebSearch(types.WebSearch{AllowedDomains: []string{"go.dev"}})
 	clone := builder.Clone()
+	clone.request.LogitBias[1] = -5
+	clone.request.WebSearch.AllowedDomains[0] = "example.com"
 	clone.request.Messages[0].(*types.UserMessage).Media[0].(*types.ImageMedia).Data[0] = 'X'
 	clone.request.Tools[0].InputSchema["properties"].(map[string]any)["query"].(map[string]any)["type"] = "number"
 	clone.request.ProviderOptions["nested"].(map[string]any)["value"] = "changed"
@@ -97,6 +119,12 @@ func TestTextRequestBuilderCloneDetachesNestedState(t *testing.T) {
 	if got := builder.request.ResponseFormat.(map[string]any)["schema"].(map[string]any)["type"]; got != "object" {
 		t.Fatalf("original response format = %v", got)
 	}
+	if got := builder.request.LogitBias[1]; got != 5 {
+		t.Fatalf("original logit bias = %d", got)
+	}
+	if got := builder.request.WebSearch.AllowedDomains[0]; got != "go.dev" {
+		t.Fatalf("original web search domain = %q", got)
+	}
 }
 
 // TestWithToolsDisabledIsNotNoOp reproduces a bug where WithToolsDisabled()
@@ -175,6 +203,14 @@ func TestTextRequestBuilderConversationCloneValidateAndJSON(t *testing.T) {
 		t.Fatal("invalid Validate returned nil")
 	}
 	assertPanics(t, func() { invalid.MustValidate() })
+
+	sampling := client.Text().Model("gpt-5").Prompt("hi").TopK(0).MinP(1.5).RepetitionPenalty(0).LogitBias(map[int]int{1: 101}).N(129)
+	err = sampling.Validate()
+	for _, field := range []string{"top_k", "min_p", "repetition_penalty", "logit_bias", "n"} {
+		if err == nil || !strings.Contains(err.Error(), field) {
+			t.Fatalf("sampling Validate error = %v, want %s", err, field)
+		}
+	}
 }
 
 func TestTextRequestBuilderGenerateAndStreamValidation(t *testing.T) {
@@ -192,6 +228,9 @@ func TestTextRequestBuilderGenerateAndStreamValidation(t *testing.T) {
 	if _, err := client.Text().Model("gpt-5").Stream(ctx); err == nil {
 		t.Fatal("Stream without messages returned nil error")
 	}
+	if _, err := client.Text().Model("gpt-5").Prompt("hello").N(2).Stream(ctx); err == nil || !strings.Contains(err.Error(), "cannot be streamed") {
+		t.Fatalf("Stream with n=2 error = %v, want cannot be streamed", err)
+	}
 	if _, _, err := client.Text().Model("gpt-5").StreamAndAccumulate(ctx); err == nil {
 		t.Fatal("StreamAndAccumulate without messages returned nil error")
 	}
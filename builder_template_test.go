@@ -0,0 +1,89 @@
+package wormhole
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestTextBuilderTemplateConcurrentClones(t *testing.T) {
+	t.Parallel()
+
+	provider := &requestCapturingProvider{BaseProvider: types.NewBaseProvider("mock")}
+	client := constraintsTestClient(provider)
+	template := client.Text().Model("gpt-4o").SystemPrompt("Be terse.").Temperature(0.3).
+		Tools(*types.NewTool("lookup", "Look up a record", map[string]any{"type": "object"}))
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := template.Clone().Prompt(fmt.Sprintf("question %d", i)).Temperature(float32(i) / 10).Generate(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, provider.requests, 16)
+	seen := map[string]bool{}
+	for _, request := range provider.requests {
+		require.Len(t, request.Messages, 2)
+		assert.Equal(t, "Be terse.", request.Messages[0].GetContent())
+		seen[request.Messages[1].GetContent().(string)] = true
+		assert.Len(t, request.Tools, 1)
+	}
+	assert.Len(t, seen, 16)
+	assert.Empty(t, template.request.Messages, "clones do not write through to the template")
+	assert.InDelta(t, 0.3, *template.request.Temperature, 1e-6)
+}
+
+func TestTextFromSavedRequest(t *testing.T) {
+	t.Parallel()
+
+	provider := &requestCapturingProvider{BaseProvider: types.NewBaseProvider("mock")}
+	client := constraintsTestClient(provider)
+	saved := client.Text().Model("gpt-4o").SystemPrompt("Answer in French.").MaxTokens(64).Stop("END").Request()
+
+	_, err := client.TextFrom(saved).Prompt("hello").Generate(context.Background())
+	require.NoError(t, err)
+	request := provider.last()
+	assert.Equal(t, "gpt-4o", request.Model)
+	assert.Equal(t, 64, *request.MaxTokens)
+	assert.Equal(t, []string{"END"}, request.Stop)
+	assert.Equal(t, types.RoleSystem, request.Messages[0].GetRole())
+
+	builder := client.TextFrom(saved).Stop("STOP")
+	builder.request.Stop[0] = "mutated"
+	*builder.request.MaxTokens = 1
+	assert.Equal(t, []string{"END"}, saved.Stop, "the template is copied")
+	assert.Equal(t, 64, *saved.MaxTokens)
+}
+
+func TestStructuredBuilderCloneAndStructuredFrom(t *testing.T) {
+	t.Parallel()
+
+	client := New(WithDefaultProvider("openai"), WithOpenAI("test-key"), WithModelValidation(false), WithDiscovery(false))
+	schema := map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}}
+	template := client.Structured().Using("openai").Model("gpt-5-mini").Schema(schema).SchemaName("person").
+		Strict(true).RepairAttempts(2).Temperature(0.1)
+
+	clone := template.Clone().Prompt("Ada").Model("gpt-5")
+	assert.Equal(t, "gpt-5-mini", template.request.Model)
+	assert.Empty(t, template.request.Messages)
+	assert.Equal(t, "openai", clone.getProvider())
+	assert.Equal(t, 2, clone.repairAttempts)
+	assert.Equal(t, types.StructuredModeStrict, clone.request.Mode)
+	assert.Equal(t, template.request.Schema, clone.request.Schema)
+
+	from := client.StructuredFrom(template.Request())
+	assert.Equal(t, "person", from.request.SchemaName)
+	assert.Equal(t, types.StructuredModeStrict, from.request.Mode)
+	assert.InDelta(t, 0.1, *from.request.Temperature, 1e-6)
+}
@@ -0,0 +1,121 @@
+package wormhole_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestCacheInfoAggregatesInMetrics(t *testing.T) {
+	t.Parallel()
+
+	collector := middleware.NewEnhancedMetricsCollector(&middleware.EnhancedMetricsConfig{
+		DefaultHistogramBuckets: []float64{100},
+		EnableLabels:            true,
+		LabelAggregation:        true,
+	})
+	cache := middleware.NewMemoryCache(10)
+	defer cache.Close()
+	provider := mocktesting.NewMockProvider("mock").WithTextResponse(types.TextResponse{
+		Text:  "ok",
+		Usage: &types.Usage{PromptTokens: 1000, CompletionTokens: 20, CacheReadTokens: 900},
+	})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+		// Metrics must wrap the cache to see its hits.
+		wormhole.WithProviderMiddleware(middleware.NewTypedEnhancedMetricsMiddleware(collector)),
+		wormhole.WithMiddleware(middleware.CacheMiddleware(middleware.CacheConfig{Cache: cache, TTL: time.Minute})),
+	)
+	defer client.Close()
+
+	ctx := context.Background()
+	fresh, err := client.Text().Model("m").Prompt("long shared prefix").Generate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, types.CacheInfo{PromptTokens: 1000, CompletionTokens: 20, CachedTokens: 900}, fresh.CacheInfo())
+
+	hit, err := client.Text().Model("m").Prompt("long shared prefix").Generate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, types.CacheSourceLocal, hit.CacheInfo().Source)
+
+	stats := collector.GetStats(&middleware.RequestLabels{Provider: "mock", Model: "m", Method: "text"})
+	assert.Equal(t, int64(2), stats["requests"])
+	assert.Equal(t, int64(1), stats["cache_hits"])
+	assert.Equal(t, int64(900), stats["cached_tokens"])
+	assert.InDelta(t, 0.9, stats["cached_token_ratio"], 1e-9)
+	assert.Equal(t, int64(900+1020), stats["cache_saved_tokens"])
+}
+
+// ttlRecordingCache records the TTL of every stored key.
+type ttlRecordingCache struct {
+	*middleware.MemoryCache
+	mu   sync.Mutex
+	ttls map[string]time.Duration
+	gets int
+}
+
+func (c *ttlRecordingCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	c.gets++
+	c.mu.Unlock()
+	return c.MemoryCache.Get(key)
+}
+
+func (c *ttlRecordingCache) Set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	c.ttls[key] = ttl
+	c.mu.Unlock()
+	c.MemoryCache.Set(key, value, ttl)
+}
+
+func TestBuilderCacheControlAndCacheKey(t *testing.T) {
+	t.Parallel()
+
+	cache := &ttlRecordingCache{MemoryCache: middleware.NewMemoryCache(10), ttls: map[string]time.Duration{}}
+	defer cache.Close()
+	provider := mocktesting.NewMockProvider("mock").WithTextResponse(types.TextResponse{Text: "ok"})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+		wormhole.WithMiddleware(middleware.CacheMiddleware(middleware.CacheConfig{Cache: cache, TTL: time.Minute})),
+	)
+	defer client.Close()
+	ctx := context.Background()
+
+	request := client.Text().Model("m").SystemPrompt("brief").Prompt("faq").Temperature(0.2).Cache(time.Hour)
+	key, err := request.CacheKey()
+	require.NoError(t, err)
+	assert.Regexp(t, `^mock:[0-9a-f]{64}$`, key)
+
+	_, err = request.Generate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{key: time.Hour}, cache.ttls, "stored under CacheKey with the builder's TTL")
+
+	same, err := client.Text().Model("m").SystemPrompt("brief").Prompt("faq").Temperature(0.2).CacheKey()
+	require.NoError(t, err)
+	assert.Equal(t, key, same, "equal requests share a key")
+	other, err := client.Text().Model("m").SystemPrompt("brief").Prompt("faq").Temperature(0.3).CacheKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	gets := cache.gets
+	_, err = client.Text().Model("m").Prompt("fresh").NoCache().Generate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, gets, cache.gets, "NoCache skips the lookup")
+	assert.Len(t, cache.ttls, 1, "NoCache skips the store")
+}
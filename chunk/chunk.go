@@ -0,0 +1,229 @@
+// Package chunk splits documents into token-bounded pieces for embedding and
+// retrieval-augmented generation.
+//
+// Three splitters share one Options type:
+//
+//   - Fixed cuts on whitespace into chunks of at most Size tokens.
+//   - Recursive cuts on paragraph, line, sentence, then word boundaries,
+//     keeping the largest natural unit that fits.
+//   - Markdown cuts on headings first, never letting a chunk span sections,
+//     and records the governing heading on each chunk.
+//
+// Token counts come from Options.Counter. Wormhole does not ship a model
+// tokenizer; EstimateTokens (about four bytes per token) is the default and is
+// deliberately conservative for English text.
+//
+// Example:
+//
+//	chunks := chunk.Markdown(doc, chunk.Options{Size: 400, Overlap: 40})
+//	resp, err := client.Embeddings().
+//	    Model("text-embedding-3-small").
+//	    Input(chunk.Texts(chunks)...).
+//	    Generate(ctx)
+package chunk
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenCounter returns the number of tokens in text.
+type TokenCounter func(text string) int
+
+// Options controls chunk size and overlap.
+type Options struct {
+	// Size is the maximum number of tokens per chunk. Zero or negative uses 512.
+	Size int
+	// Overlap is the number of trailing tokens from the previous chunk repeated
+	// at the start of the next one. Values >= Size are clamped to Size/2.
+	Overlap int
+	// Counter counts tokens. Nil uses EstimateTokens.
+	Counter TokenCounter
+	// Separators overrides the boundaries Recursive tries, in order. The
+	// empty string, always tried last, cuts between characters.
+	Separators []string
+}
+
+// Chunk is a contiguous span of the source text.
+type Chunk struct {
+	Index   int    `json:"index"`
+	Text    string `json:"text"`
+	Start   int    `json:"start"` // byte offset of Text in the source
+	End     int    `json:"end"`   // byte offset one past the end of Text
+	Tokens  int    `json:"tokens"`
+	Heading string `json:"heading,omitempty"` // nearest Markdown heading; empty for other splitters
+}
+
+const defaultSize = 512
+
+// DefaultSeparators are the boundaries Recursive tries when Options.Separators
+// is empty: paragraphs, lines, sentences, then words.
+var DefaultSeparators = []string{"\n\n", "\n", ". ", " "}
+
+// EstimateTokens approximates a token count as one token per four bytes,
+// rounding up. It over-counts slightly for English prose, which keeps chunks
+// under provider limits when no real tokenizer is available.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// Texts returns the text of each chunk, ready for EmbeddingsRequestBuilder.Input.
+func Texts(chunks []Chunk) []string {
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		texts[i] = c.Text
+	}
+	return texts
+}
+
+// Fixed splits text on whitespace into chunks of at most Size tokens with
+// Overlap tokens repeated between neighbors. Words longer than Size are cut
+// between characters.
+func Fixed(text string, opts Options) []Chunk {
+	opts.Separators = []string{" "}
+	return Recursive(text, opts)
+}
+
+// Recursive splits text on the first separator that yields pieces no larger
+// than Size, recursing into oversized pieces with the remaining separators,
+// then packs adjacent pieces into chunks of at most Size tokens.
+func Recursive(text string, opts Options) []Chunk {
+	s := newSplitter(text, opts)
+	pieces := s.split(0, len(text), s.separators)
+	return s.merge(pieces, "", nil)
+}
+
+type span struct {
+	start, end int
+}
+
+type splitter struct {
+	source     string
+	size       int
+	overlap    int
+	count      TokenCounter
+	separators []string
+}
+
+func newSplitter(source string, opts Options) *splitter {
+	s := &splitter{
+		source:     source,
+		size:       opts.Size,
+		overlap:    opts.Overlap,
+		count:      opts.Counter,
+		separators: opts.Separators,
+	}
+	if s.size <= 0 {
+		s.size = defaultSize
+	}
+	if s.overlap < 0 {
+		s.overlap = 0
+	}
+	if s.overlap >= s.size {
+		s.overlap = s.size / 2
+	}
+	if s.count == nil {
+		s.count = EstimateTokens
+	}
+	if len(s.separators) == 0 {
+		s.separators = DefaultSeparators
+	}
+	return s
+}
+
+func (s *splitter) tokens(sp span) int {
+	return s.count(s.source[sp.start:sp.end])
+}
+
+// split returns spans covering [start, end) that each fit in s.size tokens.
+// Separators stay attached to the end of the preceding span so the spans
+// concatenate back to the source.
+func (s *splitter) split(start, end int, separators []string) []span {
+	whole := span{start, end}
+	if start >= end {
+		return nil
+	}
+	if s.tokens(whole) <= s.size {
+		return []span{whole}
+	}
+	for i, sep := range separators {
+		if sep == "" || !strings.Contains(s.source[start:end], sep) {
+			continue
+		}
+		var out []span
+		pos := start
+		for pos < end {
+			idx := strings.Index(s.source[pos:end], sep)
+			next := end
+			if idx >= 0 {
+				next = pos + idx + len(sep)
+			}
+			out = append(out, s.split(pos, next, separators[i+1:])...)
+			pos = next
+		}
+		return out
+	}
+	return s.splitRunes(start, end)
+}
+
+// splitRunes cuts between characters when no separator applies.
+func (s *splitter) splitRunes(start, end int) []span {
+	var out []span
+	pos := start
+	for pos < end {
+		cut := pos
+		for cut < end {
+			_, width := utf8.DecodeRuneInString(s.source[cut:end])
+			if cut > pos && s.tokens(span{pos, cut + width}) > s.size {
+				break
+			}
+			cut += width
+		}
+		out = append(out, span{pos, cut})
+		pos = cut
+	}
+	return out
+}
+
+// merge packs adjacent spans into chunks of at most s.size tokens, starting
+// each new chunk with trailing spans of the previous one worth up to
+// s.overlap tokens. Chunks are appended to out.
+func (s *splitter) merge(pieces []span, heading string, out []Chunk) []Chunk {
+	first := 0
+	for first < len(pieces) {
+		last := first
+		for last+1 < len(pieces) && s.tokens(span{pieces[first].start, pieces[last+1].end}) <= s.size {
+			last++
+		}
+		out = s.appendChunk(out, span{pieces[first].start, pieces[last].end}, heading)
+		if last+1 >= len(pieces) {
+			break
+		}
+
+		next := last + 1
+		if s.overlap > 0 {
+			for next-1 > first &&
+				s.tokens(span{pieces[next-1].start, pieces[last].end}) <= s.overlap &&
+				s.tokens(span{pieces[next-1].start, pieces[last+1].end}) <= s.size {
+				next--
+			}
+		}
+		first = next
+	}
+	return out
+}
+
+func (s *splitter) appendChunk(out []Chunk, sp span, heading string) []Chunk {
+	text := s.source[sp.start:sp.end]
+	if strings.TrimSpace(text) == "" {
+		return out
+	}
+	return append(out, Chunk{
+		Index:   len(out),
+		Text:    text,
+		Start:   sp.start,
+		End:     sp.end,
+		Tokens:  s.count(text),
+		Heading: heading,
+	})
+}
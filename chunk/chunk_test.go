@@ -0,0 +1,94 @@
+package chunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordCounter counts whitespace-separated words, which keeps expectations
+// readable.
+func wordCounter(text string) int {
+	return len(strings.Fields(text))
+}
+
+func assertSpansMatchSource(t *testing.T, source string, chunks []Chunk) {
+	t.Helper()
+	for i, c := range chunks {
+		assert.Equal(t, i, c.Index)
+		assert.Equal(t, source[c.Start:c.End], c.Text)
+	}
+}
+
+func TestFixedRespectsSizeAndOverlap(t *testing.T) {
+	t.Parallel()
+
+	text := "one two three four five six seven eight nine ten"
+	chunks := Fixed(text, Options{Size: 4, Overlap: 1, Counter: wordCounter})
+
+	require.Equal(t, []string{
+		"one two three four ",
+		"four five six seven ",
+		"seven eight nine ten",
+	}, Texts(chunks))
+	for _, c := range chunks {
+		assert.LessOrEqual(t, c.Tokens, 4)
+	}
+	assertSpansMatchSource(t, text, chunks)
+}
+
+func TestFixedCutsOversizedWords(t *testing.T) {
+	t.Parallel()
+
+	chunks := Fixed("abcdefghij", Options{Size: 1})
+	require.Equal(t, []string{"abcd", "efgh", "ij"}, Texts(chunks))
+}
+
+func TestRecursivePrefersParagraphBoundaries(t *testing.T) {
+	t.Parallel()
+
+	text := "alpha beta gamma.\n\ndelta epsilon.\n\nzeta eta theta iota kappa lambda mu."
+	chunks := Recursive(text, Options{Size: 5, Counter: wordCounter})
+
+	require.Len(t, chunks, 3)
+	assert.Equal(t, "alpha beta gamma.\n\ndelta epsilon.\n\n", chunks[0].Text)
+	assert.Equal(t, "zeta eta theta iota kappa ", chunks[1].Text)
+	assert.Equal(t, "lambda mu.", chunks[2].Text)
+	assertSpansMatchSource(t, text, chunks)
+}
+
+func TestRecursiveSmallTextIsOneChunk(t *testing.T) {
+	t.Parallel()
+
+	chunks := Recursive("short text", Options{})
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "short text", chunks[0].Text)
+	assert.Equal(t, EstimateTokens("short text"), chunks[0].Tokens)
+	assert.Empty(t, Recursive("", Options{}))
+}
+
+func TestMarkdownSplitsOnHeadings(t *testing.T) {
+	t.Parallel()
+
+	text := "Intro line.\n# Install\nRun go get.\n```sh\n# not a heading\n```\n## Usage\nCall New.\n"
+	chunks := Markdown(text, Options{Size: 100, Counter: wordCounter})
+
+	require.Len(t, chunks, 3)
+	assert.Equal(t, "", chunks[0].Heading)
+	assert.Equal(t, "Intro line.\n", chunks[0].Text)
+	assert.Equal(t, "Install", chunks[1].Heading)
+	assert.Contains(t, chunks[1].Text, "# not a heading")
+	assert.Equal(t, "Usage", chunks[2].Heading)
+	assert.Equal(t, "## Usage\nCall New.\n", chunks[2].Text)
+	assertSpansMatchSource(t, text, chunks)
+}
+
+func TestOptionsClampOverlap(t *testing.T) {
+	t.Parallel()
+
+	s := newSplitter("", Options{Size: 10, Overlap: 50})
+	assert.Equal(t, 5, s.overlap)
+	assert.Equal(t, defaultSize, newSplitter("", Options{}).size)
+}
@@ -0,0 +1,73 @@
+package chunk
+
+import "strings"
+
+// markdownSeparators prefer code-fence and paragraph boundaries inside a
+// section before falling back to lines, sentences, and words.
+var markdownSeparators = []string{"\n```", "\n\n", "\n", ". ", " "}
+
+// Markdown splits text into sections at ATX headings (lines starting with
+// "#"), ignoring headings inside fenced code blocks, then splits each section
+// like Recursive. Chunks never span sections, and each carries the heading
+// text of its section. Options.Separators, when set, replaces the in-section
+// separators.
+func Markdown(text string, opts Options) []Chunk {
+	if len(opts.Separators) == 0 {
+		opts.Separators = markdownSeparators
+	}
+	s := newSplitter(text, opts)
+
+	var out []Chunk
+	for _, section := range markdownSections(text) {
+		pieces := s.split(section.start, section.end, s.separators)
+		out = s.merge(pieces, section.heading, out)
+	}
+	return out
+}
+
+type markdownSection struct {
+	span
+	heading string
+}
+
+func markdownSections(text string) []markdownSection {
+	var sections []markdownSection
+	current := markdownSection{}
+	inFence := false
+
+	pos := 0
+	for pos < len(text) {
+		lineEnd := strings.IndexByte(text[pos:], '\n')
+		next := len(text)
+		if lineEnd >= 0 {
+			next = pos + lineEnd + 1
+		}
+		line := strings.TrimRight(text[pos:next], "\r\n")
+		trimmed := strings.TrimLeft(line, " ")
+
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inFence = !inFence
+		case !inFence && isMarkdownHeading(trimmed):
+			if pos > current.start {
+				current.end = pos
+				sections = append(sections, current)
+			}
+			current = markdownSection{span: span{start: pos}, heading: strings.TrimSpace(strings.TrimLeft(trimmed, "#"))}
+		}
+		pos = next
+	}
+	if len(text) > current.start {
+		current.end = len(text)
+		sections = append(sections, current)
+	}
+	return sections
+}
+
+func isMarkdownHeading(line string) bool {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	return level >= 1 && level <= 6 && (level == len(line) || line[level] == ' ' || line[level] == '\t')
+}
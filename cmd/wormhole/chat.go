@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	wormhole "github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// chatHistoryLimit caps the lines kept in the history file.
+const chatHistoryLimit = 1000
+
+// chatSession is the state /save writes and /load and --load read.
+type chatSession struct {
+	Provider string        `json:"provider,omitempty"`
+	Model    string        `json:"model,omitempty"`
+	System   string        `json:"system,omitempty"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatREPL holds one interactive chat.
+type chatREPL struct {
+	client  *wormhole.Wormhole
+	session chatSession
+	history *chatHistory
+	timeout time.Duration
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+func runChat(args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	fs := flag.NewFlagSet("chat", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	provider := fs.String("provider", "", "Provider to chat with (default: the client's default provider)")
+	model := fs.String("model", "", "Model, optionally as provider/model (e.g. openai/gpt-4o)")
+	system := fs.String("system", "", "System prompt")
+	load := fs.String("load", "", "Resume a session saved with /save")
+	historyPath := fs.String("history", defaultChatHistoryPath(getenv), "File that keeps entered lines across sessions (empty disables)")
+	timeout := fs.Duration("timeout", 5*time.Minute, "Timeout per reply")
+	mockScenario := fs.String("mock-scenario", "", "Chat with a mock provider named \"mock\" from a YAML or JSON scenario file instead of real providers")
+	profileFlags := addProfileFlags(fs, getenv)
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 1
+	}
+
+	clientOpts, profile, err := profileFlags.clientOptions(getenv)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "chat: %v\n", err)
+		return 1
+	}
+	clientOpts = append(clientOpts, wormhole.WithDiscovery(false))
+	if *mockScenario != "" {
+		opts, err := scenarioClientOptions(*mockScenario)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "chat: %v\n", err)
+			return 1
+		}
+		clientOpts = append(clientOpts, opts...)
+		if *provider == "" {
+			*provider = "mock"
+		}
+	}
+
+	repl := &chatREPL{timeout: *timeout, stdout: stdout, stderr: stderr}
+	if *load != "" {
+		if err := repl.load(*load); err != nil {
+			_, _ = fmt.Fprintf(stderr, "chat: %v\n", err)
+			return 1
+		}
+	}
+	// Flags beat the loaded session, which beats the profile.
+	if profile != nil && repl.session.Provider == "" {
+		repl.session.Provider, repl.session.Model = profile.Provider, profile.Model
+	}
+	if *provider != "" {
+		repl.session.Provider = *provider
+	}
+	if *model != "" {
+		repl.setModel(*model)
+	}
+	if *system != "" {
+		repl.session.System = *system
+	}
+
+	history, err := openChatHistory(*historyPath)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "chat: %v\n", err)
+		return 1
+	}
+	repl.history = history
+
+	repl.client = wormhole.New(clientOpts...)
+	defer func() { _ = repl.client.Close() }()
+
+	_, _ = fmt.Fprintf(stdout, "Chatting with %s. Type /help for commands, /quit to exit.\n", repl.route())
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for {
+		_, _ = fmt.Fprint(stdout, "> ")
+		if !scanner.Scan() {
+			_, _ = fmt.Fprintln(stdout)
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := repl.history.add(line); err != nil {
+			_, _ = fmt.Fprintf(stderr, "chat: history: %v\n", err)
+		}
+		if strings.HasPrefix(line, "/") {
+			if quit := repl.command(line); quit {
+				return 0
+			}
+			continue
+		}
+		repl.send(line)
+	}
+	if err := scanner.Err(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "chat: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// send streams the reply to prompt. A failed or interrupted turn is dropped
+// from the session so it is not resent with the next prompt.
+func (r *chatREPL) send(prompt string) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	// Ctrl-C stops the reply instead of the chat.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	messages := make([]types.Message, 0, len(r.session.Messages)+1)
+	for _, m := range r.session.Messages {
+		if m.Role == "assistant" {
+			messages = append(messages, types.NewAssistantMessage(m.Content))
+		} else {
+			messages = append(messages, types.NewUserMessage(m.Content))
+		}
+	}
+	messages = append(messages, types.NewUserMessage(prompt))
+
+	builder := r.client.Text().Messages(messages...)
+	if r.session.Provider != "" {
+		builder.Using(r.session.Provider)
+	}
+	if r.session.Model != "" {
+		builder.Model(r.session.Model)
+	}
+	if r.session.System != "" {
+		builder.SystemPrompt(r.session.System)
+	}
+
+	resp, err := builder.StreamTo(ctx, r.stdout)
+	_, _ = fmt.Fprintln(r.stdout)
+	if err != nil {
+		_, _ = fmt.Fprintf(r.stderr, "error: %v\n", err)
+		return
+	}
+	r.session.Messages = append(r.session.Messages,
+		chatMessage{Role: "user", Content: prompt},
+		chatMessage{Role: "assistant", Content: resp.Text},
+	)
+}
+
+// command runs a slash command and reports whether the chat should end.
+func (r *chatREPL) command(line string) bool {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "/quit", "/exit":
+		return true
+	case "/help":
+		printChatHelp(r.stdout)
+	case "/switch":
+		if arg == "" {
+			_, _ = fmt.Fprintln(r.stderr, "usage: /switch <provider> [model]")
+			return false
+		}
+		provider, model, _ := strings.Cut(arg, " ")
+		r.session.Provider = provider
+		if model = strings.TrimSpace(model); model != "" {
+			r.session.Model = model
+		}
+		_, _ = fmt.Fprintf(r.stdout, "Now chatting with %s.\n", r.route())
+	case "/model":
+		if arg == "" {
+			_, _ = fmt.Fprintf(r.stdout, "%s\n", r.route())
+			return false
+		}
+		r.setModel(arg)
+		_, _ = fmt.Fprintf(r.stdout, "Now chatting with %s.\n", r.route())
+	case "/system":
+		r.session.System = arg
+		if arg == "" {
+			_, _ = fmt.Fprintln(r.stdout, "System prompt cleared.")
+			return false
+		}
+		_, _ = fmt.Fprintln(r.stdout, "System prompt set.")
+	case "/clear":
+		r.session.Messages = nil
+		_, _ = fmt.Fprintln(r.stdout, "Conversation cleared.")
+	case "/save":
+		if arg == "" {
+			_, _ = fmt.Fprintln(r.stderr, "usage: /save <file>")
+			return false
+		}
+		if err := r.save(arg); err != nil {
+			_, _ = fmt.Fprintf(r.stderr, "error: %v\n", err)
+			return false
+		}
+		_, _ = fmt.Fprintf(r.stdout, "Saved %d messages to %s.\n", len(r.session.Messages), arg)
+	case "/load":
+		if arg == "" {
+			_, _ = fmt.Fprintln(r.stderr, "usage: /load <file>")
+			return false
+		}
+		if err := r.load(arg); err != nil {
+			_, _ = fmt.Fprintf(r.stderr, "error: %v\n", err)
+			return false
+		}
+		_, _ = fmt.Fprintf(r.stdout, "Loaded %d messages; chatting with %s.\n", len(r.session.Messages), r.route())
+	case "/history":
+		n := 20
+		if arg != "" {
+			parsed, err := strconv.Atoi(arg)
+			if err != nil || parsed < 1 {
+				_, _ = fmt.Fprintln(r.stderr, "usage: /history [count]")
+				return false
+			}
+			n = parsed
+		}
+		lines := r.history.last(n)
+		for i, entry := range lines {
+			_, _ = fmt.Fprintf(r.stdout, "%4d  %s\n", len(r.history.lines)-len(lines)+i+1, entry)
+		}
+	default:
+		_, _ = fmt.Fprintf(r.stderr, "unknown command %s; type /help for commands\n", name)
+	}
+	return false
+}
+
+// setModel sets the model, switching provider too when spec is
+// provider/model for a known provider.
+func (r *chatREPL) setModel(spec string) {
+	route := parseModelRoute(spec)
+	if route.provider != "" {
+		r.session.Provider = route.provider
+	}
+	r.session.Model = route.model
+}
+
+// route describes the current provider and model for messages.
+func (r *chatREPL) route() string {
+	provider, model := r.session.Provider, r.session.Model
+	if provider == "" {
+		provider = "the default provider"
+	}
+	if model == "" {
+		return provider
+	}
+	return provider + "/" + model
+}
+
+func (r *chatREPL) save(path string) error {
+	data, err := json.MarshalIndent(r.session, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+func (r *chatREPL) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("load session: %w", err)
+	}
+	var session chatSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return fmt.Errorf("load session %s: %w", path, err)
+	}
+	for _, m := range session.Messages {
+		if m.Role != "user" && m.Role != "assistant" {
+			return fmt.Errorf("load session %s: unknown message role %q", path, m.Role)
+		}
+	}
+	r.session = session
+	return nil
+}
+
+func printChatHelp(w io.Writer) {
+	_, _ = fmt.Fprintln(w, `Commands:
+  /switch <provider> [model]  Chat with another provider
+  /model [provider/]<model>   Change the model (no argument shows the current one)
+  /system [prompt]            Set the system prompt (no argument clears it)
+  /clear                      Start a new conversation
+  /save <file>                Save the session as JSON
+  /load <file>                Resume a saved session
+  /history [count]            Show recently entered lines
+  /quit                       Exit (Ctrl-D works too)`)
+}
+
+// chatHistory keeps entered lines in a file so they survive restarts and can
+// be listed with /history. It is not line editing: the chat reads plain lines,
+// so arrow-key recall needs a wrapper such as rlwrap.
+type chatHistory struct {
+	path  string
+	lines []string
+}
+
+// defaultChatHistoryPath follows the XDG base directory layout.
+func defaultChatHistoryPath(getenv func(string) string) string {
+	if dir := getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "wormhole", "chat_history")
+	}
+	if home := getenv("HOME"); home != "" {
+		return filepath.Join(home, ".local", "state", "wormhole", "chat_history")
+	}
+	return ""
+}
+
+func openChatHistory(path string) (*chatHistory, error) {
+	history := &chatHistory{path: path}
+	if path == "" {
+		return history, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			history.lines = append(history.lines, line)
+		}
+	}
+	return history, nil
+}
+
+// add records line, rewriting the file once it grows past the limit.
+func (h *chatHistory) add(line string) error {
+	h.lines = append(h.lines, line)
+	if h.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return err
+	}
+	if len(h.lines) > chatHistoryLimit {
+		h.lines = h.lines[len(h.lines)-chatHistoryLimit:]
+		return os.WriteFile(h.path, []byte(strings.Join(h.lines, "\n")+"\n"), 0o600)
+	}
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(line + "\n"); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (h *chatHistory) last(n int) []string {
+	if n > len(h.lines) {
+		n = len(h.lines)
+	}
+	return h.lines[len(h.lines)-n:]
+}
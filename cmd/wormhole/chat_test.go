@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runChatWith swaps stdin, so tests using it must not run in parallel.
+func runChatWith(t *testing.T, input string, args ...string) (int, string, string) {
+	t.Helper()
+	original := stdin
+	t.Cleanup(func() { stdin = original })
+	stdin = strings.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"chat"}, args...), &stdout, &stderr, func(string) string { return "" })
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunChatSessionCommands(t *testing.T) {
+	dir := t.TempDir()
+	scenario := filepath.Join(dir, "scenario.json")
+	require.NoError(t, os.WriteFile(scenario, []byte(`{"rules": [
+		{"match": "pirate", "model": "model-b", "stream": ["Arr, ", "matey"]},
+		{"match": "hello", "stream": ["Hi ", "there"]}
+	]}`), 0o600))
+	history := filepath.Join(dir, "state", "history")
+	session := filepath.Join(dir, "session.json")
+
+	input := strings.Join([]string{
+		"hello",
+		"/model model-b",
+		"/system Talk like a pirate.",
+		"ahoy",
+		"/save " + session,
+		"/bogus",
+		"/quit",
+	}, "\n")
+	code, stdout, stderr := runChatWith(t, input, "--mock-scenario", scenario, "--model", "model-a", "--history", history)
+	require.Equal(t, 0, code, stderr)
+
+	assert.Contains(t, stdout, "Chatting with mock/model-a.")
+	assert.Contains(t, stdout, "Hi there\n")
+	assert.Contains(t, stdout, "Now chatting with mock/model-b.")
+	assert.Contains(t, stdout, "Arr, matey\n", "the system prompt reaches the model")
+	assert.Contains(t, stdout, "Saved 4 messages")
+	assert.Contains(t, stderr, "unknown command /bogus")
+
+	data, err := os.ReadFile(session)
+	require.NoError(t, err)
+	var saved chatSession
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, "mock", saved.Provider)
+	assert.Equal(t, "model-b", saved.Model)
+	assert.Equal(t, "Talk like a pirate.", saved.System)
+	assert.Equal(t, []chatMessage{
+		{Role: "user", Content: "hello"},
+		{Role: "assistant", Content: "Hi there"},
+		{Role: "user", Content: "ahoy"},
+		{Role: "assistant", Content: "Arr, matey"},
+	}, saved.Messages)
+
+	lines, err := os.ReadFile(history)
+	require.NoError(t, err)
+	assert.Equal(t, input+"\n", string(lines))
+
+	// A new chat resumes the saved session and recalls earlier lines.
+	code, stdout, stderr = runChatWith(t, "/history 2\n/model\n", "--mock-scenario", scenario, "--load", session, "--history", history)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "   7  /quit\n   8  /history 2\n")
+	assert.Contains(t, stdout, "mock/model-b\n")
+}
+
+func TestRunChatDropsFailedTurns(t *testing.T) {
+	dir := t.TempDir()
+	scenario := filepath.Join(dir, "scenario.json")
+	require.NoError(t, os.WriteFile(scenario, []byte(`{"rules": [{"match": "boom", "error": "overloaded", "status": 503}]}`), 0o600))
+	session := filepath.Join(dir, "session.json")
+
+	code, _, stderr := runChatWith(t, "boom\n/save "+session+"\n", "--mock-scenario", scenario, "--history", "")
+	require.Equal(t, 0, code)
+	assert.Contains(t, stderr, "error:")
+
+	data, err := os.ReadFile(session)
+	require.NoError(t, err)
+	var saved chatSession
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Empty(t, saved.Messages)
+}
+
+func TestRunChatRejectsBadSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"messages": [{"role": "tool", "content": "x"}]}`), 0o600))
+
+	code, _, stderr := runChatWith(t, "", "--load", path, "--history", "")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown message role "tool"`)
+}
+
+func TestDefaultChatHistoryPath(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"HOME": "/home/me"}
+	getenv := func(key string) string { return env[key] }
+	assert.Equal(t, filepath.Join("/home/me", ".local", "state", "wormhole", "chat_history"), defaultChatHistoryPath(getenv))
+	env["XDG_STATE_HOME"] = "/state"
+	assert.Equal(t, filepath.Join("/state", "wormhole", "chat_history"), defaultChatHistoryPath(getenv))
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	wormhole "github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// newCompareClient builds the client used by the compare command from the
+// environment and profile options. Tests replace it with a client backed by
+// mock providers.
+var newCompareClient = func(opts []wormhole.Option) *wormhole.Wormhole {
+	return wormhole.New(append(opts, wormhole.WithDiscovery(false))...)
+}
+
+// compareReport is the JSON report written by --report.
+type compareReport struct {
+	ModelA  string          `json:"model_a"`
+	ModelB  string          `json:"model_b"`
+	Results []compareResult `json:"results"`
+	Summary compareSummary  `json:"summary"`
+}
+
+type compareResult struct {
+	Prompt     string        `json:"prompt"`
+	A          compareOutput `json:"a"`
+	B          compareOutput `json:"b"`
+	Similarity *float64      `json:"similarity,omitempty"`
+}
+
+type compareOutput struct {
+	Output    string       `json:"output,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	LatencyMS int64        `json:"latency_ms"`
+	Usage     *types.Usage `json:"usage,omitempty"`
+	Cost      *float64     `json:"cost,omitempty"`
+}
+
+type compareSummary struct {
+	Prompts        int      `json:"prompts"`
+	MeanSimilarity *float64 `json:"mean_similarity,omitempty"`
+	A              modelSum `json:"a"`
+	B              modelSum `json:"b"`
+}
+
+type modelSum struct {
+	Errors        int      `json:"errors"`
+	MeanLatencyMS int64    `json:"mean_latency_ms"`
+	TotalCost     *float64 `json:"total_cost,omitempty"`
+}
+
+// modelRoute is a parsed "provider/model" flag value.
+type modelRoute struct {
+	spec     string
+	provider string
+	model    string
+}
+
+func runCompare(args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	modelA := fs.String("model-a", "", "First model as provider/model (e.g. openai/gpt-4o)")
+	modelB := fs.String("model-b", "", "Second model as provider/model (e.g. anthropic/claude-sonnet-4-5)")
+	prompt := fs.String("prompt", "", "Single prompt to compare")
+	promptFile := fs.String("prompt-file", "", "File with one prompt per line (blank lines and # comments are skipped)")
+	system := fs.String("system", "", "System prompt sent to both models")
+	maxTokens := fs.Int("max-tokens", 0, "Maximum output tokens per response (0 = provider default)")
+	timeout := fs.Duration("timeout", 2*time.Minute, "Timeout per prompt")
+	width := fs.Int("width", 120, "Output width for the side-by-side view")
+	reportPath := fs.String("report", "", "Write a JSON report to this path")
+	profileFlags := addProfileFlags(fs, getenv)
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 1
+	}
+
+	if *modelA == "" || *modelB == "" {
+		_, _ = fmt.Fprintln(stderr, "compare: --model-a and --model-b are required")
+		return 1
+	}
+	prompts, err := comparePrompts(*prompt, *promptFile)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "compare: %v\n", err)
+		return 1
+	}
+
+	clientOpts, _, err := profileFlags.clientOptions(getenv)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "compare: %v\n", err)
+		return 1
+	}
+	client := newCompareClient(clientOpts)
+	defer func() { _ = client.Close() }()
+	a, b := parseModelRoute(*modelA), parseModelRoute(*modelB)
+
+	report := compareReport{ModelA: a.spec, ModelB: b.spec}
+	for i, p := range prompts {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		var result compareResult
+		result.Prompt = p
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); result.A = compareGenerate(ctx, client, a, *system, p, *maxTokens) }()
+		go func() { defer wg.Done(); result.B = compareGenerate(ctx, client, b, *system, p, *maxTokens) }()
+		wg.Wait()
+		cancel()
+
+		if result.A.Error == "" && result.B.Error == "" {
+			similarity := textSimilarity(result.A.Output, result.B.Output)
+			result.Similarity = &similarity
+		}
+		report.Results = append(report.Results, result)
+		printComparison(stdout, i+1, len(prompts), a.spec, b.spec, result, *width)
+	}
+	report.Summary = summarizeComparison(report.Results)
+	printSummary(stdout, a.spec, b.spec, report.Summary)
+
+	if *reportPath != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err == nil {
+			err = os.WriteFile(*reportPath, append(data, '\n'), 0o644)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "compare: write report: %v\n", err)
+			return 1
+		}
+		_, _ = fmt.Fprintf(stdout, "\nReport written to %s\n", *reportPath)
+	}
+	return 0
+}
+
+// parseModelRoute splits "provider/model" when the prefix is a known provider
+// name. Anything else is a model on the default provider.
+func parseModelRoute(spec string) modelRoute {
+	if provider, model, ok := strings.Cut(spec, "/"); ok {
+		if _, known := wormhole.ProviderProfileByName(provider); known {
+			return modelRoute{spec: spec, provider: provider, model: model}
+		}
+	}
+	return modelRoute{spec: spec, model: spec}
+}
+
+func comparePrompts(prompt, path string) ([]string, error) {
+	var prompts []string
+	if prompt != "" {
+		prompts = append(prompts, prompt)
+	}
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = file.Close() }()
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			prompts = append(prompts, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("no prompts: pass --prompt or --prompt-file")
+	}
+	return prompts, nil
+}
+
+func compareGenerate(ctx context.Context, client *wormhole.Wormhole, route modelRoute, system, prompt string, maxTokens int) compareOutput {
+	builder := client.Text().Model(route.model).Prompt(prompt)
+	if route.provider != "" {
+		builder.Using(route.provider)
+	}
+	if system != "" {
+		builder.SystemPrompt(system)
+	}
+	if maxTokens > 0 {
+		builder.MaxTokens(maxTokens)
+	}
+
+	start := time.Now()
+	resp, err := builder.Generate(ctx)
+	out := compareOutput{LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		out.Error = err.Error()
+		return out
+	}
+	out.Output = resp.Text
+	out.Usage = resp.Usage
+	if resp.Usage != nil {
+		if cost, err := types.EstimateModelCost(route.model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens); err == nil {
+			out.Cost = &cost
+		}
+	}
+	return out
+}
+
+// textSimilarity is the cosine similarity of the two texts' word counts: 1
+// for the same words in any order, 0 for no words in common.
+func textSimilarity(a, b string) float64 {
+	countA, countB := wordCounts(a), wordCounts(b)
+	if len(countA) == 0 || len(countB) == 0 {
+		if len(countA) == len(countB) {
+			return 1
+		}
+		return 0
+	}
+	var dot, normA, normB float64
+	for word, n := range countA {
+		normA += float64(n * n)
+		dot += float64(n * countB[word])
+	}
+	for _, n := range countB {
+		normB += float64(n * n)
+	}
+	return math.Round(dot/math.Sqrt(normA*normB)*1000) / 1000
+}
+
+func wordCounts(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		counts[word]++
+	}
+	return counts
+}
+
+func summarizeComparison(results []compareResult) compareSummary {
+	summary := compareSummary{Prompts: len(results)}
+	var similaritySum float64
+	var similarityCount int
+	var latencyA, latencyB int64
+	for _, r := range results {
+		if r.Similarity != nil {
+			similaritySum += *r.Similarity
+			similarityCount++
+		}
+		latencyA += r.A.LatencyMS
+		latencyB += r.B.LatencyMS
+		addOutput(&summary.A, r.A)
+		addOutput(&summary.B, r.B)
+	}
+	if similarityCount > 0 {
+		mean := math.Round(similaritySum/float64(similarityCount)*1000) / 1000
+		summary.MeanSimilarity = &mean
+	}
+	if len(results) > 0 {
+		summary.A.MeanLatencyMS = latencyA / int64(len(results))
+		summary.B.MeanLatencyMS = latencyB / int64(len(results))
+	}
+	return summary
+}
+
+func addOutput(sum *modelSum, out compareOutput) {
+	if out.Error != "" {
+		sum.Errors++
+	}
+	if out.Cost != nil {
+		total := *out.Cost
+		if sum.TotalCost != nil {
+			total += *sum.TotalCost
+		}
+		sum.TotalCost = &total
+	}
+}
+
+func printComparison(w io.Writer, n, total int, specA, specB string, r compareResult, width int) {
+	column := (width - 3) / 2
+	if column < 20 {
+		column = 20
+	}
+	_, _ = fmt.Fprintf(w, "=== Prompt %d/%d ===\n%s\n\n", n, total, r.Prompt)
+	left := wrapText(outputText(r.A), column)
+	right := wrapText(outputText(r.B), column)
+	_, _ = fmt.Fprintf(w, "%-*s | %s\n", column, truncate(specA+" "+outputStats(r.A), column), truncate(specB+" "+outputStats(r.B), column))
+	_, _ = fmt.Fprintf(w, "%s-+-%s\n", strings.Repeat("-", column), strings.Repeat("-", column))
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, rt string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			rt = right[i]
+		}
+		_, _ = fmt.Fprintf(w, "%-*s | %s\n", column, l, rt)
+	}
+	if r.Similarity != nil {
+		_, _ = fmt.Fprintf(w, "\nsimilarity: %.3f\n", *r.Similarity)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+func printSummary(w io.Writer, specA, specB string, s compareSummary) {
+	_, _ = fmt.Fprintf(w, "=== Summary (%d prompts) ===\n", s.Prompts)
+	if s.MeanSimilarity != nil {
+		_, _ = fmt.Fprintf(w, "mean similarity: %.3f\n", *s.MeanSimilarity)
+	}
+	for _, row := range []struct {
+		spec string
+		sum  modelSum
+	}{{specA, s.A}, {specB, s.B}} {
+		_, _ = fmt.Fprintf(w, "%s: mean latency %dms, errors %d, cost %s\n", row.spec, row.sum.MeanLatencyMS, row.sum.Errors, formatCost(row.sum.TotalCost))
+	}
+}
+
+func outputText(out compareOutput) string {
+	if out.Error != "" {
+		return "ERROR: " + out.Error
+	}
+	return out.Output
+}
+
+func outputStats(out compareOutput) string {
+	stats := fmt.Sprintf("(%dms", out.LatencyMS)
+	if out.Usage != nil {
+		stats += fmt.Sprintf(", %d tokens", out.Usage.TotalTokens)
+	}
+	if out.Cost != nil {
+		stats += ", " + formatCost(out.Cost)
+	}
+	return stats + ")"
+}
+
+func formatCost(cost *float64) string {
+	if cost == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("$%.6f", *cost)
+}
+
+// wrapText wraps text at word boundaries to lines of at most width runes,
+// keeping the text's own line breaks.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for len([]rune(word)) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case len([]rune(line))+1+len([]rune(word)) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
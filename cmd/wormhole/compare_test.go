@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wormhole "github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+// TestRunCompare swaps newCompareClient, so it must not run in parallel.
+func TestRunCompare(t *testing.T) {
+	openai := mocktesting.NewMockProvider("openai").WithTextResponse(types.TextResponse{
+		Text:  "Paris is the capital of France.",
+		Usage: &types.Usage{PromptTokens: 5, CompletionTokens: 7, TotalTokens: 12},
+	})
+	anthropic := mocktesting.NewMockProvider("anthropic").WithTextResponse(types.TextResponse{Text: "The capital of France is Paris."})
+
+	original := newCompareClient
+	t.Cleanup(func() { newCompareClient = original })
+	newCompareClient = func([]wormhole.Option) *wormhole.Wormhole {
+		return wormhole.New(
+			wormhole.WithCustomProvider("openai", func(types.ProviderConfig) (types.Provider, error) { return openai, nil }),
+			wormhole.WithProviderConfig("openai", types.ProviderConfig{}),
+			wormhole.WithCustomProvider("anthropic", func(types.ProviderConfig) (types.Provider, error) { return anthropic, nil }),
+			wormhole.WithProviderConfig("anthropic", types.ProviderConfig{}),
+			wormhole.WithDiscovery(false),
+			wormhole.WithModelValidation(false),
+		)
+	}
+
+	dir := t.TempDir()
+	promptFile := filepath.Join(dir, "prompts.txt")
+	require.NoError(t, os.WriteFile(promptFile, []byte("# geography\nWhat is the capital of France?\n\nName a French city.\n"), 0o600))
+	reportPath := filepath.Join(dir, "report.json")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"compare", "--model-a", "openai/gpt-4o", "--model-b", "anthropic/claude-sonnet-4-5",
+		"--prompt-file", promptFile, "--report", reportPath, "--width", "80"}, &stdout, &stderr, func(string) string { return "" })
+	require.Equal(t, 0, code, stderr.String())
+
+	out := stdout.String()
+	assert.Contains(t, out, "=== Prompt 1/2 ===")
+	assert.Contains(t, out, "openai/gpt-4o (")
+	assert.Contains(t, out, "12 tokens")
+	assert.Contains(t, out, "similarity: 1.000")
+	assert.Contains(t, out, "=== Summary (2 prompts) ===")
+
+	data, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+	var report compareReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, "openai/gpt-4o", report.ModelA)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "What is the capital of France?", report.Results[0].Prompt)
+	assert.Equal(t, "The capital of France is Paris.", report.Results[0].B.Output)
+	require.NotNil(t, report.Summary.MeanSimilarity)
+}
+
+func TestRunCompareRequiresModelsAndPrompts(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"compare", "--model-a", "openai/gpt-4o"}, &stdout, &stderr, func(string) string { return "" }))
+	assert.Contains(t, stderr.String(), "--model-a and --model-b are required")
+
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"compare", "--model-a", "a", "--model-b", "b"}, &stdout, &stderr, func(string) string { return "" }))
+	assert.Contains(t, stderr.String(), "no prompts")
+}
+
+func TestCompareHelpers(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, modelRoute{spec: "anthropic/claude", provider: "anthropic", model: "claude"}, parseModelRoute("anthropic/claude"))
+	assert.Equal(t, modelRoute{spec: "meta-llama/llama-3", model: "meta-llama/llama-3"}, parseModelRoute("meta-llama/llama-3"))
+	assert.Equal(t, modelRoute{spec: "gpt-4o", model: "gpt-4o"}, parseModelRoute("gpt-4o"))
+
+	assert.Equal(t, 1.0, textSimilarity("a b c", "C, b a!"))
+	assert.Equal(t, 0.0, textSimilarity("a b", "c d"))
+	assert.InDelta(t, 0.5, textSimilarity("a b", "a c"), 0.001)
+
+	assert.Equal(t, []string{"hello", "world", "abcdefghij", "klm"}, wrapText("hello world abcdefghijklm", 10))
+	assert.Equal(t, []string{"one", "", "two"}, wrapText("one\n\ntwo", 10))
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	wormhole "github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// generateOutput is the object --json prints.
+type generateOutput struct {
+	Text         string             `json:"text"`
+	Provider     string             `json:"provider,omitempty"`
+	Model        string             `json:"model"`
+	FinishReason types.FinishReason `json:"finish_reason,omitempty"`
+	Usage        *types.Usage       `json:"usage,omitempty"`
+	Cost         *float64           `json:"cost,omitempty"`
+	LatencyMS    int64              `json:"latency_ms"`
+}
+
+func runGenerate(args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, `Usage: wormhole generate [flags] <prompt...>
+
+A "-" argument is replaced by standard input, so prompts compose with pipes:
+
+  cat notes.md | wormhole generate "Summarize these notes:" -
+
+Flags:`)
+		fs.PrintDefaults()
+	}
+	model := fs.String("model", "", "Model, optionally as provider/model (e.g. openai/gpt-4o)")
+	system := fs.String("system", "", "System prompt")
+	maxTokens := fs.Int("max-tokens", 0, "Maximum output tokens (0 = provider default)")
+	timeout := fs.Duration("timeout", 5*time.Minute, "Request timeout")
+	jsonOut := fs.Bool("json", false, "Print one JSON object with text, usage, model, and latency")
+	raw := fs.Bool("raw", false, "Print only the generated text, with no trailing newline or stats")
+	mockScenario := fs.String("mock-scenario", "", "Generate with a mock provider named \"mock\" from a YAML or JSON scenario file instead of real providers")
+	profileFlags := addProfileFlags(fs, getenv)
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 1
+	}
+
+	if *jsonOut && *raw {
+		_, _ = fmt.Fprintln(stderr, "generate: --json and --raw cannot be combined")
+		return 1
+	}
+	prompt, err := generatePrompt(fs.Args())
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "generate: %v\n", err)
+		return 1
+	}
+
+	clientOpts, _, err := profileFlags.clientOptions(getenv)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "generate: %v\n", err)
+		return 1
+	}
+	clientOpts = append(clientOpts, wormhole.WithDiscovery(false))
+	route := parseModelRoute(*model)
+	if *mockScenario != "" {
+		opts, err := scenarioClientOptions(*mockScenario)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "generate: %v\n", err)
+			return 1
+		}
+		clientOpts = append(clientOpts, opts...)
+		if route.provider == "" {
+			route.provider = "mock"
+		}
+	}
+	client := wormhole.New(clientOpts...)
+	defer func() { _ = client.Close() }()
+
+	builder := client.Text().Prompt(prompt)
+	if route.provider != "" {
+		builder.Using(route.provider)
+	}
+	if route.model != "" {
+		builder.Model(route.model)
+	}
+	if *system != "" {
+		builder.SystemPrompt(*system)
+	}
+	if *maxTokens > 0 {
+		builder.MaxTokens(*maxTokens)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	start := time.Now()
+
+	if *jsonOut {
+		resp, err := builder.Generate(ctx)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "generate: %v\n", err)
+			return 1
+		}
+		out := generateOutput{
+			Text:         resp.Text,
+			Provider:     resp.Provider,
+			Model:        resp.Model,
+			FinishReason: resp.FinishReason,
+			Usage:        resp.Usage,
+			LatencyMS:    time.Since(start).Milliseconds(),
+		}
+		if resp.Usage != nil {
+			if cost, err := types.EstimateModelCost(resp.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens); err == nil {
+				out.Cost = &cost
+			}
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(out); err != nil {
+			_, _ = fmt.Fprintf(stderr, "generate: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	resp, err := builder.StreamTo(ctx, stdout)
+	if err != nil {
+		if resp != nil && resp.Text != "" && !*raw {
+			_, _ = fmt.Fprintln(stdout)
+		}
+		_, _ = fmt.Fprintf(stderr, "generate: %v\n", err)
+		return 1
+	}
+	if *raw {
+		return 0
+	}
+	if !strings.HasSuffix(resp.Text, "\n") {
+		_, _ = fmt.Fprintln(stdout)
+	}
+	_, _ = fmt.Fprintln(stderr, generateStats(resp, time.Since(start)))
+	return 0
+}
+
+// generatePrompt joins the arguments with spaces. A "-" argument is replaced
+// by standard input, set off from the surrounding words by blank lines.
+func generatePrompt(args []string) (string, error) {
+	var segments, words []string
+	flushWords := func() {
+		if len(words) > 0 {
+			segments = append(segments, strings.Join(words, " "))
+			words = nil
+		}
+	}
+	readStdin := false
+	for _, arg := range args {
+		if arg != "-" {
+			words = append(words, arg)
+			continue
+		}
+		if readStdin {
+			return "", fmt.Errorf(`"-" may appear only once`)
+		}
+		readStdin = true
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("read stdin: %w", err)
+		}
+		flushWords()
+		if input := strings.TrimRight(string(data), "\r\n"); strings.TrimSpace(input) != "" {
+			segments = append(segments, input)
+		}
+	}
+	flushWords()
+	if len(segments) == 0 {
+		return "", fmt.Errorf(`no prompt: pass it as arguments or "-" to read standard input`)
+	}
+	return strings.Join(segments, "\n\n"), nil
+}
+
+// generateStats summarizes a reply for the stderr footer, in the style of
+// compare's per-model stats.
+func generateStats(resp *types.TextResponse, latency time.Duration) string {
+	model := resp.Model
+	if resp.Provider != "" {
+		model = resp.Provider + "/" + model
+	}
+	stats := fmt.Sprintf("%s (%dms", model, latency.Milliseconds())
+	if resp.Usage != nil {
+		stats += fmt.Sprintf(", %d tokens", resp.Usage.TotalTokens)
+	}
+	return stats + ")"
+}
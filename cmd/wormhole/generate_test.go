@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runGenerateWith swaps stdin, so tests using it must not run in parallel.
+func runGenerateWith(t *testing.T, input string, args ...string) (int, string, string) {
+	t.Helper()
+	original := stdin
+	t.Cleanup(func() { stdin = original })
+	stdin = strings.NewReader(input)
+
+	scenario := filepath.Join(t.TempDir(), "scenario.json")
+	require.NoError(t, os.WriteFile(scenario, []byte(`{"rules": [
+		{"match": "Summarize these notes:\n\n- ship v2\n- fix docs", "respond": "Ship v2, fix docs.", "stream": ["Ship ", "v2, ", "fix docs."]},
+		{"match": "fail", "error": "overloaded", "status": 503}
+	]}`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"generate", "--mock-scenario", scenario, "--model", "mock-model"}, args...), &stdout, &stderr, func(string) string { return "" })
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunGenerateOutputModes(t *testing.T) {
+	notes := "- ship v2\n- fix docs\n"
+
+	code, stdout, stderr := runGenerateWith(t, notes, "Summarize", "these", "notes:", "-")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "Ship v2, fix docs.\n", stdout)
+	assert.Contains(t, stderr, "mock-model (")
+
+	code, stdout, stderr = runGenerateWith(t, notes, "--raw", "Summarize these notes:", "-")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "Ship v2, fix docs.", stdout, "raw prints the text alone")
+	assert.Empty(t, stderr)
+
+	code, stdout, stderr = runGenerateWith(t, notes, "--json", "Summarize these notes:", "-")
+	require.Equal(t, 0, code, stderr)
+	var out generateOutput
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	assert.Equal(t, "mock-model", out.Model)
+	assert.Equal(t, "Ship v2, fix docs.", out.Text)
+	assert.GreaterOrEqual(t, out.LatencyMS, int64(0))
+	assert.Contains(t, stdout, `"latency_ms"`)
+	assert.Equal(t, 1, strings.Count(stdout, "\n"), "one JSON object per line")
+}
+
+func TestRunGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		args       []string
+		wantStderr string
+	}{
+		{name: "no prompt", wantStderr: "generate: no prompt"},
+		{name: "empty stdin", input: "\n", args: []string{"-"}, wantStderr: "generate: no prompt"},
+		{name: "stdin twice", args: []string{"-", "-"}, wantStderr: `"-" may appear only once`},
+		{name: "json and raw", args: []string{"--json", "--raw", "hi"}, wantStderr: "--json and --raw cannot be combined"},
+		{name: "provider error", args: []string{"fail"}, wantStderr: "generate: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runGenerateWith(t, tt.input, tt.args...)
+			assert.Equal(t, 1, code)
+			assert.Contains(t, stderr, tt.wantStderr)
+		})
+	}
+}
@@ -17,10 +17,14 @@ import (
 	wormhole "github.com/garyblankenship/wormhole/v2"
 	"github.com/garyblankenship/wormhole/v2/internal/server"
 	"github.com/garyblankenship/wormhole/v2/types"
+	"github.com/garyblankenship/wormhole/v2/wormholetest"
 )
 
 var version = "dev"
 
+// stdin is where chat reads lines and generate reads "-". Tests replace it.
+var stdin io.Reader = os.Stdin
+
 type proxyServer interface {
 	Start() error
 	Shutdown(context.Context) error
@@ -43,6 +47,12 @@ func run(args []string, stdout, stderr io.Writer, getenv func(string) string) in
 	switch args[0] {
 	case "serve":
 		return runServe(args[1:], stdout, stderr, getenv)
+	case "compare":
+		return runCompare(args[1:], stdout, stderr, getenv)
+	case "chat":
+		return runChat(args[1:], stdout, stderr, getenv)
+	case "generate":
+		return runGenerate(args[1:], stdout, stderr, getenv)
 	case "version":
 		_, _ = fmt.Fprintf(stdout, "wormhole %s\n", resolvedVersion())
 	case "help", "--help", "-h":
@@ -70,10 +80,13 @@ func printUsage(w io.Writer) {
 
 Commands:
   serve     Start the proxy server
+  compare   Run prompts against two models and compare the outputs
+  chat      Chat with a model interactively
+  generate  Send one prompt (or "-" for stdin) and print the reply
   version   Print version
   help      Show this help
 
-Run "wormhole serve --help" for serve options.`)
+Run "wormhole <command> --help" for command options.`)
 }
 
 func runServe(args []string, stdout, stderr io.Writer, getenv func(string) string) int {
@@ -81,6 +94,8 @@ func runServe(args []string, stdout, stderr io.Writer, getenv func(string) strin
 	fs.SetOutput(stderr)
 	addr := fs.String("addr", "127.0.0.1:8080", "Listen address (use \":8080\" to bind all interfaces; requires WORMHOLE_API_KEY)")
 	defaultProvider := fs.String("default-provider", "", "Default provider when model has no prefix")
+	mockScenario := fs.String("mock-scenario", "", "Serve a mock provider named \"mock\" from a YAML or JSON scenario file instead of real providers")
+	profileFlags := addProfileFlags(fs, getenv)
 	if err := fs.Parse(args); err != nil {
 		if err == flag.ErrHelp {
 			return 0
@@ -88,25 +103,34 @@ func runServe(args []string, stdout, stderr io.Writer, getenv func(string) strin
 		return 1
 	}
 
+	clientOpts, profile, err := profileFlags.clientOptions(getenv)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "serve: %v\n", err)
+		return 1
+	}
+	if profile != nil && *defaultProvider == "" {
+		*defaultProvider = profile.Provider
+	}
+	if *mockScenario != "" {
+		opts, err := scenarioClientOptions(*mockScenario)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "serve: %v\n", err)
+			return 1
+		}
+		clientOpts = opts
+		if *defaultProvider == "" {
+			*defaultProvider = "mock"
+		}
+	}
+
 	logger := slog.New(slog.NewJSONHandler(stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}))
 
-	var opts []wormhole.Option
-	opts = append(opts, wormhole.WithAllProvidersFromEnv())
-
-	if profile, ok := wormhole.ProviderProfileByName("ollama"); ok && profile.BaseURLEnv != "" {
-		if ollamaURL := getenv(profile.BaseURLEnv); ollamaURL != "" {
-			opts = append(opts, wormhole.WithOllama(types.ProviderConfig{
-				BaseURL: ollamaURL,
-			}))
-		}
-	}
-
 	cfg := server.Config{
 		Addr:            *addr,
 		DefaultProvider: *defaultProvider,
-		WormholeOpts:    opts,
+		WormholeOpts:    clientOpts,
 		ProxyAPIKey:     getenv("WORMHOLE_API_KEY"),
 		Logger:          logger,
 	}
@@ -137,3 +161,33 @@ func runServe(args []string, stdout, stderr io.Writer, getenv func(string) strin
 	<-shutdownDone
 	return 0
 }
+
+// scenarioClientOptions configures a single scenario-driven mock provider
+// named "mock", so QA can exercise clients against the proxy without keys.
+func scenarioClientOptions(path string) ([]wormhole.Option, error) {
+	scenario, err := wormholetest.LoadScenarioFile(path)
+	if err != nil {
+		return nil, err
+	}
+	mock := wormholetest.NewMockProvider("mock").WithScenario(*scenario)
+	return []wormhole.Option{
+		wormhole.WithCustomProvider("mock", wormholetest.MockProviderFactory(mock)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithModelValidation(false),
+		wormhole.WithDiscovery(false),
+	}, nil
+}
+
+// envClientOptions configures every provider with credentials in the
+// environment, plus Ollama when its base URL variable is set.
+func envClientOptions(getenv func(string) string) []wormhole.Option {
+	opts := []wormhole.Option{wormhole.WithAllProvidersFromEnv()}
+	if profile, ok := wormhole.ProviderProfileByName("ollama"); ok && profile.BaseURLEnv != "" {
+		if ollamaURL := getenv(profile.BaseURLEnv); ollamaURL != "" {
+			opts = append(opts, wormhole.WithOllama(types.ProviderConfig{
+				BaseURL: ollamaURL,
+			}))
+		}
+	}
+	return opts
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	wormhole "github.com/garyblankenship/wormhole/v2"
 	"github.com/garyblankenship/wormhole/v2/internal/server"
 )
 
@@ -267,3 +268,28 @@ func (b *lockedBuffer) String() string {
 	defer b.mu.Unlock()
 	return b.b.String()
 }
+
+func TestRunServeMockScenarioErrors(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"serve", "--mock-scenario", filepath.Join(t.TempDir(), "missing.json")}, &stdout, &stderr, func(string) string { return "" })
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "serve: read scenario file")
+}
+
+func TestScenarioClientOptionsServeScenarioReplies(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules": [{"match": "ping", "respond": "pong"}]}`), 0o600))
+	opts, err := scenarioClientOptions(path)
+	require.NoError(t, err)
+
+	client := wormhole.New(append(opts, wormhole.WithDefaultProvider("mock"))...)
+	defer client.Close()
+	resp, err := client.Text().Model("any").Prompt("ping").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "pong", resp.Text)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	wormhole "github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/internal/configfile"
+)
+
+// cliConfig is the CLI's own configuration file, by default
+// ~/.config/wormhole/config.yaml:
+//
+//	default_profile: work
+//	profiles:
+//	  work:
+//	    provider: openai
+//	    model: gpt-5.2
+//	    api_key_env: WORK_OPENAI_KEY
+//	  local:
+//	    provider: ollama
+//	    model: llama3.2
+//	    base_url: http://localhost:11434
+//
+// It is decoded like the library's config files: YAML, or JSON for a .json
+// path, with unknown keys rejected.
+type cliConfig struct {
+	DefaultProfile string                `json:"default_profile" yaml:"default_profile"`
+	Profiles       map[string]cliProfile `json:"profiles" yaml:"profiles"`
+}
+
+// cliProfile is one named provider route with its credentials.
+type cliProfile struct {
+	Name      string `json:"-" yaml:"-"`
+	Provider  string `json:"provider" yaml:"provider"`
+	Model     string `json:"model" yaml:"model"`
+	BaseURL   string `json:"base_url" yaml:"base_url"`
+	APIKeyEnv string `json:"api_key_env" yaml:"api_key_env"`
+}
+
+// profileFlags are the --config and --profile flags every command accepts.
+type profileFlags struct {
+	config  *string
+	profile *string
+}
+
+func addProfileFlags(fs *flag.FlagSet, getenv func(string) string) profileFlags {
+	return profileFlags{
+		config:  fs.String("config", defaultCLIConfigPath(getenv), "CLI config file with named profiles"),
+		profile: fs.String("profile", "", "Profile from the config file (default: its default_profile)"),
+	}
+}
+
+// resolve returns the selected profile, or nil when none is selected. A
+// missing config file only matters when --profile names a profile.
+func (f profileFlags) resolve() (*cliProfile, error) {
+	name := *f.profile
+	cfg, err := loadCLIConfig(*f.config)
+	if errors.Is(err, os.ErrNotExist) && name == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = cfg.DefaultProfile
+		if name == "" {
+			return nil, nil
+		}
+	}
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("config %s: no profile %q (have %s)", *f.config, name, strings.Join(cfg.profileNames(), ", "))
+	}
+	return &profile, nil
+}
+
+// clientOptions adds the selected profile's provider to the environment's
+// and makes it the default, so the profile wins for unprefixed models while
+// other configured providers stay reachable.
+func (f profileFlags) clientOptions(getenv func(string) string) ([]wormhole.Option, *cliProfile, error) {
+	opts := envClientOptions(getenv)
+	profile, err := f.resolve()
+	if err != nil || profile == nil {
+		return opts, nil, err
+	}
+	fc := wormhole.FileConfig{
+		DefaultProvider: profile.Provider,
+		Providers: map[string]wormhole.FileProviderConfig{
+			profile.Provider: {APIKeyEnv: profile.APIKeyEnv, BaseURL: profile.BaseURL, DefaultModel: profile.Model},
+		},
+	}
+	profileOpts, err := fc.Options()
+	if err != nil {
+		return nil, nil, fmt.Errorf("profile %s: %w", profile.Name, err)
+	}
+	return append(opts, profileOpts...), profile, nil
+}
+
+// defaultCLIConfigPath follows the XDG base directory layout.
+func defaultCLIConfigPath(getenv func(string) string) string {
+	if dir := getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "wormhole", "config.yaml")
+	}
+	if home := getenv("HOME"); home != "" {
+		return filepath.Join(home, ".config", "wormhole", "config.yaml")
+	}
+	return ""
+}
+
+func loadCLIConfig(path string) (*cliConfig, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no config file: set --config or HOME: %w", os.ErrNotExist)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	cfg, err := parseCLIConfig(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func parseCLIConfig(path string, data []byte) (*cliConfig, error) {
+	var cfg cliConfig
+	if err := configfile.Decode(path, data, &cfg); err != nil {
+		return nil, err
+	}
+	for name, profile := range cfg.Profiles {
+		if profile.Provider == "" {
+			return nil, fmt.Errorf("profile %q: provider is required", name)
+		}
+		profile.Name = name
+		cfg.Profiles[name] = profile
+	}
+	if cfg.DefaultProfile != "" {
+		if _, ok := cfg.Profiles[cfg.DefaultProfile]; !ok {
+			return nil, fmt.Errorf("default_profile %q is not declared under profiles", cfg.DefaultProfile)
+		}
+	}
+	return &cfg, nil
+}
+
+func (c *cliConfig) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCLIConfig = `# wormhole CLI profiles
+default_profile: work
+profiles:
+  work:
+    provider: openai
+    model: "gpt-5.2"   # pinned
+    api_key_env: WORK_OPENAI_KEY
+  local:
+    provider: ollama
+    model: llama3.2
+    base_url: 'http://localhost:11434'
+`
+
+func TestParseCLIConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseCLIConfig("config.yaml", []byte(testCLIConfig))
+	require.NoError(t, err)
+	assert.Equal(t, "work", cfg.DefaultProfile)
+	want := map[string]cliProfile{
+		"work":  {Name: "work", Provider: "openai", Model: "gpt-5.2", APIKeyEnv: "WORK_OPENAI_KEY"},
+		"local": {Name: "local", Provider: "ollama", Model: "llama3.2", BaseURL: "http://localhost:11434"},
+	}
+	assert.Equal(t, want, cfg.Profiles)
+
+	flow, err := parseCLIConfig("config.yaml", []byte("profiles: {work: {provider: openai, model: gpt-5.2, api_key_env: WORK_OPENAI_KEY}}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, want["work"], flow.Profiles["work"])
+
+	fromJSON, err := parseCLIConfig("config.json", []byte(`{"profiles": {"local": {"provider": "ollama", "model": "llama3.2", "base_url": "http://localhost:11434"}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, want["local"], fromJSON.Profiles["local"])
+}
+
+func TestParseCLIConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{name: "unknown top-level key", config: "profile: work\n", wantErr: `field profile not found`},
+		{name: "unknown profile key", config: "profiles:\n  work:\n    provider: openai\n    api_key: sk-123\n", wantErr: `field api_key not found`},
+		{name: "missing provider", config: "profiles:\n  work:\n    model: gpt-5.2\n", wantErr: `profile "work": provider is required`},
+		{name: "undeclared default", config: "default_profile: home\nprofiles:\n  work:\n    provider: openai\n", wantErr: `default_profile "home" is not declared`},
+		{name: "tabs", config: "profiles:\n\twork:\n", wantErr: "line 2"},
+		{name: "duplicate profile", config: "profiles:\n  work:\n    provider: openai\n  work:\n    provider: groq\n", wantErr: `"work" already defined`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseCLIConfig("config.yaml", []byte(tt.config))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProfileFlagsResolve(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testCLIConfig), 0o600))
+	resolve := func(config, profile string) (*cliProfile, error) {
+		return profileFlags{config: &config, profile: &profile}.resolve()
+	}
+
+	profile, err := resolve(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "work", profile.Name, "default_profile applies without --profile")
+
+	profile, err = resolve(path, "local")
+	require.NoError(t, err)
+	assert.Equal(t, "ollama", profile.Provider)
+
+	_, err = resolve(path, "home")
+	assert.ErrorContains(t, err, `no profile "home" (have local, work)`)
+
+	missing := filepath.Join(t.TempDir(), "config.yaml")
+	profile, err = resolve(missing, "")
+	require.NoError(t, err, "a missing config file is fine until a profile is asked for")
+	assert.Nil(t, profile)
+	_, err = resolve(missing, "work")
+	assert.ErrorContains(t, err, "read config")
+}
+
+// TestRunCompareWithProfile sets an environment variable, so it must not run
+// in parallel.
+func TestRunCompareWithProfile(t *testing.T) {
+	var gotAuth, gotModel string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		var body struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotModel = body.Model
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","model":"` + body.Model + `","choices":[{"index":0,"message":{"role":"assistant","content":"pong"},"finish_reason":"stop"}]}`))
+	}))
+	defer upstream.Close()
+
+	t.Setenv("GATEWAY_TOKEN", "secret-token")
+	dir := t.TempDir()
+	config := "profiles:\n  gateway:\n    provider: gateway\n    model: house-model\n    base_url: " + upstream.URL + "\n    api_key_env: GATEWAY_TOKEN\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "wormhole"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "wormhole", "config.yaml"), []byte(config), 0o600))
+	getenv := func(key string) string {
+		if key == "XDG_CONFIG_HOME" {
+			return dir
+		}
+		return ""
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"compare", "--profile", "gateway", "--model-a", "house-model", "--model-b", "house-model", "--prompt", "ping"}, &stdout, &stderr, getenv)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), "pong")
+	assert.Equal(t, "Bearer secret-token", gotAuth)
+	assert.Equal(t, "house-model", gotModel)
+
+	stderr.Reset()
+	code = run([]string{"chat", "--profile", "home"}, &stdout, &stderr, getenv)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), `chat: config `+filepath.Join(dir, "wormhole", "config.yaml")+`: no profile "home"`)
+}
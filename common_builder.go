@@ -1,12 +1,29 @@
 package wormhole
 
-import "github.com/garyblankenship/wormhole/v2/types"
+import (
+	"context"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
 
 // CommonBuilder contains shared fields and methods for all request builders
 type CommonBuilder struct {
 	wormhole *Wormhole
 	provider string
 	baseURL  string
+	tags     map[string]string        // request tags, never mutated in place (see addTags)
+	priority *Priority                // scheduling priority; nil keeps the context's (see setPriority)
+	cache    *middleware.CacheControl // per-request cache policy; nil keeps the context's
+
+	// scopedOptions holds ProviderOption values by provider name, never
+	// mutated in place (see setProviderOption).
+	scopedOptions map[string]map[string]any
+
+	promptErr error // UsePrompt lookup or render failure, returned at execution
+
+	experiment    *experimentArm // variant assigned by Experiment, recorded on Generate
+	experimentErr error          // unknown experiment, returned at execution
 }
 
 // newCommonBuilder creates a new CommonBuilder with the given wormhole instance
@@ -32,6 +49,21 @@ func (cb *CommonBuilder) setProvider(provider string) {
 	cb.provider = provider
 }
 
+// resolveModel applies the client's default model for the builder's provider
+// when model is empty, then resolves model aliases.
+func (cb *CommonBuilder) resolveModel(model string) string {
+	config := cb.getWormhole().config
+	if model == "" && len(config.DefaultModels) > 0 {
+		if providerName, err := cb.getWormhole().resolveProviderName(cb.getProvider()); err == nil {
+			model = config.DefaultModels[providerName]
+		}
+	}
+	if alias, ok := config.ModelAliases[model]; ok {
+		return alias
+	}
+	return model
+}
+
 // getBaseURL returns the current base URL
 func (cb *CommonBuilder) getBaseURL() string {
 	return cb.baseURL
@@ -114,6 +146,24 @@ func cloneBaseRequestFields(dst, src *types.BaseRequest) {
 		}
 		dst.Reasoning = &reasoning
 	}
+	if src.TopK != nil {
+		topK := *src.TopK
+		dst.TopK = &topK
+	}
+	if src.MinP != nil {
+		minP := *src.MinP
+		dst.MinP = &minP
+	}
+	if src.RepetitionPenalty != nil {
+		repetitionPenalty := *src.RepetitionPenalty
+		dst.RepetitionPenalty = &repetitionPenalty
+	}
+	if src.LogitBias != nil {
+		dst.LogitBias = make(map[int]int, len(src.LogitBias))
+		for token, bias := range src.LogitBias {
+			dst.LogitBias[token] = bias
+		}
+	}
 	if len(src.Stop) > 0 {
 		dst.Stop = make([]string, len(src.Stop))
 		copy(dst.Stop, src.Stop)
@@ -135,3 +185,94 @@ func prepareExecutionMessages(systemPrompt string, messages []types.Message) []t
 	result = append(result, messages...)
 	return result
 }
+
+// addTags merges tags into the builder's request tags. It always allocates a
+// new map, so clones sharing the old one are unaffected.
+func (cb *CommonBuilder) addTags(tags map[string]string) {
+	merged := make(map[string]string, len(cb.tags)+len(tags))
+	for k, v := range cb.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	cb.tags = merged
+}
+
+// taggedContext attaches the builder's request tags, priority and cache
+// control to ctx for middleware.
+func (cb *CommonBuilder) taggedContext(ctx context.Context) context.Context {
+	if cb.priority != nil {
+		ctx = middleware.WithPriority(ctx, *cb.priority)
+	}
+	if cb.cache != nil {
+		ctx = middleware.WithCacheControl(ctx, *cb.cache)
+	}
+	return middleware.WithTags(ctx, cb.tags)
+}
+
+// setPriority records the builder's scheduling priority.
+func (cb *CommonBuilder) setPriority(p Priority) {
+	cb.priority = &p
+}
+
+// setCacheControl records the builder's cache policy.
+func (cb *CommonBuilder) setCacheControl(control middleware.CacheControl) {
+	cb.cache = &control
+}
+
+// cacheKey returns the key CacheMiddleware would use for the request scope
+// builds for the builder's provider.
+func (cb *CommonBuilder) cacheKey(scope func(types.Provider) any) (string, error) {
+	provider, release, err := cb.getProviderWithBaseURL()
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return middleware.CacheKey(provider.Name(), scope(provider))
+}
+
+// setProviderOption records a provider option scoped to provider. Like
+// addTags it copies on write, so clones sharing the old maps are unaffected.
+func (cb *CommonBuilder) setProviderOption(provider, key string, value any) {
+	cb.scopedOptions = withScopedOption(cb.scopedOptions, provider, key, value)
+}
+
+// providerOptionsFor lays the ProviderOption values scoped to provider over
+// the request's own options. Options scoped to other providers are dropped,
+// so a fallback to another provider never sees them. options is not mutated.
+func (cb *CommonBuilder) providerOptionsFor(provider types.Provider, options map[string]any) map[string]any {
+	return mergeScopedOptions(cb.scopedOptions[provider.Name()], options)
+}
+
+// withScopedOption returns a copy of scoped with key set to value for
+// provider. scoped is not mutated.
+func withScopedOption(scoped map[string]map[string]any, provider, key string, value any) map[string]map[string]any {
+	copied := make(map[string]map[string]any, len(scoped)+1)
+	for name, options := range scoped {
+		copied[name] = options
+	}
+	options := make(map[string]any, len(copied[provider])+1)
+	for k, v := range copied[provider] {
+		options[k] = v
+	}
+	options[key] = types.CloneValue(value)
+	copied[provider] = options
+	return copied
+}
+
+// mergeScopedOptions lays scoped over options, returning options itself when
+// there is nothing to add. Neither map is mutated.
+func mergeScopedOptions(scoped, options map[string]any) map[string]any {
+	if len(scoped) == 0 {
+		return options
+	}
+	merged := make(map[string]any, len(options)+len(scoped))
+	for k, v := range options {
+		merged[k] = v
+	}
+	for k, v := range scoped {
+		merged[k] = types.CloneValue(v)
+	}
+	return merged
+}
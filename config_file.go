@@ -0,0 +1,337 @@
+package wormhole
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/internal/configfile"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// FileConfig is the declarative form of a client configuration, loaded by
+// LoadConfigFile or NewFromConfigFile. It never holds secrets: providers name
+// the environment variable their API key is read from.
+//
+// Files are JSON or YAML, chosen by extension; both use the same field names.
+//
+// Example wormhole.yaml:
+//
+//	default_provider: openai
+//	timeout: 60s
+//	retries: {max: 2, delay: 250ms}
+//	providers:
+//	  openai: {api_key_env: OPENAI_API_KEY, default_model: smart}
+//	  anthropic: {}
+//	  gateway: {base_url: "https://llm.internal/v1", api_key_env: GATEWAY_KEY}
+//	middleware:
+//	  - {type: circuit_breaker, failure_threshold: 5, timeout: 30s}
+//	model_aliases: {fast: gpt-5-nano, smart: gpt-5.2}
+//
+// The same as wormhole.json:
+//
+//	{
+//	  "default_provider": "openai",
+//	  "timeout": "60s",
+//	  "retries": {"max": 2, "delay": "250ms"},
+//	  "providers": {
+//	    "openai":    {"api_key_env": "OPENAI_API_KEY", "default_model": "smart"},
+//	    "anthropic": {},
+//	    "gateway":   {"base_url": "https://llm.internal/v1", "api_key_env": "GATEWAY_KEY"}
+//	  },
+//	  "middleware": [{"type": "circuit_breaker", "failure_threshold": 5, "timeout": "30s"}],
+//	  "model_aliases": {"fast": "gpt-5-nano", "smart": "gpt-5.2"}
+//	}
+type FileConfig struct {
+	DefaultProvider string                         `json:"default_provider,omitempty" yaml:"default_provider,omitempty"`
+	Timeout         Duration                       `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retries         *FileRetryConfig               `json:"retries,omitempty" yaml:"retries,omitempty"`
+	Discovery       *bool                          `json:"discovery,omitempty" yaml:"discovery,omitempty"`
+	ModelValidation *bool                          `json:"model_validation,omitempty" yaml:"model_validation,omitempty"`
+	Providers       map[string]FileProviderConfig  `json:"providers,omitempty" yaml:"providers,omitempty"`
+	Middleware      []FileMiddlewareConfig         `json:"middleware,omitempty" yaml:"middleware,omitempty"`
+	ModelAliases    map[string]string              `json:"model_aliases,omitempty" yaml:"model_aliases,omitempty"`
+	Validators      map[string]FileValidatorConfig `json:"validators,omitempty" yaml:"validators,omitempty"`
+}
+
+// FileRetryConfig sets the client-wide HTTP retry defaults (see WithRetries).
+type FileRetryConfig struct {
+	Max   int      `json:"max" yaml:"max"`
+	Delay Duration `json:"delay,omitempty" yaml:"delay,omitempty"`
+}
+
+// FileProviderConfig declares one provider. Known provider names (openai,
+// anthropic, gemini, groq, openrouter, ...) use their built-in profile; any
+// other name with a base_url is registered as an OpenAI-compatible endpoint.
+type FileProviderConfig struct {
+	// APIKeyEnv names the environment variable holding the API key. Empty
+	// means the profile's standard variables (OPENAI_API_KEY, ...).
+	APIKeyEnv     string            `json:"api_key_env,omitempty" yaml:"api_key_env,omitempty"`
+	BaseURL       string            `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	NoAuth        bool              `json:"no_auth,omitempty" yaml:"no_auth,omitempty"`
+	DynamicModels bool              `json:"dynamic_models,omitempty" yaml:"dynamic_models,omitempty"`
+	Timeout       Duration          `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	MaxRetries    *int              `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+	RetryDelay    Duration          `json:"retry_delay,omitempty" yaml:"retry_delay,omitempty"`
+	UserAgent     string            `json:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+	DefaultModel  string            `json:"default_model,omitempty" yaml:"default_model,omitempty"`
+}
+
+// FileMiddlewareConfig declares one middleware. Type selects it and the
+// remaining fields configure it:
+//
+//   - "timeout": Timeout
+//   - "rate_limit": RequestsPerSecond
+//   - "circuit_breaker": FailureThreshold, Timeout
+//   - "retry": MaxRetries, InitialDelay, MaxDelay
+//
+// Middleware is applied in the listed order, outermost first.
+type FileMiddlewareConfig struct {
+	Type              string   `json:"type" yaml:"type"`
+	Timeout           Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	RequestsPerSecond int      `json:"requests_per_second,omitempty" yaml:"requests_per_second,omitempty"`
+	FailureThreshold  int      `json:"failure_threshold,omitempty" yaml:"failure_threshold,omitempty"`
+	MaxRetries        int      `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+	InitialDelay      Duration `json:"initial_delay,omitempty" yaml:"initial_delay,omitempty"`
+	MaxDelay          Duration `json:"max_delay,omitempty" yaml:"max_delay,omitempty"`
+}
+
+// FileValidatorConfig declares a named response validator (see
+// WithResponseValidator). Type selects it and the remaining fields configure it:
+//
+//   - "regex": Pattern
+//   - "json_schema": Schema
+//   - "judge": Provider, Model, Criteria
+type FileValidatorConfig struct {
+	Type     string         `json:"type" yaml:"type"`
+	Pattern  string         `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Schema   map[string]any `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Provider string         `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Model    string         `json:"model,omitempty" yaml:"model,omitempty"`
+	Criteria string         `json:"criteria,omitempty" yaml:"criteria,omitempty"`
+}
+
+// Duration is a time.Duration written as a Go duration string ("30s",
+// "1m30s") in configuration files.
+type Duration time.Duration
+
+// UnmarshalText parses a Go duration string.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration as a Go duration string.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// NewFromConfigFile creates a client from a JSON or YAML configuration file.
+// Options passed here are applied after the file, so code can still override
+// it.
+//
+// Example:
+//
+//	client, err := wormhole.NewFromConfigFile(os.Getenv("WORMHOLE_CONFIG"))
+func NewFromConfigFile(path string, opts ...Option) (*Wormhole, error) {
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromFileConfig(*fc, opts...)
+}
+
+// NewFromFileConfig creates a client from an already decoded FileConfig.
+// Options passed here are applied after the file configuration.
+func NewFromFileConfig(fc FileConfig, opts ...Option) (*Wormhole, error) {
+	fileOpts, err := fc.Options()
+	if err != nil {
+		return nil, err
+	}
+	return New(append(fileOpts, opts...)...), nil
+}
+
+// LoadConfigFile reads and decodes a configuration file: YAML for a .yaml or
+// .yml extension, JSON otherwise. Unknown fields are rejected so typos fail
+// at startup instead of being ignored.
+func LoadConfigFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	var fc FileConfig
+	if err := configfile.Decode(path, data, &fc); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return &fc, nil
+}
+
+// Options converts the file configuration into client options. It fails when
+// a provider's API key variable is unset or a middleware type is unknown.
+func (fc FileConfig) Options() ([]Option, error) {
+	var opts []Option
+	if fc.Timeout != 0 {
+		opts = append(opts, WithTimeout(time.Duration(fc.Timeout)))
+	}
+	if fc.Retries != nil {
+		opts = append(opts, WithRetries(fc.Retries.Max, time.Duration(fc.Retries.Delay)))
+	}
+	if fc.Discovery != nil {
+		opts = append(opts, WithDiscovery(*fc.Discovery))
+	}
+	if fc.ModelValidation != nil {
+		opts = append(opts, WithModelValidation(*fc.ModelValidation))
+	}
+
+	names := make([]string, 0, len(fc.Providers))
+	for name := range fc.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opt, err := fc.Providers[name].option(name)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+		if model := fc.Providers[name].DefaultModel; model != "" {
+			opts = append(opts, WithDefaultModel(name, model))
+		}
+	}
+	if fc.DefaultProvider != "" {
+		if _, ok := fc.Providers[fc.DefaultProvider]; !ok {
+			return nil, fmt.Errorf("config: default_provider %q is not declared under providers", fc.DefaultProvider)
+		}
+		opts = append(opts, WithDefaultProvider(fc.DefaultProvider))
+	}
+
+	if len(fc.Middleware) > 0 {
+		mws := make([]middleware.Middleware, 0, len(fc.Middleware))
+		for i, mc := range fc.Middleware {
+			mw, err := mc.middleware()
+			if err != nil {
+				return nil, fmt.Errorf("config: middleware[%d]: %w", i, err)
+			}
+			mws = append(mws, mw)
+		}
+		opts = append(opts, WithMiddleware(mws...))
+	}
+	if len(fc.ModelAliases) > 0 {
+		opts = append(opts, WithModelAliases(fc.ModelAliases))
+	}
+	for name, vc := range fc.Validators {
+		validator, err := vc.validator()
+		if err != nil {
+			return nil, fmt.Errorf("config: validators[%s]: %w", name, err)
+		}
+		opts = append(opts, WithResponseValidator(name, validator))
+	}
+	return opts, nil
+}
+
+func (vc FileValidatorConfig) validator() (ResponseValidator, error) {
+	switch vc.Type {
+	case "regex":
+		re, err := regexp.Compile(vc.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		return RegexValidator(re), nil
+	case "json_schema":
+		if vc.Schema == nil {
+			return nil, fmt.Errorf("json_schema validator needs a schema")
+		}
+		return JSONSchemaValidator(vc.Schema), nil
+	case "judge":
+		if vc.Model == "" || vc.Criteria == "" {
+			return nil, fmt.Errorf("judge validator needs a model and criteria")
+		}
+		return JudgeValidator(TextRoute{Provider: vc.Provider, Model: vc.Model}, vc.Criteria), nil
+	default:
+		return nil, fmt.Errorf("unknown validator type %q", vc.Type)
+	}
+}
+
+func (pc FileProviderConfig) option(name string) (Option, error) {
+	cfg := types.ProviderConfig{
+		BaseURL:       pc.BaseURL,
+		Headers:       pc.Headers,
+		NoAuth:        pc.NoAuth,
+		DynamicModels: pc.DynamicModels,
+		MaxRetries:    pc.MaxRetries,
+		UserAgent:     pc.UserAgent,
+	}
+	if pc.Timeout != 0 {
+		cfg = cfg.WithTimeoutDuration(time.Duration(pc.Timeout))
+	}
+	if pc.RetryDelay != 0 {
+		delay := time.Duration(pc.RetryDelay)
+		cfg.RetryDelay = &delay
+	}
+
+	profile, known := providerProfile(name)
+	switch {
+	case pc.APIKeyEnv != "":
+		cfg.APIKey = os.Getenv(pc.APIKeyEnv)
+		if cfg.APIKey == "" && !pc.NoAuth {
+			return nil, fmt.Errorf("config: provider %q: environment variable %s is not set", name, pc.APIKeyEnv)
+		}
+	case known:
+		cfg.APIKey = configuredAPIKey(profile)
+		if cfg.APIKey == "" && !pc.NoAuth && !profile.Local && len(profile.APIKeyEnv) > 0 {
+			return nil, fmt.Errorf("config: provider %q: none of %s is set", name, strings.Join(profile.APIKeyEnv, ", "))
+		}
+	}
+
+	if !known {
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("config: provider %q is not a known provider and has no base_url", name)
+		}
+		return WithOpenAICompatible(name, cfg.BaseURL, cfg), nil
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = configuredBaseURL(profile)
+	}
+	return func(c *Config) { configureProvider(c, name, profile, cfg) }, nil
+}
+
+func (mc FileMiddlewareConfig) middleware() (middleware.Middleware, error) {
+	switch mc.Type {
+	case "timeout":
+		if mc.Timeout <= 0 {
+			return nil, fmt.Errorf("timeout middleware needs a positive timeout")
+		}
+		return middleware.TimeoutMiddleware(time.Duration(mc.Timeout)), nil
+	case "rate_limit":
+		if mc.RequestsPerSecond <= 0 {
+			return nil, fmt.Errorf("rate_limit middleware needs positive requests_per_second")
+		}
+		return middleware.RateLimitMiddleware(mc.RequestsPerSecond), nil
+	case "circuit_breaker":
+		if mc.FailureThreshold <= 0 || mc.Timeout <= 0 {
+			return nil, fmt.Errorf("circuit_breaker middleware needs positive failure_threshold and timeout")
+		}
+		return middleware.CircuitBreakerMiddleware(mc.FailureThreshold, time.Duration(mc.Timeout)), nil
+	case "retry":
+		config := middleware.DefaultRetryConfig()
+		config.MaxRetries = mc.MaxRetries
+		if mc.InitialDelay > 0 {
+			config.InitialDelay = time.Duration(mc.InitialDelay)
+		}
+		if mc.MaxDelay > 0 {
+			config.MaxDelay = time.Duration(mc.MaxDelay)
+		}
+		return middleware.RetryMiddleware(config), nil
+	default:
+		return nil, fmt.Errorf("unknown middleware type %q (want timeout, rate_limit, circuit_breaker, or retry)", mc.Type)
+	}
+}
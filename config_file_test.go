@@ -0,0 +1,197 @@
+package wormhole
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+	mockpkg "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestNewFromConfigFile(t *testing.T) {
+	t.Setenv("TEST_GATEWAY_KEY", "gw-key")
+	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-test")
+
+	path := writeConfigFile(t, "wormhole.json", `{
+		"default_provider": "gateway",
+		"timeout": "45s",
+		"retries": {"max": 1, "delay": "250ms"},
+		"discovery": false,
+		"providers": {
+			"gateway": {"base_url": "https://llm.internal/v1", "api_key_env": "TEST_GATEWAY_KEY", "default_model": "fast", "timeout": "5s"},
+			"anthropic": {"user_agent": "acme/1.0"}
+		},
+		"middleware": [
+			{"type": "circuit_breaker", "failure_threshold": 5, "timeout": "30s"},
+			{"type": "retry", "max_retries": 2}
+		],
+		"model_aliases": {"fast": "llama-3.1-8b"}
+	}`)
+
+	client, err := NewFromConfigFile(path, WithModelAliases(map[string]string{"smart": "llama-3.1-70b"}))
+	require.NoError(t, err)
+	defer client.Close()
+
+	cfg := client.config
+	assert.Equal(t, "gateway", cfg.DefaultProvider)
+	assert.Equal(t, 45*time.Second, cfg.DefaultTimeout)
+	assert.Equal(t, 1, cfg.DefaultRetries)
+	assert.False(t, cfg.EnableDiscovery)
+	assert.Len(t, cfg.Middleware, 2)
+	assert.Equal(t, map[string]string{"fast": "llama-3.1-8b", "smart": "llama-3.1-70b"}, cfg.ModelAliases)
+	assert.Equal(t, "fast", cfg.DefaultModels["gateway"])
+
+	gateway := cfg.Providers["gateway"]
+	assert.Equal(t, "gw-key", gateway.APIKey)
+	assert.Equal(t, "https://llm.internal/v1", gateway.BaseURL)
+	require.NotNil(t, gateway.HTTPTimeout)
+	assert.Equal(t, 5*time.Second, *gateway.HTTPTimeout)
+	assert.Contains(t, cfg.CustomFactories, "gateway")
+
+	anthropic := cfg.Providers["anthropic"]
+	assert.Equal(t, "sk-ant-test", anthropic.APIKey)
+	assert.Equal(t, "acme/1.0", anthropic.UserAgent)
+}
+
+func TestLoadConfigFileYAML(t *testing.T) {
+	t.Setenv("TEST_GATEWAY_KEY", "gw-key")
+
+	yamlPath := writeConfigFile(t, "wormhole.yaml", `
+default_provider: gateway
+timeout: 45s
+retries: {max: 1, delay: 250ms}
+discovery: false
+providers:
+  gateway:
+    base_url: https://llm.internal/v1
+    api_key_env: TEST_GATEWAY_KEY
+    default_model: fast
+middleware:
+  - {type: circuit_breaker, failure_threshold: 5, timeout: 30s}
+model_aliases:
+  fast: llama-3.1-8b
+validators:
+  person: {type: json_schema, schema: {type: object, required: [name]}}
+`)
+	jsonPath := writeConfigFile(t, "wormhole.json", `{
+		"default_provider": "gateway",
+		"timeout": "45s",
+		"retries": {"max": 1, "delay": "250ms"},
+		"discovery": false,
+		"providers": {"gateway": {"base_url": "https://llm.internal/v1", "api_key_env": "TEST_GATEWAY_KEY", "default_model": "fast"}},
+		"middleware": [{"type": "circuit_breaker", "failure_threshold": 5, "timeout": "30s"}],
+		"model_aliases": {"fast": "llama-3.1-8b"},
+		"validators": {"person": {"type": "json_schema", "schema": {"type": "object", "required": ["name"]}}}
+	}`)
+
+	fromYAML, err := LoadConfigFile(yamlPath)
+	require.NoError(t, err)
+	fromJSON, err := LoadConfigFile(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, fromJSON.Timeout, fromYAML.Timeout)
+	assert.Equal(t, fromJSON.Retries, fromYAML.Retries)
+	assert.Equal(t, fromJSON.Providers, fromYAML.Providers)
+	assert.Equal(t, fromJSON.Middleware, fromYAML.Middleware)
+	assert.Equal(t, fromJSON.ModelAliases, fromYAML.ModelAliases)
+
+	client, err := NewFromConfigFile(yamlPath)
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, "gateway", client.config.DefaultProvider)
+	assert.Equal(t, 45*time.Second, client.config.DefaultTimeout)
+	assert.NoError(t, client.config.ResponseValidators["person"].ValidateResponse(context.Background(), ValidationInput{Text: `{"name": "Ada"}`}))
+	assert.Error(t, client.config.ResponseValidators["person"].ValidateResponse(context.Background(), ValidationInput{Text: `{}`}))
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	t.Setenv("TEST_MISSING_KEY", "")
+
+	_, err := LoadConfigFile(writeConfigFile(t, "wormhole.toml", `default_provider = "openai"`))
+	assert.ErrorContains(t, err, "TOML")
+
+	_, err = LoadConfigFile(writeConfigFile(t, "typo.json", `{"default_provder": "openai"}`))
+	assert.ErrorContains(t, err, "default_provder")
+
+	_, err = LoadConfigFile(writeConfigFile(t, "typo.yaml", "default_provder: openai\n"))
+	assert.ErrorContains(t, err, "default_provder")
+
+	_, err = LoadConfigFile(writeConfigFile(t, "duration.yml", "timeout: soon\n"))
+	assert.ErrorContains(t, err, "soon")
+
+	_, err = NewFromConfigFile(writeConfigFile(t, "missing-env.json", `{"providers": {"gw": {"base_url": "http://x", "api_key_env": "TEST_MISSING_KEY"}}}`))
+	assert.ErrorContains(t, err, "TEST_MISSING_KEY")
+
+	_, err = NewFromConfigFile(writeConfigFile(t, "unknown.json", `{"providers": {"mystery": {}}}`))
+	assert.ErrorContains(t, err, "no base_url")
+
+	_, err = NewFromConfigFile(writeConfigFile(t, "mw.json", `{"middleware": [{"type": "teleport"}]}`))
+	assert.ErrorContains(t, err, "teleport")
+
+	_, err = NewFromConfigFile(writeConfigFile(t, "default.json", `{"default_provider": "openai"}`))
+	assert.ErrorContains(t, err, "not declared")
+
+	_, err = NewFromConfigFile(writeConfigFile(t, "validator.json", `{"validators": {"id": {"type": "regex", "pattern": "("}}}`))
+	assert.ErrorContains(t, err, "validators[id]")
+}
+
+func TestConfigFileValidators(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewFromConfigFile(writeConfigFile(t, "validators.json", `{
+		"discovery": false,
+		"validators": {
+			"order_id": {"type": "regex", "pattern": "^ORD-\\d{4}$"},
+			"person": {"type": "json_schema", "schema": {"type": "object", "required": ["name"]}},
+			"grounded": {"type": "judge", "model": "judge-model", "criteria": "cites a source"}
+		}
+	}`))
+	require.NoError(t, err)
+	defer client.Close()
+
+	validators := client.config.ResponseValidators
+	require.Len(t, validators, 3)
+	ctx := context.Background()
+	assert.NoError(t, validators["order_id"].ValidateResponse(ctx, ValidationInput{Text: "ORD-1234"}))
+	assert.Error(t, validators["order_id"].ValidateResponse(ctx, ValidationInput{Text: "ORD-1"}))
+	assert.NoError(t, validators["person"].ValidateResponse(ctx, ValidationInput{Text: `{"name": "Ada"}`}))
+}
+
+func TestModelAliasesAndDefaultModels(t *testing.T) {
+	t.Parallel()
+
+	provider := mockpkg.NewMockProvider("mock")
+	client := New(
+		WithDefaultProvider("mock"),
+		WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+		WithProviderConfig("mock", types.ProviderConfig{}),
+		WithDiscovery(false),
+		WithModelAliases(map[string]string{"fast": "mock-small"}),
+		WithDefaultModel("mock", "fast"),
+	)
+	defer client.Close()
+
+	resp, err := client.Text().Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "mock-small", resp.Model, "default model resolves through aliases")
+
+	resp, err = client.Text().Model("fast").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "mock-small", resp.Model)
+
+	resp, err = client.Text().Model("mock-large").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "mock-large", resp.Model)
+}
@@ -0,0 +1,334 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/garyblankenship/wormhole/v2/chunk"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ContextManagerConfig configures the context manager enabled by
+// WithContextManager.
+type ContextManagerConfig struct {
+	// Summarizer is the model that condenses older messages into a summary.
+	// A route with no Model truncates instead, dropping older messages.
+	Summarizer TextRoute
+	// KeepRecent is how many of the latest messages are never summarized
+	// (default 4). They are still dropped, oldest first, if they alone
+	// overflow the window.
+	KeepRecent int
+	// ReserveTokens is the room left for the reply when a request sets no
+	// MaxTokens (default 1024).
+	ReserveTokens int
+	// DefaultContextLength applies to models whose context length is not in
+	// the model registry or discovery. Zero leaves such requests untouched.
+	DefaultContextLength int
+	// Counter counts tokens. Nil uses chunk.EstimateTokens.
+	Counter chunk.TokenCounter
+}
+
+// ContextStats counts what a ContextManager has done.
+type ContextStats struct {
+	Checked       int64 // Requests measured against a known context length
+	Fitted        int64 // Requests that had to be shortened
+	Dropped       int64 // Messages summarized or removed, in total
+	Summaries     int64 // Summaries written
+	SummaryErrors int64 // Summaries that failed and fell back to truncation
+	Rejected      int64 // Requests whose newest message alone did not fit
+}
+
+// ContextManager keeps requests inside their model's context window. Before
+// each text, stream and structured request it estimates the prompt size and,
+// when the prompt would not leave room for the reply, summarizes or drops the
+// oldest messages. The system prompt, leading system messages and the newest
+// message are always kept; a request whose newest message alone overflows
+// fails with ErrRequestTooLarge before reaching the provider.
+type ContextManager struct {
+	client *Wormhole
+	config ContextManagerConfig
+
+	mu    sync.Mutex
+	stats ContextStats
+}
+
+// mediaTokens is the flat estimate charged per attached image or document.
+const mediaTokens = 1024
+
+// messageOverhead approximates the tokens a provider spends framing a message.
+const messageOverhead = 4
+
+func newContextManager(client *Wormhole, config ContextManagerConfig) *ContextManager {
+	if config.KeepRecent <= 0 {
+		config.KeepRecent = 4
+	}
+	if config.ReserveTokens <= 0 {
+		config.ReserveTokens = 1024
+	}
+	if config.Counter == nil {
+		config.Counter = chunk.EstimateTokens
+	}
+	return &ContextManager{client: client, config: config}
+}
+
+// ContextManager returns the client's context manager, or nil without
+// WithContextManager.
+func (p *Wormhole) ContextManager() *ContextManager {
+	return p.contextManager
+}
+
+// Stats returns a snapshot of the manager's counters.
+func (m *ContextManager) Stats() ContextStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+// Fit returns messages shortened to fit model's context window alongside
+// systemPrompt and a reply of maxTokens. messages is returned as is when it
+// already fits or the model's context length is unknown.
+func (m *ContextManager) Fit(ctx context.Context, provider, model, systemPrompt string, messages []types.Message, maxTokens int) ([]types.Message, error) {
+	if skip, _ := ctx.Value(skipContextManagerKey{}).(bool); skip {
+		return messages, nil
+	}
+	window := m.contextLength(ctx, provider, model)
+	if window <= 0 || len(messages) == 0 {
+		return messages, nil
+	}
+	reserve := m.config.ReserveTokens
+	if maxTokens > 0 {
+		reserve = maxTokens
+	}
+	budget := window - reserve - m.config.Counter(systemPrompt)
+
+	m.record(func(s *ContextStats) { s.Checked++ })
+	if m.cost(messages) <= budget {
+		return messages, nil
+	}
+
+	lead := 0
+	for lead < len(messages) && messages[lead].GetRole().IsSystem() {
+		lead++
+	}
+	head, rest := messages[:lead], messages[lead:]
+	budget -= m.cost(head)
+
+	var summary types.Message
+	if m.config.Summarizer.Model != "" {
+		cut := trimToUserTurn(rest, max(len(rest)-m.config.KeepRecent, 0))
+		if cut > 0 {
+			text, _, err := m.summarize(ctx, rest[:cut])
+			if err == nil {
+				summary = types.NewSystemMessage("Summary of the earlier conversation:\n" + text)
+				rest = rest[cut:]
+				budget -= m.cost([]types.Message{summary})
+				m.record(func(s *ContextStats) { s.Summaries++ })
+			} else {
+				m.record(func(s *ContextStats) { s.SummaryErrors++ })
+			}
+		}
+	}
+
+	start := 0
+	for start < len(rest)-1 && m.cost(rest[start:]) > budget {
+		start = trimToUserTurn(rest, start+1)
+	}
+	rest = rest[start:]
+	if m.cost(rest) > budget {
+		m.record(func(s *ContextStats) { s.Rejected++ })
+		return nil, types.ErrRequestTooLarge.
+			WithModel(model).
+			WithProvider(provider).
+			WithDetails(fmt.Sprintf("the newest message needs about %d tokens but %s leaves %d after the system prompt and a %d-token reply", m.cost(rest), model, max(budget, 0), reserve))
+	}
+
+	fitted := make([]types.Message, 0, len(head)+1+len(rest))
+	fitted = append(fitted, head...)
+	if summary != nil {
+		fitted = append(fitted, summary)
+	}
+	fitted = append(fitted, rest...)
+	m.record(func(s *ContextStats) {
+		s.Fitted++
+		s.Dropped += int64(len(messages) - len(head) - len(rest))
+	})
+	return fitted, nil
+}
+
+// trimToUserTurn advances i past assistant and tool messages so the kept
+// history starts on a user turn: tool results never lose their call, and
+// providers that require a leading user message accept the result. The last
+// message is never skipped.
+func trimToUserTurn(messages []types.Message, i int) int {
+	for i > 0 && i < len(messages)-1 && messages[i].GetRole() != types.RoleUser {
+		i++
+	}
+	return i
+}
+
+// summarizerPrompt instructs the summarizer model.
+const summarizerPrompt = "Summarize the conversation below for the assistant that will continue it. Keep facts, decisions, names, numbers and open questions; drop pleasantries. Reply with the summary only."
+
+// skipContextManagerKey marks the summarizer's own requests, which are sized
+// by summarize and must not be fitted (or summarized) again.
+type skipContextManagerKey struct{}
+
+// summarize asks the summarizer model to condense messages, returning the
+// summary and what the call used. When the transcript would overflow the
+// summarizer's own window, its oldest entries are left out.
+func (m *ContextManager) summarize(ctx context.Context, messages []types.Message) (string, *types.Usage, error) {
+	entries := make([]string, len(messages))
+	for i, message := range messages {
+		entries[i] = fmt.Sprintf("%s: %s\n\n", message.GetRole(), messageText(message))
+	}
+	if window := m.contextLength(ctx, m.config.Summarizer.Provider, m.config.Summarizer.Model); window > 0 {
+		budget := window - m.config.ReserveTokens - m.config.Counter(summarizerPrompt)
+		for len(entries) > 1 && m.config.Counter(strings.Join(entries, "")) > budget {
+			entries = entries[1:]
+		}
+	}
+
+	request := m.client.Text().
+		SystemPrompt(summarizerPrompt).
+		Prompt(strings.Join(entries, "")).
+		Model(m.config.Summarizer.Model)
+	if m.config.Summarizer.Provider != "" {
+		request.Using(m.config.Summarizer.Provider)
+	}
+	resp, err := request.Generate(context.WithValue(ctx, skipContextManagerKey{}, true))
+	if err != nil {
+		return "", nil, err
+	}
+	text := strings.TrimSpace(resp.Content())
+	if text == "" {
+		return "", resp.Usage, types.ErrEmptyResponse.WithModel(m.config.Summarizer.Model)
+	}
+	return text, resp.Usage, nil
+}
+
+// contextLength looks model up in the model registry, then in discovery.
+func (m *ContextManager) contextLength(ctx context.Context, provider, model string) int {
+	if registry := m.client.modelRegistry; registry != nil {
+		if info, ok := registry.Get(model); ok && info.ContextLength > 0 {
+			return info.ContextLength
+		}
+	}
+	if m.client.discoveryService != nil && provider != "" {
+		if models, err := m.client.discoveryService.GetModels(ctx, provider); err == nil {
+			for _, info := range models {
+				if info != nil && info.ID == model && info.ContextLength > 0 {
+					return info.ContextLength
+				}
+			}
+		}
+	}
+	return m.config.DefaultContextLength
+}
+
+// cost estimates the prompt tokens messages take.
+func (m *ContextManager) cost(messages []types.Message) int {
+	total := 0
+	for _, message := range messages {
+		total += messageOverhead + m.config.Counter(messageText(message))
+		switch msg := message.(type) {
+		case *types.UserMessage:
+			total += mediaTokens * len(msg.Media)
+		case *types.AssistantMessage:
+			for _, call := range msg.ToolCalls {
+				args, _ := json.Marshal(call)
+				total += m.config.Counter(string(args))
+			}
+		}
+	}
+	return total
+}
+
+func (m *ContextManager) record(update func(*ContextStats)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	update(&m.stats)
+}
+
+// messageText returns a message's content as text.
+func messageText(message types.Message) string {
+	switch content := message.GetContent().(type) {
+	case string:
+		return content
+	case nil:
+		return ""
+	default:
+		data, _ := json.Marshal(content)
+		return string(data)
+	}
+}
+
+// contextManagerMiddleware fits text, stream and structured requests to
+// their model's context window.
+type contextManagerMiddleware struct {
+	manager *ContextManager
+}
+
+func contextProvider(ctx context.Context) string {
+	provider, _ := ctx.Value(middleware.CtxKeyProvider).(string)
+	return provider
+}
+
+func maxTokens(request types.BaseRequest) int {
+	if request.MaxTokens == nil {
+		return 0
+	}
+	return *request.MaxTokens
+}
+
+func (m contextManagerMiddleware) ApplyText(next types.TextHandler) types.TextHandler {
+	return func(ctx context.Context, request types.TextRequest) (*types.TextResponse, error) {
+		messages, err := m.manager.Fit(ctx, contextProvider(ctx), request.Model, request.SystemPrompt, request.Messages, maxTokens(request.BaseRequest))
+		if err != nil {
+			return nil, err
+		}
+		request.Messages = messages
+		return next(ctx, request)
+	}
+}
+
+func (m contextManagerMiddleware) ApplyStream(next types.StreamHandler) types.StreamHandler {
+	return func(ctx context.Context, request types.TextRequest) (<-chan types.TextChunk, error) {
+		messages, err := m.manager.Fit(ctx, contextProvider(ctx), request.Model, request.SystemPrompt, request.Messages, maxTokens(request.BaseRequest))
+		if err != nil {
+			return nil, err
+		}
+		request.Messages = messages
+		return next(ctx, request)
+	}
+}
+
+func (m contextManagerMiddleware) ApplyStructured(next types.StructuredHandler) types.StructuredHandler {
+	return func(ctx context.Context, request types.StructuredRequest) (*types.StructuredResponse, error) {
+		messages, err := m.manager.Fit(ctx, contextProvider(ctx), request.Model, request.SystemPrompt, request.Messages, maxTokens(request.BaseRequest))
+		if err != nil {
+			return nil, err
+		}
+		request.Messages = messages
+		return next(ctx, request)
+	}
+}
+
+func (m contextManagerMiddleware) ApplyEmbeddings(next types.EmbeddingsHandler) types.EmbeddingsHandler {
+	return next
+}
+
+func (m contextManagerMiddleware) ApplyAudio(next types.AudioHandler) types.AudioHandler {
+	return next
+}
+
+func (m contextManagerMiddleware) ApplyImage(next types.ImageHandler) types.ImageHandler {
+	return next
+}
+
+func (m contextManagerMiddleware) ApplyRerank(next types.RerankHandler) types.RerankHandler {
+	return next
+}
@@ -0,0 +1,128 @@
+package wormhole_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func newContextClient(t *testing.T, config wormhole.ContextManagerConfig, rules ...mocktesting.ScenarioRule) *wormhole.Wormhole {
+	t.Helper()
+	provider := mocktesting.NewMockProvider("mock").WithScenario(mocktesting.Scenario{Rules: rules})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+		wormhole.WithContextManager(config),
+	)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// longConversation returns turns user/assistant pairs of about 100 estimated
+// tokens each, then a final user question.
+func longConversation(turns int) []types.Message {
+	filler := strings.Repeat("word ", 80)
+	var messages []types.Message
+	for i := range turns {
+		messages = append(messages,
+			types.NewUserMessage(filler+"question "+string(rune('a'+i))),
+			types.NewAssistantMessage(filler+"answer "+string(rune('a'+i))),
+		)
+	}
+	return append(messages, types.NewUserMessage("final question"))
+}
+
+func TestContextManagerTruncatesOldestTurns(t *testing.T) {
+	t.Parallel()
+	client := newContextClient(t, wormhole.ContextManagerConfig{DefaultContextLength: 1000, ReserveTokens: 200})
+	manager := client.ContextManager()
+	require.NotNil(t, manager)
+
+	messages := append([]types.Message{types.NewSystemMessage("be brief")}, longConversation(10)...)
+	fitted, err := manager.Fit(context.Background(), "mock", "small", "", messages, 0)
+	require.NoError(t, err)
+
+	require.Less(t, len(fitted), len(messages))
+	assert.Equal(t, types.RoleSystem, fitted[0].GetRole(), "leading system messages are kept")
+	assert.Equal(t, types.RoleUser, fitted[1].GetRole(), "kept history starts on a user turn")
+	assert.Equal(t, "final question", fitted[len(fitted)-1].GetContent())
+
+	stats := manager.Stats()
+	assert.Equal(t, int64(1), stats.Fitted)
+	assert.Equal(t, int64(len(messages)-len(fitted)), stats.Dropped)
+
+	short := longConversation(1)
+	same, err := manager.Fit(context.Background(), "mock", "small", "", short, 0)
+	require.NoError(t, err)
+	assert.Len(t, same, len(short), "requests that fit are untouched")
+}
+
+func TestContextManagerSummarizesBeforeRequest(t *testing.T) {
+	t.Parallel()
+	client := newContextClient(t,
+		wormhole.ContextManagerConfig{
+			Summarizer:           wormhole.TextRoute{Model: "cheap"},
+			KeepRecent:           3,
+			DefaultContextLength: 1000,
+			ReserveTokens:        200,
+		},
+		mocktesting.ScenarioRule{Model: "cheap", Respond: "The user asked ten questions."},
+		mocktesting.ScenarioRule{Match: "Summary of the earlier conversation", Respond: "used summary"},
+		mocktesting.ScenarioRule{Respond: "no summary"},
+	)
+
+	resp, err := client.Text().Model("big").Messages(longConversation(10)...).Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "used summary", resp.Content())
+
+	stats := client.ContextManager().Stats()
+	assert.Equal(t, int64(1), stats.Summaries)
+	assert.Equal(t, int64(0), stats.SummaryErrors)
+}
+
+func TestContextManagerFallsBackWhenSummarizerFails(t *testing.T) {
+	t.Parallel()
+	client := newContextClient(t,
+		wormhole.ContextManagerConfig{Summarizer: wormhole.TextRoute{Model: "cheap"}, DefaultContextLength: 1000, ReserveTokens: 200},
+		mocktesting.ScenarioRule{Model: "cheap", Error: "bad request", Status: 400},
+		mocktesting.ScenarioRule{Respond: "ok"},
+	)
+
+	resp, err := client.Text().Model("big").Messages(longConversation(10)...).Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Content())
+	assert.Equal(t, int64(1), client.ContextManager().Stats().SummaryErrors)
+}
+
+func TestContextManagerRejectsOversizedMessage(t *testing.T) {
+	t.Parallel()
+	client := newContextClient(t, wormhole.ContextManagerConfig{DefaultContextLength: 500}, mocktesting.ScenarioRule{Respond: "ok"})
+
+	_, err := client.Text().Model("small").Prompt(strings.Repeat("word ", 2000)).Generate(context.Background())
+	wormholeErr, ok := types.AsWormholeError(err)
+	require.True(t, ok)
+	assert.Equal(t, types.ErrRequestTooLarge.Message, wormholeErr.Message)
+	assert.Equal(t, "small", wormholeErr.Model)
+	assert.Equal(t, int64(1), client.ContextManager().Stats().Rejected)
+}
+
+func TestContextManagerLeavesUnknownModelsAlone(t *testing.T) {
+	t.Parallel()
+	client := newContextClient(t, wormhole.ContextManagerConfig{ReserveTokens: 100})
+
+	messages := longConversation(10)
+	fitted, err := client.ContextManager().Fit(context.Background(), "mock", "unregistered", "", messages, 0)
+	require.NoError(t, err)
+	assert.Len(t, fitted, len(messages))
+	assert.Zero(t, client.ContextManager().Stats().Checked)
+}
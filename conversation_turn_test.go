@@ -0,0 +1,44 @@
+package wormhole
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+	whtest "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestTextRequestBuilderGenerateTurnRecordsUsage(t *testing.T) {
+	t.Parallel()
+
+	response := whtest.TextResponseWith("Go is a language.")
+	response.Model = "turn-model"
+	response.Usage = &types.Usage{PromptTokens: 4, CompletionTokens: 5, TotalTokens: 9}
+	mock := whtest.NewMockProvider("mock").WithTextResponse(response)
+	client := New(
+		WithDefaultProvider("mock"),
+		WithCustomProvider("mock", whtest.MockProviderFactory(mock)),
+		WithProviderConfig("mock", types.ProviderConfig{}),
+		WithModelValidation(false),
+		WithDiscovery(false),
+	)
+
+	conv := types.NewConversation().System("be brief").User("What is Go?")
+	resp, err := client.Text().Model("turn-model").GenerateTurn(context.Background(), conv)
+	require.NoError(t, err)
+	assert.Equal(t, "Go is a language.", resp.Content())
+
+	require.Equal(t, 3, conv.Len())
+	turns := conv.Turns()
+	require.Len(t, turns, 1)
+	assert.Equal(t, 2, turns[0].MessageIndex)
+	assert.Equal(t, "turn-model", turns[0].Model)
+	assert.Equal(t, 9, turns[0].Usage.TotalTokens)
+	assert.Positive(t, turns[0].Latency)
+
+	_, err = client.Text().Model("turn-model").GenerateTurn(context.Background(), nil)
+	require.Error(t, err)
+}
@@ -81,6 +81,7 @@ func cloneModels(models []*types.ModelInfo) []*types.ModelInfo {
 		}
 		modelCopy.Capabilities = append([]types.ModelCapability(nil), model.Capabilities...)
 		modelCopy.Constraints = cloneConstraints(model.Constraints)
+		modelCopy.ParamConstraints = model.ParamConstraints.Clone()
 		cloned[i] = &modelCopy
 	}
 	return cloned
@@ -9,6 +9,7 @@ import (
 
 // AnthropicFetcher fetches models from Anthropic API
 type AnthropicFetcher struct {
+	userAgent
 	apiKey  string
 	baseURL string
 }
@@ -38,7 +39,7 @@ func (f *AnthropicFetcher) FetchModels(ctx context.Context) ([]*types.ModelInfo,
 		return nil, fmt.Errorf("anthropic API key not configured")
 	}
 
-	req, err := newGetRequest(ctx, f.baseURL+"/models")
+	req, err := f.newGetRequest(ctx, f.baseURL+"/models")
 	if err != nil {
 		return nil, err
 	}
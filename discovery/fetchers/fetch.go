@@ -8,7 +8,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/garyblankenship/wormhole/v2/discovery"
 	"github.com/garyblankenship/wormhole/v2/providers"
+	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 var (
@@ -37,10 +39,29 @@ func fetchJSON(req *http.Request, out any) error {
 	return json.NewDecoder(resp.Body).Decode(out)
 }
 
-func newGetRequest(ctx context.Context, url string) (*http.Request, error) {
+// userAgent carries a fetcher's User-Agent policy; fetchers embed it.
+type userAgent struct {
+	config types.ProviderConfig
+}
+
+func (u *userAgent) setUserAgent(config types.ProviderConfig) {
+	u.config = types.ProviderConfig{UserAgent: config.UserAgent, DisableUserAgent: config.DisableUserAgent}
+}
+
+func (u *userAgent) newGetRequest(ctx context.Context, url string) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	providers.SetUserAgent(req, u.config)
 	return req, nil
 }
+
+// ApplyUserAgent makes fetcher send the User-Agent configured by config's
+// UserAgent and DisableUserAgent fields, matching the provider it lists
+// models for. Fetchers from other packages are left unchanged.
+func ApplyUserAgent(fetcher discovery.ModelFetcher, config types.ProviderConfig) {
+	if f, ok := fetcher.(interface{ setUserAgent(types.ProviderConfig) }); ok {
+		f.setUserAgent(config)
+	}
+}
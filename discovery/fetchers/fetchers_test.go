@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/garyblankenship/wormhole/v2/providers"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
@@ -153,6 +154,55 @@ func TestOllamaFetcher(t *testing.T) {
 	assert.True(t, hasCapability(models[1], types.CapabilityEmbeddings))
 }
 
+func TestLMStudioFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/models", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"models":[
+			{"type":"llm","key":"qwen/qwen3-8b","display_name":"Qwen3 8B","publisher":"qwen","max_context_length":32768,
+			 "loaded_instances":[{"id":"qwen/qwen3-8b","config":{"context_length":8192}}],"capabilities":{"vision":true}},
+			{"type":"embedding","key":"nomic-embed-text-v1.5","max_context_length":2048}
+		]}`))
+	}))
+	defer server.Close()
+	useTestHTTPClient(t, server.Client())
+
+	models, err := NewLMStudioFetcher(server.URL+"/v1", "").FetchModels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, models, 2)
+	assert.Equal(t, "qwen/qwen3-8b", models[0].ID)
+	assert.Equal(t, "Qwen3 8B", models[0].Name)
+	assert.Equal(t, 8192, models[0].ContextLength)
+	assert.Equal(t, true, models[0].Constraints["loaded"])
+	assert.True(t, hasCapability(models[0], types.CapabilityVision))
+	assert.Equal(t, 2048, models[1].ContextLength)
+	assert.Equal(t, false, models[1].Constraints["loaded"])
+	assert.True(t, hasCapability(models[1], types.CapabilityEmbeddings))
+}
+
+func TestLMStudioFetcherFallsBackToOpenAIModels(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "Bearer lm-key", r.Header.Get("Authorization"))
+		if r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"qwen/qwen3-8b","owned_by":"organization_owner"}]}`))
+	}))
+	defer server.Close()
+	useTestHTTPClient(t, server.Client())
+
+	models, err := NewLMStudioFetcher(server.URL, "lm-key").FetchModels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/api/v1/models", "/v1/models"}, paths)
+	require.Len(t, models, 1)
+	assert.Equal(t, "qwen/qwen3-8b", models[0].ID)
+	assert.Equal(t, "lmstudio", models[0].Provider)
+}
+
 func TestGeminiFetcher(t *testing.T) {
 	var sawKey bool
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -282,7 +332,7 @@ func TestFetchJSONReturnsStatusError(t *testing.T) {
 	defer server.Close()
 	useTestHTTPClient(t, server.Client())
 
-	req, err := newGetRequest(context.Background(), server.URL)
+	req, err := (&userAgent{}).newGetRequest(context.Background(), server.URL)
 	require.NoError(t, err)
 
 	var out map[string]any
@@ -290,3 +340,30 @@ func TestFetchJSONReturnsStatusError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "status 418")
 }
+
+func TestApplyUserAgent(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("User-Agent"))
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+	useTestHTTPClient(t, server.Client())
+
+	fetcher := NewOpenAICompatibleFetcher("compatible", server.URL, "", nil)
+	_, err := fetcher.FetchModels(context.Background())
+	require.NoError(t, err)
+
+	ApplyUserAgent(fetcher, types.ProviderConfig{UserAgent: "acme-bot/1.0"})
+	_, err = fetcher.FetchModels(context.Background())
+	require.NoError(t, err)
+
+	ApplyUserAgent(fetcher, types.ProviderConfig{DisableUserAgent: true})
+	_, err = fetcher.FetchModels(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, got, 3)
+	assert.Equal(t, providers.DefaultUserAgent(), got[0])
+	assert.Equal(t, "acme-bot/1.0", got[1])
+	assert.Empty(t, got[2])
+}
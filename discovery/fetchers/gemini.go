@@ -13,6 +13,7 @@ const defaultGeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
 
 // GeminiFetcher fetches models from Google's Gemini API.
 type GeminiFetcher struct {
+	userAgent
 	apiKey  string
 	baseURL string
 }
@@ -45,7 +46,7 @@ func (f *GeminiFetcher) FetchModels(ctx context.Context) ([]*types.ModelInfo, er
 		return nil, fmt.Errorf("gemini API key not configured")
 	}
 
-	req, err := newGetRequest(ctx, f.baseURL+"/models?key="+url.QueryEscape(f.apiKey))
+	req, err := f.newGetRequest(ctx, f.baseURL+"/models?key="+url.QueryEscape(f.apiKey))
 	if err != nil {
 		return nil, err
 	}
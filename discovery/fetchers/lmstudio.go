@@ -0,0 +1,135 @@
+package fetchers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// LMStudioFetcher fetches downloaded models from LM Studio's native REST API,
+// which, unlike its OpenAI-compatible /v1/models, reports which models are
+// loaded and with what context length. Servers without the native API are
+// listed through /v1/models instead.
+type LMStudioFetcher struct {
+	userAgent
+	baseURL string
+	apiKey  string
+}
+
+// NewLMStudioFetcher creates a new LM Studio model fetcher. baseURL may be
+// the OpenAI-compatible root ("http://localhost:1234/v1") or the server root.
+// apiKey, when set, is sent as a bearer token for servers that require one.
+func NewLMStudioFetcher(baseURL, apiKey string) *LMStudioFetcher {
+	if baseURL == "" {
+		baseURL = "http://localhost:1234"
+	}
+	return &LMStudioFetcher{
+		baseURL: strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/v1"),
+		apiKey:  apiKey,
+	}
+}
+
+// Name returns the provider name
+func (f *LMStudioFetcher) Name() string {
+	return "lmstudio"
+}
+
+// AccountDiscriminator scopes the model cache per API key, as for other
+// OpenAI-compatible servers.
+func (f *LMStudioFetcher) AccountDiscriminator() string {
+	return accountKeyDiscriminator(f.apiKey)
+}
+
+// FetchModels retrieves all downloaded models from LM Studio. Loaded models
+// report their loaded context length in ContextLength and have
+// Constraints["loaded"] set; others report their maximum context length.
+// When the native API fails, as on LM Studio releases that predate it, the
+// models come from the OpenAI-compatible /v1/models without loaded state.
+func (f *LMStudioFetcher) FetchModels(ctx context.Context) ([]*types.ModelInfo, error) {
+	models, err := f.fetchNativeModels(ctx)
+	if err == nil {
+		return models, nil
+	}
+	compatible := &OpenAICompatibleFetcher{userAgent: f.userAgent, name: f.Name(), baseURL: f.baseURL + "/v1", apiKey: f.apiKey}
+	models, fallbackErr := compatible.FetchModels(ctx)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("%w; /v1/models: %w", err, fallbackErr)
+	}
+	return models, nil
+}
+
+func (f *LMStudioFetcher) fetchNativeModels(ctx context.Context) ([]*types.ModelInfo, error) {
+	req, err := f.newGetRequest(ctx, f.baseURL+"/api/v1/models")
+	if err != nil {
+		return nil, err
+	}
+	if f.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+f.apiKey)
+	}
+
+	var response struct {
+		Models []struct {
+			Type             string `json:"type"`
+			Key              string `json:"key"`
+			DisplayName      string `json:"display_name"`
+			Publisher        string `json:"publisher"`
+			MaxContextLength int    `json:"max_context_length"`
+			LoadedInstances  []struct {
+				ID     string `json:"id"`
+				Config struct {
+					ContextLength int `json:"context_length"`
+				} `json:"config"`
+			} `json:"loaded_instances"`
+			Capabilities struct {
+				Vision            bool `json:"vision"`
+				TrainedForToolUse bool `json:"trained_for_tool_use"`
+			} `json:"capabilities"`
+		} `json:"models"`
+	}
+
+	if err := fetchJSON(req, &response); err != nil {
+		return nil, err
+	}
+
+	models := make([]*types.ModelInfo, 0, len(response.Models))
+	for _, m := range response.Models {
+		capabilities := []types.ModelCapability{types.CapabilityText, types.CapabilityChat}
+		if m.Capabilities.Vision {
+			capabilities = append(capabilities, types.CapabilityVision)
+		}
+		if m.Capabilities.TrainedForToolUse {
+			capabilities = append(capabilities, types.CapabilityFunctions)
+		}
+		if m.Type == "embedding" {
+			capabilities = []types.ModelCapability{types.CapabilityEmbeddings}
+		}
+
+		name := m.DisplayName
+		if name == "" {
+			name = formatModelName(m.Key)
+		}
+		contextLength := m.MaxContextLength
+		loaded := len(m.LoadedInstances) > 0
+		if loaded && m.LoadedInstances[0].Config.ContextLength > 0 {
+			contextLength = m.LoadedInstances[0].Config.ContextLength
+		}
+
+		models = append(models, &types.ModelInfo{
+			ID:            m.Key,
+			Name:          name,
+			Provider:      "lmstudio",
+			OwnedBy:       m.Publisher,
+			ContextLength: contextLength,
+			MaxTokens:     contextLength,
+			Capabilities:  capabilities,
+			Constraints: map[string]any{
+				"loaded":             loaded,
+				"max_context_length": m.MaxContextLength,
+			},
+		})
+	}
+
+	return models, nil
+}
@@ -9,6 +9,7 @@ import (
 
 // OllamaFetcher fetches locally available models from Ollama
 type OllamaFetcher struct {
+	userAgent
 	baseURL string
 }
 
@@ -29,7 +30,7 @@ func (f *OllamaFetcher) Name() string {
 
 // FetchModels retrieves all locally available models from Ollama
 func (f *OllamaFetcher) FetchModels(ctx context.Context) ([]*types.ModelInfo, error) {
-	req, err := newGetRequest(ctx, f.baseURL+"/api/tags")
+	req, err := f.newGetRequest(ctx, f.baseURL+"/api/tags")
 	if err != nil {
 		return nil, err
 	}
@@ -10,6 +10,7 @@ import (
 
 // OpenAIFetcher fetches models from OpenAI API
 type OpenAIFetcher struct {
+	userAgent
 	apiKey  string
 	baseURL string
 }
@@ -39,7 +40,7 @@ func (f *OpenAIFetcher) FetchModels(ctx context.Context) ([]*types.ModelInfo, er
 		return nil, fmt.Errorf("OpenAI API key not configured")
 	}
 
-	req, err := newGetRequest(ctx, f.baseURL+"/models")
+	req, err := f.newGetRequest(ctx, f.baseURL+"/models")
 	if err != nil {
 		return nil, err
 	}
@@ -12,6 +12,7 @@ import (
 // OpenAICompatibleFetcher fetches models from providers that expose the
 // OpenAI-compatible GET /models shape.
 type OpenAICompatibleFetcher struct {
+	userAgent
 	name    string
 	baseURL string
 	apiKey  string
@@ -50,7 +51,7 @@ func (f *OpenAICompatibleFetcher) FetchModels(ctx context.Context) ([]*types.Mod
 		return nil, fmt.Errorf("%s base URL not configured", f.name)
 	}
 
-	req, err := newGetRequest(ctx, f.baseURL+"/models")
+	req, err := f.newGetRequest(ctx, f.baseURL+"/models")
 	if err != nil {
 		return nil, err
 	}
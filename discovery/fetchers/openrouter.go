@@ -12,6 +12,7 @@ const providerOpenRouter = "openrouter"
 
 // OpenRouterFetcher fetches models from OpenRouter API
 type OpenRouterFetcher struct {
+	userAgent
 	baseURL string
 }
 
@@ -29,7 +30,7 @@ func (f *OpenRouterFetcher) Name() string {
 
 // FetchModels retrieves all available models from OpenRouter
 func (f *OpenRouterFetcher) FetchModels(ctx context.Context) ([]*types.ModelInfo, error) {
-	req, err := newGetRequest(ctx, f.baseURL+"/models")
+	req, err := f.newGetRequest(ctx, f.baseURL+"/models")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,250 @@
+package wormhole
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ConfigSnapshot is the sanitized runtime configuration returned by
+// EffectiveConfig. API keys and credential-looking headers and params are
+// masked, so the JSON form can be pasted into a bug report.
+type ConfigSnapshot struct {
+	DefaultProvider   string                      `json:"default_provider,omitempty"`
+	DefaultTimeout    string                      `json:"default_timeout,omitempty"`
+	DefaultRetries    *int                        `json:"default_retries,omitempty"`
+	DefaultRetryDelay string                      `json:"default_retry_delay,omitempty"`
+	UserAgent         string                      `json:"user_agent"`
+	ModelValidation   bool                        `json:"model_validation"`
+	Discovery         bool                        `json:"discovery"`
+	DebugLogging      bool                        `json:"debug_logging,omitempty"`
+	StreamIdleTimeout string                      `json:"stream_idle_timeout,omitempty"`
+	IdempotencyTTL    string                      `json:"idempotency_ttl,omitempty"`
+	Providers         map[string]ProviderSnapshot `json:"providers"`
+	// Middleware lists the provider middleware outermost first.
+	Middleware         []string          `json:"middleware,omitempty"`
+	ModelAliases       map[string]string `json:"model_aliases,omitempty"`
+	DefaultModels      map[string]string `json:"default_models,omitempty"`
+	ModelEquivalents   []string          `json:"model_equivalents,omitempty"`
+	RegisteredModels   []string          `json:"registered_models,omitempty"`
+	ResponseValidators []string          `json:"response_validators,omitempty"`
+	Tools              []string          `json:"tools,omitempty"`
+	Hooks              map[string]bool   `json:"hooks,omitempty"`
+}
+
+// ProviderSnapshot is one configured provider in a ConfigSnapshot.
+type ProviderSnapshot struct {
+	// Profile is the built-in profile the provider uses, if any; Kind is
+	// that profile's adapter kind, or "custom" for providers registered
+	// under a name without a profile.
+	Profile     string               `json:"profile,omitempty"`
+	Kind        string               `json:"kind,omitempty"`
+	HTTPTimeout string               `json:"http_timeout,omitempty"`
+	Config      types.ProviderConfig `json:"config"`
+}
+
+// EffectiveConfig returns a sanitized snapshot of the client's configuration
+// after all options were applied: providers with their profiles, middleware
+// order, defaults, and model registry overrides. Secrets are masked.
+//
+// Example:
+//
+//	snapshot, _ := json.MarshalIndent(client.EffectiveConfig(), "", "  ")
+//	fmt.Println(string(snapshot))
+func (p *Wormhole) EffectiveConfig() ConfigSnapshot {
+	cfg := p.config
+	snapshot := ConfigSnapshot{
+		DefaultProvider: cfg.DefaultProvider,
+		UserAgent:       cfg.UserAgent,
+		ModelValidation: cfg.ModelValidation,
+		Discovery:       cfg.EnableDiscovery,
+		DebugLogging:    cfg.DebugLogging,
+		Providers:       make(map[string]ProviderSnapshot, len(cfg.Providers)),
+		ModelAliases:    cloneStringMap(cfg.ModelAliases),
+		DefaultModels:   cloneStringMap(cfg.DefaultModels),
+		Middleware:      middlewareNames(cfg),
+		Tools:           toolNames(p.ListTools()),
+	}
+	if cfg.DisableUserAgent {
+		snapshot.UserAgent = "(disabled)"
+	} else if snapshot.UserAgent == "" {
+		snapshot.UserAgent = "(default)"
+	}
+	if cfg.DefaultTimeoutSet {
+		snapshot.DefaultTimeout = cfg.DefaultTimeout.String()
+	}
+	if cfg.DefaultRetriesSet {
+		retries := cfg.DefaultRetries
+		snapshot.DefaultRetries = &retries
+	}
+	if cfg.DefaultRetryDelaySet {
+		snapshot.DefaultRetryDelay = cfg.DefaultRetryDelay.String()
+	}
+	if cfg.StreamIdleTimeout > 0 {
+		snapshot.StreamIdleTimeout = cfg.StreamIdleTimeout.String()
+	}
+	if cfg.Idempotency != nil {
+		snapshot.IdempotencyTTL = cfg.Idempotency.TTL.String()
+	}
+
+	for name, providerConfig := range cfg.Providers {
+		provider := ProviderSnapshot{Config: sanitizeProviderConfig(providerConfig)}
+		if profile, ok := providerProfile(name); ok {
+			provider.Profile = profile.Name
+			provider.Kind = profile.Kind
+		} else if _, ok := cfg.CustomFactories[name]; ok {
+			provider.Kind = "custom"
+		}
+		if providerConfig.HTTPTimeout != nil {
+			provider.HTTPTimeout = providerConfig.HTTPTimeout.String()
+		}
+		snapshot.Providers[name] = provider
+	}
+
+	for _, class := range cfg.ModelEquivalents {
+		snapshot.ModelEquivalents = append(snapshot.ModelEquivalents, class.Name)
+	}
+	for _, model := range cfg.Models {
+		if model != nil {
+			snapshot.RegisteredModels = append(snapshot.RegisteredModels, model.Provider+"/"+model.ID)
+		}
+	}
+	sort.Strings(snapshot.RegisteredModels)
+	for name := range cfg.ResponseValidators {
+		snapshot.ResponseValidators = append(snapshot.ResponseValidators, name)
+	}
+	sort.Strings(snapshot.ResponseValidators)
+
+	hooks := map[string]bool{
+		"attempt_trace": cfg.AttemptTrace != nil,
+		"stream_trace":  cfg.StreamTrace != nil,
+		"logger":        cfg.Logger != nil,
+	}
+	for name, set := range hooks {
+		if !set {
+			delete(hooks, name)
+		}
+	}
+	if len(hooks) > 0 {
+		snapshot.Hooks = hooks
+	}
+	return snapshot
+}
+
+// String returns the snapshot as indented JSON.
+func (s ConfigSnapshot) String() string {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("config snapshot: %v", err)
+	}
+	return string(data)
+}
+
+// maskedValue replaces a secret, keeping the last four characters of long
+// values so two keys can still be told apart.
+func maskedValue(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) < 12 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
+
+// isSecretName reports whether a header or param name looks like it holds a
+// credential.
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range []string{"key", "token", "secret", "auth", "password", "cookie"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func sanitizeProviderConfig(config types.ProviderConfig) types.ProviderConfig {
+	sanitized := cloneProviderConfig(config)
+	sanitized.APIKey = maskedValue(config.APIKey)
+	for i, key := range sanitized.APIKeys {
+		sanitized.APIKeys[i] = maskedValue(key)
+	}
+	for name, value := range sanitized.Headers {
+		if isSecretName(name) {
+			sanitized.Headers[name] = maskedValue(value)
+		}
+	}
+	for name := range sanitized.Params {
+		if isSecretName(name) {
+			sanitized.Params[name] = "****"
+		}
+	}
+	return sanitized
+}
+
+// middlewareNames names the configured provider middleware in order. Legacy
+// middleware functions are named after the constructor that built them.
+func middlewareNames(cfg Config) []string {
+	names := make([]string, 0, len(cfg.ProviderMiddlewares)+1)
+	if cfg.DebugLogging && cfg.Logger != nil {
+		names = append(names, "middleware.TypedLoggingMiddleware (debug)")
+	}
+	legacy := 0
+	for _, mw := range cfg.ProviderMiddlewares {
+		if _, ok := mw.(*middleware.LegacyAdapter); ok && legacy < len(cfg.Middleware) {
+			names = append(names, funcName(cfg.Middleware[legacy]))
+			legacy++
+			continue
+		}
+		names = append(names, strings.TrimPrefix(fmt.Sprintf("%T", mw), "*"))
+	}
+	return names
+}
+
+// funcName returns "package.Func" for fn, dropping the import path and any
+// closure suffixes.
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	name := f.Name()
+	if slash := strings.LastIndexByte(name, '/'); slash >= 0 {
+		name = name[slash+1:]
+	}
+	for {
+		dot := strings.LastIndexByte(name, '.')
+		if dot < 0 || !strings.HasPrefix(name[dot+1:], "func") {
+			break
+		}
+		name = name[:dot]
+	}
+	return name
+}
+
+func toolNames(tools []types.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func cloneStringMap(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	cloned := make(map[string]string, len(m))
+	for k, v := range m {
+		cloned[k] = v
+	}
+	return cloned
+}
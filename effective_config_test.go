@@ -0,0 +1,67 @@
+package wormhole
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestEffectiveConfigMasksSecrets(t *testing.T) {
+	t.Parallel()
+
+	client := New(
+		WithOpenAI("sk-proj-abcdefghijklmnop1234"),
+		WithAnthropic("short"),
+		WithOpenAICompatible("gateway", "https://llm.internal/v1", types.ProviderConfig{
+			APIKey:  "gateway-secret-9999",
+			Headers: map[string]string{"X-Api-Key": "header-secret-5678", "X-Team": "search"},
+			Params:  map[string]any{"access_token": "tok", "region": "eu"},
+		}),
+		WithDefaultProvider("openai"),
+		WithTimeout(45*time.Second),
+		WithRetries(2, 100*time.Millisecond),
+		WithMiddleware(middleware.TimeoutMiddleware(time.Second)),
+		WithModelAliases(map[string]string{"fast": "gpt-5-nano"}),
+		WithDefaultModel("openai", "fast"),
+		WithDiscovery(false),
+	)
+	defer client.Close()
+
+	snapshot := client.EffectiveConfig()
+	assert.Equal(t, "openai", snapshot.DefaultProvider)
+	assert.Equal(t, "45s", snapshot.DefaultTimeout)
+	require.NotNil(t, snapshot.DefaultRetries)
+	assert.Equal(t, 2, *snapshot.DefaultRetries)
+	assert.Equal(t, []string{"middleware.TimeoutMiddleware"}, snapshot.Middleware)
+	assert.Equal(t, map[string]string{"fast": "gpt-5-nano"}, snapshot.ModelAliases)
+	assert.Equal(t, "fast", snapshot.DefaultModels["openai"])
+
+	openai := snapshot.Providers["openai"]
+	assert.Equal(t, "openai", openai.Profile)
+	assert.Equal(t, "****1234", openai.Config.APIKey)
+	assert.Equal(t, "****", snapshot.Providers["anthropic"].Config.APIKey)
+
+	gateway := snapshot.Providers["gateway"]
+	assert.Equal(t, "custom", gateway.Kind)
+	assert.Equal(t, "****9999", gateway.Config.APIKey)
+	assert.Equal(t, "****5678", gateway.Config.Headers["X-Api-Key"])
+	assert.Equal(t, "search", gateway.Config.Headers["X-Team"])
+	assert.Equal(t, "****", gateway.Config.Params["access_token"])
+	assert.Equal(t, "eu", gateway.Config.Params["region"])
+
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	for _, secret := range []string{"abcdefghijklmnop", "gateway-secret", "header-secret", "short"} {
+		assert.NotContains(t, string(data), secret)
+	}
+	assert.Contains(t, snapshot.String(), `"default_provider": "openai"`)
+
+	// The snapshot is a copy: the live configuration still holds the keys.
+	assert.Equal(t, "sk-proj-abcdefghijklmnop1234", client.config.Providers["openai"].APIKey)
+}
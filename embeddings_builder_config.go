@@ -1,6 +1,9 @@
 package wormhole
 
 import (
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
@@ -12,9 +15,27 @@ import (
 // Do NOT reuse the same builder instance across multiple goroutines.
 type EmbeddingsRequestBuilder struct {
 	CommonBuilder
-	request *types.EmbeddingsRequest
+	request   *types.EmbeddingsRequest
+	autoBatch *embeddingsAutoBatch
+}
+
+// embeddingsAutoBatch holds the AutoBatch limits; zero values mean defaults.
+type embeddingsAutoBatch struct {
+	maxPerRequest  int
+	maxConcurrency int
 }
 
+const defaultEmbeddingsAutoBatchConcurrency = 4
+
+// defaultEmbeddingsBatchSizes are the per-request input limits documented by
+// each provider. Unknown providers use defaultEmbeddingsBatchSize.
+var defaultEmbeddingsBatchSizes = map[string]int{
+	"openai": 2048,
+	"gemini": 100,
+}
+
+const defaultEmbeddingsBatchSize = 100
+
 // Using sets the provider to use
 func (b *EmbeddingsRequestBuilder) Using(provider string) *EmbeddingsRequestBuilder {
 	b.setProvider(provider)
@@ -62,12 +83,99 @@ func (b *EmbeddingsRequestBuilder) EncodingFormat(format types.EmbeddingEncoding
 	return b
 }
 
+// AutoBatch makes Generate split inputs larger than maxPerRequest into
+// sub-requests, run up to maxConcurrency of them at once, and reassemble the
+// vectors in input order with usage summed. maxPerRequest <= 0 uses the
+// provider's documented limit (2048 for OpenAI, 100 for Gemini and others);
+// maxConcurrency <= 0 uses 4.
+//
+// Example:
+//
+//	resp, err := client.Embeddings().
+//	    Model("text-embedding-3-small").
+//	    Input(documents...).
+//	    AutoBatch(0, 8).
+//	    Generate(ctx)
+func (b *EmbeddingsRequestBuilder) AutoBatch(maxPerRequest, maxConcurrency int) *EmbeddingsRequestBuilder {
+	b.autoBatch = &embeddingsAutoBatch{maxPerRequest: maxPerRequest, maxConcurrency: maxConcurrency}
+	return b
+}
+
+// autoBatchLimits resolves the AutoBatch batch size and concurrency.
+func (b *EmbeddingsRequestBuilder) autoBatchLimits() (int, int) {
+	batchSize := b.autoBatch.maxPerRequest
+	if batchSize <= 0 {
+		batchSize = defaultEmbeddingsBatchSize
+		if providerName, err := b.getWormhole().resolveProviderName(b.getProvider()); err == nil {
+			if size, ok := defaultEmbeddingsBatchSizes[providerName]; ok {
+				batchSize = size
+			}
+		}
+	}
+	concurrency := b.autoBatch.maxConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultEmbeddingsAutoBatchConcurrency
+	}
+	return batchSize, concurrency
+}
+
 // ProviderOptions sets provider-specific options
 func (b *EmbeddingsRequestBuilder) ProviderOptions(options map[string]any) *EmbeddingsRequestBuilder {
 	b.request.ProviderOptions = options
 	return b
 }
 
+// ProviderOption sets one provider-specific option sent only to provider, as
+// TextRequestBuilder.ProviderOption does.
+func (b *EmbeddingsRequestBuilder) ProviderOption(provider, key string, value any) *EmbeddingsRequestBuilder {
+	b.setProviderOption(provider, key, value)
+	return b
+}
+
+// Metadata tags the request for metrics and logging, as
+// TextRequestBuilder.Metadata does.
+func (b *EmbeddingsRequestBuilder) Metadata(tags map[string]string) *EmbeddingsRequestBuilder {
+	b.addTags(tags)
+	return b
+}
+
+// Priority sets the request's scheduling priority, as
+// TextRequestBuilder.Priority does.
+func (b *EmbeddingsRequestBuilder) Priority(p Priority) *EmbeddingsRequestBuilder {
+	b.setPriority(p)
+	return b
+}
+
+// Cache overrides CacheMiddleware's TTL for this request, as
+// TextRequestBuilder.Cache does.
+func (b *EmbeddingsRequestBuilder) Cache(ttl time.Duration) *EmbeddingsRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{TTL: ttl})
+	return b
+}
+
+// NoCache sends the request past the cache middleware, as
+// TextRequestBuilder.NoCache does.
+func (b *EmbeddingsRequestBuilder) NoCache() *EmbeddingsRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{Bypass: true})
+	return b
+}
+
+// CacheKey returns the key CacheMiddleware uses for this request, as
+// TextRequestBuilder.CacheKey does. With AutoBatch each sub-batch has its
+// own key; this is the key of the unsplit request.
+func (b *EmbeddingsRequestBuilder) CacheKey() (string, error) {
+	if b.request == nil {
+		return "", types.NewValidationError("request", "already_used", nil, "builder already used; create a new builder for each request")
+	}
+	request := cloneEmbeddingsRequest(b.request)
+	request.Model = b.resolveModel(request.Model)
+	return b.cacheKey(func(provider types.Provider) any {
+		scoped := *request
+		scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
+		return scoped
+	})
+}
+
 // Clone creates a deep copy of the builder with all settings preserved.
 // This allows you to create variations from a base configuration.
 //
@@ -79,14 +187,23 @@ func (b *EmbeddingsRequestBuilder) ProviderOptions(options map[string]any) *Embe
 func (b *EmbeddingsRequestBuilder) Clone() *EmbeddingsRequestBuilder {
 	clonedRequest := cloneEmbeddingsRequest(b.request)
 
-	return &EmbeddingsRequestBuilder{
+	cloned := &EmbeddingsRequestBuilder{
 		CommonBuilder: CommonBuilder{
-			wormhole: b.wormhole,
-			provider: b.provider,
-			baseURL:  b.baseURL,
+			wormhole:      b.wormhole,
+			provider:      b.provider,
+			baseURL:       b.baseURL,
+			tags:          b.tags,
+			priority:      b.priority,
+			cache:         b.cache,
+			scopedOptions: b.scopedOptions,
 		},
 		request: clonedRequest,
 	}
+	if b.autoBatch != nil {
+		autoBatch := *b.autoBatch
+		cloned.autoBatch = &autoBatch
+	}
+	return cloned
 }
 
 // Validate checks the request configuration for errors before calling Generate().
@@ -2,7 +2,9 @@ package wormhole
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/garyblankenship/wormhole/v2/types"
@@ -10,6 +12,7 @@ import (
 
 // Generate executes the request and returns embeddings
 func (b *EmbeddingsRequestBuilder) Generate(ctx context.Context) (*types.EmbeddingsResponse, error) {
+	ctx = b.taggedContext(ctx)
 	if b.request == nil {
 		return nil, types.NewValidationError("request", "already_used", nil, "builder already used; create a new builder for each request")
 	}
@@ -20,6 +23,7 @@ func (b *EmbeddingsRequestBuilder) Generate(ctx context.Context) (*types.Embeddi
 	}()
 
 	request := cloneEmbeddingsRequest(b.request)
+	request.Model = b.resolveModel(request.Model)
 
 	// Validate request
 	if len(request.Input) == 0 {
@@ -36,6 +40,12 @@ func (b *EmbeddingsRequestBuilder) Generate(ctx context.Context) (*types.Embeddi
 	}
 
 	response, err := executeTrackedRequest(ctx, b.getWormhole(), b.idempotencyScope("embeddings.generate"), request, func(ctx context.Context) (*types.EmbeddingsResponse, error) {
+		if b.autoBatch != nil {
+			batchSize, concurrency := b.autoBatchLimits()
+			if len(request.Input) > batchSize {
+				return b.executeEmbeddingBatches(ctx, request, batchSize, concurrency)
+			}
+		}
 		return b.executeEmbeddings(ctx, request)
 	})
 	if err != nil {
@@ -49,6 +59,7 @@ func (b *EmbeddingsRequestBuilder) Generate(ctx context.Context) (*types.Embeddi
 // response must contain exactly one embedding per input and every embedding
 // Index must refer to an item in that sub-batch.
 func (b *EmbeddingsRequestBuilder) GenerateBatched(ctx context.Context, batchSize int) (*types.EmbeddingsResponse, error) {
+	ctx = b.taggedContext(ctx)
 	if b.request == nil {
 		return nil, types.NewValidationError("request", "already_used", nil, "builder already used; create a new builder for each request")
 	}
@@ -59,6 +70,7 @@ func (b *EmbeddingsRequestBuilder) GenerateBatched(ctx context.Context, batchSiz
 	}()
 
 	request := cloneEmbeddingsRequest(b.request)
+	request.Model = b.resolveModel(request.Model)
 	if len(request.Input) == 0 {
 		return nil, types.NewValidationError("input", "required", nil, "no input provided")
 	}
@@ -76,45 +88,99 @@ func (b *EmbeddingsRequestBuilder) GenerateBatched(ctx context.Context, batchSiz
 	}
 
 	response, err := executeTrackedRequest(ctx, b.getWormhole(), b.idempotencyScope("embeddings.generate_batched"), request, func(ctx context.Context) (*types.EmbeddingsResponse, error) {
-		out := make([]types.Embedding, len(request.Input))
-		var combined *types.EmbeddingsResponse
-		var usage *types.Usage
-
-		for start := 0; start < len(request.Input); start += batchSize {
-			end := start + batchSize
-			if end > len(request.Input) {
-				end = len(request.Input)
-			}
+		return b.executeEmbeddingBatches(ctx, request, batchSize, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return encodeEmbeddingsResponse(response, request.EncodingFormat), nil
+}
+
+// executeEmbeddingBatches splits request.Input into batches of batchSize, runs
+// up to concurrency batches at once, and reassembles vectors in caller order
+// with usage summed across batches. The first failing batch cancels the rest.
+func (b *EmbeddingsRequestBuilder) executeEmbeddingBatches(ctx context.Context, request *types.EmbeddingsRequest, batchSize, concurrency int) (*types.EmbeddingsResponse, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	batchCount := (len(request.Input) + batchSize - 1) / batchSize
+	responses := make([]*types.EmbeddingsResponse, batchCount)
+	errs := make([]error, batchCount)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for batch := 0; batch < batchCount; batch++ {
+		start := batch * batchSize
+		end := min(start+batchSize, len(request.Input))
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			errs[batch] = err
+			break
+		}
+
+		wg.Add(1)
+		go func(batch, start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
 			batchRequest := cloneEmbeddingsRequestMetadata(request)
 			batchRequest.Input = append([]string(nil), request.Input[start:end]...)
 
 			resp, err := b.executeEmbeddings(ctx, batchRequest)
-			if err != nil {
-				return nil, fmt.Errorf("embeddings batch [%d:%d]: %w", start, end, err)
+			if err == nil && resp == nil {
+				err = fmt.Errorf("provider returned nil response")
 			}
-			if resp == nil {
-				return nil, fmt.Errorf("embeddings batch [%d:%d]: provider returned nil response", start, end)
-			}
-			if combined == nil {
-				combined = cloneEmbeddingsResponseHeader(resp)
+			if err != nil {
+				errs[batch] = fmt.Errorf("embeddings batch [%d:%d]: %w", start, end, err)
+				cancel()
+				return
 			}
-			usage = mergeUsage(usage, resp.Usage)
+			responses[batch] = resp
+		}(batch, start, end)
+	}
+	wg.Wait()
 
-			if err := placeEmbeddingBatch(out, start, end-start, resp.Embeddings); err != nil {
-				return nil, fmt.Errorf("embeddings batch [%d:%d]: %w", start, end, err)
-			}
+	// Report the earliest provider failure rather than a cancellation it caused.
+	var firstErr error
+	for _, err := range errs {
+		if err == nil {
+			continue
 		}
+		if firstErr == nil || (errors.Is(firstErr, context.Canceled) && !errors.Is(err, context.Canceled)) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
 
+	out := make([]types.Embedding, len(request.Input))
+	var combined *types.EmbeddingsResponse
+	var usage *types.Usage
+	for batch, resp := range responses {
+		start := batch * batchSize
+		end := min(start+batchSize, len(request.Input))
 		if combined == nil {
-			combined = &types.EmbeddingsResponse{Model: request.Model, Created: time.Now()}
+			combined = cloneEmbeddingsResponseHeader(resp)
+		}
+		usage = mergeUsage(usage, resp.Usage)
+		if err := placeEmbeddingBatch(out, start, end-start, resp.Embeddings); err != nil {
+			return nil, fmt.Errorf("embeddings batch [%d:%d]: %w", start, end, err)
 		}
-		combined.Model = request.Model
-		combined.Embeddings = out
-		combined.Usage = usage
-		return combined, nil
-	})
-	if err != nil {
-		return nil, err
 	}
-	return encodeEmbeddingsResponse(response, request.EncodingFormat), nil
+
+	if combined == nil {
+		combined = &types.EmbeddingsResponse{Model: request.Model, Created: time.Now()}
+	}
+	combined.Model = request.Model
+	combined.Embeddings = out
+	combined.Usage = usage
+	return combined, nil
 }
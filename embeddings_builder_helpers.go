@@ -40,12 +40,14 @@ func (b *EmbeddingsRequestBuilder) executeEmbeddings(ctx context.Context, reques
 	defer release()
 
 	ctx = contextWithProviderOperation(ctx, provider, "embeddings")
+	scoped := *request
+	scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
 	if b.getWormhole().providerMiddleware != nil {
 		handler := b.getWormhole().providerMiddleware.ApplyEmbeddings(provider.Embeddings)
-		return handler(ctx, *request)
+		return handler(ctx, scoped)
 	}
 
-	return provider.Embeddings(ctx, *request)
+	return provider.Embeddings(ctx, scoped)
 }
 
 func placeEmbeddingBatch(out []types.Embedding, start, count int, embeddings []types.Embedding) error {
@@ -406,6 +406,90 @@ func TestEmbeddingsRequestBuilderGenerateBatched(t *testing.T) {
 	})
 }
 
+func TestEmbeddingsRequestBuilderAutoBatch(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(provider types.Provider) *Wormhole {
+		return New(
+			WithCustomProvider("batch", func(types.ProviderConfig) (types.Provider, error) {
+				return provider, nil
+			}),
+			WithDefaultProvider("batch"),
+		)
+	}
+
+	t.Run("splits concurrently and reassembles in order", func(t *testing.T) {
+		t.Parallel()
+		provider := &batchedEmbeddingProvider{}
+		inputs := make([]string, 7)
+		for i := range inputs {
+			inputs[i] = "input-" + strconv.Itoa(i)
+		}
+
+		resp, err := newClient(provider).Embeddings().
+			Model("embed-test").
+			Input(inputs...).
+			AutoBatch(3, 2).
+			Generate(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, resp.Embeddings, 7)
+		assert.Len(t, provider.calls, 3)
+		assert.ElementsMatch(t, [][]string{inputs[0:3], inputs[3:6], inputs[6:7]}, provider.calls)
+		require.NotNil(t, resp.Usage)
+		assert.Equal(t, 7, resp.Usage.PromptTokens)
+		for i, embedding := range resp.Embeddings {
+			assert.Equal(t, i, embedding.Index)
+			assert.Equal(t, float64(i), embedding.Embedding[0])
+		}
+	})
+
+	t.Run("small inputs use a single request", func(t *testing.T) {
+		t.Parallel()
+		provider := &batchedEmbeddingProvider{}
+
+		resp, err := newClient(provider).Embeddings().
+			Model("embed-test").
+			Input("input-0", "input-1").
+			AutoBatch(0, 0).
+			Generate(context.Background())
+
+		require.NoError(t, err)
+		assert.Len(t, resp.Embeddings, 2)
+		assert.Len(t, provider.calls, 1)
+	})
+
+	t.Run("reports the failing batch", func(t *testing.T) {
+		t.Parallel()
+		provider := &batchedEmbeddingProvider{duplicateIndex: true}
+
+		_, err := newClient(provider).Embeddings().
+			Model("embed-test").
+			Input("input-0", "input-1", "input-2", "input-3").
+			AutoBatch(2, 2).
+			Generate(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate response index")
+	})
+
+	t.Run("clone keeps limits", func(t *testing.T) {
+		t.Parallel()
+		builder := New().Embeddings().AutoBatch(5, 3)
+		batchSize, concurrency := builder.Clone().autoBatchLimits()
+		assert.Equal(t, 5, batchSize)
+		assert.Equal(t, 3, concurrency)
+	})
+
+	t.Run("defaults follow the provider", func(t *testing.T) {
+		t.Parallel()
+		client := New(WithOpenAI("sk-test"), WithDefaultProvider("openai"))
+		batchSize, concurrency := client.Embeddings().AutoBatch(0, 0).autoBatchLimits()
+		assert.Equal(t, 2048, batchSize)
+		assert.Equal(t, defaultEmbeddingsAutoBatchConcurrency, concurrency)
+	})
+}
+
 type batchedEmbeddingProvider struct {
 	*types.BaseProvider
 	mu             sync.Mutex
@@ -3,7 +3,6 @@ package wormhole
 import (
 	"context"
 	"fmt"
-	"math"
 	"os"
 	"strings"
 	"sync"
@@ -230,10 +229,10 @@ func testOpenAIEmbeddings(t *testing.T, client *Wormhole, ctx context.Context) {
 		require.Len(t, resp.Embeddings, 3)
 
 		// Calculate cosine similarity between similar texts
-		similarityBetweenSimilar := cosineSimilarity(resp.Embeddings[0].Embedding, resp.Embeddings[1].Embedding)
+		similarityBetweenSimilar := types.CosineSimilarity(resp.Embeddings[0].Embedding, resp.Embeddings[1].Embedding)
 
 		// Calculate cosine similarity between first similar text and dissimilar text
-		similarityToDissimilar := cosineSimilarity(resp.Embeddings[0].Embedding, resp.Embeddings[2].Embedding)
+		similarityToDissimilar := types.CosineSimilarity(resp.Embeddings[0].Embedding, resp.Embeddings[2].Embedding)
 
 		// Similar texts should have higher similarity than dissimilar texts
 		assert.Greater(t, similarityBetweenSimilar, similarityToDissimilar)
@@ -615,23 +614,3 @@ func TestEmbeddingsMiddleware(t *testing.T) {
 
 	assert.True(t, middlewareCalled, "Middleware was not called")
 }
-
-// cosineSimilarity calculates the cosine similarity between two vectors
-func cosineSimilarity(a, b []float64) float64 {
-	if len(a) != len(b) {
-		return 0
-	}
-
-	var dotProduct, normA, normB float64
-	for i := range a {
-		dotProduct += a[i] * b[i]
-		normA += a[i] * a[i]
-		normB += b[i] * b[i]
-	}
-
-	if normA == 0 || normB == 0 {
-		return 0
-	}
-
-	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
-}
@@ -0,0 +1,38 @@
+package wormhole_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestEmptyResponseMiddlewareCountsPerProvider(t *testing.T) {
+	t.Parallel()
+
+	counter := middleware.NewEmptyResponseCounter()
+	provider := mocktesting.NewMockProvider("mock").WithTextResponse(types.TextResponse{Text: "\n"})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+		wormhole.WithMiddleware(middleware.EmptyResponseMiddleware(middleware.EmptyResponseConfig{
+			Retries: 1,
+			Counter: counter,
+		})),
+	)
+	defer client.Close()
+
+	_, err := client.Text().Model("m").Prompt("hi").Generate(context.Background())
+	require.Error(t, err)
+	assert.True(t, types.IsEmptyResponseError(err))
+	assert.Equal(t, []middleware.EmptyResponseStats{{Provider: "mock", Model: "m", Empty: 2, Failed: 1}}, counter.Stats())
+}
@@ -30,6 +30,7 @@ type EnhancedAdaptiveLimiter struct {
 
 	// Statistics
 	totalAdjustments int64
+	shedding         sheddingStats
 }
 
 // NewEnhancedAdaptiveLimiter creates a new provider-aware adaptive limiter
@@ -147,15 +148,26 @@ func (l *EnhancedAdaptiveLimiter) ReleaseWithProvider(provider, model string) {
 // AcquireToken acquires a slot from the global limiter and returns a release function.
 // The release function captures the specific limiter instance, preventing race conditions
 // if capacity adjustment swaps the limiter between acquire and release.
+//
+// With EnhancedAdaptiveConfig.Shedding enabled, low-priority requests may be
+// shed (ok is false while ctx is still live) or deferred while degraded.
 func (l *EnhancedAdaptiveLimiter) AcquireToken(ctx context.Context) (release func(), ok bool) {
+	if !l.admit(ctx, l.globalState) {
+		return nil, false
+	}
 	return l.globalState.AcquireToken(ctx)
 }
 
 // AcquireTokenWithProvider acquires a slot with provider/model awareness and returns
 // a release function. The release function captures the specific limiter instance,
 // preventing race conditions if capacity adjustment swaps the limiter.
+// The shedding policy applies as for AcquireToken, judged per provider/model.
 func (l *EnhancedAdaptiveLimiter) AcquireTokenWithProvider(ctx context.Context, provider, model string) (release func(), ok bool) {
 	state := l.pinState(provider, model)
+	if !l.admit(ctx, state) {
+		l.unpinState(state)
+		return nil, false
+	}
 	releaseToken, ok := state.AcquireToken(ctx)
 	if !ok {
 		l.unpinState(state)
@@ -63,5 +63,11 @@ func (l *EnhancedAdaptiveLimiter) GetStats() map[string]interface{} {
 		stats["models"] = modelStats
 	}
 
+	if l.config.Shedding.Enabled {
+		shed, deferred := l.shedding.snapshot()
+		stats["shed"] = priorityCountsByName(shed)
+		stats["deferred"] = priorityCountsByName(deferred)
+	}
+
 	return stats
 }
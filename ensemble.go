@@ -0,0 +1,302 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ErrNoEnsembleCandidates reports an ensemble in which every member failed.
+var ErrNoEnsembleCandidates = errors.New("ensemble produced no successful candidates")
+
+// EnsembleBuilder sends one prompt to several models at once and picks a
+// single answer from the replies with an EnsembleStrategy. Requests fan out
+// through a Batch, so concurrency limits and adaptive limiting apply.
+//
+// Example:
+//
+//	result, err := client.Ensemble(client.Text().Prompt("Is 1009 prime? Answer yes or no.")).
+//	    Member("openai", "gpt-5-mini").
+//	    Member("anthropic", "claude-haiku-4-5").
+//	    Member("gemini", "gemini-2.5-flash").
+//	    Strategy(wormhole.MajorityVote()).
+//	    Generate(ctx)
+//	fmt.Println(result.Chosen.Content())
+type EnsembleBuilder struct {
+	wormhole    *Wormhole
+	base        *TextRequestBuilder
+	members     []TextRoute
+	strategy    EnsembleStrategy
+	concurrency int
+}
+
+// EnsembleCandidate is one member's reply.
+type EnsembleCandidate struct {
+	Provider string
+	Model    string
+	Response *types.TextResponse // Response if successful
+	Error    error               // Error if failed
+}
+
+// EnsembleResult holds the chosen answer and every candidate, in member order.
+type EnsembleResult struct {
+	Chosen      *types.TextResponse
+	ChosenIndex int // Index of Chosen in Candidates
+	Candidates  []EnsembleCandidate
+}
+
+// EnsembleStrategy picks an answer. It receives only the successful
+// candidates and returns the index of the chosen one within that slice.
+type EnsembleStrategy func(ctx context.Context, candidates []EnsembleCandidate) (int, error)
+
+// Ensemble creates an ensemble around base, which carries the prompt and
+// shared parameters. Each member runs on a clone of base with its own
+// provider and model.
+func (p *Wormhole) Ensemble(base *TextRequestBuilder) *EnsembleBuilder {
+	return &EnsembleBuilder{
+		wormhole:    p,
+		base:        base,
+		strategy:    MajorityVote(),
+		concurrency: 10,
+	}
+}
+
+// Member adds a model to the ensemble. An empty provider keeps base's
+// provider.
+func (b *EnsembleBuilder) Member(provider, model string) *EnsembleBuilder {
+	b.members = append(b.members, TextRoute{Provider: provider, Model: model})
+	return b
+}
+
+// Models adds several models on base's provider.
+func (b *EnsembleBuilder) Models(models ...string) *EnsembleBuilder {
+	for _, model := range models {
+		b.Member("", model)
+	}
+	return b
+}
+
+// Strategy sets how the answer is picked. Default is MajorityVote.
+func (b *EnsembleBuilder) Strategy(strategy EnsembleStrategy) *EnsembleBuilder {
+	b.strategy = strategy
+	return b
+}
+
+// Concurrency sets the maximum number of members queried at once.
+// Default is 10.
+func (b *EnsembleBuilder) Concurrency(n int) *EnsembleBuilder {
+	b.concurrency = n
+	return b
+}
+
+// Generate queries every member and applies the strategy. The result is
+// returned alongside an error when the strategy fails, so callers can still
+// inspect the candidates.
+func (b *EnsembleBuilder) Generate(ctx context.Context) (*EnsembleResult, error) {
+	if b.base == nil {
+		return nil, types.ErrInvalidRequest.WithDetails("ensemble has no base request")
+	}
+	if len(b.members) == 0 {
+		return nil, types.ErrInvalidRequest.WithDetails("ensemble has no members")
+	}
+	if b.strategy == nil {
+		return nil, types.ErrInvalidRequest.WithDetails("ensemble has no strategy")
+	}
+
+	batch := b.wormhole.Batch().Concurrency(b.concurrency)
+	for _, member := range b.members {
+		request := b.base.Clone()
+		if member.Provider != "" {
+			request.Using(member.Provider)
+		}
+		if member.Model != "" {
+			request.Model(member.Model)
+		}
+		batch.Add(request)
+	}
+
+	result := &EnsembleResult{ChosenIndex: -1, Candidates: make([]EnsembleCandidate, len(b.members))}
+	var succeeded []EnsembleCandidate
+	var indexes []int
+	var errs []error
+	for i, r := range batch.Execute(ctx) {
+		member := b.members[i]
+		provider, model := member.Provider, member.Model
+		if provider == "" {
+			provider = b.base.provider
+		}
+		if model == "" {
+			model = b.base.request.Model
+		}
+		candidate := EnsembleCandidate{
+			Provider: provider,
+			Model:    model,
+			Response: r.Response,
+			Error:    r.Error,
+		}
+		result.Candidates[i] = candidate
+		if r.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", memberLabel(candidate), r.Error))
+			continue
+		}
+		succeeded = append(succeeded, candidate)
+		indexes = append(indexes, i)
+	}
+	if len(succeeded) == 0 {
+		return result, errors.Join(append([]error{ErrNoEnsembleCandidates}, errs...)...)
+	}
+
+	chosen, err := b.strategy(ctx, succeeded)
+	if err != nil {
+		return result, err
+	}
+	if chosen < 0 || chosen >= len(succeeded) {
+		return result, fmt.Errorf("ensemble strategy chose candidate %d of %d", chosen, len(succeeded))
+	}
+	result.ChosenIndex = indexes[chosen]
+	result.Chosen = succeeded[chosen].Response
+	return result, nil
+}
+
+func memberLabel(c EnsembleCandidate) string {
+	if c.Provider == "" {
+		return c.Model
+	}
+	return c.Provider + "/" + c.Model
+}
+
+// MajorityVote picks the most common answer. Answers that parse as JSON are
+// compared structurally, so key order and spacing do not split the vote, which
+// makes it the natural choice for structured output; other answers are
+// compared with case and whitespace folded. Ties go to the earliest member.
+func MajorityVote() EnsembleStrategy {
+	return func(_ context.Context, candidates []EnsembleCandidate) (int, error) {
+		votes := make(map[string]int, len(candidates))
+		first := make(map[string]int, len(candidates))
+		best, bestVotes := 0, 0
+		for i, c := range candidates {
+			key := voteKey(c.Response.Content())
+			votes[key]++
+			if _, seen := first[key]; !seen {
+				first[key] = i
+			}
+			if votes[key] > bestVotes || (votes[key] == bestVotes && first[key] < best) {
+				best, bestVotes = first[key], votes[key]
+			}
+		}
+		return best, nil
+	}
+}
+
+// voteKey normalizes an answer for MajorityVote.
+func voteKey(content string) string {
+	trimmed := strings.TrimSpace(content)
+	var value any
+	if err := json.Unmarshal([]byte(trimmed), &value); err == nil {
+		// encoding/json sorts map keys, giving a canonical form.
+		if canonical, err := json.Marshal(value); err == nil {
+			return string(canonical)
+		}
+	}
+	return strings.Join(strings.Fields(strings.ToLower(trimmed)), " ")
+}
+
+// LongestCommon picks the answer that agrees most with the others: the one
+// whose longest common word sequence with every other answer is largest in
+// total. It suits free-text answers that rarely match exactly.
+func LongestCommon() EnsembleStrategy {
+	return func(_ context.Context, candidates []EnsembleCandidate) (int, error) {
+		words := make([][]string, len(candidates))
+		for i, c := range candidates {
+			words[i] = strings.FieldsFunc(strings.ToLower(c.Response.Content()), func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+		}
+		best, bestScore := 0, -1.0
+		for i := range words {
+			score := 0.0
+			for j := range words {
+				if i != j {
+					score += overlap(words[i], words[j])
+				}
+			}
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		return best, nil
+	}
+}
+
+// overlap returns the longest common subsequence of a and b as a fraction of
+// their combined length, from 0 (nothing shared) to 1 (identical).
+func overlap(a, b []string) float64 {
+	if len(a)+len(b) == 0 {
+		return 1
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case a[i-1] == b[j-1]:
+				curr[j] = prev[j-1] + 1
+			case prev[j] >= curr[j-1]:
+				curr[j] = prev[j]
+			default:
+				curr[j] = curr[j-1]
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return 2 * float64(prev[len(b)]) / float64(len(a)+len(b))
+}
+
+// JudgeSelect asks a judge model to pick the best answer. judge carries the
+// judge's provider, model and instructions, which should restate the question
+// being answered; the numbered candidates are appended as a user message, and
+// the judge must reply with the number of its choice.
+func JudgeSelect(judge *TextRequestBuilder) EnsembleStrategy {
+	return func(ctx context.Context, candidates []EnsembleCandidate) (int, error) {
+		if judge == nil {
+			return 0, types.ErrInvalidRequest.WithDetails("ensemble judge request is nil")
+		}
+		var prompt strings.Builder
+		prompt.WriteString("Several assistants answered the same request. Reply with only the number of the best answer.\n")
+		for i, c := range candidates {
+			fmt.Fprintf(&prompt, "\n### Answer %d\n%s\n", i+1, c.Response.Content())
+		}
+
+		request := judge.Clone()
+		request.request.Messages = append(request.request.Messages, types.NewUserMessage(prompt.String()))
+		resp, err := request.Generate(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("ensemble judge: %w", err)
+		}
+		choice, ok := firstNumber(resp.Content())
+		if !ok || choice < 1 || choice > len(candidates) {
+			return 0, fmt.Errorf("ensemble judge reply %q names no answer between 1 and %d", resp.Content(), len(candidates))
+		}
+		return choice - 1, nil
+	}
+}
+
+// firstNumber returns the first run of digits in s.
+func firstNumber(s string) (int, bool) {
+	start := strings.IndexFunc(s, unicode.IsDigit)
+	if start < 0 {
+		return 0, false
+	}
+	end := start
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, err := strconv.Atoi(s[start:end])
+	return n, err == nil
+}
@@ -0,0 +1,110 @@
+package wormhole_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func newEnsembleClient(t *testing.T, rules ...mocktesting.ScenarioRule) *wormhole.Wormhole {
+	t.Helper()
+	provider := mocktesting.NewMockProvider("mock").WithScenario(mocktesting.Scenario{Rules: rules})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+	)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestEnsembleMajorityVoteComparesJSONStructurally(t *testing.T) {
+	t.Parallel()
+	client := newEnsembleClient(t,
+		mocktesting.ScenarioRule{Model: "a", Respond: `{"answer":"yes","confidence":0.9}`},
+		mocktesting.ScenarioRule{Model: "b", Respond: `{"answer":"no"}`},
+		mocktesting.ScenarioRule{Model: "c", Respond: "{\n  \"confidence\": 0.9,\n  \"answer\": \"yes\"\n}"},
+		mocktesting.ScenarioRule{Model: "d", Error: "overloaded", Status: 503},
+	)
+
+	result, err := client.Ensemble(client.Text().Prompt("Is 1009 prime?")).
+		Models("a", "b", "c", "d").
+		Generate(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, result.ChosenIndex)
+	assert.JSONEq(t, `{"answer":"yes","confidence":0.9}`, result.Chosen.Content())
+	require.Len(t, result.Candidates, 4)
+	assert.Equal(t, "mock", result.Candidates[1].Provider)
+	assert.Equal(t, "b", result.Candidates[1].Model)
+	assert.Error(t, result.Candidates[3].Error)
+}
+
+func TestEnsembleLongestCommonPicksConsensusAnswer(t *testing.T) {
+	t.Parallel()
+	client := newEnsembleClient(t,
+		mocktesting.ScenarioRule{Model: "a", Respond: "Paris is the capital of France."},
+		mocktesting.ScenarioRule{Model: "b", Respond: "I believe the capital is Lyon."},
+		mocktesting.ScenarioRule{Model: "c", Respond: "The capital of France is Paris."},
+		mocktesting.ScenarioRule{Model: "d", Respond: "The capital of France is Paris, of course."},
+	)
+
+	result, err := client.Ensemble(client.Text().Prompt("Capital of France?")).
+		Models("a", "b", "c", "d").
+		Strategy(wormhole.LongestCommon()).
+		Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.ChosenIndex)
+}
+
+func TestEnsembleJudgeSelect(t *testing.T) {
+	t.Parallel()
+	client := newEnsembleClient(t,
+		mocktesting.ScenarioRule{Model: "judge", Respond: "Answer 2 is best."},
+		mocktesting.ScenarioRule{Model: "a", Respond: "first"},
+		mocktesting.ScenarioRule{Model: "b", Respond: "second"},
+	)
+
+	judge := client.Text().Model("judge").SystemPrompt("Pick the most helpful answer to: say something.")
+	result, err := client.Ensemble(client.Text().Prompt("Say something.")).
+		Models("a", "b").
+		Strategy(wormhole.JudgeSelect(judge)).
+		Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ChosenIndex)
+	assert.Equal(t, "second", result.Chosen.Content())
+}
+
+func TestEnsembleFailures(t *testing.T) {
+	t.Parallel()
+	client := newEnsembleClient(t,
+		mocktesting.ScenarioRule{Model: "judge", Respond: "none of them"},
+		mocktesting.ScenarioRule{Model: "a", Respond: "first"},
+		mocktesting.ScenarioRule{Error: "boom", Status: 500},
+	)
+	ctx := context.Background()
+
+	_, err := client.Ensemble(client.Text().Prompt("hi")).Generate(ctx)
+	assert.Error(t, err, "an ensemble needs members")
+
+	result, err := client.Ensemble(client.Text().Prompt("hi")).Models("x", "y").Generate(ctx)
+	require.ErrorIs(t, err, wormhole.ErrNoEnsembleCandidates)
+	require.Len(t, result.Candidates, 2)
+	assert.Nil(t, result.Chosen)
+
+	result, err = client.Ensemble(client.Text().Prompt("hi")).
+		Models("a").
+		Strategy(wormhole.JudgeSelect(client.Text().Model("judge"))).
+		Generate(ctx)
+	require.Error(t, err)
+	assert.Equal(t, -1, result.ChosenIndex)
+	assert.Equal(t, "first", result.Candidates[0].Response.Content())
+}
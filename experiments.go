@@ -0,0 +1,335 @@
+package wormhole
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ErrExperimentNotFound reports an experiment name with no registered match.
+var ErrExperimentNotFound = errors.New("experiment not found")
+
+// Experiment splits traffic between variants so teams can compare models,
+// prompts and parameters in production. Each request is assigned a variant
+// with probability proportional to its weight; requests that share a key
+// (typically a user or session ID) always get the same variant.
+type Experiment struct {
+	Name     string              `json:"name"`
+	Variants []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentVariant is one arm of an experiment. Empty fields leave the
+// builder's own settings in place.
+type ExperimentVariant struct {
+	Name string `json:"name"`
+	// Weight is the variant's share of traffic relative to the other variants.
+	Weight int `json:"weight"`
+	// Provider routes the request to another configured provider.
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+	// Prompt is a prompt registry reference, rendered as UsePrompt does.
+	Prompt      string   `json:"prompt,omitempty"`
+	Temperature *float32 `json:"temperature,omitempty"`
+	MaxTokens   *int     `json:"max_tokens,omitempty"`
+}
+
+// VariantStats summarizes the requests served by one variant.
+type VariantStats struct {
+	Experiment   string
+	Variant      string
+	Requests     int64
+	Errors       int64
+	TotalLatency time.Duration
+	InputTokens  int64
+	OutputTokens int64
+	// Outcomes holds caller-reported results (see RecordOutcome) by name.
+	Outcomes map[string]OutcomeStats
+}
+
+// ErrorRate returns the fraction of requests that failed.
+func (s VariantStats) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Requests)
+}
+
+// AverageLatency returns the mean request latency.
+func (s VariantStats) AverageLatency() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Requests)
+}
+
+// OutcomeStats aggregates the values reported for one outcome.
+type OutcomeStats struct {
+	Count int64
+	Sum   float64
+}
+
+// Mean returns the average reported value.
+func (o OutcomeStats) Mean() float64 {
+	if o.Count == 0 {
+		return 0
+	}
+	return o.Sum / float64(o.Count)
+}
+
+type experimentEntry struct {
+	def         Experiment
+	totalWeight int
+	arms        []*experimentArm
+}
+
+// experimentArm is a variant with its running stats.
+type experimentArm struct {
+	variant ExperimentVariant
+	mu      sync.Mutex
+	stats   VariantStats
+}
+
+// record adds one completed request to the arm's stats.
+func (a *experimentArm) record(latency time.Duration, usage *types.Usage, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stats.Requests++
+	a.stats.TotalLatency += latency
+	if err != nil {
+		a.stats.Errors++
+	}
+	if usage != nil {
+		a.stats.InputTokens += int64(usage.PromptTokens)
+		a.stats.OutputTokens += int64(usage.CompletionTokens)
+	}
+}
+
+func (a *experimentArm) snapshot() VariantStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := a.stats
+	stats.Outcomes = maps.Clone(a.stats.Outcomes)
+	return stats
+}
+
+// ExperimentRegistry holds experiments and the per-variant stats of the
+// requests routed through them. It is safe for concurrent use. Every client
+// has one (see Wormhole.Experiments); share a registry between clients with
+// WithExperimentRegistry.
+type ExperimentRegistry struct {
+	mu          sync.RWMutex
+	experiments map[string]*experimentEntry
+}
+
+// NewExperimentRegistry creates an empty registry.
+func NewExperimentRegistry() *ExperimentRegistry {
+	return &ExperimentRegistry{experiments: make(map[string]*experimentEntry)}
+}
+
+// Register adds experiments. Each needs a name and at least one variant;
+// variant names must be unique and weights positive. Registering an existing
+// name is an error: start a new experiment rather than reshuffling the
+// assignments of one already collecting results.
+func (r *ExperimentRegistry) Register(experiments ...Experiment) error {
+	entries := make([]*experimentEntry, 0, len(experiments))
+	for _, experiment := range experiments {
+		if experiment.Name == "" {
+			return fmt.Errorf("experiment: name is required")
+		}
+		if len(experiment.Variants) == 0 {
+			return fmt.Errorf("experiment %s: at least one variant is required", experiment.Name)
+		}
+		entry := &experimentEntry{def: experiment}
+		seen := make(map[string]bool, len(experiment.Variants))
+		for _, variant := range experiment.Variants {
+			if variant.Name == "" || seen[variant.Name] {
+				return fmt.Errorf("experiment %s: variant names must be unique and non-empty", experiment.Name)
+			}
+			if variant.Weight <= 0 {
+				return fmt.Errorf("experiment %s: variant %s needs a positive weight", experiment.Name, variant.Name)
+			}
+			seen[variant.Name] = true
+			entry.totalWeight += variant.Weight
+			entry.arms = append(entry.arms, &experimentArm{
+				variant: variant,
+				stats:   VariantStats{Experiment: experiment.Name, Variant: variant.Name},
+			})
+		}
+		entry.def.Variants = append([]ExperimentVariant(nil), experiment.Variants...)
+		entries = append(entries, entry)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batch := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if _, exists := r.experiments[entry.def.Name]; exists || batch[entry.def.Name] {
+			return fmt.Errorf("experiment %s is already registered", entry.def.Name)
+		}
+		batch[entry.def.Name] = true
+	}
+	for _, entry := range entries {
+		r.experiments[entry.def.Name] = entry
+	}
+	return nil
+}
+
+// Get returns a registered experiment.
+func (r *ExperimentRegistry) Get(name string) (Experiment, error) {
+	entry, err := r.entry(name)
+	if err != nil {
+		return Experiment{}, err
+	}
+	experiment := entry.def
+	experiment.Variants = append([]ExperimentVariant(nil), entry.def.Variants...)
+	return experiment, nil
+}
+
+// Assign returns the variant for key. The same key always gets the same
+// variant; an empty key picks one at random.
+func (r *ExperimentRegistry) Assign(name, key string) (ExperimentVariant, error) {
+	arm, err := r.assign(name, key)
+	if err != nil {
+		return ExperimentVariant{}, err
+	}
+	return arm.variant, nil
+}
+
+// RecordOutcome reports a result for the variant key was assigned, such as a
+// thumbs-up (1 or 0) or a conversion value, so variants can be compared on
+// what matters to the product and not only on latency and tokens. The
+// key must be the one passed to Experiment.
+func (r *ExperimentRegistry) RecordOutcome(name, key, outcome string, value float64) error {
+	if key == "" {
+		return fmt.Errorf("experiment %s: outcomes need the request's assignment key", name)
+	}
+	arm, err := r.assign(name, key)
+	if err != nil {
+		return err
+	}
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+	if arm.stats.Outcomes == nil {
+		arm.stats.Outcomes = make(map[string]OutcomeStats)
+	}
+	stats := arm.stats.Outcomes[outcome]
+	stats.Count++
+	stats.Sum += value
+	arm.stats.Outcomes[outcome] = stats
+	return nil
+}
+
+// Stats returns the stats of each variant, in registration order. Builders
+// record every Generate call made with Experiment.
+func (r *ExperimentRegistry) Stats(name string) ([]VariantStats, error) {
+	entry, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]VariantStats, 0, len(entry.arms))
+	for _, arm := range entry.arms {
+		stats = append(stats, arm.snapshot())
+	}
+	return stats, nil
+}
+
+func (r *ExperimentRegistry) entry(name string) (*experimentEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.experiments[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrExperimentNotFound, name)
+	}
+	return entry, nil
+}
+
+func (r *ExperimentRegistry) assign(name, key string) (*experimentArm, error) {
+	entry, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	var point int
+	if key == "" {
+		point = rand.IntN(entry.totalWeight)
+	} else {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(name))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(key))
+		point = int(h.Sum64() % uint64(entry.totalWeight))
+	}
+	for _, arm := range entry.arms {
+		if point < arm.variant.Weight {
+			return arm, nil
+		}
+		point -= arm.variant.Weight
+	}
+	return entry.arms[len(entry.arms)-1], nil
+}
+
+// Experiments returns the client's experiment registry.
+//
+// Example:
+//
+//	_ = client.Experiments().Register(wormhole.Experiment{
+//	    Name: "summary-model",
+//	    Variants: []wormhole.ExperimentVariant{
+//	        {Name: "control", Weight: 90, Model: "gpt-5-mini"},
+//	        {Name: "haiku", Weight: 10, Provider: "anthropic", Model: "claude-haiku-4-5"},
+//	    },
+//	})
+//	resp, err := client.Text().
+//	    Prompt(doc).
+//	    Experiment("summary-model", userID, nil).
+//	    Generate(ctx)
+//	stats, _ := client.Experiments().Stats("summary-model")
+func (p *Wormhole) Experiments() *ExperimentRegistry {
+	return p.experiments
+}
+
+// useExperiment assigns a variant for key, applies its provider, and tags the
+// request with experiment and experiment_variant. It returns the variant for
+// the caller to apply its prompt, model and parameters. Errors are kept in
+// experimentErr and returned when the request executes.
+func (cb *CommonBuilder) useExperiment(name, key string) (ExperimentVariant, bool) {
+	arm, err := cb.getWormhole().Experiments().assign(name, key)
+	if err != nil {
+		cb.experimentErr = types.ErrInvalidRequest.WithDetails(err.Error()).WithCause(err)
+		return ExperimentVariant{}, false
+	}
+	if arm.variant.Provider != "" {
+		cb.setProvider(arm.variant.Provider)
+	}
+	cb.experimentErr = nil
+	cb.experiment = arm
+	cb.addTags(map[string]string{"experiment": name, "experiment_variant": arm.variant.Name})
+	return arm.variant, true
+}
+
+// recordExperiment adds a finished request to the assigned variant's stats.
+func (cb *CommonBuilder) recordExperiment(start time.Time, usage *types.Usage, err error) {
+	if cb.experiment != nil {
+		cb.experiment.record(time.Since(start), usage, err)
+	}
+}
+
+// applyExperimentVariant sets the variant's model and parameters on request.
+func applyExperimentVariant(request *types.BaseRequest, variant ExperimentVariant) {
+	if variant.Model != "" {
+		request.Model = variant.Model
+	}
+	if variant.Temperature != nil {
+		temperature := *variant.Temperature
+		request.Temperature = &temperature
+	}
+	if variant.MaxTokens != nil {
+		maxTokens := *variant.MaxTokens
+		request.MaxTokens = &maxTokens
+	}
+}
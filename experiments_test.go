@@ -0,0 +1,135 @@
+package wormhole_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestExperimentAssignmentIsStickyAndWeighted(t *testing.T) {
+	t.Parallel()
+
+	registry := wormhole.NewExperimentRegistry()
+	require.NoError(t, registry.Register(wormhole.Experiment{
+		Name: "summary-model",
+		Variants: []wormhole.ExperimentVariant{
+			{Name: "control", Weight: 3, Model: "gpt-5-mini"},
+			{Name: "candidate", Weight: 1, Model: "claude-haiku-4-5"},
+		},
+	}))
+
+	counts := map[string]int{}
+	for i := range 2000 {
+		key := fmt.Sprintf("user-%d", i)
+		first, err := registry.Assign("summary-model", key)
+		require.NoError(t, err)
+		again, err := registry.Assign("summary-model", key)
+		require.NoError(t, err)
+		require.Equal(t, first.Name, again.Name, "assignment must be sticky for %s", key)
+		counts[first.Name]++
+	}
+	assert.InDelta(t, 1500, counts["control"], 150)
+	assert.InDelta(t, 500, counts["candidate"], 150)
+
+	_, err := registry.Assign("missing", "user-1")
+	assert.ErrorIs(t, err, wormhole.ErrExperimentNotFound)
+	assert.Error(t, registry.Register(wormhole.Experiment{Name: "summary-model", Variants: []wormhole.ExperimentVariant{{Name: "a", Weight: 1}}}))
+	assert.Error(t, registry.Register(wormhole.Experiment{Name: "no-weight", Variants: []wormhole.ExperimentVariant{{Name: "a"}}}))
+	assert.Error(t, registry.Register(wormhole.Experiment{Name: "dup", Variants: []wormhole.ExperimentVariant{{Name: "a", Weight: 1}, {Name: "a", Weight: 1}}}))
+}
+
+func TestExperimentAppliesVariantAndRecordsStats(t *testing.T) {
+	t.Parallel()
+
+	capture := mocktesting.CaptureRequests()
+	provider := mocktesting.NewMockProvider("mock").WithTextResponse(types.TextResponse{
+		Text:  "ok",
+		Usage: &types.Usage{PromptTokens: 10, CompletionTokens: 4},
+	})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+		wormhole.WithProviderMiddleware(capture),
+	)
+	defer client.Close()
+
+	temperature := float32(0.1)
+	require.NoError(t, client.Prompts().Register(wormhole.Prompt{Name: "summarizer", Version: "v2", Template: "Briefly: {{.text}}"}))
+	require.NoError(t, client.Experiments().Register(wormhole.Experiment{
+		Name: "summary",
+		Variants: []wormhole.ExperimentVariant{
+			{Name: "candidate", Weight: 1, Model: "gpt-5-mini", Prompt: "summarizer@v2", Temperature: &temperature},
+		},
+	}))
+
+	_, err := client.Text().
+		Model("gpt-5").
+		Prompt("ignored").
+		Experiment("summary", "user-42", map[string]any{"text": "Q3 report"}).
+		Generate(context.Background())
+	require.NoError(t, err)
+
+	capture.LastText(t).
+		Model("gpt-5-mini").
+		Temperature(0.1).
+		MessageContains(types.RoleUser, "Briefly: Q3 report")
+
+	require.NoError(t, client.Experiments().RecordOutcome("summary", "user-42", "thumbs_up", 1))
+	require.NoError(t, client.Experiments().RecordOutcome("summary", "user-42", "thumbs_up", 0))
+	assert.Error(t, client.Experiments().RecordOutcome("summary", "", "thumbs_up", 1))
+
+	stats, err := client.Experiments().Stats("summary")
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "candidate", stats[0].Variant)
+	assert.Equal(t, int64(1), stats[0].Requests)
+	assert.Equal(t, int64(0), stats[0].Errors)
+	assert.Equal(t, int64(10), stats[0].InputTokens)
+	assert.Equal(t, int64(4), stats[0].OutputTokens)
+	assert.Equal(t, wormhole.OutcomeStats{Count: 2, Sum: 1}, stats[0].Outcomes["thumbs_up"])
+	assert.InDelta(t, 0.5, stats[0].Outcomes["thumbs_up"].Mean(), 1e-9)
+}
+
+func TestExperimentRecordsErrorsAndUnknownExperiments(t *testing.T) {
+	t.Parallel()
+
+	provider := mocktesting.NewMockProvider("mock").WithError("boom")
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+	)
+	defer client.Close()
+	require.NoError(t, client.Experiments().Register(wormhole.Experiment{
+		Name:     "extract",
+		Variants: []wormhole.ExperimentVariant{{Name: "control", Weight: 1, Model: "m"}},
+	}))
+
+	_, err := client.Structured().
+		Prompt("Extract").
+		Schema(map[string]any{"type": "object"}).
+		Experiment("extract", "user-1", nil).
+		Generate(context.Background())
+	require.Error(t, err)
+
+	stats, err := client.Experiments().Stats("extract")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats[0].Requests)
+	assert.InDelta(t, 1.0, stats[0].ErrorRate(), 1e-9)
+
+	_, err = client.Text().Model("m").Prompt("hi").Experiment("missing", "user-1", nil).Generate(context.Background())
+	assert.True(t, errors.Is(err, wormhole.ErrExperimentNotFound))
+}
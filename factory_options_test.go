@@ -298,3 +298,30 @@ func TestWithModels_PopulatesRegistry(t *testing.T) {
 		t.Errorf("provider = %q, want %q", got.Provider, "openai")
 	}
 }
+
+func TestWithModelRegistry_IsolatesClients(t *testing.T) {
+	t.Parallel()
+
+	model := &types.ModelInfo{ID: "isolated-model", Provider: "openai", Capabilities: []types.ModelCapability{types.CapabilityChat}}
+	first := types.NewModelRegistry()
+	second := types.NewModelRegistry()
+
+	a := New(WithOpenAI("test-key"), WithModelRegistry(first), WithModels(model), WithDiscovery(false))
+	b := New(WithOpenAI("test-key"), WithModelRegistry(second), WithDiscovery(false))
+
+	if _, ok := first.Get("isolated-model"); !ok {
+		t.Fatal("WithModels did not populate the client's registry")
+	}
+	if second.Count() != 0 {
+		t.Fatalf("second registry has %d models, want 0", second.Count())
+	}
+	if _, ok := types.DefaultModelRegistry.Get("isolated-model"); ok {
+		t.Fatal("WithModels leaked into DefaultModelRegistry")
+	}
+	if err := a.validateModelAttempt("openai", "missing", textModelCapabilities, nil); err == nil {
+		t.Fatal("client with a populated registry accepted an unknown model")
+	}
+	if err := b.validateModelAttempt("openai", "missing", textModelCapabilities, nil); err != nil {
+		t.Fatalf("client with an empty registry = %v, want permissive", err)
+	}
+}
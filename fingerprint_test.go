@@ -0,0 +1,72 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestIdempotencyHeaderCarriesRequestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":      "chatcmpl-fingerprint",
+			"object":  "chat.completion",
+			"model":   "test-model",
+			"choices": []map[string]any{{"index": 0, "message": map[string]any{"role": "assistant", "content": "ok"}, "finish_reason": "stop"}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client := New(
+		WithDefaultProvider("openai"),
+		WithOpenAICompatible("openai", server.URL, types.ProviderConfig{
+			APIKey:            "test-key",
+			IdempotencyHeader: "Idempotency-Key",
+		}),
+	)
+	generate := func(ctx context.Context, system string) {
+		_, err := client.Text().Model("test-model").SystemPrompt(system).Prompt("hello").Generate(ctx)
+		require.NoError(t, err)
+	}
+
+	ctx := context.Background()
+	generate(ctx, "be brief")
+	generate(ctx, "be brief")
+	generate(ctx, "be verbose")
+	generate(types.ContextWithIdempotencyKey(ctx, "order-42"), "be brief")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, keys, 4)
+	assert.Len(t, keys[0], 64)
+	assert.Equal(t, keys[0], keys[1], "identical submissions share a key")
+	assert.NotEqual(t, keys[0], keys[2], "system prompt is part of the fingerprint")
+	assert.Equal(t, "order-42", keys[3], "caller-supplied key wins")
+}
+
+func TestTextIdempotencyRequestFingerprintIncludesFallbacks(t *testing.T) {
+	t.Parallel()
+
+	req := &types.TextRequest{BaseRequest: types.BaseRequest{Model: "m"}}
+	plain := textIdempotencyRequest{Request: req}
+	assert.Equal(t, req.Fingerprint(), plain.Fingerprint())
+
+	withFallback := textIdempotencyRequest{Request: req, FallbackModels: []string{"m2"}}
+	assert.NotEqual(t, plain.Fingerprint(), withFallback.Fingerprint())
+}
@@ -0,0 +1,83 @@
+package wormhole
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ==================== One-Shot Helpers ====================
+// These run a single request on an existing client, for scripts and small
+// tools that want an answer rather than a builder.
+
+// GenerateText sends prompt to model on client's default provider and returns
+// the response text. Use client.Text() for system prompts, parameters, tools
+// or usage.
+//
+// Example:
+//
+//	answer, err := wormhole.GenerateText(ctx, client, "gpt-4o", "What is Go?")
+func GenerateText(ctx context.Context, client *Wormhole, model, prompt string) (string, error) {
+	resp, err := client.Text().Model(model).Prompt(prompt).Generate(ctx)
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+// GenerateObject asks model for a T and decodes the result into one. The
+// schema is derived from T with SchemaFromStruct, so T must be a struct; json
+// and tool/desc tags shape it as they do for typed tools.
+//
+// Example:
+//
+//	type Recipe struct {
+//	    Name        string   `json:"name" tool:"required"`
+//	    Ingredients []string `json:"ingredients"`
+//	}
+//	recipe, err := wormhole.GenerateObject[Recipe](ctx, client, "gpt-4o", "A pancake recipe")
+func GenerateObject[T any](ctx context.Context, client *Wormhole, model, prompt string) (T, error) {
+	var result T
+	schema, err := SchemaFromStruct(result)
+	if err != nil {
+		return result, err
+	}
+	err = client.Structured().
+		Model(model).
+		Prompt(prompt).
+		Schema(schema).
+		SchemaName(objectSchemaName(reflect.TypeFor[T]())).
+		GenerateAs(ctx, &result)
+	return result, err
+}
+
+// objectSchemaName names the schema after T, e.g. "recipe" for Recipe, so
+// providers that show the name to the model get a meaningful one. A generic
+// Page[pkg.Item] becomes "page_item": package paths are dropped and other
+// characters outside [a-zA-Z0-9_-] become underscores, and the name is cut to
+// 64 characters, as OpenAI requires.
+func objectSchemaName(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	name := typeArgPackage.ReplaceAllString(t.Name(), "")
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "object"
+	}
+	return strings.ToLower(name[:min(len(name), maxSchemaNameLength)])
+}
+
+// typeArgPackage matches the package path reflect spells out before each
+// type argument of a generic type's name.
+var typeArgPackage = regexp.MustCompile(`[\w./-]*\.`)
+
+// maxSchemaNameLength is the longest schema name OpenAI accepts.
+const maxSchemaNameLength = 64
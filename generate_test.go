@@ -0,0 +1,73 @@
+package wormhole_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+type generatedRecipe struct {
+	Name        string   `json:"name" tool:"required"`
+	Ingredients []string `json:"ingredients"`
+}
+
+type generatedPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestGenerateTextAndObject(t *testing.T) {
+	t.Parallel()
+
+	var sent *types.StructuredRequest
+	provider := mocktesting.NewMockProvider("mock").
+		WithTextResponse(types.TextResponse{Text: "Go is a language."}).
+		WithStructuredData(map[string]any{"name": "Pancakes", "ingredients": []any{"flour", "milk"}})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+		wormhole.WithMiddleware(func(next middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				if structured, ok := req.(*types.StructuredRequest); ok {
+					sent = structured
+				}
+				return next(ctx, req)
+			}
+		}),
+	)
+	defer client.Close()
+	ctx := context.Background()
+
+	text, err := wormhole.GenerateText(ctx, client, "m", "What is Go?")
+	require.NoError(t, err)
+	assert.Equal(t, "Go is a language.", text)
+
+	recipe, err := wormhole.GenerateObject[generatedRecipe](ctx, client, "m", "A pancake recipe")
+	require.NoError(t, err)
+	assert.Equal(t, generatedRecipe{Name: "Pancakes", Ingredients: []string{"flour", "milk"}}, recipe)
+
+	require.NotNil(t, sent)
+	assert.Equal(t, "generatedrecipe", sent.SchemaName)
+	raw, ok := sent.Schema.([]byte)
+	require.True(t, ok)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(raw, &schema))
+	assert.Equal(t, []any{"name"}, schema["required"])
+
+	_, err = wormhole.GenerateObject[generatedPage[generatedRecipe]](ctx, client, "m", "A page of recipes")
+	require.NoError(t, err)
+	assert.Equal(t, "generatedpage_generatedrecipe", sent.SchemaName, "generic type names are made valid")
+
+	_, err = wormhole.GenerateObject[[]string](ctx, client, "m", "list")
+	assert.Error(t, err, "non-struct types have no derivable schema")
+}
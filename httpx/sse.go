@@ -0,0 +1,232 @@
+// Package httpx holds HTTP glue for serving wormhole results from web
+// handlers.
+//
+// StreamSSE turns a text stream into a server-sent events response:
+//
+//	func chat(w http.ResponseWriter, r *http.Request) {
+//	    stream, err := client.Text().Model("gpt-5-mini").Prompt(r.FormValue("q")).Stream(r.Context())
+//	    if err != nil {
+//	        http.Error(w, "stream unavailable", http.StatusBadGateway)
+//	        return
+//	    }
+//	    _ = httpx.StreamSSE(w, r, stream, httpx.SSEConfig{})
+//	}
+//
+// In the browser, each delta arrives as a "message" event:
+//
+//	const events = new EventSource("/chat?q=hello")
+//	events.onmessage = (e) => output.textContent += JSON.parse(e.data).text
+//	events.addEventListener("done", () => events.close())
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+const defaultHeartbeat = 15 * time.Second
+
+// SSEConfig configures StreamSSE. The zero value sends JSON deltas as
+// unnamed events, a heartbeat every 15 seconds, and a final "done" event.
+type SSEConfig struct {
+	// Heartbeat is how often a comment line is sent while the stream is idle,
+	// keeping proxies and load balancers from closing the connection.
+	// Zero means 15 seconds; negative disables heartbeats.
+	Heartbeat time.Duration
+	// Event names the event of each delta. Empty sends unnamed events, which
+	// browsers deliver as "message".
+	Event string
+	// Encode renders a chunk as event data. Nil uses SSEChunk's JSON form.
+	// Returning nil data skips the chunk.
+	Encode func(types.TextChunk) ([]byte, error)
+	// Retry, when positive, asks clients to wait this long before reconnecting.
+	Retry time.Duration
+}
+
+// SSEChunk is the default data of a delta event.
+type SSEChunk struct {
+	Text         string             `json:"text,omitempty"`
+	Refusal      string             `json:"refusal,omitempty"`
+	ToolCalls    []types.ToolCall   `json:"tool_calls,omitempty"`
+	FinishReason types.FinishReason `json:"finish_reason,omitempty"`
+	// RawFinishReason is the provider's own finish reason; see
+	// types.NormalizeFinishReason.
+	RawFinishReason string       `json:"raw_finish_reason,omitempty"`
+	Usage           *types.Usage `json:"usage,omitempty"`
+}
+
+// SSEError is the data of the "error" event sent when the stream fails. It
+// carries the error code only, never the upstream message, which may echo
+// provider internals.
+type SSEError struct {
+	Code      types.ErrorCode `json:"code"`
+	Message   string          `json:"message"`
+	Retryable bool            `json:"retryable"`
+}
+
+// StreamSSE writes stream to w as a text/event-stream response. Each chunk
+// becomes one event with an increasing id; the stream ends with a "done"
+// event, or an "error" event (see SSEError) if a chunk carries an error.
+//
+// StreamSSE returns when the stream closes, the stream fails, a write fails,
+// or the client disconnects (r's context ends). In the last case it returns
+// the context's error and drains the rest of stream in the background, so
+// the producer is never blocked; pass r.Context() to Stream so the upstream
+// request is canceled too. A stream error is returned after it is reported
+// to the client.
+func StreamSSE(w http.ResponseWriter, r *http.Request, stream <-chan types.TextChunk, config SSEConfig) error {
+	rc := http.NewResponseController(w)
+	header := w.Header()
+	header.Set("Content-Type", types.ContentTypeEventStream)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // nginx buffers event streams otherwise
+	w.WriteHeader(http.StatusOK)
+
+	sse := &sseWriter{w: w, rc: rc}
+	if config.Retry > 0 {
+		sse.printf("retry: %d\n\n", config.Retry.Milliseconds())
+	}
+	if err := sse.flush(); err != nil {
+		drain(stream)
+		return err
+	}
+
+	encode := config.Encode
+	if encode == nil {
+		encode = encodeSSEChunk
+	}
+	heartbeat := config.Heartbeat
+	if heartbeat == 0 {
+		heartbeat = defaultHeartbeat
+	}
+	var ticks <-chan time.Time
+	if heartbeat > 0 {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	ctx := r.Context()
+	id := 0
+	for {
+		select {
+		case <-ctx.Done():
+			drain(stream)
+			return ctx.Err()
+		case <-ticks:
+			sse.printf(": ping\n\n")
+		case chunk, ok := <-stream:
+			if !ok {
+				sse.event("done", "", []byte("[DONE]"))
+				return sse.flush()
+			}
+			if chunk.Error != nil {
+				data, _ := json.Marshal(sseError(chunk.Error))
+				sse.event("error", "", data)
+				drain(stream)
+				if err := sse.flush(); err != nil {
+					return err
+				}
+				return chunk.Error
+			}
+			data, err := encode(chunk)
+			if err != nil {
+				drain(stream)
+				return fmt.Errorf("encode SSE chunk: %w", err)
+			}
+			if data == nil {
+				continue
+			}
+			id++
+			sse.event(config.Event, strconv.Itoa(id), data)
+		}
+		if err := sse.flush(); err != nil {
+			drain(stream)
+			return err
+		}
+	}
+}
+
+func encodeSSEChunk(chunk types.TextChunk) ([]byte, error) {
+	out := SSEChunk{
+		Text:      chunk.Content(),
+		Refusal:   chunk.Refusal,
+		ToolCalls: chunk.ToolCalls,
+		Usage:     chunk.Usage,
+	}
+	if chunk.ToolCall != nil {
+		out.ToolCalls = append([]types.ToolCall{*chunk.ToolCall}, out.ToolCalls...)
+	}
+	if chunk.FinishReason != nil {
+		out.FinishReason = *chunk.FinishReason
+		out.RawFinishReason = chunk.RawFinishReason
+	}
+	return json.Marshal(out)
+}
+
+func sseError(err error) SSEError {
+	out := SSEError{Code: types.ErrorCodeUnknown, Message: "stream failed"}
+	if errors.Is(err, context.DeadlineExceeded) {
+		out.Code, out.Message, out.Retryable = types.ErrorCodeTimeout, "stream timed out", true
+	}
+	if whErr, ok := types.AsWormholeError(err); ok {
+		out.Code, out.Retryable = whErr.Code, whErr.Retryable
+	}
+	return out
+}
+
+// drain discards the rest of stream so its producer can finish.
+func drain(stream <-chan types.TextChunk) {
+	go func() {
+		for range stream {
+		}
+	}()
+}
+
+// sseWriter frames events and remembers the first write error.
+type sseWriter struct {
+	w   io.Writer
+	rc  *http.ResponseController
+	err error
+}
+
+func (s *sseWriter) printf(format string, args ...any) {
+	if s.err == nil {
+		_, s.err = fmt.Fprintf(s.w, format, args...)
+	}
+}
+
+// event writes one event. Data containing newlines is split across several
+// data lines, which clients join back together.
+func (s *sseWriter) event(name, id string, data []byte) {
+	if name != "" {
+		s.printf("event: %s\n", name)
+	}
+	if id != "" {
+		s.printf("id: %s\n", id)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		s.printf("data: %s\n", strings.TrimSuffix(line, "\r"))
+	}
+	s.printf("\n")
+}
+
+func (s *sseWriter) flush() error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.err = err
+	}
+	return s.err
+}
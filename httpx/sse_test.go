@@ -0,0 +1,103 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func chunkStream(chunks ...types.TextChunk) <-chan types.TextChunk {
+	stream := make(chan types.TextChunk, len(chunks))
+	for _, chunk := range chunks {
+		stream <- chunk
+	}
+	close(stream)
+	return stream
+}
+
+func TestStreamSSEFramesChunks(t *testing.T) {
+	t.Parallel()
+
+	stop := types.FinishReasonStop
+	stream := chunkStream(
+		types.TextChunk{Text: "Hel"},
+		types.TextChunk{Text: "lo", FinishReason: &stop, Usage: &types.Usage{TotalTokens: 3}},
+	)
+	recorder := httptest.NewRecorder()
+	err := StreamSSE(recorder, httptest.NewRequest(http.MethodGet, "/", nil), stream, SSEConfig{Retry: time.Second})
+	require.NoError(t, err)
+
+	assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", recorder.Header().Get("Cache-Control"))
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, "retry: 1000\n\n"+
+		"id: 1\ndata: {\"text\":\"Hel\"}\n\n"+
+		"id: 2\ndata: {\"text\":\"lo\",\"finish_reason\":\"stop\",\"usage\":{\"prompt_tokens\":0,\"completion_tokens\":0,\"total_tokens\":3}}\n\n"+
+		"event: done\ndata: [DONE]\n\n", recorder.Body.String())
+}
+
+func TestStreamSSESplitsMultilineData(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	err := StreamSSE(recorder, httptest.NewRequest(http.MethodGet, "/", nil), chunkStream(types.TextChunk{Text: "a\nb"}), SSEConfig{
+		Event:  "delta",
+		Encode: func(chunk types.TextChunk) ([]byte, error) { return []byte(chunk.Text), nil },
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(recorder.Body.String(), "event: delta\nid: 1\ndata: a\ndata: b\n\n"), recorder.Body.String())
+}
+
+func TestStreamSSEReportsErrorWithoutUpstreamMessage(t *testing.T) {
+	t.Parallel()
+
+	upstream := types.NewWormholeError(types.ErrorCodeRateLimit, "secret upstream body", true)
+	recorder := httptest.NewRecorder()
+	err := StreamSSE(recorder, httptest.NewRequest(http.MethodGet, "/", nil),
+		chunkStream(types.TextChunk{Text: "partial"}, types.TextChunk{Error: upstream}), SSEConfig{})
+	require.ErrorIs(t, err, upstream)
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, "event: error\ndata: {\"code\":\"RATE_LIMIT_ERROR\",\"message\":\"stream failed\",\"retryable\":true}\n\n")
+	assert.NotContains(t, body, "secret")
+	assert.NotContains(t, body, "[DONE]")
+}
+
+func TestStreamSSESendsHeartbeatsAndStopsOnDisconnect(t *testing.T) {
+	t.Parallel()
+
+	stream := make(chan types.TextChunk)
+	ctx, cancel := context.WithCancel(context.Background())
+	recorder := httptest.NewRecorder()
+	done := make(chan error, 1)
+	go func() {
+		done <- StreamSSE(recorder, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx), stream, SSEConfig{Heartbeat: time.Millisecond})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		assert.True(t, errors.Is(err, context.Canceled))
+	case <-time.After(time.Second):
+		t.Fatal("StreamSSE did not return after disconnect")
+	}
+	assert.Contains(t, recorder.Body.String(), ": ping\n\n")
+
+	// The producer is not blocked after the handler returns.
+	select {
+	case stream <- types.TextChunk{Text: "late"}:
+	case <-time.After(time.Second):
+		t.Fatal("stream was not drained")
+	}
+	close(stream)
+}
@@ -0,0 +1,48 @@
+package wormhole_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestWithHuggingFaceRoutesByHubModelID(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer hf_test", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/models/HuggingFaceH4/zephyr-7b-beta":
+			_, _ = w.Write([]byte(`[{"generated_text":"hi there"}]`))
+		case "/models/sentence-transformers/all-MiniLM-L6-v2":
+			_, _ = w.Write([]byte(`[[0.5,0.25]]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := wormhole.New(
+		wormhole.WithHuggingFace("hf_test", types.ProviderConfig{BaseURL: server.URL + "/models"}),
+		wormhole.WithDefaultProvider("huggingface"),
+		wormhole.WithDiscovery(false),
+	)
+	defer client.Close()
+
+	ctx := context.Background()
+	resp, err := client.Text().Model("HuggingFaceH4/zephyr-7b-beta").Prompt("hello").Generate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "hi there", resp.Text)
+
+	emb, err := client.Embeddings().Model("sentence-transformers/all-MiniLM-L6-v2").Input("hello").Generate(ctx)
+	require.NoError(t, err)
+	require.Len(t, emb.Embeddings, 1)
+	assert.Equal(t, []float64{0.5, 0.25}, emb.Embeddings[0].Embedding)
+}
@@ -3,16 +3,34 @@ package wormhole
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 
+	"github.com/garyblankenship/wormhole/v2/middleware"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
-// ImageRequestBuilder builds image generation requests
+// ImageRequestBuilder builds image generation requests, and image edits and
+// variations after Edit or Variations.
 type ImageRequestBuilder struct {
 	CommonBuilder
 	request *types.ImageRequest
+
+	operation imageOperation
+	images    [][]byte
+	mask      []byte
+	inputErr  error
 }
 
+// imageOperation selects the endpoint Generate calls.
+type imageOperation int
+
+const (
+	imageGenerate imageOperation = iota
+	imageEdit
+	imageVariations
+)
+
 // Using sets the provider to use
 func (b *ImageRequestBuilder) Using(provider string) *ImageRequestBuilder {
 	b.setProvider(provider)
@@ -67,14 +85,139 @@ func (b *ImageRequestBuilder) ResponseFormat(format string) *ImageRequestBuilder
 	return b
 }
 
+// Base64 asks for images as base64 data in GeneratedImage.B64JSON.
+func (b *ImageRequestBuilder) Base64() *ImageRequestBuilder {
+	return b.ResponseFormat(types.ImageResponseFormatBase64)
+}
+
+// URL asks for images as short-lived links in GeneratedImage.URL.
+func (b *ImageRequestBuilder) URL() *ImageRequestBuilder {
+	return b.ResponseFormat(types.ImageResponseFormatURL)
+}
+
+// Edit makes Generate edit the images given with Image as Prompt describes,
+// within the Mask if one is set. The provider must implement
+// types.ImageEditor, as OpenAI and compatible APIs do.
+//
+// Example:
+//
+//	photo, _ := os.Open("room.png")
+//	mask, _ := os.Open("room-mask.png")
+//	resp, err := client.Image().
+//	    Edit().
+//	    Model("gpt-image-1").
+//	    Image(photo).
+//	    Mask(mask).
+//	    Prompt("Add a reading lamp beside the sofa").
+//	    Generate(ctx)
+func (b *ImageRequestBuilder) Edit() *ImageRequestBuilder {
+	b.operation = imageEdit
+	return b
+}
+
+// Variations makes Generate return variations of the image given with Image;
+// no prompt is needed. See Edit.
+func (b *ImageRequestBuilder) Variations() *ImageRequestBuilder {
+	b.operation = imageVariations
+	return b
+}
+
+// Image adds a source image for Edit or Variations. The reader is consumed
+// immediately; a read error is returned by Generate.
+func (b *ImageRequestBuilder) Image(r io.Reader) *ImageRequestBuilder {
+	if data, ok := b.readInput(r); ok {
+		b.images = append(b.images, data)
+	}
+	return b
+}
+
+// Mask sets the PNG mask for Edit; its transparent pixels mark the area to
+// change.
+func (b *ImageRequestBuilder) Mask(r io.Reader) *ImageRequestBuilder {
+	if data, ok := b.readInput(r); ok {
+		b.mask = data
+	}
+	return b
+}
+
+func (b *ImageRequestBuilder) readInput(r io.Reader) ([]byte, bool) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		if b.inputErr == nil {
+			b.inputErr = types.ErrInvalidRequest.WithDetails("read image: " + err.Error()).WithCause(err)
+		}
+		return nil, false
+	}
+	return data, true
+}
+
 // ProviderOptions sets provider-specific image generation options.
 func (b *ImageRequestBuilder) ProviderOptions(options map[string]any) *ImageRequestBuilder {
 	b.request.ProviderOptions = options
 	return b
 }
 
+// ProviderOption sets one provider-specific option sent only to provider, as
+// TextRequestBuilder.ProviderOption does.
+func (b *ImageRequestBuilder) ProviderOption(provider, key string, value any) *ImageRequestBuilder {
+	b.setProviderOption(provider, key, value)
+	return b
+}
+
+// Metadata tags the request for metrics and logging, as
+// TextRequestBuilder.Metadata does.
+func (b *ImageRequestBuilder) Metadata(tags map[string]string) *ImageRequestBuilder {
+	b.addTags(tags)
+	return b
+}
+
+// Priority sets the request's scheduling priority, as
+// TextRequestBuilder.Priority does.
+func (b *ImageRequestBuilder) Priority(p Priority) *ImageRequestBuilder {
+	b.setPriority(p)
+	return b
+}
+
+// Cache overrides CacheMiddleware's TTL for this request, as
+// TextRequestBuilder.Cache does.
+func (b *ImageRequestBuilder) Cache(ttl time.Duration) *ImageRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{TTL: ttl})
+	return b
+}
+
+// NoCache sends the request past the cache middleware, as
+// TextRequestBuilder.NoCache does.
+func (b *ImageRequestBuilder) NoCache() *ImageRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{Bypass: true})
+	return b
+}
+
+// CacheKey returns the key CacheMiddleware uses for this request, as
+// TextRequestBuilder.CacheKey does.
+func (b *ImageRequestBuilder) CacheKey() (string, error) {
+	request := cloneImageRequest(b.request)
+	if request.N == 0 {
+		request.N = 1
+	}
+	return b.cacheKey(func(provider types.Provider) any {
+		scoped := *request
+		scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
+		return scoped
+	})
+}
+
 // Generate executes the request and returns generated images
 func (b *ImageRequestBuilder) Generate(ctx context.Context) (*types.ImageResponse, error) {
+	switch b.request.ResponseFormat {
+	case "", types.ImageResponseFormatURL, types.ImageResponseFormatBase64:
+	default:
+		return nil, fmt.Errorf("unknown image response format %q: use %q or %q", b.request.ResponseFormat, types.ImageResponseFormatURL, types.ImageResponseFormatBase64)
+	}
+	if b.operation != imageGenerate {
+		return b.editOrVary(ctx)
+	}
+
+	ctx = b.taggedContext(ctx)
 	request := cloneImageRequest(b.request)
 
 	// Validate request
@@ -101,12 +244,97 @@ func (b *ImageRequestBuilder) Generate(ctx context.Context) (*types.ImageRespons
 		defer release()
 
 		ctx = contextWithProviderOperation(ctx, provider, "image")
+		scoped := *request
+		scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
 		if b.getWormhole().providerMiddleware != nil {
 			handler := b.getWormhole().providerMiddleware.ApplyImage(provider.GenerateImage)
-			return handler(ctx, *request)
+			return handler(ctx, scoped)
 		}
 
-		return provider.GenerateImage(ctx, *request)
+		return provider.GenerateImage(ctx, scoped)
+	})
+}
+
+// editOrVary runs an Edit or Variations request through the provider
+// middleware to the provider's types.ImageEditor implementation.
+func (b *ImageRequestBuilder) editOrVary(ctx context.Context) (*types.ImageResponse, error) {
+	if b.inputErr != nil {
+		return nil, b.inputErr
+	}
+	if len(b.images) == 0 {
+		return nil, fmt.Errorf("no image provided")
+	}
+	if b.operation == imageVariations && len(b.images) > 1 {
+		return nil, fmt.Errorf("variations take a single image")
+	}
+	if b.request.Model == "" {
+		return nil, fmt.Errorf("no model specified")
+	}
+	if b.operation == imageEdit && b.request.Prompt == "" {
+		return nil, fmt.Errorf("no prompt provided")
+	}
+	if err := b.getWormhole().validateModelAttempt(b.getProvider(), b.request.Model, nil, []types.ModelCapability{types.CapabilityImages}); err != nil {
+		return nil, err
+	}
+
+	ctx = b.taggedContext(ctx)
+	request := cloneImageRequest(b.request)
+	images := make([][]byte, len(b.images))
+	for i, image := range b.images {
+		images[i] = append([]byte(nil), image...)
+	}
+	mask := append([]byte(nil), b.mask...)
+	operation := "image.edit"
+	if b.operation == imageVariations {
+		operation = "image.variations"
+	}
+
+	return executeTrackedRequest(ctx, b.getWormhole(), b.idempotencyScope(operation), request, func(ctx context.Context) (*types.ImageResponse, error) {
+		provider, release, err := b.getProviderWithBaseURL()
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		editor, ok := provider.(types.ImageEditor)
+		if !ok {
+			return nil, types.NotImplementedError(provider.Name(), operation)
+		}
+		ctx = contextWithProviderOperation(ctx, provider, "image")
+		scoped := *request
+		scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
+		// The middleware sees the request's settings as an ImageRequest; the
+		// source images and mask travel with the handler.
+		call := func(ctx context.Context, request types.ImageRequest) (*types.ImageResponse, error) {
+			if b.operation == imageVariations {
+				return editor.ImageVariations(ctx, types.ImageVariationRequest{
+					Model:           request.Model,
+					Image:           images[0],
+					Size:            request.Size,
+					N:               request.N,
+					ResponseFormat:  request.ResponseFormat,
+					ProviderOptions: request.ProviderOptions,
+				})
+			}
+			return editor.EditImage(ctx, types.ImageEditRequest{
+				Model:           request.Model,
+				Prompt:          request.Prompt,
+				Images:          images,
+				Mask:            mask,
+				Size:            request.Size,
+				Quality:         request.Quality,
+				N:               request.N,
+				ResponseFormat:  request.ResponseFormat,
+				ProviderOptions: request.ProviderOptions,
+			})
+		}
+		if b.getWormhole().providerMiddleware != nil {
+			// Cache keys cannot see the source images, so two edits with
+			// the same prompt must not share an entry.
+			ctx = middleware.WithCacheControl(ctx, middleware.CacheControl{Bypass: true})
+			return b.getWormhole().providerMiddleware.ApplyImage(call)(ctx, scoped)
+		}
+		return call(ctx, scoped)
 	})
 }
 
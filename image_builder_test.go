@@ -1,13 +1,18 @@
 package wormhole_test
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/middleware"
 	"github.com/garyblankenship/wormhole/v2/types"
 	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
 )
@@ -99,3 +104,104 @@ func TestImageRequestBuilder(t *testing.T) {
 		assert.Contains(t, err.Error(), "image generation failed")
 	})
 }
+
+func TestImageEditAndVariations(t *testing.T) {
+	t.Parallel()
+	png := []byte("\x89PNG\r\n\x1a\n fake image")
+	var paths []string
+	var forms []map[string][]string
+	server := testutil.MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		paths = append(paths, r.URL.Path)
+		fields := map[string][]string{}
+		for name, values := range r.MultipartForm.Value {
+			fields[name] = values
+		}
+		for name := range r.MultipartForm.File {
+			fields["file:"+name] = []string{r.MultipartForm.File[name][0].Header.Get("Content-Type")}
+		}
+		forms = append(forms, fields)
+		_, _ = io.WriteString(w, `{"created":1,"data":[{"b64_json":"aW1n"}]}`)
+	})
+	client := wormhole.New(wormhole.WithOpenAI("test-key", types.ProviderConfig{BaseURL: server.URL}))
+	ctx := context.Background()
+
+	resp, err := client.Image().
+		Edit().
+		Model("gpt-image-1").
+		Image(bytes.NewReader(png)).
+		Mask(bytes.NewReader(png)).
+		Prompt("Add a lamp").
+		Base64().
+		Generate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "aW1n", resp.Images[0].B64JSON)
+
+	_, err = client.Image().
+		Variations().
+		Model("dall-e-2").
+		Image(bytes.NewReader(png)).
+		N(2).
+		Generate(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/images/edits", "/images/variations"}, paths)
+	assert.Equal(t, []string{"Add a lamp"}, forms[0]["prompt"])
+	assert.Equal(t, []string{"b64_json"}, forms[0]["response_format"])
+	assert.Equal(t, []string{"image/png"}, forms[0]["file:image"])
+	assert.Equal(t, []string{"image/png"}, forms[0]["file:mask"])
+	assert.Equal(t, []string{"2"}, forms[1]["n"])
+	assert.NotContains(t, forms[1], "prompt")
+
+	_, err = client.Image().Edit().Model("gpt-image-1").Prompt("Add a lamp").Generate(ctx)
+	assert.ErrorContains(t, err, "no image provided")
+	_, err = client.Image().Model("dall-e-3").Prompt("x").ResponseFormat("png").Generate(ctx)
+	assert.ErrorContains(t, err, "unknown image response format")
+}
+
+func TestImageEditRunsProviderMiddleware(t *testing.T) {
+	t.Parallel()
+	var n []string
+	server := testutil.MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		n = append(n, r.MultipartForm.Value["n"]...)
+		_, _ = io.WriteString(w, `{"created":1,"data":[{"b64_json":"aW1n"}]}`)
+	})
+	var seen []string
+	hooks := middleware.Hooks{
+		Image: func(ctx context.Context, req types.ImageRequest, next func(context.Context, types.ImageRequest) (*types.ImageResponse, error)) (*types.ImageResponse, error) {
+			seen = append(seen, req.Prompt)
+			req.N = 3
+			return next(ctx, req)
+		},
+	}
+	client := wormhole.New(
+		wormhole.WithOpenAI("test-key", types.ProviderConfig{BaseURL: server.URL}),
+		wormhole.WithProviderMiddleware(hooks),
+	)
+	ctx := context.Background()
+	png := []byte("\x89PNG\r\n\x1a\n fake image")
+
+	_, err := client.Image().Edit().Model("gpt-image-1").Image(bytes.NewReader(png)).Prompt("Add a lamp").Generate(ctx)
+	require.NoError(t, err)
+	_, err = client.Image().Variations().Model("dall-e-2").Image(bytes.NewReader(png)).Generate(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Add a lamp", ""}, seen, "edits and variations pass through the middleware")
+	assert.Equal(t, []string{"3", "3"}, n, "the middleware's request is the one sent")
+}
+
+func TestImageEditNeedsImageEditor(t *testing.T) {
+	t.Parallel()
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(mocktesting.NewMockProvider("mock"))),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+	)
+	_, err := client.Image().
+		Variations().
+		Model("dall-e-2").
+		Image(bytes.NewReader([]byte("img"))).
+		Generate(context.Background())
+	assert.ErrorContains(t, err, "image.variations")
+}
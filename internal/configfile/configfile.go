@@ -0,0 +1,36 @@
+// Package configfile decodes the configuration files read by the library,
+// the test scenarios and the CLI, so they all accept the same formats.
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decode decodes data into v, choosing the format from path's extension:
+// .yaml and .yml are YAML and everything else is JSON. YAML uses the yaml
+// struct tags and JSON the json tags. Unknown fields are rejected in both, so
+// typos fail loudly instead of being ignored.
+func Decode(path string, data []byte, v any) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	case ".toml":
+		return fmt.Errorf("TOML is not supported; use JSON or YAML")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,101 @@
+package partialjson
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ArrayScanner incrementally extracts the elements of a streamed JSON array.
+// The array is either the document root or, for a root object, the first
+// array-valued property (the usual {"items":[...]} wrapper required by strict
+// schema modes). Elements are returned as soon as their closing delimiter
+// arrives; later arrays in the document are ignored.
+type ArrayScanner struct {
+	buf         []byte
+	pos         int
+	depth       int
+	rootObject  bool
+	targetDepth int // depth inside the element array; 0 until it is found
+	elemStart   int // offset of the pending element, -1 when none
+	inString    bool
+	escaped     bool
+	done        bool
+}
+
+// NewArrayScanner returns a scanner positioned before the first byte.
+func NewArrayScanner() *ArrayScanner {
+	return &ArrayScanner{elemStart: -1}
+}
+
+// Done reports whether the element array has been closed.
+func (s *ArrayScanner) Done() bool {
+	return s.done
+}
+
+// Write appends a text delta and returns the elements it completed, in
+// order. Elements that are not valid JSON on their own are skipped.
+func (s *ArrayScanner) Write(delta string) []json.RawMessage {
+	s.buf = append(s.buf, delta...)
+	var out []json.RawMessage
+	emit := func(end int) {
+		elem := bytes.TrimSpace(s.buf[s.elemStart:end])
+		s.elemStart = -1
+		if len(elem) > 0 && json.Valid(elem) {
+			out = append(out, json.RawMessage(bytes.Clone(elem)))
+		}
+	}
+
+	for ; s.pos < len(s.buf) && !s.done; s.pos++ {
+		c := s.buf[s.pos]
+		if s.inString {
+			switch {
+			case s.escaped:
+				s.escaped = false
+			case c == '\\':
+				s.escaped = true
+			case c == '"':
+				s.inString = false
+			}
+			continue
+		}
+
+		atElementLevel := s.targetDepth > 0 && s.depth == s.targetDepth
+		switch c {
+		case ' ', '\t', '\r', '\n':
+		case '{', '[':
+			if atElementLevel && s.elemStart < 0 {
+				s.elemStart = s.pos
+			}
+			s.depth++
+			if s.depth == 1 && c == '{' {
+				s.rootObject = true
+			}
+			if s.targetDepth == 0 && c == '[' && (s.depth == 1 || (s.depth == 2 && s.rootObject)) {
+				s.targetDepth = s.depth
+			}
+		case '}', ']':
+			if atElementLevel {
+				if s.elemStart >= 0 {
+					emit(s.pos)
+				}
+				s.done = true
+			}
+			s.depth--
+			if s.targetDepth > 0 && s.depth == s.targetDepth && s.elemStart >= 0 {
+				emit(s.pos + 1)
+			}
+		case ',':
+			if atElementLevel && s.elemStart >= 0 {
+				emit(s.pos)
+			}
+		default:
+			if atElementLevel && s.elemStart < 0 {
+				s.elemStart = s.pos
+			}
+			if c == '"' {
+				s.inString = true
+			}
+		}
+	}
+	return out
+}
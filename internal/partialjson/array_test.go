@@ -0,0 +1,65 @@
+package partialjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestArrayScannerEmitsElementsAsTheyComplete(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		deltas []string
+		want   [][]string // elements completed after each delta
+		done   bool
+	}{
+		{
+			name:   "root array of objects",
+			deltas: []string{`[{"n":1,"s":"a,]}"`, `},{"n":2`, `}]`},
+			want:   [][]string{nil, {`{"n":1,"s":"a,]}"}`}, {`{"n":2}`}},
+			done:   true,
+		},
+		{
+			name:   "wrapped array",
+			deltas: []string{`{"note":"[x]","items":[1, 2`, `,"three",[4]`, `], "more":[5]}`},
+			want:   [][]string{{`1`}, {`2`, `"three"`, `[4]`}, nil},
+			done:   true,
+		},
+		{
+			name:   "unfinished",
+			deltas: []string{`[{"a":1},{"a":`},
+			want:   [][]string{{`{"a":1}`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := NewArrayScanner()
+			for i, delta := range tt.deltas {
+				var got []string
+				for _, elem := range s.Write(delta) {
+					got = append(got, string(elem))
+				}
+				if !reflect.DeepEqual(got, tt.want[i]) {
+					t.Fatalf("Write(%q) = %v, want %v", delta, got, tt.want[i])
+				}
+			}
+			if s.Done() != tt.done {
+				t.Fatalf("Done() = %v, want %v", s.Done(), tt.done)
+			}
+		})
+	}
+}
+
+func TestArrayScannerElementsAreIndependent(t *testing.T) {
+	t.Parallel()
+
+	s := NewArrayScanner()
+	first := s.Write(`[{"id":1},`)
+	s.Write(`{"id":2}]`)
+	var v map[string]int
+	if err := json.Unmarshal(first[0], &v); err != nil || v["id"] != 1 {
+		t.Fatalf("first element = %s (%v)", first[0], err)
+	}
+}
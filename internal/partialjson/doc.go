@@ -0,0 +1,3 @@
+// Package partialjson parses truncated JSON documents produced while a model
+// is still streaming structured output.
+package partialjson
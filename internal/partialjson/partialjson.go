@@ -0,0 +1,107 @@
+package partialjson
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// cutPoint is a position where the document can be truncated so that every
+// value before it is complete, together with the containers still open there.
+type cutPoint struct {
+	offset int
+	open   string
+}
+
+// Parse decodes the longest usable prefix of a possibly truncated JSON
+// document. Open strings are closed, open objects and arrays are terminated,
+// and a trailing key, literal, or escape that cannot be completed is dropped.
+// ok is false when no value can be recovered yet (for example, empty input or
+// a bare partial literal).
+func Parse(raw string) (value any, ok bool) {
+	repaired, ok := Repair(raw)
+	if !ok {
+		return nil, false
+	}
+	if err := json.Unmarshal([]byte(repaired), &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Repair returns the longest valid JSON document that can be recovered from a
+// truncated prefix. See Parse for the recovery rules.
+func Repair(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false
+	}
+
+	var (
+		stack    []byte
+		cuts     []cutPoint
+		inString bool
+		escaped  bool
+	)
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, c)
+			cuts = append(cuts, cutPoint{offset: i + 1, open: string(stack)})
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			cuts = append(cuts, cutPoint{offset: i, open: string(stack)})
+		}
+	}
+
+	// Fast path: close whatever is open and accept if the result is valid.
+	candidate := raw
+	if inString {
+		if escaped {
+			candidate = candidate[:len(candidate)-1]
+		}
+		candidate += `"`
+	}
+	candidate += closers(string(stack))
+	if json.Valid([]byte(candidate)) {
+		return candidate, true
+	}
+
+	// Otherwise fall back to the latest point where every value was complete.
+	for i := len(cuts) - 1; i >= 0; i-- {
+		candidate = raw[:cuts[i].offset] + closers(cuts[i].open)
+		if json.Valid([]byte(candidate)) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func closers(open string) string {
+	var b strings.Builder
+	b.Grow(len(open))
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] == '{' {
+			b.WriteByte('}')
+		} else {
+			b.WriteByte(']')
+		}
+	}
+	return b.String()
+}
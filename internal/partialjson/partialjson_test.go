@@ -0,0 +1,57 @@
+package partialjson
+
+import (
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		raw  string
+		want string
+		ok   bool
+	}{
+		{name: "empty", raw: "", ok: false},
+		{name: "complete object", raw: `{"a":1}`, want: `{"a":1}`, ok: true},
+		{name: "open object", raw: `{`, want: `{}`, ok: true},
+		{name: "partial key", raw: `{"na`, want: `{}`, ok: true},
+		{name: "key without value", raw: `{"name":`, want: `{}`, ok: true},
+		{name: "partial string value", raw: `{"name":"Jo`, want: `{"name":"Jo"}`, ok: true},
+		{name: "trailing escape", raw: `{"name":"a\`, want: `{"name":"a"}`, ok: true},
+		{name: "partial literal after comma", raw: `{"a":1,"b":tr`, want: `{"a":1}`, ok: true},
+		{name: "trailing comma", raw: `{"a":1,`, want: `{"a":1}`, ok: true},
+		{name: "nested array", raw: `{"items":[{"id":1},{"id":2`, want: `{"items":[{"id":1},{"id":2}]}`, ok: true},
+		{name: "brace inside string", raw: `{"s":"{[`, want: `{"s":"{["}`, ok: true},
+		{name: "bare partial literal", raw: `nu`, ok: false},
+		{name: "top-level array", raw: `[1,2,`, want: `[1,2]`, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := Repair(tt.raw)
+			if ok != tt.ok {
+				t.Fatalf("Repair(%q) ok = %v, want %v", tt.raw, ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Fatalf("Repair(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReturnsDecodedValue(t *testing.T) {
+	t.Parallel()
+
+	value, ok := Parse(`{"user":{"name":"Ada","tags":["x","y`)
+	if !ok {
+		t.Fatal("expected partial document to parse")
+	}
+	user := value.(map[string]any)["user"].(map[string]any)
+	if user["name"] != "Ada" {
+		t.Fatalf("name = %v", user["name"])
+	}
+	if tags := user["tags"].([]any); len(tags) != 2 || tags[1] != "y" {
+		t.Fatalf("tags = %v", tags)
+	}
+}
@@ -0,0 +1,483 @@
+package schemavalidation
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// maxRefDepth bounds $ref expansion so a schema that refers to itself
+// without consuming any of the instance cannot loop forever.
+const maxRefDepth = 64
+
+// Check validates a decoded JSON value against a JSON Schema (draft 2020-12)
+// and returns every violation found, or nil when the value conforms.
+//
+// It implements the assertion keywords: type (plus the OpenAPI nullable
+// extension), enum, const, the string, number, object and array bounds,
+// properties, patternProperties, additionalProperties, required,
+// dependentRequired, propertyNames, prefixItems, items, contains,
+// uniqueItems, allOf, anyOf, oneOf, not, if/then/else, and local $ref
+// pointers ("#", "#/$defs/...", "#/definitions/..."). Annotation keywords
+// such as format and description are ignored, as the specification allows.
+func Check(instance any, schema map[string]any) []types.SchemaViolation {
+	if schema == nil {
+		return nil
+	}
+	c := &instanceChecker{root: schema, patterns: map[string]*regexp.Regexp{}}
+	return c.check(instance, schema, "", 0)
+}
+
+type instanceChecker struct {
+	root     map[string]any
+	patterns map[string]*regexp.Regexp
+}
+
+func (c *instanceChecker) check(instance any, schema any, path string, depth int) []types.SchemaViolation {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			return []types.SchemaViolation{violation(path, "false", "no value is allowed here")}
+		}
+		return nil
+	case map[string]any:
+		return c.checkMap(instance, s, path, depth)
+	}
+	return nil
+}
+
+func (c *instanceChecker) checkMap(instance any, schema map[string]any, path string, depth int) []types.SchemaViolation {
+	var out []types.SchemaViolation
+
+	if ref, ok := schema["$ref"].(string); ok && depth < maxRefDepth {
+		if target, found := c.resolve(ref); found {
+			out = append(out, c.check(instance, target, path, depth+1)...)
+		}
+	}
+
+	// A value of the wrong type makes every other keyword noise.
+	if v, ok := checkType(instance, schema, path); !ok {
+		return append(out, v)
+	}
+	if instance == nil && schema["nullable"] == true {
+		return out
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !containsJSON(enum, instance) {
+		out = append(out, violation(path, "enum", "must be one of "+describeValues(enum)))
+	}
+	if constant, ok := schema["const"]; ok && !equalJSON(constant, instance) {
+		out = append(out, violation(path, "const", "must equal "+describeValues([]any{constant})))
+	}
+
+	switch v := instance.(type) {
+	case string:
+		out = append(out, c.checkString(v, schema, path)...)
+	case map[string]any:
+		out = append(out, c.checkObject(v, schema, path, depth)...)
+	case []any:
+		out = append(out, c.checkArray(v, schema, path, depth)...)
+	default:
+		if n, ok := toFloat(instance); ok {
+			out = append(out, checkNumber(n, schema, path)...)
+		}
+	}
+
+	return append(out, c.checkCombinators(instance, schema, path, depth)...)
+}
+
+func (c *instanceChecker) checkCombinators(instance any, schema map[string]any, path string, depth int) []types.SchemaViolation {
+	var out []types.SchemaViolation
+	if branches, ok := schema["allOf"].([]any); ok {
+		for _, branch := range branches {
+			out = append(out, c.check(instance, branch, path, depth)...)
+		}
+	}
+	if branches, ok := schema["anyOf"].([]any); ok && c.countMatches(instance, branches, path, depth) == 0 {
+		out = append(out, violation(path, "anyOf", "does not match any of the allowed schemas"))
+	}
+	if branches, ok := schema["oneOf"].([]any); ok {
+		if n := c.countMatches(instance, branches, path, depth); n != 1 {
+			out = append(out, violation(path, "oneOf", fmt.Sprintf("must match exactly one schema, matched %d", n)))
+		}
+	}
+	if not, ok := schema["not"]; ok && len(c.check(instance, not, path, depth)) == 0 {
+		out = append(out, violation(path, "not", "must not match the schema under not"))
+	}
+	if cond, ok := schema["if"]; ok {
+		if len(c.check(instance, cond, path, depth)) == 0 {
+			if then, ok := schema["then"]; ok {
+				out = append(out, c.check(instance, then, path, depth)...)
+			}
+		} else if otherwise, ok := schema["else"]; ok {
+			out = append(out, c.check(instance, otherwise, path, depth)...)
+		}
+	}
+	return out
+}
+
+func (c *instanceChecker) countMatches(instance any, branches []any, path string, depth int) int {
+	n := 0
+	for _, branch := range branches {
+		if len(c.check(instance, branch, path, depth)) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *instanceChecker) checkString(s string, schema map[string]any, path string) []types.SchemaViolation {
+	var out []types.SchemaViolation
+	length := utf8.RuneCountInString(s)
+	if n, ok := intKeyword(schema, "minLength"); ok && length < n {
+		out = append(out, violation(path, "minLength", fmt.Sprintf("must be at least %d characters, got %d", n, length)))
+	}
+	if n, ok := intKeyword(schema, "maxLength"); ok && length > n {
+		out = append(out, violation(path, "maxLength", fmt.Sprintf("must be at most %d characters, got %d", n, length)))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re := c.pattern(pattern); re != nil && !re.MatchString(s) {
+			out = append(out, violation(path, "pattern", fmt.Sprintf("must match %q", pattern)))
+		}
+	}
+	return out
+}
+
+func checkNumber(n float64, schema map[string]any, path string) []types.SchemaViolation {
+	var out []types.SchemaViolation
+	if limit, ok := toFloat(schema["minimum"]); ok && n < limit {
+		out = append(out, violation(path, "minimum", fmt.Sprintf("must be >= %v, got %v", limit, n)))
+	}
+	if limit, ok := toFloat(schema["maximum"]); ok && n > limit {
+		out = append(out, violation(path, "maximum", fmt.Sprintf("must be <= %v, got %v", limit, n)))
+	}
+	if limit, ok := toFloat(schema["exclusiveMinimum"]); ok && n <= limit {
+		out = append(out, violation(path, "exclusiveMinimum", fmt.Sprintf("must be > %v, got %v", limit, n)))
+	}
+	if limit, ok := toFloat(schema["exclusiveMaximum"]); ok && n >= limit {
+		out = append(out, violation(path, "exclusiveMaximum", fmt.Sprintf("must be < %v, got %v", limit, n)))
+	}
+	if step, ok := toFloat(schema["multipleOf"]); ok && step > 0 {
+		if q := n / step; math.Abs(q-math.Round(q)) > 1e-9 {
+			out = append(out, violation(path, "multipleOf", fmt.Sprintf("must be a multiple of %v, got %v", step, n)))
+		}
+	}
+	return out
+}
+
+func (c *instanceChecker) checkObject(object map[string]any, schema map[string]any, path string, depth int) []types.SchemaViolation {
+	var out []types.SchemaViolation
+	for _, name := range stringList(schema["required"]) {
+		if _, ok := object[name]; !ok {
+			out = append(out, violation(path, "required", fmt.Sprintf("missing required property %q", name)))
+		}
+	}
+	if deps, ok := schema["dependentRequired"].(map[string]any); ok {
+		for _, name := range sortedKeys(deps) {
+			if _, present := object[name]; !present {
+				continue
+			}
+			for _, dep := range stringList(deps[name]) {
+				if _, ok := object[dep]; !ok {
+					out = append(out, violation(path, "dependentRequired", fmt.Sprintf("property %q requires %q", name, dep)))
+				}
+			}
+		}
+	}
+	if n, ok := intKeyword(schema, "minProperties"); ok && len(object) < n {
+		out = append(out, violation(path, "minProperties", fmt.Sprintf("must have at least %d properties, got %d", n, len(object))))
+	}
+	if n, ok := intKeyword(schema, "maxProperties"); ok && len(object) > n {
+		out = append(out, violation(path, "maxProperties", fmt.Sprintf("must have at most %d properties, got %d", n, len(object))))
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	patterns, _ := schema["patternProperties"].(map[string]any)
+	extra, hasExtra := schema["additionalProperties"]
+	names, hasNames := schema["propertyNames"]
+	for _, name := range sortedKeys(object) {
+		value := object[name]
+		childPath := path + "/" + escapePointer(name)
+		if hasNames {
+			for _, v := range c.check(name, names, childPath, depth) {
+				v.Keyword = "propertyNames"
+				out = append(out, v)
+			}
+		}
+		matched := false
+		if sub, ok := props[name]; ok {
+			matched = true
+			out = append(out, c.check(value, sub, childPath, depth)...)
+		}
+		for _, pattern := range sortedKeys(patterns) {
+			if re := c.pattern(pattern); re != nil && re.MatchString(name) {
+				matched = true
+				out = append(out, c.check(value, patterns[pattern], childPath, depth)...)
+			}
+		}
+		if matched || !hasExtra {
+			continue
+		}
+		if allowed, ok := extra.(bool); ok {
+			if !allowed {
+				out = append(out, violation(path, "additionalProperties", fmt.Sprintf("property %q is not allowed", name)))
+			}
+			continue
+		}
+		out = append(out, c.check(value, extra, childPath, depth)...)
+	}
+	return out
+}
+
+func (c *instanceChecker) checkArray(array []any, schema map[string]any, path string, depth int) []types.SchemaViolation {
+	var out []types.SchemaViolation
+	if n, ok := intKeyword(schema, "minItems"); ok && len(array) < n {
+		out = append(out, violation(path, "minItems", fmt.Sprintf("must have at least %d items, got %d", n, len(array))))
+	}
+	if n, ok := intKeyword(schema, "maxItems"); ok && len(array) > n {
+		out = append(out, violation(path, "maxItems", fmt.Sprintf("must have at most %d items, got %d", n, len(array))))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+	outer:
+		for i := range array {
+			for j := i + 1; j < len(array); j++ {
+				if equalJSON(array[i], array[j]) {
+					out = append(out, violation(path, "uniqueItems", fmt.Sprintf("items %d and %d are equal", i, j)))
+					break outer
+				}
+			}
+		}
+	}
+
+	prefix, _ := schema["prefixItems"].([]any)
+	for i, item := range array {
+		itemPath := fmt.Sprintf("%s/%d", path, i)
+		if i < len(prefix) {
+			out = append(out, c.check(item, prefix[i], itemPath, depth)...)
+		} else if items, ok := schema["items"]; ok {
+			out = append(out, c.check(item, items, itemPath, depth)...)
+		}
+	}
+
+	if contains, ok := schema["contains"]; ok {
+		matches := 0
+		for i, item := range array {
+			if len(c.check(item, contains, fmt.Sprintf("%s/%d", path, i), depth)) == 0 {
+				matches++
+			}
+		}
+		minContains, hasMin := intKeyword(schema, "minContains")
+		if !hasMin {
+			minContains = 1
+		}
+		if matches < minContains {
+			out = append(out, violation(path, "contains", fmt.Sprintf("must contain at least %d matching items, got %d", minContains, matches)))
+		}
+		if n, ok := intKeyword(schema, "maxContains"); ok && matches > n {
+			out = append(out, violation(path, "maxContains", fmt.Sprintf("must contain at most %d matching items, got %d", n, matches)))
+		}
+	}
+	return out
+}
+
+// resolve follows a local JSON pointer from the schema root.
+func (c *instanceChecker) resolve(ref string) (any, bool) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, false
+	}
+	var current any = c.root
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[token]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// pattern compiles and caches a regular expression; invalid patterns are
+// skipped rather than reported against the value.
+func (c *instanceChecker) pattern(expr string) *regexp.Regexp {
+	if re, ok := c.patterns[expr]; ok {
+		return re
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		re = nil
+	}
+	c.patterns[expr] = re
+	return re
+}
+
+// checkType applies the type keyword, with nullable:true also admitting null.
+func checkType(instance any, schema map[string]any, path string) (types.SchemaViolation, bool) {
+	var allowed []string
+	switch t := schema["type"].(type) {
+	case string:
+		allowed = []string{t}
+	case []any:
+		allowed = stringList(t)
+	case []string:
+		allowed = t
+	default:
+		return types.SchemaViolation{}, true
+	}
+	if instance == nil && schema["nullable"] == true {
+		return types.SchemaViolation{}, true
+	}
+	actual := jsonType(instance)
+	for _, want := range allowed {
+		if want == actual || (want == "number" && actual == "integer") {
+			return types.SchemaViolation{}, true
+		}
+	}
+	return violation(path, "type", fmt.Sprintf("must be %s, got %s", strings.Join(allowed, " or "), actual)), false
+}
+
+// jsonType names the JSON type of a decoded value. Whole numbers are
+// "integer", matching draft 2020-12.
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		if n, ok := toFloat(v); ok {
+			if n == math.Trunc(n) && !math.IsInf(n, 0) {
+				return "integer"
+			}
+			return "number"
+		}
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func intKeyword(schema map[string]any, key string) (int, bool) {
+	n, ok := toFloat(schema[key])
+	return int(n), ok
+}
+
+// equalJSON compares decoded JSON values, treating numbers of any Go type
+// as equal when their values are.
+func equalJSON(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	switch x := a.(type) {
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalJSON(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !equalJSON(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func containsJSON(values []any, value any) bool {
+	for _, v := range values {
+		if equalJSON(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func describeValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			parts[i] = fmt.Sprintf("%q", s)
+		} else {
+			parts[i] = fmt.Sprint(v)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func stringList(value any) []string {
+	switch l := value.(type) {
+	case []string:
+		return l
+	case []any:
+		out := make([]string, 0, len(l))
+		for _, v := range l {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func violation(path, keyword, message string) types.SchemaViolation {
+	return types.SchemaViolation{Path: path, Keyword: keyword, Message: message}
+}
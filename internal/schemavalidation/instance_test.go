@@ -0,0 +1,98 @@
+package schemavalidation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func decode(t *testing.T, text string) any {
+	t.Helper()
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(text), &v))
+	return v
+}
+
+func TestCheckReportsEveryFailingPath(t *testing.T) {
+	t.Parallel()
+	schema := decode(t, `{
+		"type": "object",
+		"$defs": {"tag": {"type": "string", "minLength": 2}},
+		"properties": {
+			"name": {"type": "string"},
+			"email": {"type": "string"},
+			"age": {"type": "integer", "minimum": 0},
+			"mood": {"enum": ["happy", "sad"]},
+			"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}, "uniqueItems": true},
+			"a/b": {"type": "boolean"}
+		},
+		"required": ["name", "email"],
+		"additionalProperties": false
+	}`).(map[string]any)
+	instance := decode(t, `{"age": 3.5, "mood": "angry", "tags": ["ok", "x", "ok"], "a/b": "yes", "extra": 1}`)
+
+	got := Check(instance, schema)
+	assert.Equal(t, []types.SchemaViolation{
+		{Path: "", Keyword: "required", Message: `missing required property "name"`},
+		{Path: "", Keyword: "required", Message: `missing required property "email"`},
+		{Path: "/a~1b", Keyword: "type", Message: "must be boolean, got string"},
+		{Path: "/age", Keyword: "type", Message: "must be integer, got number"},
+		{Path: "", Keyword: "additionalProperties", Message: `property "extra" is not allowed`},
+		{Path: "/mood", Keyword: "enum", Message: `must be one of "happy", "sad"`},
+		{Path: "/tags", Keyword: "uniqueItems", Message: "items 0 and 2 are equal"},
+		{Path: "/tags/1", Keyword: "minLength", Message: "must be at least 2 characters, got 1"},
+	}, got)
+
+	assert.Empty(t, Check(decode(t, `{"name": "Ada", "email": "a@b", "age": 36, "tags": ["go"]}`), schema))
+}
+
+func TestCheckKeywords(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		keyword  string
+	}{
+		{"integer accepts whole float", `{"type":"integer"}`, `2.0`, ""},
+		{"number accepts integer", `{"type":"number"}`, `2`, ""},
+		{"type list", `{"type":["string","null"]}`, `null`, ""},
+		{"nullable", `{"type":"string","nullable":true}`, `null`, ""},
+		{"const", `{"const":3}`, `4`, "const"},
+		{"maximum", `{"maximum":10}`, `11`, "maximum"},
+		{"exclusiveMinimum", `{"exclusiveMinimum":0}`, `0`, "exclusiveMinimum"},
+		{"multipleOf", `{"multipleOf":0.5}`, `1.25`, "multipleOf"},
+		{"pattern", `{"pattern":"^[a-z]+$"}`, `"Abc"`, "pattern"},
+		{"maxItems", `{"maxItems":1}`, `[1,2]`, "maxItems"},
+		{"prefixItems", `{"prefixItems":[{"type":"string"}],"items":{"type":"integer"}}`, `["a",1,"b"]`, "type"},
+		{"contains", `{"contains":{"type":"string"}}`, `[1,2]`, "contains"},
+		{"anyOf", `{"anyOf":[{"type":"string"},{"type":"integer"}]}`, `true`, "anyOf"},
+		{"oneOf", `{"oneOf":[{"type":"number"},{"type":"integer"}]}`, `1`, "oneOf"},
+		{"not", `{"not":{"type":"string"}}`, `"s"`, "not"},
+		{"if then", `{"if":{"properties":{"kind":{"const":"a"}}},"then":{"required":["a"]}}`, `{"kind":"a"}`, "required"},
+		{"if else", `{"if":{"properties":{"kind":{"const":"a"}}},"else":{"required":["b"]}}`, `{"kind":"c"}`, "required"},
+		{"dependentRequired", `{"dependentRequired":{"card":["cvv"]}}`, `{"card":"1"}`, "dependentRequired"},
+		{"propertyNames", `{"propertyNames":{"maxLength":3}}`, `{"long":1}`, "propertyNames"},
+		{"patternProperties", `{"patternProperties":{"^x-":{"type":"string"}},"additionalProperties":false}`, `{"x-a":1}`, "type"},
+		{"additionalProperties schema", `{"properties":{"a":{}},"additionalProperties":{"type":"string"}}`, `{"a":1,"b":2}`, "type"},
+		{"false schema", `{"properties":{"a":false}}`, `{"a":1}`, "false"},
+		{"recursive ref", `{"type":"object","properties":{"child":{"$ref":"#"}},"required":["id"]}`, `{"id":1,"child":{"id":2,"child":{}}}`, "required"},
+		{"format is an annotation", `{"type":"string","format":"email"}`, `"not an email"`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Check(decode(t, tt.instance), decode(t, tt.schema).(map[string]any))
+			if tt.keyword == "" {
+				assert.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1, "%v", got)
+			assert.Equal(t, tt.keyword, got[0].Keyword)
+		})
+	}
+}
@@ -161,23 +161,12 @@ func (p *proxy) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fr := string(normalizedFinishReason(resp.FinishReason))
-
-	msg := &ChatMessage{Role: "assistant", Content: resp.Text, Refusal: resp.Refusal}
-	if len(resp.ToolCalls) > 0 {
-		msg.ToolCalls = fromWormholeToolCalls(resp.ToolCalls)
-	}
-
 	out := ChatCompletionResponse{
 		ID:      fmt.Sprintf("wh-%s", resp.ID),
 		Object:  "chat.completion",
 		Created: resp.Created.Unix(),
 		Model:   model,
-		Choices: []ChatChoice{{
-			Index:        0,
-			Message:      msg,
-			FinishReason: &fr,
-		}},
+		Choices: toChatChoices(resp),
 	}
 	if resp.Usage != nil {
 		out.Usage = toChatUsage(resp.Usage)
@@ -185,6 +174,36 @@ func (p *proxy) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, out)
 }
 
+// toChatChoices renders every completion in resp, or its single top-level
+// completion when it has no Choices.
+func toChatChoices(resp *types.TextResponse) []ChatChoice {
+	choices := resp.Choices
+	if len(choices) == 0 {
+		choices = []types.Choice{{
+			Text:         resp.Text,
+			Refusal:      resp.Refusal,
+			ToolCalls:    resp.ToolCalls,
+			FinishReason: resp.FinishReason,
+			Logprobs:     resp.Logprobs,
+		}}
+	}
+	out := make([]ChatChoice, len(choices))
+	for i, choice := range choices {
+		fr := string(normalizedFinishReason(choice.FinishReason))
+		msg := &ChatMessage{Role: "assistant", Content: choice.Text, Refusal: choice.Refusal}
+		if len(choice.ToolCalls) > 0 {
+			msg.ToolCalls = fromWormholeToolCalls(choice.ToolCalls)
+		}
+		out[i] = ChatChoice{
+			Index:        i,
+			Message:      msg,
+			Logprobs:     toChatLogprobs(choice.Logprobs),
+			FinishReason: &fr,
+		}
+	}
+	return out
+}
+
 func applyChatGenerationControls(builder *wormhole.TextRequestBuilder, req ChatCompletionRequest) *wormhole.TextRequestBuilder {
 	if req.Temperature != nil {
 		builder = builder.Temperature(float32(*req.Temperature))
@@ -209,6 +228,14 @@ func applyChatGenerationControls(builder *wormhole.TextRequestBuilder, req ChatC
 	if req.ParallelToolCalls != nil {
 		builder = builder.ParallelToolCalls(*req.ParallelToolCalls)
 	}
+	if req.TopLogprobs != nil {
+		builder = builder.TopLogprobs(*req.TopLogprobs)
+	} else if req.Logprobs {
+		builder = builder.Logprobs(true)
+	}
+	if req.N != nil {
+		builder = builder.N(*req.N)
+	}
 	if len(req.Stop) > 0 {
 		builder = builder.Stop(req.Stop...)
 	}
@@ -216,8 +243,11 @@ func applyChatGenerationControls(builder *wormhole.TextRequestBuilder, req ChatC
 }
 
 func validateChatControls(req ChatCompletionRequest, provider string) error {
-	if req.N != nil && *req.N != 1 {
-		return fmt.Errorf("n=%d is unsupported; the proxy currently returns exactly one choice", *req.N)
+	if req.N != nil && (*req.N < 1 || *req.N > 128) {
+		return fmt.Errorf("n must be between 1 and 128")
+	}
+	if req.N != nil && *req.N > 1 && req.Stream {
+		return fmt.Errorf("n=%d cannot be streamed; the proxy streams exactly one choice", *req.N)
 	}
 	if req.FrequencyPenalty != nil && (*req.FrequencyPenalty < -2 || *req.FrequencyPenalty > 2) {
 		return fmt.Errorf("frequency_penalty must be between -2.0 and 2.0")
@@ -225,6 +255,9 @@ func validateChatControls(req ChatCompletionRequest, provider string) error {
 	if req.PresencePenalty != nil && (*req.PresencePenalty < -2 || *req.PresencePenalty > 2) {
 		return fmt.Errorf("presence_penalty must be between -2.0 and 2.0")
 	}
+	if req.TopLogprobs != nil && (*req.TopLogprobs < 0 || *req.TopLogprobs > 20) {
+		return fmt.Errorf("top_logprobs must be between 0 and 20")
+	}
 	if provider == "anthropic" && (req.FrequencyPenalty != nil || req.PresencePenalty != nil || req.Seed != nil) {
 		return fmt.Errorf("frequency_penalty, presence_penalty, and seed are unsupported for Anthropic")
 	}
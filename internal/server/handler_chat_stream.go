@@ -59,8 +59,9 @@ func (p *proxy) streamChat(w http.ResponseWriter, r *http.Request, builder *worm
 			Created: time.Now().Unix(),
 			Model:   model,
 			Choices: []ChatChoice{{
-				Index: 0,
-				Delta: delta,
+				Index:    0,
+				Delta:    delta,
+				Logprobs: toChatLogprobs(chunk.Logprobs),
 			}},
 		}
 
@@ -60,7 +60,7 @@ func upstreamErrorStatus(err error) (int, string, string) {
 		return http.StatusTooManyRequests, errType, upstreamClientMessage(errType)
 	case types.ErrorCodeTimeout:
 		return http.StatusGatewayTimeout, errType, upstreamClientMessage(errType)
-	case types.ErrorCodeModel, types.ErrorCodeRequest, types.ErrorCodeValidation:
+	case types.ErrorCodeModel, types.ErrorCodeRequest, types.ErrorCodeValidation, types.ErrorCodeContentBlocked, types.ErrorCodeRefusal:
 		return http.StatusBadRequest, errType, actionableInvalidRequestMessage(whErr)
 	default:
 		return http.StatusBadGateway, errType, upstreamClientMessage(errType)
@@ -87,7 +87,7 @@ func wormholeErrorType(code types.ErrorCode) string {
 		return "authentication_error"
 	case types.ErrorCodeRateLimit:
 		return "rate_limit_error"
-	case types.ErrorCodeModel, types.ErrorCodeRequest, types.ErrorCodeValidation:
+	case types.ErrorCodeModel, types.ErrorCodeRequest, types.ErrorCodeValidation, types.ErrorCodeContentBlocked, types.ErrorCodeRefusal:
 		return "invalid_request_error"
 	default:
 		return "api_error"
@@ -20,7 +20,17 @@ type Config struct {
 	DefaultProvider string
 	WormholeOpts    []wormhole.Option
 	ProxyAPIKey     string
-	Logger          *slog.Logger
+	// APIKeySetting names where ProxyAPIKey comes from, for the warning
+	// logged when it is empty (default "WORMHOLE_API_KEY").
+	APIKeySetting string
+	// LoopbackOnly, when ProxyAPIKey is empty, refuses /v1/ requests from
+	// clients that are not on a loopback address. Start enforces the same at
+	// bind time; this covers the Handler mounted in someone else's server.
+	LoopbackOnly bool
+	Logger       *slog.Logger
+	// Client, when set, is served instead of a client built from
+	// WormholeOpts. The caller keeps ownership: Shutdown does not close it.
+	Client *wormhole.Wormhole
 }
 
 type proxy struct {
@@ -28,7 +38,9 @@ type proxy struct {
 	server          *http.Server
 	logger          *slog.Logger
 	apiKey          string
+	loopbackOnly    bool
 	defaultProvider string
+	ownsClient      bool
 }
 
 // New creates and wires a new proxy server from the given config.
@@ -42,21 +54,33 @@ func New(cfg Config) *proxy {
 		cfg.Addr = "127.0.0.1:8080"
 	}
 
-	opts := make([]wormhole.Option, len(cfg.WormholeOpts))
-	copy(opts, cfg.WormholeOpts)
-	if cfg.DefaultProvider != "" {
-		opts = append(opts, wormhole.WithDefaultProvider(cfg.DefaultProvider))
-	}
-
 	p := &proxy{
-		wh:              wormhole.New(opts...),
+		wh:              cfg.Client,
 		logger:          cfg.Logger,
 		apiKey:          cfg.ProxyAPIKey,
+		loopbackOnly:    cfg.LoopbackOnly,
 		defaultProvider: cfg.DefaultProvider,
 	}
+	if p.wh == nil {
+		opts := make([]wormhole.Option, len(cfg.WormholeOpts))
+		copy(opts, cfg.WormholeOpts)
+		if cfg.DefaultProvider != "" {
+			opts = append(opts, wormhole.WithDefaultProvider(cfg.DefaultProvider))
+		}
+		p.wh = wormhole.New(opts...)
+		p.ownsClient = true
+	}
 
 	if p.apiKey == "" {
-		p.logger.Warn("proxy authentication disabled: WORMHOLE_API_KEY not set; /v1/ endpoints are unauthenticated")
+		setting := cfg.APIKeySetting
+		if setting == "" {
+			setting = "WORMHOLE_API_KEY"
+		}
+		if p.loopbackOnly {
+			p.logger.Warn("proxy authentication disabled: " + setting + " not set; /v1/ endpoints only answer loopback clients")
+		} else {
+			p.logger.Warn("proxy authentication disabled: " + setting + " not set; /v1/ endpoints are unauthenticated")
+		}
 	}
 
 	mux := http.NewServeMux()
@@ -108,15 +132,30 @@ func isLoopbackAddr(addr string) bool {
 	return ip != nil && ip.IsLoopback()
 }
 
-// Shutdown gracefully stops the HTTP server and the wormhole client.
+// Handler returns the authenticated route mux so the proxy can be mounted in
+// an existing HTTP server instead of listening on its own address.
+func (p *proxy) Handler() http.Handler {
+	return p.server.Handler
+}
+
+// Shutdown gracefully stops the HTTP server and, when the proxy built it, the
+// wormhole client.
 func (p *proxy) Shutdown(ctx context.Context) error {
 	serverErr := p.server.Shutdown(ctx)
+	if !p.ownsClient {
+		return serverErr
+	}
 	wormholeErr := p.wh.Shutdown(ctx)
 	return errors.Join(serverErr, wormholeErr)
 }
 
 func (p *proxy) auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p.apiKey == "" && p.loopbackOnly && strings.HasPrefix(r.URL.Path, "/v1/") && !isLoopbackAddr(r.RemoteAddr) {
+			writeError(w, http.StatusForbidden, "unauthenticated_remote_client",
+				"Authentication is not configured; only loopback clients are served", "permission_error")
+			return
+		}
 		if p.apiKey != "" && strings.HasPrefix(r.URL.Path, "/v1/") {
 			auth := r.Header.Get("Authorization")
 			token := strings.TrimPrefix(auth, "Bearer ")
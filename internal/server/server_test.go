@@ -75,6 +75,26 @@ func TestProxyShutdownPropagatesWormholeError(t *testing.T) {
 	require.ErrorIs(t, p.Shutdown(context.Background()), wantErr)
 }
 
+func TestProxyShutdownLeavesCallerOwnedClientOpen(t *testing.T) {
+	t.Parallel()
+
+	mock := wmtest.NewMockProvider("openai").WithTextResponse(wmtest.TextResponseWith("still open"))
+	client := wormhole.New(
+		wormhole.WithCustomProvider("openai", wmtest.MockProviderFactory(mock)),
+		wormhole.WithProviderConfig("openai", types.ProviderConfig{}),
+		wormhole.WithDefaultProvider("openai"),
+		wormhole.WithDiscovery(false),
+	)
+	defer client.Close()
+
+	p := New(Config{Client: client, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	resp, err := client.Text().Model("gpt-test").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "still open", resp.Text)
+}
+
 type capturingTextProvider struct {
 	*wmtest.MockProvider
 	mu       sync.Mutex
@@ -332,7 +352,8 @@ func TestProxyChatSamplingControlsReachSDKRequest(t *testing.T) {
 		"presence_penalty":-0.3,
 		"seed":42,
 		"n":1,
-		"parallel_tool_calls":false
+		"parallel_tool_calls":false,
+		"top_logprobs":3
 	}`)
 
 	require.Equal(t, http.StatusOK, rec.Code)
@@ -345,6 +366,34 @@ func TestProxyChatSamplingControlsReachSDKRequest(t *testing.T) {
 	assert.InDelta(t, -0.3, *request.PresencePenalty, 0.00001)
 	assert.Equal(t, 42, *request.Seed)
 	assert.False(t, *request.ParallelToolCalls)
+	assert.True(t, request.Logprobs)
+	assert.Equal(t, 3, request.TopLogprobs)
+}
+
+func TestProxyChatReturnsMultipleChoices(t *testing.T) {
+	t.Parallel()
+
+	provider := &capturingTextProvider{MockProvider: wmtest.NewMockProvider("openai").WithTextResponse(types.TextResponse{
+		ID:           "chat-n",
+		Text:         "4",
+		FinishReason: types.FinishReasonStop,
+		Choices: []types.Choice{
+			{Index: 0, Text: "4", FinishReason: types.FinishReasonStop},
+			{Index: 1, Text: "four", FinishReason: types.FinishReasonLength},
+		},
+	})}
+	p := newCapturingTestProxy(provider)
+	rec := performRequest(p, http.MethodPost, "/v1/chat/completions",
+		`{"model":"openai/gpt-test","messages":[{"role":"user","content":"2+2?"}],"n":2}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, provider.lastRequest().N)
+	var out ChatCompletionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+	require.Len(t, out.Choices, 2)
+	assert.Equal(t, 1, out.Choices[1].Index)
+	assert.Equal(t, "four", out.Choices[1].Message.Content)
+	assert.Equal(t, "length", *out.Choices[1].FinishReason)
 }
 
 func TestProxyRejectsUnsupportedSamplingControls(t *testing.T) {
@@ -354,10 +403,12 @@ func TestProxyRejectsUnsupportedSamplingControls(t *testing.T) {
 		name string
 		body string
 	}{
-		{name: "multiple choices", body: `{"model":"gpt-test","messages":[{"role":"user","content":"hi"}],"n":2}`},
+		{name: "streamed choices", body: `{"model":"gpt-test","messages":[{"role":"user","content":"hi"}],"n":2,"stream":true}`},
+		{name: "too many choices", body: `{"model":"gpt-test","messages":[{"role":"user","content":"hi"}],"n":129}`},
 		{name: "zero choices", body: `{"model":"gpt-test","messages":[{"role":"user","content":"hi"}],"n":0}`},
 		{name: "frequency range", body: `{"model":"gpt-test","messages":[{"role":"user","content":"hi"}],"frequency_penalty":2.1}`},
 		{name: "presence range", body: `{"model":"gpt-test","messages":[{"role":"user","content":"hi"}],"presence_penalty":-2.1}`},
+		{name: "top logprobs range", body: `{"model":"gpt-test","messages":[{"role":"user","content":"hi"}],"top_logprobs":21}`},
 		{name: "anthropic seed", body: `{"model":"anthropic/claude-test","messages":[{"role":"user","content":"hi"}],"seed":1}`},
 		{name: "gemini parallel", body: `{"model":"gemini/gemini-test","messages":[{"role":"user","content":"hi"}],"parallel_tool_calls":false}`},
 		{name: "ollama parallel", body: `{"model":"ollama/llama-test","messages":[{"role":"user","content":"hi"}],"parallel_tool_calls":true}`},
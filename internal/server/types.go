@@ -22,6 +22,8 @@ type ChatCompletionRequest struct {
 	Seed                *int                           `json:"seed,omitempty"`
 	N                   *int                           `json:"n,omitempty"`
 	ParallelToolCalls   *bool                          `json:"parallel_tool_calls,omitempty"`
+	Logprobs            bool                           `json:"logprobs,omitempty"`
+	TopLogprobs         *int                           `json:"top_logprobs,omitempty"`
 	Stop                []string                       `json:"stop,omitempty"`
 	Stream              bool                           `json:"stream,omitempty"`
 	Tools               []ChatTool                     `json:"tools,omitempty"`
@@ -160,10 +162,23 @@ type ChatCompletionResponse struct {
 
 // ChatChoice is a single choice in a chat completion response.
 type ChatChoice struct {
-	Index        int          `json:"index"`
-	Message      *ChatMessage `json:"message,omitempty"`
-	Delta        *ChatMessage `json:"delta,omitempty"`
-	FinishReason *string      `json:"finish_reason,omitempty"`
+	Index        int           `json:"index"`
+	Message      *ChatMessage  `json:"message,omitempty"`
+	Delta        *ChatMessage  `json:"delta,omitempty"`
+	Logprobs     *ChatLogprobs `json:"logprobs,omitempty"`
+	FinishReason *string       `json:"finish_reason,omitempty"`
+}
+
+// ChatLogprobs holds a choice's per-token log probabilities.
+type ChatLogprobs struct {
+	Content []types.TokenLogprob `json:"content"`
+}
+
+func toChatLogprobs(logprobs []types.TokenLogprob) *ChatLogprobs {
+	if len(logprobs) == 0 {
+		return nil
+	}
+	return &ChatLogprobs{Content: logprobs}
 }
 
 // ChatUsage is token usage in OpenAI format.
@@ -12,3 +12,34 @@ func MockOpenAIServer(t *testing.T, handler func(w http.ResponseWriter, r *http.
 	t.Cleanup(server.Close)
 	return server
 }
+
+// DisconnectingServer creates a server that writes partial as a streaming
+// response, flushes it, and then drops the connection without finishing the
+// body, simulating a mid-stream network failure.
+func DisconnectingServer(t *testing.T, contentType, partial string) *httptest.Server {
+	return MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(partial))
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				_ = conn.Close()
+			}
+		}
+	})
+}
+
+// StallingServer creates a server that writes partial as a streaming response
+// and then holds the connection open until the client goes away.
+func StallingServer(t *testing.T, contentType, partial string) *httptest.Server {
+	return MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(partial))
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		<-r.Context().Done()
+	})
+}
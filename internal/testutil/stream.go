@@ -2,6 +2,7 @@ package testutil
 
 import (
 	"strings"
+	"time"
 
 	"github.com/garyblankenship/wormhole/v2/types"
 )
@@ -21,9 +22,7 @@ func MergeTextChunks(chunks []types.TextChunk) *types.TextResponse {
 		if chunk.Model != "" {
 			model = chunk.Model
 		}
-		if chunk.Text != "" {
-			text.WriteString(chunk.Text)
-		}
+		text.WriteString(chunk.Content())
 		if chunk.FinishReason != nil {
 			finishReason = *chunk.FinishReason
 		}
@@ -47,3 +46,21 @@ func MergeTextChunks(chunks []types.TextChunk) *types.TextResponse {
 		Usage:        usage,
 	}
 }
+
+// DrainStream reads chunks until the stream closes or timeout elapses. The
+// second result reports whether the channel closed in time.
+func DrainStream(stream <-chan types.TextChunk, timeout time.Duration) ([]types.TextChunk, bool) {
+	var chunks []types.TextChunk
+	deadline := time.After(timeout)
+	for {
+		select {
+		case chunk, ok := <-stream:
+			if !ok {
+				return chunks, true
+			}
+			chunks = append(chunks, chunk)
+		case <-deadline:
+			return chunks, false
+		}
+	}
+}
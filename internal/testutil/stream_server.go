@@ -0,0 +1,291 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// StreamFormat selects the wire format a StreamScript is rendered in.
+type StreamFormat string
+
+const (
+	// OpenAIStream is Chat Completions chunks ending in "data: [DONE]".
+	OpenAIStream StreamFormat = "openai"
+	// AnthropicStream is Messages API events, message_start to message_stop.
+	AnthropicStream StreamFormat = "anthropic"
+	// GeminiStream is streamGenerateContent with alt=sse.
+	GeminiStream StreamFormat = "gemini"
+)
+
+// StreamScript is a provider-neutral description of one streamed reply.
+// StreamServer and StreamEvents render it the way each provider sends it, so
+// parser tests share one script instead of hand-writing events per package.
+type StreamScript struct {
+	// ID and Model default to "stream-1" and "test-model".
+	ID    string
+	Model string
+	// Text is sent as one delta per element.
+	Text []string
+	// ToolCalls are sent after the text.
+	ToolCalls []StreamToolCall
+	// FinishReason defaults to stop, or tool_calls when ToolCalls is set.
+	// It is sent in the provider's own wording (end_turn, MAX_TOKENS, ...).
+	FinishReason types.FinishReason
+	// Usage, when set, is reported where the provider reports it.
+	Usage *types.Usage
+	// Error, when set, is sent as the provider's in-band error event after
+	// the deltas, in place of the finish events.
+	Error string
+	// Truncate ends the body after the deltas, before any finish event, as
+	// an upstream that closes the stream early does.
+	Truncate bool
+}
+
+// StreamToolCall is one tool call in a StreamScript.
+type StreamToolCall struct {
+	ID   string
+	Name string
+	// Arguments are JSON fragments that together form the arguments object.
+	// OpenAI and Anthropic stream them one per event; Gemini sends the
+	// parsed object whole.
+	Arguments []string
+}
+
+// StreamServer starts a server that answers every request with script in
+// format. It is closed when the test ends.
+func StreamServer(t *testing.T, format StreamFormat, script StreamScript) *httptest.Server {
+	t.Helper()
+	events, err := StreamEvents(format, script)
+	if err != nil {
+		t.Fatalf("stream script: %v", err)
+	}
+	return MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher, _ := w.(http.Flusher)
+		for _, event := range events {
+			_, _ = w.Write([]byte(event))
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+// StreamEvents renders script as SSE frames in format, each ending in a
+// blank line.
+func StreamEvents(format StreamFormat, script StreamScript) ([]string, error) {
+	if script.ID == "" {
+		script.ID = "stream-1"
+	}
+	if script.Model == "" {
+		script.Model = "test-model"
+	}
+	if script.FinishReason == "" {
+		script.FinishReason = types.FinishReasonStop
+		if len(script.ToolCalls) > 0 {
+			script.FinishReason = types.FinishReasonToolCalls
+		}
+	}
+	var s sseWriter
+	var err error
+	switch format {
+	case OpenAIStream:
+		err = writeOpenAIStream(&s, script)
+	case AnthropicStream:
+		err = writeAnthropicStream(&s, script)
+	case GeminiStream:
+		err = writeGeminiStream(&s, script)
+	default:
+		err = fmt.Errorf("unknown stream format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.events, nil
+}
+
+type sseWriter struct {
+	events []string
+}
+
+func (s *sseWriter) data(payload any) {
+	s.event("", payload)
+}
+
+func (s *sseWriter) event(name string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		panic(fmt.Sprintf("testutil: marshal stream event: %v", err)) // payloads are built from maps of plain values
+	}
+	frame := "data: " + string(data) + "\n\n"
+	if name != "" {
+		frame = "event: " + name + "\n" + frame
+	}
+	s.events = append(s.events, frame)
+}
+
+type object = map[string]any
+
+func writeOpenAIStream(s *sseWriter, script StreamScript) error {
+	chunk := func(delta object, finish any) object {
+		return object{
+			"id": script.ID, "object": "chat.completion.chunk", "created": 1700000000, "model": script.Model,
+			"choices": []any{object{"index": 0, "delta": delta, "finish_reason": finish}},
+		}
+	}
+	s.data(chunk(object{"role": "assistant", "content": ""}, nil))
+	for _, text := range script.Text {
+		s.data(chunk(object{"content": text}, nil))
+	}
+	for i, call := range script.ToolCalls {
+		s.data(chunk(object{"tool_calls": []any{object{
+			"index": i, "id": call.ID, "type": "function",
+			"function": object{"name": call.Name, "arguments": ""},
+		}}}, nil))
+		for _, fragment := range call.Arguments {
+			s.data(chunk(object{"tool_calls": []any{object{"index": i, "function": object{"arguments": fragment}}}}, nil))
+		}
+	}
+	if script.Error != "" {
+		s.data(object{"error": object{"message": script.Error, "type": "server_error"}})
+		return nil
+	}
+	if script.Truncate {
+		return nil
+	}
+	s.data(chunk(object{}, string(script.FinishReason)))
+	if u := script.Usage; u != nil {
+		usage := object{"prompt_tokens": u.PromptTokens, "completion_tokens": u.CompletionTokens, "total_tokens": u.TotalTokens}
+		if u.CacheReadTokens > 0 {
+			usage["prompt_tokens_details"] = object{"cached_tokens": u.CacheReadTokens}
+		}
+		if u.ReasoningTokens > 0 {
+			usage["completion_tokens_details"] = object{"reasoning_tokens": u.ReasoningTokens}
+		}
+		s.data(object{"id": script.ID, "object": "chat.completion.chunk", "created": 1700000000, "model": script.Model, "choices": []any{}, "usage": usage})
+	}
+	s.events = append(s.events, "data: [DONE]\n\n")
+	return nil
+}
+
+func writeAnthropicStream(s *sseWriter, script StreamScript) error {
+	var usage types.Usage
+	if script.Usage != nil {
+		usage = *script.Usage
+	}
+	startUsage := object{"input_tokens": usage.PromptTokens, "output_tokens": 1}
+	if usage.CacheReadTokens > 0 {
+		startUsage["cache_read_input_tokens"] = usage.CacheReadTokens
+	}
+	if usage.CacheWriteTokens > 0 {
+		startUsage["cache_creation_input_tokens"] = usage.CacheWriteTokens
+	}
+	s.event("message_start", object{"type": "message_start", "message": object{
+		"id": script.ID, "type": "message", "role": "assistant", "model": script.Model,
+		"content": []any{}, "stop_reason": nil, "stop_sequence": nil, "usage": startUsage,
+	}})
+
+	index := 0
+	if len(script.Text) > 0 {
+		s.event("content_block_start", object{"type": "content_block_start", "index": index, "content_block": object{"type": "text", "text": ""}})
+		for _, text := range script.Text {
+			s.event("content_block_delta", object{"type": "content_block_delta", "index": index, "delta": object{"type": "text_delta", "text": text}})
+		}
+		s.event("content_block_stop", object{"type": "content_block_stop", "index": index})
+		index++
+	}
+	for _, call := range script.ToolCalls {
+		s.event("content_block_start", object{"type": "content_block_start", "index": index, "content_block": object{
+			"type": "tool_use", "id": call.ID, "name": call.Name, "input": object{},
+		}})
+		for _, fragment := range call.Arguments {
+			s.event("content_block_delta", object{"type": "content_block_delta", "index": index, "delta": object{"type": "input_json_delta", "partial_json": fragment}})
+		}
+		s.event("content_block_stop", object{"type": "content_block_stop", "index": index})
+		index++
+	}
+	if script.Error != "" {
+		s.event("error", object{"type": "error", "error": object{"type": "overloaded_error", "message": script.Error}})
+		return nil
+	}
+	if script.Truncate {
+		return nil
+	}
+
+	stopReason, ok := map[types.FinishReason]string{
+		types.FinishReasonStop:          "end_turn",
+		types.FinishReasonLength:        "max_tokens",
+		types.FinishReasonToolCalls:     "tool_use",
+		types.FinishReasonContentFilter: "refusal",
+	}[script.FinishReason]
+	if !ok {
+		return fmt.Errorf("anthropic has no stop reason for %q", script.FinishReason)
+	}
+	s.event("message_delta", object{
+		"type":  "message_delta",
+		"delta": object{"stop_reason": stopReason, "stop_sequence": nil},
+		"usage": object{"output_tokens": usage.CompletionTokens},
+	})
+	s.event("message_stop", object{"type": "message_stop"})
+	return nil
+}
+
+func writeGeminiStream(s *sseWriter, script StreamScript) error {
+	response := func(parts []any, finish string) object {
+		candidate := object{"content": object{"role": "model", "parts": parts}, "index": 0}
+		if finish != "" {
+			candidate["finishReason"] = finish
+		}
+		return object{"candidates": []any{candidate}, "modelVersion": script.Model, "responseId": script.ID}
+	}
+	for _, text := range script.Text {
+		s.data(response([]any{object{"text": text}}, ""))
+	}
+	if len(script.ToolCalls) > 0 {
+		parts := make([]any, 0, len(script.ToolCalls))
+		for _, call := range script.ToolCalls {
+			var args any
+			if err := json.Unmarshal([]byte(strings.Join(call.Arguments, "")), &args); err != nil {
+				return fmt.Errorf("tool call %s arguments: %w", call.Name, err)
+			}
+			parts = append(parts, object{"functionCall": object{"name": call.Name, "args": args}})
+		}
+		s.data(response(parts, ""))
+	}
+	if script.Error != "" {
+		s.data(object{"error": object{"code": 503, "message": script.Error, "status": "UNAVAILABLE"}})
+		return nil
+	}
+	if script.Truncate {
+		return nil
+	}
+
+	finish, ok := map[types.FinishReason]string{
+		types.FinishReasonStop:          "STOP",
+		types.FinishReasonLength:        "MAX_TOKENS",
+		types.FinishReasonToolCalls:     "STOP", // Gemini ends tool turns with STOP
+		types.FinishReasonContentFilter: "SAFETY",
+	}[script.FinishReason]
+	if !ok {
+		return fmt.Errorf("gemini has no finish reason for %q", script.FinishReason)
+	}
+	final := response([]any{object{"text": ""}}, finish)
+	if u := script.Usage; u != nil {
+		metadata := object{"promptTokenCount": u.PromptTokens, "candidatesTokenCount": u.CompletionTokens, "totalTokenCount": u.TotalTokens}
+		if u.CacheReadTokens > 0 {
+			metadata["cachedContentTokenCount"] = u.CacheReadTokens
+		}
+		if u.ReasoningTokens > 0 {
+			metadata["thoughtsTokenCount"] = u.ReasoningTokens
+		}
+		final["usageMetadata"] = metadata
+	}
+	s.data(final)
+	return nil
+}
@@ -0,0 +1,42 @@
+package testutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestStreamEventsRejectsUnrenderableScripts(t *testing.T) {
+	t.Parallel()
+	if _, err := StreamEvents("bedrock", StreamScript{}); err == nil {
+		t.Error("unknown format: expected an error")
+	}
+	badArgs := StreamScript{ToolCalls: []StreamToolCall{{Name: "f", Arguments: []string{`{"a":`}}}}
+	if _, err := StreamEvents(GeminiStream, badArgs); err == nil {
+		t.Error("gemini with incomplete arguments: expected an error")
+	}
+	if _, err := StreamEvents(AnthropicStream, StreamScript{FinishReason: types.FinishReason("pause_turn")}); err == nil {
+		t.Error("anthropic with an unmapped finish reason: expected an error")
+	}
+}
+
+func TestStreamEventsOpenAITerminator(t *testing.T) {
+	t.Parallel()
+	events, err := StreamEvents(OpenAIStream, StreamScript{Text: []string{"hi"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := events[len(events)-1]; last != "data: [DONE]\n\n" {
+		t.Errorf("last event = %q, want the [DONE] marker", last)
+	}
+	truncated, err := StreamEvents(OpenAIStream, StreamScript{Text: []string{"hi"}, Truncate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range truncated {
+		if strings.Contains(event, "[DONE]") || strings.Contains(event, `"finish_reason":"stop"`) {
+			t.Errorf("truncated stream sent a terminal event: %q", event)
+		}
+	}
+}
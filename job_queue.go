@@ -0,0 +1,643 @@
+package wormhole
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// JobQueueConfig configures the background job queue enabled by
+// WithJobQueue.
+type JobQueueConfig struct {
+	// Store persists jobs (default an in-process MemoryJobStore).
+	Store JobStore
+	// Workers is the number of jobs run at once (default 4).
+	Workers int
+	// MaxAttempts caps the attempts per job (default 3). Only transient,
+	// rate-limit, timeout and network errors are retried.
+	MaxAttempts int
+	// RetryDelay is the wait before the first retry (default 1s). It doubles
+	// with each attempt, and a provider's Retry-After wins when longer.
+	RetryDelay time.Duration
+	// Timeout bounds each attempt; zero means no limit beyond the request's
+	// own.
+	Timeout time.Duration
+	// PollInterval is how often idle workers check the store for jobs other
+	// processes submitted, and how often Wait checks on jobs other processes
+	// run (default 1s).
+	PollInterval time.Duration
+	// Lease is how long a claimed job stays reserved for its worker (default
+	// 1m). The worker renews it every third of Lease while the attempt runs;
+	// a job whose worker died is claimed again once its lease lapses, and
+	// the lost attempt counts toward MaxAttempts.
+	Lease time.Duration
+
+	// OnFinish is called with every job this process finishes, after it is
+	// stored. It runs on a goroutine of its own, ahead of the job's NotifyFunc
+	// callback, and Close waits for it.
+	OnFinish func(ctx context.Context, job *Job)
+	// WebhookSecret signs the deliveries to NotifyURL webhooks (see
+	// VerifyJobWebhook). It is kept out of the store; empty sends them
+	// unsigned.
+	WebhookSecret string
+	// WebhookClient sends webhook deliveries (default a client with a 10s
+	// timeout).
+	WebhookClient *http.Client
+	// WebhookAttempts caps the deliveries tried per webhook (default 3). A
+	// delivery fails on a transport error or a non-2xx status, and retries
+	// wait RetryDelay, doubling.
+	WebhookAttempts int
+}
+
+// JobQueue runs submitted requests in the background, so a web handler can
+// return a job ID at once and the caller can poll or wait for the result.
+// Jobs are RequestSpecs run by a pool of workers with retry; their state lives
+// in a JobStore, so with a persistent store queued jobs survive a restart and
+// several processes can work one queue. See WithJobQueue.
+type JobQueue struct {
+	client *Wormhole
+	config JobQueueConfig
+
+	ctx        context.Context // canceled on Close, aborting running attempts
+	cancel     context.CancelFunc
+	wake       chan struct{}
+	wg         sync.WaitGroup
+	deliveries sync.WaitGroup // announcements in flight, drained by Close
+
+	// claimMu is held shared by workers claiming a job and exclusively by
+	// Cancel, so Cancel cannot miss a job between the store claim and its
+	// registration in running. Submit and Wait never take it.
+	claimMu sync.RWMutex
+
+	mu        sync.Mutex // guards the maps below; never held across store calls
+	closed    bool
+	running   map[string]context.CancelFunc
+	canceled  map[string]bool
+	waiters   map[string][]chan struct{}
+	callbacks map[string]func(context.Context, *Job) // NotifyFunc callbacks by job ID
+}
+
+func newJobQueue(client *Wormhole, config JobQueueConfig) *JobQueue {
+	if config.Store == nil {
+		config.Store = NewMemoryJobStore(0)
+	}
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = time.Second
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.Lease <= 0 {
+		config.Lease = time.Minute
+	}
+	if config.WebhookClient == nil {
+		config.WebhookClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if config.WebhookAttempts <= 0 {
+		config.WebhookAttempts = 3
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobQueue{
+		client:    client,
+		config:    config,
+		ctx:       ctx,
+		cancel:    cancel,
+		wake:      make(chan struct{}, config.Workers),
+		running:   make(map[string]context.CancelFunc),
+		canceled:  make(map[string]bool),
+		waiters:   make(map[string][]chan struct{}),
+		callbacks: make(map[string]func(context.Context, *Job)),
+	}
+}
+
+// start launches the workers. They exit on Close or client shutdown.
+func (q *JobQueue) start() {
+	for range q.config.Workers {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+// Close stops the workers. Attempts still running are aborted and their jobs
+// put back in the queue without counting the attempt, so a persistent store
+// hands them to the next process. It is called by the client's Shutdown,
+// after in-flight requests have drained, and waits for the callbacks and
+// webhook deliveries of jobs already announced; jobs finishing after it
+// starts are not announced.
+func (q *JobQueue) Close() error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.cancel()
+	q.wg.Wait()
+	q.deliveries.Wait()
+	return nil
+}
+
+// Submit validates spec, stores it as a queued job and returns the job ID.
+// The spec is copied through its JSON form, so it can be reused and any spec
+// that submits also persists. Options ask to be notified when the job
+// finishes (see NotifyURL and NotifyFunc).
+func (q *JobQueue) Submit(ctx context.Context, spec *RequestSpec, opts ...JobOption) (string, error) {
+	if q.isClosed() {
+		return "", fmt.Errorf("job queue is closed")
+	}
+	var options jobOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if err := options.validate(); err != nil {
+		return "", fmt.Errorf("submit job: %w", err)
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("submit job: %w", err)
+	}
+	spec, err = ParseRequestSpec(data)
+	if err != nil {
+		return "", fmt.Errorf("submit job: %w", err)
+	}
+	if _, _, err := q.builder(spec); err != nil {
+		return "", fmt.Errorf("submit job: %w", err)
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return "", fmt.Errorf("submit job: %w", err)
+	}
+	now := time.Now()
+	job := &Job{ID: id, Status: JobQueued, Spec: spec, Webhook: options.webhook, CreatedAt: now, UpdatedAt: now}
+	if options.callback != nil {
+		// Registered before the job is stored, so a fast worker cannot finish
+		// it unannounced.
+		q.mu.Lock()
+		q.callbacks[id] = options.callback
+		q.mu.Unlock()
+	}
+	if err := q.config.Store.Save(ctx, job); err != nil {
+		q.mu.Lock()
+		delete(q.callbacks, id)
+		q.mu.Unlock()
+		return "", fmt.Errorf("submit job: %w", err)
+	}
+	q.wakeWorker()
+	return id, nil
+}
+
+// Get returns the job with id as currently stored.
+func (q *JobQueue) Get(ctx context.Context, id string) (*Job, error) {
+	job, err := q.config.Store.Get(ctx, id)
+	if err == nil {
+		q.forget(job)
+	}
+	return job, err
+}
+
+// Wait blocks until the job with id finishes, then returns it. Jobs run by
+// this process wake Wait at once; jobs run elsewhere are polled every
+// PollInterval.
+//
+// Example:
+//
+//	id, _ := client.Submit(ctx, spec)
+//	job, err := client.Jobs().Wait(ctx, id)
+//	if err == nil && job.Status == wormhole.JobSucceeded {
+//	    fmt.Println(job.Text.Text)
+//	}
+func (q *JobQueue) Wait(ctx context.Context, id string) (*Job, error) {
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		done := q.subscribe(id)
+		job, err := q.Get(ctx, id)
+		if err != nil || job.Status.Finished() {
+			q.unsubscribe(id, done)
+			return job, err
+		}
+		select {
+		case <-done:
+		case <-ticker.C:
+			q.unsubscribe(id, done)
+		case <-ctx.Done():
+			q.unsubscribe(id, done)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Cancel cancels the job with id. A queued job will not run; an attempt this
+// process is running is aborted. Canceling a finished job does nothing. A job
+// running in another process is not reached and returns an error.
+func (q *JobQueue) Cancel(ctx context.Context, id string) error {
+	job, callback, err := q.cancelJob(ctx, id)
+	if err != nil || job == nil {
+		return err
+	}
+	q.notify(id)
+	q.announce(ctx, job, callback)
+	return nil
+}
+
+// cancelJob does Cancel's work under claimMu and returns the job and its
+// callback if it canceled a queued one, for the caller to announce once
+// claimMu is released.
+func (q *JobQueue) cancelJob(ctx context.Context, id string) (*Job, func(context.Context, *Job), error) {
+	q.claimMu.Lock()
+	defer q.claimMu.Unlock()
+	if q.cancelRunning(id) {
+		return nil, nil, nil
+	}
+	job, err := q.config.Store.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch job.Status {
+	case JobQueued:
+		// Taken before the save, so a Get that sees the job canceled does
+		// not forget the callback first.
+		callback := q.takeCallback(id)
+		job.Status = JobCanceled
+		job.UpdatedAt = time.Now()
+		if err := q.config.Store.Save(ctx, job); err != nil {
+			q.restoreCallback(id, callback)
+			return nil, nil, err
+		}
+		return job, callback, nil
+	case JobRunning:
+		if !job.ClaimedUntil.Before(time.Now()) {
+			return nil, nil, fmt.Errorf("job %s is running in another process", id)
+		}
+		// The lease lapsed: its worker is gone, so cancel it like a queued job.
+		callback := q.takeCallback(id)
+		job.Status = JobCanceled
+		job.ClaimedUntil = time.Time{}
+		job.UpdatedAt = time.Now()
+		if err := q.config.Store.Save(ctx, job); err != nil {
+			q.restoreCallback(id, callback)
+			return nil, nil, err
+		}
+		return job, callback, nil
+	}
+	return nil, nil, nil
+}
+
+// cancelRunning aborts the attempt running id in this process, if any.
+func (q *JobQueue) cancelRunning(id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cancel, ok := q.running[id]
+	if ok {
+		q.canceled[id] = true
+		cancel()
+	}
+	return ok
+}
+
+func (q *JobQueue) work() {
+	defer q.wg.Done()
+	idle := time.NewTimer(q.config.PollInterval)
+	defer idle.Stop()
+	for {
+		if q.stopping() {
+			return
+		}
+		job, ctx, err := q.claim()
+		if err != nil {
+			q.warn("claim job failed", "error", err)
+		}
+		if job != nil {
+			q.run(ctx, job)
+			continue
+		}
+		idle.Reset(q.config.PollInterval)
+		select {
+		case <-q.wake:
+		case <-idle.C:
+		case <-q.ctx.Done():
+			return
+		case <-q.client.shutdownChan:
+			return
+		}
+	}
+}
+
+// claim takes the next due job and registers it as running here.
+func (q *JobQueue) claim() (*Job, context.Context, error) {
+	q.claimMu.RLock()
+	defer q.claimMu.RUnlock()
+	if q.isClosed() {
+		return nil, nil, nil
+	}
+	job, err := q.config.Store.Claim(q.ctx, time.Now(), q.config.Lease)
+	if err != nil || job == nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithCancel(q.ctx)
+	q.mu.Lock()
+	q.running[job.ID] = cancel
+	q.mu.Unlock()
+	return job, ctx, nil
+}
+
+func (q *JobQueue) run(ctx context.Context, job *Job) {
+	if job.Attempts > q.config.MaxAttempts {
+		// Reclaimed after the worker on its last attempt stopped renewing
+		// the lease; that attempt counted, so there is none left to run.
+		job.Attempts = q.config.MaxAttempts
+		q.finish(context.WithoutCancel(ctx), job, nil, nil, errJobLeaseExpired)
+		return
+	}
+
+	attemptCtx := ctx
+	if q.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, q.config.Timeout)
+		defer cancel()
+	}
+	renewCtx, stopRenewing := context.WithCancel(attemptCtx)
+	renewed := q.renewLease(renewCtx, job)
+	text, structured, err := q.execute(attemptCtx, job.Spec)
+	stopRenewing()
+	<-renewed
+	// The attempt context is done; store the outcome under the queue's.
+	q.finish(context.WithoutCancel(ctx), job, text, structured, err)
+}
+
+// errJobLeaseExpired fails a job whose last attempt was lost with its worker.
+var errJobLeaseExpired = errors.New("job lease expired: worker stopped during the last attempt")
+
+// renewLease extends job's lease every third of Lease until ctx is done, and
+// returns a channel closed once it stops renewing. It must stop before the
+// outcome is saved, or a late renewal would overwrite it.
+func (q *JobQueue) renewLease(ctx context.Context, job *Job) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(q.config.Lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			now := time.Now()
+			job.ClaimedUntil = now.Add(q.config.Lease)
+			job.UpdatedAt = now
+			if err := q.config.Store.Save(context.WithoutCancel(ctx), job); err != nil {
+				q.warn("renew job lease failed", "job", job.ID, "error", err)
+			}
+		}
+	}()
+	return done
+}
+
+// finish records job's attempt and announces the job if it finished.
+func (q *JobQueue) finish(ctx context.Context, job *Job, text *types.TextResponse, structured *types.StructuredResponse, err error) {
+	if callback, finished := q.record(ctx, job, text, structured, err); finished {
+		q.announce(ctx, job, callback)
+	}
+}
+
+// record stores the outcome of job's attempt and unregisters it, returning
+// whether the job finished and its callback if so. Holding claimMu shared
+// keeps Cancel from seeing the job neither running here nor finished in the
+// store, and the job stays in running until it is saved so Get does not
+// forget its callback.
+func (q *JobQueue) record(ctx context.Context, job *Job, text *types.TextResponse, structured *types.StructuredResponse, err error) (func(context.Context, *Job), bool) {
+	q.claimMu.RLock()
+	defer q.claimMu.RUnlock()
+	canceled, closed := q.attemptEnded(job.ID)
+
+	now := time.Now()
+	job.UpdatedAt = now
+	job.ClaimedUntil = time.Time{}
+	switch {
+	case canceled:
+		job.Status = JobCanceled
+		job.Error = "canceled"
+	case err == nil:
+		job.Status = JobSucceeded
+		job.Error = ""
+		job.Text, job.Structured = text, structured
+	case closed || q.client.IsShuttingDown():
+		// Interrupted by shutdown: requeue for the next worker, wherever it runs.
+		job.Status = JobQueued
+		job.Attempts--
+	case job.Attempts < q.config.MaxAttempts && retryableJobError(err):
+		delay := q.retryDelay(job.Attempts, err)
+		job.Status = JobQueued
+		job.Error = err.Error()
+		job.RunAfter = now.Add(delay)
+		time.AfterFunc(delay, q.wakeWorker)
+	default:
+		job.Status = JobFailed
+		job.Error = err.Error()
+	}
+	if err := q.config.Store.Save(ctx, job); err != nil {
+		q.warn("save job failed", "job", job.ID, "error", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.running, job.ID)
+	delete(q.canceled, job.ID)
+	if !job.Status.Finished() {
+		return nil, false
+	}
+	return q.notifyLocked(job.ID), true
+}
+
+// attemptEnded releases the attempt context of the job running id and reports
+// whether it was canceled and whether the queue is closed.
+func (q *JobQueue) attemptEnded(id string) (canceled, closed bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.running[id]()
+	return q.canceled[id], q.closed
+}
+
+// builder rebuilds spec's request builder, returning exactly one of a text
+// or structured builder.
+func (q *JobQueue) builder(spec *RequestSpec) (*TextRequestBuilder, *StructuredRequestBuilder, error) {
+	switch spec.Kind {
+	case RequestKindText:
+		b, err := q.client.FromSpec(spec)
+		return b, nil, err
+	case RequestKindStructured:
+		b, err := q.client.StructuredFromSpec(spec)
+		return nil, b, err
+	}
+	return nil, nil, types.ErrInvalidRequest.WithDetails(fmt.Sprintf("unknown request kind %q", spec.Kind))
+}
+
+func (q *JobQueue) execute(ctx context.Context, spec *RequestSpec) (*types.TextResponse, *types.StructuredResponse, error) {
+	text, structured, err := q.builder(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	if text != nil {
+		resp, err := text.Generate(ctx)
+		return resp, nil, err
+	}
+	resp, err := structured.Generate(ctx)
+	return nil, resp, err
+}
+
+// retryDelay doubles RetryDelay per attempt, deferring to a longer
+// Retry-After from the provider.
+func (q *JobQueue) retryDelay(attempt int, err error) time.Duration {
+	delay := q.config.RetryDelay << min(attempt-1, 16)
+	if wormholeErr, ok := types.AsWormholeError(err); ok && wormholeErr.RetryAfter > delay {
+		delay = wormholeErr.RetryAfter
+	}
+	return delay
+}
+
+func retryableJobError(err error) bool {
+	switch types.ClassifyError(err) {
+	case types.ErrorClassTransient, types.ErrorClassRateLimit, types.ErrorClassTimeout, types.ErrorClassNetwork:
+		return true
+	}
+	return false
+}
+
+func (q *JobQueue) wakeWorker() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *JobQueue) subscribe(id string) chan struct{} {
+	done := make(chan struct{})
+	q.mu.Lock()
+	q.waiters[id] = append(q.waiters[id], done)
+	q.mu.Unlock()
+	return done
+}
+
+func (q *JobQueue) unsubscribe(id string, done chan struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	waiters := q.waiters[id]
+	for i, ch := range waiters {
+		if ch == done {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(q.waiters, id)
+	} else {
+		q.waiters[id] = waiters
+	}
+}
+
+// notify is notifyLocked for callers not holding mu.
+func (q *JobQueue) notify(id string) func(context.Context, *Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.notifyLocked(id)
+}
+
+func (q *JobQueue) takeCallback(id string) func(context.Context, *Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	callback := q.callbacks[id]
+	delete(q.callbacks, id)
+	return callback
+}
+
+func (q *JobQueue) restoreCallback(id string, callback func(context.Context, *Job)) {
+	if callback == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.callbacks[id] = callback
+}
+
+// forget drops the callback of a job seen finished in the store that this
+// process is not running: another process finished it, so the callback would
+// never be called.
+func (q *JobQueue) forget(job *Job) {
+	if !job.Status.Finished() {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.running[job.ID]; !ok {
+		delete(q.callbacks, job.ID)
+	}
+}
+
+// notifyLocked wakes every Wait on id and returns the job's NotifyFunc
+// callback, if any, for the caller to run once it releases mu.
+func (q *JobQueue) notifyLocked(id string) func(context.Context, *Job) {
+	for _, done := range q.waiters[id] {
+		close(done)
+	}
+	delete(q.waiters, id)
+	callback := q.callbacks[id]
+	delete(q.callbacks, id)
+	return callback
+}
+
+func (q *JobQueue) isClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+func (q *JobQueue) stopping() bool {
+	return q.ctx.Err() != nil || q.client.IsShuttingDown()
+}
+
+func (q *JobQueue) warn(msg string, args ...any) {
+	if logger := q.client.config.Logger; logger != nil {
+		logger.Warn(msg, args...)
+	}
+}
+
+func newJobID() (string, error) {
+	var b [12]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return "job_" + hex.EncodeToString(b[:]), nil
+}
+
+// Jobs returns the job queue enabled by WithJobQueue, or nil.
+func (p *Wormhole) Jobs() *JobQueue {
+	return p.jobs
+}
+
+// Submit queues spec on the client's job queue and returns the job ID; see
+// JobQueue.Submit. It fails unless the client was created WithJobQueue.
+//
+// Example:
+//
+//	spec, _ := client.Text().Model("gpt-5").Prompt(longReport).ToSpec()
+//	id, err := client.Submit(ctx, spec)
+//	// return id to the caller; poll later with client.Jobs().Get(ctx, id)
+func (p *Wormhole) Submit(ctx context.Context, spec *RequestSpec, opts ...JobOption) (string, error) {
+	if p.jobs == nil {
+		return "", types.ErrInvalidRequest.WithDetails("job queue not enabled; create the client WithJobQueue")
+	}
+	return p.jobs.Submit(ctx, spec, opts...)
+}
@@ -0,0 +1,274 @@
+package wormhole
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// jobTestProvider answers each call with respond, numbering calls from 1.
+type jobTestProvider struct {
+	*types.BaseProvider
+	mu      sync.Mutex
+	calls   int
+	respond func(ctx context.Context, call int) (*types.TextResponse, error)
+}
+
+func (p *jobTestProvider) Text(ctx context.Context, request types.TextRequest) (*types.TextResponse, error) {
+	p.mu.Lock()
+	p.calls++
+	call := p.calls
+	p.mu.Unlock()
+	return p.respond(ctx, call)
+}
+
+func jobTestClient(t *testing.T, config JobQueueConfig, respond func(ctx context.Context, call int) (*types.TextResponse, error)) *Wormhole {
+	t.Helper()
+	provider := &jobTestProvider{BaseProvider: types.NewBaseProvider("mock"), respond: respond}
+	client := New(
+		WithDefaultProvider("mock"),
+		WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+		WithProviderConfig("mock", types.ProviderConfig{}),
+		WithModelValidation(false),
+		WithDiscovery(false),
+		WithJobQueue(config),
+	)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func jobTestSpec(t *testing.T, client *Wormhole, prompt string) *RequestSpec {
+	t.Helper()
+	spec, err := client.Text().Model("test-model").Prompt(prompt).ToSpec()
+	require.NoError(t, err)
+	return spec
+}
+
+func waitJobStatus(t *testing.T, client *Wormhole, id string, status JobStatus) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		job, err := client.Jobs().Get(context.Background(), id)
+		return err == nil && job.Status == status
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestJobQueueSubmitAndWait(t *testing.T) {
+	t.Parallel()
+	client := jobTestClient(t, JobQueueConfig{}, func(context.Context, int) (*types.TextResponse, error) {
+		return &types.TextResponse{Text: "report ready", FinishReason: types.FinishReasonStop}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	id, err := client.Submit(ctx, jobTestSpec(t, client, "write the report"))
+	require.NoError(t, err)
+
+	job, err := client.Jobs().Wait(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, JobSucceeded, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	require.NotNil(t, job.Text)
+	assert.Equal(t, "report ready", job.Text.Text)
+	assert.Equal(t, RequestKindText, job.Spec.Kind)
+
+	_, err = client.Jobs().Get(ctx, "job_missing")
+	require.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestJobQueueRetries(t *testing.T) {
+	t.Parallel()
+	client := jobTestClient(t, JobQueueConfig{RetryDelay: 10 * time.Millisecond, MaxAttempts: 3}, func(_ context.Context, call int) (*types.TextResponse, error) {
+		if call == 1 {
+			return nil, types.ErrProviderUnavailable
+		}
+		return &types.TextResponse{Text: "second time lucky", FinishReason: types.FinishReasonStop}, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := client.Submit(ctx, jobTestSpec(t, client, "flaky"))
+	require.NoError(t, err)
+	job, err := client.Jobs().Wait(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, JobSucceeded, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Empty(t, job.Error)
+
+	rejecting := jobTestClient(t, JobQueueConfig{RetryDelay: 10 * time.Millisecond}, func(context.Context, int) (*types.TextResponse, error) {
+		return nil, types.ErrInvalidAPIKey
+	})
+	id, err = rejecting.Submit(ctx, jobTestSpec(t, rejecting, "no key"))
+	require.NoError(t, err)
+	job, err = rejecting.Jobs().Wait(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, JobFailed, job.Status)
+	assert.Equal(t, 1, job.Attempts, "auth errors are not retried")
+	assert.Contains(t, job.Error, "invalid API key")
+}
+
+func TestJobQueueCancel(t *testing.T) {
+	t.Parallel()
+	client := jobTestClient(t, JobQueueConfig{Workers: 1}, func(ctx context.Context, _ int) (*types.TextResponse, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	running, err := client.Submit(ctx, jobTestSpec(t, client, "long"))
+	require.NoError(t, err)
+	waitJobStatus(t, client, running, JobRunning)
+	queued, err := client.Submit(ctx, jobTestSpec(t, client, "behind it"))
+	require.NoError(t, err)
+
+	require.NoError(t, client.Jobs().Cancel(ctx, queued))
+	require.NoError(t, client.Jobs().Cancel(ctx, running))
+	for _, id := range []string{running, queued} {
+		job, err := client.Jobs().Wait(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, JobCanceled, job.Status, id)
+	}
+}
+
+func TestJobQueueShutdownRequeuesRunningJobs(t *testing.T) {
+	t.Parallel()
+	store := NewMemoryJobStore(0)
+	client := jobTestClient(t, JobQueueConfig{Store: store}, func(ctx context.Context, _ int) (*types.TextResponse, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	id, err := client.Submit(context.Background(), jobTestSpec(t, client, "interrupted"))
+	require.NoError(t, err)
+	waitJobStatus(t, client, id, JobRunning)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_ = client.Shutdown(shutdownCtx)
+
+	job, err := store.Get(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, JobQueued, job.Status, "a persistent store hands the job to the next process")
+	assert.Zero(t, job.Attempts)
+
+	_, err = client.Submit(context.Background(), jobTestSpec(t, client, "too late"))
+	require.Error(t, err)
+}
+
+func TestJobQueueSubmitErrors(t *testing.T) {
+	t.Parallel()
+	client := New(WithDefaultProvider("openai"), WithOpenAI("test-key"), WithDiscovery(false))
+	spec, err := client.Text().Model("gpt-4o").Prompt("hi").ToSpec()
+	require.NoError(t, err)
+	_, err = client.Submit(context.Background(), spec)
+	require.Error(t, err, "the client has no job queue")
+
+	queued := jobTestClient(t, JobQueueConfig{}, func(context.Context, int) (*types.TextResponse, error) {
+		return &types.TextResponse{Text: "ok"}, nil
+	})
+	_, err = queued.Submit(context.Background(), &RequestSpec{Version: RequestSpecVersion, Kind: "image"})
+	require.Error(t, err)
+	_, err = queued.Submit(context.Background(), &RequestSpec{Version: RequestSpecVersion, Kind: RequestKindText, Messages: []types.MessageRecord{{Role: "narrator"}}})
+	require.Error(t, err)
+}
+
+func TestMemoryJobStoreCopiesJobs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := NewMemoryJobStore(0)
+	job := &Job{ID: "job_1", Status: JobSucceeded, Spec: &RequestSpec{Kind: RequestKindText, SystemPrompt: "be brief"}, Text: &types.TextResponse{Text: "done"}}
+	require.NoError(t, store.Save(ctx, job))
+	job.Spec.SystemPrompt = "changed after save"
+
+	got, err := store.Get(ctx, "job_1")
+	require.NoError(t, err)
+	assert.Equal(t, "be brief", got.Spec.SystemPrompt)
+	got.Text.Text = "changed after get"
+
+	again, err := store.Get(ctx, "job_1")
+	require.NoError(t, err)
+	assert.Equal(t, "done", again.Text.Text)
+}
+
+func TestMemoryJobStoreClaimsExpiredLeases(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryJobStore(0)
+	require.NoError(t, store.Save(ctx, &Job{ID: "job_held", Status: JobRunning, Attempts: 1, ClaimedUntil: now.Add(time.Minute)}))
+	require.NoError(t, store.Save(ctx, &Job{ID: "job_lapsed", Status: JobRunning, Attempts: 1, ClaimedUntil: now.Add(-time.Second)}))
+
+	job, err := store.Claim(ctx, now, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, "job_lapsed", job.ID, "a live lease is not taken over")
+	assert.Equal(t, 2, job.Attempts, "the lost attempt counts")
+	assert.True(t, now.Add(time.Minute).Equal(job.ClaimedUntil))
+
+	job, err = store.Claim(ctx, now, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestJobQueueReclaimsJobsWhoseWorkerDied(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	store := NewMemoryJobStore(0)
+	client := jobTestClient(t, JobQueueConfig{Store: store, MaxAttempts: 2, PollInterval: 10 * time.Millisecond}, func(context.Context, int) (*types.TextResponse, error) {
+		return &types.TextResponse{Text: "ok"}, nil
+	})
+	// Jobs left running by a process that died mid-attempt.
+	spec := jobTestSpec(t, client, "hi")
+	lapsed := time.Now().Add(-time.Second)
+	require.NoError(t, store.Save(ctx, &Job{ID: "job_retry", Status: JobRunning, Spec: spec, Attempts: 1, ClaimedUntil: lapsed}))
+	require.NoError(t, store.Save(ctx, &Job{ID: "job_spent", Status: JobRunning, Spec: spec, Attempts: 2, ClaimedUntil: lapsed}))
+
+	job, err := client.Jobs().Wait(ctx, "job_retry")
+	require.NoError(t, err)
+	assert.Equal(t, JobSucceeded, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.True(t, job.ClaimedUntil.IsZero())
+
+	job, err = client.Jobs().Wait(ctx, "job_spent")
+	require.NoError(t, err)
+	assert.Equal(t, JobFailed, job.Status, "the lost attempt was the last one")
+	assert.Equal(t, 2, job.Attempts)
+	assert.Contains(t, job.Error, "lease expired")
+}
+
+func TestJobQueueRenewsLeaseWhileRunning(t *testing.T) {
+	t.Parallel()
+	store := NewMemoryJobStore(0)
+	release := make(chan struct{})
+	client := jobTestClient(t, JobQueueConfig{Store: store, Lease: 30 * time.Millisecond}, func(ctx context.Context, _ int) (*types.TextResponse, error) {
+		select {
+		case <-release:
+			return &types.TextResponse{Text: "ok"}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	id, err := client.Submit(context.Background(), jobTestSpec(t, client, "slow"))
+	require.NoError(t, err)
+	waitJobStatus(t, client, id, JobRunning)
+
+	// Another process polling the store must not take the job over.
+	for range 10 {
+		time.Sleep(15 * time.Millisecond)
+		job, err := store.Claim(context.Background(), time.Now(), time.Minute)
+		require.NoError(t, err)
+		require.Nil(t, job, "the lease lapsed while the worker was alive")
+	}
+	close(release)
+
+	job, err := client.Jobs().Wait(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, JobSucceeded, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+}
@@ -0,0 +1,175 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ErrJobNotFound is returned for a job ID the store does not hold.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobStatus is the state of a queued job.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"    // waiting for a worker, or for its next attempt
+	JobRunning   JobStatus = "running"   // claimed by a worker holding its lease
+	JobSucceeded JobStatus = "succeeded" // finished with a response
+	JobFailed    JobStatus = "failed"    // out of attempts, or failed with a non-retryable error
+	JobCanceled  JobStatus = "canceled"  // canceled with JobQueue.Cancel
+)
+
+// Finished reports whether the job will not run again.
+func (s JobStatus) Finished() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCanceled
+}
+
+// Job is a request submitted to a JobQueue, and its outcome. It is plain
+// data, so stores can persist it as JSON.
+type Job struct {
+	ID       string       `json:"id"`
+	Status   JobStatus    `json:"status"`
+	Spec     *RequestSpec `json:"spec,omitempty"`
+	Attempts int          `json:"attempts"`
+	// Webhook is the URL notified when the job finishes (see NotifyURL).
+	Webhook string `json:"webhook,omitempty"`
+	// Error is the last attempt's error. It is kept while a retry is
+	// pending and cleared on success.
+	Error string `json:"error,omitempty"`
+	// Text or Structured holds the response, according to Spec.Kind.
+	Text       *types.TextResponse       `json:"text,omitempty"`
+	Structured *types.StructuredResponse `json:"structured,omitempty"`
+	CreatedAt  time.Time                 `json:"created_at"`
+	UpdatedAt  time.Time                 `json:"updated_at"`
+	// RunAfter is the earliest time a queued job may be claimed; zero means
+	// now. Retries use it for their backoff.
+	RunAfter time.Time `json:"run_after,omitzero"`
+	// ClaimedUntil is when a running job's lease expires. The worker running
+	// it renews the lease while the attempt runs; once it lapses, the worker
+	// is presumed dead and Claim hands the job to another.
+	ClaimedUntil time.Time `json:"claimed_until,omitzero"`
+}
+
+// JobStore persists jobs for a JobQueue. MemoryJobStore keeps them in
+// process; implement JobStore over a database so queued jobs survive a
+// restart and several processes can share one queue.
+type JobStore interface {
+	// Save inserts job or replaces the stored job with the same ID.
+	Save(ctx context.Context, job *Job) error
+	// Get returns the job with id, or an error wrapping ErrJobNotFound.
+	Get(ctx context.Context, id string) (*Job, error)
+	// Claim takes the oldest job that is either queued with a RunAfter not
+	// after now, or running with a ClaimedUntil before now. It marks the job
+	// running, counts the attempt in Attempts, sets ClaimedUntil to
+	// now+lease and returns it, or returns nil when no job is due. It must
+	// be atomic: a job is claimed by one worker only, across every process
+	// sharing the store.
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error)
+}
+
+// MemoryJobStore is an in-process JobStore. Finished jobs are dropped once
+// they are older than its retention, so polling clients must collect results
+// within that time. Jobs are copied through their JSON form on the way in and
+// out, as a persistent store would, so callers never share a Spec or response
+// with the store.
+type MemoryJobStore struct {
+	retention time.Duration
+
+	mu    sync.Mutex
+	jobs  map[string]*Job
+	order []string // job IDs in submission order
+}
+
+// NewMemoryJobStore creates an in-process store that keeps finished jobs for
+// retention (default 1h).
+func NewMemoryJobStore(retention time.Duration) *MemoryJobStore {
+	if retention <= 0 {
+		retention = time.Hour
+	}
+	return &MemoryJobStore{retention: retention, jobs: make(map[string]*Job)}
+}
+
+// Save stores a copy of job.
+func (s *MemoryJobStore) Save(_ context.Context, job *Job) error {
+	copied, err := copyJob(job)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.ID]; !ok {
+		s.order = append(s.order, job.ID)
+	}
+	s.jobs[job.ID] = copied
+	return nil
+}
+
+// Get returns a copy of the job with id.
+func (s *MemoryJobStore) Get(_ context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return copyJob(job)
+}
+
+// Claim marks the oldest due job as running under a lease and returns a copy
+// of it. It also drops finished jobs older than the store's retention.
+func (s *MemoryJobStore) Claim(_ context.Context, now time.Time, lease time.Duration) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var claimed *Job
+	var err error
+	kept := s.order[:0]
+	for _, id := range s.order {
+		job := s.jobs[id]
+		if job.Status.Finished() && now.Sub(job.UpdatedAt) > s.retention {
+			delete(s.jobs, id)
+			continue
+		}
+		kept = append(kept, id)
+		if claimed == nil && jobDue(job, now) {
+			job.Status = JobRunning
+			job.Attempts++
+			job.ClaimedUntil = now.Add(lease)
+			job.UpdatedAt = now
+			claimed, err = copyJob(job)
+		}
+	}
+	clear(s.order[len(kept):])
+	s.order = kept
+	return claimed, err
+}
+
+// jobDue reports whether job may be claimed at now: it is queued and past
+// its RunAfter, or running under a lease that has expired.
+func jobDue(job *Job, now time.Time) bool {
+	switch job.Status {
+	case JobQueued:
+		return !job.RunAfter.After(now)
+	case JobRunning:
+		return job.ClaimedUntil.Before(now)
+	}
+	return false
+}
+
+// copyJob deep-copies job through its JSON form.
+func copyJob(job *Job) (*Job, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("copy job %s: %w", job.ID, err)
+	}
+	var copied Job
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, fmt.Errorf("copy job %s: %w", job.ID, err)
+	}
+	return &copied, nil
+}
@@ -0,0 +1,197 @@
+package wormhole
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// Headers sent with job webhook deliveries.
+const (
+	JobWebhookEventHeader     = "X-Wormhole-Event"     // "job.succeeded", "job.failed" or "job.canceled"
+	JobWebhookJobHeader       = "X-Wormhole-Job"       // the job ID
+	JobWebhookSignatureHeader = "X-Wormhole-Signature" // "t=<unix seconds>,v1=<hex HMAC-SHA256>"
+)
+
+// ErrInvalidWebhookSignature is returned by VerifyJobWebhook for a delivery
+// that was not signed with the secret, or was signed too long ago.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// JobOption configures a submitted job.
+type JobOption func(*jobOptions)
+
+type jobOptions struct {
+	webhook  string
+	callback func(context.Context, *Job)
+}
+
+// NotifyURL has the queue POST the finished job, as JSON without its spec, to
+// rawURL. The URL is stored with the job, so whichever process finishes it
+// delivers. Deliveries are signed when JobQueueConfig.WebhookSecret is set.
+func NotifyURL(rawURL string) JobOption {
+	return func(o *jobOptions) { o.webhook = rawURL }
+}
+
+// NotifyFunc has the queue call fn with the finished job. Unlike NotifyURL it
+// is held in memory, so it is only called if this process finishes the job;
+// it is dropped once Get or Wait sees another process finish it.
+func NotifyFunc(fn func(ctx context.Context, job *Job)) JobOption {
+	return func(o *jobOptions) { o.callback = fn }
+}
+
+func (o *jobOptions) validate() error {
+	if o.webhook == "" {
+		return nil
+	}
+	u, err := url.Parse(o.webhook)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return types.ErrInvalidRequest.WithDetails(fmt.Sprintf("webhook URL %q must be an absolute http or https URL", o.webhook))
+	}
+	return nil
+}
+
+// announce reports a finished job to OnFinish, its NotifyFunc callback and
+// its webhook on a goroutine of its own, so slow callbacks do not hold up the
+// worker. Once the queue is closed nothing more is announced, so Close's wait
+// on deliveries cannot race a new one.
+func (q *JobQueue) announce(ctx context.Context, job *Job, callback func(context.Context, *Job)) {
+	if q.config.OnFinish == nil && callback == nil && job.Webhook == "" {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		q.warn("job finished after the queue closed; not announced", "job", job.ID)
+		return
+	}
+	q.deliveries.Add(1)
+	go func() {
+		defer q.deliveries.Done()
+		q.notifyJob(context.WithoutCancel(ctx), job, callback)
+	}()
+}
+
+func (q *JobQueue) notifyJob(ctx context.Context, job *Job, callback func(context.Context, *Job)) {
+	if q.config.OnFinish != nil {
+		q.config.OnFinish(ctx, job)
+	}
+	if callback != nil {
+		callback(ctx, job)
+	}
+	if job.Webhook == "" {
+		return
+	}
+	payload := *job
+	payload.Spec = nil
+	body, err := json.Marshal(payload)
+	if err != nil {
+		q.warn("encode job webhook failed", "job", job.ID, "error", err)
+		return
+	}
+	q.deliver(job, body)
+}
+
+// deliver POSTs body to the job's webhook, retrying failed deliveries with
+// backoff. Close stops the retries but not a delivery already in flight.
+func (q *JobQueue) deliver(job *Job, body []byte) {
+	for attempt := 1; ; attempt++ {
+		err := q.post(job, body)
+		if err == nil {
+			return
+		}
+		if attempt == q.config.WebhookAttempts || q.stopping() {
+			q.warn("job webhook failed", "job", job.ID, "url", job.Webhook, "attempts", attempt, "error", err)
+			return
+		}
+		timer := time.NewTimer(q.config.RetryDelay << min(attempt-1, 16))
+		select {
+		case <-timer.C:
+		case <-q.ctx.Done():
+			timer.Stop()
+		}
+	}
+}
+
+func (q *JobQueue) post(job *Job, body []byte) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, job.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(JobWebhookEventHeader, "job."+string(job.Status))
+	req.Header.Set(JobWebhookJobHeader, job.ID)
+	if q.config.WebhookSecret != "" {
+		req.Header.Set(JobWebhookSignatureHeader, signJobWebhook(q.config.WebhookSecret, time.Now().Unix(), body))
+	}
+	resp, err := q.config.WebhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func signJobWebhook(secret string, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyJobWebhook checks a job webhook delivery: signature is its
+// X-Wormhole-Signature header and body its raw request body. Deliveries
+// signed more than tolerance ago are rejected, so a captured one cannot be
+// replayed later; zero skips that check.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	if err := wormhole.VerifyJobWebhook(secret, r.Header.Get(wormhole.JobWebhookSignatureHeader), body, 5*time.Minute); err != nil {
+//		http.Error(w, "bad signature", http.StatusUnauthorized)
+//		return
+//	}
+//	var job wormhole.Job
+//	_ = json.Unmarshal(body, &job)
+func VerifyJobWebhook(secret, signature string, body []byte, tolerance time.Duration) error {
+	var timestamp, sum string
+	for part := range strings.SplitSeq(signature, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			sum = value
+		}
+	}
+	t, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || sum == "" {
+		return fmt.Errorf("%w: malformed header", ErrInvalidWebhookSignature)
+	}
+	expected := signJobWebhook(secret, t, body)
+	if !hmac.Equal([]byte(expected), []byte("t="+timestamp+",v1="+sum)) {
+		return ErrInvalidWebhookSignature
+	}
+	if tolerance > 0 {
+		if age := time.Since(time.Unix(t, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: signed %s ago", ErrInvalidWebhookSignature, age.Round(time.Second))
+		}
+	}
+	return nil
+}
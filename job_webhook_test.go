@@ -0,0 +1,172 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// webhookDelivery is one request received by a webhook test server.
+type webhookDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookTestServer records deliveries and answers the first failures of
+// them with a 500.
+func webhookTestServer(t *testing.T, failures int) (*httptest.Server, chan webhookDelivery) {
+	t.Helper()
+	var mu sync.Mutex
+	deliveries := make(chan webhookDelivery, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		fail := failures > 0
+		failures--
+		mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		deliveries <- webhookDelivery{header: r.Header.Clone(), body: body}
+	}))
+	t.Cleanup(server.Close)
+	return server, deliveries
+}
+
+func TestJobWebhookSignedDelivery(t *testing.T) {
+	t.Parallel()
+	server, deliveries := webhookTestServer(t, 1)
+	client := jobTestClient(t, JobQueueConfig{WebhookSecret: "whsec", RetryDelay: 10 * time.Millisecond}, func(context.Context, int) (*types.TextResponse, error) {
+		return &types.TextResponse{Text: "report ready", FinishReason: types.FinishReasonStop}, nil
+	})
+
+	id, err := client.Submit(context.Background(), jobTestSpec(t, client, "write the report"), NotifyURL(server.URL+"/hooks/jobs"))
+	require.NoError(t, err)
+
+	var delivery webhookDelivery
+	select {
+	case delivery = <-deliveries:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no webhook delivery")
+	}
+	assert.Equal(t, "job.succeeded", delivery.header.Get(JobWebhookEventHeader))
+	assert.Equal(t, id, delivery.header.Get(JobWebhookJobHeader))
+	signature := delivery.header.Get(JobWebhookSignatureHeader)
+	require.NoError(t, VerifyJobWebhook("whsec", signature, delivery.body, time.Minute), "the retried delivery is signed")
+	require.ErrorIs(t, VerifyJobWebhook("other", signature, delivery.body, time.Minute), ErrInvalidWebhookSignature)
+	require.ErrorIs(t, VerifyJobWebhook("whsec", signature, append(delivery.body, ' '), time.Minute), ErrInvalidWebhookSignature)
+	require.ErrorIs(t, VerifyJobWebhook("whsec", "v1=abc", delivery.body, 0), ErrInvalidWebhookSignature)
+
+	var job Job
+	require.NoError(t, json.Unmarshal(delivery.body, &job))
+	assert.Equal(t, JobSucceeded, job.Status)
+	require.NotNil(t, job.Text)
+	assert.Equal(t, "report ready", job.Text.Text)
+	assert.Nil(t, job.Spec, "the spec is left out of the payload")
+
+	stale := signJobWebhook("whsec", time.Now().Add(-time.Hour).Unix(), delivery.body)
+	require.ErrorIs(t, VerifyJobWebhook("whsec", stale, delivery.body, 5*time.Minute), ErrInvalidWebhookSignature)
+	require.NoError(t, VerifyJobWebhook("whsec", stale, delivery.body, 0))
+}
+
+func TestJobCallbacks(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var finished []string
+	client := jobTestClient(t, JobQueueConfig{
+		Workers: 1,
+		OnFinish: func(_ context.Context, job *Job) {
+			mu.Lock()
+			finished = append(finished, job.ID)
+			mu.Unlock()
+		},
+	}, func(ctx context.Context, _ int) (*types.TextResponse, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	notified := make(chan *Job, 2)
+	notify := NotifyFunc(func(_ context.Context, job *Job) {
+		// Calling back into the queue must not deadlock.
+		_, err := client.Jobs().Get(context.Background(), job.ID)
+		assert.NoError(t, err)
+		notified <- job
+	})
+	running, err := client.Submit(ctx, jobTestSpec(t, client, "long"), notify)
+	require.NoError(t, err)
+	waitJobStatus(t, client, running, JobRunning)
+	queued, err := client.Submit(ctx, jobTestSpec(t, client, "behind it"), notify)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Jobs().Cancel(ctx, queued))
+	require.NoError(t, client.Jobs().Cancel(ctx, running))
+	got := map[string]JobStatus{}
+	for range 2 {
+		select {
+		case job := <-notified:
+			got[job.ID] = job.Status
+		case <-ctx.Done():
+			t.Fatal("callback not called")
+		}
+	}
+	assert.Equal(t, map[string]JobStatus{running: JobCanceled, queued: JobCanceled}, got)
+	mu.Lock()
+	assert.ElementsMatch(t, []string{running, queued}, finished)
+	mu.Unlock()
+
+	_, err = client.Submit(ctx, jobTestSpec(t, client, "nowhere"), NotifyURL("ftp://example.test/hook"))
+	require.Error(t, err)
+	_, err = client.Submit(ctx, jobTestSpec(t, client, "nowhere"), NotifyURL("/relative"))
+	require.Error(t, err)
+}
+
+func TestJobCallbacksForgottenWhenFinishedElsewhere(t *testing.T) {
+	t.Parallel()
+	client := jobTestClient(t, JobQueueConfig{}, func(context.Context, int) (*types.TextResponse, error) {
+		return &types.TextResponse{Text: "ok"}, nil
+	})
+	ctx := context.Background()
+	store := NewMemoryJobStore(0)
+	// Not started, so the job stays queued until "another process" finishes it.
+	q := newJobQueue(client, JobQueueConfig{Store: store})
+	id, err := q.Submit(ctx, jobTestSpec(t, client, "elsewhere"), NotifyFunc(func(context.Context, *Job) {
+		t.Error("the callback of a job finished elsewhere must not run")
+	}))
+	require.NoError(t, err)
+	job, err := store.Get(ctx, id)
+	require.NoError(t, err)
+	job.Status = JobSucceeded
+	require.NoError(t, store.Save(ctx, job))
+
+	_, err = q.Get(ctx, id)
+	require.NoError(t, err)
+	q.mu.Lock()
+	assert.Empty(t, q.callbacks)
+	q.mu.Unlock()
+}
+
+func TestJobQueueDoesNotAnnounceAfterClose(t *testing.T) {
+	t.Parallel()
+	client := jobTestClient(t, JobQueueConfig{}, func(context.Context, int) (*types.TextResponse, error) {
+		return &types.TextResponse{Text: "ok"}, nil
+	})
+	called := false
+	q := newJobQueue(client, JobQueueConfig{OnFinish: func(context.Context, *Job) { called = true }})
+	require.NoError(t, q.Close())
+	q.announce(context.Background(), &Job{ID: "job_late", Status: JobSucceeded}, nil)
+	q.deliveries.Wait()
+	assert.False(t, called)
+}
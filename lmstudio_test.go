@@ -0,0 +1,69 @@
+package wormhole_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/discovery"
+	"github.com/garyblankenship/wormhole/v2/providers/lmstudio"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestWithLMStudioReportsLoadedModels(t *testing.T) {
+	t.Parallel()
+
+	var loaded atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/models":
+			instances := `[]`
+			if loaded.Load() {
+				instances = `[{"id":"qwen/qwen3-8b","config":{"context_length":8192}}]`
+			}
+			_, _ = w.Write([]byte(`{"models":[{"type":"llm","key":"qwen/qwen3-8b","max_context_length":32768,"loaded_instances":` + instances + `}]}`))
+		case "/api/v1/models/load":
+			loaded.Store(true)
+			_, _ = w.Write([]byte(`{"instance_id":"qwen/qwen3-8b","status":"loaded"}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := wormhole.New(
+		wormhole.WithLMStudio(types.ProviderConfig{BaseURL: server.URL + "/v1"}),
+		wormhole.WithDiscoveryConfig(discovery.DiscoveryConfig{
+			DisableFileCache:         true,
+			DisableBackgroundRefresh: true,
+		}),
+	)
+	defer client.Close()
+
+	models, err := client.ListAvailableModels("lmstudio")
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+	assert.Equal(t, false, models[0].Constraints["loaded"])
+	assert.Equal(t, 32768, models[0].ContextLength)
+
+	provider, err := client.ProviderWithHandle("lmstudio")
+	require.NoError(t, err)
+	defer provider.Close()
+	lms, ok := provider.Provider.(*lmstudio.Provider)
+	require.True(t, ok, "lmstudio provider is %T", provider.Provider)
+	_, err = lms.LoadModel(context.Background(), "qwen/qwen3-8b", lmstudio.LoadOptions{ContextLength: 8192})
+	require.NoError(t, err)
+
+	require.NoError(t, client.RefreshModels())
+	models, err = client.ListAvailableModels("lmstudio")
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+	assert.Equal(t, true, models[0].Constraints["loaded"])
+	assert.Equal(t, 8192, models[0].ContextLength)
+}
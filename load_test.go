@@ -2,7 +2,6 @@ package wormhole
 
 import (
 	"context"
-	"fmt"
 	"runtime"
 	"sort"
 	"sync"
@@ -247,21 +246,18 @@ func TestLoadWithErrorInjection(t *testing.T) {
 	t.Parallel()
 	skipLoadTestInShortMode(t)
 	// Create mock provider that errors 20% of the time
-	mockProvider := testing_pkg.NewMockProvider("mock")
-	mockProvider.WithTextResponse(types.TextResponse{
-		Text:  "Hello, World!",
-		Usage: &types.Usage{TotalTokens: 10},
-	})
+	mockProvider := testing_pkg.NewMockProvider("mock").
+		WithTextResponse(types.TextResponse{
+			Text:  "Hello, World!",
+			Usage: &types.Usage{TotalTokens: 10},
+		}).
+		WithErrorRate(0.2)
 
-	// Create client with error-injecting provider
 	client := &Wormhole{
 		providerFactories: make(map[string]types.ProviderFactory),
 		providers: map[string]*cachedProvider{
 			"mock": {
-				provider: &errorInjectingProvider{
-					MockProvider: mockProvider,
-					errorRate:    0.2, // 20% error rate
-				},
+				provider: mockProvider,
 				lastUsed: time.Now().UnixNano(),
 				refCount: 1,
 			},
@@ -284,21 +280,6 @@ func TestLoadWithErrorInjection(t *testing.T) {
 	runLoadTestWithClient(t, config, client, "error_injection")
 }
 
-// errorInjectingProvider wraps MockProvider to inject errors
-type errorInjectingProvider struct {
-	*testing_pkg.MockProvider
-	errorRate float64
-	counter   atomic.Int64
-}
-
-func (p *errorInjectingProvider) Text(ctx context.Context, request types.TextRequest) (*types.TextResponse, error) {
-	count := p.counter.Add(1)
-	if float64(count%100) < p.errorRate {
-		return nil, fmt.Errorf("injected error at request %d", count)
-	}
-	return p.MockProvider.Text(ctx, request)
-}
-
 // TestLoadWithMiddleware tests load with middleware chain
 func TestLoadWithMiddleware(t *testing.T) {
 	t.Parallel()
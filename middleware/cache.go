@@ -8,6 +8,8 @@ import (
 	"reflect"
 	"sync"
 	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 // Cache interface for middleware
@@ -139,11 +141,17 @@ func (mc *MemoryCache) cleanup() {
 // CacheKeyGenerator generates cache keys from requests
 type CacheKeyGenerator func(req any) (string, error)
 
-// DefaultCacheKeyGenerator creates a cache key by hashing the JSON representation
-// plus ProviderOptions, which carries json:"-" (so it is invisible to Marshal)
-// yet changes the upstream call — requests differing only in ProviderOptions must
-// not collide.
+// DefaultCacheKeyGenerator uses the request's Fingerprint when it has one
+// (every wormhole request type does). Other values hash their JSON
+// representation plus ProviderOptions, which carries json:"-" (so it is
+// invisible to Marshal) yet changes the upstream call — requests differing
+// only in ProviderOptions must not collide.
 func DefaultCacheKeyGenerator(req any) (string, error) {
+	if fp, ok := req.(interface{ Fingerprint() string }); ok {
+		if key := fp.Fingerprint(); key != "" {
+			return key, nil
+		}
+	}
 	data, err := json.Marshal(req)
 	if err != nil {
 		return "", err
@@ -169,7 +177,11 @@ type CacheConfig struct {
 	CacheableFunc func(req any) bool
 }
 
-// CacheMiddleware implements response caching.
+// CacheMiddleware implements response caching. Text and structured responses
+// served from the cache carry types.CacheHitKey in their Metadata, so
+// CacheInfo reports them as local hits. A CacheControl on the request context
+// (see the builders' Cache and NoCache methods) overrides the TTL or skips
+// the cache; CacheKey documents how keys are derived.
 //
 // Example usage:
 //
@@ -192,7 +204,7 @@ func CacheMiddleware(config CacheConfig) Middleware {
 	return func(next Handler) Handler {
 		return func(ctx context.Context, req any) (any, error) {
 			// Check if request is cacheable
-			if config.CacheableFunc != nil && !config.CacheableFunc(req) {
+			if cacheBypassed(ctx) || (config.CacheableFunc != nil && !config.CacheableFunc(req)) {
 				resp, err := next(ctx, req)
 				return resp, wrapIfNotWormholeError("cache", err)
 			}
@@ -204,11 +216,8 @@ func CacheMiddleware(config CacheConfig) Middleware {
 				resp, err := next(ctx, req)
 				return resp, wrapIfNotWormholeError("cache", err)
 			}
-			// Namespace the key by provider so the same model string on two
-			// providers (or a cache shared across providers) cannot collide.
-			if p, ok := ctx.Value(CtxKeyProvider).(string); ok && p != "" {
-				key = p + ":" + key
-			}
+			provider, _ := ctx.Value(CtxKeyProvider).(string)
+			key = namespaceCacheKey(provider, key)
 
 			// Check cache
 			if cached, found := config.Cache.Get(key); found {
@@ -218,7 +227,7 @@ func CacheMiddleware(config CacheConfig) Middleware {
 					// the cache hit is still valid, just without isolation.
 					return cached, nil
 				}
-				return cloned, nil
+				return withResponseMetadata(cloned, types.CacheHitKey, true), nil
 			}
 
 			// Execute request
@@ -238,7 +247,7 @@ func CacheMiddleware(config CacheConfig) Middleware {
 			// through the same pointer/reference returned on the miss path.
 			cachedResp, cloneErr := cloneValue(resp)
 			if cloneErr == nil {
-				config.Cache.Set(key, cachedResp, config.TTL)
+				config.Cache.Set(key, cachedResp, cacheTTL(ctx, config.TTL))
 			}
 
 			return resp, nil
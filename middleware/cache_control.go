@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"time"
+)
+
+// CacheControl overrides the cache middleware's policy for one request.
+type CacheControl struct {
+	// TTL replaces the configured TTL for a response stored by this request.
+	// Zero keeps the configured TTL.
+	TTL time.Duration
+	// Bypass skips the cache entirely: no lookup and no store.
+	Bypass bool
+}
+
+// CtxKeyCacheControl carries the request's CacheControl.
+const CtxKeyCacheControl contextKey = "cache_control"
+
+// WithCacheControl returns a context whose requests follow control in
+// CacheMiddleware and SemanticCacheMiddleware.
+func WithCacheControl(ctx context.Context, control CacheControl) context.Context {
+	return context.WithValue(ctx, CtxKeyCacheControl, control)
+}
+
+// CacheControlFromContext returns the CacheControl stored by
+// WithCacheControl, and whether there was one.
+func CacheControlFromContext(ctx context.Context) (CacheControl, bool) {
+	control, ok := ctx.Value(CtxKeyCacheControl).(CacheControl)
+	return control, ok
+}
+
+// cacheTTL returns the TTL to store a response under: the request's override
+// when it sets one, otherwise configured.
+func cacheTTL(ctx context.Context, configured time.Duration) time.Duration {
+	if control, ok := CacheControlFromContext(ctx); ok && control.TTL > 0 {
+		return control.TTL
+	}
+	return configured
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	control, ok := CacheControlFromContext(ctx)
+	return ok && control.Bypass
+}
+
+// CacheKey returns the key CacheMiddleware, with the default key generator,
+// stores req under when it is sent to provider. The key is
+//
+//	<provider>:<fingerprint>
+//
+// where the fingerprint is the SHA-256 of the request kind, the request's
+// JSON (struct fields in declaration order, map keys sorted: model,
+// messages, sampling parameters, tools, schema and so on) and the fields
+// JSON leaves out, SystemPrompt and ProviderOptions. Requests that agree on
+// all of these share a cache entry; any difference, including a single
+// provider option, gives a new key. Request types without a Fingerprint
+// method hash their JSON and ProviderOptions instead.
+func CacheKey(provider string, req any) (string, error) {
+	key, err := DefaultCacheKeyGenerator(req)
+	if err != nil {
+		return "", err
+	}
+	return namespaceCacheKey(provider, key), nil
+}
+
+// namespaceCacheKey prefixes key with the provider so the same model string
+// on two providers (or a cache shared across providers) cannot collide.
+func namespaceCacheKey(provider, key string) string {
+	if provider == "" {
+		return key
+	}
+	return provider + ":" + key
+}
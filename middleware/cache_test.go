@@ -537,3 +537,44 @@ func TestCacheMiddlewareKeyGeneratorError(t *testing.T) {
 		t.Errorf("Expected handler to be called twice (no caching), got %d", callCount)
 	}
 }
+
+func TestCacheMiddlewareHonorsCacheControl(t *testing.T) {
+	t.Parallel()
+	cache := NewMemoryCache(10)
+	defer cache.Close()
+
+	callCount := 0
+	handler := CacheMiddleware(CacheConfig{Cache: cache, TTL: time.Hour})(
+		func(ctx context.Context, req any) (any, error) {
+			callCount++
+			return testResponse, nil
+		})
+
+	req := map[string]string{"test": "request"}
+	ctx := context.WithValue(context.Background(), CtxKeyProvider, "openai")
+	key, err := CacheKey("openai", req)
+	if err != nil {
+		t.Fatalf("CacheKey: %v", err)
+	}
+
+	// Bypass neither reads nor stores.
+	bypass := WithCacheControl(ctx, CacheControl{Bypass: true})
+	_, _ = handler(bypass, req)
+	_, _ = handler(bypass, req)
+	if callCount != 2 {
+		t.Errorf("Expected bypassed calls to reach the handler twice, got %d", callCount)
+	}
+	if _, found := cache.Get(key); found {
+		t.Error("Expected bypassed call not to store a response")
+	}
+
+	// A TTL override is used for the stored entry, under CacheKey's key.
+	_, _ = handler(WithCacheControl(ctx, CacheControl{TTL: time.Millisecond}), req)
+	if _, found := cache.Get(key); !found {
+		t.Fatalf("Expected response stored under %q", key)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, found := cache.Get(key); found {
+		t.Error("Expected entry to expire after the overridden TTL")
+	}
+}
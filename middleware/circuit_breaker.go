@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,6 +22,31 @@ const (
 	StateHalfOpen
 )
 
+// String returns "closed", "open" or "half-open".
+func (s CircuitState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitStateChange describes one breaker transition. Provider and Operation
+// identify the breaker inside a CircuitBreakers registry and are empty for a
+// standalone CircuitBreaker.
+type CircuitStateChange struct {
+	Provider  string
+	Operation string
+	From      CircuitState
+	To        CircuitState
+	At        time.Time
+}
+
 var (
 	// ErrCircuitOpen is returned when circuit breaker is open
 	ErrCircuitOpen = types.NewWormholeError(types.ErrorCodeMiddleware, "circuit breaker is open", true)
@@ -35,39 +61,86 @@ type CircuitBreaker struct {
 	failureThreshold int
 	successThreshold int
 	timeout          time.Duration
+	maxTimeout       time.Duration // Cap for exponential open timeouts; <= timeout disables growth
+	trips            int           // Consecutive openings without an intervening close
 	lastFailureTime  time.Time
 	halfOpenCalls    atomic.Int32 // Atomic for CAS-based admission control
 	maxHalfOpenCalls int32        // int32 for atomic comparison
+
+	provider      string
+	operation     string
+	opens         int64     // Total transitions into StateOpen
+	lastChange    time.Time // Time of the most recent transition
+	onStateChange func(CircuitStateChange)
 }
 
 const defaultCircuitKey = "default\x00default"
 
-type circuitBreakerRegistry struct {
-	mu               sync.RWMutex
-	breakers         map[string]*CircuitBreaker
-	failureThreshold int
-	timeout          time.Duration
+// CircuitBreakerConfig configures a circuit breaker.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // Failures that open a closed circuit
+	Timeout          time.Duration // How long the circuit stays open before probing
+	// MaxTimeout enables exponential opening: each time a half-open probe
+	// fails, the open timeout doubles, up to MaxTimeout. A successful close
+	// resets it to Timeout. Zero (or any value <= Timeout) keeps a fixed timeout.
+	MaxTimeout time.Duration
+	// OnStateChange, if set, is called after every transition between
+	// closed, open and half-open. It runs on the request goroutine with no
+	// breaker lock held, so it should return quickly.
+	OnStateChange func(CircuitStateChange)
 }
 
-func newCircuitBreakerRegistry(failureThreshold int, timeout time.Duration) *circuitBreakerRegistry {
-	return &circuitBreakerRegistry{
-		breakers:         make(map[string]*CircuitBreaker),
-		failureThreshold: failureThreshold,
-		timeout:          timeout,
+// CircuitBreakerStats is a point-in-time view of one breaker.
+type CircuitBreakerStats struct {
+	Provider   string
+	Operation  string
+	State      CircuitState
+	Failures   int       // Weighted failures since the last success
+	Opens      int64     // Times the breaker has tripped open
+	LastChange time.Time // Zero until the first transition
+}
+
+// CircuitBreakers keeps one CircuitBreaker per provider and operation, read
+// from the request context, so a failing provider never blocks its
+// fallbacks. Unlike CircuitBreakerMiddleware it can be queried: ProviderState
+// and Stats show which backends are tripped.
+//
+// Example:
+//
+//	breakers := middleware.NewCircuitBreakers(middleware.CircuitBreakerConfig{
+//	    FailureThreshold: 5,
+//	    Timeout:          30 * time.Second,
+//	    OnStateChange: func(c middleware.CircuitStateChange) {
+//	        log.Printf("%s/%s: %s -> %s", c.Provider, c.Operation, c.From, c.To)
+//	    },
+//	})
+//	client := wormhole.New(wormhole.WithMiddleware(breakers.Middleware()))
+type CircuitBreakers struct {
+	mu       sync.RWMutex
+	breakers map[string]*CircuitBreaker
+	config   CircuitBreakerConfig
+}
+
+// NewCircuitBreakers returns an empty registry; breakers are created on first
+// use with config.
+func NewCircuitBreakers(config CircuitBreakerConfig) *CircuitBreakers {
+	return &CircuitBreakers{
+		breakers: make(map[string]*CircuitBreaker),
+		config:   config,
 	}
 }
 
-func circuitKey(ctx context.Context) string {
-	provider, _ := ctx.Value(CtxKeyProvider).(string)
-	method, _ := ctx.Value(CtxKeyMethod).(string)
+func circuitKey(provider, method string) string {
 	if provider == "" && method == "" {
 		return defaultCircuitKey
 	}
 	return provider + "\x00" + method
 }
 
-func (r *circuitBreakerRegistry) breaker(ctx context.Context) *CircuitBreaker {
-	key := circuitKey(ctx)
+func (r *CircuitBreakers) breaker(ctx context.Context) *CircuitBreaker {
+	provider, _ := ctx.Value(CtxKeyProvider).(string)
+	method, _ := ctx.Value(CtxKeyMethod).(string)
+	key := circuitKey(provider, method)
 	r.mu.RLock()
 	breaker := r.breakers[key]
 	r.mu.RUnlock()
@@ -78,14 +151,75 @@ func (r *circuitBreakerRegistry) breaker(ctx context.Context) *CircuitBreaker {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if breaker = r.breakers[key]; breaker == nil {
-		breaker = NewCircuitBreaker(r.failureThreshold, r.timeout)
+		breaker = NewCircuitBreakerWithConfig(r.config)
+		breaker.provider = provider
+		breaker.operation = method
 		r.breakers[key] = breaker
 	}
 	return breaker
 }
 
+// Middleware returns middleware that routes each call through its
+// provider/operation breaker.
+func (r *CircuitBreakers) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			breaker := r.breaker(ctx)
+			result, err := breaker.Execute(ctx, func() (any, error) {
+				return next(ctx, req)
+			})
+			return result, wrapIfNotWormholeError("circuit_breaker", err)
+		}
+	}
+}
+
+// ProviderState reports the worst state across the provider's operations:
+// open if any is open, else half-open if any is probing, else closed. A
+// provider with no traffic yet is closed.
+func (r *CircuitBreakers) ProviderState(provider string) CircuitState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	state := StateClosed
+	for _, breaker := range r.breakers {
+		if breaker.provider != provider {
+			continue
+		}
+		switch breaker.GetState() {
+		case StateOpen:
+			return StateOpen
+		case StateHalfOpen:
+			state = StateHalfOpen
+		}
+	}
+	return state
+}
+
+// Stats returns every breaker's stats, sorted by provider then operation.
+func (r *CircuitBreakers) Stats() []CircuitBreakerStats {
+	r.mu.RLock()
+	stats := make([]CircuitBreakerStats, 0, len(r.breakers))
+	for _, breaker := range r.breakers {
+		stats = append(stats, breaker.Stats())
+	}
+	r.mu.RUnlock()
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Provider != stats[j].Provider {
+			return stats[i].Provider < stats[j].Provider
+		}
+		return stats[i].Operation < stats[j].Operation
+	})
+	return stats
+}
+
 // NewCircuitBreaker creates a new circuit breaker
 func NewCircuitBreaker(failureThreshold int, timeout time.Duration) *CircuitBreaker {
+	return NewCircuitBreakerWithConfig(CircuitBreakerConfig{FailureThreshold: failureThreshold, Timeout: timeout})
+}
+
+// NewCircuitBreakerWithConfig creates a circuit breaker, optionally with
+// exponentially growing open timeouts.
+func NewCircuitBreakerWithConfig(config CircuitBreakerConfig) *CircuitBreaker {
+	failureThreshold := config.FailureThreshold
 	// maxHalfOpen is the probe budget admitted per half-open cycle. successThreshold
 	// must never exceed it: if it does, the breaker can admit fewer probes than it
 	// needs to close, so once the provider recovers all probes succeed but the count
@@ -99,8 +233,39 @@ func NewCircuitBreaker(failureThreshold int, timeout time.Duration) *CircuitBrea
 		state:            StateClosed,
 		failureThreshold: failureThreshold,
 		successThreshold: successThreshold,
-		timeout:          timeout,
+		timeout:          config.Timeout,
+		maxTimeout:       config.MaxTimeout,
 		maxHalfOpenCalls: maxHalfOpen,
+		onStateChange:    config.OnStateChange,
+	}
+}
+
+// setState moves the breaker to state and returns the transition to report
+// once cb.mu is released, or nil if the state did not change. Callers must
+// hold cb.mu.
+func (cb *CircuitBreaker) setState(state CircuitState) *CircuitStateChange {
+	if cb.state == state {
+		return nil
+	}
+	change := &CircuitStateChange{
+		Provider:  cb.provider,
+		Operation: cb.operation,
+		From:      cb.state,
+		To:        state,
+		At:        time.Now(),
+	}
+	cb.state = state
+	cb.lastChange = change.At
+	if state == StateOpen {
+		cb.opens++
+	}
+	return change
+}
+
+// notify reports change to OnStateChange. Call without cb.mu held.
+func (cb *CircuitBreaker) notify(change *CircuitStateChange) {
+	if change != nil && cb.onStateChange != nil {
+		cb.onStateChange(*change)
 	}
 }
 
@@ -109,9 +274,10 @@ func (cb *CircuitBreaker) Execute(ctx context.Context, fn func() (any, error)) (
 	cb.mu.Lock()
 
 	// Check if we should transition from open to half-open
+	var probe *CircuitStateChange
 	if cb.state == StateOpen {
-		if time.Since(cb.lastFailureTime) > cb.timeout {
-			cb.state = StateHalfOpen
+		if time.Since(cb.lastFailureTime) > cb.openTimeout() {
+			probe = cb.setState(StateHalfOpen)
 			cb.halfOpenCalls.Store(0)
 			cb.successes = 0
 		} else {
@@ -138,53 +304,85 @@ func (cb *CircuitBreaker) Execute(ctx context.Context, fn func() (any, error)) (
 	}
 
 	cb.mu.Unlock()
+	cb.notify(probe)
 
 	// Execute the function
 	result, err := fn()
 
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
-
+	var change *CircuitStateChange
 	if err != nil {
-		return cb.handleError(result, wrapIfNotWormholeError("circuit_breaker", err))
+		err = wrapIfNotWormholeError("circuit_breaker", err)
+		change = cb.handleError(err)
+	} else {
+		change = cb.handleSuccess()
 	}
+	cb.mu.Unlock()
+	cb.notify(change)
 
-	return cb.handleSuccess(result), nil
+	return result, err
 }
 
-func (cb *CircuitBreaker) handleError(result any, err error) (any, error) {
+func (cb *CircuitBreaker) handleError(err error) *CircuitStateChange {
 	cb.failures += circuitFailureWeight(err, cb.failureThreshold)
 	cb.lastFailureTime = time.Now()
 
 	switch cb.state {
 	case StateClosed:
 		if cb.failures >= cb.failureThreshold {
-			cb.state = StateOpen
+			cb.trips = 1
+			return cb.setState(StateOpen)
 		}
 	case StateHalfOpen:
 		// Any failure in half-open state reopens the circuit
-		cb.state = StateOpen
+		cb.trips++
 		cb.failures = cb.failureThreshold
 		cb.halfOpenCalls.Store(0) // Reset for next half-open cycle
+		return cb.setState(StateOpen)
 	}
 
-	return result, err
+	return nil
 }
 
-func (cb *CircuitBreaker) handleSuccess(result any) any {
+func (cb *CircuitBreaker) handleSuccess() *CircuitStateChange {
 	cb.failures = 0
 
 	switch cb.state {
 	case StateHalfOpen:
 		cb.successes++
 		if cb.successes >= cb.successThreshold {
-			cb.state = StateClosed
 			cb.successes = 0
+			cb.trips = 0
 			cb.halfOpenCalls.Store(0) // Reset for next half-open cycle
+			return cb.setState(StateClosed)
+		}
+	}
+
+	return nil
+}
+
+// openTimeout returns how long the circuit stays open for the current trip:
+// Timeout doubled per consecutive reopening, capped at MaxTimeout.
+// Callers must hold cb.mu.
+func (cb *CircuitBreaker) openTimeout() time.Duration {
+	if cb.maxTimeout <= cb.timeout || cb.trips <= 1 {
+		return cb.timeout
+	}
+	timeout := cb.timeout
+	for i := 1; i < cb.trips; i++ {
+		timeout *= 2
+		if timeout >= cb.maxTimeout || timeout <= 0 {
+			return cb.maxTimeout
 		}
 	}
+	return timeout
+}
 
-	return result
+// OpenTimeout returns the open duration that applies to the current trip.
+func (cb *CircuitBreaker) OpenTimeout() time.Duration {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return cb.openTimeout()
 }
 
 // GetState returns the current state of the circuit breaker
@@ -194,6 +392,20 @@ func (cb *CircuitBreaker) GetState() CircuitState {
 	return cb.state
 }
 
+// Stats returns the breaker's current state and counters.
+func (cb *CircuitBreaker) Stats() CircuitBreakerStats {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return CircuitBreakerStats{
+		Provider:   cb.provider,
+		Operation:  cb.operation,
+		State:      cb.state,
+		Failures:   cb.failures,
+		Opens:      cb.opens,
+		LastChange: cb.lastChange,
+	}
+}
+
 // Close is a no-op for circuit breaker (no background resources)
 func (cb *CircuitBreaker) Close() error {
 	return nil
@@ -201,15 +413,13 @@ func (cb *CircuitBreaker) Close() error {
 
 // CircuitBreakerMiddleware creates a middleware with circuit breaker protection
 func CircuitBreakerMiddleware(threshold int, timeout time.Duration) Middleware {
-	registry := newCircuitBreakerRegistry(threshold, timeout)
+	return CircuitBreakerMiddlewareWithConfig(CircuitBreakerConfig{FailureThreshold: threshold, Timeout: timeout})
+}
 
-	return func(next Handler) Handler {
-		return func(ctx context.Context, req any) (any, error) {
-			breaker := registry.breaker(ctx)
-			result, err := breaker.Execute(ctx, func() (any, error) {
-				return next(ctx, req)
-			})
-			return result, wrapIfNotWormholeError("circuit_breaker", err)
-		}
-	}
+// CircuitBreakerMiddlewareWithConfig creates a circuit breaker middleware with
+// per provider/operation breakers built from config. Combine with a
+// RetryConfig.Budget inside it to fail fast and back off exponentially during
+// provider outages. Use NewCircuitBreakers instead to query breaker state.
+func CircuitBreakerMiddlewareWithConfig(config CircuitBreakerConfig) Middleware {
+	return NewCircuitBreakers(config).Middleware()
 }
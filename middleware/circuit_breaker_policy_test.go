@@ -105,3 +105,60 @@ func TestCircuitBreakerErrorPolicyKeepsTransientThreshold(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, StateClosed, cb.GetState())
 }
+
+func TestCircuitBreakersReportStateChanges(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var changes []CircuitStateChange
+	breakers := NewCircuitBreakers(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		Timeout:          10 * time.Millisecond,
+		OnStateChange: func(c CircuitStateChange) {
+			mu.Lock()
+			changes = append(changes, c)
+			mu.Unlock()
+		},
+	})
+	failing := true
+	wrapped := breakers.Middleware()(func(context.Context, any) (any, error) {
+		if failing {
+			return nil, errors.New("provider unavailable")
+		}
+		return "ok", nil
+	})
+
+	primary := circuitContext("primary", "text")
+	_, _ = wrapped(primary, nil)
+	_, _ = wrapped(primary, nil)
+	_, _ = wrapped(circuitContext("primary", "embeddings"), nil)
+	_, _ = wrapped(circuitContext("fallback", "text"), nil)
+
+	assert.Equal(t, StateOpen, breakers.ProviderState("primary"))
+	assert.Equal(t, StateClosed, breakers.ProviderState("fallback"))
+	assert.Equal(t, StateClosed, breakers.ProviderState("unused"))
+
+	time.Sleep(15 * time.Millisecond)
+	failing = false
+	_, err := wrapped(primary, nil)
+	require.NoError(t, err)
+	assert.Equal(t, StateClosed, breakers.ProviderState("primary"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, changes, 3)
+	for i, want := range [][2]CircuitState{{StateClosed, StateOpen}, {StateOpen, StateHalfOpen}, {StateHalfOpen, StateClosed}} {
+		assert.Equal(t, "primary", changes[i].Provider)
+		assert.Equal(t, "text", changes[i].Operation)
+		assert.Equal(t, want[0], changes[i].From, "change %d", i)
+		assert.Equal(t, want[1], changes[i].To, "change %d", i)
+	}
+
+	stats := breakers.Stats()
+	require.Len(t, stats, 3)
+	assert.Equal(t, "fallback", stats[0].Provider)
+	assert.Equal(t, "embeddings", stats[1].Operation)
+	assert.Equal(t, "text", stats[2].Operation)
+	assert.Equal(t, int64(1), stats[2].Opens)
+	assert.Equal(t, StateClosed, stats[2].State)
+	assert.Equal(t, "half-open", StateHalfOpen.String())
+}
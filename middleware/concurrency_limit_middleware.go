@@ -3,6 +3,8 @@ package middleware
 import (
 	"context"
 	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 // ProviderAwareConcurrencyLimitConfig holds configuration for provider-aware concurrency limiting.
@@ -34,6 +36,9 @@ func ProviderAwareConcurrencyLimitMiddlewareWithConfig(config ProviderAwareConcu
 			provider, model := providerModelFromContext(ctx, enableProviderAware)
 			release, ok := acquireConcurrencyToken(ctx, limiter, provider, model, enableProviderAware)
 			if !ok {
+				if ctx.Err() == nil {
+					return nil, types.ErrLoadShed
+				}
 				return nil, wrapMiddlewareError("provider_aware_concurrency_limit", "acquire", ctx.Err())
 			}
 
@@ -0,0 +1,278 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ErrConcurrencyLimited matches every *ConcurrencyLimitError with errors.Is.
+var ErrConcurrencyLimited = types.NewWormholeError(types.ErrorCodeMiddleware, "concurrency limit reached", true)
+
+// ConcurrencyLimitError reports a call refused by a ConcurrencyLimiter,
+// either at once (RejectWhenFull) or after waiting MaxWait.
+type ConcurrencyLimitError struct {
+	Key    string        // Tenant key of the refused call
+	Tenant bool          // True when the tenant's quota, not the global limit, was full
+	Limit  int           // The limit that was full
+	Waited time.Duration // Time spent queued before giving up
+}
+
+func (e *ConcurrencyLimitError) Error() string {
+	scope := "global"
+	if e.Tenant {
+		scope = fmt.Sprintf("tenant %q", e.Key)
+	}
+	return fmt.Sprintf("concurrency limit reached: %s limit %d", scope, e.Limit)
+}
+
+// Unwrap returns ErrConcurrencyLimited.
+func (e *ConcurrencyLimitError) Unwrap() error {
+	return ErrConcurrencyLimited
+}
+
+// TagKey returns a tenant key function that reads the named request tag, as
+// set by the builders' Metadata method or WithTags.
+func TagKey(name string) func(context.Context) string {
+	return func(ctx context.Context) string {
+		return TagsFromContext(ctx)[name]
+	}
+}
+
+// ConcurrencyLimitConfig configures a ConcurrencyLimiter.
+type ConcurrencyLimitConfig struct {
+	// Max caps in-flight calls across all tenants. Zero means no global cap.
+	Max int
+	// Key names the tenant of a call. Nil, or an empty result, puts the call
+	// in the shared "" tenant.
+	Key func(context.Context) string
+	// PerKey caps in-flight calls per tenant. Zero means no per-tenant cap.
+	PerKey int
+	// Quotas overrides PerKey for specific tenants.
+	Quotas map[string]int
+	// RejectWhenFull refuses excess calls immediately instead of queueing.
+	RejectWhenFull bool
+	// MaxWait bounds how long an excess call queues. Zero waits as long as
+	// the request context allows.
+	MaxWait time.Duration
+}
+
+// ConcurrencyStats is a point-in-time view of a ConcurrencyLimiter.
+type ConcurrencyStats struct {
+	InFlight int              // Calls running now
+	Waiting  int              // Calls queued now
+	PerKey   map[string]int   // Calls running now by tenant
+	Rejected map[string]int64 // Calls refused by tenant
+}
+
+// ConcurrencyLimiter bounds simultaneous provider calls globally and per
+// tenant. Excess calls queue; when a slot frees it goes to the waiting tenant
+// with the fewest calls in flight, so a tenant that floods the client cannot
+// starve the others.
+type ConcurrencyLimiter struct {
+	mu       sync.Mutex
+	config   ConcurrencyLimitConfig
+	inFlight int
+	perKey   map[string]int
+	waiting  []*concurrencyWaiter
+	rejected map[string]int64
+}
+
+type concurrencyWaiter struct {
+	key   string
+	ready chan struct{}
+	done  bool // admitted; guarded by ConcurrencyLimiter.mu
+}
+
+// NewConcurrencyLimiter returns a limiter with no calls in flight.
+func NewConcurrencyLimiter(config ConcurrencyLimitConfig) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		config:   config,
+		perKey:   make(map[string]int),
+		rejected: make(map[string]int64),
+	}
+}
+
+func (l *ConcurrencyLimiter) key(ctx context.Context) string {
+	if l.config.Key == nil {
+		return ""
+	}
+	return l.config.Key(ctx)
+}
+
+func (l *ConcurrencyLimiter) quota(key string) int {
+	if quota, ok := l.config.Quotas[key]; ok {
+		return quota
+	}
+	return l.config.PerKey
+}
+
+// fullLocked reports which limit, if any, keeps key from starting a call.
+func (l *ConcurrencyLimiter) fullLocked(key string) (limit int, tenant, full bool) {
+	if quota := l.quota(key); quota > 0 && l.perKey[key] >= quota {
+		return quota, true, true
+	}
+	if l.config.Max > 0 && l.inFlight >= l.config.Max {
+		return l.config.Max, false, true
+	}
+	return 0, false, false
+}
+
+func (l *ConcurrencyLimiter) admitLocked(key string) {
+	l.inFlight++
+	l.perKey[key]++
+}
+
+// Acquire waits for a slot for ctx's tenant and returns the function that
+// frees it. A refused call gets a *ConcurrencyLimitError; a call whose
+// context ends while queued gets the context's error.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) (func(), error) {
+	key := l.key(ctx)
+	l.mu.Lock()
+	limit, tenant, full := l.fullLocked(key)
+	if !full {
+		l.admitLocked(key)
+		l.mu.Unlock()
+		return l.releaser(key), nil
+	}
+	if l.config.RejectWhenFull {
+		l.rejected[key]++
+		l.mu.Unlock()
+		return nil, &ConcurrencyLimitError{Key: key, Tenant: tenant, Limit: limit}
+	}
+	w := &concurrencyWaiter{key: key, ready: make(chan struct{})}
+	l.waiting = append(l.waiting, w)
+	l.mu.Unlock()
+
+	start := time.Now()
+	var timeout <-chan time.Time
+	if l.config.MaxWait > 0 {
+		timer := time.NewTimer(l.config.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-w.ready:
+		return l.releaser(key), nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+	}
+
+	l.mu.Lock()
+	if w.done {
+		// Admitted while giving up; hand the slot back.
+		l.mu.Unlock()
+		l.release(key)
+	} else {
+		l.removeLocked(w)
+		if err == nil {
+			l.rejected[key]++
+			limit, tenant, _ = l.fullLocked(key)
+			err = &ConcurrencyLimitError{Key: key, Tenant: tenant, Limit: limit, Waited: time.Since(start)}
+		}
+		l.mu.Unlock()
+	}
+	return nil, err
+}
+
+func (l *ConcurrencyLimiter) removeLocked(w *concurrencyWaiter) {
+	for i, queued := range l.waiting {
+		if queued == w {
+			l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+func (l *ConcurrencyLimiter) releaser(key string) func() {
+	var once sync.Once
+	return func() { once.Do(func() { l.release(key) }) }
+}
+
+func (l *ConcurrencyLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	if l.perKey[key]--; l.perKey[key] <= 0 {
+		delete(l.perKey, key)
+	}
+	l.dispatchLocked()
+}
+
+// dispatchLocked hands free slots to waiters, always choosing the admissible
+// waiter whose tenant has the fewest calls in flight, oldest first on ties.
+func (l *ConcurrencyLimiter) dispatchLocked() {
+	for {
+		best := -1
+		for i, w := range l.waiting {
+			if _, _, full := l.fullLocked(w.key); full {
+				continue
+			}
+			if best < 0 || l.perKey[w.key] < l.perKey[l.waiting[best].key] {
+				best = i
+			}
+		}
+		if best < 0 {
+			return
+		}
+		w := l.waiting[best]
+		l.waiting = append(l.waiting[:best], l.waiting[best+1:]...)
+		l.admitLocked(w.key)
+		w.done = true
+		close(w.ready)
+	}
+}
+
+// Stats returns current occupancy and rejection counts.
+func (l *ConcurrencyLimiter) Stats() ConcurrencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := ConcurrencyStats{
+		InFlight: l.inFlight,
+		Waiting:  len(l.waiting),
+		PerKey:   make(map[string]int, len(l.perKey)),
+		Rejected: make(map[string]int64, len(l.rejected)),
+	}
+	for key, n := range l.perKey {
+		stats.PerKey[key] = n
+	}
+	for key, n := range l.rejected {
+		stats.Rejected[key] = n
+	}
+	return stats
+}
+
+// Middleware returns middleware that holds a slot for each call.
+func (l *ConcurrencyLimiter) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			release, err := l.Acquire(ctx)
+			if err != nil {
+				return nil, wrapIfNotWormholeError("concurrency_limit", err)
+			}
+			defer release()
+			return next(ctx, req)
+		}
+	}
+}
+
+// ConcurrencyLimitMiddleware bounds in-flight calls to maxInFlight, queueing the
+// excess and sharing freed slots fairly between the tenants perKey returns.
+// Use NewConcurrencyLimiter for per-tenant quotas, rejection instead of
+// queueing, or stats.
+//
+// Example:
+//
+//	client := wormhole.New(
+//	    wormhole.WithOpenAI(apiKey),
+//	    wormhole.WithMiddleware(middleware.ConcurrencyLimitMiddleware(32, middleware.TagKey("tenant"))),
+//	)
+func ConcurrencyLimitMiddleware(maxInFlight int, perKey func(context.Context) string) Middleware {
+	return NewConcurrencyLimiter(ConcurrencyLimitConfig{Max: maxInFlight, Key: perKey}).Middleware()
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tenantContext(tenant string) context.Context {
+	return WithTags(context.Background(), map[string]string{"tenant": tenant})
+}
+
+func TestConcurrencyLimiterRejectsWithTypedErrors(t *testing.T) {
+	t.Parallel()
+	limiter := NewConcurrencyLimiter(ConcurrencyLimitConfig{
+		Max:            3,
+		Key:            TagKey("tenant"),
+		PerKey:         1,
+		Quotas:         map[string]int{"vip": 2},
+		RejectWhenFull: true,
+	})
+
+	acme, err := limiter.Acquire(tenantContext("acme"))
+	require.NoError(t, err)
+	_, err = limiter.Acquire(tenantContext("acme"))
+	var limitErr *ConcurrencyLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.True(t, errors.Is(err, ErrConcurrencyLimited))
+	assert.Equal(t, ConcurrencyLimitError{Key: "acme", Tenant: true, Limit: 1}, *limitErr)
+
+	for i := 0; i < 2; i++ {
+		_, err := limiter.Acquire(tenantContext("vip"))
+		require.NoError(t, err, "vip quota overrides PerKey")
+	}
+	_, err = limiter.Acquire(tenantContext("globex"))
+	require.ErrorAs(t, err, &limitErr)
+	assert.False(t, limitErr.Tenant)
+	assert.Equal(t, 3, limitErr.Limit)
+
+	acme()
+	acme() // release is idempotent
+	stats := limiter.Stats()
+	assert.Equal(t, 2, stats.InFlight)
+	assert.Equal(t, map[string]int{"vip": 2}, stats.PerKey)
+	assert.Equal(t, map[string]int64{"acme": 1, "globex": 1}, stats.Rejected)
+}
+
+func TestConcurrencyLimiterSharesFreedSlotsFairly(t *testing.T) {
+	t.Parallel()
+	limiter := NewConcurrencyLimiter(ConcurrencyLimitConfig{Max: 2, Key: TagKey("tenant")})
+
+	first, err := limiter.Acquire(tenantContext("noisy"))
+	require.NoError(t, err)
+	second, err := limiter.Acquire(tenantContext("noisy"))
+	require.NoError(t, err)
+
+	admitted := make(chan string, 3)
+	queue := func(tenant string) {
+		go func() {
+			release, err := limiter.Acquire(tenantContext(tenant))
+			if err != nil {
+				t.Errorf("Acquire(%s) error = %v", tenant, err)
+				return
+			}
+			admitted <- tenant
+			_ = release
+		}()
+	}
+	queue("noisy")
+	require.Eventually(t, func() bool { return limiter.Stats().Waiting == 1 }, time.Second, time.Millisecond)
+	queue("quiet")
+	require.Eventually(t, func() bool { return limiter.Stats().Waiting == 2 }, time.Second, time.Millisecond)
+
+	first()
+	assert.Equal(t, "quiet", <-admitted, "the tenant with nothing in flight goes first")
+	second()
+	assert.Equal(t, "noisy", <-admitted)
+}
+
+func TestConcurrencyLimiterMaxWaitAndCancel(t *testing.T) {
+	t.Parallel()
+	limiter := NewConcurrencyLimiter(ConcurrencyLimitConfig{Max: 1, MaxWait: 20 * time.Millisecond})
+	hold, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+
+	_, err = limiter.Acquire(context.Background())
+	var limitErr *ConcurrencyLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.GreaterOrEqual(t, limitErr.Waited, 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = limiter.Acquire(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, limiter.Stats().Waiting)
+
+	hold()
+	assert.Equal(t, 0, limiter.Stats().InFlight)
+}
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	t.Parallel()
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	handler := ConcurrencyLimitMiddleware(1, TagKey("tenant"))(func(ctx context.Context, req any) (any, error) {
+		if req == "slow" {
+			close(started)
+			<-unblock
+		}
+		return req, nil
+	})
+
+	go func() { _, _ = handler(tenantContext("acme"), "slow") }()
+	<-started
+	ctx, cancel := context.WithTimeout(tenantContext("globex"), 10*time.Millisecond)
+	defer cancel()
+	_, err := handler(ctx, "fast")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(unblock)
+	resp, err := handler(tenantContext("globex"), "fast")
+	require.NoError(t, err)
+	assert.Equal(t, "fast", resp)
+}
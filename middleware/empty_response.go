@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// EmptyResponseConfig holds empty response middleware configuration
+type EmptyResponseConfig struct {
+	// Retries is how many times a request is sent again after an empty
+	// response before it fails with types.ErrEmptyResponse. Zero fails on the
+	// first empty response.
+	Retries int
+	// Counter, if set, counts empty responses per provider and model.
+	Counter *EmptyResponseCounter
+}
+
+// EmptyResponseMiddleware turns a successful text or structured response with
+// no usable output — empty or whitespace-only text and no tool calls — into
+// types.ErrEmptyResponse, optionally retrying first. Some OpenAI-compatible
+// endpoints occasionally return 200 with an empty completion; without this
+// the caller sees a blank answer instead of an error. Provider errors that
+// already carry types.ErrorCodeEmptyResponse are handled the same way.
+// Streams pass through unchecked.
+//
+// Example usage:
+//
+//	counter := middleware.NewEmptyResponseCounter()
+//	middleware.EmptyResponseMiddleware(middleware.EmptyResponseConfig{
+//	    Retries: 2,
+//	    Counter: counter,
+//	})
+func EmptyResponseMiddleware(config EmptyResponseConfig) Middleware {
+	retries := max(config.Retries, 0)
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if ctx.Value(CtxKeyMethod) == "stream" {
+				resp, err := next(ctx, req)
+				return resp, wrapIfNotWormholeError("empty_response", err)
+			}
+			provider, _ := ctx.Value(CtxKeyProvider).(string)
+			model := requestModel(req)
+
+			for attempt := 0; ; attempt++ {
+				resp, err := next(ctx, req)
+				if err != nil && !types.IsEmptyResponseError(err) {
+					return resp, wrapIfNotWormholeError("empty_response", err)
+				}
+				if err == nil && !isEmptyResponse(resp) {
+					if attempt > 0 {
+						config.Counter.record(provider, model, 0, 1, 0)
+					}
+					return resp, nil
+				}
+
+				config.Counter.record(provider, model, 1, 0, 0)
+				if attempt < retries && ctx.Err() == nil {
+					continue
+				}
+				config.Counter.record(provider, model, 0, 0, 1)
+				if err != nil {
+					return nil, err
+				}
+				emptyErr := types.ErrEmptyResponse.WithProvider(provider).WithModel(model)
+				if attempt > 0 {
+					emptyErr = emptyErr.WithDetails(fmt.Sprintf("empty after %d attempts", attempt+1))
+				}
+				return nil, emptyErr
+			}
+		}
+	}
+}
+
+func isEmptyResponse(resp any) bool {
+	switch r := resp.(type) {
+	case *types.TextResponse:
+		// A deferred completion has no content yet by design.
+		deferred, _ := r.Metadata["deferred"].(bool)
+		return !deferred && r.IsEmpty()
+	case *types.StructuredResponse:
+		return r.IsEmpty()
+	}
+	return false
+}
+
+func requestModel(req any) string {
+	switch r := req.(type) {
+	case *types.TextRequest:
+		return r.Model
+	case *types.StructuredRequest:
+		return r.Model
+	}
+	return ""
+}
+
+// EmptyResponseStats counts empty responses from one provider and model.
+type EmptyResponseStats struct {
+	Provider string
+	Model    string
+	// Empty is every empty response received, including those later retried.
+	Empty int64
+	// Recovered is requests whose retry returned content.
+	Recovered int64
+	// Failed is requests that returned types.ErrEmptyResponse.
+	Failed int64
+}
+
+type emptyResponseKey struct {
+	provider, model string
+}
+
+// EmptyResponseCounter tallies empty responses so providers that return them
+// can be identified. It is safe for concurrent use and may be shared by
+// several middleware instances.
+type EmptyResponseCounter struct {
+	mu    sync.Mutex
+	stats map[emptyResponseKey]*EmptyResponseStats
+}
+
+// NewEmptyResponseCounter creates an empty counter.
+func NewEmptyResponseCounter() *EmptyResponseCounter {
+	return &EmptyResponseCounter{stats: make(map[emptyResponseKey]*EmptyResponseStats)}
+}
+
+// Stats returns a snapshot of the counts, sorted by provider and model.
+func (c *EmptyResponseCounter) Stats() []EmptyResponseStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]EmptyResponseStats, 0, len(c.stats))
+	for _, s := range c.stats {
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b EmptyResponseStats) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Model, b.Model))
+	})
+	return out
+}
+
+func (c *EmptyResponseCounter) record(provider, model string, empty, recovered, failed int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := emptyResponseKey{provider, model}
+	s, ok := c.stats[key]
+	if !ok {
+		s = &EmptyResponseStats{Provider: provider, Model: model}
+		c.stats[key] = s
+	}
+	s.Empty += empty
+	s.Recovered += recovered
+	s.Failed += failed
+}
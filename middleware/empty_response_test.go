@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestEmptyResponseMiddlewareRetriesUntilContent(t *testing.T) {
+	t.Parallel()
+
+	counter := NewEmptyResponseCounter()
+	replies := []string{"", " \n\t", "hello"}
+	calls := 0
+	handler := EmptyResponseMiddleware(EmptyResponseConfig{Retries: 2, Counter: counter})(
+		func(_ context.Context, _ any) (any, error) {
+			calls++
+			return &types.TextResponse{Text: replies[calls-1]}, nil
+		})
+
+	ctx := context.WithValue(context.Background(), CtxKeyProvider, "groq")
+	resp, err := handler(ctx, textRequest("llama", "hi"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", resp.(*types.TextResponse).Text)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []EmptyResponseStats{{Provider: "groq", Model: "llama", Empty: 2, Recovered: 1}}, counter.Stats())
+}
+
+func TestEmptyResponseMiddlewareFailsWithTypedError(t *testing.T) {
+	t.Parallel()
+
+	counter := NewEmptyResponseCounter()
+	calls := 0
+	handler := EmptyResponseMiddleware(EmptyResponseConfig{Retries: 1, Counter: counter})(
+		func(_ context.Context, _ any) (any, error) {
+			calls++
+			return &types.TextResponse{Text: "  "}, nil
+		})
+
+	ctx := context.WithValue(context.Background(), CtxKeyProvider, "groq")
+	_, err := handler(ctx, textRequest("llama", "hi"))
+	require.Error(t, err)
+	assert.True(t, types.IsEmptyResponseError(err))
+	assert.True(t, types.IsRetryableError(err))
+	assert.Contains(t, err.Error(), "empty after 2 attempts")
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []EmptyResponseStats{{Provider: "groq", Model: "llama", Empty: 2, Failed: 1}}, counter.Stats())
+	assert.Equal(t, "empty_response", (&ErrorTypeDetector{}).DetectErrorType(err))
+}
+
+func TestEmptyResponseMiddlewareRetriesProviderEmptyError(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := EmptyResponseMiddleware(EmptyResponseConfig{Retries: 1})(
+		func(_ context.Context, _ any) (any, error) {
+			calls++
+			if calls == 1 {
+				return nil, types.ErrEmptyResponse.WithProvider("openai")
+			}
+			return &types.TextResponse{Text: "ok"}, nil
+		})
+
+	resp, err := handler(context.Background(), textRequest("gpt-5", "hi"))
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.(*types.TextResponse).Text)
+}
+
+func TestEmptyResponseMiddlewareKeepsToolCallsAndStreams(t *testing.T) {
+	t.Parallel()
+
+	handler := EmptyResponseMiddleware(EmptyResponseConfig{})(
+		func(ctx context.Context, _ any) (any, error) {
+			if ctx.Value(CtxKeyMethod) == "stream" {
+				return &types.TextResponse{}, nil
+			}
+			return &types.TextResponse{ToolCalls: []types.ToolCall{{ID: "call_1"}}}, nil
+		})
+
+	_, err := handler(context.Background(), textRequest("gpt-5", "hi"))
+	assert.NoError(t, err, "tool calls are content")
+
+	_, err = handler(context.WithValue(context.Background(), CtxKeyMethod, "stream"), textRequest("gpt-5", "hi"))
+	assert.NoError(t, err, "streams are not checked")
+}
+
+func TestEmptyResponseMiddlewareStructured(t *testing.T) {
+	t.Parallel()
+
+	handler := EmptyResponseMiddleware(EmptyResponseConfig{})(
+		func(_ context.Context, _ any) (any, error) {
+			return &types.StructuredResponse{Raw: "\n"}, nil
+		})
+
+	_, err := handler(context.Background(), &types.StructuredRequest{BaseRequest: types.BaseRequest{Model: "gpt-5"}})
+	require.Error(t, err)
+	assert.True(t, types.IsEmptyResponseError(err))
+}
@@ -1,6 +1,10 @@
 package middleware
 
-import "github.com/garyblankenship/wormhole/v2/types"
+import (
+	"errors"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
 
 type circuitErrorClass int
 
@@ -31,6 +35,11 @@ func circuitFailureWeight(err error, threshold int) int {
 	if threshold < 1 {
 		threshold = 1
 	}
+	// An exhausted retry budget means the provider is already failing at a
+	// rate the caller chose not to amplify; open immediately.
+	if errors.Is(err, ErrRetryBudgetExceeded) {
+		return threshold
+	}
 	switch classifyCircuitError(err) {
 	case circuitErrorRateLimit, circuitErrorQuota, circuitErrorAuth, circuitErrorConfig:
 		return threshold
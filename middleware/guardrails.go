@@ -0,0 +1,353 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// Guardrail inspects one piece of prompt or response text. It returns the text
+// to use in its place — unchanged, or rewritten as RedactPII does — or an error
+// to block the request. Errors that are not already WormholeErrors surface as
+// types.ErrContentBlocked.
+type Guardrail func(ctx context.Context, text string) (string, error)
+
+// GuardrailsConfig holds guardrails middleware configuration
+type GuardrailsConfig struct {
+	// Input runs, in order, on prompts before they are sent: user and system
+	// messages, the system prompt, and embedding inputs.
+	Input []Guardrail
+	// Output runs, in order, on text and structured responses before they are
+	// returned. Streams are checked on input only.
+	Output []Guardrail
+}
+
+// GuardrailsMiddleware filters prompts before they reach the provider and
+// responses before they reach the caller. A blocked request fails with an
+// error whose code is types.ErrorCodeContentBlocked; a blocked prompt is never
+// sent. The caller's request is not modified when a guardrail rewrites it.
+//
+// Example usage:
+//
+//	middleware.GuardrailsMiddleware(middleware.GuardrailsConfig{
+//	    Input:  []middleware.Guardrail{middleware.MaxLength(8000), middleware.RedactPII()},
+//	    Output: []middleware.Guardrail{middleware.BlockKeywords("internal use only")},
+//	})
+func GuardrailsMiddleware(config GuardrailsConfig) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			guarded, err := guardRequest(ctx, req, config.Input)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := next(ctx, guarded)
+			if err != nil || len(config.Output) == 0 || ctx.Value(CtxKeyMethod) == "stream" {
+				return resp, wrapIfNotWormholeError("guardrails", err)
+			}
+			return guardResponse(ctx, resp, config.Output)
+		}
+	}
+}
+
+func guardRequest(ctx context.Context, req any, guardrails []Guardrail) (any, error) {
+	if len(guardrails) == 0 {
+		return req, nil
+	}
+	var err error
+	switch r := req.(type) {
+	case *types.TextRequest:
+		guarded := *r
+		if guarded.SystemPrompt, err = runGuardrails(ctx, "input", guardrails, r.SystemPrompt); err != nil {
+			return nil, err
+		}
+		if guarded.Messages, err = guardMessages(ctx, r.Messages, guardrails); err != nil {
+			return nil, err
+		}
+		return &guarded, nil
+	case *types.StructuredRequest:
+		guarded := *r
+		if guarded.SystemPrompt, err = runGuardrails(ctx, "input", guardrails, r.SystemPrompt); err != nil {
+			return nil, err
+		}
+		if guarded.Messages, err = guardMessages(ctx, r.Messages, guardrails); err != nil {
+			return nil, err
+		}
+		return &guarded, nil
+	case *types.EmbeddingsRequest:
+		guarded := *r
+		guarded.Input = make([]string, len(r.Input))
+		for i, input := range r.Input {
+			if guarded.Input[i], err = runGuardrails(ctx, "input", guardrails, input); err != nil {
+				return nil, err
+			}
+		}
+		return &guarded, nil
+	}
+	return req, nil
+}
+
+// guardMessages checks user and system messages, copying any it rewrites.
+// Assistant turns and tool results are the model's and tools' own output.
+func guardMessages(ctx context.Context, messages []types.Message, guardrails []Guardrail) ([]types.Message, error) {
+	out := make([]types.Message, len(messages))
+	for i, msg := range messages {
+		out[i] = msg
+		switch m := msg.(type) {
+		case *types.UserMessage:
+			text, err := runGuardrails(ctx, "input", guardrails, m.Content)
+			if err != nil {
+				return nil, err
+			}
+			if text != m.Content {
+				out[i] = &types.UserMessage{Content: text, Media: m.Media}
+			}
+		case *types.SystemMessage:
+			text, err := runGuardrails(ctx, "input", guardrails, m.Content)
+			if err != nil {
+				return nil, err
+			}
+			if text != m.Content {
+				out[i] = &types.SystemMessage{Content: text}
+			}
+		case *types.DeveloperMessage:
+			text, err := runGuardrails(ctx, "input", guardrails, m.Content)
+			if err != nil {
+				return nil, err
+			}
+			if text != m.Content {
+				out[i] = &types.DeveloperMessage{Content: text}
+			}
+		}
+	}
+	return out, nil
+}
+
+func guardResponse(ctx context.Context, resp any, guardrails []Guardrail) (any, error) {
+	switch r := resp.(type) {
+	case *types.TextResponse:
+		text, err := runGuardrails(ctx, "output", guardrails, r.Text)
+		if err != nil {
+			return nil, err
+		}
+		guarded := *r
+		guarded.Text = text
+		return &guarded, nil
+	case *types.StructuredResponse:
+		raw := r.Raw
+		if raw == "" {
+			data, err := json.Marshal(r.Data)
+			if err != nil {
+				return nil, wrapIfNotWormholeError("guardrails", err)
+			}
+			raw = string(data)
+		}
+		text, err := runGuardrails(ctx, "output", guardrails, raw)
+		if err != nil {
+			return nil, err
+		}
+		if text == raw {
+			return resp, nil
+		}
+		guarded := *r
+		guarded.Raw = text
+		if err := json.Unmarshal([]byte(text), &guarded.Data); err != nil {
+			return nil, types.ErrContentBlocked.WithDetails("output: rewritten structured response is not valid JSON").WithCause(err)
+		}
+		return &guarded, nil
+	}
+	return resp, nil
+}
+
+// runGuardrails applies guardrails in order. Empty text is skipped.
+func runGuardrails(ctx context.Context, stage string, guardrails []Guardrail, text string) (string, error) {
+	if text == "" {
+		return text, nil
+	}
+	for _, guardrail := range guardrails {
+		out, err := guardrail(ctx, text)
+		if err != nil {
+			if types.IsWormholeError(err) {
+				return "", err
+			}
+			return "", types.ErrContentBlocked.WithDetails(stage + ": " + err.Error()).WithCause(err)
+		}
+		text = out
+	}
+	return text, nil
+}
+
+// BlockKeywords blocks text containing any keyword, ignoring case.
+func BlockKeywords(keywords ...string) Guardrail {
+	lowered := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword != "" {
+			lowered = append(lowered, strings.ToLower(keyword))
+		}
+	}
+	return func(_ context.Context, text string) (string, error) {
+		lower := strings.ToLower(text)
+		for _, keyword := range lowered {
+			if strings.Contains(lower, keyword) {
+				return "", fmt.Errorf("blocked keyword %q", keyword)
+			}
+		}
+		return text, nil
+	}
+}
+
+// BlockPatterns blocks text matching any pattern.
+func BlockPatterns(patterns ...*regexp.Regexp) Guardrail {
+	return func(_ context.Context, text string) (string, error) {
+		for _, pattern := range patterns {
+			if pattern.MatchString(text) {
+				return "", fmt.Errorf("blocked pattern %q", pattern.String())
+			}
+		}
+		return text, nil
+	}
+}
+
+// BlockModerated blocks text that moderator classifies as harmful: any of
+// categories (or any category, when none are given) scoring at least
+// threshold, or flagged by the moderator when threshold is zero. A failed
+// moderation call fails the request rather than letting the text through.
+func BlockModerated(moderator types.Moderator, threshold float64, categories ...types.ModerationCategory) Guardrail {
+	return func(ctx context.Context, text string) (string, error) {
+		if strings.TrimSpace(text) == "" {
+			return text, nil
+		}
+		resp, err := moderator.Moderate(ctx, types.ModerationRequest{Input: []string{text}})
+		if err != nil {
+			if types.IsWormholeError(err) {
+				return "", err
+			}
+			return "", types.NewWormholeError(types.ErrorCodeMiddleware, "moderation failed", false).WithCause(err)
+		}
+		for _, result := range resp.Results {
+			for _, category := range result.Exceeds(threshold) {
+				if len(categories) == 0 || slices.Contains(categories, category) {
+					return "", fmt.Errorf("moderation flagged %s", category)
+				}
+			}
+		}
+		return text, nil
+	}
+}
+
+// MaxLength blocks text longer than maxChars characters.
+func MaxLength(maxChars int) Guardrail {
+	return func(_ context.Context, text string) (string, error) {
+		if n := utf8.RuneCountInString(text); n > maxChars {
+			return "", fmt.Errorf("text length %d exceeds %d characters", n, maxChars)
+		}
+		return text, nil
+	}
+}
+
+// PIIKind names a category of personally identifiable information.
+type PIIKind string
+
+const (
+	PIIEmail      PIIKind = "email"
+	PIIPhone      PIIKind = "phone"
+	PIICreditCard PIIKind = "credit_card"
+	PIISSN        PIIKind = "ssn"
+	PIIIPAddress  PIIKind = "ip_address"
+)
+
+// piiDetectors run in this order so that longer digit runs (cards, SSNs) are
+// claimed before the phone pattern can match part of them.
+var piiDetectors = []struct {
+	kind    PIIKind
+	pattern *regexp.Regexp
+	valid   func(string) bool
+}{
+	{PIICreditCard, regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), luhnValid},
+	{PIISSN, regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), nil},
+	{PIIPhone, regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)|\b\d{3})[\s.-]?\d{3}[\s.-]?\d{4}\b`), nil},
+	{PIIEmail, regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), nil},
+	{PIIIPAddress, regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`), nil},
+}
+
+// RedactPII replaces detected PII with a placeholder such as
+// "[REDACTED_EMAIL]". With no kinds it redacts every supported kind.
+// Detection is pattern-based and tuned for common formats, not a guarantee.
+func RedactPII(kinds ...PIIKind) Guardrail {
+	return func(_ context.Context, text string) (string, error) {
+		for _, detector := range piiDetectors {
+			if !piiKindSelected(detector.kind, kinds) {
+				continue
+			}
+			placeholder := "[REDACTED_" + strings.ToUpper(string(detector.kind)) + "]"
+			text = detector.pattern.ReplaceAllStringFunc(text, func(match string) string {
+				if detector.valid != nil && !detector.valid(match) {
+					return match
+				}
+				return placeholder
+			})
+		}
+		return text, nil
+	}
+}
+
+// BlockPII blocks text containing PII of the given kinds, or of any supported
+// kind when none are given.
+func BlockPII(kinds ...PIIKind) Guardrail {
+	return func(_ context.Context, text string) (string, error) {
+		if found := DetectPII(text, kinds...); len(found) > 0 {
+			return "", fmt.Errorf("contains %s", found[0])
+		}
+		return text, nil
+	}
+}
+
+// DetectPII reports which kinds of PII text contains, in detection order.
+// With no kinds it checks every supported kind.
+func DetectPII(text string, kinds ...PIIKind) []PIIKind {
+	var found []PIIKind
+	for _, detector := range piiDetectors {
+		if !piiKindSelected(detector.kind, kinds) {
+			continue
+		}
+		for _, match := range detector.pattern.FindAllString(text, -1) {
+			if detector.valid == nil || detector.valid(match) {
+				found = append(found, detector.kind)
+				break
+			}
+		}
+	}
+	return found
+}
+
+func piiKindSelected(kind PIIKind, kinds []PIIKind) bool {
+	return len(kinds) == 0 || slices.Contains(kinds, kind)
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum, which
+// filters order numbers and other long digit runs out of card detection.
+func luhnValid(s string) bool {
+	sum, digits := 0, 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+		double = !double
+	}
+	return digits >= 13 && sum%10 == 0
+}
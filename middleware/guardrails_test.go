@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestGuardrailsMiddlewareRedactsInputWithoutMutatingCaller(t *testing.T) {
+	t.Parallel()
+
+	var sent *types.TextRequest
+	handler := GuardrailsMiddleware(GuardrailsConfig{Input: []Guardrail{RedactPII()}})(
+		func(_ context.Context, req any) (any, error) {
+			sent = req.(*types.TextRequest)
+			return &types.TextResponse{Text: "ok"}, nil
+		})
+
+	req := textRequest("gpt-5", "mail jane@example.com or call 415-555-0134")
+	req.SystemPrompt = "support agent"
+	_, err := handler(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "mail [REDACTED_EMAIL] or call [REDACTED_PHONE]", sent.Messages[0].GetContent())
+	assert.Equal(t, "support agent", sent.SystemPrompt)
+	assert.Equal(t, "mail jane@example.com or call 415-555-0134", req.Messages[0].GetContent(), "caller's request is untouched")
+}
+
+func TestGuardrailsMiddlewareBlocksInputBeforeSending(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := GuardrailsMiddleware(GuardrailsConfig{
+		Input: []Guardrail{BlockKeywords("Project Falcon")},
+	})(func(_ context.Context, _ any) (any, error) {
+		calls++
+		return &types.TextResponse{Text: "ok"}, nil
+	})
+
+	_, err := handler(context.Background(), textRequest("gpt-5", "status of project falcon?"))
+	require.Error(t, err)
+	assert.True(t, types.IsContentBlockedError(err))
+	assert.Contains(t, err.Error(), "input: blocked keyword")
+	assert.Zero(t, calls, "blocked prompt is never sent")
+}
+
+func TestGuardrailsMiddlewareFiltersOutput(t *testing.T) {
+	t.Parallel()
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+		handler := GuardrailsMiddleware(GuardrailsConfig{
+			Output: []Guardrail{BlockPatterns(regexp.MustCompile(`(?i)password:\s*\S+`))},
+		})(func(_ context.Context, _ any) (any, error) {
+			return &types.TextResponse{Text: "the password: hunter2"}, nil
+		})
+		_, err := handler(context.Background(), textRequest("gpt-5", "hi"))
+		require.Error(t, err)
+		assert.True(t, types.IsContentBlockedError(err))
+		assert.Contains(t, err.Error(), "output:")
+	})
+
+	t.Run("structured", func(t *testing.T) {
+		t.Parallel()
+		handler := GuardrailsMiddleware(GuardrailsConfig{Output: []Guardrail{RedactPII(PIISSN)}})(
+			func(_ context.Context, _ any) (any, error) {
+				return &types.StructuredResponse{Data: map[string]any{"name": "Ann", "ssn": "123-45-6789"}}, nil
+			})
+		resp, err := handler(context.Background(), &types.StructuredRequest{})
+		require.NoError(t, err)
+		structured := resp.(*types.StructuredResponse)
+		assert.Equal(t, map[string]any{"name": "Ann", "ssn": "[REDACTED_SSN]"}, structured.Data)
+		assert.JSONEq(t, `{"name":"Ann","ssn":"[REDACTED_SSN]"}`, structured.Raw)
+	})
+
+	t.Run("streams skip output", func(t *testing.T) {
+		t.Parallel()
+		handler := GuardrailsMiddleware(GuardrailsConfig{Output: []Guardrail{MaxLength(1)}})(
+			func(_ context.Context, _ any) (any, error) {
+				return make(<-chan types.StreamChunk), nil
+			})
+		ctx := context.WithValue(context.Background(), CtxKeyMethod, "stream")
+		_, err := handler(ctx, textRequest("gpt-5", "hi"))
+		assert.NoError(t, err)
+	})
+}
+
+func TestGuardrailsMiddlewareCustomGuardrail(t *testing.T) {
+	t.Parallel()
+
+	errOffTopic := errors.New("off topic")
+	onTopic := func(_ context.Context, text string) (string, error) {
+		if text == "tell me a joke" {
+			return "", errOffTopic
+		}
+		return text, nil
+	}
+	handler := GuardrailsMiddleware(GuardrailsConfig{Input: []Guardrail{onTopic}})(
+		func(_ context.Context, _ any) (any, error) { return &types.TextResponse{}, nil })
+
+	_, err := handler(context.Background(), textRequest("gpt-5", "tell me a joke"))
+	assert.True(t, types.IsContentBlockedError(err))
+	assert.ErrorIs(t, err, errOffTopic)
+}
+
+func TestGuardrailsMiddlewareEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	var sent *types.EmbeddingsRequest
+	handler := GuardrailsMiddleware(GuardrailsConfig{Input: []Guardrail{RedactPII(PIIEmail)}})(
+		func(_ context.Context, req any) (any, error) {
+			sent = req.(*types.EmbeddingsRequest)
+			return &types.EmbeddingsResponse{}, nil
+		})
+	_, err := handler(context.Background(), &types.EmbeddingsRequest{Input: []string{"a@b.io", "plain"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"[REDACTED_EMAIL]", "plain"}, sent.Input)
+}
+
+func TestDetectPII(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		want []PIIKind
+	}{
+		{text: "card 4111 1111 1111 1111", want: []PIIKind{PIICreditCard}},
+		{text: "order 4111 1111 1111 1112", want: nil},
+		{text: "ssn 123-45-6789", want: []PIIKind{PIISSN}},
+		{text: "call +1 (415) 555-0134", want: []PIIKind{PIIPhone}},
+		{text: "host 10.0.0.12", want: []PIIKind{PIIIPAddress}},
+		{text: "nothing here", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, DetectPII(tt.text))
+		})
+	}
+
+	_, err := BlockPII(PIIEmail)(context.Background(), "x@y.com")
+	assert.EqualError(t, err, "contains email")
+	_, err = MaxLength(3)(context.Background(), "héllo")
+	assert.EqualError(t, err, "text length 5 exceeds 3 characters")
+}
+
+type stubModerator struct {
+	result types.ModerationResult
+	err    error
+}
+
+func (m stubModerator) Moderate(_ context.Context, request types.ModerationRequest) (*types.ModerationResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	results := make([]types.ModerationResult, len(request.Input))
+	for i := range results {
+		results[i] = m.result
+	}
+	return &types.ModerationResponse{Results: results}, nil
+}
+
+func TestBlockModerated(t *testing.T) {
+	t.Parallel()
+	moderator := stubModerator{result: types.ModerationResult{
+		Flagged:    true,
+		Categories: map[types.ModerationCategory]bool{types.ModerationViolence: true},
+		Scores:     map[types.ModerationCategory]float64{types.ModerationViolence: 0.6, types.ModerationHate: 0.1},
+	}}
+	ctx := context.Background()
+
+	_, err := BlockModerated(moderator, 0)(ctx, "text")
+	assert.EqualError(t, err, "moderation flagged violence")
+
+	out, err := BlockModerated(moderator, 0.9)(ctx, "text")
+	require.NoError(t, err, "scores under the threshold pass")
+	assert.Equal(t, "text", out)
+
+	_, err = BlockModerated(moderator, 0.5, types.ModerationHate)(ctx, "text")
+	assert.NoError(t, err, "only the listed categories block")
+
+	_, err = BlockModerated(stubModerator{err: errors.New("down")}, 0)(ctx, "text")
+	require.Error(t, err)
+	assert.False(t, types.IsContentBlockedError(err), "a failed moderation call is not a content block")
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// Typed is middleware for one operation with concrete request and response
+// types, such as Typed[types.TextRequest, *types.TextResponse]. It calls next
+// to continue the chain and may change the request or the response, or answer
+// without calling next at all. No type assertions are involved.
+type Typed[Req any, Resp any] func(ctx context.Context, req Req, next func(context.Context, Req) (Resp, error)) (Resp, error)
+
+// wrapTyped turns m into a handler wrapper; a nil m leaves next untouched.
+func wrapTyped[Req any, Resp any, H ~func(context.Context, Req) (Resp, error)](m Typed[Req, Resp], next H) H {
+	if m == nil {
+		return next
+	}
+	return func(ctx context.Context, req Req) (Resp, error) {
+		return m(ctx, req, next)
+	}
+}
+
+// Hooks is a types.ProviderMiddleware assembled from per-operation functions,
+// so middleware that only cares about text does not have to implement the
+// other six Apply methods. Nil fields pass calls through unchanged. Register
+// it with wormhole.WithProviderMiddleware like any other typed middleware.
+//
+// For text calls OnTextRequest runs first, then Text (or Stream), then the
+// provider; OnTextResponse sees what Text returned.
+//
+// Example:
+//
+//	audit := middleware.Hooks{
+//	    OnTextRequest: func(ctx context.Context, req *types.TextRequest) error {
+//	        if req.MaxTokens == nil {
+//	            limit := 1024
+//	            req.MaxTokens = &limit
+//	        }
+//	        return nil
+//	    },
+//	    OnTextResponse: func(ctx context.Context, req types.TextRequest, resp *types.TextResponse, err error) {
+//	        if err == nil {
+//	            log.Printf("%s used %d tokens", req.Model, resp.Usage.TotalTokens)
+//	        }
+//	    },
+//	    Embeddings: func(ctx context.Context, req types.EmbeddingsRequest, next func(context.Context, types.EmbeddingsRequest) (*types.EmbeddingsResponse, error)) (*types.EmbeddingsResponse, error) {
+//	        req.Input = normalize(req.Input)
+//	        return next(ctx, req)
+//	    },
+//	}
+//	client := wormhole.New(wormhole.WithProviderMiddleware(audit))
+type Hooks struct {
+	Text        Typed[types.TextRequest, *types.TextResponse]
+	Stream      Typed[types.TextRequest, <-chan types.StreamChunk]
+	Structured  Typed[types.StructuredRequest, *types.StructuredResponse]
+	Embeddings  Typed[types.EmbeddingsRequest, *types.EmbeddingsResponse]
+	Audio       Typed[types.AudioRequest, *types.AudioResponse]
+	AudioStream Typed[types.AudioRequest, <-chan types.TextChunk]
+	Image       Typed[types.ImageRequest, *types.ImageResponse]
+	Rerank      Typed[types.RerankRequest, *types.RerankResponse]
+
+	// OnTextRequest runs before every Text and Stream call and may edit the
+	// request in place. An error fails the call before it reaches the
+	// provider.
+	OnTextRequest func(ctx context.Context, req *types.TextRequest) error
+	// OnTextResponse observes the outcome of every Text call.
+	OnTextResponse func(ctx context.Context, req types.TextRequest, resp *types.TextResponse, err error)
+}
+
+var (
+	_ types.ProviderMiddleware    = Hooks{}
+	_ types.AudioStreamMiddleware = Hooks{}
+)
+
+// withTextRequest runs OnTextRequest ahead of next.
+func withTextRequest[Resp any](hook func(context.Context, *types.TextRequest) error, next func(context.Context, types.TextRequest) (Resp, error)) func(context.Context, types.TextRequest) (Resp, error) {
+	if hook == nil {
+		return next
+	}
+	return func(ctx context.Context, req types.TextRequest) (Resp, error) {
+		if err := hook(ctx, &req); err != nil {
+			var zero Resp
+			return zero, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// ApplyText wraps text generation calls with OnTextRequest, Text and
+// OnTextResponse.
+func (h Hooks) ApplyText(next types.TextHandler) types.TextHandler {
+	handler := wrapTyped(h.Text, next)
+	if h.OnTextResponse != nil {
+		inner := handler
+		handler = func(ctx context.Context, req types.TextRequest) (*types.TextResponse, error) {
+			resp, err := inner(ctx, req)
+			h.OnTextResponse(ctx, req, resp, err)
+			return resp, err
+		}
+	}
+	return withTextRequest(h.OnTextRequest, handler)
+}
+
+// ApplyStream wraps streaming calls with OnTextRequest and Stream.
+func (h Hooks) ApplyStream(next types.StreamHandler) types.StreamHandler {
+	return withTextRequest(h.OnTextRequest, wrapTyped(h.Stream, next))
+}
+
+// ApplyStructured wraps structured output calls with Structured.
+func (h Hooks) ApplyStructured(next types.StructuredHandler) types.StructuredHandler {
+	return wrapTyped(h.Structured, next)
+}
+
+// ApplyEmbeddings wraps embeddings calls with Embeddings.
+func (h Hooks) ApplyEmbeddings(next types.EmbeddingsHandler) types.EmbeddingsHandler {
+	return wrapTyped(h.Embeddings, next)
+}
+
+// ApplyAudio wraps audio calls with Audio.
+func (h Hooks) ApplyAudio(next types.AudioHandler) types.AudioHandler {
+	return wrapTyped(h.Audio, next)
+}
+
+// ApplyAudioStream wraps streamed transcriptions with AudioStream.
+func (h Hooks) ApplyAudioStream(next types.AudioStreamHandler) types.AudioStreamHandler {
+	return wrapTyped(h.AudioStream, next)
+}
+
+// ApplyImage wraps image generation, edit and variation calls with Image.
+func (h Hooks) ApplyImage(next types.ImageHandler) types.ImageHandler {
+	return wrapTyped(h.Image, next)
+}
+
+// ApplyRerank wraps rerank calls with Rerank.
+func (h Hooks) ApplyRerank(next types.RerankHandler) types.RerankHandler {
+	return wrapTyped(h.Rerank, next)
+}
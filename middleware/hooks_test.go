@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestHooksTextOrder(t *testing.T) {
+	t.Parallel()
+	var order []string
+	hooks := Hooks{
+		OnTextRequest: func(_ context.Context, req *types.TextRequest) error {
+			order = append(order, "request")
+			req.Model = "rewritten"
+			return nil
+		},
+		Text: func(ctx context.Context, req types.TextRequest, next func(context.Context, types.TextRequest) (*types.TextResponse, error)) (*types.TextResponse, error) {
+			order = append(order, "text:"+req.Model)
+			resp, err := next(ctx, req)
+			if resp != nil {
+				resp.Text += "!"
+			}
+			return resp, err
+		},
+		OnTextResponse: func(_ context.Context, req types.TextRequest, resp *types.TextResponse, err error) {
+			order = append(order, "response:"+resp.Text)
+		},
+	}
+
+	handler := types.NewProviderChain(hooks).ApplyText(func(_ context.Context, req types.TextRequest) (*types.TextResponse, error) {
+		order = append(order, "provider:"+req.Model)
+		return &types.TextResponse{Text: "ok"}, nil
+	})
+	resp, err := handler(context.Background(), types.TextRequest{BaseRequest: types.BaseRequest{Model: "m"}})
+	if err != nil {
+		t.Fatalf("ApplyText error = %v", err)
+	}
+	if resp.Text != "ok!" {
+		t.Fatalf("text = %q", resp.Text)
+	}
+	want := []string{"request", "text:rewritten", "provider:rewritten", "response:ok!"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestHooksRequestErrorSkipsProvider(t *testing.T) {
+	t.Parallel()
+	blocked := errors.New("blocked")
+	hooks := Hooks{OnTextRequest: func(context.Context, *types.TextRequest) error { return blocked }}
+
+	called := false
+	_, err := hooks.ApplyStream(func(context.Context, types.TextRequest) (<-chan types.StreamChunk, error) {
+		called = true
+		return nil, nil
+	})(context.Background(), types.TextRequest{})
+	if !errors.Is(err, blocked) || called {
+		t.Fatalf("err = %v, provider called = %v", err, called)
+	}
+}
+
+func TestHooksNilFieldsPassThrough(t *testing.T) {
+	t.Parallel()
+	hooks := Hooks{
+		Embeddings: func(ctx context.Context, req types.EmbeddingsRequest, next func(context.Context, types.EmbeddingsRequest) (*types.EmbeddingsResponse, error)) (*types.EmbeddingsResponse, error) {
+			req.Input = append(req.Input, "added")
+			return next(ctx, req)
+		},
+	}
+
+	embeddings, err := hooks.ApplyEmbeddings(func(_ context.Context, req types.EmbeddingsRequest) (*types.EmbeddingsResponse, error) {
+		return &types.EmbeddingsResponse{Model: req.Input[len(req.Input)-1]}, nil
+	})(context.Background(), types.EmbeddingsRequest{Input: []string{"a"}})
+	if err != nil || embeddings.Model != "added" {
+		t.Fatalf("embeddings = %#v, err = %v", embeddings, err)
+	}
+
+	rerank, err := hooks.ApplyRerank(func(context.Context, types.RerankRequest) (*types.RerankResponse, error) {
+		return &types.RerankResponse{Model: "direct"}, nil
+	})(context.Background(), types.RerankRequest{})
+	if err != nil || rerank.Model != "direct" {
+		t.Fatalf("rerank = %#v, err = %v", rerank, err)
+	}
+}
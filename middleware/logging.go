@@ -37,7 +37,8 @@ func DetailedLoggingMiddleware(config LoggingConfig) Middleware {
 			duration := time.Since(start)
 
 			if config.LogTiming {
-				config.Logger.Debug("Request completed", "duration", duration)
+				args := append([]any{"duration", duration}, requestMetadataAttrs(ctx)...)
+				config.Logger.Debug("Request completed", args...)
 			}
 
 			if config.LogResponses && resp != nil {
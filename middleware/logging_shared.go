@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"reflect"
+	"sort"
 	"time"
 
 	"github.com/garyblankenship/wormhole/v2/types"
@@ -59,6 +60,15 @@ func requestMetadataAttrs(ctx context.Context) []any {
 			attrs = append(attrs, slog.String(field.name, types.SafeLogString(boundedMetadata(value))))
 		}
 	}
+	tags := TagsFromContext(ctx)
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		attrs = append(attrs, slog.String("tag_"+types.SafeLogString(boundedMetadata(name)), types.SafeLogString(boundedMetadata(tags[name]))))
+	}
 	return attrs
 }
 
@@ -184,6 +194,8 @@ func logAudioRequestDetails(logger types.Logger, request types.AudioRequest) {
 	switch request.Type {
 	case types.AudioRequestTypeSTT:
 		logger.Debug("Type", "value", "Speech to Text")
+	case types.AudioRequestTypeTranslation:
+		logger.Debug("Type", "value", "Translation")
 	case types.AudioRequestTypeTTS:
 		logger.Debug("Type", "value", "Text to Speech")
 		if request.Voice != "" {
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 // EnhancedMetricsConfig holds configuration for enhanced metrics collection
@@ -41,8 +43,9 @@ func DefaultEnhancedMetricsConfig() *EnhancedMetricsConfig {
 type RequestLabels struct {
 	Provider  string
 	Model     string
-	Method    string // text, stream, structured, embeddings, audio, image
-	ErrorType string // auth, rate_limit, timeout, provider, network, unknown
+	Method    string            // text, stream, structured, embeddings, audio, image
+	ErrorType string            // auth, rate_limit, timeout, provider, network, unknown
+	Tags      map[string]string // caller tags from WithTags, e.g. tenant or feature
 }
 
 // String returns a string representation of the labels for use as map key
@@ -50,7 +53,11 @@ func (l *RequestLabels) String() string {
 	if l == nil {
 		return ""
 	}
-	return fmt.Sprintf("%s:%s:%s:%s", l.Provider, l.Model, l.Method, l.ErrorType)
+	key := fmt.Sprintf("%s:%s:%s:%s", l.Provider, l.Model, l.Method, l.ErrorType)
+	if len(l.Tags) > 0 {
+		key += ":" + formatTags(l.Tags)
+	}
+	return key
 }
 
 // EnhancedMetricsCollector collects enhanced metrics with labels and histograms
@@ -63,6 +70,9 @@ type EnhancedMetricsCollector struct {
 	// Per-label metrics (if LabelAggregation is true)
 	perLabel *sync.Map // map[string]*enhancedMetricsBucket
 
+	// Per-tag metrics, keyed by "name=value" (if EnableLabels is true)
+	perTag *sync.Map // map[string]*enhancedMetricsBucket
+
 	// Histogram buckets (shared across all metrics)
 	buckets []float64
 
@@ -82,6 +92,14 @@ type enhancedMetricsBucket struct {
 	inputTokens  int64 // atomic
 	outputTokens int64 // atomic
 
+	// Cache statistics (see RecordCache)
+	cacheHits         int64 // atomic, local exact-match hits
+	semanticCacheHits int64 // atomic
+	promptTokens      int64 // atomic, provider-reported, fresh responses only
+	cachedTokens      int64 // atomic, provider prompt-cache reads
+	cacheWriteTokens  int64 // atomic
+	cacheSavedTokens  int64 // atomic, see types.CacheInfo.SavedTokens
+
 	// Histogram data - using fixed-size array with atomic operations
 	histogramCounts []int64 // atomic slices for each bucket + overflow
 }
@@ -95,6 +113,10 @@ func (d *ErrorTypeDetector) DetectErrorType(err error) string {
 		return ""
 	}
 
+	if types.IsEmptyResponseError(err) {
+		return "empty_response"
+	}
+
 	errStr := err.Error()
 
 	// Check for common error patterns
@@ -129,6 +151,7 @@ func NewEnhancedMetricsCollector(config *EnhancedMetricsConfig) *EnhancedMetrics
 		config:        config,
 		global:        newEnhancedMetricsBucket(config.DefaultHistogramBuckets),
 		perLabel:      &sync.Map{},
+		perTag:        &sync.Map{},
 		buckets:       config.DefaultHistogramBuckets,
 		errorDetector: &ErrorTypeDetector{},
 	}
@@ -149,31 +172,76 @@ func (c *EnhancedMetricsCollector) RecordRequest(labels *RequestLabels, duration
 		errorType = c.errorDetector.DetectErrorType(err)
 	}
 
-	// Create or get labels if enabled
-	var bucketLabels *RequestLabels
-	if c.config.EnableLabels && labels != nil {
-		bucketLabels = &RequestLabels{
-			Provider:  labels.Provider,
-			Model:     labels.Model,
-			Method:    labels.Method,
-			ErrorType: errorType,
+	bucketLabels := c.bucketLabels(labels, errorType)
+	c.bucketFor(bucketLabels).record(c.buckets, duration, err != nil, retries, inputTokens, outputTokens)
+	if bucketLabels != nil {
+		for name, value := range bucketLabels.Tags {
+			c.tagBucket(name, value).record(c.buckets, duration, err != nil, retries, inputTokens, outputTokens)
+		}
+	}
+
+	// TODO: concurrency gauge tracking - increment at request start, decrement at request end
+}
+
+// RecordCache records the cache statistics of a successful text or structured
+// response: local and semantic cache hits, provider prompt-cache token reads
+// and writes, and the tokens caching saved. They appear in GetStats next to
+// the request counts for the same labels, so caching ROI can be compared
+// across providers and models.
+func (c *EnhancedMetricsCollector) RecordCache(labels *RequestLabels, info types.CacheInfo) {
+	bucketLabels := c.bucketLabels(labels, "")
+	c.bucketFor(bucketLabels).recordCache(info)
+	if bucketLabels != nil {
+		for name, value := range bucketLabels.Tags {
+			c.tagBucket(name, value).recordCache(info)
 		}
 	}
+}
 
-	// Get the metrics bucket
-	var bucket *enhancedMetricsBucket
-	if c.config.LabelAggregation && bucketLabels != nil {
-		key := bucketLabels.String()
-		actual, _ := c.perLabel.LoadOrStore(key, newEnhancedMetricsBucket(c.buckets))
-		bucket = actual.(*enhancedMetricsBucket)
-	} else {
-		bucket = c.global
+// bucketLabels returns the labels metrics are keyed by, or nil when labels
+// are disabled.
+func (c *EnhancedMetricsCollector) bucketLabels(labels *RequestLabels, errorType string) *RequestLabels {
+	if !c.config.EnableLabels || labels == nil {
+		return nil
+	}
+	return &RequestLabels{
+		Provider:  labels.Provider,
+		Model:     labels.Model,
+		Method:    labels.Method,
+		ErrorType: errorType,
+		Tags:      labels.Tags,
 	}
+}
 
-	// Record metrics
-	bucket.record(c.buckets, duration, err != nil, retries, inputTokens, outputTokens)
+// bucketFor returns the per-label bucket for labels, or the global bucket.
+func (c *EnhancedMetricsCollector) bucketFor(labels *RequestLabels) *enhancedMetricsBucket {
+	if !c.config.LabelAggregation || labels == nil {
+		return c.global
+	}
+	actual, _ := c.perLabel.LoadOrStore(labels.String(), newEnhancedMetricsBucket(c.buckets))
+	return actual.(*enhancedMetricsBucket)
+}
 
-	// TODO: concurrency gauge tracking - increment at request start, decrement at request end
+func (c *EnhancedMetricsCollector) tagBucket(name, value string) *enhancedMetricsBucket {
+	actual, _ := c.perTag.LoadOrStore(name+"="+value, newEnhancedMetricsBucket(c.buckets))
+	return actual.(*enhancedMetricsBucket)
+}
+
+// recordCache updates a metrics bucket with a response's cache statistics
+func (b *enhancedMetricsBucket) recordCache(info types.CacheInfo) {
+	switch info.Source {
+	case types.CacheSourceLocal:
+		atomic.AddInt64(&b.cacheHits, 1)
+	case types.CacheSourceSemantic:
+		atomic.AddInt64(&b.semanticCacheHits, 1)
+	default:
+		// Hits replay the original usage; only fresh responses count toward
+		// the provider's prompt-cache ratio.
+		atomic.AddInt64(&b.promptTokens, int64(info.PromptTokens))
+		atomic.AddInt64(&b.cachedTokens, int64(info.CachedTokens))
+		atomic.AddInt64(&b.cacheWriteTokens, int64(info.CacheWriteTokens))
+	}
+	atomic.AddInt64(&b.cacheSavedTokens, int64(info.SavedTokens()))
 }
 
 // record updates a metrics bucket with a request
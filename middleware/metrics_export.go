@@ -48,6 +48,21 @@ func (c *EnhancedMetricsCollector) GetAllStats() map[string]interface{} {
 	return result
 }
 
+// GetTagStats returns statistics per value of the named request tag (see
+// WithTags), e.g. GetTagStats("tenant") for per-tenant request counts and
+// token usage.
+func (c *EnhancedMetricsCollector) GetTagStats(name string) map[string]map[string]interface{} {
+	result := make(map[string]map[string]interface{})
+	prefix := name + "="
+	c.perTag.Range(func(key, value interface{}) bool {
+		if tagValue, ok := strings.CutPrefix(key.(string), prefix); ok {
+			result[tagValue] = value.(*enhancedMetricsBucket).getStats(c.buckets)
+		}
+		return true
+	})
+	return result
+}
+
 // getStats returns statistics from a metrics bucket
 func (b *enhancedMetricsBucket) getStats(buckets []float64) map[string]interface{} {
 	requests := atomic.LoadInt64(&b.requests)
@@ -56,6 +71,8 @@ func (b *enhancedMetricsBucket) getStats(buckets []float64) map[string]interface
 	totalDuration := atomic.LoadInt64(&b.totalDuration)
 	inputTokens := atomic.LoadInt64(&b.inputTokens)
 	outputTokens := atomic.LoadInt64(&b.outputTokens)
+	promptTokens := atomic.LoadInt64(&b.promptTokens)
+	cachedTokens := atomic.LoadInt64(&b.cachedTokens)
 
 	avgDuration := time.Duration(0)
 	if requests > 0 {
@@ -68,6 +85,11 @@ func (b *enhancedMetricsBucket) getStats(buckets []float64) map[string]interface
 		histogramCounts[i] = atomic.LoadInt64(&b.histogramCounts[i])
 	}
 
+	cachedTokenRatio := 0.0
+	if promptTokens > 0 {
+		cachedTokenRatio = float64(cachedTokens) / float64(promptTokens)
+	}
+
 	return map[string]interface{}{
 		"requests":          requests,
 		"errors":            errors,
@@ -78,6 +100,13 @@ func (b *enhancedMetricsBucket) getStats(buckets []float64) map[string]interface
 		"output_tokens":     outputTokens,
 		"histogram_buckets": buckets,
 		"histogram_counts":  histogramCounts,
+
+		"cache_hits":          atomic.LoadInt64(&b.cacheHits),
+		"semantic_cache_hits": atomic.LoadInt64(&b.semanticCacheHits),
+		"cached_tokens":       cachedTokens,
+		"cache_write_tokens":  atomic.LoadInt64(&b.cacheWriteTokens),
+		"cache_saved_tokens":  atomic.LoadInt64(&b.cacheSavedTokens),
+		"cached_token_ratio":  cachedTokenRatio,
 	}
 }
 
@@ -124,6 +153,11 @@ func (b *enhancedMetricsBucket) prometheusFormat(labels string, buckets []float6
 	fmt.Fprintf(&builder, "wormhole_duration_total_ns%s %d\n", labelStr, totalDuration)
 	fmt.Fprintf(&builder, "wormhole_input_tokens_total%s %d\n", labelStr, inputTokens)
 	fmt.Fprintf(&builder, "wormhole_output_tokens_total%s %d\n", labelStr, outputTokens)
+	fmt.Fprintf(&builder, "wormhole_cache_hits_total%s %d\n", labelStr, atomic.LoadInt64(&b.cacheHits))
+	fmt.Fprintf(&builder, "wormhole_semantic_cache_hits_total%s %d\n", labelStr, atomic.LoadInt64(&b.semanticCacheHits))
+	fmt.Fprintf(&builder, "wormhole_cached_tokens_total%s %d\n", labelStr, atomic.LoadInt64(&b.cachedTokens))
+	fmt.Fprintf(&builder, "wormhole_cache_write_tokens_total%s %d\n", labelStr, atomic.LoadInt64(&b.cacheWriteTokens))
+	fmt.Fprintf(&builder, "wormhole_cache_saved_tokens_total%s %d\n", labelStr, atomic.LoadInt64(&b.cacheSavedTokens))
 
 	// Write histogram (simplified)
 	for i, count := range b.histogramCounts {
@@ -151,6 +185,7 @@ func (c *EnhancedMetricsCollector) Reset() {
 	if c.config.LabelAggregation {
 		c.perLabel = &sync.Map{}
 	}
+	c.perTag = &sync.Map{}
 }
 
 // Helper function to extract labels from request context
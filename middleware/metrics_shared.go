@@ -41,7 +41,8 @@ func requestLabelsFromContext(ctx context.Context, method, model string) *Reques
 		}
 	}
 
-	if method == "" && model == "" && provider == "unknown" {
+	tags := TagsFromContext(ctx)
+	if method == "" && model == "" && provider == "unknown" && len(tags) == 0 {
 		return nil
 	}
 
@@ -50,5 +51,6 @@ func requestLabelsFromContext(ctx context.Context, method, model string) *Reques
 		Model:     model,
 		Method:    method,
 		ErrorType: "",
+		Tags:      tags,
 	}
 }
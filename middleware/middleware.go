@@ -31,7 +31,8 @@ const (
 )
 
 // Middleware represents a function that wraps provider calls
-// DEPRECATED: Use types.ProviderMiddleware for type-safe middleware instead
+// DEPRECATED: Use types.ProviderMiddleware for type-safe middleware instead;
+// Hooks is the shortest way to write one
 type Middleware func(next Handler) Handler
 
 // Handler represents any provider method signature
@@ -22,12 +22,66 @@ func AvailableMiddleware() []MiddlewareInfo {
 			Example:    "middleware.CacheMiddleware(middleware.CacheConfig{Cache: cache, TTL: 5*time.Minute})",
 			ConfigType: "CacheConfig",
 		},
+		{
+			Name:       "SemanticCacheMiddleware",
+			Purpose:    "Serve cached responses to prompts similar to earlier ones, per model",
+			Example:    "middleware.SemanticCacheMiddleware(middleware.SemanticCacheConfig{Embed: client.Embedder(\"openai\", \"text-embedding-3-small\"), Threshold: 0.92, TTL: time.Hour})",
+			ConfigType: "SemanticCacheConfig",
+		},
+		{
+			Name:       "GuardrailsMiddleware",
+			Purpose:    "Block or redact prompts and responses: blocklists, length limits, PII, custom checks",
+			Example:    "middleware.GuardrailsMiddleware(middleware.GuardrailsConfig{Input: []middleware.Guardrail{middleware.RedactPII()}})",
+			ConfigType: "GuardrailsConfig",
+		},
+		{
+			Name:       "EmptyResponseMiddleware",
+			Purpose:    "Fail or retry 200 responses with empty or whitespace-only text, counted per provider",
+			Example:    "middleware.EmptyResponseMiddleware(middleware.EmptyResponseConfig{Retries: 2, Counter: middleware.NewEmptyResponseCounter()})",
+			ConfigType: "EmptyResponseConfig",
+		},
+		{
+			Name:       "Mirror.Middleware",
+			Purpose:    "Asynchronously ship request/response envelopes to a data pipeline publisher",
+			Example:    "middleware.NewMirror(middleware.MirrorConfig{Publisher: publisher}).Middleware()",
+			ConfigType: "MirrorConfig",
+		},
 		{
 			Name:       "CircuitBreakerMiddleware",
 			Purpose:    "Circuit breaking for failing providers",
 			Example:    "middleware.CircuitBreakerMiddleware(5, 30*time.Second)",
 			ConfigType: "threshold int, timeout time.Duration",
 		},
+		{
+			Name:       "CircuitBreakerMiddlewareWithConfig",
+			Purpose:    "Circuit breaking with exponentially growing open timeouts",
+			Example:    "middleware.CircuitBreakerMiddlewareWithConfig(middleware.CircuitBreakerConfig{FailureThreshold: 5, Timeout: 10*time.Second, MaxTimeout: 5*time.Minute})",
+			ConfigType: "CircuitBreakerConfig",
+		},
+		{
+			Name:       "CircuitBreakers.Middleware",
+			Purpose:    "Per-provider circuit breaking with state callbacks, ProviderState and Stats",
+			Example:    "middleware.NewCircuitBreakers(middleware.CircuitBreakerConfig{FailureThreshold: 5, Timeout: 30*time.Second}).Middleware()",
+			ConfigType: "CircuitBreakerConfig",
+		},
+		{
+			Name:       "Scheduler.Middleware",
+			Purpose:    "Priority queueing under rate and concurrency limits, with queue depth stats",
+			Example:    "middleware.NewScheduler(middleware.SchedulerConfig{RequestsPerSecond: 50, MaxConcurrent: 20, BackgroundConcurrency: 4}).Middleware()",
+			ConfigType: "SchedulerConfig",
+		},
+		{
+			Name:       "ConcurrencyLimitMiddleware",
+			Purpose:    "Bound in-flight calls globally and share slots fairly across tenant keys",
+			Example:    "middleware.ConcurrencyLimitMiddleware(32, middleware.TagKey(\"tenant\"))",
+			ConfigType: "maxInFlight int, perKey func(context.Context) string",
+		},
+		{
+			Name:       "RetryMiddleware",
+			Purpose:    "Retries with exponential backoff and an optional rolling retry budget",
+			Example:    "middleware.RetryMiddleware(middleware.RetryConfig{MaxRetries: 3, Budget: middleware.NewRetryBudget(middleware.DefaultRetryBudgetConfig())})",
+			ConfigType: "RetryConfig",
+		},
 		{
 			Name:       "RateLimitMiddleware",
 			Purpose:    "Request rate limiting",
@@ -52,6 +106,12 @@ func AvailableMiddleware() []MiddlewareInfo {
 			Example:    "middleware.LoggingMiddleware(logger)",
 			ConfigType: "logger types.Logger",
 		},
+		{
+			Name:       "StructuredLoggingMiddleware",
+			Purpose:    "Production slog records with latency, tokens, error codes and redacted content",
+			Example:    "middleware.NewStructuredLoggingMiddleware(middleware.StructuredLoggingConfig{Logger: logger, Redact: []middleware.Guardrail{middleware.RedactPII()}})",
+			ConfigType: "StructuredLoggingConfig",
+		},
 		{
 			Name:       "MetricsMiddleware",
 			Purpose:    "Request metrics collection",
@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// MirrorEnvelope is one request and its outcome, as shipped by a Mirror.
+// Payloads are the JSON forms of the request and response, which already omit
+// credentials, system prompts and provider options. Errors are reduced to
+// their code so upstream response bodies never leave the process.
+type MirrorEnvelope struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Provider  string            `json:"provider,omitempty"`
+	Method    string            `json:"method,omitempty"`
+	Model     string            `json:"model,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Latency   time.Duration     `json:"latency_ns"`
+	Request   json.RawMessage   `json:"request,omitempty"`
+	Response  json.RawMessage   `json:"response,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// MirrorPublisher ships envelopes to an external sink such as Kafka or
+// Pub/Sub. Publish runs on the mirror's background goroutine, never on the
+// request path.
+type MirrorPublisher interface {
+	Publish(ctx context.Context, envelope MirrorEnvelope) error
+}
+
+// MirrorPublisherFunc adapts a function to MirrorPublisher.
+type MirrorPublisherFunc func(ctx context.Context, envelope MirrorEnvelope) error
+
+// Publish calls f.
+func (f MirrorPublisherFunc) Publish(ctx context.Context, envelope MirrorEnvelope) error {
+	return f(ctx, envelope)
+}
+
+// MirrorConfig holds request mirroring configuration
+type MirrorConfig struct {
+	// Publisher receives every envelope. Required.
+	Publisher MirrorPublisher
+	// BufferSize bounds queued envelopes. When the queue is full new envelopes
+	// are dropped rather than slowing requests down. Default 1024.
+	BufferSize int
+	// PublishTimeout bounds each Publish call. Default 5s.
+	PublishTimeout time.Duration
+	// Sanitize may rewrite an envelope before it is queued, for example to
+	// redact payloads. Returning false skips the envelope.
+	Sanitize func(*MirrorEnvelope) bool
+	// OnError is called with Publish failures. Optional.
+	OnError func(error)
+}
+
+// Mirror asynchronously copies request/response envelopes to a publisher so
+// data pipelines can ingest LLM traffic without scraping logs. Requests never
+// wait on the publisher: envelopes go through a bounded queue drained by one
+// background goroutine.
+type Mirror struct {
+	config    MirrorConfig
+	queue     chan MirrorEnvelope
+	done      chan struct{}
+	mu        sync.RWMutex
+	closed    bool
+	published atomic.Int64
+	dropped   atomic.Int64
+}
+
+// NewMirror starts a mirror. Call Close to flush queued envelopes on shutdown.
+//
+// Example usage:
+//
+//	mirror := middleware.NewMirror(middleware.MirrorConfig{
+//	    Publisher: middleware.MirrorPublisherFunc(func(ctx context.Context, e middleware.MirrorEnvelope) error {
+//	        payload, _ := json.Marshal(e)
+//	        return producer.Produce(ctx, "llm-traffic", payload)
+//	    }),
+//	})
+//	defer mirror.Close(context.Background())
+//	client := wormhole.New(wormhole.WithMiddleware(mirror.Middleware()))
+func NewMirror(config MirrorConfig) *Mirror {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1024
+	}
+	if config.PublishTimeout <= 0 {
+		config.PublishTimeout = 5 * time.Second
+	}
+	m := &Mirror{
+		config: config,
+		queue:  make(chan MirrorEnvelope, config.BufferSize),
+		done:   make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
+
+// Middleware returns the middleware that feeds the mirror. Streams are
+// mirrored once they finish, with the accumulated text as the response.
+func (m *Mirror) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			if stream, ok := resp.(<-chan types.StreamChunk); ok && err == nil {
+				return m.mirrorStream(ctx, req, start, stream), nil
+			}
+			m.record(ctx, req, resp, err, start)
+			return resp, wrapIfNotWormholeError("mirror", err)
+		}
+	}
+}
+
+// Published returns how many envelopes the publisher accepted.
+func (m *Mirror) Published() int64 {
+	return m.published.Load()
+}
+
+// Dropped returns how many envelopes were discarded because the queue was
+// full, the mirror was closed, or Publish failed.
+func (m *Mirror) Dropped() int64 {
+	return m.dropped.Load()
+}
+
+// Close stops accepting envelopes and waits until the queued ones are
+// published or ctx is done.
+func (m *Mirror) Close(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+	}
+	m.mu.Unlock()
+
+	select {
+	case <-m.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Mirror) run() {
+	defer close(m.done)
+	for envelope := range m.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), m.config.PublishTimeout)
+		err := m.config.Publisher.Publish(ctx, envelope)
+		cancel()
+		if err != nil {
+			m.dropped.Add(1)
+			if m.config.OnError != nil {
+				m.config.OnError(err)
+			}
+			continue
+		}
+		m.published.Add(1)
+	}
+}
+
+func (m *Mirror) record(ctx context.Context, req, resp any, err error, start time.Time) {
+	if m.config.Publisher == nil {
+		return
+	}
+	envelope := MirrorEnvelope{
+		Timestamp: start,
+		Model:     mirrorModel(req),
+		Latency:   time.Since(start),
+	}
+	if labels := requestLabelsFromContext(ctx, "", ""); labels != nil {
+		envelope.Provider, envelope.Method, envelope.Tags = labels.Provider, labels.Method, labels.Tags
+	}
+	if data, marshalErr := json.Marshal(req); marshalErr == nil {
+		envelope.Request = data
+	}
+	if err != nil {
+		envelope.Error = mirrorErrorCode(err)
+	} else if data, marshalErr := json.Marshal(resp); marshalErr == nil {
+		envelope.Response = data
+	}
+	if m.config.Sanitize != nil && !m.config.Sanitize(&envelope) {
+		return
+	}
+	m.enqueue(envelope)
+}
+
+func (m *Mirror) enqueue(envelope MirrorEnvelope) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		m.dropped.Add(1)
+		return
+	}
+	select {
+	case m.queue <- envelope:
+	default:
+		m.dropped.Add(1)
+	}
+}
+
+// mirrorStream forwards chunks unchanged and records the stream once it ends.
+func (m *Mirror) mirrorStream(ctx context.Context, req any, start time.Time, stream <-chan types.StreamChunk) <-chan types.StreamChunk {
+	wrapped := make(chan types.StreamChunk, 1)
+	go func() {
+		defer close(wrapped)
+		var text strings.Builder
+		var final types.TextResponse
+		var streamErr error
+		defer func() {
+			final.Text = text.String()
+			m.record(ctx, req, &final, streamErr, start)
+		}()
+		for {
+			select {
+			case chunk, ok := <-stream:
+				if !ok {
+					return
+				}
+				text.WriteString(chunk.Content())
+				if chunk.Error != nil {
+					streamErr = chunk.Error
+				}
+				if chunk.Model != "" {
+					final.Model = chunk.Model
+				}
+				if chunk.FinishReason != nil {
+					final.FinishReason = *chunk.FinishReason
+					final.RawFinishReason = chunk.RawFinishReason
+				}
+				if chunk.Usage != nil {
+					final.Usage = chunk.Usage
+				}
+				select {
+				case wrapped <- chunk:
+				case <-ctx.Done():
+					streamErr = ctx.Err()
+					drainStreamUntilIdle(stream)
+					return
+				}
+			case <-ctx.Done():
+				streamErr = ctx.Err()
+				drainStreamUntilIdle(stream)
+				return
+			}
+		}
+	}()
+	return wrapped
+}
+
+func mirrorModel(req any) string {
+	switch r := req.(type) {
+	case *types.TextRequest:
+		return r.Model
+	case *types.StructuredRequest:
+		return r.Model
+	case *types.EmbeddingsRequest:
+		return r.Model
+	case *types.AudioRequest:
+		return r.Model
+	case *types.ImagesRequest:
+		return r.Model
+	case *types.RerankRequest:
+		return r.Model
+	}
+	return ""
+}
+
+func mirrorErrorCode(err error) string {
+	if wormholeErr, ok := types.AsWormholeError(err); ok {
+		return string(wormholeErr.Code)
+	}
+	return string(types.ErrorCodeUnknown)
+}
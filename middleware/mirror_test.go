@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+type recordingPublisher struct {
+	mu        sync.Mutex
+	envelopes []MirrorEnvelope
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, envelope MirrorEnvelope) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.envelopes = append(p.envelopes, envelope)
+	return nil
+}
+
+func (p *recordingPublisher) all() []MirrorEnvelope {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]MirrorEnvelope(nil), p.envelopes...)
+}
+
+func TestMirrorPublishesEnvelopes(t *testing.T) {
+	t.Parallel()
+
+	publisher := &recordingPublisher{}
+	mirror := NewMirror(MirrorConfig{Publisher: publisher})
+	handler := mirror.Middleware()(func(_ context.Context, req any) (any, error) {
+		if req.(*types.TextRequest).Model == "broken" {
+			return nil, types.ErrProviderUnavailable.WithDetails("upstream body with secrets")
+		}
+		return &types.TextResponse{Model: "gpt-5", Text: "hi there"}, nil
+	})
+
+	ctx := context.WithValue(context.Background(), CtxKeyProvider, "openai")
+	ctx = context.WithValue(ctx, CtxKeyMethod, "text")
+	ctx = WithTags(ctx, map[string]string{"tenant": "acme"})
+	resp, err := handler(ctx, textRequest("gpt-5", "hello"))
+	require.NoError(t, err)
+	assert.Equal(t, "hi there", resp.(*types.TextResponse).Text)
+	_, err = handler(ctx, textRequest("broken", "hello"))
+	require.Error(t, err)
+
+	require.NoError(t, mirror.Close(context.Background()))
+	envelopes := publisher.all()
+	require.Len(t, envelopes, 2)
+	assert.Equal(t, int64(2), mirror.Published())
+
+	ok := envelopes[0]
+	assert.Equal(t, "openai", ok.Provider)
+	assert.Equal(t, "text", ok.Method)
+	assert.Equal(t, "gpt-5", ok.Model)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, ok.Tags)
+	assert.Contains(t, string(ok.Request), `"hello"`)
+	assert.Contains(t, string(ok.Response), `"hi there"`)
+	assert.Empty(t, ok.Error)
+
+	failed := envelopes[1]
+	assert.Equal(t, string(types.ErrorCodeProvider), failed.Error)
+	assert.Empty(t, failed.Response)
+	data, err := json.Marshal(failed)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secrets", "error details never leave the process")
+}
+
+func TestMirrorDoesNotBlockRequests(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	mirror := NewMirror(MirrorConfig{
+		BufferSize: 1,
+		Publisher: MirrorPublisherFunc(func(context.Context, MirrorEnvelope) error {
+			<-release
+			return nil
+		}),
+	})
+	handler := mirror.Middleware()(func(context.Context, any) (any, error) {
+		return &types.TextResponse{Text: "ok"}, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 5 {
+			_, _ = handler(context.Background(), textRequest("gpt-5", "hi"))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("requests waited on a stalled publisher")
+	}
+	assert.Positive(t, mirror.Dropped())
+
+	close(release)
+	require.NoError(t, mirror.Close(context.Background()))
+	assert.Equal(t, int64(5), mirror.Published()+mirror.Dropped())
+}
+
+func TestMirrorSanitizeAndPublishErrors(t *testing.T) {
+	t.Parallel()
+
+	publisher := &recordingPublisher{}
+	var publishErrs []error
+	failing := MirrorPublisherFunc(func(ctx context.Context, e MirrorEnvelope) error {
+		if e.Model == "fail" {
+			return errors.New("broker down")
+		}
+		return publisher.Publish(ctx, e)
+	})
+	mirror := NewMirror(MirrorConfig{
+		Publisher: failing,
+		Sanitize: func(e *MirrorEnvelope) bool {
+			if e.Model == "skip" {
+				return false
+			}
+			e.Request = nil
+			return true
+		},
+		OnError: func(err error) { publishErrs = append(publishErrs, err) },
+	})
+	handler := mirror.Middleware()(func(context.Context, any) (any, error) {
+		return &types.TextResponse{Text: "ok"}, nil
+	})
+	for _, model := range []string{"keep", "skip", "fail"} {
+		_, err := handler(context.Background(), textRequest(model, "hi"))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, mirror.Close(context.Background()))
+	envelopes := publisher.all()
+	require.Len(t, envelopes, 1)
+	assert.Equal(t, "keep", envelopes[0].Model)
+	assert.Nil(t, envelopes[0].Request)
+	assert.Len(t, publishErrs, 1)
+	assert.Equal(t, int64(1), mirror.Dropped())
+}
+
+func TestMirrorRecordsStreamsWhenTheyFinish(t *testing.T) {
+	t.Parallel()
+
+	publisher := &recordingPublisher{}
+	mirror := NewMirror(MirrorConfig{Publisher: publisher})
+	handler := mirror.Middleware()(func(context.Context, any) (any, error) {
+		stream := make(chan types.StreamChunk, 2)
+		stream <- types.StreamChunk{Text: "Hel"}
+		stream <- types.StreamChunk{Text: "lo", Model: "gpt-5"}
+		close(stream)
+		return (<-chan types.StreamChunk)(stream), nil
+	})
+
+	resp, err := handler(context.Background(), textRequest("gpt-5", "hi"))
+	require.NoError(t, err)
+	var got string
+	for chunk := range resp.(<-chan types.StreamChunk) {
+		got += chunk.Text
+	}
+	assert.Equal(t, "Hello", got)
+
+	require.NoError(t, mirror.Close(context.Background()))
+	envelopes := publisher.all()
+	require.Len(t, envelopes, 1)
+	assert.Contains(t, string(envelopes[0].Response), `"text":"Hello"`)
+}
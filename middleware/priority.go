@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+)
+
+// Priority ranks a request for load shedding. Higher values are more
+// important; the zero value is PriorityNormal.
+type Priority int
+
+const (
+	// PriorityLow marks background traffic (batch jobs, prefetching) that
+	// may be shed or deferred while a provider is degraded.
+	PriorityLow Priority = -1
+	// PriorityNormal is the default priority of requests that carry none.
+	PriorityNormal Priority = 0
+	// PriorityHigh marks interactive traffic that should keep flowing.
+	PriorityHigh Priority = 1
+)
+
+// CtxKeyPriority carries the request Priority used by adaptive load shedding.
+const CtxKeyPriority contextKey = "priority"
+
+// String returns "low", "normal", "high", or the numeric value.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	default:
+		return strconv.Itoa(int(p))
+	}
+}
+
+// WithPriority returns a context that tags requests made with it as p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, CtxKeyPriority, p)
+}
+
+// PriorityFromContext returns the Priority stored by WithPriority, or
+// PriorityNormal when none is set.
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(CtxKeyPriority).(Priority); ok {
+		return p
+	}
+	return PriorityNormal
+}
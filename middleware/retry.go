@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"math/rand"
 	"time"
@@ -23,6 +24,11 @@ type RetryConfig struct {
 	BackoffMultiple float64          // Multiplier for exponential backoff
 	Jitter          bool             // Add random jitter to prevent thundering herd
 	RetryableFunc   func(error) bool // Custom function to determine if error is retryable; nil falls back to DefaultRetryableFunc
+	// Budget, when set, caps retries to a share of recent requests. A refused
+	// retry fails fast with ErrRetryBudgetExceeded wrapping the last error;
+	// place CircuitBreakerMiddleware outside this middleware so the refusal
+	// opens the circuit.
+	Budget *RetryBudget
 }
 
 // DefaultRetryConfig returns sensible defaults for retry configuration
@@ -60,6 +66,9 @@ func RetryMiddleware(config RetryConfig) Middleware {
 	return func(handler Handler) Handler {
 		return func(ctx context.Context, req any) (any, error) {
 			var lastErr error
+			if config.Budget != nil {
+				config.Budget.RecordRequest()
+			}
 
 			for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 				result, err := handler(ctx, req)
@@ -78,6 +87,9 @@ func RetryMiddleware(config RetryConfig) Middleware {
 				if attempt == config.MaxRetries {
 					break
 				}
+				if config.Budget != nil && !config.Budget.TryRetry() {
+					return nil, fmt.Errorf("%w: %w", ErrRetryBudgetExceeded, err)
+				}
 
 				// Calculate delay with exponential backoff, honoring a
 				// provider-supplied Retry-After when present since it is
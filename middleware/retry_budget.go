@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ErrRetryBudgetExceeded is returned when a retry is refused because retries
+// already make up the configured share of recent traffic.
+var ErrRetryBudgetExceeded = types.ErrRetryBudgetExceeded
+
+const retryBudgetBuckets = 10
+
+// RetryBudgetConfig bounds how much extra load retries may add.
+type RetryBudgetConfig struct {
+	Ratio      float64       // Maximum retries as a fraction of requests in the window (e.g. 0.2 = 20%)
+	MinRetries int           // Retries always allowed per window so low-traffic clients can still retry
+	Window     time.Duration // Rolling window over which requests and retries are counted
+}
+
+// DefaultRetryBudgetConfig returns a budget allowing retries up to 20% of
+// requests over a 10 second window, with a floor of 10 retries.
+func DefaultRetryBudgetConfig() RetryBudgetConfig {
+	return RetryBudgetConfig{
+		Ratio:      0.2,
+		MinRetries: 10,
+		Window:     10 * time.Second,
+	}
+}
+
+// RetryBudgetStats reports the counts inside the current rolling window.
+type RetryBudgetStats struct {
+	Requests int
+	Retries  int
+}
+
+type retryBudgetBucket struct {
+	start    time.Time
+	requests int
+	retries  int
+}
+
+// RetryBudget tracks requests and retries over a rolling window and refuses
+// retries once they exceed the configured share, so a provider outage cannot
+// turn every caller into a retry storm. A single budget may be shared by
+// several RetryMiddleware instances to cap retries across routes.
+type RetryBudget struct {
+	mu          sync.Mutex
+	config      RetryBudgetConfig
+	bucketWidth time.Duration
+	buckets     [retryBudgetBuckets]retryBudgetBucket
+	now         func() time.Time
+}
+
+// NewRetryBudget creates a retry budget. Zero config fields fall back to
+// DefaultRetryBudgetConfig values; a negative Ratio or MinRetries is treated
+// as zero.
+func NewRetryBudget(config RetryBudgetConfig) *RetryBudget {
+	defaults := DefaultRetryBudgetConfig()
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.Ratio == 0 && config.MinRetries == 0 {
+		config.Ratio = defaults.Ratio
+		config.MinRetries = defaults.MinRetries
+	}
+	if config.Ratio < 0 {
+		config.Ratio = 0
+	}
+	if config.MinRetries < 0 {
+		config.MinRetries = 0
+	}
+	bucketWidth := config.Window / retryBudgetBuckets
+	if bucketWidth <= 0 {
+		bucketWidth = config.Window
+	}
+	return &RetryBudget{
+		config:      config,
+		bucketWidth: bucketWidth,
+		now:         time.Now,
+	}
+}
+
+// RecordRequest counts one original (non-retry) request toward the budget.
+func (b *RetryBudget) RecordRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.currentBucket().requests++
+}
+
+// TryRetry reports whether another retry fits in the budget and, if so,
+// counts it.
+func (b *RetryBudget) TryRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := b.statsLocked()
+	allowed := int(float64(stats.Requests) * b.config.Ratio)
+	if allowed < b.config.MinRetries {
+		allowed = b.config.MinRetries
+	}
+	if stats.Retries >= allowed {
+		return false
+	}
+	b.currentBucket().retries++
+	return true
+}
+
+// Stats returns request and retry counts within the rolling window.
+func (b *RetryBudget) Stats() RetryBudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.statsLocked()
+}
+
+func (b *RetryBudget) currentBucket() *retryBudgetBucket {
+	now := b.now()
+	start := now.Truncate(b.bucketWidth)
+	bucket := &b.buckets[(start.UnixNano()/int64(b.bucketWidth))%retryBudgetBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = retryBudgetBucket{start: start}
+	}
+	return bucket
+}
+
+func (b *RetryBudget) statsLocked() RetryBudgetStats {
+	cutoff := b.now().Add(-b.config.Window)
+	var stats RetryBudgetStats
+	for _, bucket := range b.buckets {
+		if bucket.start.After(cutoff) {
+			stats.Requests += bucket.requests
+			stats.Retries += bucket.retries
+		}
+	}
+	return stats
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestRetryBudgetCapsRetriesToRatioOfRequests(t *testing.T) {
+	t.Parallel()
+	budget := NewRetryBudget(RetryBudgetConfig{Ratio: 0.5, MinRetries: 1, Window: time.Minute})
+
+	for i := 0; i < 4; i++ {
+		budget.RecordRequest()
+	}
+	assert.True(t, budget.TryRetry())
+	assert.True(t, budget.TryRetry())
+	assert.False(t, budget.TryRetry(), "third retry exceeds 50% of four requests")
+	assert.Equal(t, RetryBudgetStats{Requests: 4, Retries: 2}, budget.Stats())
+}
+
+func TestRetryBudgetWindowExpires(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(1_700_000_000, 0)
+	budget := NewRetryBudget(RetryBudgetConfig{Ratio: 0, MinRetries: 1, Window: 10 * time.Second})
+	budget.now = func() time.Time { return now }
+
+	require.True(t, budget.TryRetry())
+	require.False(t, budget.TryRetry())
+
+	now = now.Add(11 * time.Second)
+	assert.True(t, budget.TryRetry(), "retries outside the window no longer count")
+}
+
+func TestRetryMiddlewareFailsFastWhenBudgetExhausted(t *testing.T) {
+	t.Parallel()
+	budget := NewRetryBudget(RetryBudgetConfig{Ratio: 0, MinRetries: 1, Window: time.Minute})
+	upstream := errors.New("provider unavailable")
+	calls := 0
+	handler := RetryMiddleware(RetryConfig{
+		MaxRetries:   5,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		Budget:       budget,
+	})(func(context.Context, any) (any, error) {
+		calls++
+		return nil, upstream
+	})
+
+	_, err := handler(context.Background(), nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrRetryBudgetExceeded)
+	assert.ErrorIs(t, err, upstream)
+	assert.Equal(t, 2, calls, "one original attempt plus the single budgeted retry")
+	assert.False(t, types.IsRetryableError(err))
+}
+
+func TestRetryBudgetExhaustionOpensCircuit(t *testing.T) {
+	t.Parallel()
+	budget := NewRetryBudget(RetryBudgetConfig{Ratio: 0, MinRetries: 1, Window: time.Minute})
+	chain := NewChain(
+		CircuitBreakerMiddleware(5, time.Hour),
+		RetryMiddleware(RetryConfig{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Budget: budget}),
+	)
+	handler := chain.Apply(func(context.Context, any) (any, error) {
+		return nil, errors.New("provider unavailable")
+	})
+
+	ctx := circuitContext("openai", "text")
+	_, err := handler(ctx, nil)
+	require.ErrorIs(t, err, ErrRetryBudgetExceeded)
+	_, err = handler(ctx, nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestCircuitBreakerOpenTimeoutGrowsExponentially(t *testing.T) {
+	t.Parallel()
+	cb := NewCircuitBreakerWithConfig(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		Timeout:          time.Millisecond,
+		MaxTimeout:       4 * time.Millisecond,
+	})
+	fail := func() (any, error) { return nil, errors.New("down") }
+
+	_, _ = cb.Execute(context.Background(), fail)
+	require.Equal(t, StateOpen, cb.GetState())
+	assert.Equal(t, time.Millisecond, cb.OpenTimeout())
+
+	for _, want := range []time.Duration{2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond} {
+		time.Sleep(cb.OpenTimeout() + time.Millisecond)
+		_, _ = cb.Execute(context.Background(), fail)
+		require.Equal(t, StateOpen, cb.GetState())
+		assert.Equal(t, want, cb.OpenTimeout())
+	}
+
+	time.Sleep(cb.OpenTimeout() + time.Millisecond)
+	_, err := cb.Execute(context.Background(), func() (any, error) { return "ok", nil })
+	require.NoError(t, err)
+	assert.Equal(t, StateClosed, cb.GetState())
+	assert.Equal(t, time.Millisecond, cb.OpenTimeout())
+}
@@ -0,0 +1,338 @@
+package middleware
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+var (
+	// ErrSchedulerQueueFull is returned when a request arrives at a full
+	// scheduler queue, or is pushed out of it by a higher-priority request.
+	ErrSchedulerQueueFull = types.NewWormholeError(types.ErrorCodeRateLimit, "scheduler queue is full", true)
+	// ErrSchedulerClosed is returned to requests waiting in a closed scheduler.
+	ErrSchedulerClosed = types.NewWormholeError(types.ErrorCodeMiddleware, "scheduler is closed", false)
+)
+
+// SchedulerConfig configures a Scheduler. Zero fields disable the limit they
+// control.
+type SchedulerConfig struct {
+	// RequestsPerSecond shapes how fast requests are released to providers.
+	RequestsPerSecond float64
+	// Burst is how many requests may be released at once after a quiet
+	// period. Defaults to RequestsPerSecond rounded up, at least 1.
+	Burst int
+	// MaxConcurrent caps requests in flight at once.
+	MaxConcurrent int
+	// BackgroundConcurrency caps in-flight requests below PriorityNormal,
+	// keeping batch traffic from filling every slot.
+	BackgroundConcurrency int
+	// MaxQueue caps waiting requests. When full, a newcomer pushes out the
+	// newest lower-priority waiter, or is rejected if there is none.
+	MaxQueue int
+}
+
+// SchedulerStats is a point-in-time view of a Scheduler.
+type SchedulerStats struct {
+	QueueDepth    int                        // Requests waiting now
+	MaxQueueDepth int                        // Highest QueueDepth seen
+	InFlight      int                        // Requests holding a slot now
+	Queued        map[Priority]int           // Waiting requests by priority
+	Admitted      map[Priority]int64         // Requests released to the provider
+	Rejected      map[Priority]int64         // Requests refused or pushed out by MaxQueue
+	Waited        map[Priority]time.Duration // Total time admitted requests spent queued
+}
+
+// Scheduler releases requests in priority order. While its rate or
+// concurrency limits leave room, requests pass straight through; once they
+// bind, requests queue and each free slot goes to the highest-priority waiter,
+// first come first served within a priority. Priority comes from the request
+// context (see WithPriority or the builders' Priority method).
+//
+// Streams hold their slot only until the stream opens.
+//
+// Example:
+//
+//	scheduler := middleware.NewScheduler(middleware.SchedulerConfig{
+//	    RequestsPerSecond:     50,
+//	    MaxConcurrent:         20,
+//	    BackgroundConcurrency: 4,
+//	})
+//	client := wormhole.New(wormhole.WithMiddleware(scheduler.Middleware()))
+//	client.Text().Model("gpt-5-mini").Prompt(q).Priority(wormhole.High).Generate(ctx)
+type Scheduler struct {
+	mu         sync.Mutex
+	config     SchedulerConfig
+	waiting    waiterHeap
+	seq        uint64
+	inFlight   int
+	background int
+	tokens     float64
+	lastRefill time.Time
+	wake       *time.Timer
+	closed     bool
+
+	maxDepth int
+	admitted map[Priority]int64
+	rejected map[Priority]int64
+	waited   map[Priority]time.Duration
+}
+
+// NewScheduler returns a scheduler with a full token bucket.
+func NewScheduler(config SchedulerConfig) *Scheduler {
+	if config.RequestsPerSecond > 0 && config.Burst <= 0 {
+		config.Burst = int(math.Ceil(config.RequestsPerSecond))
+	}
+	return &Scheduler{
+		config:     config,
+		tokens:     float64(config.Burst),
+		lastRefill: time.Now(),
+		admitted:   make(map[Priority]int64),
+		rejected:   make(map[Priority]int64),
+		waited:     make(map[Priority]time.Duration),
+	}
+}
+
+type schedulerWaiter struct {
+	priority Priority
+	seq      uint64
+	queued   time.Time
+	index    int
+	ready    chan struct{}
+	err      error // set before ready is closed when the waiter is refused
+}
+
+// waiterHeap orders waiters by priority, then arrival.
+type waiterHeap []*schedulerWaiter
+
+func (h waiterHeap) Len() int { return len(h) }
+func (h waiterHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+func (h waiterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *waiterHeap) Push(x any) {
+	w := x.(*schedulerWaiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+func (h *waiterHeap) Pop() any {
+	old := *h
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	w.index = -1
+	return w
+}
+
+// Acquire waits for a slot for a request of priority p and returns the
+// function that frees it. It fails when ctx ends first, the queue has no
+// room, or the scheduler is closed.
+func (s *Scheduler) Acquire(ctx context.Context, p Priority) (func(), error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrSchedulerClosed
+	}
+	if s.config.MaxQueue > 0 && len(s.waiting) >= s.config.MaxQueue && !s.evictBelowLocked(p) {
+		s.rejected[p]++
+		s.mu.Unlock()
+		return nil, ErrSchedulerQueueFull
+	}
+	s.seq++
+	w := &schedulerWaiter{priority: p, seq: s.seq, queued: time.Now(), ready: make(chan struct{})}
+	heap.Push(&s.waiting, w)
+	if len(s.waiting) > s.maxDepth {
+		s.maxDepth = len(s.waiting)
+	}
+	s.dispatchLocked()
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		s.mu.Lock()
+		if w.index >= 0 {
+			heap.Remove(&s.waiting, w.index)
+			s.dispatchLocked()
+			s.mu.Unlock()
+			return nil, ctx.Err()
+		}
+		s.mu.Unlock()
+		// Admitted or refused while ctx was ending.
+		if w.err == nil {
+			s.release(p)
+		}
+		return nil, ctx.Err()
+	}
+	if w.err != nil {
+		return nil, w.err
+	}
+	var once sync.Once
+	return func() { once.Do(func() { s.release(p) }) }, nil
+}
+
+// evictBelowLocked refuses the newest waiter with priority below p to make
+// room, reporting whether it found one.
+func (s *Scheduler) evictBelowLocked(p Priority) bool {
+	var victim *schedulerWaiter
+	for _, w := range s.waiting {
+		if w.priority >= p {
+			continue
+		}
+		if victim == nil || w.priority < victim.priority || (w.priority == victim.priority && w.seq > victim.seq) {
+			victim = w
+		}
+	}
+	if victim == nil {
+		return false
+	}
+	heap.Remove(&s.waiting, victim.index)
+	s.rejected[victim.priority]++
+	victim.err = ErrSchedulerQueueFull
+	close(victim.ready)
+	return true
+}
+
+// dispatchLocked admits waiters from the top of the queue while limits allow.
+func (s *Scheduler) dispatchLocked() {
+	for len(s.waiting) > 0 {
+		w := s.waiting[0]
+		if s.config.MaxConcurrent > 0 && s.inFlight >= s.config.MaxConcurrent {
+			return
+		}
+		if w.priority < PriorityNormal && s.config.BackgroundConcurrency > 0 && s.background >= s.config.BackgroundConcurrency {
+			return
+		}
+		if s.config.RequestsPerSecond > 0 {
+			s.refillLocked()
+			if s.tokens < 1 {
+				s.scheduleWakeLocked(time.Duration((1 - s.tokens) / s.config.RequestsPerSecond * float64(time.Second)))
+				return
+			}
+			s.tokens--
+		}
+		heap.Pop(&s.waiting)
+		s.inFlight++
+		if w.priority < PriorityNormal {
+			s.background++
+		}
+		s.admitted[w.priority]++
+		s.waited[w.priority] += time.Since(w.queued)
+		close(w.ready)
+	}
+}
+
+func (s *Scheduler) refillLocked() {
+	now := time.Now()
+	s.tokens += now.Sub(s.lastRefill).Seconds() * s.config.RequestsPerSecond
+	if burst := float64(s.config.Burst); s.tokens > burst {
+		s.tokens = burst
+	}
+	s.lastRefill = now
+}
+
+// scheduleWakeLocked re-runs dispatch once the next token is due.
+func (s *Scheduler) scheduleWakeLocked(after time.Duration) {
+	if s.wake != nil {
+		return
+	}
+	s.wake = time.AfterFunc(after, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.wake = nil
+		if !s.closed {
+			s.dispatchLocked()
+		}
+	})
+}
+
+func (s *Scheduler) release(p Priority) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight--
+	if p < PriorityNormal {
+		s.background--
+	}
+	s.dispatchLocked()
+}
+
+// QueueDepth returns the number of requests waiting for a slot.
+func (s *Scheduler) QueueDepth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.waiting)
+}
+
+// Stats returns queue depth and per-priority counters.
+func (s *Scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := SchedulerStats{
+		QueueDepth:    len(s.waiting),
+		MaxQueueDepth: s.maxDepth,
+		InFlight:      s.inFlight,
+		Queued:        make(map[Priority]int),
+		Admitted:      make(map[Priority]int64, len(s.admitted)),
+		Rejected:      make(map[Priority]int64, len(s.rejected)),
+		Waited:        make(map[Priority]time.Duration, len(s.waited)),
+	}
+	for _, w := range s.waiting {
+		stats.Queued[w.priority]++
+	}
+	for p, n := range s.admitted {
+		stats.Admitted[p] = n
+	}
+	for p, n := range s.rejected {
+		stats.Rejected[p] = n
+	}
+	for p, d := range s.waited {
+		stats.Waited[p] = d
+	}
+	return stats
+}
+
+// Middleware returns middleware that holds a scheduler slot for each call.
+func (s *Scheduler) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			release, err := s.Acquire(ctx, PriorityFromContext(ctx))
+			if err != nil {
+				return nil, wrapIfNotWormholeError("scheduler", err)
+			}
+			defer release()
+			return next(ctx, req)
+		}
+	}
+}
+
+// Close refuses every waiting request with ErrSchedulerClosed and any later
+// Acquire. Requests already in flight finish normally.
+func (s *Scheduler) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.wake != nil {
+		s.wake.Stop()
+		s.wake = nil
+	}
+	for len(s.waiting) > 0 {
+		w := heap.Pop(&s.waiting).(*schedulerWaiter)
+		w.err = ErrSchedulerClosed
+		close(w.ready)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// acquireAsync starts an Acquire and returns a channel that yields its
+// release function once admitted.
+func acquireAsync(t *testing.T, s *Scheduler, p Priority) <-chan func() {
+	t.Helper()
+	admitted := make(chan func(), 1)
+	depth := s.QueueDepth()
+	go func() {
+		release, err := s.Acquire(context.Background(), p)
+		if err != nil {
+			t.Errorf("Acquire(%s) error = %v", p, err)
+			return
+		}
+		admitted <- release
+	}()
+	require.Eventually(t, func() bool { return s.QueueDepth() > depth }, time.Second, time.Millisecond)
+	return admitted
+}
+
+func TestSchedulerAdmitsHighPriorityFirst(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(SchedulerConfig{MaxConcurrent: 1})
+	hold, err := s.Acquire(context.Background(), PriorityNormal)
+	require.NoError(t, err)
+
+	low := acquireAsync(t, s, PriorityLow)
+	normal := acquireAsync(t, s, PriorityNormal)
+	high := acquireAsync(t, s, PriorityHigh)
+	stats := s.Stats()
+	assert.Equal(t, 3, stats.QueueDepth)
+	assert.Equal(t, map[Priority]int{PriorityLow: 1, PriorityNormal: 1, PriorityHigh: 1}, stats.Queued)
+
+	hold()
+	for _, next := range []<-chan func(){high, normal, low} {
+		select {
+		case release := <-next:
+			release()
+		case <-time.After(time.Second):
+			t.Fatal("waiter was not admitted in priority order")
+		}
+	}
+	stats = s.Stats()
+	assert.Equal(t, 0, stats.InFlight)
+	assert.Equal(t, 3, stats.MaxQueueDepth)
+	assert.Equal(t, int64(1), stats.Admitted[PriorityHigh])
+}
+
+func TestSchedulerShapesBackgroundTraffic(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(SchedulerConfig{MaxConcurrent: 3, BackgroundConcurrency: 1})
+	batch, err := s.Acquire(context.Background(), PriorityLow)
+	require.NoError(t, err)
+
+	queued := acquireAsync(t, s, PriorityLow)
+	release, err := s.Acquire(context.Background(), PriorityNormal)
+	require.NoError(t, err, "normal traffic should pass while batch traffic is capped")
+	release()
+
+	batch()
+	select {
+	case release := <-queued:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("queued batch request was not admitted after a batch slot freed")
+	}
+}
+
+func TestSchedulerFullQueuePushesOutLowerPriority(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(SchedulerConfig{MaxConcurrent: 1, MaxQueue: 1})
+	hold, err := s.Acquire(context.Background(), PriorityNormal)
+	require.NoError(t, err)
+
+	lowErr := make(chan error, 1)
+	go func() {
+		_, err := s.Acquire(context.Background(), PriorityLow)
+		lowErr <- err
+	}()
+	require.Eventually(t, func() bool { return s.QueueDepth() == 1 }, time.Second, time.Millisecond)
+
+	highErr := make(chan error, 1)
+	go func() {
+		release, err := s.Acquire(context.Background(), PriorityHigh)
+		if err == nil {
+			release()
+		}
+		highErr <- err
+	}()
+	assert.ErrorIs(t, <-lowErr, ErrSchedulerQueueFull)
+	require.Eventually(t, func() bool { return s.Stats().Queued[PriorityHigh] == 1 }, time.Second, time.Millisecond)
+
+	_, err = s.Acquire(context.Background(), PriorityNormal)
+	assert.ErrorIs(t, err, ErrSchedulerQueueFull, "a lower-priority newcomer cannot push out a waiter")
+	assert.Equal(t, map[Priority]int64{PriorityLow: 1, PriorityNormal: 1}, s.Stats().Rejected)
+
+	hold()
+	assert.NoError(t, <-highErr)
+}
+
+func TestSchedulerCancelLeavesQueue(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(SchedulerConfig{MaxConcurrent: 1})
+	hold, err := s.Acquire(context.Background(), PriorityNormal)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = s.Acquire(ctx, PriorityHigh)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "err = %v", err)
+	assert.Equal(t, 0, s.QueueDepth())
+
+	hold()
+	release, err := s.Acquire(context.Background(), PriorityNormal)
+	require.NoError(t, err)
+	release()
+	assert.Equal(t, 0, s.Stats().InFlight)
+}
+
+func TestSchedulerRateLimitsReleases(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(SchedulerConfig{RequestsPerSecond: 50, Burst: 1})
+	handler := s.Middleware()(func(context.Context, any) (any, error) { return "ok", nil })
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := handler(context.Background(), nil)
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+}
+
+func TestSchedulerCloseRefusesWaiters(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(SchedulerConfig{MaxConcurrent: 1})
+	_, err := s.Acquire(context.Background(), PriorityNormal)
+	require.NoError(t, err)
+
+	waitErr := make(chan error, 1)
+	go func() {
+		_, err := s.Acquire(context.Background(), PriorityHigh)
+		waitErr <- err
+	}()
+	require.Eventually(t, func() bool { return s.QueueDepth() == 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, s.Close())
+	assert.ErrorIs(t, <-waitErr, ErrSchedulerClosed)
+	_, err = s.Acquire(context.Background(), PriorityHigh)
+	assert.ErrorIs(t, err, ErrSchedulerClosed)
+}
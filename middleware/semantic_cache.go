@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// SemanticCacheScoreKey is the response Metadata key holding the similarity
+// score of a semantic cache hit. See types.CacheInfo.
+const SemanticCacheScoreKey = types.SemanticCacheScoreKey
+
+// Embedder returns the embedding vector of text.
+type Embedder func(ctx context.Context, text string) ([]float64, error)
+
+// VectorIndex stores embedded prompts and their responses. Namespaces keep
+// entries for different providers, models, and request shapes apart.
+type VectorIndex interface {
+	// Nearest returns the live entry in namespace most similar to vector.
+	Nearest(namespace string, vector []float64) (value any, score float64, found bool)
+	// Add stores value under vector. A zero ttl means the entry never expires.
+	Add(namespace string, vector []float64, value any, ttl time.Duration)
+}
+
+// SemanticCacheConfig holds semantic cache middleware configuration
+type SemanticCacheConfig struct {
+	// Embed embeds the final user prompt. Required.
+	Embed Embedder
+	// Threshold is the minimum cosine similarity for a hit. Default 0.95.
+	Threshold float64
+	// TTL bounds how long a response is served. Zero means no expiry.
+	TTL time.Duration
+	// Index stores the vectors. Default NewMemoryVectorIndex(1000).
+	Index VectorIndex
+}
+
+// SemanticCacheMiddleware returns cached text and structured responses for
+// prompts that mean the same thing, not just prompts that are byte-identical.
+// Only the final user message is compared semantically; the model, system
+// prompt, earlier messages, tools, schema and parameters must match exactly,
+// so a hit never crosses models or conversations. Streams, multimodal prompts,
+// requests whose prompt fails to embed and requests with a bypassing
+// CacheControl always run fresh.
+//
+// Example usage:
+//
+//	middleware.SemanticCacheMiddleware(middleware.SemanticCacheConfig{
+//	    Embed:     embedder,
+//	    Threshold: 0.92,
+//	    TTL:       time.Hour,
+//	})
+func SemanticCacheMiddleware(config SemanticCacheConfig) Middleware {
+	if config.Threshold <= 0 {
+		config.Threshold = 0.95
+	}
+	if config.Index == nil {
+		config.Index = NewMemoryVectorIndex(1000)
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if config.Embed == nil || ctx.Value(CtxKeyMethod) == "stream" || cacheBypassed(ctx) {
+				resp, err := next(ctx, req)
+				return resp, wrapIfNotWormholeError("semantic_cache", err)
+			}
+			namespace, prompt, ok := semanticCacheKey(ctx, req)
+			if !ok {
+				resp, err := next(ctx, req)
+				return resp, wrapIfNotWormholeError("semantic_cache", err)
+			}
+			vector, err := config.Embed(ctx, prompt)
+			if err != nil || len(vector) == 0 {
+				resp, err := next(ctx, req)
+				return resp, wrapIfNotWormholeError("semantic_cache", err)
+			}
+
+			if cached, score, found := config.Index.Nearest(namespace, vector); found && score >= config.Threshold {
+				if cloned, err := cloneValue(cached); err == nil {
+					return withSemanticCacheScore(cloned, score), nil
+				}
+			}
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				return nil, wrapIfNotWormholeError("semantic_cache", err)
+			}
+			if stored, cloneErr := cloneValue(resp); cloneErr == nil {
+				config.Index.Add(namespace, vector, stored, cacheTTL(ctx, config.TTL))
+			}
+			return resp, nil
+		}
+	}
+}
+
+// semanticCacheKey splits a request into the final user prompt and a
+// namespace covering everything else.
+func semanticCacheKey(ctx context.Context, req any) (namespace, prompt string, ok bool) {
+	var model, system string
+	var rest any
+	switch r := req.(type) {
+	case *types.TextRequest:
+		prompt, ok = finalUserPrompt(r.Messages)
+		shape := *r
+		shape.Messages = r.Messages[:max(len(r.Messages)-1, 0)]
+		model, system, rest = r.Model, r.SystemPrompt, shape
+	case *types.StructuredRequest:
+		prompt, ok = finalUserPrompt(r.Messages)
+		shape := *r
+		shape.Messages = r.Messages[:max(len(r.Messages)-1, 0)]
+		model, system, rest = r.Model, r.SystemPrompt, shape
+	}
+	if !ok {
+		return "", "", false
+	}
+	// SystemPrompt is excluded from request JSON, so hash it alongside.
+	hash, err := DefaultCacheKeyGenerator(struct {
+		Request any    `json:"request"`
+		System  string `json:"system,omitempty"`
+	}{rest, system})
+	if err != nil {
+		return "", "", false
+	}
+	provider, _ := ctx.Value(CtxKeyProvider).(string)
+	return provider + ":" + model + ":" + hash, prompt, true
+}
+
+func finalUserPrompt(messages []types.Message) (string, bool) {
+	if len(messages) == 0 {
+		return "", false
+	}
+	user, ok := messages[len(messages)-1].(*types.UserMessage)
+	if !ok || user.Content == "" || len(user.Media) > 0 {
+		return "", false
+	}
+	return user.Content, true
+}
+
+func withSemanticCacheScore(resp any, score float64) any {
+	return withResponseMetadata(resp, SemanticCacheScoreKey, score)
+}
+
+// withResponseMetadata sets key on text and structured responses.
+func withResponseMetadata(resp any, key string, value any) any {
+	switch r := resp.(type) {
+	case *types.TextResponse:
+		if r.Metadata == nil {
+			r.Metadata = make(map[string]any, 1)
+		}
+		r.Metadata[key] = value
+	case *types.StructuredResponse:
+		if r.Metadata == nil {
+			r.Metadata = make(map[string]any, 1)
+		}
+		r.Metadata[key] = value
+	}
+	return resp
+}
+
+// MemoryVectorIndex is an in-memory VectorIndex with exact nearest-neighbor
+// search. Each namespace keeps at most maxPerNamespace entries, evicting the
+// oldest first.
+type MemoryVectorIndex struct {
+	mu              sync.Mutex
+	maxPerNamespace int
+	namespaces      map[string][]vectorEntry
+}
+
+type vectorEntry struct {
+	vector    []float64
+	value     any
+	expiresAt time.Time
+}
+
+// NewMemoryVectorIndex creates an in-memory vector index.
+func NewMemoryVectorIndex(maxPerNamespace int) *MemoryVectorIndex {
+	if maxPerNamespace <= 0 {
+		maxPerNamespace = 1000
+	}
+	return &MemoryVectorIndex{
+		maxPerNamespace: maxPerNamespace,
+		namespaces:      make(map[string][]vectorEntry),
+	}
+}
+
+// Nearest returns the most similar unexpired entry, dropping expired ones.
+func (idx *MemoryVectorIndex) Nearest(namespace string, vector []float64) (any, float64, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	now := time.Now()
+	entries := idx.namespaces[namespace]
+	live := entries[:0]
+	var best any
+	bestScore := -2.0
+	for _, entry := range entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			continue
+		}
+		live = append(live, entry)
+		if score := types.CosineSimilarity(vector, entry.vector); score > bestScore {
+			best, bestScore = entry.value, score
+		}
+	}
+	clear(entries[len(live):])
+	idx.namespaces[namespace] = live
+	if len(live) == 0 {
+		delete(idx.namespaces, namespace)
+		return nil, 0, false
+	}
+	return best, bestScore, true
+}
+
+// Add stores value, evicting the oldest entry when the namespace is full.
+func (idx *MemoryVectorIndex) Add(namespace string, vector []float64, value any, ttl time.Duration) {
+	entry := vectorEntry{vector: append([]float64(nil), vector...), value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	entries := idx.namespaces[namespace]
+	if len(entries) >= idx.maxPerNamespace {
+		entries = append(entries[:0], entries[len(entries)-idx.maxPerNamespace+1:]...)
+	}
+	idx.namespaces[namespace] = append(entries, entry)
+}
+
+// Len returns the number of stored entries, including expired ones not yet
+// dropped by a lookup.
+func (idx *MemoryVectorIndex) Len() int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	n := 0
+	for _, entries := range idx.namespaces {
+		n += len(entries)
+	}
+	return n
+}
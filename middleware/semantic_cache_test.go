@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// wordEmbedder embeds text as counts over a tiny fixed vocabulary, so
+// paraphrases that share words land close together.
+func wordEmbedder(_ context.Context, text string) ([]float64, error) {
+	vocab := []string{"capital", "france", "paris", "weather", "today", "germany"}
+	vector := make([]float64, len(vocab))
+	for _, word := range strings.Fields(strings.ToLower(strings.Trim(text, "?."))) {
+		for i, v := range vocab {
+			if strings.Trim(word, "?.,") == v {
+				vector[i]++
+			}
+		}
+	}
+	return vector, nil
+}
+
+func textRequest(model, prompt string) *types.TextRequest {
+	return &types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: model},
+		Messages:    []types.Message{types.NewUserMessage(prompt)},
+	}
+}
+
+func TestSemanticCacheMiddlewareServesSimilarPrompts(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := SemanticCacheMiddleware(SemanticCacheConfig{Embed: wordEmbedder, Threshold: 0.9})(
+		func(_ context.Context, req any) (any, error) {
+			calls++
+			return &types.TextResponse{Text: "answer to " + req.(*types.TextRequest).Messages[0].GetContent().(string)}, nil
+		})
+	ctx := context.WithValue(context.Background(), CtxKeyProvider, "openai")
+
+	first, err := handler(ctx, textRequest("gpt-5", "What is the capital of France?"))
+	require.NoError(t, err)
+	hit, err := handler(ctx, textRequest("gpt-5", "capital of france, please"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, first.(*types.TextResponse).Text, hit.(*types.TextResponse).Text)
+	assert.InDelta(t, 1.0, hit.(*types.TextResponse).Metadata[SemanticCacheScoreKey], 1e-9)
+	assert.Nil(t, first.(*types.TextResponse).Metadata, "hits must not mutate the stored response")
+
+	// Unrelated prompts, other models, and other system prompts miss.
+	_, _ = handler(ctx, textRequest("gpt-5", "weather today"))
+	_, _ = handler(ctx, textRequest("gpt-5-mini", "What is the capital of France?"))
+	withSystem := textRequest("gpt-5", "What is the capital of France?")
+	withSystem.SystemPrompt = "Answer in German."
+	_, _ = handler(ctx, withSystem)
+	assert.Equal(t, 4, calls)
+}
+
+func TestSemanticCacheMiddlewareBypasses(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := SemanticCacheMiddleware(SemanticCacheConfig{Embed: wordEmbedder})(
+		func(context.Context, any) (any, error) {
+			calls++
+			return &types.TextResponse{Text: "ok"}, nil
+		})
+
+	stream := context.WithValue(context.Background(), CtxKeyMethod, "stream")
+	_, _ = handler(stream, textRequest("m", "capital of france"))
+	_, _ = handler(stream, textRequest("m", "capital of france"))
+
+	image := textRequest("m", "capital of france")
+	image.Messages[0].(*types.UserMessage).Media = []types.Media{&types.ImageMedia{URL: "https://example.com/a.png"}}
+	_, _ = handler(context.Background(), image)
+	_, _ = handler(context.Background(), image)
+	assert.Equal(t, 4, calls)
+}
+
+func TestMemoryVectorIndexTTLAndEviction(t *testing.T) {
+	t.Parallel()
+
+	index := NewMemoryVectorIndex(2)
+	index.Add("ns", []float64{1, 0}, "a", 0)
+	index.Add("ns", []float64{0, 1}, "b", 0)
+	index.Add("ns", []float64{1, 1}, "c", 0)
+	assert.Equal(t, 2, index.Len())
+
+	value, score, found := index.Nearest("ns", []float64{1, 0})
+	require.True(t, found)
+	assert.Equal(t, "c", value, "oldest entry a was evicted")
+	assert.Less(t, score, 1.0)
+
+	_, _, found = index.Nearest("other", []float64{1, 0})
+	assert.False(t, found)
+
+	index.Add("short", []float64{1}, "x", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, _, found = index.Nearest("short", []float64{1})
+	assert.False(t, found)
+	assert.Equal(t, 2, index.Len())
+}
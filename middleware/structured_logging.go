@@ -0,0 +1,329 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ContentLogging controls whether prompt and response text reach the log.
+type ContentLogging int
+
+const (
+	// ContentOmit logs only the length of prompts and responses.
+	ContentOmit ContentLogging = iota
+	// ContentTruncated logs the first MaxContentChars characters.
+	ContentTruncated
+	// ContentFull logs prompts and responses in full.
+	ContentFull
+)
+
+const defaultMaxContentChars = 256
+
+// StructuredLoggingConfig configures StructuredLoggingMiddleware.
+type StructuredLoggingConfig struct {
+	// Logger receives the records; nil uses slog.Default(). For zap, wrap a
+	// zap core with an slog handler such as go.uber.org/zap/exp/zapslog.
+	Logger types.Logger
+	// Level is used for successful calls; failures always log at Error.
+	Level slog.Level
+	// Content selects how much prompt and response text is logged.
+	Content ContentLogging
+	// MaxContentChars bounds ContentTruncated output. Defaults to 256.
+	MaxContentChars int
+	// Redact rewrites text before it is logged, e.g. RedactPII(). If one
+	// fails, the text is left out of the record.
+	Redact []Guardrail
+}
+
+// StructuredLoggingMiddleware writes one structured record per provider
+// call with the operation, provider, model, latency, token usage, finish
+// reason, request tags and, on failure, the error code. Unlike the debug
+// logging middleware it is meant for production: prompt and response text is
+// omitted unless Content asks for it, and then passes through Redact first.
+//
+// Example:
+//
+//	logging := middleware.NewStructuredLoggingMiddleware(middleware.StructuredLoggingConfig{
+//	    Logger:  slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+//	    Content: middleware.ContentTruncated,
+//	    Redact:  []middleware.Guardrail{middleware.RedactPII()},
+//	})
+//	client := wormhole.New(wormhole.WithProviderMiddleware(logging))
+type StructuredLoggingMiddleware struct {
+	config StructuredLoggingConfig
+}
+
+var _ types.ProviderMiddleware = (*StructuredLoggingMiddleware)(nil)
+
+// NewStructuredLoggingMiddleware returns structured logging middleware.
+func NewStructuredLoggingMiddleware(config StructuredLoggingConfig) *StructuredLoggingMiddleware {
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if config.MaxContentChars <= 0 {
+		config.MaxContentChars = defaultMaxContentChars
+	}
+	return &StructuredLoggingMiddleware{config: config}
+}
+
+// callRecord collects what one call contributes to its log record.
+type callRecord struct {
+	operation string
+	model     string
+	prompt    string
+	response  string
+	usage     *types.Usage
+	finish    string
+	rawFinish string // provider's own reason, logged when it says more than finish
+	attrs     []slog.Attr
+}
+
+func (m *StructuredLoggingMiddleware) emit(ctx context.Context, rec callRecord, start time.Time, err error) {
+	attrs := make([]slog.Attr, 0, 16)
+	attrs = append(attrs, slog.String("operation", rec.operation))
+	if provider, ok := ctx.Value(CtxKeyProvider).(string); ok && provider != "" {
+		attrs = append(attrs, slog.String("provider", types.SafeLogString(provider)))
+	}
+	if rec.model != "" {
+		attrs = append(attrs, slog.String("model", types.SafeLogString(rec.model)))
+	}
+	attrs = append(attrs, slog.Int64("latency_ms", time.Since(start).Milliseconds()))
+	if rec.usage != nil {
+		attrs = append(attrs,
+			slog.Int("prompt_tokens", rec.usage.PromptTokens),
+			slog.Int("completion_tokens", rec.usage.CompletionTokens),
+			slog.Int("total_tokens", rec.usage.TotalTokens))
+	}
+	if rec.finish != "" {
+		attrs = append(attrs, slog.String("finish_reason", rec.finish))
+	}
+	if rec.rawFinish != "" && rec.rawFinish != rec.finish {
+		attrs = append(attrs, slog.String("raw_finish_reason", types.SafeLogString(rec.rawFinish)))
+	}
+	attrs = append(attrs, rec.attrs...)
+	attrs = append(attrs, m.contentAttrs(ctx, "prompt", rec.prompt)...)
+	if err == nil {
+		attrs = append(attrs, m.contentAttrs(ctx, "response", rec.response)...)
+	}
+	if tags := TagsFromContext(ctx); len(tags) > 0 {
+		tagAttrs := make([]any, 0, len(tags))
+		for name, value := range tags {
+			tagAttrs = append(tagAttrs, slog.String(types.SafeLogString(boundedMetadata(name)), types.SafeLogString(boundedMetadata(value))))
+		}
+		attrs = append(attrs, slog.Group("tags", tagAttrs...))
+	}
+
+	level, msg := m.config.Level, "provider call"
+	if err != nil {
+		level, msg = slog.LevelError, "provider call failed"
+		errAttrs := make([]any, 0, 6)
+		for _, attr := range types.SafeErrorAttrs(err) {
+			errAttrs = append(errAttrs, attr)
+		}
+		attrs = append(attrs, slog.Group("error", errAttrs...))
+	}
+	m.config.Logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// contentAttrs returns name_chars and, when Content allows, the redacted and
+// possibly truncated text.
+func (m *StructuredLoggingMiddleware) contentAttrs(ctx context.Context, name, text string) []slog.Attr {
+	if text == "" {
+		return nil
+	}
+	attrs := []slog.Attr{slog.Int(name+"_chars", utf8.RuneCountInString(text))}
+	if m.config.Content == ContentOmit {
+		return attrs
+	}
+	for _, redact := range m.config.Redact {
+		var err error
+		if text, err = redact(ctx, text); err != nil {
+			return attrs
+		}
+	}
+	if m.config.Content == ContentTruncated {
+		text = truncateRunes(text, m.config.MaxContentChars)
+	}
+	return append(attrs, slog.String(name, text))
+}
+
+func truncateRunes(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit]) + "…"
+}
+
+// messagesText joins the system prompt and the string content of messages.
+func messagesText(systemPrompt string, messages []types.Message) string {
+	parts := make([]string, 0, len(messages)+1)
+	if systemPrompt != "" {
+		parts = append(parts, systemPrompt)
+	}
+	for _, msg := range messages {
+		if text, ok := msg.GetContent().(string); ok && text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// withStructuredLogging runs next and logs the call once it returns.
+func withStructuredLogging[Req any, Resp any](
+	m *StructuredLoggingMiddleware,
+	ctx context.Context,
+	req Req,
+	rec callRecord,
+	describe func(*callRecord, Resp),
+	next func(context.Context, Req) (Resp, error),
+) (Resp, error) {
+	start := time.Now()
+	resp, err := next(ctx, req)
+	if err == nil && !isNilResponse(resp) {
+		describe(&rec, resp)
+	}
+	m.emit(ctx, rec, start, err)
+	return resp, err
+}
+
+// ApplyText logs text generation calls.
+func (m *StructuredLoggingMiddleware) ApplyText(next types.TextHandler) types.TextHandler {
+	return func(ctx context.Context, req types.TextRequest) (*types.TextResponse, error) {
+		rec := callRecord{operation: "text", model: req.Model, prompt: messagesText(req.SystemPrompt, req.Messages)}
+		return withStructuredLogging(m, ctx, req, rec, func(rec *callRecord, resp *types.TextResponse) {
+			rec.response, rec.usage, rec.finish = resp.Text, resp.Usage, string(resp.FinishReason)
+			rec.rawFinish = resp.RawFinishReason
+			if len(resp.ToolCalls) > 0 {
+				rec.attrs = append(rec.attrs, slog.Int("tool_calls", len(resp.ToolCalls)))
+			}
+		}, next)
+	}
+}
+
+// ApplyStream logs streaming calls when the stream ends, with the chunk
+// count and the usage reported by the final chunks.
+func (m *StructuredLoggingMiddleware) ApplyStream(next types.StreamHandler) types.StreamHandler {
+	return func(ctx context.Context, req types.TextRequest) (<-chan types.StreamChunk, error) {
+		start := time.Now()
+		rec := callRecord{operation: "stream", model: req.Model, prompt: messagesText(req.SystemPrompt, req.Messages)}
+		stream, err := next(ctx, req)
+		if err != nil || stream == nil {
+			m.emit(ctx, rec, start, err)
+			return stream, err
+		}
+
+		out := make(chan types.StreamChunk, 1)
+		go func() {
+			defer close(out)
+			var text strings.Builder
+			var streamErr error
+			chunks := 0
+			defer func() {
+				rec.response = text.String()
+				rec.attrs = append(rec.attrs, slog.Int("chunks", chunks))
+				m.emit(ctx, rec, start, streamErr)
+			}()
+			for chunk := range stream {
+				chunks++
+				text.WriteString(chunk.Content())
+				if chunk.Usage != nil {
+					rec.usage = chunk.Usage
+				}
+				if chunk.FinishReason != nil {
+					rec.finish = string(*chunk.FinishReason)
+					rec.rawFinish = chunk.RawFinishReason
+				}
+				if chunk.Error != nil {
+					streamErr = chunk.Error
+				}
+				select {
+				case out <- chunk:
+				case <-ctx.Done():
+					streamErr = ctx.Err()
+					drainStreamUntilIdle(stream)
+					return
+				}
+			}
+		}()
+		return out, nil
+	}
+}
+
+// ApplyStructured logs structured output calls.
+func (m *StructuredLoggingMiddleware) ApplyStructured(next types.StructuredHandler) types.StructuredHandler {
+	return func(ctx context.Context, req types.StructuredRequest) (*types.StructuredResponse, error) {
+		rec := callRecord{operation: "structured", model: req.Model, prompt: messagesText(req.SystemPrompt, req.Messages)}
+		return withStructuredLogging(m, ctx, req, rec, func(rec *callRecord, resp *types.StructuredResponse) {
+			rec.usage, rec.response = resp.Usage, resp.Raw
+			if rec.response == "" && m.config.Content != ContentOmit {
+				if data, err := json.Marshal(resp.Data); err == nil {
+					rec.response = string(data)
+				}
+			}
+		}, next)
+	}
+}
+
+// ApplyEmbeddings logs embeddings calls.
+func (m *StructuredLoggingMiddleware) ApplyEmbeddings(next types.EmbeddingsHandler) types.EmbeddingsHandler {
+	return func(ctx context.Context, req types.EmbeddingsRequest) (*types.EmbeddingsResponse, error) {
+		rec := callRecord{
+			operation: "embeddings",
+			model:     req.Model,
+			prompt:    strings.Join(req.Input, "\n"),
+			attrs:     []slog.Attr{slog.Int("inputs", len(req.Input))},
+		}
+		return withStructuredLogging(m, ctx, req, rec, func(rec *callRecord, resp *types.EmbeddingsResponse) {
+			rec.usage = resp.Usage
+		}, next)
+	}
+}
+
+// ApplyAudio logs audio calls. Only text-to-speech input and speech-to-text
+// output are treated as content; audio bytes are never logged.
+func (m *StructuredLoggingMiddleware) ApplyAudio(next types.AudioHandler) types.AudioHandler {
+	return func(ctx context.Context, req types.AudioRequest) (*types.AudioResponse, error) {
+		rec := callRecord{operation: "audio", model: req.Model, attrs: []slog.Attr{slog.String("audio_type", string(req.Type))}}
+		if input, ok := req.Input.(string); ok {
+			rec.prompt = input
+		}
+		return withStructuredLogging(m, ctx, req, rec, func(rec *callRecord, resp *types.AudioResponse) {
+			rec.response = resp.Text
+			if len(resp.Audio) > 0 {
+				rec.attrs = append(rec.attrs, slog.Int("audio_bytes", len(resp.Audio)))
+			}
+		}, next)
+	}
+}
+
+// ApplyImage logs image generation calls.
+func (m *StructuredLoggingMiddleware) ApplyImage(next types.ImageHandler) types.ImageHandler {
+	return func(ctx context.Context, req types.ImageRequest) (*types.ImageResponse, error) {
+		rec := callRecord{operation: "image", model: req.Model, prompt: req.Prompt}
+		return withStructuredLogging(m, ctx, req, rec, func(rec *callRecord, resp *types.ImageResponse) {
+			rec.attrs = append(rec.attrs, slog.Int("images", len(resp.Images)))
+		}, next)
+	}
+}
+
+// ApplyRerank logs rerank calls; the query is the prompt.
+func (m *StructuredLoggingMiddleware) ApplyRerank(next types.RerankHandler) types.RerankHandler {
+	return func(ctx context.Context, req types.RerankRequest) (*types.RerankResponse, error) {
+		rec := callRecord{
+			operation: "rerank",
+			model:     req.Model,
+			prompt:    req.Query,
+			attrs:     []slog.Attr{slog.Int("documents", len(req.Documents))},
+		}
+		return withStructuredLogging(m, ctx, req, rec, func(rec *callRecord, resp *types.RerankResponse) {
+			rec.usage = resp.Usage
+		}, next)
+	}
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func structuredLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func newStructuredLogger(buf *bytes.Buffer) types.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestStructuredLoggingOmitsContentByDefault(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logging := NewStructuredLoggingMiddleware(StructuredLoggingConfig{Logger: newStructuredLogger(&buf)})
+	handler := logging.ApplyText(func(_ context.Context, _ types.TextRequest) (*types.TextResponse, error) {
+		return &types.TextResponse{
+			Text:         "hello jane",
+			FinishReason: types.FinishReasonStop,
+			Usage:        &types.Usage{PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10},
+		}, nil
+	})
+
+	ctx := WithTags(context.WithValue(context.Background(), CtxKeyProvider, "openai"), map[string]string{"tenant": "acme"})
+	_, err := handler(ctx, *textRequest("gpt-5", "my email is jane@example.com"))
+	require.NoError(t, err)
+
+	records := structuredLogRecords(t, &buf)
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "text", record["operation"])
+	assert.Equal(t, "openai", record["provider"])
+	assert.Equal(t, "gpt-5", record["model"])
+	assert.EqualValues(t, 10, record["total_tokens"])
+	assert.Equal(t, "stop", record["finish_reason"])
+	assert.EqualValues(t, 28, record["prompt_chars"])
+	assert.Equal(t, map[string]any{"tenant": "acme"}, record["tags"])
+	assert.NotContains(t, record, "prompt")
+	assert.NotContains(t, record, "response")
+	assert.NotContains(t, buf.String(), "jane")
+}
+
+func TestStructuredLoggingRedactsAndTruncatesContent(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logging := NewStructuredLoggingMiddleware(StructuredLoggingConfig{
+		Logger:          newStructuredLogger(&buf),
+		Content:         ContentTruncated,
+		MaxContentChars: 21,
+		Redact:          []Guardrail{RedactPII()},
+	})
+	handler := logging.ApplyText(func(_ context.Context, _ types.TextRequest) (*types.TextResponse, error) {
+		return &types.TextResponse{Text: "ok"}, nil
+	})
+
+	_, err := handler(context.Background(), *textRequest("gpt-5", "mail jane@example.com about the invoice"))
+	require.NoError(t, err)
+
+	record := structuredLogRecords(t, &buf)[0]
+	assert.Equal(t, "mail [REDACTED_EMAIL]…", record["prompt"])
+	assert.Equal(t, "ok", record["response"])
+	assert.NotContains(t, buf.String(), "jane@example.com")
+}
+
+func TestStructuredLoggingRecordsErrorCode(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logging := NewStructuredLoggingMiddleware(StructuredLoggingConfig{Logger: newStructuredLogger(&buf), Content: ContentFull})
+	handler := logging.ApplyEmbeddings(func(_ context.Context, _ types.EmbeddingsRequest) (*types.EmbeddingsResponse, error) {
+		return nil, types.NewWormholeError(types.ErrorCodeRateLimit, "slow down", true)
+	})
+
+	_, err := handler(context.Background(), types.EmbeddingsRequest{Model: "embed", Input: []string{"a", "b"}})
+	require.Error(t, err)
+
+	record := structuredLogRecords(t, &buf)[0]
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "embeddings", record["operation"])
+	assert.EqualValues(t, 2, record["inputs"])
+	errGroup, ok := record["error"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, string(types.ErrorCodeRateLimit), errGroup["code"])
+}
+
+func TestStructuredLoggingLogsStreamOnCompletion(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logging := NewStructuredLoggingMiddleware(StructuredLoggingConfig{Logger: newStructuredLogger(&buf), Content: ContentFull})
+	handler := logging.ApplyStream(func(_ context.Context, _ types.TextRequest) (<-chan types.StreamChunk, error) {
+		stream := make(chan types.StreamChunk, 2)
+		stop := types.FinishReasonStop
+		stream <- types.StreamChunk{Text: "hel"}
+		stream <- types.StreamChunk{Text: "lo", FinishReason: &stop, Usage: &types.Usage{TotalTokens: 4}}
+		close(stream)
+		return stream, nil
+	})
+
+	stream, err := handler(context.Background(), *textRequest("gpt-5", "hi"))
+	require.NoError(t, err)
+	for range stream {
+	}
+
+	record := structuredLogRecords(t, &buf)[0]
+	assert.Equal(t, "stream", record["operation"])
+	assert.Equal(t, "hello", record["response"])
+	assert.EqualValues(t, 2, record["chunks"])
+	assert.EqualValues(t, 4, record["total_tokens"])
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// CtxKeyTags carries caller-defined request tags such as {"tenant": "acme"}.
+// Metrics middleware adds them to request labels and logging middleware adds
+// them to log records as tag_<name> attributes.
+const CtxKeyTags contextKey = "tags"
+
+// WithTags returns a context that tags requests made with it. Tags already on
+// ctx are kept unless tags overrides them.
+func WithTags(ctx context.Context, tags map[string]string) context.Context {
+	if len(tags) == 0 {
+		return ctx
+	}
+	existing := TagsFromContext(ctx)
+	merged := make(map[string]string, len(existing)+len(tags))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, CtxKeyTags, merged)
+}
+
+// TagsFromContext returns the tags stored by WithTags, or nil. The returned
+// map must not be modified.
+func TagsFromContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	tags, _ := ctx.Value(CtxKeyTags).(map[string]string)
+	return tags
+}
+
+// formatTags renders tags as sorted "name=value" pairs joined by commas.
+func formatTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
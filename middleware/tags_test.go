@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTagsMerges(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithTags(context.Background(), map[string]string{"tenant": "acme", "feature": "search"})
+	ctx = WithTags(ctx, map[string]string{"feature": "summarize"})
+	assert.Equal(t, map[string]string{"tenant": "acme", "feature": "summarize"}, TagsFromContext(ctx))
+	assert.Nil(t, TagsFromContext(context.Background()))
+
+	base := context.Background()
+	assert.Equal(t, base, WithTags(base, nil))
+}
+
+func TestTagsFlowIntoMetricsLabels(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultEnhancedMetricsConfig()
+	config.LabelAggregation = true
+	collector := NewEnhancedMetricsCollector(config)
+
+	acme := WithTags(context.Background(), map[string]string{"tenant": "acme"})
+	globex := WithTags(context.Background(), map[string]string{"tenant": "globex"})
+	collector.RecordRequest(requestLabelsFromContext(acme, "text", "gpt-5"), time.Millisecond, nil, 0, 10, 5)
+	collector.RecordRequest(requestLabelsFromContext(acme, "text", "gpt-5"), time.Millisecond, nil, 0, 20, 5)
+	collector.RecordRequest(requestLabelsFromContext(globex, "text", "gpt-5"), time.Millisecond, nil, 0, 1, 1)
+
+	labels := requestLabelsFromContext(acme, "text", "gpt-5")
+	assert.Equal(t, "unknown:gpt-5:text::tenant=acme", labels.String())
+	assert.Equal(t, int64(2), collector.GetStats(labels)["requests"])
+
+	byTenant := collector.GetTagStats("tenant")
+	require.Len(t, byTenant, 2)
+	assert.Equal(t, int64(2), byTenant["acme"]["requests"])
+	assert.Equal(t, int64(30), byTenant["acme"]["input_tokens"])
+	assert.Equal(t, int64(1), byTenant["globex"]["requests"])
+
+	collector.Reset()
+	assert.Empty(t, collector.GetTagStats("tenant"))
+}
+
+func TestTagsInLogAttributes(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	ctx := WithTags(context.Background(), map[string]string{"tenant": "acme"})
+	logger.Info("done", requestMetadataAttrs(ctx)...)
+	assert.Contains(t, buf.String(), "tag_tenant=acme")
+}
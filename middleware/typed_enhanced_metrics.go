@@ -27,14 +27,18 @@ func (m *TypedEnhancedMetricsMiddleware) ApplyText(next types.TextHandler) types
 			if resp != nil {
 				outputTokens = estimateTextTokens(resp.Text)
 			}
+			labels := requestLabelsFromContext(ctx, "text", request.Model)
 			m.collector.RecordRequest(
-				requestLabelsFromContext(ctx, "text", request.Model),
+				labels,
 				duration,
 				err,
 				0,
-				estimateInputTokens(request.Messages),
+				estimateInputTokens(request.Model, request.Messages),
 				outputTokens,
 			)
+			if err == nil && resp != nil {
+				m.collector.RecordCache(labels, resp.CacheInfo())
+			}
 		})
 	}
 }
@@ -48,7 +52,7 @@ func (m *TypedEnhancedMetricsMiddleware) ApplyStream(next types.StreamHandler) t
 				duration,
 				err,
 				0,
-				estimateInputTokens(request.Messages),
+				estimateInputTokens(request.Model, request.Messages),
 				0,
 			)
 		})
@@ -63,14 +67,18 @@ func (m *TypedEnhancedMetricsMiddleware) ApplyStructured(next types.StructuredHa
 			if resp != nil {
 				outputTokens = estimateStructuredOutputTokens(resp.Content)
 			}
+			labels := requestLabelsFromContext(ctx, "structured", request.Model)
 			m.collector.RecordRequest(
-				requestLabelsFromContext(ctx, "structured", request.Model),
+				labels,
 				duration,
 				err,
 				0,
-				estimateInputTokens(request.Messages),
+				estimateInputTokens(request.Model, request.Messages),
 				outputTokens,
 			)
+			if err == nil && resp != nil {
+				m.collector.RecordCache(labels, resp.CacheInfo())
+			}
 		})
 	}
 }
@@ -156,8 +164,9 @@ func (m *TypedEnhancedMetricsMiddleware) ApplyImage(next types.ImageHandler) typ
 
 // Helper functions for token estimation
 
-// estimateInputTokens estimates tokens from messages
-func estimateInputTokens(messages []types.Message) int {
+// estimateInputTokens estimates tokens from messages, including attached
+// images as the model's registry entry bills them.
+func estimateInputTokens(model string, messages []types.Message) int {
 	total := 0
 	for _, msg := range messages {
 		content := msg.GetContent()
@@ -165,6 +174,9 @@ func estimateInputTokens(messages []types.Message) int {
 			total += estimateTextTokens(str)
 		}
 	}
+	if images := types.MessageImages(messages); len(images) > 0 {
+		total += types.EstimateImageTokens(model, images...)
+	}
 	return total
 }
 
@@ -47,7 +47,8 @@ func withLogging[Req any, Resp any](
 
 	// Log timing if enabled
 	if config.LogTiming {
-		config.Logger.Debug("Request completed", "request_type", requestType, "duration", duration)
+		args := append([]any{"request_type", requestType, "duration", duration}, requestMetadataAttrs(ctx)...)
+		config.Logger.Debug("Request completed", args...)
 	}
 
 	// Log response if enabled (need to check for nil with type assertion)
@@ -12,6 +12,8 @@ type TypedTimeoutMiddleware struct {
 	timeout time.Duration
 }
 
+var _ types.AudioStreamMiddleware = (*TypedTimeoutMiddleware)(nil)
+
 // NewTypedTimeoutMiddleware creates a new type-safe timeout middleware
 func NewTypedTimeoutMiddleware(timeout time.Duration) *TypedTimeoutMiddleware {
 	return &TypedTimeoutMiddleware{
@@ -58,63 +60,81 @@ func (m *TypedTimeoutMiddleware) ApplyText(next types.TextHandler) types.TextHan
 	}
 }
 
-// ApplyStream wraps streaming calls with timeout enforcement
-// Note: Streaming requires special handling to maintain timeout during the stream
-func (m *TypedTimeoutMiddleware) ApplyStream(next types.StreamHandler) types.StreamHandler {
-	return func(ctx context.Context, request types.TextRequest) (<-chan types.StreamChunk, error) {
-		ctx, cancel := context.WithTimeout(ctx, m.timeout)
+// streamWithTimeout opens a stream under timeout and keeps enforcing it
+// while chunks are forwarded, for text and transcription streams alike.
+func streamWithTimeout[Req any](
+	ctx context.Context,
+	timeout time.Duration,
+	request Req,
+	next func(context.Context, Req) (<-chan types.StreamChunk, error),
+) (<-chan types.StreamChunk, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 
-		type result struct {
-			stream <-chan types.StreamChunk
-			err    error
-		}
+	type result struct {
+		stream <-chan types.StreamChunk
+		err    error
+	}
 
-		done := make(chan result, 1)
+	done := make(chan result, 1)
 
-		go func() {
-			stream, err := next(ctx, request)
-			done <- result{stream, err}
-		}()
+	go func() {
+		stream, err := next(ctx, request)
+		done <- result{stream, err}
+	}()
 
-		select {
-		case <-ctx.Done():
+	select {
+	case <-ctx.Done():
+		cancel()
+		return nil, ctx.Err()
+	case res := <-done:
+		if res.err != nil {
 			cancel()
-			return nil, ctx.Err()
-		case res := <-done:
-			if res.err != nil {
-				cancel()
-				return res.stream, res.err
-			}
+			return res.stream, res.err
+		}
 
-			// Wrap stream to handle timeout during streaming
-			wrappedStream := make(chan types.StreamChunk)
-			go func() {
-				defer close(wrappedStream)
-				defer cancel()
+		// Wrap stream to handle timeout during streaming
+		wrappedStream := make(chan types.StreamChunk)
+		go func() {
+			defer close(wrappedStream)
+			defer cancel()
 
-				if res.stream == nil {
-					return
-				}
+			if res.stream == nil {
+				return
+			}
 
-				for {
+			for {
+				select {
+				case chunk, ok := <-res.stream:
+					if !ok {
+						return
+					}
 					select {
-					case chunk, ok := <-res.stream:
-						if !ok {
-							return
-						}
-						select {
-						case wrappedStream <- chunk:
-						case <-ctx.Done():
-							return
-						}
+					case wrappedStream <- chunk:
 					case <-ctx.Done():
 						return
 					}
+				case <-ctx.Done():
+					return
 				}
-			}()
+			}
+		}()
 
-			return wrappedStream, nil
-		}
+		return wrappedStream, nil
+	}
+}
+
+// ApplyStream wraps streaming calls with timeout enforcement
+// Note: Streaming requires special handling to maintain timeout during the stream
+func (m *TypedTimeoutMiddleware) ApplyStream(next types.StreamHandler) types.StreamHandler {
+	return func(ctx context.Context, request types.TextRequest) (<-chan types.StreamChunk, error) {
+		return streamWithTimeout(ctx, m.timeout, request, next)
+	}
+}
+
+// ApplyAudioStream wraps streamed transcriptions with timeout enforcement
+func (m *TypedTimeoutMiddleware) ApplyAudioStream(next types.AudioStreamHandler) types.AudioStreamHandler {
+	return func(ctx context.Context, request types.AudioRequest) (<-chan types.TextChunk, error) {
+		return streamWithTimeout(ctx, m.timeout, request, next)
 	}
 }
 
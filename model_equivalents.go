@@ -0,0 +1,124 @@
+package wormhole
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed model_equivalents.json
+var modelEquivalentsJSON []byte
+
+// ModelEquivalenceClass groups models of comparable capability across
+// providers. Models lists each provider's models in preference order; the
+// first entry is the translation target when falling back to that provider.
+type ModelEquivalenceClass struct {
+	Name   string              `json:"name"`
+	Models map[string][]string `json:"models"`
+}
+
+// builtinModelEquivalents is the parsed built-in table. The JSON is embedded
+// at build time, so a parse error is a packaging bug and panics at init
+// rather than leaving fallbacks silently untranslated.
+var builtinModelEquivalents = mustParseModelEquivalents(modelEquivalentsJSON)
+
+// KnownModelEquivalents returns the built-in model equivalence table.
+func KnownModelEquivalents() []ModelEquivalenceClass {
+	return cloneModelEquivalenceClasses(loadModelEquivalents())
+}
+
+// EquivalentModel translates model to the preferred equivalent on provider
+// using the built-in table. It reports false when model belongs to no class or
+// the class has no entry for provider.
+//
+// Example:
+//
+//	model, ok := wormhole.EquivalentModel("gpt-4o", "anthropic") // "claude-sonnet-4-5", true
+func EquivalentModel(model, provider string) (string, bool) {
+	return lookupEquivalentModel(loadModelEquivalents(), model, provider)
+}
+
+// equivalentModel consults caller overrides from WithModelEquivalents before
+// the built-in table.
+func (p *Wormhole) equivalentModel(model, provider string) (string, bool) {
+	if target, ok := lookupEquivalentModel(p.config.ModelEquivalents, model, provider); ok {
+		return target, true
+	}
+	return EquivalentModel(model, provider)
+}
+
+// resolveTextRoutes fills routes that omit Model with the equivalent of
+// primaryModel on the route's provider. When no equivalent is known the
+// primary model name is used unchanged, which suits gateways that accept
+// foreign model IDs.
+func (p *Wormhole) resolveTextRoutes(routes []TextRoute, primaryModel string) []TextRoute {
+	resolved := make([]TextRoute, len(routes))
+	for i, route := range routes {
+		resolved[i] = route
+		if route.Model != "" {
+			continue
+		}
+		resolved[i].Model = primaryModel
+		if target, ok := p.equivalentModel(primaryModel, route.Provider); ok {
+			resolved[i].Model = target
+		}
+	}
+	return resolved
+}
+
+func lookupEquivalentModel(classes []ModelEquivalenceClass, model, provider string) (string, bool) {
+	for _, class := range classes {
+		if !modelEquivalenceClassContains(class, model) {
+			continue
+		}
+		if targets := class.Models[provider]; len(targets) > 0 {
+			return targets[0], true
+		}
+	}
+	return "", false
+}
+
+func modelEquivalenceClassContains(class ModelEquivalenceClass, model string) bool {
+	for _, models := range class.Models {
+		for _, candidate := range models {
+			if candidate == model {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func loadModelEquivalents() []ModelEquivalenceClass {
+	return builtinModelEquivalents
+}
+
+func mustParseModelEquivalents(data []byte) []ModelEquivalenceClass {
+	classes, err := parseModelEquivalents(data)
+	if err != nil {
+		panic(err)
+	}
+	return classes
+}
+
+func parseModelEquivalents(data []byte) ([]ModelEquivalenceClass, error) {
+	var classes []ModelEquivalenceClass
+	if err := json.Unmarshal(data, &classes); err != nil {
+		return nil, fmt.Errorf("load model equivalents: %w", err)
+	}
+	return classes, nil
+}
+
+func cloneModelEquivalenceClasses(src []ModelEquivalenceClass) []ModelEquivalenceClass {
+	if src == nil {
+		return nil
+	}
+	out := make([]ModelEquivalenceClass, len(src))
+	for i, class := range src {
+		out[i] = ModelEquivalenceClass{Name: class.Name, Models: make(map[string][]string, len(class.Models))}
+		for provider, models := range class.Models {
+			out[i].Models[provider] = append([]string(nil), models...)
+		}
+	}
+	return out
+}
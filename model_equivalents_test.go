@@ -0,0 +1,107 @@
+package wormhole
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestEquivalentModelUsesBuiltInTable(t *testing.T) {
+	t.Parallel()
+
+	model, ok := EquivalentModel("gpt-4o", "anthropic")
+	require.True(t, ok)
+	assert.Equal(t, "claude-sonnet-4-5", model)
+
+	model, ok = EquivalentModel("claude-3-7-sonnet-latest", "gemini")
+	require.True(t, ok)
+	assert.Equal(t, "gemini-2.5-pro", model)
+
+	model, ok = EquivalentModel("gemini-2.0-pro", "openai")
+	require.True(t, ok, "gemini-2.0-pro is in the gpt-4o class")
+	assert.Equal(t, "gpt-4o", model)
+
+	_, ok = EquivalentModel("unknown-model", "anthropic")
+	assert.False(t, ok)
+	_, ok = EquivalentModel("gpt-4o", "no-such-provider")
+	assert.False(t, ok)
+
+	classes := KnownModelEquivalents()
+	require.NotEmpty(t, classes)
+	classes[0].Models["openai"][0] = "mutated"
+	assert.NotEqual(t, "mutated", KnownModelEquivalents()[0].Models["openai"][0])
+}
+
+func TestParseModelEquivalentsReportsInvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseModelEquivalents([]byte(`{"name":`))
+	require.ErrorContains(t, err, "load model equivalents")
+	assert.Panics(t, func() { mustParseModelEquivalents([]byte("not json")) })
+}
+
+func TestWithFallbackProvidersTranslatesModel(t *testing.T) {
+	t.Parallel()
+
+	primary := &providerFallbackTextProvider{
+		BaseProvider: types.NewBaseProvider("primary"),
+		err:          errors.New("primary unavailable"),
+	}
+	secondary := &providerFallbackTextProvider{
+		BaseProvider: types.NewBaseProvider("secondary"),
+		response:     "secondary response",
+	}
+	client := New(
+		WithDefaultProvider("primary"),
+		WithCustomProvider("primary", func(types.ProviderConfig) (types.Provider, error) { return primary, nil }),
+		WithProviderConfig("primary", types.ProviderConfig{}),
+		WithCustomProvider("secondary", func(types.ProviderConfig) (types.Provider, error) { return secondary, nil }),
+		WithProviderConfig("secondary", types.ProviderConfig{}),
+		WithModelEquivalents(ModelEquivalenceClass{
+			Name: "house",
+			Models: map[string][]string{
+				"primary":   {"primary-large"},
+				"secondary": {"secondary-large"},
+			},
+		}),
+		WithDiscovery(false),
+	)
+
+	response, err := client.Text().
+		Model("primary-large").
+		WithFallbackProviders("secondary").
+		Prompt("hello").
+		Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "secondary-large", response.Model)
+
+	response, err = client.Text().
+		Model("unmapped-model").
+		WithFallbackProviders("secondary").
+		Prompt("hello").
+		Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "unmapped-model", response.Model, "unknown models pass through unchanged")
+}
+
+func TestModelEquivalentOverridesTakePrecedence(t *testing.T) {
+	t.Parallel()
+
+	client := New(
+		WithModelEquivalents(ModelEquivalenceClass{Name: "first", Models: map[string][]string{"openai": {"gpt-4o"}, "anthropic": {"claude-first"}}}),
+		WithModelEquivalents(ModelEquivalenceClass{Name: "second", Models: map[string][]string{"openai": {"gpt-4o"}, "anthropic": {"claude-second"}}}),
+		WithDiscovery(false),
+	)
+
+	model, ok := client.equivalentModel("gpt-4o", "anthropic")
+	require.True(t, ok)
+	assert.Equal(t, "claude-second", model)
+
+	routes := client.resolveTextRoutes([]TextRoute{{Provider: "anthropic"}, {Provider: "gemini", Model: "explicit"}}, "gpt-4o")
+	assert.Equal(t, []TextRoute{{Provider: "anthropic", Model: "claude-second"}, {Provider: "gemini", Model: "explicit"}}, routes)
+}
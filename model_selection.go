@@ -214,5 +214,6 @@ func cloneModelInfo(model *types.ModelInfo) *types.ModelInfo {
 		cost := *model.Cost
 		cloned.Cost = &cost
 	}
+	cloned.ParamConstraints = model.ParamConstraints.Clone()
 	return &cloned
 }
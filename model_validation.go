@@ -2,15 +2,58 @@ package wormhole
 
 import (
 	"fmt"
+	"log/slog"
+	"slices"
 
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
+// ModelValidationMode sets how model validation treats models missing from
+// the registry. See WithModelValidationMode.
+type ModelValidationMode int
+
+const (
+	// Strict rejects models the registry does not know. It is the default.
+	Strict ModelValidationMode = iota
+	// Permissive passes unknown models through to the provider, logging a
+	// warning the first time each provider/model pair is seen. Known models
+	// are still checked for deprecation and capabilities.
+	Permissive
+)
+
+// String returns the mode name.
+func (m ModelValidationMode) String() string {
+	switch m {
+	case Strict:
+		return "strict"
+	case Permissive:
+		return "permissive"
+	}
+	return fmt.Sprintf("ModelValidationMode(%d)", int(m))
+}
+
+// maxUnknownModelWarnings caps how many provider/model pairs Permissive mode
+// remembers having warned about. Past it the set starts over, so a client fed
+// arbitrary model IDs repeats some warnings instead of growing without bound.
+const maxUnknownModelWarnings = 1024
+
 var textModelCapabilities = []types.ModelCapability{
 	types.CapabilityText,
 	types.CapabilityChat,
 }
 
+// featureCapabilities are the per-request features of the capability matrix.
+// Registries rarely list them, so a model is only held to them once its entry
+// names at least one; until then its feature support is unknown and allowed.
+var featureCapabilities = []types.ModelCapability{
+	types.CapabilityAudioInput,
+	types.CapabilityAudioOutput,
+	types.CapabilityJSONSchemaStrict,
+	types.CapabilityParallelToolCalls,
+	types.CapabilityLogprobs,
+	types.CapabilitySeed,
+}
+
 // validateModelAttempt applies the opt-in registry policy immediately before
 // an operation attempt. Empty registries and dynamic-provider catalogs remain
 // permissive so provider-native model IDs keep working by default.
@@ -33,15 +76,29 @@ func (p *Wormhole) validateModelAttempt(providerName, modelID string, anyOf, req
 
 	model, ok := p.modelRegistry.Get(modelID)
 	if !ok {
+		if p.config.ModelValidationMode == Permissive {
+			p.warnUnknownModel(resolvedProvider, modelID, "model is not in the registry")
+			return nil
+		}
 		return types.ErrModelNotFound.WithModel(modelID)
 	}
 	if model.Provider != "" && model.Provider != resolvedProvider {
+		details := fmt.Sprintf("model is registered for provider %q", model.Provider)
+		if p.config.ModelValidationMode == Permissive {
+			p.warnUnknownModel(resolvedProvider, modelID, details)
+			return nil
+		}
 		return types.ErrModelNotFound.
 			WithModel(modelID).
 			WithProvider(resolvedProvider).
-			WithDetails(fmt.Sprintf("model is registered for provider %q", model.Provider))
+			WithDetails(details)
 	}
 
+	if !declaresFeatures(model) {
+		required = slices.DeleteFunc(slices.Clone(required), func(capability types.ModelCapability) bool {
+			return slices.Contains(featureCapabilities, capability)
+		})
+	}
 	if err := p.modelRegistry.ValidateModel(modelID, required); err != nil {
 		return err
 	}
@@ -61,6 +118,38 @@ func (p *Wormhole) validateModelAttempt(providerName, modelID string, anyOf, req
 		WithDetails(fmt.Sprintf("missing one of capabilities: %v", anyOf))
 }
 
+// declaresFeatures reports whether model's registry entry lists any feature
+// capability, making the ones it leaves out explicitly unsupported.
+func declaresFeatures(model *types.ModelInfo) bool {
+	return slices.ContainsFunc(model.Capabilities, func(capability types.ModelCapability) bool {
+		return slices.Contains(featureCapabilities, capability)
+	})
+}
+
+// warnUnknownModel logs a permissive-mode pass-through once per
+// provider/model pair.
+func (p *Wormhole) warnUnknownModel(provider, modelID, reason string) {
+	key := provider + "/" + modelID
+	p.unknownModelsMu.Lock()
+	_, seen := p.unknownModels[key]
+	if !seen {
+		if p.unknownModels == nil || len(p.unknownModels) >= maxUnknownModelWarnings {
+			p.unknownModels = make(map[string]struct{})
+		}
+		p.unknownModels[key] = struct{}{}
+	}
+	p.unknownModelsMu.Unlock()
+	if seen {
+		return
+	}
+	logger := p.config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn("passing unknown model through permissive validation",
+		"provider", provider, "model", modelID, "reason", reason)
+}
+
 func textRequiredCapabilities(request *types.TextRequest, toolsEnabled, streaming bool) []types.ModelCapability {
 	required := make([]types.ModelCapability, 0, 3)
 	if streaming {
@@ -69,20 +158,55 @@ func textRequiredCapabilities(request *types.TextRequest, toolsEnabled, streamin
 	if toolsEnabled || len(request.Tools) > 0 {
 		required = append(required, types.CapabilityFunctions)
 	}
-	if textRequestHasMedia(request) {
+	vision, audio := textRequestMedia(request)
+	if vision {
 		required = append(required, types.CapabilityVision)
 	}
+	if audio {
+		required = append(required, types.CapabilityAudioInput)
+	}
+	return append(required, textFeatureCapabilities(request)...)
+}
+
+// textFeatureCapabilities lists the optional features a TextRequest turns on.
+func textFeatureCapabilities(request *types.TextRequest) []types.ModelCapability {
+	if request == nil {
+		return nil
+	}
+	var required []types.ModelCapability
+	if request.Audio != nil {
+		required = append(required, types.CapabilityAudioOutput)
+	}
+	if request.ParallelToolCalls != nil {
+		required = append(required, types.CapabilityParallelToolCalls)
+	}
+	if request.Logprobs || request.TopLogprobs > 0 {
+		required = append(required, types.CapabilityLogprobs)
+	}
+	if request.Seed != nil {
+		required = append(required, types.CapabilitySeed)
+	}
 	return required
 }
 
-func textRequestHasMedia(request *types.TextRequest) bool {
+// textRequestMedia reports whether the user messages carry visual media
+// (images and documents) and audio.
+func textRequestMedia(request *types.TextRequest) (vision, audio bool) {
 	if request == nil {
-		return false
+		return false, false
 	}
 	for _, message := range request.Messages {
-		if user, ok := message.(*types.UserMessage); ok && len(user.Media) > 0 {
-			return true
+		user, ok := message.(*types.UserMessage)
+		if !ok {
+			continue
+		}
+		for _, media := range user.Media {
+			if _, isAudio := media.(*types.AudioMedia); isAudio {
+				audio = true
+			} else {
+				vision = true
+			}
 		}
 	}
-	return false
+	return vision, audio
 }
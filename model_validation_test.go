@@ -1,7 +1,12 @@
 package wormhole
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -53,12 +58,11 @@ func (p *validationRecordingProvider) reset() {
 	p.models = nil
 }
 
-func useModelRegistry(t *testing.T, models ...*types.ModelInfo) {
-	t.Helper()
-	original := types.DefaultModelRegistry
-	types.DefaultModelRegistry = types.NewModelRegistry()
-	types.DefaultModelRegistry.LoadModelsFromConfig(models)
-	t.Cleanup(func() { types.DefaultModelRegistry = original })
+// withTestModels gives a client its own registry holding models.
+func withTestModels(models ...*types.ModelInfo) Option {
+	registry := types.NewModelRegistry()
+	registry.LoadModelsFromConfig(models)
+	return WithModelRegistry(registry)
 }
 
 func validationTestClient(config types.ProviderConfig, opts ...Option) *Wormhole {
@@ -74,32 +78,106 @@ func validationTestClient(config types.ProviderConfig, opts ...Option) *Wormhole
 
 func TestModelValidationActivationRules(t *testing.T) {
 	t.Run("empty registry is permissive", func(t *testing.T) {
-		useModelRegistry(t)
-		client := validationTestClient(types.ProviderConfig{})
+		registry := withTestModels()
+		client := validationTestClient(types.ProviderConfig{}, registry)
 		if err := client.validateModelAttempt("mock", "unknown", textModelCapabilities, nil); err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("disabled validation is permissive", func(t *testing.T) {
-		useModelRegistry(t, &types.ModelInfo{ID: "known", Capabilities: []types.ModelCapability{types.CapabilityText}})
-		client := validationTestClient(types.ProviderConfig{}, WithModelValidation(false))
+		registry := withTestModels(&types.ModelInfo{ID: "known", Capabilities: []types.ModelCapability{types.CapabilityText}})
+		client := validationTestClient(types.ProviderConfig{}, registry, WithModelValidation(false))
 		if err := client.validateModelAttempt("mock", "unknown", textModelCapabilities, nil); err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("dynamic provider is permissive", func(t *testing.T) {
-		useModelRegistry(t, &types.ModelInfo{ID: "known", Capabilities: []types.ModelCapability{types.CapabilityText}})
-		client := validationTestClient(types.ProviderConfig{DynamicModels: true})
+		registry := withTestModels(&types.ModelInfo{ID: "known", Capabilities: []types.ModelCapability{types.CapabilityText}})
+		client := validationTestClient(types.ProviderConfig{DynamicModels: true}, registry)
 		if err := client.validateModelAttempt("mock", "unknown", textModelCapabilities, nil); err != nil {
 			t.Fatal(err)
 		}
 	})
 }
 
+func TestModelValidationPermissiveModePassesUnknownModels(t *testing.T) {
+	var logs bytes.Buffer
+	registry := withTestModels(
+		&types.ModelInfo{ID: "text-only", Provider: "mock", Capabilities: []types.ModelCapability{types.CapabilityText}},
+		&types.ModelInfo{ID: "elsewhere", Provider: "other", Capabilities: []types.ModelCapability{types.CapabilityText}},
+	)
+	client := validationTestClient(types.ProviderConfig{}, registry,
+		WithModelValidationMode(Permissive),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+
+	for range 2 {
+		if err := client.validateModelAttempt("mock", "custom-finetune", textModelCapabilities, nil); err != nil {
+			t.Fatalf("unknown model = %v, want pass-through", err)
+		}
+	}
+	if err := client.validateModelAttempt("mock", "elsewhere", textModelCapabilities, nil); err != nil {
+		t.Fatalf("model registered for another provider = %v, want pass-through", err)
+	}
+	if got := strings.Count(logs.String(), "model=custom-finetune"); got != 1 {
+		t.Fatalf("warnings for custom-finetune = %d, want 1:\n%s", got, logs.String())
+	}
+	if !strings.Contains(logs.String(), "model=elsewhere") {
+		t.Fatalf("no warning for elsewhere:\n%s", logs.String())
+	}
+
+	err := client.validateModelAttempt("mock", "text-only", textModelCapabilities, []types.ModelCapability{types.CapabilityStream})
+	if err == nil || !strings.Contains(err.Error(), "stream") {
+		t.Fatalf("known model missing a capability = %v, want error", err)
+	}
+}
+
+func TestModelValidationPermissiveWarningsAreBounded(t *testing.T) {
+	client := validationTestClient(types.ProviderConfig{}, withTestModels(
+		&types.ModelInfo{ID: "known", Provider: "mock", Capabilities: []types.ModelCapability{types.CapabilityText}},
+	), WithModelValidationMode(Permissive), WithLogger(slog.New(slog.DiscardHandler)))
+
+	for i := range maxUnknownModelWarnings + 10 {
+		if err := client.validateModelAttempt("mock", fmt.Sprintf("custom-%d", i), textModelCapabilities, nil); err != nil {
+			t.Fatalf("unknown model = %v, want pass-through", err)
+		}
+	}
+	if got := len(client.unknownModels); got > maxUnknownModelWarnings {
+		t.Fatalf("remembered %d unknown models, want at most %d", got, maxUnknownModelWarnings)
+	}
+}
+
+func TestWithModelValidationSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		option  Option
+		enabled bool
+		mode    ModelValidationMode
+	}{
+		{"bool off", WithModelValidation(false), false, Strict},
+		{"bool on", WithModelValidation(true), true, Strict},
+		{"strict", WithModelValidationMode(Strict), true, Strict},
+		{"permissive", WithModelValidationMode(Permissive), true, Permissive},
+		{"off after mode", func(c *Config) {
+			WithModelValidationMode(Permissive)(c)
+			WithModelValidation(false)(c)
+		}, false, Permissive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{}
+			tt.option(&config)
+			if config.ModelValidation != tt.enabled || config.ModelValidationMode != tt.mode {
+				t.Fatalf("config = (%v, %v), want (%v, %v)", config.ModelValidation, config.ModelValidationMode, tt.enabled, tt.mode)
+			}
+		})
+	}
+}
+
 func TestModelValidationRejectsRegistryViolations(t *testing.T) {
-	useModelRegistry(t,
+	registry := withTestModels(
 		&types.ModelInfo{ID: "text", Capabilities: []types.ModelCapability{types.CapabilityText}},
 		&types.ModelInfo{ID: "chat", Capabilities: []types.ModelCapability{types.CapabilityChat}},
 		&types.ModelInfo{ID: "full", Capabilities: []types.ModelCapability{
@@ -110,7 +188,7 @@ func TestModelValidationRejectsRegistryViolations(t *testing.T) {
 		}},
 		&types.ModelInfo{ID: "old", Deprecated: true, Capabilities: []types.ModelCapability{types.CapabilityText}},
 	)
-	client := validationTestClient(types.ProviderConfig{})
+	client := validationTestClient(types.ProviderConfig{}, registry)
 
 	tests := []struct {
 		name     string
@@ -145,11 +223,12 @@ func TestModelValidationRejectsRegistryViolations(t *testing.T) {
 }
 
 func TestModelValidationRequiresRegisteredProvider(t *testing.T) {
-	useModelRegistry(t,
+	registry := withTestModels(
 		&types.ModelInfo{ID: "alpha-model", Provider: "alpha", Capabilities: []types.ModelCapability{types.CapabilityText}},
 		&types.ModelInfo{ID: "shared-model", Capabilities: []types.ModelCapability{types.CapabilityText}},
 	)
 	client := New(
+		registry,
 		WithDefaultProvider("alpha"),
 		WithCustomProvider("alpha", whtest.MockProviderFactory(whtest.NewMockProvider("alpha"))),
 		WithProviderConfig("alpha", types.ProviderConfig{}),
@@ -170,11 +249,12 @@ func TestModelValidationRequiresRegisteredProvider(t *testing.T) {
 }
 
 func TestModelValidationDuplicateIDUsesCurrentProviderRegistration(t *testing.T) {
-	useModelRegistry(t,
+	registry := withTestModels(
 		&types.ModelInfo{ID: "shared-id", Provider: "alpha", Capabilities: []types.ModelCapability{types.CapabilityText}},
 		&types.ModelInfo{ID: "shared-id", Provider: "beta", Capabilities: []types.ModelCapability{types.CapabilityText}},
 	)
 	client := New(
+		registry,
 		WithDefaultProvider("alpha"),
 		WithCustomProvider("alpha", whtest.MockProviderFactory(whtest.NewMockProvider("alpha"))),
 		WithProviderConfig("alpha", types.ProviderConfig{}),
@@ -192,10 +272,11 @@ func TestModelValidationDuplicateIDUsesCurrentProviderRegistration(t *testing.T)
 }
 
 func TestNonTextAndAgentValidationHappensBeforeProviderLease(t *testing.T) {
-	useModelRegistry(t, &types.ModelInfo{ID: "text-only", Capabilities: []types.ModelCapability{types.CapabilityText}})
+	registry := withTestModels(&types.ModelInfo{ID: "text-only", Capabilities: []types.ModelCapability{types.CapabilityText}})
 
 	var factoryCalls atomic.Int32
 	client := New(
+		registry,
 		WithDefaultProvider("mock"),
 		WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) {
 			factoryCalls.Add(1)
@@ -241,13 +322,84 @@ func TestTextRequiredCapabilities(t *testing.T) {
 	}
 }
 
+func TestTextRequiredFeatureCapabilities(t *testing.T) {
+	seed := 7
+	parallel := false
+	request := &types.TextRequest{
+		BaseRequest: types.BaseRequest{Seed: &seed, ParallelToolCalls: &parallel},
+		Messages: []types.Message{&types.UserMessage{
+			Content: "transcribe",
+			Media:   []types.Media{types.NewAudio([]byte("wav"), "wav")},
+		}},
+		Audio:    &types.AudioOutput{Voice: "alloy", Format: "wav"},
+		Logprobs: true,
+	}
+	got := textRequiredCapabilities(request, false, false)
+	want := []types.ModelCapability{
+		types.CapabilityAudioInput,
+		types.CapabilityAudioOutput,
+		types.CapabilityParallelToolCalls,
+		types.CapabilityLogprobs,
+		types.CapabilitySeed,
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("capabilities = %v, want %v", got, want)
+	}
+}
+
+func TestBuilderValidateChecksModelFeatures(t *testing.T) {
+	registry := withTestModels(
+		&types.ModelInfo{ID: "plain", Capabilities: []types.ModelCapability{types.CapabilityText, types.CapabilityStructured}},
+		&types.ModelInfo{ID: "partial", Capabilities: []types.ModelCapability{types.CapabilityText, types.CapabilityStructured, types.CapabilityParallelToolCalls}},
+		&types.ModelInfo{ID: "rich", Capabilities: []types.ModelCapability{
+			types.CapabilityText,
+			types.CapabilityStructured,
+			types.CapabilityJSONSchemaStrict,
+			types.CapabilityLogprobs,
+			types.CapabilitySeed,
+		}},
+	)
+	client := validationTestClient(types.ProviderConfig{}, registry)
+
+	// A model listing no features has unknown, so allowed, feature support.
+	if err := client.Text().Model("plain").Seed(1).Logprobs(true).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	schema := map[string]any{"type": "object"}
+	if err := client.Structured().Model("plain").Schema(schema).Mode(types.StructuredModeStrict).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := client.Text().Model("partial").Seed(1).Validate()
+	var wormholeErr *types.WormholeError
+	if !errors.As(err, &wormholeErr) || wormholeErr.Code != types.ErrorCodeModel || !strings.Contains(err.Error(), "seed") {
+		t.Fatalf("seed error = %v", err)
+	}
+	err = client.Text().Model("partial").Logprobs(true).Validate()
+	if err == nil || !strings.Contains(err.Error(), "logprobs") {
+		t.Fatalf("logprobs error = %v", err)
+	}
+	if err := client.Text().Model("rich").Seed(1).Logprobs(true).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.Structured().Model("partial").Schema(schema).Mode(types.StructuredModeStrict).Validate()
+	if err == nil || !strings.Contains(err.Error(), "json_schema_strict") {
+		t.Fatalf("strict error = %v", err)
+	}
+	if err := client.Structured().Model("rich").Schema(schema).Mode(types.StructuredModeStrict).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestTextModelValidationAdvancesAcrossFallbacks(t *testing.T) {
-	useModelRegistry(t, &types.ModelInfo{
+	registry := withTestModels(&types.ModelInfo{
 		ID:           "valid",
 		Capabilities: []types.ModelCapability{types.CapabilityText, types.CapabilityStream},
 	})
 	provider := newValidationRecordingProvider("mock")
 	client := New(
+		registry,
 		WithDefaultProvider("mock"),
 		WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
 		WithProviderConfig("mock", types.ProviderConfig{}),
@@ -279,10 +431,11 @@ func TestTextModelValidationAdvancesAcrossFallbacks(t *testing.T) {
 }
 
 func TestTextModelValidationAdvancesToProviderFallback(t *testing.T) {
-	useModelRegistry(t, &types.ModelInfo{ID: "secondary-model", Capabilities: []types.ModelCapability{types.CapabilityChat}})
+	registry := withTestModels(&types.ModelInfo{ID: "secondary-model", Capabilities: []types.ModelCapability{types.CapabilityChat}})
 	primary := newValidationRecordingProvider("primary")
 	secondary := newValidationRecordingProvider("secondary")
 	client := New(
+		registry,
 		WithDefaultProvider("primary"),
 		WithCustomProvider("primary", func(types.ProviderConfig) (types.Provider, error) { return primary, nil }),
 		WithProviderConfig("primary", types.ProviderConfig{}),
@@ -309,10 +462,11 @@ func TestTextModelValidationAdvancesToProviderFallback(t *testing.T) {
 }
 
 func TestTextModelValidationFeatureModifiersPreventInvocation(t *testing.T) {
-	useModelRegistry(t, &types.ModelInfo{ID: "text-only", Capabilities: []types.ModelCapability{types.CapabilityText}})
+	registry := withTestModels(&types.ModelInfo{ID: "text-only", Capabilities: []types.ModelCapability{types.CapabilityText}})
 	provider := newValidationRecordingProvider("mock")
 	var factoryCalls atomic.Int32
 	client := New(
+		registry,
 		WithDefaultProvider("mock"),
 		WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) {
 			factoryCalls.Add(1)
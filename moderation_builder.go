@@ -0,0 +1,215 @@
+package wormhole
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ModerationRequestBuilder builds moderation requests. Requests go to the
+// provider's moderation endpoint, or to a local types.Moderator set with
+// Moderator. Moderation calls do not pass through provider middleware.
+//
+// Example:
+//
+//	resp, err := client.Moderate().Input("some user text").Generate(ctx)
+//	if resp.Flagged() {
+//	    log.Println(resp.Results[0].Exceeds(0.5))
+//	}
+type ModerationRequestBuilder struct {
+	CommonBuilder
+	request   *types.ModerationRequest
+	moderator types.Moderator
+}
+
+// Moderate creates a new moderation request builder.
+func (p *Wormhole) Moderate() *ModerationRequestBuilder {
+	return &ModerationRequestBuilder{
+		CommonBuilder: newCommonBuilder(p),
+		request:       &types.ModerationRequest{},
+	}
+}
+
+// Using sets the provider to use.
+func (b *ModerationRequestBuilder) Using(provider string) *ModerationRequestBuilder {
+	b.setProvider(provider)
+	return b
+}
+
+// BaseURL sets a custom base URL for OpenAI-compatible APIs.
+func (b *ModerationRequestBuilder) BaseURL(url string) *ModerationRequestBuilder {
+	b.setBaseURL(url)
+	return b
+}
+
+// Model sets the moderation model. Empty uses the provider's default.
+func (b *ModerationRequestBuilder) Model(model string) *ModerationRequestBuilder {
+	b.request.Model = model
+	return b
+}
+
+// Input sets the texts to classify; each gets its own result.
+func (b *ModerationRequestBuilder) Input(texts ...string) *ModerationRequestBuilder {
+	b.request.Input = texts
+	return b
+}
+
+// Moderator classifies locally with moderator, such as a KeywordModerator,
+// instead of calling a provider.
+func (b *ModerationRequestBuilder) Moderator(moderator types.Moderator) *ModerationRequestBuilder {
+	b.moderator = moderator
+	return b
+}
+
+// ProviderOptions sets provider-specific options.
+func (b *ModerationRequestBuilder) ProviderOptions(options map[string]any) *ModerationRequestBuilder {
+	b.request.ProviderOptions = options
+	return b
+}
+
+// ProviderOption sets one provider-specific option sent only to provider, as
+// TextRequestBuilder.ProviderOption does. A local Moderator never sees it.
+func (b *ModerationRequestBuilder) ProviderOption(provider, key string, value any) *ModerationRequestBuilder {
+	b.setProviderOption(provider, key, value)
+	return b
+}
+
+// Metadata tags the request for metrics and logging, as
+// TextRequestBuilder.Metadata does.
+func (b *ModerationRequestBuilder) Metadata(tags map[string]string) *ModerationRequestBuilder {
+	b.addTags(tags)
+	return b
+}
+
+// Priority sets the request's scheduling priority, as
+// TextRequestBuilder.Priority does.
+func (b *ModerationRequestBuilder) Priority(p Priority) *ModerationRequestBuilder {
+	b.setPriority(p)
+	return b
+}
+
+// Validate checks the request configuration for errors before calling Generate().
+func (b *ModerationRequestBuilder) Validate() error {
+	var errs types.ValidationErrors
+	if len(b.request.Input) == 0 {
+		errs.Add("input", "required", nil, "at least one input must be provided")
+	}
+	return errs.Error()
+}
+
+// Generate classifies the inputs and returns one result per input.
+func (b *ModerationRequestBuilder) Generate(ctx context.Context) (*types.ModerationResponse, error) {
+	ctx = b.taggedContext(ctx)
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+
+	request := b.request
+	return executeTrackedRequest(ctx, b.getWormhole(), b.idempotencyScope("moderation.generate"), request, func(ctx context.Context) (*types.ModerationResponse, error) {
+		if b.moderator != nil {
+			return b.moderator.Moderate(ctx, *request)
+		}
+		provider, release, err := b.getProviderWithBaseURL()
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		moderator, ok := provider.(types.Moderator)
+		if !ok {
+			return nil, types.NewWormholeError(types.ErrorCodeProvider, fmt.Sprintf("%s provider does not support moderation", provider.Name()), false)
+		}
+		scoped := *request
+		scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
+		return moderator.Moderate(contextWithProviderOperation(ctx, provider, "moderation"), scoped)
+	})
+}
+
+// KeywordModerator is a local types.Moderator that flags an input when it
+// contains any of a category's keywords as whole words, ignoring case.
+// Matching categories score 1 and all others 0, so it suits blocklists that
+// must work offline or in front of a provider moderation call.
+//
+// Example:
+//
+//	local := wormhole.KeywordModerator{
+//	    types.ModerationViolence: {"kill", "shoot up"},
+//	}
+//	resp, err := client.Moderate().Moderator(local).Input(text).Generate(ctx)
+type KeywordModerator map[types.ModerationCategory][]string
+
+// Moderate implements types.Moderator.
+func (m KeywordModerator) Moderate(_ context.Context, request types.ModerationRequest) (*types.ModerationResponse, error) {
+	results := make([]types.ModerationResult, len(request.Input))
+	for i, input := range request.Input {
+		text := " " + strings.Join(keywordWords(input), " ") + " "
+		result := types.ModerationResult{
+			Categories: make(map[types.ModerationCategory]bool, len(m)),
+			Scores:     make(map[types.ModerationCategory]float64, len(m)),
+		}
+		for category, keywords := range m {
+			result.Scores[category] = 0
+			for _, keyword := range keywords {
+				words := keywordWords(keyword)
+				if len(words) > 0 && strings.Contains(text, " "+strings.Join(words, " ")+" ") {
+					result.Categories[category] = true
+					result.Scores[category] = 1
+					result.Flagged = true
+					break
+				}
+			}
+		}
+		results[i] = result
+	}
+	return &types.ModerationResponse{Provider: "keyword", Model: "keyword", Results: results, Created: time.Now()}, nil
+}
+
+func keywordWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// ModerationConfig configures the prompt moderation enabled by WithModeration.
+type ModerationConfig struct {
+	// Provider and Model select the moderation endpoint. Empty Provider uses
+	// the default provider; empty Model uses the provider's default model.
+	Provider string
+	Model    string
+	// Moderator, when set, classifies locally instead of calling a provider.
+	Moderator types.Moderator
+	// Threshold blocks a category scoring at least this much. Zero blocks
+	// what the moderator itself flags.
+	Threshold float64
+	// Categories limits blocking to these categories; empty blocks any.
+	Categories []types.ModerationCategory
+}
+
+// clientModerator sends guardrail moderation calls through the client.
+type clientModerator struct {
+	client *Wormhole
+	config ModerationConfig
+}
+
+func (m clientModerator) Moderate(ctx context.Context, request types.ModerationRequest) (*types.ModerationResponse, error) {
+	builder := m.client.Moderate().Model(m.config.Model).Input(request.Input...)
+	if m.config.Provider != "" {
+		builder.Using(m.config.Provider)
+	}
+	return builder.Generate(ctx)
+}
+
+func newModerationMiddleware(client *Wormhole, config ModerationConfig) types.ProviderMiddleware {
+	moderator := config.Moderator
+	if moderator == nil {
+		moderator = clientModerator{client: client, config: config}
+	}
+	return middleware.NewLegacyAdapter(middleware.GuardrailsMiddleware(middleware.GuardrailsConfig{
+		Input: []middleware.Guardrail{middleware.BlockModerated(moderator, config.Threshold, config.Categories...)},
+	}))
+}
@@ -0,0 +1,90 @@
+package wormhole_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestModerationBuilderOpenAI(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/moderations", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "omni-moderation-latest", body["model"])
+		assert.Equal(t, []any{"fine", "bad"}, body["input"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"modr-1","model":"omni-moderation-2024-09-26","results":[
+			{"flagged":false,"categories":{"hate":false},"category_scores":{"hate":0.01,"self-harm/intent":0.02}},
+			{"flagged":true,"categories":{"violence":false,"violence/graphic":true,"sexual/minors":false},"category_scores":{"violence":0.4,"violence/graphic":0.9,"sexual/minors":0.05}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := wormhole.New(wormhole.WithOpenAI("test-key", types.ProviderConfig{BaseURL: server.URL}), wormhole.WithDiscovery(false))
+	resp, err := client.Moderate().Using("openai").Input("fine", "bad").Generate(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "openai", resp.Provider)
+	assert.Equal(t, "omni-moderation-2024-09-26", resp.Model)
+	require.Len(t, resp.Results, 2)
+	assert.True(t, resp.Flagged())
+	assert.InDelta(t, 0.02, resp.Results[0].Scores[types.ModerationSelfHarm], 1e-9)
+	assert.InDelta(t, 0.9, resp.Results[1].Scores[types.ModerationViolence], 1e-9, "subcategory scores fold into their parent")
+	assert.True(t, resp.Results[1].Categories[types.ModerationViolence])
+	assert.InDelta(t, 0.05, resp.Results[1].Scores[types.ModerationSexualMinors], 1e-9)
+	assert.InDelta(t, 0.9, resp.Results[1].Raw["violence/graphic"], 1e-9)
+}
+
+func TestModerationBuilderKeywordModerator(t *testing.T) {
+	t.Parallel()
+	client := wormhole.New()
+	local := wormhole.KeywordModerator{types.ModerationViolence: {"shoot up"}}
+
+	resp, err := client.Moderate().Moderator(local).Input("they will SHOOT UP the place", "photo shoot upstairs").Generate(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	assert.True(t, resp.Results[0].Flagged)
+	assert.Equal(t, []types.ModerationCategory{types.ModerationViolence}, resp.Results[0].Exceeds(0.5))
+	assert.False(t, resp.Results[1].Flagged, "keywords match whole words only")
+
+	assert.Error(t, client.Moderate().Validate())
+}
+
+func TestWithModerationBlocksPrompts(t *testing.T) {
+	t.Parallel()
+	provider := mocktesting.NewMockProvider("mock").WithTextResponse(types.TextResponse{Text: "ok"})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+		wormhole.WithModeration(wormhole.ModerationConfig{
+			Moderator: wormhole.KeywordModerator{types.ModerationIllicit: {"counterfeit"}},
+		}),
+	)
+	defer client.Close()
+
+	_, err := client.Text().Model("m").Prompt("How do I print counterfeit bills?").Generate(context.Background())
+	require.Error(t, err)
+	assert.True(t, types.IsContentBlockedError(err))
+
+	resp, err := client.Text().Model("m").Prompt("How do I print photos?").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Content())
+
+	_, err = client.Moderate().Input("x").Generate(context.Background())
+	require.Error(t, err, "the mock provider has no moderation endpoint")
+}
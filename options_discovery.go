@@ -1,6 +1,8 @@
 package wormhole
 
 import (
+	"os"
+
 	"github.com/garyblankenship/wormhole/v2/discovery"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
@@ -58,10 +60,12 @@ func WithDiscovery(enabled bool) Option {
 // the built-in provider profile registry.
 //
 // Supported provider names:
-//   - "openai" -> OPENAI_API_KEY, OPENAI_BASE_URL
+//   - "openai" -> OPENAI_API_KEY, OPENAI_BASE_URL, OPENAI_ORG_ID, OPENAI_PROJECT_ID
 //   - "anthropic" -> ANTHROPIC_API_KEY, ANTHROPIC_BASE_URL
 //   - "gemini" -> GEMINI_API_KEY, GEMINI_BASE_URL
 //   - "groq" -> GROQ_API_KEY
+//   - "xai" -> XAI_API_KEY
+//   - "huggingface" -> HF_TOKEN or HUGGINGFACE_API_KEY, HUGGINGFACE_BASE_URL
 //   - "openrouter" -> OPENROUTER_API_KEY
 //
 // Example:
@@ -95,25 +99,43 @@ func WithProviderFromEnv(provider string) Option {
 			BaseURL: configuredBaseURL(profile),
 		}
 
-		switch provider {
-		case "openai":
-			WithOpenAI(apiKey, cfg)(c)
-		case "anthropic":
-			WithAnthropic(apiKey, cfg)(c)
-		case "gemini":
-			WithGemini(apiKey, cfg)(c)
-		case "groq":
-			WithGroq(apiKey, cfg)(c)
-		case "mistral":
-			WithMistral(cfg)(c)
-		case "ollama":
-			WithOllama(cfg)(c)
-		case "openrouter":
-			WithProfiledOpenAICompatible("openrouter", cfg)(c)
-		default:
-			if profile.Kind == providerKindOpenAICompatible && cfg.BaseURL != "" {
-				WithProfiledOpenAICompatible(provider, cfg)(c)
-			}
+		if provider == "openai" {
+			cfg.Organization = os.Getenv("OPENAI_ORG_ID")
+			cfg.Project = os.Getenv("OPENAI_PROJECT_ID")
+		}
+		configureProvider(c, provider, profile, cfg)
+	}
+}
+
+// configureProvider registers cfg under a known profile's name using the
+// provider's dedicated option.
+func configureProvider(c *Config, provider string, profile ProviderProfile, cfg types.ProviderConfig) {
+	switch provider {
+	case "openai":
+		WithOpenAI(cfg.APIKey, cfg)(c)
+	case "anthropic":
+		WithAnthropic(cfg.APIKey, cfg)(c)
+	case "gemini":
+		WithGemini(cfg.APIKey, cfg)(c)
+	case "groq":
+		WithGroq(cfg.APIKey, cfg)(c)
+	case "xai":
+		WithXAI(cfg.APIKey, cfg)(c)
+	case "deepseek":
+		WithDeepSeek(cfg.APIKey, cfg)(c)
+	case "mistral":
+		WithMistral(cfg)(c)
+	case "huggingface":
+		WithHuggingFace(cfg.APIKey, cfg)(c)
+	case "ollama":
+		WithOllama(cfg)(c)
+	case "lmstudio":
+		WithLMStudio(cfg)(c)
+	case "openrouter":
+		WithProfiledOpenAICompatible("openrouter", cfg)(c)
+	default:
+		if profile.Kind == providerKindOpenAICompatible && cfg.BaseURL != "" {
+			WithProfiledOpenAICompatible(provider, cfg)(c)
 		}
 	}
 }
@@ -33,10 +33,12 @@ func WithIdempotencyKey(key string, ttl ...time.Duration) Option {
 
 // WithModels populates the opt-in model registry with the given models.
 //
-// The global model registry (types.DefaultModelRegistry) starts empty. When
-// model validation is enabled (the default), validation helpers have nothing
-// to check against until the registry is populated. WithModels loads the
-// provided models into the registry at New() time, making the opt-in explicit.
+// The client's registry (WithModelRegistry, or the global
+// types.DefaultModelRegistry without it) starts empty. When model validation
+// is enabled (the default), validation helpers have nothing to check against
+// until the registry is populated. WithModels loads the provided models into
+// the registry at New() time, making the opt-in explicit. Registries that do
+// not implement types.ModelRegistrar ignore them.
 //
 // Example:
 //
@@ -51,3 +53,23 @@ func WithModels(models ...*types.ModelInfo) Option {
 		c.Models = append(c.Models, models...)
 	}
 }
+
+// WithModelRegistry gives the client its own model registry for validation,
+// context sizing, and scorecard pricing instead of the global
+// types.DefaultModelRegistry. Clients sharing a registry see each other's
+// WithModels entries; clients with separate registries do not.
+//
+// Example:
+//
+//	registry := types.NewReadThroughRegistry(types.ReadThroughConfig{TTL: time.Hour},
+//	    fetchers.NewOpenAIFetcher(apiKey),
+//	)
+//	client := wormhole.New(
+//	    wormhole.WithOpenAI(apiKey),
+//	    wormhole.WithModelRegistry(registry),
+//	)
+func WithModelRegistry(registry types.ModelCatalog) Option {
+	return func(c *Config) {
+		c.ModelRegistry = registry
+	}
+}
@@ -89,11 +89,85 @@ func WithGroq(apiKey string, config ...types.ProviderConfig) Option {
 	return WithProfiledOpenAICompatible("groq", cfg)
 }
 
+// WithDeepSeek configures DeepSeek as an OpenAI-compatible endpoint. Reasoning
+// from reasoning_content is returned in TextResponse.Thinking, and reasoning
+// tokens in Usage.ReasoningTokens.
+func WithDeepSeek(apiKey string, config ...types.ProviderConfig) Option {
+	var cfg types.ProviderConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg.APIKey = apiKey
+
+	return WithProfiledOpenAICompatible("deepseek", cfg)
+}
+
+// WithXAI configures xAI (Grok) as an OpenAI-compatible endpoint. Live Search
+// and deferred completions are set per request with types.XAIOptions.
+func WithXAI(apiKey string, config ...types.ProviderConfig) Option {
+	var cfg types.ProviderConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg.APIKey = apiKey
+
+	return WithProfiledOpenAICompatible("xai", cfg)
+}
+
 // WithMistral configures the Mistral provider as an OpenAI-compatible endpoint.
 func WithMistral(config types.ProviderConfig) Option {
 	return WithProfiledOpenAICompatible("mistral", config)
 }
 
+// WithCohere configures Cohere for reranking (POST /v2/rerank).
+func WithCohere(apiKey string, config ...types.ProviderConfig) Option {
+	var cfg types.ProviderConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg.APIKey = apiKey
+
+	return WithProfiledOpenAICompatible("cohere", cfg)
+}
+
+// WithJina configures Jina AI for reranking.
+func WithJina(apiKey string, config ...types.ProviderConfig) Option {
+	var cfg types.ProviderConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg.APIKey = apiKey
+
+	return WithProfiledOpenAICompatible("jina", cfg)
+}
+
+// WithVoyage configures Voyage AI for reranking.
+func WithVoyage(apiKey string, config ...types.ProviderConfig) Option {
+	var cfg types.ProviderConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg.APIKey = apiKey
+
+	return WithProfiledOpenAICompatible("voyage", cfg)
+}
+
+// WithHuggingFace configures the Hugging Face provider for the serverless
+// Inference API's text-generation and feature-extraction tasks. Model names
+// are Hub IDs such as "mistralai/Mistral-7B-Instruct-v0.3". For a dedicated
+// Inference Endpoint set config.BaseURL to the endpoint URL; requests are then
+// sent there regardless of model.
+func WithHuggingFace(apiKey string, config ...types.ProviderConfig) Option {
+	return func(c *Config) {
+		var cfg types.ProviderConfig
+		if len(config) > 0 {
+			cfg = config[0]
+		}
+		cfg.DynamicModels = true // any Hub model ID
+		registerProvider(c, "huggingface", apiKey, cfg)
+	}
+}
+
 // WithOllama configures the Ollama provider.
 func WithOllama(config types.ProviderConfig) Option {
 	return func(c *Config) {
@@ -104,9 +178,14 @@ func WithOllama(config types.ProviderConfig) Option {
 	}
 }
 
-// WithLMStudio configures the LMStudio provider.
+// WithLMStudio configures the LMStudio provider. Generation uses LM Studio's
+// OpenAI-compatible API; the concrete *lmstudio.Provider also loads and
+// unloads models, and model discovery reports which models are loaded.
 func WithLMStudio(config types.ProviderConfig) Option {
-	return WithProfiledOpenAICompatible("lmstudio", config)
+	return func(c *Config) {
+		WithProfiledOpenAICompatible(providerLMStudio, config)(c)
+		c.CustomFactories[providerLMStudio] = lmStudioFactory()
+	}
 }
 
 // WithVLLM configures the vLLM provider.
@@ -185,6 +264,18 @@ func applyProviderProfile(profile ProviderProfile, config *types.ProviderConfig)
 	if config.RequestPolicy.MaxTokensCap == 0 {
 		config.RequestPolicy.MaxTokensCap = profile.RequestPolicy.MaxTokensCap
 	}
+	if config.RequestPolicy.RerankTopNParam == "" {
+		config.RequestPolicy.RerankTopNParam = profile.RequestPolicy.RerankTopNParam
+	}
+	if config.RequestPolicy.SamplingParams == nil && profile.RequestPolicy.SamplingParams != nil {
+		config.RequestPolicy.SamplingParams = make([]types.SamplingParam, 0, len(profile.RequestPolicy.SamplingParams))
+		for _, param := range profile.RequestPolicy.SamplingParams {
+			config.RequestPolicy.SamplingParams = append(config.RequestPolicy.SamplingParams, types.SamplingParam(param))
+		}
+	}
+	if !config.RequestPolicy.ThinkTags {
+		config.RequestPolicy.ThinkTags = profile.RequestPolicy.ThinkTags
+	}
 	if config.ImagePath == "" {
 		config.ImagePath = profile.ImagePath
 	}
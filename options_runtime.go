@@ -55,6 +55,7 @@ func WithMiddleware(mw ...middleware.Middleware) Option {
 
 // WithProviderMiddleware adds type-safe middleware to the client's execution chain.
 // Use this for compile-time type checking instead of the deprecated WithMiddleware.
+// middleware.Hooks builds one from just the operations you need.
 func WithProviderMiddleware(mw ...types.ProviderMiddleware) Option {
 	return func(c *Config) {
 		c.ProviderMiddlewares = append(c.ProviderMiddlewares, mw...)
@@ -90,6 +91,25 @@ func WithRetries(maxRetries int, delay time.Duration) Option {
 	}
 }
 
+// WithUserAgent sets the User-Agent sent to providers that do not configure
+// ProviderConfig.UserAgent. By default Wormhole identifies itself as
+// "wormhole/<version> (<go version>; <os>/<arch>)".
+func WithUserAgent(userAgent string) Option {
+	return func(c *Config) {
+		c.UserAgent = userAgent
+		c.DisableUserAgent = false
+	}
+}
+
+// WithoutUserAgent sends no User-Agent header at all, including Go's default.
+// Providers that set ProviderConfig.UserAgent still send theirs.
+func WithoutUserAgent() Option {
+	return func(c *Config) {
+		c.UserAgent = ""
+		c.DisableUserAgent = true
+	}
+}
+
 // WithDebugLogging enables debug logging with an optional custom logger.
 func WithDebugLogging(logger ...types.Logger) Option {
 	return func(c *Config) {
@@ -131,16 +151,238 @@ func WithStreamTrace(trace StreamTraceFunc) Option {
 	}
 }
 
-// WithModelValidation enables or disables model validation against the opt-in
-// global model registry. Validation runs only when enabled, the registry is
-// nonempty, and the selected provider is not configured with DynamicModels.
-// Each request must name a known, non-deprecated model supporting its operation.
-// Text and agents accept text or chat; streams additionally require stream,
-// tool-enabled requests require functions, and media-bearing text requires
-// vision. Structured, embeddings, images, audio, and rerank requests require
-// their matching capability. Fallback attempts are validated independently.
+// WithModelValidation enables or disables model validation against the
+// client's model registry. Enabled validation is Strict unless
+// WithModelValidationMode picks another mode.
+//
+// Validation runs only when enabled, the registry is nonempty, and the
+// selected provider is not configured with DynamicModels. Each request must
+// name a known, non-deprecated model supporting its operation. Text and agents
+// accept text or chat; streams additionally require stream, tool-enabled
+// requests require functions, and media-bearing text requires vision.
+// Structured, embeddings, images, audio, and rerank requests require their
+// matching capability. Fallback attempts are validated independently.
 func WithModelValidation(enabled bool) Option {
 	return func(c *Config) {
 		c.ModelValidation = enabled
 	}
 }
+
+// WithModelValidationMode enables model validation in mode:
+//
+//	wormhole.WithModelValidationMode(wormhole.Permissive) // unknown models warn
+func WithModelValidationMode(mode ModelValidationMode) Option {
+	return func(c *Config) {
+		c.ModelValidation = true
+		c.ModelValidationMode = mode
+	}
+}
+
+// WithoutParamConstraints turns off automatic model parameter constraints.
+// By default each request has its model's types.ParamConstraints applied
+// before the provider call. Those are the registry entry's, or the built-in
+// rules that drop sampling parameters GPT-5 and o-series reasoning models
+// reject. Changed parameters are listed in the response's
+// Metadata["adjusted_params"] and logged once at warn level. With this option
+// requests are sent as written and provider errors surface as is. To turn the
+// rules off for one model only, register it with an empty ParamConstraints.
+func WithoutParamConstraints() Option {
+	return func(c *Config) {
+		c.DisableParamConstraints = true
+	}
+}
+
+// WithModelEquivalents adds model equivalence classes consulted before the
+// built-in table when a provider fallback route omits its model (see
+// TextRequestBuilder.WithFallbackProviders). Later options take precedence
+// over earlier ones.
+//
+// Example:
+//
+//	client := wormhole.New(
+//	    wormhole.WithModelEquivalents(wormhole.ModelEquivalenceClass{
+//	        Name: "house-default",
+//	        Models: map[string][]string{
+//	            "openai":    {"gpt-5-mini"},
+//	            "anthropic": {"claude-haiku-4-5"},
+//	        },
+//	    }),
+//	)
+func WithModelEquivalents(classes ...ModelEquivalenceClass) Option {
+	return func(c *Config) {
+		c.ModelEquivalents = append(cloneModelEquivalenceClasses(classes), c.ModelEquivalents...)
+	}
+}
+
+// WithModelAliases maps application-level model names to provider model names,
+// e.g. "fast" to "gpt-5-nano". Text, structured, and embeddings requests
+// resolve aliases when they execute, so a deployment can retarget a model
+// without a code change. Later calls add to and override earlier aliases.
+func WithModelAliases(aliases map[string]string) Option {
+	return func(c *Config) {
+		if c.ModelAliases == nil {
+			c.ModelAliases = make(map[string]string, len(aliases))
+		}
+		for alias, model := range aliases {
+			c.ModelAliases[alias] = model
+		}
+	}
+}
+
+// WithDefaultModel sets the model text, structured, and embeddings requests
+// use on provider when they do not call Model. The model may be an alias.
+func WithDefaultModel(provider, model string) Option {
+	return func(c *Config) {
+		if c.DefaultModels == nil {
+			c.DefaultModels = make(map[string]string)
+		}
+		c.DefaultModels[provider] = model
+	}
+}
+
+// WithPromptRegistry uses registry for UsePrompt instead of a per-client
+// registry, so several clients share one set of prompts.
+func WithPromptRegistry(registry *PromptRegistry) Option {
+	return func(c *Config) {
+		c.Prompts = registry
+	}
+}
+
+// WithExperimentRegistry uses registry for Experiment instead of a per-client
+// registry, so several clients split traffic and collect stats together.
+func WithExperimentRegistry(registry *ExperimentRegistry) Option {
+	return func(c *Config) {
+		c.Experiments = registry
+	}
+}
+
+// WithWarmPool keeps config.Routes warm with periodic one-token requests and
+// tracks per-route cold-start latency (see Wormhole.WarmPool). Builders that
+// call PreferWarm route around cold primaries.
+//
+// Example:
+//
+//	client := wormhole.New(
+//	    wormhole.WithProfiledOpenAICompatible("openrouter", types.ProviderConfig{APIKey: key}),
+//	    wormhole.WithWarmPool(wormhole.WarmPoolConfig{
+//	        Routes:      []wormhole.TextRoute{{Provider: "openrouter", Model: "meta-llama/llama-3.3-70b-instruct:free"}},
+//	        Interval:    2 * time.Minute,
+//	        TokenBudget: 2000,
+//	    }),
+//	)
+func WithWarmPool(config WarmPoolConfig) Option {
+	return func(c *Config) {
+		c.WarmPool = &config
+	}
+}
+
+// WithJobQueue runs requests submitted with Submit on background workers,
+// retrying transient failures and keeping each job's state in config.Store
+// (see Wormhole.Jobs).
+//
+// Example:
+//
+//	client := wormhole.New(
+//	    wormhole.WithOpenAI(apiKey),
+//	    wormhole.WithJobQueue(wormhole.JobQueueConfig{Workers: 8, Timeout: 10 * time.Minute}),
+//	)
+func WithJobQueue(config JobQueueConfig) Option {
+	return func(c *Config) {
+		c.JobQueue = &config
+	}
+}
+
+// WithScorecard tracks per-provider success rate, latency percentiles, cost
+// per 1K tokens and error mix over a rolling window (see Wormhole.Scorecard).
+//
+// Example:
+//
+//	client := wormhole.New(
+//	    wormhole.WithOpenAI(openaiKey),
+//	    wormhole.WithAnthropic(anthropicKey),
+//	    wormhole.WithScorecard(wormhole.ScorecardConfig{Window: 10 * time.Minute}),
+//	)
+//	for _, score := range client.Scorecard().Scores() {
+//	    fmt.Printf("%s: %.1f%% ok, p95 %s\n", score.Provider, 100*score.SuccessRate(), score.P95Latency)
+//	}
+func WithScorecard(config ScorecardConfig) Option {
+	return func(c *Config) {
+		c.Scorecard = &config
+	}
+}
+
+// WithContextManager keeps long conversations inside each model's context
+// window. Before a request, messages that would push the prompt past the
+// model's context length (from WithModels or discovery) are summarized with
+// config.Summarizer or, without one, dropped oldest first (see
+// Wormhole.ContextManager).
+//
+// Example:
+//
+//	client := wormhole.New(
+//	    wormhole.WithOpenAI(apiKey),
+//	    wormhole.WithContextManager(wormhole.ContextManagerConfig{
+//	        Summarizer: wormhole.TextRoute{Model: "gpt-5-nano"},
+//	        KeepRecent: 6,
+//	    }),
+//	)
+func WithContextManager(config ContextManagerConfig) Option {
+	return func(c *Config) {
+		c.ContextManager = &config
+	}
+}
+
+// WithModeration checks every text, stream and structured prompt with a
+// moderation model before it is sent; a prompt that trips config's threshold
+// fails with a types.ErrorCodeContentBlocked error and never reaches the
+// provider.
+//
+// Example:
+//
+//	client := wormhole.New(
+//	    wormhole.WithOpenAI(apiKey),
+//	    wormhole.WithModeration(wormhole.ModerationConfig{Provider: "openai", Threshold: 0.8}),
+//	)
+func WithModeration(config ModerationConfig) Option {
+	return func(c *Config) {
+		c.Moderation = &config
+	}
+}
+
+// WithCircuitBreaker gives every provider and operation its own circuit
+// breaker, so one failing backend fails fast without blocking fallbacks.
+// Wormhole.ProviderState reports which providers are tripped, and
+// config.OnStateChange observes every transition.
+//
+// Example:
+//
+//	client := wormhole.New(
+//	    wormhole.WithOpenAI(openaiKey),
+//	    wormhole.WithAnthropic(anthropicKey),
+//	    wormhole.WithCircuitBreaker(middleware.CircuitBreakerConfig{
+//	        FailureThreshold: 5,
+//	        Timeout:          30 * time.Second,
+//	        OnStateChange: func(c middleware.CircuitStateChange) {
+//	            alerts.Send(c.Provider + " circuit " + c.To.String())
+//	        },
+//	    }),
+//	)
+//	if client.ProviderState("openai") == middleware.StateOpen {
+//	    // route elsewhere
+//	}
+func WithCircuitBreaker(config middleware.CircuitBreakerConfig) Option {
+	return func(c *Config) {
+		c.CircuitBreaker = &config
+	}
+}
+
+// WithResponseValidator registers a named validator that ResponseValidation
+// policies can reference, so one definition serves every builder.
+func WithResponseValidator(name string, validator ResponseValidator) Option {
+	return func(c *Config) {
+		if c.ResponseValidators == nil {
+			c.ResponseValidators = make(map[string]ResponseValidator)
+		}
+		c.ResponseValidators[name] = validator
+	}
+}
@@ -0,0 +1,91 @@
+package wormhole
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// paramConstraintsMiddleware applies each model's types.ParamConstraints to
+// text, stream and structured requests. It runs per attempt, so every model
+// in a fallback chain gets its own rules.
+//
+// Text and structured responses list the parameters it changed under
+// Metadata["adjusted_params"]. Each distinct change is also logged once per
+// client at warn level, which is the only report for streams.
+type paramConstraintsMiddleware struct {
+	client *Wormhole
+	warned sync.Map // "model\x00params" keys already logged
+}
+
+// apply runs changed with the rules for model and returns the parameters they
+// changed, logging them the first time the change is seen.
+func (m *paramConstraintsMiddleware) apply(ctx context.Context, model string, changed func(*types.ParamConstraints) []string) []string {
+	adjusted := changed(types.ModelParamConstraints(m.client.modelRegistry, model))
+	if len(adjusted) == 0 || m.client.config.Logger == nil {
+		return adjusted
+	}
+	if _, seen := m.warned.LoadOrStore(model+"\x00"+strings.Join(adjusted, ","), true); !seen {
+		m.client.config.Logger.WarnContext(ctx, "model parameter constraints changed the request",
+			"provider", contextProvider(ctx), "model", model, "params", adjusted)
+	}
+	return adjusted
+}
+
+// reportAdjusted records adjusted in metadata, allocating the map if needed.
+func reportAdjusted(metadata *map[string]any, adjusted []string) {
+	if len(adjusted) == 0 {
+		return
+	}
+	if *metadata == nil {
+		*metadata = make(map[string]any, 1)
+	}
+	(*metadata)["adjusted_params"] = adjusted
+}
+
+func (m *paramConstraintsMiddleware) ApplyText(next types.TextHandler) types.TextHandler {
+	return func(ctx context.Context, request types.TextRequest) (*types.TextResponse, error) {
+		adjusted := m.apply(ctx, request.Model, func(c *types.ParamConstraints) []string { return c.ApplyText(&request) })
+		resp, err := next(ctx, request)
+		if resp != nil {
+			reportAdjusted(&resp.Metadata, adjusted)
+		}
+		return resp, err
+	}
+}
+
+func (m *paramConstraintsMiddleware) ApplyStream(next types.StreamHandler) types.StreamHandler {
+	return func(ctx context.Context, request types.TextRequest) (<-chan types.TextChunk, error) {
+		m.apply(ctx, request.Model, func(c *types.ParamConstraints) []string { return c.ApplyText(&request) })
+		return next(ctx, request)
+	}
+}
+
+func (m *paramConstraintsMiddleware) ApplyStructured(next types.StructuredHandler) types.StructuredHandler {
+	return func(ctx context.Context, request types.StructuredRequest) (*types.StructuredResponse, error) {
+		adjusted := m.apply(ctx, request.Model, func(c *types.ParamConstraints) []string { return c.ApplyStructured(&request) })
+		resp, err := next(ctx, request)
+		if resp != nil {
+			reportAdjusted(&resp.Metadata, adjusted)
+		}
+		return resp, err
+	}
+}
+
+func (m *paramConstraintsMiddleware) ApplyEmbeddings(next types.EmbeddingsHandler) types.EmbeddingsHandler {
+	return next
+}
+
+func (m *paramConstraintsMiddleware) ApplyAudio(next types.AudioHandler) types.AudioHandler {
+	return next
+}
+
+func (m *paramConstraintsMiddleware) ApplyImage(next types.ImageHandler) types.ImageHandler {
+	return next
+}
+
+func (m *paramConstraintsMiddleware) ApplyRerank(next types.RerankHandler) types.RerankHandler {
+	return next
+}
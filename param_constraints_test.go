@@ -0,0 +1,139 @@
+package wormhole
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+type requestCapturingProvider struct {
+	*types.BaseProvider
+	mu       sync.Mutex
+	requests []types.TextRequest
+}
+
+func (p *requestCapturingProvider) Text(_ context.Context, request types.TextRequest) (*types.TextResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, request)
+	return &types.TextResponse{Model: request.Model, Text: "ok", FinishReason: types.FinishReasonStop}, nil
+}
+
+func (p *requestCapturingProvider) last() types.TextRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests[len(p.requests)-1]
+}
+
+func constraintsTestClient(provider *requestCapturingProvider, opts ...Option) *Wormhole {
+	base := []Option{
+		WithDefaultProvider("mock"),
+		WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+		WithProviderConfig("mock", types.ProviderConfig{}),
+		WithModelRegistry(types.NewModelRegistry()),
+		WithDiscovery(false),
+	}
+	return New(append(base, opts...)...)
+}
+
+func TestParamConstraintsApplyBuiltinRules(t *testing.T) {
+	t.Parallel()
+
+	provider := &requestCapturingProvider{BaseProvider: types.NewBaseProvider("mock")}
+	client := constraintsTestClient(provider)
+
+	_, err := client.Text().Model("o3-mini").Prompt("hi").Temperature(0.2).TopP(0.9).MaxTokens(500).Generate(context.Background())
+	require.NoError(t, err)
+	request := provider.last()
+	assert.Nil(t, request.Temperature)
+	assert.Nil(t, request.TopP)
+	assert.Equal(t, 500, *request.MaxTokens)
+	assert.Equal(t, "max_completion_tokens", request.MaxTokensParam)
+
+	_, err = client.Text().Model("gpt-4o").Prompt("hi").Temperature(0.2).Generate(context.Background())
+	require.NoError(t, err)
+	assert.InDelta(t, 0.2, *provider.last().Temperature, 1e-6, "models without rules are untouched")
+}
+
+func TestParamConstraintsReportChanges(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	provider := &requestCapturingProvider{BaseProvider: types.NewBaseProvider("mock")}
+	client := constraintsTestClient(provider, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	for range 2 {
+		resp, err := client.Text().Model("gpt-5").Prompt("hi").Temperature(0.2).TopP(0.9).Generate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"temperature", "top_p"}, resp.Metadata["adjusted_params"])
+	}
+	assert.Equal(t, 1, strings.Count(logs.String(), "level=WARN"), "a repeated change is logged once: %s", logs.String())
+	assert.Contains(t, logs.String(), "model=gpt-5")
+
+	resp, err := client.Text().Model("gpt-5").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	assert.NotContains(t, resp.Metadata, "adjusted_params", "requests the rules leave alone are not reported")
+}
+
+func TestParamConstraintsFromRegistryAndEscapeHatch(t *testing.T) {
+	t.Parallel()
+
+	registry := types.NewModelRegistry()
+	registry.Register(&types.ModelInfo{ID: "house-model", ParamConstraints: &types.ParamConstraints{
+		Forbidden:       []string{"seed"},
+		Forced:          map[string]any{"temperature": 1.0},
+		MaxOutputTokens: 256,
+	}})
+	registry.Register(&types.ModelInfo{ID: "gpt-5", ParamConstraints: &types.ParamConstraints{}})
+
+	provider := &requestCapturingProvider{BaseProvider: types.NewBaseProvider("mock")}
+	client := constraintsTestClient(provider, WithModelRegistry(registry), WithModelValidation(false))
+
+	_, err := client.Text().Model("house-model").Prompt("hi").Temperature(0.3).Seed(7).MaxTokens(4096).Generate(context.Background())
+	require.NoError(t, err)
+	request := provider.last()
+	assert.Nil(t, request.Seed)
+	assert.InDelta(t, 1.0, *request.Temperature, 1e-6)
+	assert.Equal(t, 256, *request.MaxTokens)
+
+	_, err = client.Text().Model("gpt-5").Prompt("hi").Temperature(0.3).Generate(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, provider.last().Temperature, "an empty registry entry turns the built-in rules off")
+
+	raw := &requestCapturingProvider{BaseProvider: types.NewBaseProvider("mock")}
+	client = constraintsTestClient(raw, WithoutParamConstraints())
+	_, err = client.Text().Model("o3-mini").Prompt("hi").Temperature(0.2).Generate(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, raw.last().Temperature)
+	assert.Empty(t, raw.last().MaxTokensParam)
+}
+
+func TestParamConstraintsMapSystemRoles(t *testing.T) {
+	t.Parallel()
+
+	provider := &requestCapturingProvider{BaseProvider: types.NewBaseProvider("mock")}
+	client := constraintsTestClient(provider)
+
+	_, err := client.Text().Model("o3").SystemPrompt("Be terse.").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	messages := provider.last().Messages
+	require.Len(t, messages, 2)
+	assert.Equal(t, types.RoleDeveloper, messages[0].GetRole())
+	assert.Equal(t, "Be terse.", messages[0].GetContent())
+
+	_, err = client.Text().Model("o1-mini").SystemPrompt("Be terse.").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, types.RoleUser, provider.last().Messages[0].GetRole())
+
+	_, err = client.Text().Model("gpt-4o").SystemPrompt("Be terse.").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, types.RoleSystem, provider.last().Messages[0].GetRole())
+}
@@ -0,0 +1,14 @@
+package wormhole
+
+import "github.com/garyblankenship/wormhole/v2/middleware"
+
+// Priority ranks a request for scheduling and load shedding; see the
+// builders' Priority method and middleware.Scheduler.
+type Priority = middleware.Priority
+
+// Request priorities, from background batch work to interactive traffic.
+const (
+	Low    = middleware.PriorityLow
+	Normal = middleware.PriorityNormal
+	High   = middleware.PriorityHigh
+)
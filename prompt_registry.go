@@ -0,0 +1,191 @@
+package wormhole
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ErrPromptNotFound reports a prompt reference with no registered match.
+var ErrPromptNotFound = errors.New("prompt not found")
+
+// Prompt is a named, versioned prompt. System and Template are text/template
+// sources rendered with the variables passed to UsePrompt; a variable the
+// template references but the caller does not supply is an error.
+type Prompt struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// System is the optional system prompt.
+	System string `json:"system,omitempty"`
+	// Template is the user prompt.
+	Template string `json:"template"`
+	// Model is used when the builder sets none.
+	Model string `json:"model,omitempty"`
+	// Metadata is added to the request tags, alongside prompt and
+	// prompt_version, so metrics and logs attribute traffic to the prompt.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Ref returns the prompt's "name@version" reference.
+func (p Prompt) Ref() string {
+	return p.Name + "@" + p.Version
+}
+
+// Render fills in the system and user templates.
+func (p Prompt) Render(vars map[string]any) (system, user string, err error) {
+	if system, err = renderPromptTemplate(p.Ref()+"/system", p.System, vars); err != nil {
+		return "", "", err
+	}
+	if user, err = renderPromptTemplate(p.Ref(), p.Template, vars); err != nil {
+		return "", "", err
+	}
+	return system, user, nil
+}
+
+// tags returns the request tags that attribute a request to this prompt.
+func (p Prompt) tags() map[string]string {
+	tags := make(map[string]string, len(p.Metadata)+2)
+	maps.Copy(tags, p.Metadata)
+	tags["prompt"] = p.Name
+	tags["prompt_version"] = p.Version
+	return tags
+}
+
+func renderPromptTemplate(name, source string, vars map[string]any) (string, error) {
+	if source == "" {
+		return "", nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("prompt %s: %w", name, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, vars); err != nil {
+		return "", fmt.Errorf("prompt %s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// PromptRegistry holds versioned prompts so prompt text lives in one place and
+// every request records which version produced it. It is safe for concurrent
+// use. Every client has one (see Wormhole.Prompts); share a registry between
+// clients with WithPromptRegistry.
+type PromptRegistry struct {
+	mu       sync.RWMutex
+	prompts  map[string]map[string]Prompt // name -> version -> prompt
+	versions map[string][]string          // name -> versions in registration order
+}
+
+// NewPromptRegistry creates an empty registry.
+func NewPromptRegistry() *PromptRegistry {
+	return &PromptRegistry{
+		prompts:  make(map[string]map[string]Prompt),
+		versions: make(map[string][]string),
+	}
+}
+
+// Register adds prompts. Each needs a name and a version, and templates that
+// parse. Registering an existing name@version is an error: publish a new
+// version instead of changing one that responses are already attributed to.
+func (r *PromptRegistry) Register(prompts ...Prompt) error {
+	for _, prompt := range prompts {
+		if prompt.Name == "" || prompt.Version == "" {
+			return fmt.Errorf("prompt %q: name and version are required", prompt.Ref())
+		}
+		if strings.Contains(prompt.Name, "@") {
+			return fmt.Errorf("prompt %q: name must not contain @", prompt.Name)
+		}
+		for _, source := range []string{prompt.System, prompt.Template} {
+			if _, err := template.New(prompt.Ref()).Parse(source); err != nil {
+				return fmt.Errorf("prompt %s: %w", prompt.Ref(), err)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batch := make(map[string]bool, len(prompts))
+	for _, prompt := range prompts {
+		if _, exists := r.prompts[prompt.Name][prompt.Version]; exists || batch[prompt.Ref()] {
+			return fmt.Errorf("prompt %s is already registered", prompt.Ref())
+		}
+		batch[prompt.Ref()] = true
+	}
+	for _, prompt := range prompts {
+		if r.prompts[prompt.Name] == nil {
+			r.prompts[prompt.Name] = make(map[string]Prompt)
+		}
+		prompt.Metadata = maps.Clone(prompt.Metadata)
+		r.prompts[prompt.Name][prompt.Version] = prompt
+		r.versions[prompt.Name] = append(r.versions[prompt.Name], prompt.Version)
+	}
+	return nil
+}
+
+// Get returns the prompt for a "name@version" reference. A bare name returns
+// the most recently registered version.
+func (r *PromptRegistry) Get(ref string) (Prompt, error) {
+	name, version, pinned := strings.Cut(ref, "@")
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !pinned {
+		versions := r.versions[name]
+		if len(versions) == 0 {
+			return Prompt{}, fmt.Errorf("%w: %s", ErrPromptNotFound, ref)
+		}
+		version = versions[len(versions)-1]
+	}
+	prompt, ok := r.prompts[name][version]
+	if !ok {
+		return Prompt{}, fmt.Errorf("%w: %s", ErrPromptNotFound, ref)
+	}
+	prompt.Metadata = maps.Clone(prompt.Metadata)
+	return prompt, nil
+}
+
+// Versions returns a prompt's versions in registration order.
+func (r *PromptRegistry) Versions(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.versions[name]...)
+}
+
+// Prompts returns the client's prompt registry.
+//
+// Example:
+//
+//	_ = client.Prompts().Register(wormhole.Prompt{
+//	    Name:     "summarizer",
+//	    Version:  "v3",
+//	    System:   "You write one-paragraph summaries.",
+//	    Template: "Summarize for {{.audience}}:\n\n{{.text}}",
+//	})
+//	resp, err := client.Text().
+//	    UsePrompt("summarizer@v3", map[string]any{"audience": "executives", "text": doc}).
+//	    Generate(ctx)
+func (p *Wormhole) Prompts() *PromptRegistry {
+	return p.prompts
+}
+
+// usePrompt renders ref from the client's registry and tags the request with
+// the prompt's name, version and metadata. Errors are kept in promptErr and
+// returned when the request executes.
+func (cb *CommonBuilder) usePrompt(ref string, vars map[string]any) (prompt Prompt, system, user string, ok bool) {
+	prompt, err := cb.getWormhole().Prompts().Get(ref)
+	if err == nil {
+		system, user, err = prompt.Render(vars)
+	}
+	if err != nil {
+		cb.promptErr = types.ErrInvalidRequest.WithDetails(err.Error()).WithCause(err)
+		return Prompt{}, "", "", false
+	}
+	cb.promptErr = nil
+	cb.addTags(prompt.tags())
+	return prompt, system, user, true
+}
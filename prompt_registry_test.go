@@ -0,0 +1,119 @@
+package wormhole_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestPromptRegistryVersions(t *testing.T) {
+	t.Parallel()
+
+	registry := wormhole.NewPromptRegistry()
+	require.NoError(t, registry.Register(
+		wormhole.Prompt{Name: "summarizer", Version: "v1", Template: "Summarize: {{.text}}"},
+		wormhole.Prompt{Name: "summarizer", Version: "v2", Template: "Summarize briefly: {{.text}}"},
+	))
+
+	pinned, err := registry.Get("summarizer@v1")
+	require.NoError(t, err)
+	assert.Equal(t, "summarizer@v1", pinned.Ref())
+
+	latest, err := registry.Get("summarizer")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", latest.Version)
+	assert.Equal(t, []string{"v1", "v2"}, registry.Versions("summarizer"))
+
+	_, err = registry.Get("summarizer@v9")
+	assert.ErrorIs(t, err, wormhole.ErrPromptNotFound)
+
+	assert.Error(t, registry.Register(wormhole.Prompt{Name: "summarizer", Version: "v2", Template: "changed"}), "versions are immutable")
+	assert.Error(t, registry.Register(wormhole.Prompt{Name: "broken", Version: "v1", Template: "{{.text"}))
+	assert.Error(t, registry.Register(wormhole.Prompt{Name: "unversioned", Template: "hi"}))
+}
+
+func TestUsePromptRendersAndTagsRequests(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var seen *types.TextRequest
+	var tags map[string]string
+	capture := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			mu.Lock()
+			seen = req.(*types.TextRequest)
+			tags = middleware.TagsFromContext(ctx)
+			mu.Unlock()
+			return next(ctx, req)
+		}
+	}
+	provider := mocktesting.NewMockProvider("mock").WithTextResponse(types.TextResponse{Text: "ok"})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+		wormhole.WithMiddleware(capture),
+	)
+	defer client.Close()
+
+	require.NoError(t, client.Prompts().Register(wormhole.Prompt{
+		Name:     "summarizer",
+		Version:  "v3",
+		System:   "You write for {{.audience}}.",
+		Template: "Summarize: {{.text}}",
+		Model:    "gpt-5-mini",
+		Metadata: map[string]string{"experiment": "tone"},
+	}))
+
+	_, err := client.Text().
+		UsePrompt("summarizer@v3", map[string]any{"audience": "executives", "text": "Q3 report"}).
+		Generate(context.Background())
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "gpt-5-mini", seen.Model)
+	assert.Equal(t, "Summarize: Q3 report", seen.Messages[len(seen.Messages)-1].GetContent())
+	assert.Equal(t, types.NewSystemMessage("You write for executives."), seen.Messages[0])
+	assert.Equal(t, map[string]string{"prompt": "summarizer", "prompt_version": "v3", "experiment": "tone"}, tags)
+}
+
+func TestUsePromptErrorsSurfaceAtExecution(t *testing.T) {
+	t.Parallel()
+
+	client := wormhole.New(wormhole.WithDiscovery(false))
+	defer client.Close()
+	require.NoError(t, client.Prompts().Register(wormhole.Prompt{Name: "greet", Version: "v1", Template: "Hi {{.name}}"}))
+
+	_, err := client.Text().Model("m").UsePrompt("missing", nil).Generate(context.Background())
+	assert.True(t, errors.Is(err, wormhole.ErrPromptNotFound))
+
+	_, err = client.Structured().Model("m").Schema(map[string]any{"type": "object"}).UsePrompt("greet@v1", map[string]any{}).Generate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "name")
+}
+
+func TestWithPromptRegistrySharesPrompts(t *testing.T) {
+	t.Parallel()
+
+	registry := wormhole.NewPromptRegistry()
+	a := wormhole.New(wormhole.WithDiscovery(false), wormhole.WithPromptRegistry(registry))
+	b := wormhole.New(wormhole.WithDiscovery(false), wormhole.WithPromptRegistry(registry))
+	defer a.Close()
+	defer b.Close()
+
+	require.NoError(t, a.Prompts().Register(wormhole.Prompt{Name: "greet", Version: "v1", Template: "Hi"}))
+	_, err := b.Prompts().Get("greet@v1")
+	assert.NoError(t, err)
+}
@@ -1,6 +1,7 @@
 package wormhole
 
 import (
+	"context"
 	"fmt"
 	"sync/atomic"
 
@@ -87,3 +88,28 @@ func (p *Wormhole) providerFactoryFor(name string) (types.ProviderFactory, error
 
 	return nil, types.ErrProviderNotFound.WithProvider(name).WithDetails(p.formatProviderHint(name))
 }
+
+// DeferredCompletion fetches the result of a request sent with
+// types.XAIOptions{Deferred: true}; requestID is the ID of the response that
+// request returned. It returns types.ErrDeferredPending while the completion
+// is still running, so callers can poll:
+//
+//	for {
+//	    resp, err := client.DeferredCompletion(ctx, "xai", id)
+//	    if !errors.Is(err, types.ErrDeferredPending) {
+//	        return resp, err
+//	    }
+//	    time.Sleep(2 * time.Second)
+//	}
+func (p *Wormhole) DeferredCompletion(ctx context.Context, provider, requestID string) (*types.TextResponse, error) {
+	handle, release, err := p.leaseProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	completer, ok := handle.(types.DeferredCompleter)
+	if !ok {
+		return nil, types.NewWormholeError(types.ErrorCodeProvider, fmt.Sprintf("%s provider does not support deferred completions", handle.Name()), false)
+	}
+	return completer.DeferredCompletion(ctx, requestID)
+}
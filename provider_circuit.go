@@ -0,0 +1,19 @@
+package wormhole
+
+import "github.com/garyblankenship/wormhole/v2/middleware"
+
+// ProviderState reports the circuit state of a provider: open if any of its
+// operations is tripped, half-open while probing, closed otherwise. Without
+// WithCircuitBreaker every provider is closed.
+func (p *Wormhole) ProviderState(provider string) middleware.CircuitState {
+	if p.circuits == nil {
+		return middleware.StateClosed
+	}
+	return p.circuits.ProviderState(provider)
+}
+
+// CircuitBreakers returns the client's per-provider breakers for detailed
+// stats, or nil without WithCircuitBreaker.
+func (p *Wormhole) CircuitBreakers() *middleware.CircuitBreakers {
+	return p.circuits
+}
@@ -3,6 +3,7 @@ package wormhole
 import (
 	"fmt"
 	"maps"
+	"slices"
 	"time"
 
 	whconfig "github.com/garyblankenship/wormhole/v2/config"
@@ -41,6 +42,9 @@ func cloneProviderConfig(config types.ProviderConfig) types.ProviderConfig {
 		cloned.RequestPolicy.MaxTokensParamRules = make([]types.MaxTokensParamRule, len(config.RequestPolicy.MaxTokensParamRules))
 		copy(cloned.RequestPolicy.MaxTokensParamRules, config.RequestPolicy.MaxTokensParamRules)
 	}
+	if config.RequestPolicy.SamplingParams != nil {
+		cloned.RequestPolicy.SamplingParams = append([]types.SamplingParam{}, config.RequestPolicy.SamplingParams...)
+	}
 	if config.MaxRetries != nil {
 		maxRetries := *config.MaxRetries
 		cloned.MaxRetries = &maxRetries
@@ -53,6 +57,12 @@ func cloneProviderConfig(config types.ProviderConfig) types.ProviderConfig {
 		retryMaxDelay := *config.RetryMaxDelay
 		cloned.RetryMaxDelay = &retryMaxDelay
 	}
+	if config.SchemaSupport != nil {
+		support := *config.SchemaSupport
+		support.Formats = slices.Clone(config.SchemaSupport.Formats)
+		support.UnsupportedKeywords = slices.Clone(config.SchemaSupport.UnsupportedKeywords)
+		cloned.SchemaSupport = &support
+	}
 	if config.HTTPTimeout != nil {
 		httpTimeout := *config.HTTPTimeout
 		cloned.HTTPTimeout = &httpTimeout
@@ -87,6 +97,15 @@ func (p *Wormhole) applyDefaultRetries(config types.ProviderConfig) types.Provid
 	return config
 }
 
+func (p *Wormhole) applyDefaultUserAgent(config types.ProviderConfig) types.ProviderConfig {
+	if config.UserAgent != "" || config.DisableUserAgent {
+		return config
+	}
+	config.UserAgent = p.config.UserAgent
+	config.DisableUserAgent = p.config.DisableUserAgent
+	return config
+}
+
 func (p *Wormhole) createProviderWithConfig(name string, config types.ProviderConfig) (types.Provider, error) {
 	factory, err := p.providerFactoryFor(name)
 	if err != nil {
@@ -104,6 +123,7 @@ func (p *Wormhole) createProviderWithConfig(name string, config types.ProviderCo
 
 	config = p.applyDefaultTimeout(config)
 	config = p.applyDefaultRetries(config)
+	config = p.applyDefaultUserAgent(config)
 	provider, err := factory(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create provider %s: %w", name, err)
@@ -8,6 +8,8 @@ import (
 
 	"github.com/garyblankenship/wormhole/v2/providers/anthropic"
 	"github.com/garyblankenship/wormhole/v2/providers/gemini"
+	"github.com/garyblankenship/wormhole/v2/providers/huggingface"
+	"github.com/garyblankenship/wormhole/v2/providers/lmstudio"
 	"github.com/garyblankenship/wormhole/v2/providers/ollama"
 	"github.com/garyblankenship/wormhole/v2/providers/openai"
 	"github.com/garyblankenship/wormhole/v2/types"
@@ -87,6 +89,18 @@ func ollamaFactory() types.ProviderFactory {
 	}
 }
 
+func huggingFaceFactory() types.ProviderFactory {
+	return func(c types.ProviderConfig) (types.Provider, error) {
+		return huggingface.New(c), nil
+	}
+}
+
+func lmStudioFactory() types.ProviderFactory {
+	return func(c types.ProviderConfig) (types.Provider, error) {
+		return lmstudio.New(c), nil
+	}
+}
+
 func namedOpenAICompatibleFactory(name string) types.ProviderFactory {
 	return func(c types.ProviderConfig) (types.Provider, error) {
 		return openai.NewWithName(name, c), nil
@@ -99,6 +113,8 @@ const (
 	providerGemini     = "gemini"
 	providerOpenRouter = "openrouter"
 	providerOllama     = "ollama"
+	providerHF         = "huggingface"
+	providerLMStudio   = "lmstudio"
 )
 
 type cachedProvider struct {
@@ -129,4 +145,5 @@ func (p *Wormhole) registerBuiltinProviders() {
 	p.providerFactories[providerAnthropic] = anthropicFactory()
 	p.providerFactories[providerGemini] = geminiFactory()
 	p.providerFactories[providerOllama] = ollamaFactory()
+	p.providerFactories[providerHF] = huggingFaceFactory()
 }
@@ -42,6 +42,56 @@ func TestCircuitBreakerDoesNotBlockProviderFallback(t *testing.T) {
 	}
 }
 
+func TestProviderStateReportsTrippedProvider(t *testing.T) {
+	primary := &providerFallbackTextProvider{
+		BaseProvider: types.NewBaseProvider("primary"),
+		err:          errors.New("primary unavailable"),
+	}
+	secondary := &providerFallbackTextProvider{
+		BaseProvider: types.NewBaseProvider("secondary"),
+		response:     "fallback survived",
+	}
+	var changes []middleware.CircuitStateChange
+	client := New(
+		WithDefaultProvider("primary"),
+		WithCustomProvider("primary", func(types.ProviderConfig) (types.Provider, error) { return primary, nil }),
+		WithProviderConfig("primary", types.ProviderConfig{}),
+		WithCustomProvider("secondary", func(types.ProviderConfig) (types.Provider, error) { return secondary, nil }),
+		WithProviderConfig("secondary", types.ProviderConfig{}),
+		WithCircuitBreaker(middleware.CircuitBreakerConfig{
+			FailureThreshold: 1,
+			Timeout:          time.Hour,
+			OnStateChange:    func(c middleware.CircuitStateChange) { changes = append(changes, c) },
+		}),
+		WithDiscovery(false),
+	)
+
+	if got := client.ProviderState("primary"); got != middleware.StateClosed {
+		t.Fatalf("initial state = %s, want closed", got)
+	}
+	_, err := client.Text().
+		Model("primary-model").
+		WithProviderFallback(TextRoute{Provider: "secondary", Model: "secondary-model"}).
+		Prompt("hello").
+		Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := client.ProviderState("primary"); got != middleware.StateOpen {
+		t.Fatalf("primary state = %s, want open", got)
+	}
+	if got := client.ProviderState("secondary"); got != middleware.StateClosed {
+		t.Fatalf("secondary state = %s, want closed", got)
+	}
+	if len(changes) != 1 || changes[0].Provider != "primary" || changes[0].Operation != "text" || changes[0].To != middleware.StateOpen {
+		t.Fatalf("changes = %+v", changes)
+	}
+	if stats := client.CircuitBreakers().Stats(); len(stats) != 2 || stats[0].Opens != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
 type providerFallbackTextProvider struct {
 	*types.BaseProvider
 	err      error
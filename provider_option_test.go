@@ -0,0 +1,89 @@
+package wormhole_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func newProviderOptionClient(t *testing.T) (*wormhole.Wormhole, func() []map[string]any) {
+	t.Helper()
+	provider := mocktesting.NewMockProvider("mock").
+		WithTextResponse(types.TextResponse{Text: "ok", FinishReason: types.FinishReasonStop}).
+		WithEmbeddings([]types.Embedding{{Index: 0, Embedding: []float64{0.1}}})
+
+	var mu sync.Mutex
+	var sent []map[string]any
+	capture := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			mu.Lock()
+			switch request := req.(type) {
+			case *types.TextRequest:
+				sent = append(sent, request.ProviderOptions)
+			case *types.EmbeddingsRequest:
+				sent = append(sent, request.ProviderOptions)
+			}
+			mu.Unlock()
+			return next(ctx, req)
+		}
+	}
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithMiddleware(capture),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+	)
+	t.Cleanup(func() { _ = client.Close() })
+	return client, func() []map[string]any {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]map[string]any(nil), sent...)
+	}
+}
+
+func TestProviderOptionScopedToServingProvider(t *testing.T) {
+	t.Parallel()
+	client, sent := newProviderOptionClient(t)
+
+	_, err := client.Text().
+		Model("m").
+		Prompt("hi").
+		ProviderOptions(map[string]any{"top_k": 1, "keep": true}).
+		ProviderOption("mock", "top_k", 40).
+		ProviderOption("mock", "min_p", 0.1).
+		ProviderOption("anthropic", "thinking", "never sent").
+		Generate(context.Background())
+	require.NoError(t, err)
+
+	got := sent()
+	require.Len(t, got, 1)
+	assert.Equal(t, map[string]any{"top_k": 40, "keep": true, "min_p": 0.1}, got[0])
+}
+
+func TestProviderOptionOnEmbeddingsAndClones(t *testing.T) {
+	t.Parallel()
+	client, sent := newProviderOptionClient(t)
+
+	base := client.Embeddings().Model("e").Input("a").ProviderOption("mock", "input_type", "query")
+	clone := base.Clone().ProviderOption("mock", "input_type", "document")
+
+	_, err := base.Generate(context.Background())
+	require.NoError(t, err)
+	_, err = clone.Generate(context.Background())
+	require.NoError(t, err)
+
+	got := sent()
+	require.Len(t, got, 2)
+	assert.Equal(t, map[string]any{"input_type": "query"}, got[0])
+	assert.Equal(t, map[string]any{"input_type": "document"}, got[1])
+}
@@ -36,6 +36,9 @@ type ProviderRequestPolicy struct {
 	MaxTokensParam      string               `json:"max_tokens_param,omitempty"`
 	MaxTokensParamRules []MaxTokensParamRule `json:"max_tokens_param_rules,omitempty"`
 	MaxTokensCap        int                  `json:"max_tokens_cap,omitempty"`
+	RerankTopNParam     string               `json:"rerank_top_n_param,omitempty"`
+	SamplingParams      []string             `json:"sampling_params,omitempty"`
+	ThinkTags           bool                 `json:"think_tags,omitempty"`
 }
 
 // MaxTokensParamRule selects a request parameter name when ModelContains is
@@ -52,6 +55,7 @@ const (
 	discoveryAnthropic        = "anthropic"
 	discoveryGemini           = "gemini"
 	discoveryOllama           = "ollama"
+	discoveryLMStudio         = "lmstudio"
 	discoveryOpenRouter       = "openrouter"
 	discoveryOpenAICompatible = "openai-compatible"
 )
@@ -128,6 +132,9 @@ func cloneProviderProfile(src ProviderProfile) ProviderProfile {
 	dst := src
 	dst.APIKeyEnv = append([]string(nil), src.APIKeyEnv...)
 	dst.RequestPolicy.MaxTokensParamRules = append([]MaxTokensParamRule(nil), src.RequestPolicy.MaxTokensParamRules...)
+	if src.RequestPolicy.SamplingParams != nil {
+		dst.RequestPolicy.SamplingParams = append([]string{}, src.RequestPolicy.SamplingParams...)
+	}
 	dst.DefaultProviderOptions = cloneProviderOptions(src.DefaultProviderOptions)
 	return dst
 }
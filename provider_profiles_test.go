@@ -25,6 +25,7 @@ func TestProviderProfilesExposeKnownProviders(t *testing.T) {
 		{name: "groq", baseURL: "https://api.groq.com/openai/v1"},
 		{name: "synthetic", baseURL: "https://api.synthetic.new/v1"},
 		{name: "zai", baseURL: "https://api.z.ai/api/coding/paas/v4"},
+		{name: "xai", baseURL: "https://api.x.ai/v1"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -99,6 +100,7 @@ func TestProfiledOpenAICompatibleUsesProfileBaseURL(t *testing.T) {
 		{name: "groq", baseURL: "https://api.groq.com/openai/v1"},
 		{name: "synthetic", baseURL: "https://api.synthetic.new/v1"},
 		{name: "zai", baseURL: "https://api.z.ai/api/coding/paas/v4"},
+		{name: "xai", baseURL: "https://api.x.ai/v1"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -132,6 +134,38 @@ func TestDeepSeekProfileDisablesThinkingByDefault(t *testing.T) {
 	}
 }
 
+func TestWithDeepSeekUsesDeepSeekProfile(t *testing.T) {
+	t.Parallel()
+
+	client := New(WithDeepSeek("test-key"), WithDiscovery(false))
+	cfg, ok := client.config.Providers["deepseek"]
+	if !ok {
+		t.Fatal("deepseek provider was not configured")
+	}
+	if cfg.APIKey != "test-key" || cfg.BaseURL != "https://api.deepseek.com" {
+		t.Fatalf("deepseek config = key %q, base URL %q", cfg.APIKey, cfg.BaseURL)
+	}
+	if cfg.RequestPolicy.ThinkTags {
+		t.Fatal("deepseek returns reasoning_content; its profile must not split inline <think> blocks")
+	}
+}
+
+func TestThinkTagsLimitedToInlineReasoningProfiles(t *testing.T) {
+	t.Parallel()
+
+	inline := map[string]bool{"groq": true, "vllm": true, "lmstudio": true, "ollama-openai": true}
+	for _, profile := range KnownProviderProfiles() {
+		if profile.RequestPolicy.ThinkTags != inline[profile.Name] {
+			t.Errorf("%s think_tags = %v, want %v", profile.Name, profile.RequestPolicy.ThinkTags, inline[profile.Name])
+		}
+	}
+
+	client := New(WithGroq("test-key"), WithDiscovery(false))
+	if !client.config.Providers["groq"].RequestPolicy.ThinkTags {
+		t.Fatal("groq profile did not apply think_tags to the provider config")
+	}
+}
+
 func TestProfileDefaultProviderOptionsPreserveConfigOverride(t *testing.T) {
 	t.Parallel()
 
@@ -231,6 +265,14 @@ func TestProviderProfileRequestPolicyFlowsIntoConfig(t *testing.T) {
 	if len(cfg.RequestPolicy.MaxTokensParamRules) != 1 {
 		t.Fatalf("max token rules = %#v", cfg.RequestPolicy.MaxTokensParamRules)
 	}
+	if got := cfg.RequestPolicy.SamplingParams; len(got) != 1 || got[0] != types.SamplingLogitBias {
+		t.Fatalf("openai sampling params = %#v", got)
+	}
+
+	groq := New(WithGroq("test-key"), WithDiscovery(false)).config.Providers["groq"]
+	if groq.RequestPolicy.SamplingParams == nil || len(groq.RequestPolicy.SamplingParams) != 0 {
+		t.Fatalf("groq sampling params = %#v, want empty non-nil", groq.RequestPolicy.SamplingParams)
+	}
 }
 
 func TestWithProviderFromEnvUsesProfileEnvNames(t *testing.T) {
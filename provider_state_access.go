@@ -57,3 +57,14 @@ func (s *ProviderAdaptiveState) InUse() int {
 	}
 	return s.limiter.InUse()
 }
+
+// Degraded reports whether the average latency over the sampling window
+// exceeds the target latency. A state with no samples is never degraded.
+func (s *ProviderAdaptiveState) Degraded() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.latencySamples == 0 || s.targetLatency <= 0 {
+		return false
+	}
+	return s.totalLatency/time.Duration(s.latencySamples) > s.targetLatency
+}
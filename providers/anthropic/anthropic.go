@@ -48,6 +48,8 @@ func (p *Provider) SupportedCapabilities() []types.ModelCapability {
 		types.CapabilityStructured,
 		types.CapabilityStream,
 		types.CapabilityFunctions,
+		types.CapabilityWebSearch,
+		types.CapabilityTokenCounting,
 	}
 }
 
@@ -77,24 +79,10 @@ func (p *Provider) Text(ctx context.Context, request types.TextRequest) (*types.
 	return resp, nil
 }
 
-// stampProvider sets Provider on the terminal chunk. Sole closer of out;
-// exits when the upstream channel closes.
+// stampProvider ends the stream pipeline with providerstream.Finalize, which
+// stamps the terminal chunk, types errors, and closes promptly on cancellation.
 func (p *Provider) stampProvider(ctx context.Context, in <-chan types.StreamChunk) <-chan types.StreamChunk {
-	out := make(chan types.StreamChunk)
-	go func() {
-		defer close(out)
-		for chunk := range in {
-			if chunk.IsDone() {
-				chunk.Provider = p.Name()
-			}
-			select {
-			case out <- chunk:
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-	return out
+	return providerstream.Finalize(ctx, p.Name(), in)
 }
 
 // Stream generates a streaming text response
@@ -118,13 +106,28 @@ func (p *Provider) Stream(ctx context.Context, request types.TextRequest) (<-cha
 		return nil, err
 	}
 
-	return p.stampProvider(ctx, p.accumulatingStream(ctx, providerstream.ProcessSSE(ctx, body, p.parseStreamChunk, 100))), nil
+	return p.stampProvider(ctx, p.accumulatingStream(ctx, providerstream.FinalUsage(ctx, providerstream.ProcessSSE(ctx, body, p.parseStreamChunk, 100)))), nil
 }
 
 func (p *Provider) validateSamplingControls(request types.TextRequest) error {
 	if request.FrequencyPenalty != nil || request.PresencePenalty != nil || request.Seed != nil {
 		return p.ValidationError("frequency_penalty, presence_penalty, and seed are not supported by Anthropic")
 	}
+	if request.Logprobs {
+		return p.ValidationError("logprobs are not supported by Anthropic")
+	}
+	if request.N > 1 {
+		return p.ValidationError("n above 1 is not supported by Anthropic")
+	}
+	if request.Audio != nil {
+		return p.ValidationError("audio output is not supported by Anthropic")
+	}
+	if err := p.CheckAudioInput(request.Messages, "Anthropic"); err != nil {
+		return err
+	}
+	if err := p.CheckSamplingParams(request.BaseRequest, "Anthropic", types.SamplingTopK); err != nil {
+		return err
+	}
 	if request.ParallelToolCalls != nil && request.ToolChoice != nil && request.ToolChoice.Type == types.ToolChoiceTypeNone {
 		return p.ValidationError("parallel_tool_calls cannot be used when Anthropic tool_choice is none")
 	}
@@ -160,6 +163,9 @@ func (p *Provider) Structured(ctx context.Context, request types.StructuredReque
 	if err != nil {
 		return nil, err
 	}
+	if err := response.RefusalError(); err != nil {
+		return nil, err
+	}
 
 	// Extract structured data from tool call
 	if len(response.ToolCalls) == 0 {
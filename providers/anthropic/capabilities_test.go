@@ -16,11 +16,13 @@ func TestProviderSupportedCapabilities(t *testing.T) {
 	provider := anthropic.New(types.ProviderConfig{APIKey: "test-key"})
 	capabilities := provider.SupportedCapabilities()
 
-	require.Len(t, capabilities, 5)
+	require.Len(t, capabilities, 7)
 	assert.Contains(t, capabilities, types.CapabilityText)
 	assert.Contains(t, capabilities, types.CapabilityChat)
 	assert.Contains(t, capabilities, types.CapabilityStructured)
 	assert.Contains(t, capabilities, types.CapabilityStream)
 	assert.Contains(t, capabilities, types.CapabilityFunctions)
+	assert.Contains(t, capabilities, types.CapabilityWebSearch)
+	assert.Contains(t, capabilities, types.CapabilityTokenCounting)
 	assert.NotContains(t, capabilities, types.CapabilityImages)
 }
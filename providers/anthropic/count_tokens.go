@@ -0,0 +1,42 @@
+package anthropic
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/garyblankenship/wormhole/v2/providers"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+var _ types.TokenCounter = (*Provider)(nil)
+
+// countTokensFields are the Messages API fields count_tokens accepts; it
+// rejects generation settings such as max_tokens and temperature.
+var countTokensFields = []string{"model", "messages", "system", "tools", "tool_choice", "thinking", "mcp_servers"}
+
+// CountTokens returns the input tokens request would use, counted by
+// Anthropic's count_tokens endpoint. System prompts, tools, images, and
+// documents are all counted.
+func (p *Provider) CountTokens(ctx context.Context, request types.TextRequest) (*types.TokenCount, error) {
+	if _, _, err := providers.PrepareMessages(request.Messages); err != nil {
+		return nil, err
+	}
+	full, err := p.buildMessagePayload(&request)
+	if err != nil {
+		return nil, err
+	}
+	payload := make(map[string]any, len(countTokensFields))
+	for _, field := range countTokensFields {
+		if value, ok := full[field]; ok {
+			payload[field] = value
+		}
+	}
+
+	var response struct {
+		InputTokens int `json:"input_tokens"`
+	}
+	if err := p.DoRequest(ctx, http.MethodPost, p.GetBaseURL()+"/messages/count_tokens", payload, &response); err != nil {
+		return nil, err
+	}
+	return &types.TokenCount{Provider: p.Name(), Model: request.Model, InputTokens: response.InputTokens}, nil
+}
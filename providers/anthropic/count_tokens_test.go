@@ -0,0 +1,42 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestCountTokensSendsOnlyCountableFields(t *testing.T) {
+	t.Parallel()
+	var path string
+	var body map[string]any
+	server := testutil.MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = io.WriteString(w, `{"input_tokens":42}`)
+	})
+
+	temperature := float32(0.5)
+	provider := New(types.ProviderConfig{APIKey: "test-key", BaseURL: server.URL})
+	count, err := provider.CountTokens(context.Background(), types.TextRequest{
+		BaseRequest:  types.BaseRequest{Model: "claude-sonnet-4-5", Temperature: &temperature},
+		SystemPrompt: "Be brief.",
+		Messages:     []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, count.InputTokens)
+	assert.Equal(t, "anthropic", count.Provider)
+
+	assert.Equal(t, "/messages/count_tokens", path)
+	assert.Equal(t, "Be brief.", body["system"])
+	assert.NotContains(t, body, "max_tokens")
+	assert.NotContains(t, body, "temperature")
+}
@@ -0,0 +1,26 @@
+package anthropic
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+	"github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+// TestGoldenPayloads pins the Messages API wire format.
+// Regenerate with WORMHOLE_UPDATE_GOLDEN=1 and review the fixture diff.
+func TestGoldenPayloads(t *testing.T) {
+	t.Parallel()
+
+	for name, request := range wormholetest.GoldenTextRequests("claude-sonnet-4-5") {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			wormholetest.AssertGoldenPayload(t, filepath.Join("testdata", "golden", name+".json"), func(baseURL string) {
+				config := types.NewProviderConfig("test-key").WithBaseURL(baseURL).WithNoRetries()
+				_, _ = New(config).Text(context.Background(), request)
+			})
+		})
+	}
+}
@@ -206,12 +206,13 @@ func TestAnthropicProvider_SystemMessagesInMessageList(t *testing.T) {
 		SystemPrompt: "base system",
 		Messages: []types.Message{
 			types.NewSystemMessage("message system"),
+			types.NewDeveloperMessage("developer note"),
 			types.NewUserMessage("hello"),
 		},
 	})
 	require.NoError(t, err)
 
-	assert.Equal(t, "base system\n\nmessage system", captured["system"])
+	assert.Equal(t, "base system\n\nmessage system\n\ndeveloper note", captured["system"])
 	messages := captured["messages"].([]any)
 	require.Len(t, messages, 1)
 	assert.Equal(t, "user", messages[0].(map[string]any)["role"])
@@ -1,7 +1,9 @@
 package anthropic
 
 import (
+	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/garyblankenship/wormhole/v2/config"
 	"github.com/garyblankenship/wormhole/v2/providers"
@@ -13,6 +15,9 @@ const (
 	contentTypeText     = "text"
 	contentTypeThinking = "thinking"
 	contentTypeToolUse  = "tool_use"
+	// contentTypeServerToolUse is a tool call Anthropic runs itself, such as
+	// web search; it is never returned as a ToolCall.
+	contentTypeServerToolUse = "server_tool_use"
 )
 
 // Role constant
@@ -24,6 +29,9 @@ func (p *Provider) buildMessagePayload(request *types.TextRequest) (map[string]a
 	if err != nil {
 		prepared = request.Messages
 	}
+	if err := documentLimits.CheckDocuments(prepared); err != nil {
+		return nil, p.ValidationError("unsupported document input", err.Error())
+	}
 	payload := map[string]any{
 		"model":    request.Model,
 		"messages": p.transformMessages(prepared),
@@ -51,6 +59,9 @@ func (p *Provider) buildMessagePayload(request *types.TextRequest) (map[string]a
 		payload["stop_sequences"] = stop
 		delete(payload, "stop")
 	}
+	if request.TopK != nil {
+		payload["top_k"] = *request.TopK
+	}
 
 	if thinking := anthropicThinkingPayload(request.Reasoning); len(thinking) > 0 {
 		payload["thinking"] = thinking
@@ -77,6 +88,10 @@ func (p *Provider) buildMessagePayload(request *types.TextRequest) (map[string]a
 			payload["tool_choice"] = toolChoice
 		}
 	}
+	if request.WebSearch != nil {
+		tools, _ := payload["tools"].([]map[string]any)
+		payload["tools"] = append(tools, webSearchTool(request.WebSearch))
+	}
 
 	// Provider options
 	for k, v := range p.Config.MergedProviderOptions(request.Model, request.ProviderOptions) {
@@ -108,7 +123,7 @@ func (p *Provider) transformMessages(messages []types.Message) []map[string]any
 
 	for _, msg := range messages {
 		// Skip system messages as they go in a separate field
-		if msg.GetRole() == types.RoleSystem {
+		if msg.GetRole().IsSystem() {
 			continue
 		}
 
@@ -149,6 +164,35 @@ func coalesceSameRole(messages []map[string]any) []map[string]any {
 	return merged
 }
 
+// documentLimits reflects Anthropic's document blocks: PDFs up to 32 MB
+// (inline or by URL) and plain text.
+var documentLimits = types.DocumentLimits{
+	MaxBytes:  32 << 20,
+	MimeTypes: []string{types.MimeTypePDF, "text/plain"},
+	AllowURL:  true,
+}
+
+// documentBlock renders a document as an Anthropic document content block.
+func documentBlock(doc *types.DocumentMedia) map[string]any {
+	var source map[string]any
+	switch {
+	case len(doc.Data) == 0:
+		source = map[string]any{"type": "url", "url": doc.URL}
+	case strings.HasPrefix(doc.MimeType, "text/"):
+		source = map[string]any{"type": "text", "media_type": "text/plain", "data": string(doc.Data)}
+	default:
+		source = map[string]any{"type": "base64", "media_type": doc.MimeType, "data": base64.StdEncoding.EncodeToString(doc.Data)}
+	}
+	block := map[string]any{"type": "document", "source": source}
+	if doc.Filename != "" {
+		block["title"] = doc.Filename
+	}
+	if doc.Citations {
+		block["citations"] = map[string]any{"enabled": true}
+	}
+	return block
+}
+
 // buildContent builds the content array for a message
 func (p *Provider) buildContent(msg types.Message) []map[string]any {
 	var contentParts []map[string]any
@@ -184,6 +228,19 @@ func (p *Provider) buildContent(msg types.Message) []map[string]any {
 		})
 	}
 
+	// Documents, plus custom media registered with types.RegisterMediaCodec.
+	if userMsg, ok := msg.(*types.UserMessage); ok {
+		for _, media := range userMsg.Media {
+			if doc, ok := media.(*types.DocumentMedia); ok {
+				contentParts = append(contentParts, documentBlock(doc))
+				continue
+			}
+			if part, ok := types.MarshalMedia(types.MediaFormatAnthropic, media); ok {
+				contentParts = append(contentParts, part)
+			}
+		}
+	}
+
 	// Handle tool messages: Anthropic requires a distinct tool_result block,
 	// not a text block with tool_use_id bolted on.
 	if toolMsg, ok := msg.(*types.ToolMessage); ok {
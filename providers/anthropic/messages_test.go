@@ -173,3 +173,56 @@ func TestTransformMessages_CoalescesConsecutiveUserRole(t *testing.T) {
 		assert.NotEqual(t, out[i-1]["role"], out[i]["role"], "no two adjacent messages may share a role")
 	}
 }
+
+func TestBuildMessagePayloadDocuments(t *testing.T) {
+	t.Parallel()
+	p := New(types.ProviderConfig{APIKey: "test-key"})
+
+	pdf := types.NewDocument([]byte("%PDF-1.7"), types.MimeTypePDF)
+	pdf.Filename = "report.pdf"
+	payload, err := p.buildMessagePayload(&types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "claude-sonnet-4-5"},
+		Messages: []types.Message{&types.UserMessage{
+			Content: "Summarize",
+			Media: []types.Media{
+				pdf,
+				&types.DocumentMedia{URL: "https://example.com/spec.pdf", MimeType: types.MimeTypePDF},
+				types.NewDocument([]byte("plain notes"), "text/plain"),
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	content := payload["messages"].([]map[string]any)[0]["content"].([]map[string]any)
+	require.Len(t, content, 4)
+	assert.Equal(t, map[string]any{
+		"type":  "document",
+		"title": "report.pdf",
+		"source": map[string]any{
+			"type": "base64", "media_type": types.MimeTypePDF, "data": "JVBERi0xLjc=",
+		},
+	}, content[1])
+	assert.Equal(t, map[string]any{"type": "url", "url": "https://example.com/spec.pdf"}, content[2]["source"])
+	assert.Equal(t, map[string]any{"type": "text", "media_type": "text/plain", "data": "plain notes"}, content[3]["source"])
+	assert.NotContains(t, content[3], "citations")
+
+	cited := types.NewDocument([]byte("plain notes"), "text/plain")
+	cited.Citations = true
+	payload, err = p.buildMessagePayload(&types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "claude-sonnet-4-5"},
+		Messages:    []types.Message{&types.UserMessage{Content: "Summarize", Media: []types.Media{cited}}},
+	})
+	require.NoError(t, err)
+	content = payload["messages"].([]map[string]any)[0]["content"].([]map[string]any)
+	assert.Equal(t, map[string]any{"enabled": true}, content[1]["citations"])
+
+	_, err = p.buildMessagePayload(&types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "claude-sonnet-4-5"},
+		Messages: []types.Message{&types.UserMessage{
+			Content: "Summarize",
+			Media:   []types.Media{types.NewDocument([]byte("PK"), "application/zip")},
+		}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported document input")
+}
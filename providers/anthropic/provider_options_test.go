@@ -1,6 +1,7 @@
 package anthropic
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/garyblankenship/wormhole/v2/types"
@@ -77,6 +78,25 @@ func TestParallelToolCallsMapsToAnthropicToolChoice(t *testing.T) {
 	if err := provider.validateSamplingControls(types.TextRequest{BaseRequest: types.BaseRequest{FrequencyPenalty: &frequency}}); err == nil {
 		t.Fatal("Anthropic accepted unsupported frequency_penalty")
 	}
+	if err := provider.validateSamplingControls(types.TextRequest{Logprobs: true}); err == nil {
+		t.Fatal("Anthropic accepted unsupported logprobs")
+	}
+	minP := float32(0.05)
+	if err := provider.validateSamplingControls(types.TextRequest{BaseRequest: types.BaseRequest{MinP: &minP, LogitBias: map[int]int{1: 5}}}); err == nil ||
+		!strings.Contains(err.Error(), "min_p, logit_bias are not supported by Anthropic") {
+		t.Fatalf("Anthropic min_p/logit_bias error = %v", err)
+	}
+	topK := 40
+	if err := provider.validateSamplingControls(types.TextRequest{BaseRequest: types.BaseRequest{TopK: &topK}}); err != nil {
+		t.Fatalf("Anthropic rejected top_k: %v", err)
+	}
+	topKPayload, err := provider.buildMessagePayload(&types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "claude-test", TopK: &topK},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	if err != nil || topKPayload["top_k"] != topK {
+		t.Fatalf("top_k payload = %#v, err = %v", topKPayload["top_k"], err)
+	}
 
 	none := &types.ToolChoice{Type: types.ToolChoiceTypeNone}
 	request := types.TextRequest{
@@ -100,3 +120,19 @@ func TestParallelToolCallsMapsToAnthropicToolChoice(t *testing.T) {
 		t.Fatalf("tool_choice contains invalid disable_parallel_tool_use: %#v", choice)
 	}
 }
+
+func TestAudioInputRejectedByAnthropic(t *testing.T) {
+	t.Parallel()
+	provider := New(types.NewProviderConfig("key"))
+	request := types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "claude-test"},
+		Messages: []types.Message{&types.UserMessage{
+			Content: "transcribe this",
+			Media:   []types.Media{types.NewAudio([]byte("RIFF"), "wav")},
+		}},
+	}
+	if err := provider.validateSamplingControls(request); err == nil ||
+		!strings.Contains(err.Error(), "audio input is not supported by Anthropic") {
+		t.Fatalf("Anthropic audio input error = %v", err)
+	}
+}
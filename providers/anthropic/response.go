@@ -3,6 +3,7 @@ package anthropic
 import (
 	"encoding/json"
 	"time"
+	"unicode/utf8"
 
 	providerTransform "github.com/garyblankenship/wormhole/v2/providers/internal/transform"
 	"github.com/garyblankenship/wormhole/v2/types"
@@ -13,12 +14,15 @@ func (p *Provider) transformTextResponse(response *messageResponse) *types.TextR
 	text := ""
 	var thinking *types.Thinking
 	var toolCalls []types.ToolCall
+	var citations []types.Citation
 
 	// Extract content from response
 	for _, content := range response.Content {
 		switch content.Type {
 		case contentTypeText:
+			start := utf8.RuneCountInString(text)
 			text += content.Text
+			citations = appendTextCitations(citations, content.Citations, start, utf8.RuneCountInString(text))
 		case contentTypeThinking:
 			thinking = &types.Thinking{Content: content.Thinking, Signature: content.Signature, Provider: "anthropic"}
 		case contentTypeToolUse:
@@ -37,17 +41,53 @@ func (p *Provider) transformTextResponse(response *messageResponse) *types.TextR
 	}
 
 	return &types.TextResponse{
-		ID:           response.ID,
-		Model:        response.Model,
-		Text:         text,
-		Thinking:     thinking,
-		ToolCalls:    toolCalls,
-		FinishReason: p.mapStopReason(response.StopReason),
-		Usage:        p.convertUsage(response.Usage),
-		Created:      time.Now(),
+		ID:              response.ID,
+		Model:           response.Model,
+		Text:            text,
+		Thinking:        thinking,
+		ToolCalls:       toolCalls,
+		Citations:       citations,
+		FinishReason:    p.mapStopReason(response.StopReason),
+		Usage:           p.convertUsage(response.Usage),
+		Created:         time.Now(),
+		RawFinishReason: response.StopReason,
 	}
 }
 
+// appendTextCitations maps the citations of one text block; the block spans
+// characters [start, end) of the response text.
+func appendTextCitations(citations []types.Citation, blockCitations []textCitation, start, end int) []types.Citation {
+	for _, c := range blockCitations {
+		citation := types.Citation{
+			Type:       types.CitationTypeDocument,
+			Title:      c.DocumentTitle,
+			CitedText:  c.CitedText,
+			StartIndex: start,
+			EndIndex:   end,
+		}
+		switch c.Type {
+		case "web_search_result_location":
+			citation.Type, citation.URL, citation.Title = types.CitationTypeURL, c.URL, c.Title
+		case "search_result_location":
+			citation.URL, citation.Title = c.Source, c.Title
+			citation.SourceStart, citation.SourceEnd = c.StartBlockIndex, c.EndBlockIndex
+		case "char_location":
+			citation.DocumentIndex = c.DocumentIndex
+			citation.SourceStart, citation.SourceEnd = c.StartCharIndex, c.EndCharIndex
+		case "page_location":
+			citation.DocumentIndex = c.DocumentIndex
+			citation.SourceStart, citation.SourceEnd = c.StartPageNumber, c.EndPageNumber
+		case "content_block_location":
+			citation.DocumentIndex = c.DocumentIndex
+			citation.SourceStart, citation.SourceEnd = c.StartBlockIndex, c.EndBlockIndex
+		default:
+			continue
+		}
+		citations = append(citations, citation)
+	}
+	return citations
+}
+
 func (p *Provider) convertUsage(u messageUsage) *types.Usage {
 	return &types.Usage{
 		PromptTokens:     u.InputTokens,
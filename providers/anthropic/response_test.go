@@ -1,10 +1,13 @@
 package anthropic
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 func TestTransformTextResponse_ToolUsePopulatesArgumentsMap(t *testing.T) {
@@ -19,3 +22,55 @@ func TestTransformTextResponse_ToolUsePopulatesArgumentsMap(t *testing.T) {
 	require.NotNil(t, out.ToolCalls[0].Arguments, "non-streaming tool_use must populate the Arguments map (parity with streaming)")
 	assert.Equal(t, "sf", out.ToolCalls[0].Arguments["city"])
 }
+
+func TestTransformTextResponse_DocumentCitations(t *testing.T) {
+	t.Parallel()
+	var resp messageResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"content":[
+		{"type":"text","text":"Revenue grew 12%.","citations":[
+			{"type":"char_location","cited_text":"Revenue rose 12% year over year.","document_index":1,"document_title":"notes.txt","start_char_index":40,"end_char_index":72},
+			{"type":"page_location","cited_text":"12% growth","document_index":0,"document_title":"report.pdf","start_page_number":3,"end_page_number":4}
+		]}
+	]}`), &resp))
+
+	out := (&Provider{}).transformTextResponse(&resp)
+	assert.Equal(t, []types.Citation{
+		{
+			Type:          types.CitationTypeDocument,
+			Title:         "notes.txt",
+			DocumentIndex: 1,
+			CitedText:     "Revenue rose 12% year over year.",
+			EndIndex:      17,
+			SourceStart:   40,
+			SourceEnd:     72,
+		},
+		{
+			Type:        types.CitationTypeDocument,
+			Title:       "report.pdf",
+			CitedText:   "12% growth",
+			EndIndex:    17,
+			SourceStart: 3,
+			SourceEnd:   4,
+		},
+	}, out.Citations)
+}
+
+func TestTransformTextResponse_KeepsRawStopReason(t *testing.T) {
+	t.Parallel()
+	p := &Provider{}
+	refusal := p.transformTextResponse(&messageResponse{StopReason: "refusal"})
+	assert.Equal(t, types.FinishReasonContentFilter, refusal.FinishReason)
+	assert.Equal(t, "refusal", refusal.RawFinishReason)
+	assert.True(t, refusal.IsRefusal())
+	assert.False(t, refusal.IsEmpty(), "a refusal is an answer, not an empty response")
+
+	truncated := p.transformTextResponse(&messageResponse{StopReason: "max_tokens"})
+	assert.Equal(t, types.FinishReasonLength, truncated.FinishReason)
+	assert.Equal(t, "max_tokens", truncated.RawFinishReason)
+
+	chunk, err := p.parseStreamChunk([]byte(`{"type":"message_delta","delta":{"stop_reason":"refusal"},"usage":{"output_tokens":2}}`))
+	require.NoError(t, err)
+	require.NotNil(t, chunk.FinishReason)
+	assert.Equal(t, types.FinishReasonContentFilter, *chunk.FinishReason)
+	assert.Equal(t, "refusal", chunk.RawFinishReason)
+}
@@ -38,13 +38,15 @@ func (p *Provider) parseStreamChunk(data []byte) (*types.StreamChunk, error) {
 		if err := json.Unmarshal(data, &event); err != nil {
 			return nil, err
 		}
-		// Only tool_use blocks open a tool call; text/thinking blocks are no-ops
-		// here (their content arrives via content_block_delta).
-		if event.ContentBlock.Type == "tool_use" {
+		// Only tool_use and server_tool_use blocks open a tool call; text/thinking
+		// blocks are no-ops here (their content arrives via content_block_delta).
+		// A server tool call gets its own slot so its input fragments do not
+		// land on the previous tool call; the accumulator drops it at the end.
+		if event.ContentBlock.Type == contentTypeToolUse || event.ContentBlock.Type == contentTypeServerToolUse {
 			chunk.Delta = &types.ChunkDelta{
 				ToolCalls: []types.ToolCall{{
 					ID:   event.ContentBlock.ID,
-					Type: "tool_use",
+					Type: event.ContentBlock.Type,
 					Name: event.ContentBlock.Name,
 					Function: &types.ToolCallFunction{
 						Name:      event.ContentBlock.Name,
@@ -91,9 +93,14 @@ func (p *Provider) parseStreamChunk(data []byte) (*types.StreamChunk, error) {
 		if event.Delta.StopReason != "" {
 			reason := p.mapStopReason(event.Delta.StopReason)
 			chunk.FinishReason = &reason
+			chunk.RawFinishReason = event.Delta.StopReason
 		}
-		if event.Delta.Usage.InputTokens > 0 || event.Delta.Usage.OutputTokens > 0 {
-			chunk.Usage = p.convertUsage(event.Delta.Usage)
+		usage := event.Usage
+		if usage.InputTokens == 0 && usage.OutputTokens == 0 {
+			usage = event.Delta.Usage
+		}
+		if usage.InputTokens > 0 || usage.OutputTokens > 0 {
+			chunk.Usage = p.convertUsage(usage)
 		}
 
 	case "message_stop":
@@ -65,6 +65,9 @@ func (s *streamFragmentAccumulator) finish() []types.ToolCall {
 	}
 	out := make([]types.ToolCall, 0, len(s.calls))
 	for _, acc := range s.calls {
+		if acc.typ == contentTypeServerToolUse {
+			continue
+		}
 		argsMap, parseErrMsg := types.ParseToolArgs(string(acc.args), map[string]any{})
 		toolCall := types.ToolCall{
 			ID:        acc.id,
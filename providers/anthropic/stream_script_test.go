@@ -0,0 +1,76 @@
+package anthropic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func streamScript(t *testing.T, script testutil.StreamScript) []types.TextChunk {
+	t.Helper()
+	server := testutil.StreamServer(t, testutil.AnthropicStream, script)
+	provider := New(types.ProviderConfig{APIKey: "test-key", BaseURL: server.URL})
+	stream, err := provider.Stream(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "claude-sonnet-4-5"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+	chunks, closed := testutil.DrainStream(stream, 5*time.Second)
+	require.True(t, closed, "stream must close")
+	return chunks
+}
+
+// Usage is split across message_start (input, cache) and message_delta
+// (output); the merged result must carry both halves.
+func TestStreamScriptedToolCallAndUsage(t *testing.T) {
+	t.Parallel()
+	chunks := streamScript(t, testutil.StreamScript{
+		Text:      []string{"Hel", "lo"},
+		ToolCalls: []testutil.StreamToolCall{{ID: "toolu_1", Name: "get_weather", Arguments: []string{`{"city":`, `"Paris"}`}}},
+		Usage:     &types.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, CacheReadTokens: 4},
+	})
+	for _, chunk := range chunks {
+		require.NoError(t, chunk.Error)
+	}
+
+	merged := testutil.MergeTextChunks(chunks)
+	assert.Equal(t, "Hello", merged.Text)
+	assert.Equal(t, types.FinishReasonToolCalls, merged.FinishReason)
+	require.Len(t, merged.ToolCalls, 1)
+	assert.Equal(t, "toolu_1", merged.ToolCalls[0].ID)
+	assert.Equal(t, map[string]any{"city": "Paris"}, merged.ToolCalls[0].Arguments)
+	require.NotNil(t, merged.Usage)
+	assert.Equal(t, 10, merged.Usage.PromptTokens)
+	assert.Equal(t, 5, merged.Usage.CompletionTokens)
+	assert.Equal(t, 4, merged.Usage.CacheReadTokens)
+}
+
+func TestStreamScriptedFailures(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		script testutil.StreamScript
+		code   types.ErrorCode
+	}{
+		{"error event", testutil.StreamScript{Text: []string{"Hel"}, Error: "overloaded"}, types.ErrorCodeProvider},
+		{"no message_stop", testutil.StreamScript{Text: []string{"Hel"}, Truncate: true}, types.ErrorCodeNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chunks := streamScript(t, tt.script)
+			require.NotEmpty(t, chunks)
+			assert.Equal(t, "Hel", testutil.MergeTextChunks(chunks[:len(chunks)-1]).Text)
+			wormholeErr, ok := types.AsWormholeError(chunks[len(chunks)-1].Error)
+			require.True(t, ok, "final chunk must carry a *types.WormholeError")
+			assert.Equal(t, tt.code, wormholeErr.Code)
+			assert.Equal(t, "anthropic", wormholeErr.Provider)
+		})
+	}
+}
@@ -1,7 +1,13 @@
 package anthropic
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 // message_start carries Anthropic's input + cache tokens; only output_tokens
@@ -30,3 +36,41 @@ func TestParseStreamChunkMessageStartCapturesUsage(t *testing.T) {
 		t.Fatalf("CacheWriteTokens = %d, want 20", chunk.Usage.CacheWriteTokens)
 	}
 }
+
+// Anthropic reports input tokens on message_start and output tokens on a
+// top-level message_delta usage; the final chunk must carry both.
+func TestStreamFinalChunkMergesUsage(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"model\":\"claude-sonnet-4-5\",\"usage\":{\"input_tokens\":25,\"output_tokens\":1,\"cache_read_input_tokens\":10}}}\n\n")
+		_, _ = io.WriteString(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"hi\"}}\n\n")
+		_, _ = io.WriteString(w, "event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},\"usage\":{\"output_tokens\":12}}\n\n")
+		_, _ = io.WriteString(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	}))
+	defer server.Close()
+
+	provider := New(types.ProviderConfig{APIKey: "test-key", BaseURL: server.URL})
+	stream, err := provider.Stream(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "claude-sonnet-4-5"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+
+	var final types.TextChunk
+	for chunk := range stream {
+		if chunk.Error != nil {
+			t.Fatalf("stream error: %v", chunk.Error)
+		}
+		final = chunk
+	}
+	if !final.IsDone() || final.Usage == nil {
+		t.Fatalf("final chunk = %+v, want done with usage", final)
+	}
+	want := types.Usage{PromptTokens: 25, CompletionTokens: 12, TotalTokens: 37, CacheReadTokens: 10}
+	if *final.Usage != want {
+		t.Fatalf("final usage = %+v, want %+v", *final.Usage, want)
+	}
+}
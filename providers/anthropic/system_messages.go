@@ -7,8 +7,8 @@ import (
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
-// mergeSystemMessages merges any RoleSystem messages from msgs into base.
-// Anthropic's transformMessages skips RoleSystem (system must travel in the
+// mergeSystemMessages merges any system or developer messages from msgs into
+// base. Anthropic's transformMessages skips them (system must travel in the
 // top-level "system" field), so without this merge a caller-provided system
 // message in request.Messages would be silently dropped.
 func mergeSystemMessages(base string, msgs []types.Message) string {
@@ -17,7 +17,7 @@ func mergeSystemMessages(base string, msgs []types.Message) string {
 		parts = append(parts, base)
 	}
 	for _, m := range msgs {
-		if m.GetRole() != types.RoleSystem {
+		if !m.GetRole().IsSystem() {
 			continue
 		}
 		switch c := m.GetContent().(type) {
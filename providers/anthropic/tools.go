@@ -60,6 +60,24 @@ func (p *Provider) transformTools(tools []types.Tool) ([]map[string]any, error)
 	return result, nil
 }
 
+// webSearchTool builds Anthropic's web search server tool.
+func webSearchTool(search *types.WebSearch) map[string]any {
+	tool := map[string]any{
+		"type": "web_search_20250305",
+		"name": "web_search",
+	}
+	if search.MaxUses > 0 {
+		tool["max_uses"] = search.MaxUses
+	}
+	if len(search.AllowedDomains) > 0 {
+		tool["allowed_domains"] = search.AllowedDomains
+	}
+	if len(search.BlockedDomains) > 0 {
+		tool["blocked_domains"] = search.BlockedDomains
+	}
+	return tool
+}
+
 // transformToolChoice converts the internal tool choice to Anthropic's wire format.
 func (p *Provider) transformToolChoice(tc *types.ToolChoice) map[string]any {
 	switch tc.Type {
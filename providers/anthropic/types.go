@@ -20,6 +20,34 @@ type contentPart struct {
 	ID        string    `json:"id,omitempty"`
 	Name      string    `json:"name,omitempty"`
 	Input     toolInput `json:"input,omitempty"`
+	// Citations attributes a text block to web search results.
+	Citations []textCitation `json:"citations,omitempty"`
+}
+
+// textCitation is one entry of a text block's citations. The location
+// fields present depend on Type.
+type textCitation struct {
+	Type      string `json:"type"`
+	CitedText string `json:"cited_text,omitempty"`
+
+	// web_search_result_location
+	URL   string `json:"url,omitempty"`
+	Title string `json:"title,omitempty"`
+
+	// char_location, page_location, content_block_location
+	DocumentIndex   int    `json:"document_index"`
+	DocumentTitle   string `json:"document_title,omitempty"`
+	StartCharIndex  int    `json:"start_char_index"`
+	EndCharIndex    int    `json:"end_char_index"`
+	StartPageNumber int    `json:"start_page_number"`
+	EndPageNumber   int    `json:"end_page_number"`
+
+	// content_block_location, search_result_location
+	StartBlockIndex int `json:"start_block_index"`
+	EndBlockIndex   int `json:"end_block_index"`
+
+	// search_result_location
+	Source string `json:"source,omitempty"`
 }
 
 type toolInput map[string]any
@@ -57,6 +85,9 @@ type messageDeltaEvent struct {
 		StopReason string       `json:"stop_reason,omitempty"`
 		Usage      messageUsage `json:"usage,omitempty"`
 	} `json:"delta"`
+	// Usage is where the Messages API reports cumulative usage; Delta.Usage
+	// is accepted for proxies that nest it.
+	Usage messageUsage `json:"usage,omitempty"`
 }
 
 type contentBlockStartEvent struct {
@@ -0,0 +1,66 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestBuildMessagePayloadAddsWebSearchTool(t *testing.T) {
+	t.Parallel()
+	p := New(types.ProviderConfig{APIKey: "test-key"})
+	payload, err := p.buildMessagePayload(&types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "claude-sonnet-4-5"},
+		Messages:    []types.Message{types.NewUserMessage("What changed in Go 1.25?")},
+		WebSearch:   &types.WebSearch{MaxUses: 3, AllowedDomains: []string{"go.dev"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{
+		"type":            "web_search_20250305",
+		"name":            "web_search",
+		"max_uses":        3,
+		"allowed_domains": []string{"go.dev"},
+	}}, payload["tools"])
+	assert.NotContains(t, payload, "tool_choice")
+}
+
+func TestTransformTextResponseMapsWebSearchCitations(t *testing.T) {
+	t.Parallel()
+	var resp messageResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"msg_1","model":"claude-sonnet-4-5","stop_reason":"end_turn","content":[
+		{"type":"text","text":"I'll check. "},
+		{"type":"server_tool_use","id":"srvtoolu_1","name":"web_search","input":{"query":"go 1.25"}},
+		{"type":"web_search_tool_result","tool_use_id":"srvtoolu_1","content":[{"type":"web_search_result","url":"https://go.dev/doc/go1.25","title":"Go 1.25"}]},
+		{"type":"text","text":"Go 1.25 shipped in August.","citations":[{"type":"web_search_result_location","url":"https://go.dev/doc/go1.25","title":"Go 1.25","cited_text":"Go 1.25 was released in August 2025."}]}
+	]}`), &resp))
+
+	out := (&Provider{}).transformTextResponse(&resp)
+	assert.Equal(t, "I'll check. Go 1.25 shipped in August.", out.Text)
+	assert.Empty(t, out.ToolCalls, "server tool calls are not client tool calls")
+	assert.Equal(t, []types.Citation{{
+		Type:       types.CitationTypeURL,
+		URL:        "https://go.dev/doc/go1.25",
+		Title:      "Go 1.25",
+		CitedText:  "Go 1.25 was released in August 2025.",
+		StartIndex: 12,
+		EndIndex:   38,
+	}}, out.Citations)
+}
+
+func TestStreamAccumulatorDropsServerToolUse(t *testing.T) {
+	t.Parallel()
+	acc := newStreamFragmentAccumulator()
+	acc.add([]types.ToolCall{{ID: "toolu_1", Type: contentTypeToolUse, Name: "lookup", Function: &types.ToolCallFunction{}}})
+	acc.add([]types.ToolCall{{Function: &types.ToolCallFunction{Arguments: `{"id":1}`}}})
+	acc.add([]types.ToolCall{{ID: "srvtoolu_1", Type: contentTypeServerToolUse, Name: "web_search", Function: &types.ToolCallFunction{}}})
+	acc.add([]types.ToolCall{{Function: &types.ToolCallFunction{Arguments: `{"query":"go"}`}}})
+
+	calls := acc.finish()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "toolu_1", calls[0].ID)
+	assert.Equal(t, `{"id":1}`, calls[0].Function.Arguments)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/garyblankenship/wormhole/v2/providers"
+	providerstream "github.com/garyblankenship/wormhole/v2/providers/internal/stream"
 	transform "github.com/garyblankenship/wormhole/v2/providers/internal/transform"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
@@ -66,8 +67,10 @@ func (g *Gemini) SupportedCapabilities() []types.ModelCapability {
 		types.CapabilityStructured,
 		types.CapabilityEmbeddings,
 		types.CapabilityImages,
+		types.CapabilityAudio,
 		types.CapabilityStream,
 		types.CapabilityFunctions,
+		types.CapabilityWebSearch,
 	}
 }
 
@@ -77,6 +80,7 @@ func (g *Gemini) Text(ctx context.Context, request types.TextRequest) (*types.Te
 	if err != nil {
 		return nil, err
 	}
+	schemaWarnings := g.downgradeSchemas(payload)
 
 	modelName := normalizeModelResource(request.Model)
 	endpoint := fmt.Sprintf("%s/models/%s:generateContent",
@@ -94,27 +98,14 @@ func (g *Gemini) Text(ctx context.Context, request types.TextRequest) (*types.Te
 		return nil, err
 	}
 	resp.Provider = g.Name()
+	resp.Metadata = providers.AddSchemaWarnings(resp.Metadata, schemaWarnings)
 	return resp, nil
 }
 
-// stampProvider sets Provider on the terminal chunk. Sole closer of out;
-// exits when the upstream channel closes.
+// stampProvider ends the stream pipeline with providerstream.Finalize, which
+// stamps the terminal chunk, types errors, and closes promptly on cancellation.
 func (g *Gemini) stampProvider(ctx context.Context, in <-chan types.TextChunk) <-chan types.TextChunk {
-	out := make(chan types.TextChunk)
-	go func() {
-		defer close(out)
-		for chunk := range in {
-			if chunk.IsDone() {
-				chunk.Provider = g.Name()
-			}
-			select {
-			case out <- chunk:
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-	return out
+	return providerstream.Finalize(ctx, g.Name(), in)
 }
 
 // Stream generates streaming text using Gemini models
@@ -123,6 +114,7 @@ func (g *Gemini) Stream(ctx context.Context, request types.TextRequest) (<-chan
 	if err != nil {
 		return nil, err
 	}
+	g.downgradeSchemas(payload)
 
 	modelName := normalizeModelResource(request.Model)
 	// alt=sse is REQUIRED: streamGenerateContent defaults to a JSON-array stream,
@@ -138,7 +130,7 @@ func (g *Gemini) Stream(ctx context.Context, request types.TextRequest) (<-chan
 		return nil, err
 	}
 
-	return g.stampProvider(ctx, g.handleStream(ctx, stream)), nil
+	return g.stampProvider(ctx, providerstream.FinalUsage(ctx, g.handleStream(ctx, stream))), nil
 }
 
 // Structured generates structured output using Gemini models
@@ -147,6 +139,7 @@ func (g *Gemini) Structured(ctx context.Context, request types.StructuredRequest
 	if err != nil {
 		return nil, err
 	}
+	schemaWarnings := g.downgradeSchemas(payload)
 
 	modelName := normalizeModelResource(request.Model)
 	endpoint := fmt.Sprintf("%s/models/%s:generateContent",
@@ -159,16 +152,32 @@ func (g *Gemini) Structured(ctx context.Context, request types.StructuredRequest
 		return nil, err
 	}
 
-	return g.transformStructuredResponse(&response, request.Schema)
+	resp, err := g.transformStructuredResponse(&response, request.Schema)
+	if err != nil {
+		return nil, err
+	}
+	resp.Metadata = providers.AddSchemaWarnings(resp.Metadata, schemaWarnings)
+	return resp, nil
 }
 
-// Audio is not supported by Gemini
+// Audio synthesizes speech with a Gemini TTS model. Speech-to-text is not
+// supported; send audio to Text as media and ask for a transcript instead.
 func (g *Gemini) Audio(ctx context.Context, request types.AudioRequest) (*types.AudioResponse, error) {
-	return nil, g.NotImplementedError("Audio")
+	switch request.Type {
+	case types.AudioRequestTypeSTT:
+		return nil, g.NotImplementedError("speech-to-text")
+	case types.AudioRequestTypeTranslation:
+		return nil, g.NotImplementedError("translation")
+	}
+	return g.textToSpeech(ctx, request)
 }
 
-// Images generates images using Gemini's native generateContent endpoint.
+// Images generates images. Imagen models use the :predict endpoint; other
+// models, such as gemini-2.5-flash-image, use generateContent.
 func (g *Gemini) Images(ctx context.Context, request types.ImagesRequest) (*types.ImagesResponse, error) {
+	if isImagenModel(request.Model) {
+		return g.imagenImages(ctx, request)
+	}
 	payload, err := g.buildImagesPayload(request)
 	if err != nil {
 		return nil, err
@@ -219,7 +219,7 @@ func TestTransformResponses_SurfacePromptBlockReason(t *testing.T) {
 	}
 }
 
-func TestProcessStreamCandidate_SyntheticToolCallIDs(t *testing.T) {
+func TestProcessStreamCandidate_LeavesToolCallIDsToStream(t *testing.T) {
 	t.Parallel()
 
 	provider := New("test-key", types.ProviderConfig{})
@@ -235,14 +235,15 @@ func TestProcessStreamCandidate_SyntheticToolCallIDs(t *testing.T) {
 
 	chunks := provider.processStreamCandidate(cand)
 
-	var ids []string
+	var args []any
 	for _, c := range chunks {
 		if c.ToolCall != nil {
-			ids = append(ids, c.ToolCall.ID)
+			args = append(args, c.ToolCall.Arguments["q"])
 			assert.Equal(t, "lookup", c.ToolCall.Name)
+			assert.Empty(t, c.ToolCall.ID, "handleStream assigns IDs across the stream")
 		}
 	}
-	assert.Equal(t, []string{"gemini-call-0-lookup", "gemini-call-1-lookup"}, ids)
+	assert.Equal(t, []any{"a", "b"}, args)
 }
 
 func TestTransformTextResponse_ThoughtPartsRouteToThinking(t *testing.T) {
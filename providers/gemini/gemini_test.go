@@ -848,15 +848,17 @@ func TestGeminiProvider_UnsupportedMethods(t *testing.T) {
 
 	ctx := context.Background()
 
-	t.Run("Audio not supported", func(t *testing.T) {
+	t.Run("speech-to-text not supported", func(t *testing.T) {
 		t.Parallel()
 		audioReq := types.AudioRequest{
+			Type:  types.AudioRequestTypeSTT,
 			Model: "gemini-pro",
+			Input: []byte("audio"),
 		}
 
 		_, err := provider.Audio(ctx, audioReq)
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "gemini provider does not support Audio")
+		assert.Contains(t, err.Error(), "gemini provider does not support speech-to-text")
 	})
 
 }
@@ -1187,6 +1189,7 @@ func TestGeminiProvider_FinishReasonMapping(t *testing.T) {
 			require.NoError(t, err)
 			require.NotNil(t, response)
 			assert.Equal(t, tc.expected, response.FinishReason)
+			assert.Equal(t, tc.geminiReason, response.RawFinishReason)
 		})
 	}
 }
@@ -0,0 +1,26 @@
+package gemini
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+	"github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+// TestGoldenPayloads pins the generateContent wire format.
+// Regenerate with WORMHOLE_UPDATE_GOLDEN=1 and review the fixture diff.
+func TestGoldenPayloads(t *testing.T) {
+	t.Parallel()
+
+	for name, request := range wormholetest.GoldenTextRequests("gemini-2.5-flash") {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			wormholetest.AssertGoldenPayload(t, filepath.Join("testdata", "golden", name+".json"), func(baseURL string) {
+				config := types.NewProviderConfig("test-key").WithBaseURL(baseURL).WithNoRetries()
+				_, _ = New("test-key", config).Text(context.Background(), request)
+			})
+		})
+	}
+}
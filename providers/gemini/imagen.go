@@ -0,0 +1,153 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// imagenAspectRatios are the aspect ratios Imagen accepts.
+var imagenAspectRatios = []string{"1:1", "3:4", "4:3", "9:16", "16:9"}
+
+// isImagenModel reports whether model is served by the Imagen predict
+// endpoint rather than generateContent.
+func isImagenModel(model string) bool {
+	model = strings.TrimPrefix(strings.TrimPrefix(model, "google/"), "models/")
+	return strings.HasPrefix(model, "imagen-")
+}
+
+type imagenResponse struct {
+	Predictions []struct {
+		BytesBase64Encoded string `json:"bytesBase64Encoded"`
+		MimeType           string `json:"mimeType"`
+		RAIFilteredReason  string `json:"raiFilteredReason"`
+	} `json:"predictions"`
+}
+
+// imagenImages generates images with an Imagen model through :predict.
+func (g *Gemini) imagenImages(ctx context.Context, request types.ImagesRequest) (*types.ImagesResponse, error) {
+	payload, err := g.buildImagenPayload(request)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("%s/models/%s:predict", g.GetBaseURL(), normalizeModelResource(request.Model))
+
+	var response imagenResponse
+	if err := g.DoRequest(ctx, "POST", endpoint, payload, &response); err != nil {
+		return nil, err
+	}
+
+	var images []types.GeneratedImage
+	var mimeTypes, filtered []string
+	for _, prediction := range response.Predictions {
+		if prediction.BytesBase64Encoded == "" {
+			if prediction.RAIFilteredReason != "" {
+				filtered = append(filtered, prediction.RAIFilteredReason)
+			}
+			continue
+		}
+		images = append(images, types.GeneratedImage{B64JSON: prediction.BytesBase64Encoded})
+		mimeTypes = append(mimeTypes, prediction.MimeType)
+	}
+	if len(images) == 0 {
+		if len(filtered) > 0 {
+			return nil, g.ProviderError("all images were filtered", filtered...)
+		}
+		return nil, g.ProviderError("no images in response")
+	}
+
+	metadata := map[string]any{
+		"provider":   "gemini",
+		"mime_types": mimeTypes,
+	}
+	if len(filtered) > 0 {
+		metadata["filtered_reasons"] = filtered
+	}
+	return &types.ImagesResponse{
+		Model:    request.Model,
+		Images:   images,
+		Created:  time.Now(),
+		Metadata: metadata,
+	}, nil
+}
+
+// buildImagenPayload maps the request onto Imagen's instances/parameters
+// shape. N becomes sampleCount and Size becomes the nearest aspect ratio;
+// the aspect_ratio, image_size, and person_generation provider options set
+// the Imagen parameters directly, and other options pass through.
+func (g *Gemini) buildImagenPayload(request types.ImagesRequest) (map[string]any, error) {
+	if request.ResponseFormat == "url" {
+		return nil, g.ValidationError("Imagen returns image data only; url response format is not supported")
+	}
+	parameters := map[string]any{}
+	if request.N > 0 {
+		parameters["sampleCount"] = request.N
+	}
+	if request.Size != "" {
+		ratio, err := imagenAspectRatio(request.Size)
+		if err != nil {
+			return nil, g.ValidationError(err.Error())
+		}
+		parameters["aspectRatio"] = ratio
+	}
+
+	for k, v := range g.Config.MergedProviderOptions(request.Model, request.ProviderOptions) {
+		switch k {
+		case "images":
+			return nil, g.ValidationError("Imagen does not take reference images; use a Gemini image model")
+		case "aspect_ratio":
+			parameters["aspectRatio"] = v
+		case "image_size":
+			parameters["imageSize"] = v
+		case "person_generation":
+			parameters["personGeneration"] = v
+		default:
+			parameters[k] = v
+		}
+	}
+
+	payload := map[string]any{
+		"instances": []map[string]any{{"prompt": request.Prompt}},
+	}
+	if len(parameters) > 0 {
+		payload["parameters"] = parameters
+	}
+	return payload, nil
+}
+
+// imagenAspectRatio converts a WIDTHxHEIGHT size, or an aspect ratio given
+// as is, to the Imagen aspect ratio nearest to it.
+func imagenAspectRatio(size string) (string, error) {
+	for _, ratio := range imagenAspectRatios {
+		if size == ratio {
+			return ratio, nil
+		}
+	}
+	width, height, ok := strings.Cut(size, "x")
+	w, errW := strconv.Atoi(width)
+	h, errH := strconv.Atoi(height)
+	if !ok || errW != nil || errH != nil || w <= 0 || h <= 0 {
+		return "", fmt.Errorf("image size %q must be WIDTHxHEIGHT or one of %s", size, strings.Join(imagenAspectRatios, ", "))
+	}
+	// Sizes such as 1792x1024 are close to, not exactly, a supported ratio;
+	// take the nearest one within 5%.
+	want := float64(w) / float64(h)
+	best, bestDiff := "", math.Inf(1)
+	for _, ratio := range imagenAspectRatios {
+		rw, rh, _ := strings.Cut(ratio, ":")
+		a, _ := strconv.Atoi(rw)
+		b, _ := strconv.Atoi(rh)
+		if diff := math.Abs(want/(float64(a)/float64(b)) - 1); diff < bestDiff {
+			best, bestDiff = ratio, diff
+		}
+	}
+	if bestDiff <= 0.05 {
+		return best, nil
+	}
+	return "", fmt.Errorf("image size %q has no Imagen aspect ratio; use one of %s", size, strings.Join(imagenAspectRatios, ", "))
+}
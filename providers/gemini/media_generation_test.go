@@ -0,0 +1,111 @@
+package gemini_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/providers/gemini"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestImagenImagesUsePredict(t *testing.T) {
+	t.Parallel()
+	var path string
+	var body map[string]any
+	server := testutil.MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = io.WriteString(w, `{"predictions":[
+			{"bytesBase64Encoded":"aW1n","mimeType":"image/png"},
+			{"raiFilteredReason":"filtered for safety"}]}`)
+	})
+	provider := gemini.New("test-key", types.ProviderConfig{BaseURL: server.URL})
+
+	resp, err := provider.Images(context.Background(), types.ImagesRequest{
+		Model:           "imagen-4.0-generate-001",
+		Prompt:          "a lighthouse",
+		N:               2,
+		Size:            "1792x1024",
+		ProviderOptions: map[string]any{"person_generation": "dont_allow"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/models/imagen-4.0-generate-001:predict", path)
+	require.Len(t, resp.Images, 1)
+	assert.Equal(t, "aW1n", resp.Images[0].B64JSON)
+	assert.Equal(t, []string{"filtered for safety"}, resp.Metadata["filtered_reasons"])
+
+	assert.Equal(t, []any{map[string]any{"prompt": "a lighthouse"}}, body["instances"])
+	assert.Equal(t, map[string]any{
+		"sampleCount":      float64(2),
+		"aspectRatio":      "16:9",
+		"personGeneration": "dont_allow",
+	}, body["parameters"])
+}
+
+func TestImagenRejectsUnmappableSize(t *testing.T) {
+	t.Parallel()
+	provider := gemini.New("test-key", types.ProviderConfig{BaseURL: "http://127.0.0.1:0"})
+	_, err := provider.Images(context.Background(), types.ImagesRequest{
+		Model:  "imagen-4.0-generate-001",
+		Prompt: "a lighthouse",
+		Size:   "1000x700",
+	})
+	assert.ErrorContains(t, err, "has no Imagen aspect ratio")
+}
+
+func TestTextToSpeech(t *testing.T) {
+	t.Parallel()
+	pcm := []byte{1, 0, 2, 0, 3, 0}
+	var body map[string]any
+	server := testutil.MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/models/gemini-2.5-flash-preview-tts:generateContent", r.URL.Path)
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_ = json.NewEncoder(w).Encode(map[string]any{"candidates": []any{map[string]any{
+			"content": map[string]any{"role": "model", "parts": []any{map[string]any{
+				"inlineData": map[string]any{"mimeType": "audio/L16;codec=pcm;rate=16000", "data": base64.StdEncoding.EncodeToString(pcm)},
+			}}},
+		}}})
+	})
+	provider := gemini.New("test-key", types.ProviderConfig{BaseURL: server.URL})
+	request := types.AudioRequest{
+		Type:  types.AudioRequestTypeTTS,
+		Model: "gemini-2.5-flash-preview-tts",
+		Input: "Say cheerfully: have a wonderful day!",
+		Voice: "Kore",
+	}
+
+	resp, err := provider.Audio(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "wav", resp.Format)
+	require.Len(t, resp.Audio, 44+len(pcm))
+	assert.Equal(t, "RIFF", string(resp.Audio[:4]))
+	assert.Equal(t, uint32(16000), binary.LittleEndian.Uint32(resp.Audio[24:]), "sample rate comes from the MIME type")
+	assert.Equal(t, pcm, resp.Audio[44:])
+
+	config := body["generationConfig"].(map[string]any)
+	assert.Equal(t, []any{"AUDIO"}, config["responseModalities"])
+	assert.Equal(t, "Kore", config["speechConfig"].(map[string]any)["voiceConfig"].(map[string]any)["prebuiltVoiceConfig"].(map[string]any)["voiceName"])
+
+	request.ResponseFormat = "pcm"
+	resp, err = provider.Audio(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, pcm, resp.Audio)
+
+	request.ResponseFormat = "mp3"
+	_, err = provider.Audio(context.Background(), request)
+	assert.ErrorContains(t, err, "wav or pcm")
+
+	request.ResponseFormat = ""
+	request.Speed = 1.5
+	_, err = provider.Audio(context.Background(), request)
+	assert.ErrorContains(t, err, "no speed setting")
+}
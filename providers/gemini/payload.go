@@ -13,6 +13,18 @@ func (g *Gemini) buildTextPayload(request types.TextRequest) (map[string]any, er
 	if request.ParallelToolCalls != nil {
 		return nil, g.ValidationError("parallel_tool_calls is not supported by Gemini")
 	}
+	if request.Logprobs {
+		return nil, g.ValidationError("logprobs are not supported by Gemini")
+	}
+	if request.N > 1 {
+		return nil, g.ValidationError("n above 1 is not supported by Gemini")
+	}
+	if request.Audio != nil {
+		return nil, g.ValidationError("audio output is not supported on Gemini text requests; use Audio with a TTS model")
+	}
+	if err := g.CheckSamplingParams(request.BaseRequest, "Gemini", types.SamplingTopK); err != nil {
+		return nil, err
+	}
 	prepared, _, prepareErr := providers.PrepareMessages(request.Messages)
 	if prepareErr != nil {
 		return nil, prepareErr
@@ -62,6 +74,9 @@ func (g *Gemini) buildTextPayload(request types.TextRequest) (map[string]any, er
 	if request.Seed != nil {
 		generationConfig["seed"] = *request.Seed
 	}
+	if request.TopK != nil {
+		generationConfig["topK"] = *request.TopK
+	}
 	if thinking := geminiThinkingConfig(request.Reasoning); len(thinking) > 0 {
 		generationConfig["thinkingConfig"] = thinking
 	}
@@ -80,6 +95,12 @@ func (g *Gemini) buildTextPayload(request types.TextRequest) (map[string]any, er
 			payload["toolConfig"] = g.transformToolChoice(request.ToolChoice)
 		}
 	}
+	// Grounding with Google Search takes no options; WebSearch limits are
+	// not applied.
+	if request.WebSearch != nil {
+		tools, _ := payload["tools"].([]map[string]any)
+		payload["tools"] = append(tools, map[string]any{"google_search": map[string]any{}})
+	}
 
 	for k, v := range g.Config.MergedProviderOptions(request.Model, request.ProviderOptions) {
 		if k == "generationConfig" {
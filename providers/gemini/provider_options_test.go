@@ -1,6 +1,7 @@
 package gemini
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/garyblankenship/wormhole/v2/types"
@@ -62,6 +63,7 @@ func TestTypedSamplingControlsReachGenerationConfig(t *testing.T) {
 	frequency := float32(0.4)
 	presence := float32(-0.3)
 	seed := 42
+	topK := 40
 	provider := New("key", types.NewProviderConfig("key"))
 
 	payload, err := provider.buildTextPayload(types.TextRequest{
@@ -70,6 +72,7 @@ func TestTypedSamplingControlsReachGenerationConfig(t *testing.T) {
 			FrequencyPenalty: &frequency,
 			PresencePenalty:  &presence,
 			Seed:             &seed,
+			TopK:             &topK,
 		},
 		Messages: []types.Message{types.NewUserMessage("hi")},
 	})
@@ -77,7 +80,7 @@ func TestTypedSamplingControlsReachGenerationConfig(t *testing.T) {
 		t.Fatalf("buildTextPayload returned error: %v", err)
 	}
 	generationConfig := payload["generationConfig"].(map[string]any)
-	if generationConfig["frequencyPenalty"] != frequency || generationConfig["presencePenalty"] != presence || generationConfig["seed"] != seed {
+	if generationConfig["frequencyPenalty"] != frequency || generationConfig["presencePenalty"] != presence || generationConfig["seed"] != seed || generationConfig["topK"] != topK {
 		t.Fatalf("generationConfig = %#v", generationConfig)
 	}
 
@@ -89,6 +92,24 @@ func TestTypedSamplingControlsReachGenerationConfig(t *testing.T) {
 	if err == nil {
 		t.Fatal("Gemini accepted unsupported parallel_tool_calls")
 	}
+
+	_, err = provider.buildTextPayload(types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gemini-test"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+		Logprobs:    true,
+	})
+	if err == nil {
+		t.Fatal("Gemini accepted unsupported logprobs")
+	}
+
+	minP := float32(0.05)
+	_, err = provider.buildTextPayload(types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gemini-test", MinP: &minP},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "min_p is not supported by Gemini") {
+		t.Fatalf("Gemini min_p error = %v", err)
+	}
 }
 
 func TestProviderOptionsGenerationConfigMergesIntoTextPayload(t *testing.T) {
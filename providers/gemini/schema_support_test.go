@@ -0,0 +1,76 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/providers"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestStructuredDowngradesUnsupportedSchema(t *testing.T) {
+	t.Parallel()
+
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"{\"id\":\"a1\"}"}]}}]}`))
+	}))
+	defer server.Close()
+
+	g := New("key", types.ProviderConfig{BaseURL: server.URL})
+	resp, err := g.Structured(context.Background(), types.StructuredRequest{
+		BaseRequest: types.BaseRequest{Model: "gemini-2.5-flash"},
+		Messages:    []types.Message{types.NewUserMessage("id?")},
+		Schema: []byte(`{
+			"type": "object",
+			"properties": {
+				"id": {"oneOf": [{"type": "string"}, {"type": "integer"}]},
+				"email": {"type": "string", "format": "email"}
+			},
+			"additionalProperties": false
+		}`),
+	})
+	require.NoError(t, err)
+
+	schema := sent["generationConfig"].(map[string]any)["responseSchema"].(map[string]any)
+	props := schema["properties"].(map[string]any)
+	assert.Contains(t, props["id"], "anyOf")
+	assert.NotContains(t, props["email"], "format")
+	assert.NotContains(t, schema, "additionalProperties")
+
+	warnings, ok := resp.Metadata[providers.SchemaWarningsKey].([]string)
+	require.True(t, ok)
+	assert.Contains(t, warnings, "response schema #/properties/id: oneOf rewritten as anyOf; exclusivity is not enforced")
+	assert.Len(t, warnings, 3)
+}
+
+func TestDowngradeSchemasHonorsConfiguredProfile(t *testing.T) {
+	t.Parallel()
+
+	g := New("key", types.ProviderConfig{SchemaSupport: &types.SchemaSupport{AnyOf: true, OneOf: true, AdditionalProperties: true}})
+	payload, err := g.buildTextPayload(types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gemini-2.5-flash"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+		Tools: []types.Tool{{
+			Name: "lookup",
+			InputSchema: map[string]any{
+				"type":                 "object",
+				"properties":           map[string]any{"q": map[string]any{"oneOf": []any{map[string]any{"type": "string"}}}},
+				"additionalProperties": false,
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, g.downgradeSchemas(payload))
+	params := payload["tools"].([]map[string]any)[0]["functionDeclarations"].([]map[string]any)[0]["parameters"].(map[string]any)
+	assert.Equal(t, false, params["additionalProperties"])
+}
@@ -0,0 +1,140 @@
+package gemini
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// defaultSpeechSampleRate is the rate Gemini TTS models speak at when the
+// response MIME type does not say.
+const defaultSpeechSampleRate = 24000
+
+// textToSpeech synthesizes speech with a Gemini TTS model, such as
+// gemini-2.5-flash-preview-tts. Voice is a prebuilt voice name ("Kore",
+// "Puck", ...); tone and pace are steered by the input text itself. The
+// model returns 16-bit mono PCM, delivered as a WAV file unless
+// ResponseFormat is "pcm".
+func (g *Gemini) textToSpeech(ctx context.Context, request types.AudioRequest) (*types.AudioResponse, error) {
+	input, ok := request.Input.(string)
+	if !ok || input == "" {
+		return nil, g.ValidationError("text-to-speech input must be a non-empty string")
+	}
+	if request.Speed != 0 {
+		return nil, g.ValidationError("Gemini TTS has no speed setting; describe the pace in the input instead")
+	}
+	format := request.ResponseFormat
+	switch format {
+	case "":
+		format = "wav"
+	case "wav", "pcm":
+	default:
+		return nil, g.ValidationErrorf("Gemini TTS returns wav or pcm audio, not %q", format)
+	}
+
+	generationConfig := map[string]any{"responseModalities": []string{"AUDIO"}}
+	if request.Voice != "" {
+		generationConfig["speechConfig"] = map[string]any{
+			"voiceConfig": map[string]any{
+				"prebuiltVoiceConfig": map[string]any{"voiceName": request.Voice},
+			},
+		}
+	}
+	payload := map[string]any{
+		"contents":         []map[string]any{{"parts": []map[string]any{{"text": input}}}},
+		"generationConfig": generationConfig,
+	}
+	for k, v := range g.Config.MergedProviderOptions(request.Model, request.ProviderOptions) {
+		if opts, ok := v.(map[string]any); ok && k == "generationConfig" {
+			for optKey, optValue := range opts {
+				generationConfig[optKey] = optValue
+			}
+			continue
+		}
+		payload[k] = v
+	}
+
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent", g.GetBaseURL(), normalizeModelResource(request.Model))
+	var response geminiTextResponse
+	if err := g.DoRequest(ctx, "POST", endpoint, payload, &response); err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, g.ProviderError(response.Error.Message)
+	}
+	if len(response.Candidates) == 0 {
+		return nil, g.noCandidatesError(&response)
+	}
+
+	var pcm []byte
+	mimeType := ""
+	for _, part := range response.Candidates[0].Content.Parts {
+		if part.InlineData == nil || part.InlineData.Data == "" {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(part.InlineData.Data)
+		if err != nil {
+			return nil, g.RequestError("failed to decode audio data", err)
+		}
+		pcm = append(pcm, data...)
+		mimeType = part.InlineData.MimeType
+	}
+	if len(pcm) == 0 {
+		return nil, g.ProviderError("no audio in response")
+	}
+
+	rate := pcmSampleRate(mimeType)
+	audio := pcm
+	if format == "wav" {
+		audio = wavFile(pcm, rate)
+	}
+	return &types.AudioResponse{
+		Model:   request.Model,
+		Audio:   audio,
+		Format:  format,
+		Created: time.Now(),
+		Metadata: map[string]any{
+			"provider":    "gemini",
+			"mime_type":   mimeType,
+			"sample_rate": rate,
+		},
+	}, nil
+}
+
+// pcmSampleRate reads the rate parameter of an audio/L16 MIME type.
+func pcmSampleRate(mimeType string) int {
+	for _, param := range strings.Split(mimeType, ";") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(param), "rate="); ok {
+			if rate, err := strconv.Atoi(value); err == nil && rate > 0 {
+				return rate
+			}
+		}
+	}
+	return defaultSpeechSampleRate
+}
+
+// wavFile wraps 16-bit little-endian mono PCM in a WAV header.
+func wavFile(pcm []byte, sampleRate int) []byte {
+	const channels, bitsPerSample = 1, 16
+	blockAlign := channels * bitsPerSample / 8
+	out := make([]byte, 44, 44+len(pcm))
+	copy(out[0:], "RIFF")
+	binary.LittleEndian.PutUint32(out[4:], uint32(36+len(pcm)))
+	copy(out[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(out[16:], 16) // fmt chunk size
+	binary.LittleEndian.PutUint16(out[20:], 1)  // PCM
+	binary.LittleEndian.PutUint16(out[22:], channels)
+	binary.LittleEndian.PutUint32(out[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(out[28:], uint32(sampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(out[32:], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(out[34:], bitsPerSample)
+	copy(out[36:], "data")
+	binary.LittleEndian.PutUint32(out[40:], uint32(len(pcm)))
+	return append(out, pcm...)
+}
@@ -0,0 +1,114 @@
+package gemini
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func streamScript(t *testing.T, script testutil.StreamScript) []types.TextChunk {
+	t.Helper()
+	server := testutil.StreamServer(t, testutil.GeminiStream, script)
+	provider := New("test-key", types.ProviderConfig{BaseURL: server.URL})
+	stream, err := provider.Stream(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gemini-pro"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+	chunks, closed := testutil.DrainStream(stream, 5*time.Second)
+	require.True(t, closed, "stream must close")
+	return chunks
+}
+
+// Gemini sends function calls whole and ends tool turns with STOP, so the
+// finish reason stays stop and the call ID is synthesized.
+func TestStreamScriptedToolCallAndUsage(t *testing.T) {
+	t.Parallel()
+	chunks := streamScript(t, testutil.StreamScript{
+		Text:      []string{"Hel", "lo"},
+		ToolCalls: []testutil.StreamToolCall{{Name: "get_weather", Arguments: []string{`{"city":"Paris"}`}}},
+		Usage:     &types.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, ReasoningTokens: 2},
+	})
+	for _, chunk := range chunks {
+		require.NoError(t, chunk.Error)
+	}
+
+	merged := testutil.MergeTextChunks(chunks)
+	assert.Equal(t, "Hello", merged.Text)
+	assert.Equal(t, types.FinishReasonStop, merged.FinishReason)
+	require.Len(t, merged.ToolCalls, 1)
+	assert.Equal(t, "get_weather", merged.ToolCalls[0].Name)
+	assert.NotEmpty(t, merged.ToolCalls[0].ID)
+	assert.Equal(t, map[string]any{"city": "Paris"}, merged.ToolCalls[0].Arguments)
+	require.NotNil(t, merged.Usage)
+	assert.Equal(t, 10, merged.Usage.PromptTokens)
+	assert.Equal(t, 2, merged.Usage.ReasoningTokens)
+}
+
+func TestStreamScriptedFailures(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		script testutil.StreamScript
+		code   types.ErrorCode
+	}{
+		{"error object", testutil.StreamScript{Text: []string{"Hel"}, Error: "overloaded"}, types.ErrorCodeProvider},
+		{"no finishReason", testutil.StreamScript{Text: []string{"Hel"}, Truncate: true}, types.ErrorCodeNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chunks := streamScript(t, tt.script)
+			require.NotEmpty(t, chunks)
+			assert.Equal(t, "Hel", testutil.MergeTextChunks(chunks[:len(chunks)-1]).Text)
+			wormholeErr, ok := types.AsWormholeError(chunks[len(chunks)-1].Error)
+			require.True(t, ok, "final chunk must carry a *types.WormholeError")
+			assert.Equal(t, tt.code, wormholeErr.Code)
+		})
+	}
+}
+
+// Parallel function calls can arrive in separate events; each part index
+// restarts at zero, so IDs must be numbered across the whole stream.
+func TestStreamParallelCallsAcrossEventsGetDistinctIDs(t *testing.T) {
+	t.Parallel()
+	server := testutil.MockOpenAIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range []string{
+			`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"get_weather","args":{"city":"Paris"}}}]}}]}`,
+			`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"get_weather","args":{"city":"Rome"}}}]}}]}`,
+			`{"candidates":[{"content":{"role":"model","parts":[{"text":""}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":8,"candidatesTokenCount":4,"totalTokenCount":12}}`,
+		} {
+			_, _ = io.WriteString(w, "data: "+event+"\n\n")
+		}
+	})
+	provider := New("test-key", types.ProviderConfig{BaseURL: server.URL})
+	stream, err := provider.Stream(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gemini-pro"},
+		Messages:    []types.Message{types.NewUserMessage("weather in Paris and Rome")},
+	})
+	require.NoError(t, err)
+	chunks, closed := testutil.DrainStream(stream, 5*time.Second)
+	require.True(t, closed)
+	require.NotEmpty(t, chunks)
+
+	calls := testutil.MergeTextChunks(chunks).ToolCalls
+	require.Len(t, calls, 2)
+	assert.Equal(t, "gemini-call-0-get_weather", calls[0].ID)
+	assert.Equal(t, "gemini-call-1-get_weather", calls[1].ID)
+	assert.Equal(t, 1, calls[1].Index)
+	assert.Equal(t, "Rome", calls[1].Arguments["city"])
+
+	final := chunks[len(chunks)-1]
+	require.NotNil(t, final.FinishReason, "usage rides on the terminal chunk")
+	require.NotNil(t, final.Usage)
+	assert.Equal(t, 12, final.Usage.TotalTokens)
+}
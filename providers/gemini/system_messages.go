@@ -7,8 +7,8 @@ import (
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
-// mergeSystemInstruction merges any RoleSystem messages from msgs into base.
-// transformMessages skips RoleSystem (system text must travel in the top-level
+// mergeSystemInstruction merges any system or developer messages from msgs into
+// base. transformMessages skips them (system text must travel in the top-level
 // systemInstruction field), so without this merge a caller-provided system
 // message in request.Messages would be silently dropped.
 func mergeSystemInstruction(base string, msgs []types.Message) string {
@@ -17,7 +17,7 @@ func mergeSystemInstruction(base string, msgs []types.Message) string {
 		parts = append(parts, base)
 	}
 	for _, m := range msgs {
-		if m.GetRole() != types.RoleSystem {
+		if !m.GetRole().IsSystem() {
 			continue
 		}
 		switch c := m.GetContent().(type) {
@@ -39,6 +39,8 @@ func TestMergeSystemInstruction_MergesMessagesAndPrompt(t *testing.T) {
 	assert.Equal(t, "base prompt\n\nfrom messages", mergeSystemInstruction("base prompt", msgs))
 	assert.Equal(t, "base prompt", mergeSystemInstruction("base prompt", []types.Message{types.NewUserMessage("hi")}))
 	assert.Equal(t, "", mergeSystemInstruction("", []types.Message{types.NewUserMessage("hi")}))
+	assert.Equal(t, "from messages\n\nfrom developer",
+		mergeSystemInstruction("", append(msgs, types.NewDeveloperMessage("from developer"))), "developer messages are system messages here")
 }
 
 func TestBuildTextPayload_SystemMessageBecomesSystemInstruction(t *testing.T) {
@@ -59,7 +59,7 @@ func (g *Gemini) transformMessages(messages []types.Message, model string) ([]ma
 	for _, msg := range messages {
 		// Skip system messages — Gemini carries system text in the top-level
 		// systemInstruction field (see buildTextPayload), not in contents.
-		if msg.GetRole() == types.RoleSystem {
+		if msg.GetRole().IsSystem() {
 			continue
 		}
 
@@ -190,6 +190,8 @@ func (g *Gemini) transformMessageToParts(msg types.Message, model string) ([]map
 		})
 	case *types.SystemMessage:
 		parts = append(parts, map[string]any{"text": m.Content})
+	case *types.DeveloperMessage:
+		parts = append(parts, map[string]any{"text": m.Content})
 
 	default:
 		return nil, g.ProviderErrorf("unsupported message type: %T", msg)
@@ -198,6 +200,14 @@ func (g *Gemini) transformMessageToParts(msg types.Message, model string) ([]map
 	return parts, nil
 }
 
+// documentLimits reflects Gemini document understanding: PDFs and text files,
+// inline up to the 20 MB request limit, or by file URI (Files API uploads).
+var documentLimits = types.DocumentLimits{
+	MaxBytes:  20 << 20,
+	MimeTypes: []string{types.MimeTypePDF, "text/*"},
+	AllowURL:  true,
+}
+
 // transformMedia converts media to Gemini format
 func (g *Gemini) transformMedia(media types.Media) (map[string]any, error) {
 	switch m := media.(type) {
@@ -221,6 +231,17 @@ func (g *Gemini) transformMedia(media types.Media) (map[string]any, error) {
 		}, nil
 
 	case *types.DocumentMedia:
+		if err := documentLimits.Check(m); err != nil {
+			return nil, g.ValidationError("unsupported document input", err.Error())
+		}
+		if len(m.Data) == 0 {
+			return map[string]any{
+				"fileData": map[string]any{
+					"mimeType": m.MimeType,
+					"fileUri":  m.URL,
+				},
+			}, nil
+		}
 		return map[string]any{
 			"inlineData": map[string]any{
 				"mimeType": m.MimeType,
@@ -228,7 +249,18 @@ func (g *Gemini) transformMedia(media types.Media) (map[string]any, error) {
 			},
 		}, nil
 
+	case *types.AudioMedia:
+		return map[string]any{
+			"inlineData": map[string]any{
+				"mimeType": "audio/" + m.Format,
+				"data":     base64.StdEncoding.EncodeToString(m.Data),
+			},
+		}, nil
+
 	default:
+		if part, ok := types.MarshalMedia(types.MediaFormatGemini, media); ok {
+			return part, nil
+		}
 		return nil, g.ProviderErrorf("unsupported media type: %T", media)
 	}
 }
@@ -51,9 +51,10 @@ func (g *Gemini) transformTextResponse(response *geminiTextResponse) (*types.Tex
 	finishReason := providerTransform.MapFinishReason(candidate.FinishReason)
 
 	result := &types.TextResponse{
-		Text:         text,
-		ToolCalls:    toolCalls,
-		FinishReason: finishReason,
+		Text:            text,
+		ToolCalls:       toolCalls,
+		FinishReason:    finishReason,
+		RawFinishReason: candidate.FinishReason,
 	}
 
 	if thinking != "" {
@@ -70,10 +71,57 @@ func (g *Gemini) transformTextResponse(response *geminiTextResponse) (*types.Tex
 	if candidate.GroundingMetadata != nil {
 		result.Metadata["groundingMetadata"] = candidate.GroundingMetadata
 	}
+	result.Citations = candidateCitations(candidate)
 
 	return result, nil
 }
 
+// candidateCitations maps grounding supports, one citation per supporting
+// source, then any recitation sources from citationMetadata. Sources no
+// support refers to are listed without a span.
+func candidateCitations(cand candidate) []types.Citation {
+	var citations []types.Citation
+	if grounding := cand.GroundingMetadata; grounding != nil {
+		cited := make([]bool, len(grounding.GroundingChunks))
+		for _, support := range grounding.GroundingSupports {
+			for j, i := range support.GroundingChunkIndices {
+				if i < 0 || i >= len(grounding.GroundingChunks) {
+					continue
+				}
+				citation, ok := grounding.GroundingChunks[i].citation()
+				if !ok {
+					continue
+				}
+				cited[i] = true
+				citation.CitedText = support.Segment.Text
+				citation.StartIndex = support.Segment.StartIndex
+				citation.EndIndex = support.Segment.EndIndex
+				if j < len(support.ConfidenceScores) {
+					citation.Confidence = support.ConfidenceScores[j]
+				}
+				citations = append(citations, citation)
+			}
+		}
+		for i, chunk := range grounding.GroundingChunks {
+			if citation, ok := chunk.citation(); ok && !cited[i] {
+				citations = append(citations, citation)
+			}
+		}
+	}
+	if cand.CitationMetadata != nil {
+		for _, c := range cand.CitationMetadata.Citations {
+			citations = append(citations, types.Citation{
+				Type:       types.CitationTypeURL,
+				URL:        c.URI,
+				Title:      c.Title,
+				StartIndex: c.StartIndex,
+				EndIndex:   c.EndIndex,
+			})
+		}
+	}
+	return citations
+}
+
 // transformStructuredResponse converts Gemini response to types.StructuredResponse
 func (g *Gemini) transformStructuredResponse(response *geminiTextResponse, schema types.Schema) (*types.StructuredResponse, error) {
 	if response.Error != nil {
@@ -3,6 +3,8 @@ package gemini
 import (
 	"encoding/json"
 
+	"github.com/garyblankenship/wormhole/v2/providers"
+
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
@@ -234,3 +236,52 @@ func (g *Gemini) stringSchemaToMap(s *types.StringSchema, result map[string]any)
 		result["pattern"] = s.Pattern
 	}
 }
+
+// DefaultSchemaSupport is the JSON Schema subset Gemini accepts in
+// responseSchema and function parameters. Override it per provider with
+// types.ProviderConfig.SchemaSupport.
+var DefaultSchemaSupport = types.SchemaSupport{
+	AnyOf:   true,
+	Formats: []string{"enum", "date-time", "int32", "int64", "float", "double"},
+	UnsupportedKeywords: []string{
+		"$schema", "$id", "$comment", "not", "if", "then", "else",
+		"patternProperties", "dependentRequired", "dependentSchemas",
+		"unevaluatedProperties", "contains",
+	},
+}
+
+func (g *Gemini) schemaSupport() types.SchemaSupport {
+	if g.Config.SchemaSupport != nil {
+		return *g.Config.SchemaSupport
+	}
+	return DefaultSchemaSupport
+}
+
+// downgradeSchemas rewrites the function parameters and response schema in a
+// built payload to fit the schema profile and returns what it changed.
+func (g *Gemini) downgradeSchemas(payload map[string]any) []string {
+	support := g.schemaSupport()
+	var warnings []string
+	tools, _ := payload["tools"].([]map[string]any)
+	for _, tool := range tools {
+		functions, _ := tool["functionDeclarations"].([]map[string]any)
+		for _, function := range functions {
+			params, ok := function["parameters"].(map[string]any)
+			if !ok {
+				continue
+			}
+			params, w := types.DowngradeSchema(params, support)
+			function["parameters"] = params
+			name, _ := function["name"].(string)
+			warnings = append(warnings, providers.PrefixSchemaWarnings("tool "+name, w)...)
+		}
+	}
+	if config, ok := payload["generationConfig"].(map[string]any); ok {
+		if schema, ok := config["responseSchema"].(map[string]any); ok {
+			schema, w := types.DowngradeSchema(schema, support)
+			config["responseSchema"] = schema
+			warnings = append(warnings, providers.PrefixSchemaWarnings("response schema", w)...)
+		}
+	}
+	return warnings
+}
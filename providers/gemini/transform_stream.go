@@ -3,6 +3,7 @@ package gemini
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -16,7 +17,7 @@ import (
 func (g *Gemini) processStreamCandidate(candidate candidate) []types.TextChunk {
 	chunks := make([]types.TextChunk, 0, len(candidate.Content.Parts)+1)
 
-	for idx, part := range candidate.Content.Parts {
+	for _, part := range candidate.Content.Parts {
 		if part.Text != "" {
 			if part.Thought {
 				chunks = append(chunks, types.TextChunk{
@@ -31,11 +32,10 @@ func (g *Gemini) processStreamCandidate(candidate candidate) []types.TextChunk {
 			}
 		}
 		if part.FunctionCall != nil {
-			// Synthetic unique-per-part ID (Gemini provides none); see
-			// transformTextResponse for rationale.
+			// Gemini sends no call ID; handleStream numbers the calls across
+			// the stream.
 			chunks = append(chunks, types.TextChunk{
 				ToolCall: &types.ToolCall{
-					ID:               fmt.Sprintf("gemini-call-%d-%s", idx, part.FunctionCall.Name),
 					Name:             part.FunctionCall.Name,
 					Arguments:        part.FunctionCall.Args,
 					ThoughtSignature: part.ThoughtSignature,
@@ -48,8 +48,9 @@ func (g *Gemini) processStreamCandidate(candidate candidate) []types.TextChunk {
 	if candidate.FinishReason != "" {
 		finishReason := providerTransform.MapFinishReason(candidate.FinishReason)
 		chunks = append(chunks, types.TextChunk{
-			FinishReason: &finishReason,
-			Model:        "gemini",
+			FinishReason:    &finishReason,
+			RawFinishReason: candidate.FinishReason,
+			Model:           "gemini",
 		})
 	}
 
@@ -97,29 +98,33 @@ func (g *Gemini) parseStreamEvent(data string) ([]types.TextChunk, bool, error)
 
 // handleStream processes streaming responses. Every send is guarded by
 // ctx.Done() so the goroutine exits and the body closes if the consumer
-// stops reading.
+// stops reading. The body is drained for reuse only after a clean end.
 func (g *Gemini) handleStream(ctx context.Context, stream io.ReadCloser) <-chan types.TextChunk {
 	ch := make(chan types.TextChunk)
 
 	go func() {
+		clean := false
+		defer func() { providerstream.DrainAndClose(ctx, stream, clean) }()
 		defer close(ch)
-		defer func() {
-			_ = stream.Close()
-		}()
 
 		scanner := providerstream.NewSSEScanner(stream)
 		terminal := false
 		sawEvent := false
+		// Gemini streams each functionCall whole, but parallel calls may
+		// arrive in separate events. Number them across the stream so two
+		// calls to one function never share an ID.
+		toolCalls := 0
 		for scanner.Scan() {
 			chunks, done, err := g.parseStreamEvent(scanner.Event().Data)
 			if err != nil {
 				select {
-				case ch <- types.TextChunk{Error: err}:
+				case ch <- types.TextChunk{Error: providerstream.ChunkError(err)}:
 				case <-ctx.Done():
 				}
 				return
 			}
 			if done {
+				clean = true
 				return
 			}
 			for _, chunk := range chunks {
@@ -127,6 +132,11 @@ func (g *Gemini) handleStream(ctx context.Context, stream io.ReadCloser) <-chan
 				if chunk.FinishReason != nil {
 					terminal = true
 				}
+				if call := chunk.ToolCall; call != nil {
+					call.ID = fmt.Sprintf("gemini-call-%d-%s", toolCalls, call.Name)
+					call.Index = toolCalls
+					toolCalls++
+				}
 				select {
 				case ch <- chunk:
 				case <-ctx.Done():
@@ -137,14 +147,15 @@ func (g *Gemini) handleStream(ctx context.Context, stream io.ReadCloser) <-chan
 
 		if err := scanner.Err(); err != nil {
 			select {
-			case ch <- types.TextChunk{Error: err}:
+			case ch <- types.TextChunk{Error: providerstream.InterruptedError(err)}:
 			case <-ctx.Done():
 			}
 			return
 		}
+		clean = terminal
 		if sawEvent && !terminal {
 			select {
-			case ch <- types.TextChunk{Error: fmt.Errorf("Gemini stream ended before terminal event")}:
+			case ch <- types.TextChunk{Error: providerstream.InterruptedError(errors.New("Gemini stream ended before terminal event"))}:
 			case <-ctx.Done():
 			}
 		}
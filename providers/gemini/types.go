@@ -1,5 +1,7 @@
 package gemini
 
+import "github.com/garyblankenship/wormhole/v2/types"
+
 // Gemini API response types
 type geminiTextResponse struct {
 	Candidates     []candidate     `json:"candidates"`
@@ -81,6 +83,51 @@ type groundingMetadata struct {
 	WebSearchQueries      []string               `json:"webSearchQueries,omitempty"`
 	SearchEntryPoint      *searchEntryPoint      `json:"searchEntryPoint,omitempty"`
 	GroundingAttributions []groundingAttribution `json:"groundingAttributions,omitempty"`
+	GroundingChunks       []groundingChunk       `json:"groundingChunks,omitempty"`
+	GroundingSupports     []groundingSupport     `json:"groundingSupports,omitempty"`
+}
+
+// groundingChunk is one grounding source: a Google Search result (Web) or
+// a passage from a retrieval store (RetrievedContext).
+type groundingChunk struct {
+	Web *struct {
+		URI   string `json:"uri"`
+		Title string `json:"title,omitempty"`
+	} `json:"web,omitempty"`
+	RetrievedContext *struct {
+		URI   string `json:"uri,omitempty"`
+		Title string `json:"title,omitempty"`
+		Text  string `json:"text,omitempty"`
+	} `json:"retrievedContext,omitempty"`
+}
+
+// citation returns the chunk as a source without a response span.
+func (c groundingChunk) citation() (types.Citation, bool) {
+	switch {
+	case c.Web != nil:
+		return types.Citation{Type: types.CitationTypeURL, URL: c.Web.URI, Title: c.Web.Title}, true
+	case c.RetrievedContext != nil:
+		return types.Citation{
+			Type:      types.CitationTypeDocument,
+			URL:       c.RetrievedContext.URI,
+			Title:     c.RetrievedContext.Title,
+			CitedText: c.RetrievedContext.Text,
+		}, true
+	}
+	return types.Citation{}, false
+}
+
+// groundingSupport ties a segment of the response to the grounding chunks
+// that back it. Segment offsets are UTF-8 byte offsets into the text.
+type groundingSupport struct {
+	Segment struct {
+		StartIndex int    `json:"startIndex"`
+		EndIndex   int    `json:"endIndex"`
+		Text       string `json:"text,omitempty"`
+	} `json:"segment"`
+	GroundingChunkIndices []int `json:"groundingChunkIndices,omitempty"`
+	// ConfidenceScores pairs with GroundingChunkIndices.
+	ConfidenceScores []float64 `json:"confidenceScores,omitempty"`
 }
 
 type searchEntryPoint struct {
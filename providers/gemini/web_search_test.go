@@ -0,0 +1,81 @@
+package gemini
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestBuildTextPayloadAddsGoogleSearchTool(t *testing.T) {
+	t.Parallel()
+
+	provider := New("test-key", types.ProviderConfig{})
+	payload, err := provider.buildTextPayload(types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gemini-2.5-flash"},
+		Messages:    []types.Message{types.NewUserMessage("Who won the last Tour de France?")},
+		Tools:       []types.Tool{*types.NewTool("lookup", "Look up a rider", map[string]any{"type": "object"})},
+		WebSearch:   &types.WebSearch{},
+	})
+	require.NoError(t, err)
+
+	tools := payload["tools"].([]map[string]any)
+	require.Len(t, tools, 2)
+	assert.Contains(t, tools[0], "functionDeclarations")
+	assert.Equal(t, map[string]any{"google_search": map[string]any{}}, tools[1])
+}
+
+func TestTransformTextResponseMapsGroundingToCitations(t *testing.T) {
+	t.Parallel()
+
+	var response geminiTextResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"candidates":[{
+		"content":{"role":"model","parts":[{"text":"Pogačar won in 2025."}]},
+		"finishReason":"STOP",
+		"groundingMetadata":{
+			"webSearchQueries":["tour de france 2025 winner"],
+			"groundingChunks":[
+				{"web":{"uri":"https://example.com/a","title":"example.com"}},
+				{"web":{"uri":"https://example.com/b","title":"example.org"}}
+			],
+			"groundingSupports":[
+				{"segment":{"startIndex":0,"endIndex":21,"text":"Pogačar won in 2025."},"groundingChunkIndices":[0]}
+			]
+		}
+	}]}`), &response))
+
+	resp, err := New("test-key", types.ProviderConfig{}).transformTextResponse(&response)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Citation{
+		{Type: types.CitationTypeURL, URL: "https://example.com/a", Title: "example.com", CitedText: "Pogačar won in 2025.", EndIndex: 21},
+		{Type: types.CitationTypeURL, URL: "https://example.com/b", Title: "example.org"},
+	}, resp.Citations)
+	assert.NotNil(t, resp.Metadata["groundingMetadata"])
+}
+
+func TestTransformTextResponseMapsRetrievedContextWithConfidence(t *testing.T) {
+	t.Parallel()
+
+	var response geminiTextResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"candidates":[{
+		"content":{"role":"model","parts":[{"text":"The SLA is 99.9%."}]},
+		"groundingMetadata":{
+			"groundingChunks":[{"retrievedContext":{"uri":"gs://docs/sla.pdf","title":"sla.pdf","text":"Uptime: 99.9% monthly."}}],
+			"groundingSupports":[{"segment":{"endIndex":17,"text":"The SLA is 99.9%."},"groundingChunkIndices":[0],"confidenceScores":[0.92]}]
+		}
+	}]}`), &response))
+
+	resp, err := New("test-key", types.ProviderConfig{}).transformTextResponse(&response)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Citation{{
+		Type:       types.CitationTypeDocument,
+		URL:        "gs://docs/sla.pdf",
+		Title:      "sla.pdf",
+		CitedText:  "The SLA is 99.9%.",
+		EndIndex:   17,
+		Confidence: 0.92,
+	}}, resp.Citations)
+}
@@ -345,3 +345,110 @@ func TestBuildErrorResponseClassifiesInsufficientQuotaAsQuota(t *testing.T) {
 		t.Fatalf("ClassifyError = %v, want %v", got, types.ErrorClassQuota)
 	}
 }
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestHTTPClientWrapperTransportHooks(t *testing.T) {
+	t.Parallel()
+
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if got := r.Header.Get("X-Hooks"); got != "outer,inner,client" {
+			t.Errorf("X-Hooks = %q on attempt %d", got, attempts)
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	mark := func(name string, next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			hooks := name
+			if prior := req.Header.Get("X-Hooks"); prior != "" {
+				hooks = prior + "," + name
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Hooks", hooks)
+			return next.RoundTrip(req)
+		})
+	}
+	clientTransport := mark("client", http.DefaultTransport)
+	client := &http.Client{Transport: clientTransport}
+	config := types.ProviderConfig{BaseURL: server.URL}.
+		WithHTTPClient(client).
+		WithRetries(1, time.Millisecond).
+		WithTransportWrapper(func(next http.RoundTripper) http.RoundTripper { return mark("inner", next) }).
+		WithTransportWrapper(func(next http.RoundTripper) http.RoundTripper { return mark("outer", next) })
+
+	wrapper := NewHTTPClientWrapper("test", config, nil, &NoAuthStrategy{}, nil)
+	var result map[string]any
+	if err := wrapper.DoRequest(context.Background(), http.MethodPost, server.URL+"/x", map[string]any{}, &result); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	if attempts != 2 || result["ok"] != true {
+		t.Fatalf("attempts = %d, result = %v", attempts, result)
+	}
+	if client.Transport == nil || wrapper.GetHTTPClient() == client {
+		t.Fatal("caller's http.Client was modified instead of copied")
+	}
+}
+
+func TestHTTPClientWrapperSharesTunedTransports(t *testing.T) {
+	t.Parallel()
+
+	tuning := types.TransportConfig{
+		MaxIdleConnsPerHost: 77,
+		IdleConnTimeout:     42 * time.Second,
+		DisableHTTP2:        true,
+		ProxyURL:            "http://proxy.shared-transport.test:3128",
+	}
+	first := NewHTTPClientWrapper("a", types.ProviderConfig{BaseURL: "https://shared-transport.test/v1"}.WithTransport(tuning), nil, &NoAuthStrategy{}, nil)
+	second := NewHTTPClientWrapper("b", types.ProviderConfig{BaseURL: "https://shared-transport.test/v2"}.WithTransport(tuning), nil, &NoAuthStrategy{}, nil)
+	other := NewHTTPClientWrapper("c", types.ProviderConfig{BaseURL: "https://shared-transport.test"}, nil, &NoAuthStrategy{}, nil)
+
+	transport, ok := first.GetHTTPClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport = %T, want *http.Transport", first.GetHTTPClient().Transport)
+	}
+	if second.GetHTTPClient().Transport != transport {
+		t.Fatal("providers with the same host and tuning should share a transport")
+	}
+	if other.GetHTTPClient().Transport == transport {
+		t.Fatal("providers with different tuning must not share a transport")
+	}
+
+	if transport.MaxIdleConnsPerHost != 77 || transport.IdleConnTimeout != 42*time.Second || transport.MaxIdleConns != 100 {
+		t.Fatalf("pool settings = %d/%s/%d", transport.MaxIdleConnsPerHost, transport.IdleConnTimeout, transport.MaxIdleConns)
+	}
+	if transport.ForceAttemptHTTP2 || transport.TLSNextProto == nil {
+		t.Fatal("HTTP/2 should be disabled")
+	}
+	proxy, err := transport.Proxy(httptest.NewRequest(http.MethodGet, "https://shared-transport.test", nil))
+	if err != nil || proxy == nil || proxy.Host != "proxy.shared-transport.test:3128" {
+		t.Fatalf("proxy = %v, err = %v", proxy, err)
+	}
+
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if second.GetHTTPClient().Transport != transport {
+		t.Fatal("Close must leave the shared transport in place")
+	}
+}
+
+func TestTransportProxyRejectsInvalidURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultHTTPTransportConfig()
+	cfg.ProxyURL = "http://bad host:3128"
+	proxy := transportProxy(&cfg)
+	if _, err := proxy(httptest.NewRequest(http.MethodGet, "https://example.com", nil)); err == nil {
+		t.Fatal("expected an error for an unparsable proxy URL")
+	}
+}
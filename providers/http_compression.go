@@ -0,0 +1,74 @@
+package providers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+const (
+	encodingGzip     = "gzip"
+	encodingIdentity = "identity"
+
+	defaultMinGzipRequestBytes = 1 << 10
+)
+
+// compressRequestBody gzips payload when the provider opted into request
+// compression and the body is large enough to be worth it. It reports whether
+// the returned bytes are compressed.
+func (w *HTTPClientWrapper) compressRequestBody(payload []byte) ([]byte, bool, error) {
+	cfg := w.Config.Compression
+	if !cfg.GzipRequests {
+		return payload, false, nil
+	}
+	minBytes := cfg.MinRequestBytes
+	if minBytes <= 0 {
+		minBytes = defaultMinGzipRequestBytes
+	}
+	if len(payload) < minBytes {
+		return payload, false, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(payload); err != nil {
+		return nil, false, types.Errorf("gzip request body", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, false, types.Errorf("gzip request body", err)
+	}
+	return buf.Bytes(), true, nil
+}
+
+// decodeResponseBody undoes a gzip Content-Encoding the transport left in
+// place. net/http only decodes responses to requests where it added
+// Accept-Encoding itself; custom HTTPClients and gateways that compress
+// regardless of the request still need decoding here.
+func decodeResponseBody(resp *http.Response) error {
+	if resp.Uncompressed || !strings.EqualFold(strings.TrimSpace(resp.Header.Get(types.HeaderContentEncoding)), encodingGzip) {
+		return nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return types.Errorf("decode gzip response", err)
+	}
+	resp.Body = &gzipReadCloser{Reader: zr, body: resp.Body}
+	resp.Header.Del(types.HeaderContentEncoding)
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (r *gzipReadCloser) Close() error {
+	_ = r.Reader.Close()
+	return r.body.Close()
+}
@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestHTTPClientWrapperGzipRequests(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Repeat("embed me ", 200)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get(types.HeaderContentEncoding))
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		var got map[string]string
+		require.NoError(t, json.NewDecoder(zr).Decode(&got))
+		assert.Equal(t, input, got["input"])
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	wrapper := NewHTTPClientWrapper("test", types.ProviderConfig{}.WithGzipRequests(0), nil, &NoAuthStrategy{}, server.Client())
+	var result map[string]bool
+	require.NoError(t, wrapper.DoRequest(context.Background(), http.MethodPost, server.URL, map[string]string{"input": input}, &result))
+	assert.True(t, result["ok"])
+
+	// Bodies under the threshold go out as plain JSON.
+	small := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(types.HeaderContentEncoding))
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(small.Close)
+	require.NoError(t, wrapper.DoRequest(context.Background(), http.MethodPost, small.URL, map[string]string{"input": "hi"}, &result))
+}
+
+func TestHTTPClientWrapperDecodesGzipResponses(t *testing.T) {
+	t.Parallel()
+
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get(types.HeaderAcceptEncoding)
+		// Compress regardless of what the client asked for, as some gateways do.
+		w.Header().Set(types.HeaderContentEncoding, "gzip")
+		if r.Header.Get(types.HeaderAccept) == types.ContentTypeEventStream {
+			_, _ = w.Write(gzipBytes(t, []byte("data: ok\n\n")))
+			return
+		}
+		_, _ = w.Write(gzipBytes(t, []byte(`{"ok":true}`)))
+	}))
+	t.Cleanup(server.Close)
+
+	wrapper := NewHTTPClientWrapper("test", types.ProviderConfig{
+		Compression: types.CompressionConfig{DisableResponseCompression: true},
+	}, nil, &NoAuthStrategy{}, server.Client())
+
+	var result map[string]bool
+	require.NoError(t, wrapper.DoRequest(context.Background(), http.MethodPost, server.URL, map[string]string{}, &result))
+	assert.True(t, result["ok"])
+	assert.Equal(t, "identity", acceptEncoding)
+
+	body, err := wrapper.StreamRequest(context.Background(), http.MethodPost, server.URL, map[string]string{})
+	require.NoError(t, err)
+	defer func() { _ = body.Close() }()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "data: ok\n\n", string(data))
+	assert.Equal(t, "identity", acceptEncoding, "streams never negotiate compression")
+}
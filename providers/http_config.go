@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/garyblankenship/wormhole/v2/config"
+	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 // HTTPTransportConfig holds configuration for HTTP transport settings.
@@ -38,6 +39,11 @@ type HTTPTransportConfig struct {
 
 	// Proxy settings (optional)
 	Proxy func(*http.Request) (*url.URL, error)
+	// ProxyURL, when set, takes precedence over Proxy.
+	ProxyURL string
+
+	// DisableHTTP2 keeps connections on HTTP/1.1.
+	DisableHTTP2 bool
 }
 
 // DefaultHTTPTransportConfig returns a secure HTTP transport configuration
@@ -72,10 +78,32 @@ func (c HTTPTransportConfig) Fingerprint() string {
 		c.MaxIdleConns, c.MaxIdleConnsPerHost, c.MaxConnsPerHost,
 		c.IdleConnTimeout, c.DialTimeout, c.DialKeepAlive,
 		c.TLSHandshakeTimeout, c.ExpectContinueTimeout, c.ResponseHeaderTimeout)
-	fmt.Fprintf(&b, "|proxy:%s", proxyFingerprint(c.Proxy))
+	fmt.Fprintf(&b, "|proxy:%s|proxyurl:%s|http2:%t", proxyFingerprint(c.Proxy), c.ProxyURL, !c.DisableHTTP2)
 	return b.String()
 }
 
+// applyProviderTransport overlays the non-zero fields of a provider's
+// TransportConfig onto c.
+func (c HTTPTransportConfig) applyProviderTransport(t types.TransportConfig) HTTPTransportConfig {
+	if t.MaxIdleConns > 0 {
+		c.MaxIdleConns = t.MaxIdleConns
+	}
+	if t.MaxIdleConnsPerHost > 0 {
+		c.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	}
+	if t.MaxConnsPerHost > 0 {
+		c.MaxConnsPerHost = t.MaxConnsPerHost
+	}
+	if t.IdleConnTimeout > 0 {
+		c.IdleConnTimeout = t.IdleConnTimeout
+	}
+	if t.ProxyURL != "" {
+		c.ProxyURL = t.ProxyURL
+	}
+	c.DisableHTTP2 = c.DisableHTTP2 || t.DisableHTTP2
+	return c
+}
+
 func proxyFingerprint(proxy func(*http.Request) (*url.URL, error)) string {
 	if proxy == nil {
 		return "nil"
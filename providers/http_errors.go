@@ -53,6 +53,10 @@ func (w *HTTPClientWrapper) extractErrorMessage(statusCode int, status string, r
 			return msg
 		}
 	}
+	// Hugging Face and some self-hosted servers send {"error": "message"}.
+	if msg, ok := errorResp["error"].(string); ok && msg != "" {
+		return msg
+	}
 
 	return errorMessage
 }
@@ -180,7 +184,8 @@ func (w *HTTPClientWrapper) isTimeoutError(err error) bool {
 }
 
 func (w *HTTPClientWrapper) Close() error {
-	if w.httpClient != nil && w.httpClient.Transport != nil {
+	// Shared transports serve other providers too; leave their pools alone.
+	if w.ownsTransport && w.httpClient != nil && w.httpClient.Transport != nil {
 		if transport, ok := w.httpClient.Transport.(interface{ CloseIdleConnections() }); ok {
 			transport.CloseIdleConnections()
 		}
@@ -22,12 +22,19 @@ func (w *HTTPClientWrapper) StreamRequest(ctx context.Context, method, url strin
 	}
 	req.Header.Set(types.HeaderAccept, types.ContentTypeEventStream)
 	req.Header.Set(types.HeaderCacheControl, "no-cache")
+	// A gzip stream holds events back until a compressed block fills up.
+	req.Header.Set(types.HeaderAcceptEncoding, encodingIdentity)
 
 	resp, err := w.retryClient.Do(req)
 	if err != nil {
 		cancel()
 		return nil, w.handleRequestError(ctx, err)
 	}
+	if err := decodeResponseBody(resp); err != nil {
+		_ = resp.Body.Close()
+		cancel()
+		return nil, err
+	}
 
 	if resp.StatusCode >= 400 {
 		defer cancel()
@@ -59,6 +66,10 @@ func (w *HTTPClientWrapper) buildRequest(ctx context.Context, method, url string
 	if err != nil {
 		return nil, err
 	}
+	payload, compressed, err := w.compressRequestBody(payload)
+	if err != nil {
+		return nil, err
+	}
 
 	var reqBody io.Reader
 	if payload != nil {
@@ -79,6 +90,9 @@ func (w *HTTPClientWrapper) buildRequest(ctx context.Context, method, url string
 	if err := w.setRequestHeaders(req); err != nil {
 		return nil, err
 	}
+	if compressed {
+		req.Header.Set(types.HeaderContentEncoding, encodingGzip)
+	}
 
 	return req, nil
 }
@@ -102,9 +116,19 @@ func (w *HTTPClientWrapper) setRequestHeaders(req *http.Request) error {
 		return err
 	}
 
+	if w.Config.Compression.DisableResponseCompression {
+		req.Header.Set(types.HeaderAcceptEncoding, encodingIdentity)
+	}
+
+	SetUserAgent(req, w.Config)
 	for k, v := range w.Config.Headers {
 		req.Header.Set(k, v)
 	}
+	if w.Config.IdempotencyHeader != "" {
+		if key := types.IdempotencyKeyFromContext(req.Context()); key != "" {
+			req.Header.Set(w.Config.IdempotencyHeader, key)
+		}
+	}
 
 	return nil
 }
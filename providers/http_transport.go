@@ -2,8 +2,10 @@ package providers
 
 import (
 	"crypto/tls"
+	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/garyblankenship/wormhole/v2/config"
@@ -12,7 +14,7 @@ import (
 // NewSecureHTTPClient creates a new HTTP client with secure TLS configuration
 // and optimized transport settings. This standalone form does NOT share a
 // transport cache; each call builds a fresh transport. For cache-backed reuse,
-// HTTPClientWrapper routes through its instance-scoped *TransportCache.
+// HTTPClientWrapper routes through the process-wide shared *TransportCache.
 func NewSecureHTTPClient(timeout time.Duration, tlsConfig *config.TLSConfig, transportConfig *HTTPTransportConfig, baseURL string) *http.Client {
 	return buildSecureHTTPClient(timeout, tlsConfig, transportConfig, baseURL, nil)
 }
@@ -90,8 +92,8 @@ func approvedTLSConfig(tlsConfig *config.TLSConfig) *config.TLSConfig {
 
 // newTransportFromConfig constructs an *http.Transport from the given config.
 func newTransportFromConfig(transportConfig *HTTPTransportConfig, tlsClientConfig *tls.Config) *http.Transport {
-	return &http.Transport{
-		Proxy: transportConfig.Proxy,
+	transport := &http.Transport{
+		Proxy: transportProxy(transportConfig),
 		DialContext: (&net.Dialer{
 			Timeout:   transportConfig.DialTimeout,
 			KeepAlive: transportConfig.DialKeepAlive,
@@ -104,8 +106,27 @@ func newTransportFromConfig(transportConfig *HTTPTransportConfig, tlsClientConfi
 		MaxConnsPerHost:       transportConfig.MaxConnsPerHost,
 		IdleConnTimeout:       transportConfig.IdleConnTimeout,
 		TLSClientConfig:       tlsClientConfig,
-		ForceAttemptHTTP2:     true, // Enable HTTP/2
+		ForceAttemptHTTP2:     !transportConfig.DisableHTTP2,
 	}
+	if transportConfig.DisableHTTP2 {
+		// A non-nil empty map stops net/http from negotiating h2 via ALPN.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+// transportProxy resolves the proxy function for a transport config. An
+// unparsable ProxyURL fails each request rather than silently going direct.
+func transportProxy(transportConfig *HTTPTransportConfig) func(*http.Request) (*url.URL, error) {
+	if transportConfig.ProxyURL == "" {
+		return transportConfig.Proxy
+	}
+	proxyURL, err := url.Parse(transportConfig.ProxyURL)
+	if err != nil {
+		proxyErr := fmt.Errorf("invalid proxy URL %q: %w", transportConfig.ProxyURL, err)
+		return func(*http.Request) (*url.URL, error) { return nil, proxyErr }
+	}
+	return http.ProxyURL(proxyURL)
 }
 
 // NewInsecureHTTPClient creates an HTTP client with insecure TLS configuration
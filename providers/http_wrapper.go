@@ -19,6 +19,9 @@ type HTTPClientWrapper struct {
 	authStrategy   AuthStrategy
 	keyPool        *keyPool
 	transportCache *TransportCache
+	// ownsTransport is true when httpClient's transport belongs to this
+	// wrapper (injected by the caller) rather than the shared cache.
+	ownsTransport bool
 }
 
 // NewHTTPClientWrapper creates a new HTTPClientWrapper.
@@ -32,25 +35,32 @@ func NewHTTPClientWrapper(name string, providerConfig types.ProviderConfig, tlsC
 		providerConfig.APIKey = providerConfig.EffectiveAPIKey()
 	}
 
+	if httpClient == nil && providerConfig.HTTPClient != nil {
+		httpClient = providerConfig.HTTPClient
+	}
+
 	w := &HTTPClientWrapper{
 		providerName:   name,
 		Config:         providerConfig,
 		tlsConfig:      tlsConfig,
 		authStrategy:   authStrategy,
-		transportCache: NewTransportCache(),
+		transportCache: sharedTransports,
 	}
 
+	transportConfig := DefaultHTTPTransportConfig().applyProviderTransport(providerConfig.Transport)
+
 	// Use injected client if provided, otherwise create default
 	if httpClient != nil {
 		// Type assertion to get the concrete *http.Client if possible
 		if hc, ok := httpClient.(*http.Client); ok {
 			w.httpClient = hc
+			w.ownsTransport = true
 		} else {
 			// For non-standard HTTPClient implementations, create a concrete client for GetHTTPClient()
-			w.httpClient = w.transportCache.newSecureHTTPClient(0, tlsConfig, nil, providerConfig.BaseURL)
+			w.httpClient = w.transportCache.newSecureHTTPClient(0, tlsConfig, &transportConfig, providerConfig.BaseURL)
 		}
 	} else {
-		w.httpClient = w.transportCache.newSecureHTTPClient(0, tlsConfig, nil, providerConfig.BaseURL)
+		w.httpClient = w.transportCache.newSecureHTTPClient(0, tlsConfig, &transportConfig, providerConfig.BaseURL)
 	}
 
 	retryConfig := defaultRetryConfig()
@@ -67,8 +77,12 @@ func NewHTTPClientWrapper(name string, providerConfig types.ProviderConfig, tlsC
 		w.keyPool = newKeyPool(providerConfig.APIKeys, retryConfig.InitialDelay)
 	}
 
+	if providerConfig.WrapTransport != nil {
+		w.httpClient = wrapClientTransport(w.httpClient, providerConfig.WrapTransport)
+	}
+
 	// Use injected client for retry wrapper if provided, otherwise use the concrete httpClient
-	if httpClient != nil {
+	if _, concrete := httpClient.(*http.Client); httpClient != nil && !concrete {
 		w.retryClient = newRetryableHTTPClient(httpClient, retryConfig)
 	} else {
 		w.retryClient = newRetryableHTTPClient(w.httpClient, retryConfig)
@@ -96,6 +110,18 @@ func NewHTTPClientWrapper(name string, providerConfig types.ProviderConfig, tlsC
 	return w
 }
 
+// wrapClientTransport returns a copy of client whose transport is wrapped by
+// wrap. The copy keeps a shared or caller-owned client untouched.
+func wrapClientTransport(client *http.Client, wrap func(http.RoundTripper) http.RoundTripper) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	wrapped := *client
+	wrapped.Transport = wrap(base)
+	return &wrapped
+}
+
 func (w *HTTPClientWrapper) GetHTTPTimeout() time.Duration {
 	if w.Config.HTTPTimeout != nil {
 		return *w.Config.HTTPTimeout
@@ -134,6 +160,9 @@ func (w *HTTPClientWrapper) DoRequest(ctx context.Context, method, url string, b
 			slog.Warn("failed to close response body", "error", err)
 		}
 	}()
+	if err := decodeResponseBody(resp); err != nil {
+		return err
+	}
 
 	respBody, err := readResponseBodyLimited(resp.Body)
 	if err != nil {
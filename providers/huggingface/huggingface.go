@@ -0,0 +1,207 @@
+package huggingface
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/providers"
+	providerstream "github.com/garyblankenship/wormhole/v2/providers/internal/stream"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// DefaultBaseURL is the serverless Inference API model root. Model IDs are
+// appended to it, as to any BaseURL ending in "/models"; any other BaseURL is
+// a dedicated Inference Endpoint and is posted to as-is.
+const DefaultBaseURL = "https://api-inference.huggingface.co/models"
+
+// Provider implements the Hugging Face Inference API and Inference Endpoints
+// for the text-generation and feature-extraction tasks.
+type Provider struct {
+	*providers.BaseProvider
+}
+
+var _ types.Provider = (*Provider)(nil)
+
+// New creates a new Hugging Face provider. Requests wait for cold models to
+// load unless config.Params["wait_for_model"] is false, in which case a
+// loading model fails fast with a retryable 503 whose RetryAfter is HF's
+// estimated load time.
+func New(config types.ProviderConfig) *Provider {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	return &Provider{
+		BaseProvider: providers.NewBaseProvider("huggingface", config),
+	}
+}
+
+// SupportedCapabilities returns the capabilities supported by Hugging Face provider
+func (p *Provider) SupportedCapabilities() []types.ModelCapability {
+	return []types.ModelCapability{
+		types.CapabilityText,
+		types.CapabilityChat,
+		types.CapabilityStructured,
+		types.CapabilityEmbeddings,
+		types.CapabilityStream,
+	}
+}
+
+// Text generates a text response using the text-generation task
+func (p *Provider) Text(ctx context.Context, request types.TextRequest) (*types.TextResponse, error) {
+	payload, err := p.buildGenerateRequest(&request)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := p.DoRequest(ctx, http.MethodPost, p.modelURL(request.Model), payload, &raw); err != nil {
+		return nil, p.modelLoadingError(err, request.Model)
+	}
+	gen, err := decodeGeneration(raw)
+	if err != nil {
+		return nil, p.RequestError("failed to parse text-generation response", err)
+	}
+
+	resp := p.transformTextResponse(gen, request.Model)
+	if resp.IsEmpty() {
+		return nil, types.ErrEmptyResponse.WithProvider(p.Name()).WithModel(request.Model).WithDetails("no generated_text returned")
+	}
+	return resp, nil
+}
+
+// Stream generates a streaming text response. Streaming requires a model
+// served by Text Generation Inference, which all current serverless
+// text-generation models and Inference Endpoints are.
+func (p *Provider) Stream(ctx context.Context, request types.TextRequest) (<-chan types.TextChunk, error) {
+	payload, err := p.buildGenerateRequest(&request)
+	if err != nil {
+		return nil, err
+	}
+	payload.Stream = true
+
+	body, err := p.StreamRequest(ctx, http.MethodPost, p.modelURL(request.Model), payload)
+	if err != nil {
+		return nil, p.modelLoadingError(err, request.Model)
+	}
+
+	chunks := providerstream.ProcessSSE(ctx, body, func(data []byte) (*types.TextChunk, error) {
+		return p.parseStreamEvent(data, request.Model)
+	}, 100)
+	return providerstream.Finalize(ctx, p.Name(), chunks), nil
+}
+
+// Structured generates JSON constrained by the request schema using TGI's
+// grammar parameter.
+func (p *Provider) Structured(ctx context.Context, request types.StructuredRequest) (*types.StructuredResponse, error) {
+	textRequest := types.TextRequest{
+		BaseRequest:  request.BaseRequest,
+		Messages:     request.Messages,
+		SystemPrompt: request.SystemPrompt,
+	}
+	payload, err := p.buildGenerateRequest(&textRequest)
+	if err != nil {
+		return nil, err
+	}
+	if request.Schema != nil {
+		payload.Parameters["grammar"] = map[string]any{"type": "json", "value": request.Schema}
+	}
+
+	var raw json.RawMessage
+	if err := p.DoRequest(ctx, http.MethodPost, p.modelURL(request.Model), payload, &raw); err != nil {
+		return nil, p.modelLoadingError(err, request.Model)
+	}
+	gen, err := decodeGeneration(raw)
+	if err != nil {
+		return nil, p.RequestError("failed to parse text-generation response", err)
+	}
+	response := p.transformTextResponse(gen, request.Model)
+
+	var data any
+	if err := json.Unmarshal([]byte(response.Text), &data); err != nil {
+		return nil, p.RequestError("failed to parse structured response", err)
+	}
+
+	return &types.StructuredResponse{
+		ID:      response.ID,
+		Model:   response.Model,
+		Data:    data,
+		Raw:     response.Text,
+		Usage:   response.Usage,
+		Created: response.Created,
+	}, nil
+}
+
+// Embeddings generates embeddings using the feature-extraction task. Models
+// that return one vector per token are mean-pooled into one vector per input.
+func (p *Provider) Embeddings(ctx context.Context, request types.EmbeddingsRequest) (*types.EmbeddingsResponse, error) {
+	if len(request.Input) == 0 {
+		return nil, p.ValidationError("no input provided for embeddings")
+	}
+
+	payload := &featureExtractionRequest{
+		Inputs:  request.Input,
+		Options: p.requestOptions(request.ProviderOptions),
+	}
+
+	var response featureExtractionResponse
+	if err := p.DoRequest(ctx, http.MethodPost, p.modelURL(request.Model), payload, &response); err != nil {
+		return nil, p.modelLoadingError(err, request.Model)
+	}
+	if len(response) != len(request.Input) {
+		return nil, p.ProviderErrorf("feature-extraction returned %d vectors for %d inputs", len(response), len(request.Input))
+	}
+
+	embeddings := make([]types.Embedding, len(response))
+	for i, raw := range response {
+		vector, err := decodeVector(raw)
+		if err != nil {
+			return nil, p.RequestError("failed to parse feature-extraction response", err)
+		}
+		embeddings[i] = types.Embedding{Index: i, Embedding: vector}
+	}
+
+	return &types.EmbeddingsResponse{
+		Provider:   p.Name(),
+		Model:      request.Model,
+		Embeddings: embeddings,
+		Created:    time.Now(),
+	}, nil
+}
+
+// modelURL returns the URL requests for model are posted to.
+func (p *Provider) modelURL(model string) string {
+	base := strings.TrimRight(p.GetBaseURL(), "/")
+	if strings.HasSuffix(base, "/models") && model != "" {
+		return base + "/" + model
+	}
+	return base
+}
+
+// modelLoadingError recognizes HF's cold-start 503, whose body is
+// {"error": "Model ... is currently loading", "estimated_time": 20.0}, and
+// returns a retryable error that asks callers to wait the estimated time.
+// Other errors are returned unchanged.
+func (p *Provider) modelLoadingError(err error, model string) error {
+	wormholeErr, ok := types.AsWormholeError(err)
+	if !ok || wormholeErr.StatusCode != http.StatusServiceUnavailable {
+		return err
+	}
+	_, body, found := strings.Cut(wormholeErr.Details, "Response: ")
+	if !found {
+		return err
+	}
+	var loading errorResponse
+	if json.Unmarshal([]byte(body), &loading) != nil || loading.EstimatedTime <= 0 {
+		return err
+	}
+	return types.ErrProviderUnavailable.
+		WithProvider(p.Name()).
+		WithModel(model).
+		WithStatusCode(http.StatusServiceUnavailable).
+		WithDetails(loading.Error).
+		WithRetryAfter(time.Duration(loading.EstimatedTime * float64(time.Second))).
+		WithCause(err)
+}
@@ -0,0 +1,205 @@
+package huggingface
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func newHFTestProvider(t *testing.T, baseURL string, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	noRetries := 0
+	return New(types.ProviderConfig{
+		APIKey:     "hf_test",
+		BaseURL:    server.URL + baseURL,
+		MaxRetries: &noRetries,
+	})
+}
+
+func TestProviderTextServerless(t *testing.T) {
+	t.Parallel()
+	provider := newHFTestProvider(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/models/mistralai/Mistral-7B-Instruct-v0.3", r.URL.Path)
+		assert.Equal(t, "Bearer hf_test", r.Header.Get("Authorization"))
+
+		var req generateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "hi", req.Inputs)
+		assert.Equal(t, float64(32), req.Parameters["max_new_tokens"])
+		assert.Equal(t, false, req.Parameters["return_full_text"])
+		assert.Equal(t, 1.1, req.Parameters["repetition_penalty"])
+		require.NotNil(t, req.Options)
+		assert.True(t, req.Options.WaitForModel)
+
+		_, _ = w.Write([]byte(`[{"generated_text":"hello","details":{"finish_reason":"length","generated_tokens":32}}]`))
+	})
+
+	maxTokens := 32
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{
+			Model:           "mistralai/Mistral-7B-Instruct-v0.3",
+			MaxTokens:       &maxTokens,
+			ProviderOptions: map[string]any{"repetition_penalty": 1.1},
+		},
+		Messages: []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", resp.Text)
+	assert.Equal(t, "huggingface", resp.Provider)
+	assert.Equal(t, types.FinishReasonLength, resp.FinishReason)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, 32, resp.Usage.CompletionTokens)
+}
+
+func TestProviderTextEndpointRendersConversation(t *testing.T) {
+	t.Parallel()
+	provider := newHFTestProvider(t, "", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/", r.URL.Path, "dedicated endpoints are posted to as-is")
+
+		var req generateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "System: be brief\n\nUser: hi\n\nAssistant: hello\n\nUser: bye\n\nAssistant:", req.Inputs)
+
+		_, _ = w.Write([]byte(`{"generated_text":" goodbye"}`))
+	})
+
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest:  types.BaseRequest{Model: "tgi"},
+		SystemPrompt: "be brief",
+		Messages: []types.Message{
+			types.NewUserMessage("hi"),
+			types.NewAssistantMessage("hello"),
+			types.NewUserMessage("bye"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, " goodbye", resp.Text)
+	assert.Equal(t, types.FinishReasonStop, resp.FinishReason)
+}
+
+func TestProviderModelLoading(t *testing.T) {
+	t.Parallel()
+	provider := newHFTestProvider(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		var req generateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.False(t, req.Options.WaitForModel)
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"Model gpt2 is currently loading","estimated_time":20.5}`))
+	})
+
+	_, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt2", ProviderOptions: map[string]any{"wait_for_model": false}},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.Error(t, err)
+	wormholeErr, ok := types.AsWormholeError(err)
+	require.True(t, ok)
+	assert.True(t, wormholeErr.IsRetryable())
+	assert.Equal(t, http.StatusServiceUnavailable, wormholeErr.StatusCode)
+	assert.Equal(t, 20500*time.Millisecond, wormholeErr.RetryAfter)
+	assert.Contains(t, err.Error(), "currently loading")
+}
+
+func TestProviderEmptyGeneration(t *testing.T) {
+	t.Parallel()
+	provider := newHFTestProvider(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"generated_text":"  "}]`))
+	})
+
+	_, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt2"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	assert.True(t, types.IsEmptyResponseError(err))
+}
+
+func TestProviderStream(t *testing.T) {
+	t.Parallel()
+	provider := newHFTestProvider(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		var req generateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, req.Stream)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data:{\"token\":{\"text\":\"Hel\",\"special\":false},\"generated_text\":null}\n\n" +
+			"data:{\"token\":{\"text\":\"lo\",\"special\":false},\"generated_text\":null}\n\n" +
+			"data:{\"token\":{\"text\":\"</s>\",\"special\":true},\"generated_text\":\"Hello\",\"details\":{\"finish_reason\":\"eos_token\",\"generated_tokens\":3}}\n\n"))
+	})
+
+	stream, err := provider.Stream(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt2"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+
+	var text string
+	var last types.TextChunk
+	for chunk := range stream {
+		require.NoError(t, chunk.Error)
+		text += chunk.Content()
+		last = chunk
+	}
+	assert.Equal(t, "Hello", text)
+	require.True(t, last.IsDone())
+	assert.Equal(t, types.FinishReasonStop, *last.FinishReason)
+	assert.Equal(t, "huggingface", last.Provider)
+	assert.Equal(t, 3, last.Usage.CompletionTokens)
+}
+
+func TestProviderStructuredUsesGrammar(t *testing.T) {
+	t.Parallel()
+	provider := newHFTestProvider(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		var req generateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		grammar, ok := req.Parameters["grammar"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "json", grammar["type"])
+		assert.NotNil(t, grammar["value"])
+
+		_, _ = w.Write([]byte(`[{"generated_text":"{\"name\":\"Ada\"}"}]`))
+	})
+
+	resp, err := provider.Structured(context.Background(), types.StructuredRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt2"},
+		Messages:    []types.Message{types.NewUserMessage("who?")},
+		Schema:      map[string]any{"type": "object"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "Ada"}, resp.Data)
+}
+
+func TestProviderEmbeddings(t *testing.T) {
+	t.Parallel()
+	provider := newHFTestProvider(t, "/models", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/models/BAAI/bge-small-en-v1.5", r.URL.Path)
+
+		var req featureExtractionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []string{"a", "b"}, req.Inputs)
+
+		// A sentence embedding for "a", per-token vectors for "b".
+		_, _ = w.Write([]byte(`[[0.1,0.2],[[1,2],[3,4]]]`))
+	})
+
+	resp, err := provider.Embeddings(context.Background(), types.EmbeddingsRequest{
+		Model: "BAAI/bge-small-en-v1.5",
+		Input: []string{"a", "b"},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Embeddings, 2)
+	assert.Equal(t, []float64{0.1, 0.2}, resp.Embeddings[0].Embedding)
+	assert.Equal(t, []float64{2, 3}, resp.Embeddings[1].Embedding, "token vectors are mean-pooled")
+	assert.Equal(t, 1, resp.Embeddings[1].Index)
+}
@@ -0,0 +1,59 @@
+package huggingface
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestStreamAbruptDisconnect(t *testing.T) {
+	t.Parallel()
+	server := testutil.DisconnectingServer(t, "text/event-stream", "data:{\"token\":{\"text\":\"Hel\",\"special\":false},\"generated_text\":null}\n\n")
+	provider := New(types.ProviderConfig{APIKey: "hf_test", BaseURL: server.URL + "/models"})
+	stream, err := provider.Stream(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt2"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+
+	chunks, closed := testutil.DrainStream(stream, 5*time.Second)
+	require.True(t, closed, "stream must close after the connection drops")
+	require.NotEmpty(t, chunks)
+	var text string
+	for _, chunk := range chunks[:len(chunks)-1] {
+		require.NoError(t, chunk.Error)
+		text += chunk.Content()
+	}
+	assert.Equal(t, "Hel", text)
+
+	wormholeErr, ok := types.AsWormholeError(chunks[len(chunks)-1].Error)
+	require.True(t, ok, "final chunk must carry a *types.WormholeError")
+	assert.Equal(t, types.ErrorCodeNetwork, wormholeErr.Code)
+	assert.Equal(t, "huggingface", wormholeErr.Provider)
+}
+
+func TestStreamClosesPromptlyOnCancel(t *testing.T) {
+	t.Parallel()
+	server := testutil.StallingServer(t, "text/event-stream", "data:{\"token\":{\"text\":\"Hel\",\"special\":false},\"generated_text\":null}\n\n")
+	provider := New(types.ProviderConfig{APIKey: "hf_test", BaseURL: server.URL + "/models"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := provider.Stream(ctx, types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt2"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+
+	first := <-stream
+	require.NoError(t, first.Error)
+
+	cancel()
+	_, closed := testutil.DrainStream(stream, 2*time.Second)
+	assert.True(t, closed, "stream must close once ctx is canceled, even while the body is stalled")
+}
@@ -0,0 +1,244 @@
+package huggingface
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// buildGenerateRequest converts a text request to a text-generation payload
+func (p *Provider) buildGenerateRequest(request *types.TextRequest) (*generateRequest, error) {
+	if len(request.Tools) > 0 {
+		return nil, p.ValidationError("tools are not supported by the Hugging Face text-generation task")
+	}
+	if request.Logprobs {
+		return nil, p.ValidationError("logprobs are not supported by the Hugging Face text-generation task")
+	}
+	if request.N > 1 {
+		return nil, p.ValidationError("n above 1 is not supported by the Hugging Face text-generation task")
+	}
+	if request.WebSearch != nil {
+		return nil, p.ValidationError("web search is not supported by the Hugging Face text-generation task")
+	}
+	if request.Audio != nil {
+		return nil, p.ValidationError("audio output is not supported by the Hugging Face text-generation task")
+	}
+	if err := p.CheckSamplingParams(request.BaseRequest, "the Hugging Face text-generation task", types.SamplingTopK, types.SamplingRepetitionPenalty); err != nil {
+		return nil, err
+	}
+	prompt, err := renderPrompt(request.SystemPrompt, request.Messages)
+	if err != nil {
+		return nil, p.ValidationError(err.Error())
+	}
+
+	params := map[string]any{
+		"return_full_text": false,
+		"details":          true,
+	}
+	if request.MaxTokens != nil {
+		params["max_new_tokens"] = *request.MaxTokens
+	}
+	if request.Temperature != nil {
+		params["temperature"] = *request.Temperature
+	}
+	if request.TopP != nil {
+		params["top_p"] = *request.TopP
+	}
+	if len(request.Stop) > 0 {
+		params["stop"] = request.Stop
+	}
+	if request.Seed != nil {
+		params["seed"] = *request.Seed
+	}
+	if request.TopK != nil {
+		params["top_k"] = *request.TopK
+	}
+	if request.RepetitionPenalty != nil {
+		params["repetition_penalty"] = *request.RepetitionPenalty
+	}
+	for key, value := range request.ProviderOptions {
+		if key != "wait_for_model" && key != "use_cache" {
+			params[key] = value
+		}
+	}
+
+	return &generateRequest{
+		Inputs:     prompt,
+		Parameters: params,
+		Options:    p.requestOptions(request.ProviderOptions),
+	}, nil
+}
+
+// requestOptions builds the options object from the provider config and the
+// request's "wait_for_model" and "use_cache" provider options.
+func (p *Provider) requestOptions(providerOptions map[string]any) *options {
+	opts := &options{WaitForModel: true}
+	if wait, ok := p.Config.Params["wait_for_model"].(bool); ok {
+		opts.WaitForModel = wait
+	}
+	if wait, ok := providerOptions["wait_for_model"].(bool); ok {
+		opts.WaitForModel = wait
+	}
+	if useCache, ok := providerOptions["use_cache"].(bool); ok {
+		opts.UseCache = &useCache
+	}
+	return opts
+}
+
+// renderPrompt flattens a conversation into the single string the
+// text-generation task takes. A lone user message is sent as-is; anything
+// longer is rendered as "Role: content" turns ending in "Assistant:", since
+// the task applies no chat template.
+func renderPrompt(systemPrompt string, messages []types.Message) (string, error) {
+	if len(messages) == 0 {
+		return "", fmt.Errorf("no messages provided")
+	}
+	if systemPrompt == "" && len(messages) == 1 {
+		if user, ok := messages[0].(*types.UserMessage); ok {
+			if len(user.Media) > 0 {
+				return "", fmt.Errorf("media inputs are not supported by the Hugging Face text-generation task")
+			}
+			return user.Content, nil
+		}
+	}
+
+	var b strings.Builder
+	if systemPrompt != "" {
+		b.WriteString("System: " + systemPrompt + "\n\n")
+	}
+	for _, msg := range messages {
+		switch m := msg.(type) {
+		case *types.SystemMessage:
+			b.WriteString("System: " + m.Content + "\n\n")
+		case *types.DeveloperMessage:
+			b.WriteString("System: " + m.Content + "\n\n")
+		case *types.UserMessage:
+			if len(m.Media) > 0 {
+				return "", fmt.Errorf("media inputs are not supported by the Hugging Face text-generation task")
+			}
+			b.WriteString("User: " + m.Content + "\n\n")
+		case *types.AssistantMessage:
+			b.WriteString("Assistant: " + m.Content + "\n\n")
+		default:
+			return "", fmt.Errorf("%s messages are not supported by the Hugging Face text-generation task", msg.GetRole())
+		}
+	}
+	b.WriteString("Assistant:")
+	return b.String(), nil
+}
+
+// decodeGeneration accepts both the serverless list shape and the single
+// object returned by Inference Endpoints.
+func decodeGeneration(raw json.RawMessage) (*generation, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		var list []generation
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		if len(list) == 0 {
+			return &generation{}, nil
+		}
+		return &list[0], nil
+	}
+	var gen generation
+	if err := json.Unmarshal(raw, &gen); err != nil {
+		return nil, err
+	}
+	return &gen, nil
+}
+
+// transformTextResponse converts a generation to internal format
+func (p *Provider) transformTextResponse(gen *generation, model string) *types.TextResponse {
+	resp := &types.TextResponse{
+		ID:           fmt.Sprintf("huggingface_%d", time.Now().UnixNano()),
+		Provider:     p.Name(),
+		Model:        model,
+		Text:         gen.GeneratedText,
+		FinishReason: types.FinishReasonStop,
+		Created:      time.Now(),
+	}
+	if gen.Details != nil {
+		resp.FinishReason = mapFinishReason(gen.Details.FinishReason)
+		resp.RawFinishReason = gen.Details.FinishReason
+		resp.Usage = &types.Usage{
+			CompletionTokens: gen.Details.GeneratedTokens,
+			TotalTokens:      gen.Details.GeneratedTokens,
+		}
+	}
+	return resp
+}
+
+// parseStreamEvent parses one streamed text-generation event
+func (p *Provider) parseStreamEvent(data []byte, model string) (*types.TextChunk, error) {
+	var event streamEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	if event.Error != "" {
+		return nil, p.ProviderError(event.Error)
+	}
+
+	chunk := &types.TextChunk{Model: model}
+	if event.Token != nil && !event.Token.Special {
+		chunk.Text = event.Token.Text
+		chunk.Delta = &types.ChunkDelta{Content: event.Token.Text}
+	}
+	if event.GeneratedText != nil {
+		reason := types.FinishReasonStop
+		if event.Details != nil {
+			reason = mapFinishReason(event.Details.FinishReason)
+			chunk.RawFinishReason = event.Details.FinishReason
+			chunk.Usage = &types.Usage{
+				CompletionTokens: event.Details.GeneratedTokens,
+				TotalTokens:      event.Details.GeneratedTokens,
+			}
+		}
+		chunk.FinishReason = &reason
+		chunk.Provider = p.Name()
+	}
+	return chunk, nil
+}
+
+// mapFinishReason maps TGI finish reasons (length, eos_token, stop_sequence)
+// to internal format. TGI omits the reason on plain stops.
+func mapFinishReason(reason string) types.FinishReason {
+	if reason == "" {
+		return types.FinishReasonStop
+	}
+	return types.NormalizeFinishReason(reason)
+}
+
+// decodeVector decodes one feature-extraction result. Sentence embedding
+// models return a vector; plain encoders return a vector per token, which is
+// mean-pooled.
+func decodeVector(raw json.RawMessage) ([]float64, error) {
+	var vector []float64
+	if err := json.Unmarshal(raw, &vector); err == nil {
+		return vector, nil
+	}
+	var tokens [][]float64
+	if err := json.Unmarshal(raw, &tokens); err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty feature-extraction result")
+	}
+	pooled := make([]float64, len(tokens[0]))
+	for _, token := range tokens {
+		if len(token) != len(pooled) {
+			return nil, fmt.Errorf("ragged feature-extraction result")
+		}
+		for i, v := range token {
+			pooled[i] += v
+		}
+	}
+	for i := range pooled {
+		pooled[i] /= float64(len(tokens))
+	}
+	return pooled, nil
+}
@@ -0,0 +1,65 @@
+package huggingface
+
+import "encoding/json"
+
+// Hugging Face Inference API request/response types for the text-generation
+// and feature-extraction tasks. Both tasks share the {inputs, parameters,
+// options} envelope and are posted to the model's URL.
+
+// generateRequest represents a text-generation request
+type generateRequest struct {
+	Inputs     string         `json:"inputs"`
+	Parameters map[string]any `json:"parameters,omitempty"`
+	Options    *options       `json:"options,omitempty"`
+	Stream     bool           `json:"stream,omitempty"`
+}
+
+// featureExtractionRequest represents a feature-extraction (embeddings) request
+type featureExtractionRequest struct {
+	Inputs  []string `json:"inputs"`
+	Options *options `json:"options,omitempty"`
+}
+
+// options controls how the Inference API serves the request
+type options struct {
+	// WaitForModel holds the request while a cold model loads instead of
+	// failing with 503.
+	WaitForModel bool  `json:"wait_for_model"`
+	UseCache     *bool `json:"use_cache,omitempty"`
+}
+
+// generation is one text-generation result. The serverless API returns a
+// list of them; dedicated endpoints running TGI return a single object.
+type generation struct {
+	GeneratedText string   `json:"generated_text"`
+	Details       *details `json:"details,omitempty"`
+}
+
+// details is returned when parameters.details is true
+type details struct {
+	FinishReason    string `json:"finish_reason"`
+	GeneratedTokens int    `json:"generated_tokens"`
+}
+
+// streamEvent is one server-sent event of a streamed generation. The last
+// event carries GeneratedText and Details.
+type streamEvent struct {
+	Token *struct {
+		Text    string `json:"text"`
+		Special bool   `json:"special"`
+	} `json:"token"`
+	GeneratedText *string  `json:"generated_text"`
+	Details       *details `json:"details"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// errorResponse is the body of a failed request. A cold model answers 503
+// with EstimatedTime set to the expected load time in seconds.
+type errorResponse struct {
+	Error         string  `json:"error"`
+	EstimatedTime float64 `json:"estimated_time"`
+}
+
+// featureExtractionResponse holds one vector per input for sentence
+// embedding models, or one vector per token for plain encoders.
+type featureExtractionResponse []json.RawMessage
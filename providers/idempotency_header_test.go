@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestHTTPClientWrapperIdempotencyHeader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		key    string
+		want   string
+	}{
+		{name: "header and key", header: "Idempotency-Key", key: "abc123", want: "abc123"},
+		{name: "no header configured", key: "abc123"},
+		{name: "no key in context", header: "Idempotency-Key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Idempotency-Key")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			t.Cleanup(server.Close)
+
+			config := types.ProviderConfig{IdempotencyHeader: tt.header}
+			wrapper := NewHTTPClientWrapper("test", config, nil, &NoAuthStrategy{}, server.Client())
+			ctx := types.ContextWithIdempotencyKey(context.Background(), tt.key)
+			var out map[string]any
+			require.NoError(t, wrapper.DoRequest(ctx, http.MethodPost, server.URL, map[string]string{}, &out))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
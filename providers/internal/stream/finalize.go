@@ -0,0 +1,146 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// drainLimit bounds how much of an unread response body is discarded before
+// closing it, so the connection can be reused without reading an unbounded
+// tail.
+const drainLimit = 64 << 10 // 64 KiB
+
+// InterruptedError reports a stream cut off by the transport: a read error or
+// an EOF before the terminal event. It is retryable.
+func InterruptedError(cause error) error {
+	return types.WrapProviderError("", types.ErrorCodeNetwork, "stream interrupted", cause)
+}
+
+// ChunkError reports a stream event the provider sent but that could not be
+// used: malformed JSON or an in-band error event. Errors that are already
+// typed, such as a content block, are returned unchanged.
+func ChunkError(cause error) error {
+	if _, ok := types.AsWormholeError(cause); ok {
+		return cause
+	}
+	return types.WrapProviderError("", types.ErrorCodeProvider, "stream event rejected", cause)
+}
+
+// DrainAndClose closes a response body. When the stream ended cleanly, at
+// EOF after its terminal event or at [DONE], it first discards up to
+// drainLimit unread bytes so the HTTP connection returns to the pool. Any
+// other exit (a parse error, a transport error, ctx done) closes the body at
+// once: the connection is not worth saving and reading on could block.
+func DrainAndClose(ctx context.Context, body io.ReadCloser, clean bool) {
+	if clean && ctx.Err() == nil {
+		_, _ = io.CopyN(io.Discard, body, drainLimit)
+	}
+	_ = body.Close()
+}
+
+// Finalize is the last stage of every provider stream and gives callers these
+// guarantees:
+//   - the returned channel closes as soon as ctx is done, even while an
+//     upstream stage is still blocked reading the response body;
+//   - the terminal chunk is stamped with provider;
+//   - an error ends the stream: it arrives as one final chunk whose Error is a
+//     *types.WormholeError naming provider, and nothing follows it;
+//   - when ctx is done, a receiver still waiting on the channel gets one final
+//     chunk carrying ctx's error (a timeout or a cancellation) before it
+//     closes, so a canceled stream is distinguishable from a finished one.
+//
+// Whatever upstream sends after Finalize stops reading is discarded so the
+// upstream goroutines can exit.
+func Finalize(ctx context.Context, provider string, in <-chan types.TextChunk) <-chan types.TextChunk {
+	out := make(chan types.TextChunk)
+	go func() {
+		defer close(out)
+		defer func() { go discard(in) }()
+		send := func(chunk types.TextChunk) bool {
+			// Checked first so a canceled stream never delivers another chunk
+			// to a receiver that happens to be waiting.
+			select {
+			case <-ctx.Done():
+				return false
+			default:
+			}
+			select {
+			case out <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		// canceled reports ctx's error without blocking: a receiver that has
+		// walked away must not keep this goroutine alive.
+		canceled := func() {
+			select {
+			case out <- types.TextChunk{Error: StreamError(provider, ctx.Err()), Provider: provider}:
+			default:
+			}
+		}
+
+		for {
+			var chunk types.TextChunk
+			var ok bool
+			select {
+			case chunk, ok = <-in:
+			case <-ctx.Done():
+				canceled()
+				return
+			}
+			if !ok {
+				return
+			}
+			if chunk.Error != nil {
+				chunk.Error = StreamError(provider, chunk.Error)
+				chunk.Provider = provider
+				if !send(chunk) {
+					canceled()
+				}
+				return
+			}
+			if chunk.IsDone() {
+				chunk.Provider = provider
+			}
+			if !send(chunk) {
+				canceled()
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// StreamError returns err as a *types.WormholeError attributed to provider.
+// Errors that are not already typed are classified by cause: context errors
+// are timeouts, anything else is a provider error.
+func StreamError(provider string, err error) *types.WormholeError {
+	if wormholeErr, ok := types.AsWormholeError(err); ok {
+		if wormholeErr.Provider == "" {
+			return wormholeErr.WithProvider(provider)
+		}
+		return wormholeErr
+	}
+
+	code, message := types.ErrorCodeProvider, "stream failed"
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code, message = types.ErrorCodeTimeout, "stream timed out"
+	case errors.Is(err, context.Canceled):
+		code, message = types.ErrorCodeRequest, "stream canceled"
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		code, message = types.ErrorCodeNetwork, "stream interrupted"
+	}
+	wrapped, _ := types.AsWormholeError(types.WrapProviderError(provider, code, message, err))
+	return wrapped
+}
+
+// discard drains in until its producer closes it.
+func discard(in <-chan types.TextChunk) {
+	for range in {
+	}
+}
@@ -0,0 +1,155 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestFinalizeClosesOnCancelWhileUpstreamBlocked(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan types.TextChunk) // never written, never closed
+	out := Finalize(ctx, "test", in)
+
+	cancel()
+	_, closed := testutil.DrainStream(out, time.Second)
+	assert.True(t, closed, "Finalize must close its output once ctx is done")
+}
+
+func TestFinalizeReportsCancelToWaitingReceiver(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan types.TextChunk) // never written, never closed
+	out := Finalize(ctx, "test", in)
+
+	received := make(chan types.TextChunk, 1)
+	go func() { received <- <-out }()
+	time.Sleep(20 * time.Millisecond) // let the receiver park on out
+	cancel()
+
+	select {
+	case chunk := <-received:
+		require.Error(t, chunk.Error, "a canceled stream must not close silently")
+		assert.ErrorIs(t, chunk.Error, context.Canceled)
+		assert.Equal(t, "test", chunk.Provider)
+		wormholeErr, ok := types.AsWormholeError(chunk.Error)
+		require.True(t, ok)
+		assert.Equal(t, types.ErrorCodeRequest, wormholeErr.Code)
+	case <-time.After(time.Second):
+		t.Fatal("no chunk after cancel")
+	}
+	_, closed := testutil.DrainStream(out, time.Second)
+	assert.True(t, closed)
+}
+
+func TestFinalizeTypesErrorsAndStopsAfterThem(t *testing.T) {
+	t.Parallel()
+	in := make(chan types.TextChunk, 3)
+	in <- types.TextChunk{Text: "partial"}
+	in <- types.TextChunk{Error: errors.New("boom")}
+	in <- types.TextChunk{Text: "after error"}
+	close(in)
+
+	chunks, closed := testutil.DrainStream(Finalize(context.Background(), "test", in), time.Second)
+	require.True(t, closed)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "partial", chunks[0].Text)
+
+	wormholeErr, ok := types.AsWormholeError(chunks[1].Error)
+	require.True(t, ok, "error chunk must carry a *types.WormholeError")
+	assert.Equal(t, "test", wormholeErr.Provider)
+	assert.Equal(t, "test", chunks[1].Provider)
+	assert.Equal(t, types.ErrorCodeProvider, wormholeErr.Code)
+}
+
+func TestFinalizeStampsTerminalChunk(t *testing.T) {
+	t.Parallel()
+	stop := types.FinishReasonStop
+	in := make(chan types.TextChunk, 2)
+	in <- types.TextChunk{Text: "hi"}
+	in <- types.TextChunk{FinishReason: &stop}
+	close(in)
+
+	chunks, closed := testutil.DrainStream(Finalize(context.Background(), "test", in), time.Second)
+	require.True(t, closed)
+	require.Len(t, chunks, 2)
+	assert.Empty(t, chunks[0].Provider)
+	assert.Equal(t, "test", chunks[1].Provider)
+}
+
+func TestStreamErrorClassification(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		err   error
+		cause error
+		code  types.ErrorCode
+	}{
+		{"deadline", context.DeadlineExceeded, context.DeadlineExceeded, types.ErrorCodeTimeout},
+		{"canceled", context.Canceled, context.Canceled, types.ErrorCodeRequest},
+		{"unexpected eof", io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, types.ErrorCodeNetwork},
+		{"already typed", InterruptedError(io.EOF), io.EOF, types.ErrorCodeNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := StreamError("test", tt.err)
+			assert.Equal(t, tt.code, got.Code)
+			assert.Equal(t, "test", got.Provider)
+			assert.ErrorIs(t, got, tt.cause)
+		})
+	}
+}
+
+func TestChunkErrorKeepsTypedErrors(t *testing.T) {
+	t.Parallel()
+	blocked := types.NewWormholeError(types.ErrorCodeContentBlocked, "blocked", false)
+	assert.Same(t, blocked, ChunkError(blocked))
+
+	wrapped, ok := types.AsWormholeError(ChunkError(errors.New("bad json")))
+	require.True(t, ok)
+	assert.Equal(t, types.ErrorCodeProvider, wrapped.Code)
+}
+
+type trackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDrainAndClose(t *testing.T) {
+	t.Parallel()
+	body := &trackingBody{Reader: strings.NewReader("leftover")}
+	DrainAndClose(context.Background(), body, true)
+	assert.True(t, body.closed)
+	rest, _ := io.ReadAll(body.Reader)
+	assert.Empty(t, rest, "unread tail is drained so the connection can be reused")
+
+	body = &trackingBody{Reader: strings.NewReader("leftover")}
+	DrainAndClose(context.Background(), body, false)
+	assert.True(t, body.closed)
+	rest, _ = io.ReadAll(body.Reader)
+	assert.Equal(t, "leftover", string(rest), "an error exit closes without reading")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	body = &trackingBody{Reader: strings.NewReader("leftover")}
+	DrainAndClose(ctx, body, true)
+	assert.True(t, body.closed)
+	rest, _ = io.ReadAll(body.Reader)
+	assert.Equal(t, "leftover", string(rest), "a canceled stream closes without reading")
+}
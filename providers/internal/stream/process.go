@@ -3,6 +3,7 @@ package stream
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
@@ -26,8 +27,9 @@ func NewStreamProcessor(r io.Reader, transformer func([]byte) (*types.TextChunk,
 // Process processes the stream and sends chunks to the channel.
 // Every send is guarded by ctx.Done() so the goroutine exits if the
 // consumer stops reading (otherwise the send blocks forever, holding
-// the upstream body open).
-func (p *StreamProcessor) Process(ctx context.Context, chunks chan<- types.TextChunk) {
+// the upstream body open). It reports whether the stream ended cleanly:
+// at [DONE], or at EOF after a terminal chunk.
+func (p *StreamProcessor) Process(ctx context.Context, chunks chan<- types.TextChunk) (clean bool) {
 	defer close(chunks)
 
 	var finished bool
@@ -38,17 +40,23 @@ func (p *StreamProcessor) Process(ctx context.Context, chunks chan<- types.TextC
 			if err == io.EOF {
 				if !finished {
 					select {
-					case chunks <- types.TextChunk{Error: fmt.Errorf("stream ended prematurely: no terminal finish event received")}:
+					case chunks <- types.TextChunk{Error: InterruptedError(errors.New("stream ended prematurely: no terminal finish event received"))}:
 					case <-ctx.Done():
 					}
 				}
+				return finished
 			} else {
+				if errors.Is(err, errSSEFrameTooLarge) {
+					err = ChunkError(err)
+				} else {
+					err = InterruptedError(err)
+				}
 				select {
 				case chunks <- types.TextChunk{Error: err}:
 				case <-ctx.Done():
 				}
 			}
-			return
+			return false
 		}
 
 		// Skip non-data events
@@ -58,17 +66,17 @@ func (p *StreamProcessor) Process(ctx context.Context, chunks chan<- types.TextC
 
 		// Handle [DONE] marker
 		if event.Data == streamDoneMarker {
-			return
+			return true
 		}
 
 		// Transform the data
 		chunk, err := p.transformer([]byte(event.Data))
 		if err != nil {
 			select {
-			case chunks <- types.TextChunk{Error: fmt.Errorf("failed to parse chunk: %w", err)}:
+			case chunks <- types.TextChunk{Error: ChunkError(fmt.Errorf("failed to parse chunk: %w", err))}:
 			case <-ctx.Done():
 			}
-			return
+			return false
 		}
 
 		if chunk != nil {
@@ -78,7 +86,7 @@ func (p *StreamProcessor) Process(ctx context.Context, chunks chan<- types.TextC
 			select {
 			case chunks <- *chunk:
 			case <-ctx.Done():
-				return
+				return false
 			}
 		}
 	}
@@ -86,7 +94,8 @@ func (p *StreamProcessor) Process(ctx context.Context, chunks chan<- types.TextC
 
 // ProcessSSE creates and processes an SSE stream in a goroutine, returning the channel.
 // This is a convenience function that combines channel creation, goroutine launch, and processing.
-// ctx cancellation unblocks the producer goroutine's sends and lets the body close.
+// ctx cancellation unblocks the producer goroutine's sends and lets the body close; the
+// body is always closed, after the channel, and drained first only after a clean end.
 func ProcessSSE(
 	ctx context.Context,
 	body io.ReadCloser,
@@ -95,18 +104,18 @@ func ProcessSSE(
 ) <-chan types.TextChunk {
 	chunks := make(chan types.TextChunk, bufferSize)
 	go func() {
-		defer func() {
-			_ = body.Close()
-		}()
+		clean := false
+		defer func() { DrainAndClose(ctx, body, clean) }()
 		processor := NewStreamProcessor(body, transformer)
-		processor.Process(ctx, chunks)
+		clean = processor.Process(ctx, chunks)
 	}()
 	return chunks
 }
 
 // ProcessNDJSON processes an NDJSON (newline-delimited JSON) stream.
 // Each line is a complete JSON object with no SSE framing (used by Ollama).
-// ctx cancellation unblocks the producer goroutine's sends and lets the body close.
+// ctx cancellation unblocks the producer goroutine's sends and lets the body close; the
+// body is always closed, after the channel, and drained first only after the terminal chunk.
 func ProcessNDJSON(
 	ctx context.Context,
 	body io.ReadCloser,
@@ -115,9 +124,10 @@ func ProcessNDJSON(
 ) <-chan types.TextChunk {
 	chunks := make(chan types.TextChunk, bufferSize)
 	go func() {
+		clean := false
 		defer func() {
 			close(chunks)
-			_ = body.Close()
+			DrainAndClose(ctx, body, clean)
 		}()
 		scanner := bufio.NewScanner(body)
 		// Ollama can return large final chunks with usage data.
@@ -130,7 +140,7 @@ func ProcessNDJSON(
 			chunk, err := transformer(line)
 			if err != nil {
 				select {
-				case chunks <- types.TextChunk{Error: fmt.Errorf("failed to parse NDJSON chunk: %w", err)}:
+				case chunks <- types.TextChunk{Error: ChunkError(fmt.Errorf("failed to parse NDJSON chunk: %w", err))}:
 				case <-ctx.Done():
 					return
 				}
@@ -145,18 +155,19 @@ func ProcessNDJSON(
 				return
 			}
 			if chunk.IsDone() {
+				clean = true
 				return
 			}
 		}
 		if err := scanner.Err(); err != nil {
 			select {
-			case chunks <- types.TextChunk{Error: fmt.Errorf("NDJSON scan error: %w", err)}:
+			case chunks <- types.TextChunk{Error: InterruptedError(fmt.Errorf("NDJSON scan error: %w", err))}:
 			case <-ctx.Done():
 			}
 			return
 		}
 		select {
-		case chunks <- types.TextChunk{Error: fmt.Errorf("NDJSON stream ended before terminal chunk")}:
+		case chunks <- types.TextChunk{Error: InterruptedError(errors.New("NDJSON stream ended before terminal chunk"))}:
 		case <-ctx.Done():
 		}
 	}()
@@ -0,0 +1,121 @@
+package stream
+
+import (
+	"context"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// FinalUsage moves the token usage reported during a stream onto its final
+// chunk, so consumers read usage from one place regardless of provider.
+// Providers report usage piecemeal or late: Anthropic sends input tokens on
+// message_start and output tokens on message_delta, while OpenAI and Gemini
+// send usage after the finish_reason chunk. The terminal chunk is therefore
+// held until the stream ends and usage-only chunks that trail it are absorbed;
+// earlier chunks are forwarded without their usage.
+// A stream that ends in an error carries the usage seen so far on the error
+// chunk. Sole closer of out; exits when in closes or ctx is done.
+func FinalUsage(ctx context.Context, in <-chan types.TextChunk) <-chan types.TextChunk {
+	out := make(chan types.TextChunk)
+	go func() {
+		defer close(out)
+		send := func(chunk types.TextChunk) bool {
+			select {
+			case out <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var usage *types.Usage
+		var final *types.TextChunk
+		for chunk := range in {
+			if chunk.Usage != nil {
+				usage = mergeUsage(usage, chunk.Usage)
+				chunk.Usage = nil
+			}
+			if final != nil {
+				if isUsageOnly(chunk) {
+					continue
+				}
+				// Content after the terminal chunk: release the held chunk
+				// first so ordering is preserved.
+				final.Usage = usage
+				if !send(*final) {
+					return
+				}
+				final = nil
+			}
+			switch {
+			case chunk.Error != nil:
+				chunk.Usage = usage
+				send(chunk)
+				return
+			case chunk.IsDone():
+				held := chunk
+				final = &held
+			case isUsageOnly(chunk) && chunk.ID == "" && chunk.Model == "":
+				continue
+			default:
+				if !send(chunk) {
+					return
+				}
+			}
+		}
+
+		switch {
+		case final != nil:
+			final.Usage = usage
+			send(*final)
+		case usage != nil:
+			// The stream ended without a finish reason (e.g. a bare [DONE]);
+			// still deliver the usage it reported.
+			send(types.TextChunk{Usage: usage})
+		}
+	}()
+	return out
+}
+
+// mergeUsage folds a later usage report into the running total. Counts are
+// cumulative where providers repeat them, so a later non-zero count replaces
+// an earlier one; the total is at least prompt plus completion tokens, which
+// covers providers that report the two halves in separate events.
+func mergeUsage(acc, next *types.Usage) *types.Usage {
+	if next.IsZero() {
+		return acc
+	}
+	var merged types.Usage
+	if acc != nil {
+		merged = *acc
+	}
+	replace := func(dst *int, src int) {
+		if src != 0 {
+			*dst = src
+		}
+	}
+	replace(&merged.PromptTokens, next.PromptTokens)
+	replace(&merged.CompletionTokens, next.CompletionTokens)
+	replace(&merged.TotalTokens, next.TotalTokens)
+	replace(&merged.CacheReadTokens, next.CacheReadTokens)
+	replace(&merged.CacheWriteTokens, next.CacheWriteTokens)
+	replace(&merged.ReasoningTokens, next.ReasoningTokens)
+	if sum := merged.PromptTokens + merged.CompletionTokens; merged.TotalTokens < sum {
+		merged.TotalTokens = sum
+	}
+	return &merged
+}
+
+// isUsageOnly reports whether chunk carries nothing for the consumer once its
+// usage has been taken.
+func isUsageOnly(chunk types.TextChunk) bool {
+	if chunk.Text != "" || chunk.Refusal != "" || chunk.Thinking != nil ||
+		chunk.ToolCall != nil || len(chunk.ToolCalls) > 0 ||
+		chunk.FinishReason != nil || chunk.Error != nil {
+		return false
+	}
+	if d := chunk.Delta; d != nil {
+		return d.Content == "" && d.Refusal == "" && d.Thinking == nil && len(d.ToolCalls) == 0
+	}
+	return true
+}
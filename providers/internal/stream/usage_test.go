@@ -0,0 +1,78 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func collectFinalUsage(t *testing.T, chunks ...types.TextChunk) []types.TextChunk {
+	t.Helper()
+	in := make(chan types.TextChunk, len(chunks))
+	for _, chunk := range chunks {
+		in <- chunk
+	}
+	close(in)
+
+	var out []types.TextChunk
+	for chunk := range FinalUsage(context.Background(), in) {
+		out = append(out, chunk)
+	}
+	return out
+}
+
+func TestFinalUsageAbsorbsTrailingUsage(t *testing.T) {
+	t.Parallel()
+	stop := types.FinishReasonStop
+
+	out := collectFinalUsage(t,
+		types.TextChunk{ID: "c1", Text: "hi"},
+		types.TextChunk{ID: "c1", FinishReason: &stop},
+		types.TextChunk{ID: "c1", Usage: &types.Usage{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4}},
+	)
+	require.Len(t, out, 2)
+	assert.Nil(t, out[0].Usage)
+	assert.True(t, out[1].IsDone())
+	assert.Equal(t, &types.Usage{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4}, out[1].Usage)
+}
+
+func TestFinalUsageMergesSplitReports(t *testing.T) {
+	t.Parallel()
+	stop := types.FinishReasonStop
+
+	out := collectFinalUsage(t,
+		types.TextChunk{ID: "msg_1", Model: "claude", Usage: &types.Usage{PromptTokens: 100, TotalTokens: 100, CacheReadTokens: 30}},
+		types.TextChunk{Delta: &types.ChunkDelta{Content: "hello"}},
+		types.TextChunk{FinishReason: &stop, Usage: &types.Usage{CompletionTokens: 50, TotalTokens: 50}},
+	)
+	require.Len(t, out, 3)
+	assert.Equal(t, "msg_1", out[0].ID, "metadata chunks are kept")
+	assert.Nil(t, out[0].Usage)
+	assert.Equal(t, &types.Usage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150, CacheReadTokens: 30}, out[2].Usage)
+}
+
+func TestFinalUsageOnErrorAndUnterminatedStreams(t *testing.T) {
+	t.Parallel()
+	usage := &types.Usage{PromptTokens: 5, TotalTokens: 5}
+
+	out := collectFinalUsage(t,
+		types.TextChunk{Usage: usage},
+		types.TextChunk{Text: "partial"},
+		types.TextChunk{Error: errors.New("connection reset")},
+	)
+	require.Len(t, out, 2)
+	require.Error(t, out[1].Error)
+	assert.Equal(t, usage, out[1].Usage)
+
+	out = collectFinalUsage(t,
+		types.TextChunk{Text: "partial"},
+		types.TextChunk{Usage: usage},
+	)
+	require.Len(t, out, 2)
+	assert.Equal(t, usage, out[1].Usage)
+}
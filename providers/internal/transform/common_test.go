@@ -24,6 +24,13 @@ func TestMapFinishReason(t *testing.T) {
 		"content_filter":            types.FinishReasonContentFilter,
 		"safety":                    types.FinishReasonContentFilter,
 		"recitation":                types.FinishReasonContentFilter,
+		"refusal":                   types.FinishReasonContentFilter,
+		"BLOCKLIST":                 types.FinishReasonContentFilter,
+		"stop_sequence":             types.FinishReasonStop,
+		"eos_token":                 types.FinishReasonStop,
+		"max_output_tokens":         types.FinishReasonLength,
+		"pause_turn":                types.FinishReasonOther,
+		"MALFORMED_FUNCTION_CALL":   types.FinishReasonOther,
 		"other":                     types.FinishReasonOther,
 		"finish_reason_unspecified": types.FinishReasonOther,
 		"unexpected":                types.FinishReasonOther,
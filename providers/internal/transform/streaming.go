@@ -19,6 +19,7 @@ type StreamingConfig struct {
 	ModelPath             string // e.g., "model"
 	ThinkingPath          string // e.g., "choices.0.delta.reasoning_content"
 	RefusalPath           string // e.g., "choices.0.delta.refusal"
+	LogprobsPath          string // e.g., "choices.0.logprobs.content"
 	ExtraFinishReasonPath string // secondary path when FinishReasonPath is a bool true (e.g., Ollama "done_reason")
 
 	// Field adapters for provider-specific formats
@@ -215,6 +216,17 @@ func (t *StreamingTransformer) ParseChunk(data []byte) (*types.TextChunk, error)
 		}
 	}
 
+	// Extract per-token log probabilities
+	if t.config.LogprobsPath != "" {
+		if val := t.getFieldByPath(response, t.config.LogprobsPath); val != nil {
+			logprobs, err := parseLogprobs(val)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse logprobs: %w", err)
+			}
+			chunk.Logprobs = logprobs
+		}
+	}
+
 	// Extract finish reason
 	if t.config.FinishReasonPath != "" {
 		if val := t.getFieldByPath(response, t.config.FinishReasonPath); val != nil {
@@ -239,6 +251,9 @@ func (t *StreamingTransformer) ParseChunk(data []byte) (*types.TextChunk, error)
 			}
 
 			if reasonStr != "" {
+				if reasonStr != "true" {
+					chunk.RawFinishReason = reasonStr
+				}
 				if t.config.FinishReasonAdapter != nil {
 					reason := t.config.FinishReasonAdapter(reasonStr)
 					chunk.FinishReason = &reason
@@ -63,6 +63,7 @@ func NewOpenAIStreamingTransformer() *StreamingTransformer {
 		ModelPath:           "model",
 		ThinkingPath:        "choices.0.delta.reasoning_content",
 		RefusalPath:         "choices.0.delta.refusal",
+		LogprobsPath:        "choices.0.logprobs.content",
 		FinishReasonAdapter: MapFinishReason,
 		UsageAdapter:        openAIStreamUsage,
 		ReturnsBatch:        false,
@@ -138,3 +139,17 @@ func NewGeminiStreamingTransformer() *StreamingTransformer {
 		ChunkType:    "text_chunk",
 	})
 }
+
+// parseLogprobs decodes an OpenAI-shaped logprobs.content array, whose field
+// names types.TokenLogprob shares.
+func parseLogprobs(data any) ([]types.TokenLogprob, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var logprobs []types.TokenLogprob
+	if err := json.Unmarshal(raw, &logprobs); err != nil {
+		return nil, err
+	}
+	return logprobs, nil
+}
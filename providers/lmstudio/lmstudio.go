@@ -0,0 +1,173 @@
+package lmstudio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/providers/openai"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// DefaultBaseURL is LM Studio's OpenAI-compatible API root.
+const DefaultBaseURL = "http://localhost:1234/v1"
+
+// Provider implements LM Studio. Generation goes through the
+// OpenAI-compatible API; model management uses LM Studio's native REST API,
+// served from the same host under /api/v1.
+type Provider struct {
+	*openai.Provider
+}
+
+var _ types.Provider = (*Provider)(nil)
+
+// New creates a new LM Studio provider. Without an API key requests are sent
+// unauthenticated, as LM Studio's server expects unless authentication is
+// turned on. To let LM Studio load a model on demand and unload it after it
+// sits idle, pass the idle time in seconds as the "ttl" provider option on a
+// request.
+func New(config types.ProviderConfig) *Provider {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	if config.EffectiveAPIKey() == "" {
+		config.NoAuth = true
+	}
+	return &Provider{
+		Provider: openai.NewWithName("lmstudio", config),
+	}
+}
+
+// NativeURL returns the root of LM Studio's native REST API for an
+// OpenAI-compatible base URL, e.g. "http://localhost:1234" for
+// "http://localhost:1234/v1".
+func NativeURL(baseURL string) string {
+	return strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/v1")
+}
+
+// Model is a model downloaded to LM Studio.
+type Model struct {
+	Key              string
+	DisplayName      string
+	Type             string // "llm" or "embedding"
+	Publisher        string
+	Architecture     string
+	SizeBytes        int64
+	MaxContextLength int
+	Vision           bool
+	ToolUse          bool
+	Instances        []Instance
+}
+
+// Instance is a loaded copy of a model.
+type Instance struct {
+	ID            string
+	ContextLength int
+}
+
+// Loaded reports whether any instance of the model is loaded.
+func (m Model) Loaded() bool {
+	return len(m.Instances) > 0
+}
+
+// ContextLength returns the context length of the first loaded instance, or
+// the model's maximum when it is not loaded.
+func (m Model) ContextLength() int {
+	if len(m.Instances) > 0 && m.Instances[0].ContextLength > 0 {
+		return m.Instances[0].ContextLength
+	}
+	return m.MaxContextLength
+}
+
+// ListModels returns every downloaded model with its loaded instances.
+func (p *Provider) ListModels(ctx context.Context) ([]Model, error) {
+	var response modelsResponse
+	if err := p.DoRequest(ctx, http.MethodGet, p.nativeURL("/models"), nil, &response); err != nil {
+		return nil, p.WrapError(types.ErrorCodeProvider, "failed to list models", err)
+	}
+
+	models := make([]Model, 0, len(response.Models))
+	for _, m := range response.Models {
+		model := Model{
+			Key:              m.Key,
+			DisplayName:      m.DisplayName,
+			Type:             m.Type,
+			Publisher:        m.Publisher,
+			Architecture:     m.Architecture,
+			SizeBytes:        m.SizeBytes,
+			MaxContextLength: m.MaxContextLength,
+			Vision:           m.Capabilities.Vision,
+			ToolUse:          m.Capabilities.TrainedForToolUse,
+		}
+		for _, instance := range m.LoadedInstances {
+			model.Instances = append(model.Instances, Instance{ID: instance.ID, ContextLength: instance.Config.ContextLength})
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// LoadedModels returns the models that have at least one loaded instance.
+func (p *Provider) LoadedModels(ctx context.Context) ([]Model, error) {
+	models, err := p.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loaded := models[:0]
+	for _, model := range models {
+		if model.Loaded() {
+			loaded = append(loaded, model)
+		}
+	}
+	return loaded, nil
+}
+
+// LoadOptions configures LoadModel. Zero fields use LM Studio's defaults.
+type LoadOptions struct {
+	// ContextLength is the context window to load the model with, in tokens.
+	ContextLength int
+	// TTL unloads the instance after it has been idle this long.
+	TTL time.Duration
+}
+
+// LoadModel loads model and returns the new instance's ID, which
+// UnloadModel takes.
+func (p *Provider) LoadModel(ctx context.Context, model string, opts LoadOptions) (string, error) {
+	if model == "" {
+		return "", p.ValidationError("model is required")
+	}
+	payload := &loadRequest{
+		Model:         model,
+		ContextLength: opts.ContextLength,
+		TTL:           int(opts.TTL / time.Second),
+	}
+
+	var response loadResponse
+	if err := p.DoRequest(ctx, http.MethodPost, p.nativeURL("/models/load"), payload, &response); err != nil {
+		return "", p.WrapError(types.ErrorCodeProvider, fmt.Sprintf("failed to load model %s", model), err)
+	}
+	if response.InstanceID == "" {
+		return model, nil
+	}
+	return response.InstanceID, nil
+}
+
+// UnloadModel unloads a loaded instance. Instance IDs come from LoadModel or
+// Model.Instances; for a model loaded once the ID is the model key.
+func (p *Provider) UnloadModel(ctx context.Context, instanceID string) error {
+	if instanceID == "" {
+		return p.ValidationError("instance ID is required")
+	}
+
+	var response map[string]any
+	if err := p.DoRequest(ctx, http.MethodPost, p.nativeURL("/models/unload"), &unloadRequest{InstanceID: instanceID}, &response); err != nil {
+		return p.WrapError(types.ErrorCodeProvider, fmt.Sprintf("failed to unload model instance %s", instanceID), err)
+	}
+	return nil
+}
+
+func (p *Provider) nativeURL(path string) string {
+	return NativeURL(p.GetBaseURL()) + "/api/v1" + path
+}
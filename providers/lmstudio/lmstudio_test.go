@@ -0,0 +1,113 @@
+package lmstudio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+const modelsJSON = `{"models":[
+{"type":"llm","key":"qwen/qwen3-8b","display_name":"Qwen3 8B","publisher":"qwen","architecture":"qwen3","size_bytes":5000,"max_context_length":32768,
+ "loaded_instances":[{"id":"qwen/qwen3-8b","config":{"context_length":8192}}],"capabilities":{"trained_for_tool_use":true}},
+{"type":"embedding","key":"nomic-embed-text-v1.5","display_name":"Nomic Embed","max_context_length":2048,"loaded_instances":[]}
+]}`
+
+func newLMStudioTestProvider(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	noRetries := 0
+	return New(types.ProviderConfig{BaseURL: server.URL + "/v1", MaxRetries: &noRetries})
+}
+
+func TestNativeURL(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "http://localhost:1234", NativeURL("http://localhost:1234/v1"))
+	assert.Equal(t, "http://localhost:1234", NativeURL("http://localhost:1234/v1/"))
+	assert.Equal(t, "http://localhost:1234", NativeURL("http://localhost:1234"))
+}
+
+func TestProviderListModels(t *testing.T) {
+	t.Parallel()
+	provider := newLMStudioTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v1/models", r.URL.Path)
+		_, _ = w.Write([]byte(modelsJSON))
+	})
+
+	models, err := provider.ListModels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, models, 2)
+	assert.True(t, models[0].Loaded())
+	assert.Equal(t, 8192, models[0].ContextLength())
+	assert.True(t, models[0].ToolUse)
+	assert.False(t, models[1].Loaded())
+	assert.Equal(t, 2048, models[1].ContextLength())
+
+	loaded, err := provider.LoadedModels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "qwen/qwen3-8b", loaded[0].Key)
+}
+
+func TestProviderLoadAndUnloadModel(t *testing.T) {
+	t.Parallel()
+	provider := newLMStudioTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		switch r.URL.Path {
+		case "/api/v1/models/load":
+			var req loadRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, loadRequest{Model: "qwen/qwen3-8b", ContextLength: 16384, TTL: 600}, req)
+			_, _ = w.Write([]byte(`{"type":"llm","instance_id":"qwen/qwen3-8b:2","status":"loaded"}`))
+		case "/api/v1/models/unload":
+			var req unloadRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			if req.InstanceID != "qwen/qwen3-8b:2" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"no such instance"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"instance_id":"qwen/qwen3-8b:2"}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	ctx := context.Background()
+	id, err := provider.LoadModel(ctx, "qwen/qwen3-8b", LoadOptions{ContextLength: 16384, TTL: 10 * time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, "qwen/qwen3-8b:2", id)
+
+	require.NoError(t, provider.UnloadModel(ctx, id))
+	require.Error(t, provider.UnloadModel(ctx, "missing"))
+	require.Error(t, provider.UnloadModel(ctx, ""))
+}
+
+func TestProviderGeneratesThroughOpenAICompatibleAPI(t *testing.T) {
+	t.Parallel()
+	provider := newLMStudioTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, float64(300), req["ttl"])
+		_, _ = w.Write([]byte(`{"id":"1","model":"qwen/qwen3-8b","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	})
+
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "qwen/qwen3-8b", ProviderOptions: map[string]any{"ttl": 300}},
+		Messages:    []types.Message{types.NewUserMessage("hello")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hi", resp.Text)
+	assert.Equal(t, "lmstudio", resp.Provider)
+}
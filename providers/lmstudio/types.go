@@ -0,0 +1,47 @@
+package lmstudio
+
+// LM Studio native REST API request/response types
+
+// modelsResponse is the GET /api/v1/models response
+type modelsResponse struct {
+	Models []modelInfo `json:"models"`
+}
+
+// modelInfo is one downloaded model
+type modelInfo struct {
+	Type             string `json:"type"`
+	Key              string `json:"key"`
+	DisplayName      string `json:"display_name"`
+	Publisher        string `json:"publisher"`
+	Architecture     string `json:"architecture"`
+	SizeBytes        int64  `json:"size_bytes"`
+	MaxContextLength int    `json:"max_context_length"`
+	LoadedInstances  []struct {
+		ID     string `json:"id"`
+		Config struct {
+			ContextLength int `json:"context_length"`
+		} `json:"config"`
+	} `json:"loaded_instances"`
+	Capabilities struct {
+		Vision            bool `json:"vision"`
+		TrainedForToolUse bool `json:"trained_for_tool_use"`
+	} `json:"capabilities"`
+}
+
+// loadRequest is the POST /api/v1/models/load payload
+type loadRequest struct {
+	Model         string `json:"model"`
+	ContextLength int    `json:"context_length,omitempty"`
+	TTL           int    `json:"ttl,omitempty"` // idle seconds before unload
+}
+
+// loadResponse is the POST /api/v1/models/load response
+type loadResponse struct {
+	InstanceID string `json:"instance_id"`
+	Status     string `json:"status"`
+}
+
+// unloadRequest is the POST /api/v1/models/unload payload
+type unloadRequest struct {
+	InstanceID string `json:"instance_id"`
+}
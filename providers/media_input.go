@@ -0,0 +1,23 @@
+package providers
+
+import (
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// CheckAudioInput rejects a conversation whose user messages carry AudioMedia
+// for an API that cannot listen, rather than letting the transform drop the
+// clip silently. api names the endpoint in the error, e.g. "Anthropic".
+func (p *BaseProvider) CheckAudioInput(messages []types.Message, api string) error {
+	for _, message := range messages {
+		user, ok := message.(*types.UserMessage)
+		if !ok {
+			continue
+		}
+		for _, media := range user.Media {
+			if _, isAudio := media.(*types.AudioMedia); isAudio {
+				return p.ValidationError("audio input is not supported by " + api)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package ollama
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+	"github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+// TestGoldenPayloads pins the native /api/chat wire format.
+// Regenerate with WORMHOLE_UPDATE_GOLDEN=1 and review the fixture diff.
+func TestGoldenPayloads(t *testing.T) {
+	t.Parallel()
+
+	for name, request := range wormholetest.GoldenTextRequests("llama3.2") {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			wormholetest.AssertGoldenPayload(t, filepath.Join("testdata", "golden", name+".json"), func(baseURL string) {
+				provider, err := New(types.ProviderConfig{BaseURL: baseURL}.WithNoRetries())
+				require.NoError(t, err)
+				_, _ = provider.Text(context.Background(), request)
+			})
+		})
+	}
+}
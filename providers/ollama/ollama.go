@@ -57,6 +57,24 @@ func (p *Provider) Text(ctx context.Context, request types.TextRequest) (*types.
 	if request.ParallelToolCalls != nil {
 		return nil, p.ValidationError("parallel_tool_calls is not supported by Ollama")
 	}
+	if request.Logprobs {
+		return nil, p.ValidationError("logprobs are not supported by Ollama")
+	}
+	if request.N > 1 {
+		return nil, p.ValidationError("n above 1 is not supported by Ollama")
+	}
+	if request.WebSearch != nil {
+		return nil, p.ValidationError("web search is not supported by Ollama")
+	}
+	if request.Audio != nil {
+		return nil, p.ValidationError("audio output is not supported by Ollama")
+	}
+	if err := p.CheckAudioInput(request.Messages, "Ollama"); err != nil {
+		return nil, err
+	}
+	if err := p.CheckSamplingParams(request.BaseRequest, "Ollama", types.SamplingTopK, types.SamplingMinP, types.SamplingRepetitionPenalty); err != nil {
+		return nil, err
+	}
 	if _, _, err := providers.PrepareMessages(request.Messages); err != nil {
 		return nil, err
 	}
@@ -75,24 +93,10 @@ func (p *Provider) Text(ctx context.Context, request types.TextRequest) (*types.
 	return resp, nil
 }
 
-// stampProvider sets Provider on the terminal chunk. Sole closer of out;
-// exits when the upstream channel closes.
+// stampProvider ends the stream pipeline with providerstream.Finalize, which
+// stamps the terminal chunk, types errors, and closes promptly on cancellation.
 func (p *Provider) stampProvider(ctx context.Context, in <-chan types.TextChunk) <-chan types.TextChunk {
-	out := make(chan types.TextChunk)
-	go func() {
-		defer close(out)
-		for chunk := range in {
-			if chunk.IsDone() {
-				chunk.Provider = p.Name()
-			}
-			select {
-			case out <- chunk:
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-	return out
+	return providerstream.Finalize(ctx, p.Name(), in)
 }
 
 // Stream generates a streaming text response using Ollama's streaming chat API
@@ -100,6 +104,24 @@ func (p *Provider) Stream(ctx context.Context, request types.TextRequest) (<-cha
 	if request.ParallelToolCalls != nil {
 		return nil, p.ValidationError("parallel_tool_calls is not supported by Ollama")
 	}
+	if request.Logprobs {
+		return nil, p.ValidationError("logprobs are not supported by Ollama")
+	}
+	if request.N > 1 {
+		return nil, p.ValidationError("n above 1 is not supported by Ollama")
+	}
+	if request.WebSearch != nil {
+		return nil, p.ValidationError("web search is not supported by Ollama")
+	}
+	if request.Audio != nil {
+		return nil, p.ValidationError("audio output is not supported by Ollama")
+	}
+	if err := p.CheckAudioInput(request.Messages, "Ollama"); err != nil {
+		return nil, err
+	}
+	if err := p.CheckSamplingParams(request.BaseRequest, "Ollama", types.SamplingTopK, types.SamplingMinP, types.SamplingRepetitionPenalty); err != nil {
+		return nil, err
+	}
 	if _, _, err := providers.PrepareMessages(request.Messages); err != nil {
 		return nil, err
 	}
@@ -198,7 +220,7 @@ func (p *Provider) Images(ctx context.Context, request types.ImagesRequest) (*ty
 
 // Audio handles both speech-to-text and text-to-speech
 func (p *Provider) Audio(ctx context.Context, request types.AudioRequest) (*types.AudioResponse, error) {
-	if request.Type == types.AudioRequestTypeSTT {
+	if request.Type == types.AudioRequestTypeSTT || request.Type == types.AudioRequestTypeTranslation {
 		return p.handleSpeechToText(ctx, request)
 	}
 
@@ -171,7 +171,7 @@ func TestProviderModelManagement(t *testing.T) {
 			require.NoError(t, json.NewEncoder(w).Encode(modelsResponse{
 				Models: []modelInfo{{Name: "llama3:latest", Model: "llama3", Size: 123}},
 			}))
-		case "/api/pull", "/api/show", "/api/delete":
+		case "/api/show", "/api/delete":
 			assert.Contains(t, []string{http.MethodPost, http.MethodDelete}, r.Method)
 			var req map[string]any
 			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
@@ -187,8 +187,6 @@ func TestProviderModelManagement(t *testing.T) {
 	require.Len(t, models.Models, 1)
 	assert.Equal(t, "llama3:latest", models.Models[0].Name)
 
-	require.NoError(t, provider.PullModel(context.Background(), "llama3"))
-
 	info, err := provider.ShowModel(context.Background(), "llama3")
 	require.NoError(t, err)
 	assert.Equal(t, "ok", info["status"])
@@ -270,6 +268,16 @@ func TestTransformHelpers(t *testing.T) {
 	assert.Equal(t, seed, *payload.Options.Seed)
 	assert.Equal(t, []string{"stop"}, payload.Options.Stop)
 
+	typedTopK := 40
+	minP := float32(0.05)
+	repetition := float32(1.1)
+	req.TopK, req.MinP, req.RepetitionPenalty = &typedTopK, &minP, &repetition
+	typed := provider.buildChatPayload(req)
+	assert.Equal(t, typedTopK, *typed.Options.TopK, "typed TopK wins over ProviderOptions")
+	assert.Equal(t, minP, *typed.Options.MinP)
+	assert.Equal(t, repetition, *typed.Options.RepeatPenalty)
+	req.TopK, req.MinP, req.RepetitionPenalty = nil, nil, nil
+
 	require.Len(t, payload.Messages, 3)
 	assert.Equal(t, roleSystem, payload.Messages[0].Role)
 	assert.Equal(t, roleAssistant, payload.Messages[1].Role)
@@ -288,3 +296,106 @@ func convertMultimodalPartsForTest(parts []types.MessagePart) []string {
 	_, images := convertMultimodalParts(parts)
 	return images
 }
+
+func TestProviderPullModelStreamsProgress(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOllamaTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/pull", r.URL.Path)
+		var req pullRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "llama3", req.Model)
+		assert.True(t, req.Stream)
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(`{"status":"pulling manifest"}
+{"status":"downloading sha256:abc","digest":"sha256:abc","total":200,"completed":50}
+{"status":"success"}
+`))
+	})
+
+	progress, err := provider.PullModel(context.Background(), "llama3")
+	require.NoError(t, err)
+
+	var updates []PullProgress
+	for update := range progress {
+		require.NoError(t, update.Err)
+		updates = append(updates, update)
+	}
+	require.Len(t, updates, 3)
+	assert.Equal(t, "sha256:abc", updates[1].Digest)
+	assert.Equal(t, 0.25, updates[1].Fraction())
+	assert.False(t, updates[1].Done())
+	assert.True(t, updates[2].Done())
+}
+
+func TestProviderEnsureModel(t *testing.T) {
+	t.Parallel()
+	var pulls atomic.Int32
+	provider, _ := newOllamaTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			require.NoError(t, json.NewEncoder(w).Encode(modelsResponse{
+				Models: []modelInfo{{Name: "llama3:latest", Model: "llama3:latest"}},
+			}))
+		case "/api/pull":
+			pulls.Add(1)
+			var req pullRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			if req.Model == "missing" {
+				_, _ = w.Write([]byte(`{"status":"pulling manifest"}` + "\n" + `{"error":"pull model manifest: file does not exist"}` + "\n"))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"pulling manifest"}` + "\n" + `{"status":"success"}` + "\n"))
+		}
+	})
+
+	require.NoError(t, provider.EnsureModel(context.Background(), "llama3", nil))
+	assert.Zero(t, pulls.Load(), "installed models are not pulled")
+
+	var statuses []string
+	require.NoError(t, provider.EnsureModel(context.Background(), "qwen3", func(p PullProgress) {
+		statuses = append(statuses, p.Status)
+	}))
+	assert.Equal(t, []string{"pulling manifest", "success"}, statuses)
+
+	err := provider.EnsureModel(context.Background(), "missing", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "file does not exist")
+}
+
+func TestProviderNativeOptions(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOllamaTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "1h0m0s", req["keep_alive"])
+		assert.Equal(t, map[string]any{
+			"temperature":  0.5,
+			"num_ctx":      float64(8192),
+			"num_gpu":      float64(0),
+			"mirostat":     float64(2),
+			"mirostat_tau": 4.5,
+			"tfs_z":        0.9,
+		}, req["options"])
+
+		require.NoError(t, json.NewEncoder(w).Encode(chatResponse{Message: message{Role: roleAssistant, Content: "ok"}, Done: true}))
+	})
+
+	keepAlive, cpuOnly, temperature := time.Hour, 0, float32(0.5)
+	_, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{
+			Model:       "llama3",
+			Temperature: &temperature,
+			ProviderOptions: types.OllamaOptions{
+				KeepAlive:   &keepAlive,
+				NumCtx:      8192,
+				NumGPU:      &cpuOnly,
+				Mirostat:    2,
+				MirostatTau: 4.5,
+				Options:     map[string]any{"tfs_z": 0.9, "temperature": 1.0},
+			}.ProviderOptions(),
+		},
+		Messages: []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+}
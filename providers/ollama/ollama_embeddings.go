@@ -9,15 +9,26 @@ import (
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
+// newEmbeddingsRequest builds the payload for one input, carrying the
+// request's "options" and "keep_alive" provider options
+func newEmbeddingsRequest(request types.EmbeddingsRequest, input string) *embeddingsRequest {
+	payload := &embeddingsRequest{
+		Model:     request.Model,
+		Prompt:    input,
+		KeepAlive: request.ProviderOptions["keep_alive"],
+	}
+	if runtime, ok := request.ProviderOptions["options"].(map[string]any); ok {
+		payload.Options = runtime
+	}
+	return payload
+}
+
 // processEmbeddingsSequentially handles small batches sequentially
 func (p *Provider) processEmbeddingsSequentially(ctx context.Context, request types.EmbeddingsRequest) (*types.EmbeddingsResponse, error) {
 	embeddings := make([]types.Embedding, 0, len(request.Input))
 
 	for i, input := range request.Input {
-		payload := &embeddingsRequest{
-			Model:  request.Model,
-			Prompt: input,
-		}
+		payload := newEmbeddingsRequest(request, input)
 
 		url := p.GetBaseURL() + "/api/embeddings"
 
@@ -61,10 +72,7 @@ func (p *Provider) processEmbeddingsConcurrently(ctx context.Context, request ty
 			semaphore <- struct{}{}        // Acquire semaphore
 			defer func() { <-semaphore }() // Release semaphore
 
-			payload := &embeddingsRequest{
-				Model:  request.Model,
-				Prompt: txt,
-			}
+			payload := newEmbeddingsRequest(request, txt)
 
 			url := p.GetBaseURL() + "/api/embeddings"
 
@@ -30,3 +30,22 @@ func TestTransformMessagesIncludesUserMediaImages(t *testing.T) {
 	assert.Equal(t, b64, out[0].Images[0])
 	assert.Equal(t, "describe this", out[0].Content)
 }
+
+func TestAudioInputRejectedByOllama(t *testing.T) {
+	t.Parallel()
+
+	p, err := New(types.ProviderConfig{BaseURL: "http://127.0.0.1:1"})
+	require.NoError(t, err)
+	request := types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "llama3"},
+		Messages: []types.Message{&types.UserMessage{
+			Content: "transcribe this",
+			Media:   []types.Media{types.NewAudio([]byte("RIFF"), "wav")},
+		}},
+	}
+
+	_, err = p.Text(t.Context(), request)
+	require.ErrorContains(t, err, "audio input is not supported by Ollama")
+	_, err = p.Stream(t.Context(), request)
+	require.ErrorContains(t, err, "audio input is not supported by Ollama")
+}
@@ -1,7 +1,9 @@
 package ollama
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -21,21 +23,121 @@ func (p *Provider) ListModels(ctx context.Context) (*modelsResponse, error) {
 	return &response, nil
 }
 
-// PullModel pulls a model from Ollama registry
-func (p *Provider) PullModel(ctx context.Context, model string) error {
-	payload := map[string]any{
-		"name": model,
+// PullProgress reports one step of a model pull. Status is Ollama's
+// description of the step ("pulling manifest", "downloading <digest>",
+// "success", ...); Total and Completed are bytes for download steps. The
+// last progress of a failed pull carries Err.
+type PullProgress struct {
+	Status    string
+	Digest    string
+	Total     int64
+	Completed int64
+	Err       error
+}
+
+// Fraction returns the completed fraction of a download step, or 0 when the
+// step has no size.
+func (p PullProgress) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
 	}
+	return float64(p.Completed) / float64(p.Total)
+}
 
+// Done reports whether the pull finished successfully.
+func (p PullProgress) Done() bool {
+	return p.Err == nil && p.Status == "success"
+}
+
+// PullModel pulls a model from the Ollama registry and streams its progress.
+// The channel closes when the pull ends; a failed pull ends with a progress
+// whose Err is set. Pulling a model that is already present is cheap.
+func (p *Provider) PullModel(ctx context.Context, model string) (<-chan PullProgress, error) {
 	url := p.GetBaseURL() + "/api/pull"
 
-	// This is a streaming endpoint but we'll treat it as regular request for simplicity
-	var response map[string]any // Ollama returns various status messages
-	err := p.DoRequest(ctx, http.MethodPost, url, payload, &response)
+	body, err := p.StreamRequest(ctx, http.MethodPost, url, &pullRequest{Model: model, Stream: true})
+	if err != nil {
+		return nil, p.WrapError(types.ErrorCodeProvider, fmt.Sprintf("failed to pull model %s", model), err)
+	}
+
+	progress := make(chan PullProgress, 16)
+	go func() {
+		defer func() {
+			close(progress)
+			_ = body.Close()
+		}()
+		send := func(update PullProgress) bool {
+			select {
+			case progress <- update:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			var status pullStatus
+			if err := json.Unmarshal(line, &status); err != nil {
+				send(PullProgress{Err: p.RequestError("failed to parse pull progress", err)})
+				return
+			}
+			if status.Error != "" {
+				send(PullProgress{Err: p.ProviderErrorf("failed to pull model %s: %s", model, status.Error)})
+				return
+			}
+			if !send(PullProgress{Status: status.Status, Digest: status.Digest, Total: status.Total, Completed: status.Completed}) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			send(PullProgress{Err: p.WrapError(types.ErrorCodeNetwork, fmt.Sprintf("pull of model %s interrupted", model), err)})
+		}
+	}()
+	return progress, nil
+}
+
+// EnsureModel pulls model unless it is already installed, so applications
+// can make sure a model exists before generating. onProgress, if non-nil,
+// receives each pull step.
+func (p *Provider) EnsureModel(ctx context.Context, model string, onProgress func(PullProgress)) error {
+	models, err := p.ListModels(ctx)
 	if err != nil {
-		return p.WrapError(types.ErrorCodeProvider, fmt.Sprintf("failed to pull model %s", model), err)
+		return err
+	}
+	for _, installed := range models.Models {
+		if installed.Name == model || installed.Model == model || installed.Name == model+":latest" {
+			return nil
+		}
 	}
 
+	progress, err := p.PullModel(ctx, model)
+	if err != nil {
+		return err
+	}
+	done := false
+	for update := range progress {
+		if onProgress != nil {
+			onProgress(update)
+		}
+		if update.Err != nil {
+			return update.Err
+		}
+		done = done || update.Done()
+	}
+	if !done {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return p.ProviderErrorf("pull of model %s ended without success", model)
+	}
 	return nil
 }
 
@@ -28,6 +28,9 @@ func (p *Provider) buildChatPayload(request *types.TextRequest) *chatRequest {
 		Messages: p.transformMessages(prepared, request.SystemPrompt),
 		Options:  p.buildOptions(request),
 	}
+	if keepAlive, ok := request.ProviderOptions["keep_alive"]; ok {
+		payload.KeepAlive = keepAlive
+	}
 
 	// Set JSON format for structured output
 	if request.ResponseFormat != nil {
@@ -75,16 +78,36 @@ func (p *Provider) buildOptions(request *types.TextRequest) *options {
 		opts.FrequencyPenalty = request.FrequencyPenalty
 		hasOptions = true
 	}
+	if request.TopK != nil {
+		opts.TopK = request.TopK
+		hasOptions = true
+	}
+	if request.MinP != nil {
+		opts.MinP = request.MinP
+		hasOptions = true
+	}
+	if request.RepetitionPenalty != nil {
+		opts.RepeatPenalty = request.RepetitionPenalty
+		hasOptions = true
+	}
 
 	// Provider-specific options
 	if request.ProviderOptions != nil {
-		if topK, ok := request.ProviderOptions["top_k"].(int); ok {
-			opts.TopK = &topK
+		if extra, ok := request.ProviderOptions["options"].(map[string]any); ok && len(extra) > 0 {
+			opts.Extra = extra
 			hasOptions = true
 		}
-		if repeatPenalty, ok := request.ProviderOptions["repeat_penalty"].(float32); ok {
-			opts.RepeatPenalty = &repeatPenalty
-			hasOptions = true
+		if request.TopK == nil {
+			if topK, ok := request.ProviderOptions["top_k"].(int); ok {
+				opts.TopK = &topK
+				hasOptions = true
+			}
+		}
+		if request.RepetitionPenalty == nil {
+			if repeatPenalty, ok := request.ProviderOptions["repeat_penalty"].(float32); ok {
+				opts.RepeatPenalty = &repeatPenalty
+				hasOptions = true
+			}
 		}
 		if request.PresencePenalty == nil {
 			if presencePenalty, ok := request.ProviderOptions["presence_penalty"].(float32); ok {
@@ -217,7 +240,7 @@ func (p *Provider) transformMessages(messages []types.Message, systemPrompt stri
 // mapRole maps internal role to Ollama role
 func (p *Provider) mapRole(role types.Role) string {
 	switch role {
-	case types.RoleSystem:
+	case types.RoleSystem, types.RoleDeveloper:
 		return roleSystem
 	case types.RoleUser:
 		return roleUser
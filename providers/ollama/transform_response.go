@@ -22,12 +22,13 @@ func (p *Provider) transformTextResponse(response *chatResponse) *types.TextResp
 	}
 
 	return &types.TextResponse{
-		ID:           id,
-		Model:        response.Model,
-		Text:         content,
-		FinishReason: p.mapFinishReason(response.DoneReason),
-		Usage:        p.convertUsage(response),
-		Created:      response.CreatedAt,
+		ID:              id,
+		Model:           response.Model,
+		Text:            content,
+		FinishReason:    p.mapFinishReason(response.DoneReason),
+		Usage:           p.convertUsage(response),
+		Created:         response.CreatedAt,
+		RawFinishReason: response.DoneReason,
 	}
 }
 
@@ -66,6 +67,7 @@ func (p *Provider) parseStreamChunk(data []byte) (*types.TextChunk, error) {
 	if response.Done {
 		reason := p.mapFinishReason(response.DoneReason)
 		chunk.FinishReason = &reason
+		chunk.RawFinishReason = response.DoneReason
 	}
 
 	if response.Done {
@@ -87,19 +89,10 @@ func (p *Provider) parseStreamChunk(data []byte) (*types.TextChunk, error) {
 // Helper functions
 
 // mapFinishReason maps Ollama's done_reason to finish reason.
-// Ollama returns done_reason values: "stop", "length", "load", "unload".
+// Ollama returns done_reason values: "stop", "length", "load", "unload"; a
+// model load or unload is not a normal generation stop and maps to other.
 func (p *Provider) mapFinishReason(doneReason string) types.FinishReason {
-	switch doneReason {
-	case "stop":
-		return types.FinishReasonStop
-	case "length":
-		return types.FinishReasonLength
-	case "load", "unload":
-		// Model load/unload — not a normal generation stop.
-		return types.FinishReasonOther
-	default:
-		return types.FinishReasonOther
-	}
+	return types.NormalizeFinishReason(doneReason)
 }
 
 // convertUsage converts Ollama response to usage info
@@ -1,6 +1,10 @@
 package ollama
 
-import "time"
+import (
+	"encoding/json"
+	"maps"
+	"time"
+)
 
 // Ollama-specific API request/response types based on Ollama REST API
 
@@ -11,6 +15,8 @@ type chatRequest struct {
 	Stream   bool      `json:"stream,omitempty"`
 	Format   string    `json:"format,omitempty"` // "json" for structured output
 	Options  *options  `json:"options,omitempty"`
+	// KeepAlive is a duration string or number of seconds (see OllamaOptions)
+	KeepAlive any `json:"keep_alive,omitempty"`
 }
 
 // message represents an Ollama message
@@ -25,12 +31,29 @@ type options struct {
 	Temperature      *float32 `json:"temperature,omitempty"`
 	TopP             *float32 `json:"top_p,omitempty"`
 	TopK             *int     `json:"top_k,omitempty"`
+	MinP             *float32 `json:"min_p,omitempty"`
 	NumPredict       *int     `json:"num_predict,omitempty"` // equivalent to max_tokens
 	Stop             []string `json:"stop,omitempty"`
 	RepeatPenalty    *float32 `json:"repeat_penalty,omitempty"`
 	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
 	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
 	Seed             *int     `json:"seed,omitempty"`
+	// Extra holds passthrough runtime options; the typed fields win on conflict
+	Extra map[string]any `json:"-"`
+}
+
+// MarshalJSON merges Extra into the typed options
+func (o options) MarshalJSON() ([]byte, error) {
+	type plain options
+	data, err := json.Marshal(plain(o))
+	if err != nil || len(o.Extra) == 0 {
+		return data, err
+	}
+	merged := maps.Clone(o.Extra)
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	return json.Marshal(merged)
 }
 
 // chatResponse represents an Ollama chat response
@@ -65,8 +88,10 @@ type streamResponse struct {
 
 // embeddingsRequest represents an Ollama embeddings request
 type embeddingsRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"` // Single string for embeddings
+	Model     string         `json:"model"`
+	Prompt    string         `json:"prompt"` // Single string for embeddings
+	Options   map[string]any `json:"options,omitempty"`
+	KeepAlive any            `json:"keep_alive,omitempty"`
 }
 
 // embeddingsResponse represents an Ollama embeddings response
@@ -88,3 +113,18 @@ type modelInfo struct {
 	Digest     string            `json:"digest"`
 	Details    map[string]string `json:"details"`
 }
+
+// pullRequest represents an Ollama model pull request
+type pullRequest struct {
+	Model  string `json:"model"`
+	Stream bool   `json:"stream"`
+}
+
+// pullStatus is one line of the streamed pull response
+type pullStatus struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
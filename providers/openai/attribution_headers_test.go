@@ -0,0 +1,57 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestOrganizationAndProjectHeaders(t *testing.T) {
+	t.Parallel()
+
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","model":"gpt-4o-mini","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	callerHeaders := map[string]string{
+		"X-Trace":                      "abc",
+		types.HeaderOpenAIOrganization: "org-stale",
+	}
+	provider := New(types.ProviderConfig{
+		APIKey:       "sk-test",
+		BaseURL:      server.URL,
+		Headers:      callerHeaders,
+		Organization: "org-billing",
+		Project:      "proj-search",
+	})
+
+	_, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "org-billing", got.Get(types.HeaderOpenAIOrganization))
+	assert.Equal(t, "proj-search", got.Get(types.HeaderOpenAIProject))
+	assert.Equal(t, "abc", got.Get("X-Trace"))
+	assert.Equal(t, "org-stale", callerHeaders[types.HeaderOpenAIOrganization], "caller headers must not be mutated")
+}
+
+func TestAttributionHeadersUnsetLeavesHeadersAlone(t *testing.T) {
+	t.Parallel()
+
+	headers := map[string]string{"X-Trace": "abc"}
+	out := attributionHeaders(types.ProviderConfig{Headers: headers})
+	assert.Equal(t, headers, out)
+	assert.NotContains(t, out, types.HeaderOpenAIOrganization)
+}
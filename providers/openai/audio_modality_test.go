@@ -0,0 +1,72 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestChatAudioInputAndOutput(t *testing.T) {
+	t.Parallel()
+	var payload map[string]any
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		_, _ = w.Write([]byte(`{"id":"c1","model":"gpt-4o-audio-preview","choices":[{"message":{"role":"assistant","content":null,"audio":{"id":"audio_1","data":"UklGRg==","transcript":"It is sunny.","expires_at":1792000000}},"finish_reason":"stop"}]}`))
+	})
+
+	question := types.NewUserMessage("")
+	question.Media = []types.Media{types.NewAudio([]byte("RIFF"), "wav")}
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-audio-preview"},
+		Messages: []types.Message{
+			types.NewUserMessage("Hi"),
+			&types.AssistantMessage{AudioID: "audio_0"},
+			question,
+		},
+		Audio: &types.AudioOutput{Voice: "alloy", Format: "wav"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &types.ResponseAudio{
+		ID:         "audio_1",
+		Data:       []byte("RIFF"),
+		Format:     "wav",
+		Transcript: "It is sunny.",
+		ExpiresAt:  time.Unix(1792000000, 0),
+	}, resp.Audio)
+	assert.False(t, resp.IsEmpty())
+
+	assert.Equal(t, []any{"text", "audio"}, payload["modalities"])
+	assert.Equal(t, map[string]any{"voice": "alloy", "format": "wav"}, payload["audio"])
+	messages := payload["messages"].([]any)
+	assert.Equal(t, map[string]any{"id": "audio_0"}, messages[1].(map[string]any)["audio"])
+	assert.Equal(t, []any{map[string]any{
+		"type":        "input_audio",
+		"input_audio": map[string]any{"data": "UklGRg==", "format": "wav"},
+	}}, messages[2].(map[string]any)["content"])
+}
+
+func TestAudioOutputRejectedWhereUnsupported(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent")
+	})
+	request := types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-audio-preview"},
+		Messages:    []types.Message{types.NewUserMessage("Hi")},
+		Audio:       &types.AudioOutput{Voice: "alloy", Format: "pcm16"},
+	}
+
+	_, err := provider.Stream(context.Background(), request)
+	assert.ErrorContains(t, err, "cannot be streamed")
+
+	provider.Config.UseResponsesAPI = true
+	_, err = provider.Text(context.Background(), request)
+	assert.ErrorContains(t, err, "Responses API")
+}
@@ -0,0 +1,82 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestChatMultipleChoices(t *testing.T) {
+	t.Parallel()
+	config := types.ProviderConfig{APIKey: "test-key", RequestPolicy: types.ProviderRequestPolicy{ThinkTags: true}}
+	provider, _ := newOpenAITestProviderWithConfig(t, config, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, float64(3), req["n"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chat-n","model":"gpt-4o-mini",
+			"choices":[
+				{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"408"}},
+				{"index":1,"finish_reason":"stop","message":{"role":"assistant","content":"<think>17*24</think>408"}},
+				{"index":2,"finish_reason":"length","message":{"role":"assistant","content":"398"}}
+			],
+			"usage":{"prompt_tokens":12,"completion_tokens":9,"total_tokens":21}
+		}`))
+	})
+
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages:    []types.Message{types.NewUserMessage("17 * 24?")},
+		N:           3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "408", resp.Text)
+	assert.Equal(t, []string{"408", "408", "398"}, resp.Texts())
+	require.Len(t, resp.Choices, 3)
+	assert.Equal(t, 2, resp.Choices[2].Index)
+	assert.Equal(t, types.FinishReasonLength, resp.Choices[2].FinishReason)
+	require.NotNil(t, resp.Choices[1].Thinking)
+	assert.Equal(t, "17*24", resp.Choices[1].Thinking.Content)
+	assert.Equal(t, 21, resp.Usage.TotalTokens)
+}
+
+func TestChatSingleChoiceLeavesChoicesEmpty(t *testing.T) {
+	t.Parallel()
+	payload := New(types.ProviderConfig{APIKey: "test-key"}).buildChatPayload(&types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+		N:           1,
+	})
+	assert.NotContains(t, payload, "n")
+
+	resp := New(types.ProviderConfig{APIKey: "test-key"}).transformTextResponse(&chatCompletionResponse{
+		Choices: []chatChoice{{Message: message{Content: "hi"}}},
+	})
+	assert.Empty(t, resp.Choices)
+	assert.Equal(t, []string{"hi"}, resp.Texts())
+}
+
+func TestMultipleChoicesRejectedWhereUnsupported(t *testing.T) {
+	t.Parallel()
+	request := types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+		N:           2,
+	}
+
+	_, err := New(types.ProviderConfig{APIKey: "test-key"}).Stream(context.Background(), request)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be streamed")
+
+	_, err = New(types.ProviderConfig{APIKey: "test-key", UseResponsesAPI: true}).Text(context.Background(), request)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Responses API")
+}
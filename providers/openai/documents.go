@@ -0,0 +1,67 @@
+package openai
+
+import (
+	"encoding/base64"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+const responsesContentInputFile = "input_file"
+
+// OpenAI accepts PDFs as file inputs, up to 32 MB per request. Chat
+// Completions only takes inline data; the Responses API also fetches URLs.
+var (
+	chatDocumentLimits = types.DocumentLimits{
+		MaxBytes:  32 << 20,
+		MimeTypes: []string{types.MimeTypePDF},
+	}
+	responsesDocumentLimits = types.DocumentLimits{
+		MaxBytes:  32 << 20,
+		MimeTypes: []string{types.MimeTypePDF},
+		AllowURL:  true,
+	}
+)
+
+func (p *Provider) checkDocuments(messages []types.Message, limits types.DocumentLimits) error {
+	if err := limits.CheckDocuments(messages); err != nil {
+		return p.ValidationError("unsupported document input", err.Error())
+	}
+	return nil
+}
+
+func documentDataURL(doc *types.DocumentMedia) string {
+	return "data:" + doc.MimeType + ";base64," + base64.StdEncoding.EncodeToString(doc.Data)
+}
+
+func documentFilename(doc *types.DocumentMedia) string {
+	if doc.Filename != "" {
+		return doc.Filename
+	}
+	return "document.pdf"
+}
+
+// chatDocumentPart renders a document as a Chat Completions file part.
+func chatDocumentPart(doc *types.DocumentMedia) map[string]any {
+	return map[string]any{
+		"type": "file",
+		"file": map[string]any{
+			"filename":  documentFilename(doc),
+			"file_data": documentDataURL(doc),
+		},
+	}
+}
+
+// responsesDocumentPart renders a document as a Responses input_file part.
+func responsesDocumentPart(doc *types.DocumentMedia) map[string]any {
+	if len(doc.Data) == 0 {
+		return map[string]any{
+			"type":     responsesContentInputFile,
+			"file_url": doc.URL,
+		}
+	}
+	return map[string]any{
+		"type":      responsesContentInputFile,
+		"filename":  documentFilename(doc),
+		"file_data": documentDataURL(doc),
+	}
+}
@@ -0,0 +1,33 @@
+package openai
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+	"github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+// TestGoldenPayloads pins the Chat Completions and Responses wire formats.
+// Regenerate with WORMHOLE_UPDATE_GOLDEN=1 and review the fixture diff.
+func TestGoldenPayloads(t *testing.T) {
+	t.Parallel()
+
+	for name, request := range wormholetest.GoldenTextRequests("gpt-4o-mini") {
+		for _, responses := range []bool{false, true} {
+			fixture := name + ".json"
+			if responses {
+				fixture = "responses_" + fixture
+			}
+			t.Run(fixture, func(t *testing.T) {
+				t.Parallel()
+				wormholetest.AssertGoldenPayload(t, filepath.Join("testdata", "golden", fixture), func(baseURL string) {
+					config := types.NewProviderConfig("test-key").WithBaseURL(baseURL).WithNoRetries()
+					config.UseResponsesAPI = responses
+					_, _ = New(config).Text(context.Background(), request)
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// maxImageJSONBytes bounds an edit or variation response; base64 images for
+// a large N run to tens of megabytes.
+const maxImageJSONBytes = 128 << 20
+
+var _ types.ImageEditor = (*Provider)(nil)
+
+// EditImage edits images through /images/edits. Several images are sent as
+// image[] parts, which gpt-image-1 accepts; a single image goes as image.
+func (p *Provider) EditImage(ctx context.Context, request types.ImageEditRequest) (*types.ImagesResponse, error) {
+	if len(request.Images) == 0 {
+		return nil, p.ValidationError("image edit requires an image")
+	}
+	if request.Prompt == "" {
+		return nil, p.ValidationError("image edit requires a prompt")
+	}
+
+	field := "image"
+	if len(request.Images) > 1 {
+		field = "image[]"
+	}
+	files := make([]imageFormFile, 0, len(request.Images)+1)
+	for _, image := range request.Images {
+		files = append(files, imageFormFile{field: field, data: image})
+	}
+	if len(request.Mask) > 0 {
+		files = append(files, imageFormFile{field: "mask", data: request.Mask})
+	}
+
+	fields := []formField{
+		{name: "prompt", value: request.Prompt},
+		{name: "quality", value: request.Quality},
+	}
+	fields = append(fields, imageFormFields(request.Model, request.Size, request.N, request.ResponseFormat)...)
+	options, err := p.formOptionFields(request.Model, request.ProviderOptions)
+	if err != nil {
+		return nil, err
+	}
+	return p.postImageForm(ctx, "/images/edits", files, append(fields, options...))
+}
+
+// ImageVariations makes variations of an image through /images/variations.
+func (p *Provider) ImageVariations(ctx context.Context, request types.ImageVariationRequest) (*types.ImagesResponse, error) {
+	if len(request.Image) == 0 {
+		return nil, p.ValidationError("image variations require an image")
+	}
+	files := []imageFormFile{{field: "image", data: request.Image}}
+	fields := imageFormFields(request.Model, request.Size, request.N, request.ResponseFormat)
+	options, err := p.formOptionFields(request.Model, request.ProviderOptions)
+	if err != nil {
+		return nil, err
+	}
+	return p.postImageForm(ctx, "/images/variations", files, append(fields, options...))
+}
+
+func imageFormFields(model, size string, n int, responseFormat string) []formField {
+	fields := []formField{
+		{name: "model", value: model},
+		{name: "size", value: size},
+		{name: "response_format", value: responseFormat},
+	}
+	if n > 0 {
+		fields = append(fields, formField{name: "n", value: strconv.Itoa(n)})
+	}
+	return fields
+}
+
+func (p *Provider) postImageForm(ctx context.Context, path string, files []imageFormFile, fields []formField) (*types.ImagesResponse, error) {
+	reader, contentType, err := buildImageForm(files, fields)
+	if err != nil {
+		return nil, p.RequestError("failed to build image form", err)
+	}
+	body, err := p.postMultipart(ctx, path, reader, contentType, false)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	data, err := readLimited(body, maxImageJSONBytes)
+	if err != nil {
+		return nil, types.Errorf("read response", err)
+	}
+	var response imageResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, types.Errorf("parse response", err)
+	}
+	return p.transformImageResponse(&response), nil
+}
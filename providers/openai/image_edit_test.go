@@ -0,0 +1,74 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestEditImageSendsSeveralImagesAsArray(t *testing.T) {
+	t.Parallel()
+	jpeg := []byte("\xff\xd8\xff\xe0 fake jpeg")
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/images/edits", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		files := r.MultipartForm.File["image[]"]
+		require.Len(t, files, 2)
+		assert.Equal(t, "image/jpeg", files[0].Header.Get("Content-Type"))
+		assert.Equal(t, "image-0.jpg", files[0].Filename)
+		assert.NotContains(t, r.MultipartForm.File, "mask")
+		assert.Equal(t, []string{"high"}, r.MultipartForm.Value["input_fidelity"])
+		assert.Equal(t, []string{"medium"}, r.MultipartForm.Value["quality"])
+		_, _ = io.WriteString(w, `{"created":1,"data":[{"b64_json":"aW1n"}]}`)
+	})
+
+	resp, err := provider.EditImage(context.Background(), types.ImageEditRequest{
+		Model:           "gpt-image-1",
+		Prompt:          "Combine into a gift basket",
+		Images:          [][]byte{jpeg, jpeg},
+		Quality:         "medium",
+		ProviderOptions: map[string]any{"input_fidelity": "high"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "aW1n", resp.Images[0].B64JSON)
+
+	_, err = provider.EditImage(context.Background(), types.ImageEditRequest{Model: "gpt-image-1", Images: [][]byte{jpeg}})
+	assert.ErrorContains(t, err, "requires a prompt")
+}
+
+func TestImageVariationsEncodeNonScalarOptionsAsJSON(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, []string{`{"bottom":8,"top":8}`}, r.MultipartForm.Value["padding"])
+		assert.Equal(t, []string{`["#fff","#000"]`}, r.MultipartForm.Value["palette"])
+		assert.Equal(t, []string{"0.5"}, r.MultipartForm.Value["strength"])
+		assert.Equal(t, []string{"true"}, r.MultipartForm.Value["seamless"])
+		assert.NotContains(t, r.MultipartForm.Value, "unset")
+		_, _ = io.WriteString(w, `{"created":1,"data":[{"url":"https://example.com/v.png"}]}`)
+	})
+
+	request := types.ImageVariationRequest{
+		Model: "dall-e-2",
+		Image: []byte("\x89PNG fake"),
+		ProviderOptions: map[string]any{
+			"padding":  map[string]int{"top": 8, "bottom": 8},
+			"palette":  []string{"#fff", "#000"},
+			"strength": 0.5,
+			"seamless": true,
+			"unset":    nil,
+		},
+	}
+	_, err := provider.ImageVariations(context.Background(), request)
+	require.NoError(t, err)
+
+	request.ProviderOptions = map[string]any{"callback": func() {}}
+	_, err = provider.ImageVariations(context.Background(), request)
+	assert.ErrorContains(t, err, `provider option "callback"`)
+}
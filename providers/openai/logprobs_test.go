@@ -0,0 +1,112 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestChatLogprobs(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, true, req["logprobs"])
+		assert.Equal(t, float64(2), req["top_logprobs"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chat-1","model":"gpt-4o-mini",
+			"choices":[{"index":0,"finish_reason":"length",
+				"message":{"role":"assistant","content":"yes"},
+				"logprobs":{"content":[{"token":"yes","logprob":-0.1,"bytes":[121,101,115],
+					"top_logprobs":[{"token":"yes","logprob":-0.1},{"token":"no","logprob":-2.4}]}]}}]
+		}`))
+	})
+
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages:    []types.Message{types.NewUserMessage("positive?")},
+		Logprobs:    true,
+		TopLogprobs: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Logprobs, 1)
+	token := resp.Logprobs[0]
+	assert.Equal(t, "yes", token.Token)
+	assert.Equal(t, []int{121, 101, 115}, token.Bytes)
+	assert.InDelta(t, 0.905, token.Probability(), 0.001)
+	require.Len(t, token.TopLogprobs, 2)
+	assert.Equal(t, "no", token.TopLogprobs[1].Token)
+}
+
+func TestChatLogprobsOmittedByDefault(t *testing.T) {
+	t.Parallel()
+	payload := New(types.ProviderConfig{APIKey: "test-key"}).buildChatPayload(&types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	assert.NotContains(t, payload, "logprobs")
+	assert.NotContains(t, payload, "top_logprobs")
+}
+
+func TestStreamChunkLogprobs(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{"id":"c","model":"m","choices":[{"index":0,"delta":{"content":"Hi"},
+		"logprobs":{"content":[{"token":"Hi","logprob":-0.5,"top_logprobs":[]}]}}]}`)
+
+	provider := New(types.ProviderConfig{APIKey: "test-key"})
+	chunk, err := provider.parseStreamChunk(data)
+	require.NoError(t, err)
+	require.Len(t, chunk.Logprobs, 1)
+	assert.Equal(t, "Hi", chunk.Logprobs[0].Token)
+	assert.Equal(t, -0.5, chunk.Logprobs[0].Logprob)
+
+	provider.streamingTransformer = nil
+	chunk, err = provider.parseStreamChunk(data)
+	require.NoError(t, err)
+	require.Len(t, chunk.Logprobs, 1)
+	assert.Equal(t, "Hi", chunk.Logprobs[0].Token)
+}
+
+func TestResponsesAPILogprobs(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []any{"message.output_text.logprobs"}, req["include"])
+		assert.Equal(t, float64(3), req["top_logprobs"])
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(responsesResponse{
+			ID:     "resp-1",
+			Model:  "gpt-5",
+			Status: "completed",
+			Output: []responsesOutputItem{{
+				Type: responsesItemMessage,
+				Role: "assistant",
+				Content: []responsesContentPart{{
+					Type:     responsesContentOutputText,
+					Text:     "ok",
+					Logprobs: []types.TokenLogprob{{Token: "ok", Logprob: -0.2}},
+				}},
+			}},
+		}))
+	})
+	provider.Config.UseResponsesAPI = true
+
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-5"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+		Logprobs:    true,
+		TopLogprobs: 3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []types.TokenLogprob{{Token: "ok", Logprob: -0.2}}, resp.Logprobs)
+}
@@ -45,7 +45,11 @@ func (p *Provider) Rerank(ctx context.Context, request types.RerankRequest) (*ty
 	}
 
 	if request.TopN != nil {
-		payload["top_n"] = *request.TopN
+		topNParam := p.Config.RequestPolicy.RerankTopNParam
+		if topNParam == "" {
+			topNParam = "top_n"
+		}
+		payload[topNParam] = *request.TopN
 	}
 
 	// Merge provider-specific options (allows overriding any parameter)
@@ -61,7 +65,7 @@ func (p *Provider) Rerank(ctx context.Context, request types.RerankRequest) (*ty
 		return nil, err
 	}
 
-	resp := p.transformRerankResponse(&response, request.Model)
+	resp := p.transformRerankResponse(&response, request)
 	resp.Provider = p.Name()
 	return resp, nil
 }
@@ -0,0 +1,82 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// defaultModerationModel is used when a moderation request names no model.
+const defaultModerationModel = "omni-moderation-latest"
+
+// Moderate classifies text with the /moderations endpoint.
+func (p *Provider) Moderate(ctx context.Context, request types.ModerationRequest) (*types.ModerationResponse, error) {
+	if len(request.Input) == 0 {
+		return nil, p.ValidationError("moderation requires at least one input")
+	}
+	model := request.Model
+	if model == "" {
+		model = defaultModerationModel
+	}
+	payload := map[string]any{
+		"model": model,
+		"input": request.Input,
+	}
+
+	// Merge provider-specific options (allows overriding any parameter)
+	for k, v := range p.Config.MergedProviderOptions(model, request.ProviderOptions) {
+		payload[k] = v
+	}
+
+	var response moderationResponse
+	if err := p.DoRequest(ctx, http.MethodPost, p.GetBaseURL()+"/moderations", payload, &response); err != nil {
+		return nil, err
+	}
+
+	resp := transformModerationResponse(&response, model)
+	resp.Provider = p.Name()
+	return resp, nil
+}
+
+func transformModerationResponse(response *moderationResponse, model string) *types.ModerationResponse {
+	results := make([]types.ModerationResult, len(response.Results))
+	for i, r := range response.Results {
+		result := types.ModerationResult{
+			Flagged:    r.Flagged,
+			Categories: make(map[types.ModerationCategory]bool),
+			Scores:     make(map[types.ModerationCategory]float64),
+			Raw:        r.CategoryScores,
+		}
+		for name, score := range r.CategoryScores {
+			category := moderationCategory(name)
+			result.Scores[category] = max(result.Scores[category], score)
+		}
+		for name, flagged := range r.Categories {
+			category := moderationCategory(name)
+			result.Categories[category] = result.Categories[category] || flagged
+		}
+		results[i] = result
+	}
+	if response.Model != "" {
+		model = response.Model
+	}
+	return &types.ModerationResponse{
+		ID:      response.ID,
+		Model:   model,
+		Results: results,
+		Created: time.Now(),
+	}
+}
+
+// moderationCategory folds an OpenAI category such as "self-harm/intent" into
+// its unified parent.
+func moderationCategory(name string) types.ModerationCategory {
+	if name == "sexual/minors" {
+		return types.ModerationSexualMinors
+	}
+	parent, _, _ := strings.Cut(name, "/")
+	return types.ModerationCategory(strings.ReplaceAll(parent, "-", "_"))
+}
@@ -2,11 +2,16 @@ package openai
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"mime/multipart"
+	"net/http"
 	"net/textproto"
 	"path/filepath"
+	"reflect"
+	"slices"
 )
 
 type audioFormData struct {
@@ -16,6 +21,13 @@ type audioFormData struct {
 	language    string
 	prompt      string
 	temperature *float32
+	// responseFormat, timestampGranularities, and stream are transcription
+	// options.
+	responseFormat         string
+	timestampGranularities []string
+	stream                 bool
+	// options are provider options, sent after the fields above.
+	options []formField
 }
 
 func buildAudioForm(data audioFormData) (io.Reader, string, error) {
@@ -48,6 +60,7 @@ func buildAudioForm(data audioFormData) (io.Reader, string, error) {
 		{name: "model", value: data.model},
 		{name: "language", value: data.language},
 		{name: "prompt", value: data.prompt},
+		{name: "response_format", value: data.responseFormat},
 	}
 	for _, field := range fields {
 		if field.value == "" {
@@ -63,6 +76,22 @@ func buildAudioForm(data audioFormData) (io.Reader, string, error) {
 		}
 	}
 
+	for _, granularity := range data.timestampGranularities {
+		if err := writer.WriteField("timestamp_granularities[]", granularity); err != nil {
+			return nil, "", fmt.Errorf("failed to add timestamp_granularities field: %w", err)
+		}
+	}
+	if data.stream {
+		if err := writer.WriteField("stream", "true"); err != nil {
+			return nil, "", fmt.Errorf("failed to add stream field: %w", err)
+		}
+	}
+	for _, field := range data.options {
+		if err := writer.WriteField(field.name, field.value); err != nil {
+			return nil, "", fmt.Errorf("failed to add %s field: %w", field.name, err)
+		}
+	}
+
 	if err := writer.Close(); err != nil {
 		return nil, "", fmt.Errorf("failed to close multipart writer: %w", err)
 	}
@@ -87,3 +116,96 @@ func audioContentType(filename string) string {
 		return "application/octet-stream"
 	}
 }
+
+// formOptionFields sends provider options as form fields, in key order.
+// Multipart values are strings: scalars are sent as text and anything else,
+// such as a map or slice, as JSON. Nil options are left out.
+func (p *Provider) formOptionFields(model string, options map[string]any) ([]formField, error) {
+	merged := p.Config.MergedProviderOptions(model, options)
+	fields := make([]formField, 0, len(merged))
+	for _, k := range slices.Sorted(maps.Keys(merged)) {
+		v := merged[k]
+		if v == nil {
+			continue
+		}
+		value, err := formValue(v)
+		if err != nil {
+			return nil, p.ValidationErrorf("provider option %q cannot be sent as a form field: %v", k, err)
+		}
+		fields = append(fields, formField{name: k, value: value})
+	}
+	return fields, nil
+}
+
+// formValue formats v as a multipart form value.
+func formValue(v any) (string, error) {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// formField is a multipart text field.
+type formField struct {
+	name  string
+	value string
+}
+
+// imageFormFile is an image, or a mask, sent as a multipart file part.
+type imageFormFile struct {
+	field string
+	data  []byte
+}
+
+// buildImageForm encodes images and text fields for the image edit and
+// variation endpoints. Each part's content type is sniffed from its bytes.
+func buildImageForm(files []imageFormFile, fields []formField) (io.Reader, string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i, file := range files {
+		contentType := http.DetectContentType(file.data)
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="image-%d%s"`, file.field, i, imageExtension(contentType)))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create %s part: %w", file.field, err)
+		}
+		if _, err := part.Write(file.data); err != nil {
+			return nil, "", fmt.Errorf("failed to write %s data: %w", file.field, err)
+		}
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		if err := writer.WriteField(field.name, field.value); err != nil {
+			return nil, "", fmt.Errorf("failed to add %s field: %w", field.name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+	return bytes.NewReader(body.Bytes()), writer.FormDataContentType(), nil
+}
+
+func imageExtension(contentType string) string {
+	switch contentType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ""
+	}
+}
@@ -3,6 +3,8 @@ package openai
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/garyblankenship/wormhole/v2/providers"
 	providerstream "github.com/garyblankenship/wormhole/v2/providers/internal/stream"
@@ -22,7 +24,10 @@ type Provider struct {
 	streamingTransformer *transform.StreamingTransformer
 }
 
-var _ types.Provider = (*Provider)(nil)
+var (
+	_ types.Provider          = (*Provider)(nil)
+	_ types.DeferredCompleter = (*Provider)(nil)
+)
 
 // New creates a new OpenAI provider
 func New(config types.ProviderConfig) *Provider {
@@ -34,6 +39,7 @@ func NewWithName(name string, config types.ProviderConfig) *Provider {
 	if config.BaseURL == "" {
 		config.BaseURL = defaultBaseURL
 	}
+	config.Headers = attributionHeaders(config)
 
 	return &Provider{
 		BaseProvider:         providers.NewBaseProvider(name, config),
@@ -43,6 +49,25 @@ func NewWithName(name string, config types.ProviderConfig) *Provider {
 	}
 }
 
+// attributionHeaders returns config.Headers extended with the organization and
+// project headers. The caller's map is copied, never mutated.
+func attributionHeaders(config types.ProviderConfig) map[string]string {
+	if config.Organization == "" && config.Project == "" {
+		return config.Headers
+	}
+	headers := make(map[string]string, len(config.Headers)+2)
+	for k, v := range config.Headers {
+		headers[k] = v
+	}
+	if config.Organization != "" {
+		headers[types.HeaderOpenAIOrganization] = config.Organization
+	}
+	if config.Project != "" {
+		headers[types.HeaderOpenAIProject] = config.Project
+	}
+	return headers
+}
+
 // chatCompletionsURL returns the chat-completions endpoint, honoring a
 // configured ChatPath override (empty = the OpenAI default).
 func (p *Provider) chatCompletionsURL() string {
@@ -84,6 +109,7 @@ func (p *Provider) SupportedCapabilities() []types.ModelCapability {
 		types.CapabilityImages,
 		types.CapabilityStream,
 		types.CapabilityFunctions,
+		types.CapabilityWebSearch,
 	}
 }
 
@@ -92,8 +118,20 @@ func (p *Provider) Text(ctx context.Context, request types.TextRequest) (*types.
 	if _, _, err := providers.PrepareMessages(request.Messages); err != nil {
 		return nil, err
 	}
+	request, schemaWarnings := p.downgradeSchemas(request)
 	if p.Config.UseResponsesAPI {
-		return p.responsesText(ctx, request)
+		resp, err := p.responsesText(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		resp.Metadata = providers.AddSchemaWarnings(resp.Metadata, schemaWarnings)
+		return resp, nil
+	}
+	if err := p.checkDocuments(request.Messages, chatDocumentLimits); err != nil {
+		return nil, err
+	}
+	if err := p.validateChatSampling(request); err != nil {
+		return nil, err
 	}
 
 	payload := p.buildChatPayload(&request)
@@ -105,15 +143,52 @@ func (p *Provider) Text(ctx context.Context, request types.TextRequest) (*types.
 	if err != nil {
 		return nil, err
 	}
+	if response.RequestID != "" && len(response.Choices) == 0 {
+		return &types.TextResponse{
+			ID:       response.RequestID,
+			Model:    request.Model,
+			Provider: p.Name(),
+			Created:  time.Now(),
+			Metadata: map[string]any{"deferred": true},
+		}, nil
+	}
 
 	textResponse := p.transformTextResponse(&response)
 	textResponse.Provider = p.Name()
+	if len(response.Choices) > 0 && response.Choices[0].Message.Audio != nil {
+		audio, err := p.responseAudio(response.Choices[0].Message.Audio, request.Audio)
+		if err != nil {
+			return nil, err
+		}
+		textResponse.Audio = audio
+	}
 
 	// Validate response has content to prevent silent failures
-	if textResponse.Text == "" && len(textResponse.ToolCalls) == 0 {
-		return nil, p.ProviderError("received empty response from OpenAI API", "no content or tool calls returned")
+	if textResponse.IsEmpty() {
+		return nil, types.ErrEmptyResponse.WithProvider(p.Name()).WithModel(request.Model).WithDetails("no content or tool calls returned")
 	}
+	textResponse.Metadata = providers.AddSchemaWarnings(textResponse.Metadata, schemaWarnings)
+
+	return textResponse, nil
+}
 
+// DeferredCompletion fetches the result of a completion queued with
+// "deferred": true, on APIs that support it such as xAI. It returns
+// types.ErrDeferredPending while the completion is still running.
+func (p *Provider) DeferredCompletion(ctx context.Context, requestID string) (*types.TextResponse, error) {
+	if requestID == "" {
+		return nil, p.ValidationError("deferred completion requires a request ID")
+	}
+	var response chatCompletionResponse
+	endpoint := p.GetBaseURL() + "/chat/deferred-completion/" + url.PathEscape(requestID)
+	if err := p.DoRequest(ctx, http.MethodGet, endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Choices) == 0 {
+		return nil, types.ErrDeferredPending
+	}
+	textResponse := p.transformTextResponse(&response)
+	textResponse.Provider = p.Name()
 	return textResponse, nil
 }
 
@@ -122,9 +197,22 @@ func (p *Provider) Stream(ctx context.Context, request types.TextRequest) (<-cha
 	if _, _, err := providers.PrepareMessages(request.Messages); err != nil {
 		return nil, err
 	}
+	request, _ = p.downgradeSchemas(request)
 	if p.Config.UseResponsesAPI {
 		return p.responsesStream(ctx, request)
 	}
+	if err := p.checkDocuments(request.Messages, chatDocumentLimits); err != nil {
+		return nil, err
+	}
+	if err := p.validateChatSampling(request); err != nil {
+		return nil, err
+	}
+	if request.Audio != nil {
+		return nil, p.ValidationError("audio output cannot be streamed; use Text")
+	}
+	if request.N > 1 {
+		return nil, p.ValidationError("n above 1 cannot be streamed; use Text")
+	}
 
 	payload := p.buildChatPayload(&request)
 	payload["stream"] = true
@@ -139,30 +227,11 @@ func (p *Provider) Stream(ctx context.Context, request types.TextRequest) (<-cha
 		return nil, err
 	}
 
-	return p.stampProvider(ctx, p.accumulatingStream(ctx, providerstream.ProcessSSE(ctx, body, p.parseStreamChunk, 100))), nil
+	return p.stampProvider(ctx, p.accumulatingStream(ctx, providerstream.FinalUsage(ctx, providerstream.ProcessSSE(ctx, body, p.parseStreamChunk, 100)))), nil
 }
 
-// stampProvider sets Provider on the terminal chunk. Sole closer of out;
-// exits when the upstream channel closes.
+// stampProvider ends the stream pipeline with providerstream.Finalize, which
+// stamps the terminal chunk, types errors, and closes promptly on cancellation.
 func (p *Provider) stampProvider(ctx context.Context, in <-chan types.TextChunk) <-chan types.TextChunk {
-	out := make(chan types.TextChunk)
-	go func() {
-		defer close(out)
-		for chunk := range in {
-			if chunk.IsDone() {
-				chunk.Provider = p.Name()
-			}
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
-			select {
-			case out <- chunk:
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-	return out
+	return providerstream.Finalize(ctx, p.Name(), in)
 }
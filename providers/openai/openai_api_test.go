@@ -46,11 +46,7 @@ func TestProviderTextAndEmptyResponse(t *testing.T) {
 				ID:      "chatcmpl-1",
 				Created: 100,
 				Model:   "gpt-4o-mini",
-				Choices: []struct {
-					Index        int     `json:"index"`
-					Message      message `json:"message"`
-					FinishReason string  `json:"finish_reason"`
-				}{{
+				Choices: []chatChoice{{
 					Message:      message{Role: "assistant", Content: "hello"},
 					FinishReason: "stop",
 				}},
@@ -75,11 +71,7 @@ func TestProviderTextAndEmptyResponse(t *testing.T) {
 				ID:      "chatcmpl-empty",
 				Created: 100,
 				Model:   "gpt-4o-mini",
-				Choices: []struct {
-					Index        int     `json:"index"`
-					Message      message `json:"message"`
-					FinishReason string  `json:"finish_reason"`
-				}{{Message: message{Role: "assistant"}}},
+				Choices: []chatChoice{{Message: message{Role: "assistant"}}},
 			}))
 		})
 
@@ -134,11 +126,7 @@ func TestProviderStructuredJSONAndTools(t *testing.T) {
 				ID:      "chatcmpl-json",
 				Created: 100,
 				Model:   "gpt-4o-mini",
-				Choices: []struct {
-					Index        int     `json:"index"`
-					Message      message `json:"message"`
-					FinishReason string  `json:"finish_reason"`
-				}{{Message: message{Role: "assistant", Content: `{"name":"Ada"}`}, FinishReason: "stop"}},
+				Choices: []chatChoice{{Message: message{Role: "assistant", Content: `{"name":"Ada"}`}, FinishReason: "stop"}},
 			}))
 		})
 
@@ -164,11 +152,7 @@ func TestProviderStructuredJSONAndTools(t *testing.T) {
 				ID:      "chatcmpl-tool",
 				Created: 100,
 				Model:   "gpt-4o-mini",
-				Choices: []struct {
-					Index        int     `json:"index"`
-					Message      message `json:"message"`
-					FinishReason string  `json:"finish_reason"`
-				}{{
+				Choices: []chatChoice{{
 					Message: message{Role: "assistant", ToolCalls: []toolCall{{
 						ID:   "call-1",
 						Type: "function",
@@ -200,6 +184,7 @@ func TestStructuredStrictEmitsJSONSchema(t *testing.T) {
 		"properties": map[string]any{
 			"name": map[string]any{"type": "string"},
 		},
+		"required": []any{"name"},
 	}
 
 	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
@@ -216,18 +201,16 @@ func TestStructuredStrictEmitsJSONSchema(t *testing.T) {
 		assert.Equal(t, true, jsonSchema["strict"])
 		schemaData, ok := jsonSchema["schema"].(map[string]any)
 		require.True(t, ok)
-		assert.Equal(t, schema, schemaData)
+		assert.Equal(t, schema["properties"], schemaData["properties"])
+		assert.Equal(t, schema["required"], schemaData["required"])
+		assert.Equal(t, false, schemaData["additionalProperties"], "strict mode closes every object")
 
 		w.Header().Set("Content-Type", "application/json")
 		require.NoError(t, json.NewEncoder(w).Encode(chatCompletionResponse{
 			ID:      "chatcmpl-strict-json-schema",
 			Created: 100,
 			Model:   "gpt-4o-mini",
-			Choices: []struct {
-				Index        int     `json:"index"`
-				Message      message `json:"message"`
-				FinishReason string  `json:"finish_reason"`
-			}{{Message: message{Role: "assistant", Content: `{"name":"Ada"}`}, FinishReason: "stop"}},
+			Choices: []chatChoice{{Message: message{Role: "assistant", Content: `{"name":"Ada"}`}, FinishReason: "stop"}},
 		}))
 	})
 
@@ -242,6 +225,32 @@ func TestStructuredStrictEmitsJSONSchema(t *testing.T) {
 	assert.Equal(t, map[string]any{"name": "Ada"}, resp.Data)
 }
 
+func TestStructuredStrictReturnsRefusalError(t *testing.T) {
+	t.Parallel()
+
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(chatCompletionResponse{
+			ID:      "chatcmpl-refusal",
+			Created: 100,
+			Model:   "gpt-4o-mini",
+			Choices: []chatChoice{{Message: message{Role: "assistant", Refusal: "I can't help with that."}, FinishReason: "stop"}},
+		}))
+	})
+
+	_, err := provider.Structured(context.Background(), types.StructuredRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages:    []types.Message{types.NewUserMessage("strict")},
+		Mode:        types.StructuredModeStrict,
+		Schema:      map[string]any{"type": "object"},
+	})
+	require.Error(t, err)
+	assert.True(t, types.IsRefusalError(err))
+	wormholeErr, ok := types.AsWormholeError(err)
+	require.True(t, ok)
+	assert.Equal(t, "I can't help with that.", wormholeErr.Details)
+}
+
 func TestProviderEmbeddingsImagesAndAudio(t *testing.T) {
 	t.Parallel()
 	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
@@ -494,3 +503,62 @@ func TestProviderRerank(t *testing.T) {
 	require.NotNil(t, resp.Usage)
 	assert.Equal(t, 150, resp.Usage.TotalTokens)
 }
+
+func TestProviderRerankListEnvelopeAndTopNParam(t *testing.T) {
+	t.Parallel()
+	config := types.ProviderConfig{APIKey: "test-key"}
+	config.RequestPolicy.RerankTopNParam = "top_k"
+	provider, _ := newOpenAITestProviderWithConfig(t, config, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, float64(1), req["top_k"])
+		assert.NotContains(t, req, "top_n")
+		_, err := w.Write([]byte(`{"object":"list","data":[{"index":1,"relevance_score":0.91}],"model":"rerank-2","usage":{"total_tokens":12}}`))
+		require.NoError(t, err)
+	})
+
+	topN := 1
+	resp, err := provider.Rerank(context.Background(), types.RerankRequest{
+		Model:     "rerank-2",
+		Query:     "capital of France",
+		Documents: []string{"Berlin is in Germany.", "Paris is the capital of France."},
+		TopN:      &topN,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, 1, resp.Results[0].Index)
+	assert.Equal(t, "Paris is the capital of France.", resp.Results[0].Document, "document text is echoed from the request")
+	assert.Equal(t, 12, resp.Usage.TotalTokens)
+}
+
+func TestProviderStreamCarriesUsageOnFinalChunk(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, map[string]any{"include_usage": true}, req["stream_options"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: {\"id\":\"chunk-1\",\"model\":\"gpt-4o-mini\",\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		_, _ = io.WriteString(w, "data: {\"id\":\"chunk-1\",\"model\":\"gpt-4o-mini\",\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		_, _ = io.WriteString(w, "data: {\"id\":\"chunk-1\",\"model\":\"gpt-4o-mini\",\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":1,\"total_tokens\":8}}\n\n")
+		_, _ = io.WriteString(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := provider.Stream(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+
+	var chunks []types.TextChunk
+	for chunk := range stream {
+		require.NoError(t, chunk.Error)
+		chunks = append(chunks, chunk)
+	}
+	require.NotEmpty(t, chunks)
+	final := chunks[len(chunks)-1]
+	assert.True(t, final.IsDone())
+	require.NotNil(t, final.Usage)
+	assert.Equal(t, 8, final.Usage.TotalTokens)
+}
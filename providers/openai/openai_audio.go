@@ -7,18 +7,24 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 
+	"github.com/garyblankenship/wormhole/v2/providers"
+	providerstream "github.com/garyblankenship/wormhole/v2/providers/internal/stream"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 const (
 	maxTextToSpeechAudioBytes = 64 << 20
-	maxSpeechToTextJSONBytes  = 1 << 20
+	// verbose_json with word timings runs to megabytes for long recordings.
+	maxSpeechToTextJSONBytes = 8 << 20
 )
 
-// Audio handles both speech-to-text and text-to-speech
+var _ types.TranscriptionStreamer = (*Provider)(nil)
+
+// Audio handles text-to-speech, speech-to-text, and translation into English
 func (p *Provider) Audio(ctx context.Context, request types.AudioRequest) (*types.AudioResponse, error) {
-	if request.Type == types.AudioRequestTypeSTT {
+	if request.Type == types.AudioRequestTypeSTT || request.Type == types.AudioRequestTypeTranslation {
 		return p.handleSpeechToText(ctx, request)
 	}
 
@@ -42,6 +48,9 @@ func (p *Provider) handleTextToSpeech(ctx context.Context, request types.AudioRe
 	if request.ResponseFormat != "" {
 		payload["response_format"] = request.ResponseFormat
 	}
+	for k, v := range p.Config.MergedProviderOptions(request.Model, request.ProviderOptions) {
+		payload[k] = v
+	}
 
 	url := p.GetBaseURL() + "/audio/speech"
 
@@ -65,79 +74,241 @@ func (p *Provider) handleTextToSpeech(ctx context.Context, request types.AudioRe
 	}, nil
 }
 
-// handleSpeechToText handles speech-to-text requests
+// handleSpeechToText handles transcription and translation requests. Asking
+// for timestamp granularities selects the verbose_json format, whose segments
+// and words are parsed into the response; text, srt, and vtt formats come
+// back verbatim in Text.
 func (p *Provider) handleSpeechToText(ctx context.Context, request types.AudioRequest) (*types.AudioResponse, error) {
+	form, err := p.speechToTextForm(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.postAudioForm(ctx, speechToTextPath(request), form)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := body.Close(); err != nil {
+			slog.Warn("failed to close response body", "error", err)
+		}
+	}()
+
+	data, err := readLimited(body, maxSpeechToTextJSONBytes)
+	if err != nil {
+		return nil, types.Errorf("read response", err)
+	}
+
+	switch form.responseFormat {
+	case "text", "srt", "vtt":
+		return &types.AudioResponse{
+			Model:  request.Model,
+			Text:   string(data),
+			Format: form.responseFormat,
+		}, nil
+	}
+
+	var sttResponse struct {
+		Text     string                    `json:"text"`
+		Language string                    `json:"language,omitempty"`
+		Duration float64                   `json:"duration,omitempty"`
+		Segments []types.TranscriptSegment `json:"segments,omitempty"`
+		Words    []types.TranscriptWord    `json:"words,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &sttResponse); err != nil {
+		return nil, types.Errorf("parse response", err)
+	}
+
+	return &types.AudioResponse{
+		Model:    request.Model,
+		Text:     sttResponse.Text,
+		Language: sttResponse.Language,
+		Duration: sttResponse.Duration,
+		Segments: sttResponse.Segments,
+		Words:    sttResponse.Words,
+		Format:   "text",
+	}, nil
+}
+
+// StreamTranscription streams transcript text while the audio is processed.
+// OpenAI streams only with the gpt-4o-transcribe models; whisper-1 rejects
+// the request.
+func (p *Provider) StreamTranscription(ctx context.Context, request types.AudioRequest) (<-chan types.TextChunk, error) {
+	if request.Type == types.AudioRequestTypeTranslation {
+		return nil, p.ValidationError("translations cannot be streamed")
+	}
+	if len(request.TimestampGranularities) > 0 {
+		return nil, p.ValidationError("timestamp granularities are not available on streamed transcriptions")
+	}
+	form, err := p.speechToTextForm(request)
+	if err != nil {
+		return nil, err
+	}
+	form.stream = true
+
+	body, err := p.postAudioForm(ctx, "/audio/transcriptions", form)
+	if err != nil {
+		return nil, err
+	}
+	return p.stampProvider(ctx, providerstream.ProcessSSE(ctx, body, parseTranscriptionEvent, 100)), nil
+}
+
+// transcriptionEvent is a streamed transcription event: text deltas, then a
+// done event with the full text and usage.
+type transcriptionEvent struct {
+	Type  string `json:"type"`
+	Delta string `json:"delta"`
+	Usage *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+func parseTranscriptionEvent(data []byte) (*types.TextChunk, error) {
+	var event transcriptionEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	switch event.Type {
+	case "transcript.text.delta":
+		return &types.TextChunk{Text: event.Delta, Delta: &types.ChunkDelta{Content: event.Delta}}, nil
+	case "transcript.text.done":
+		stop := types.FinishReasonStop
+		chunk := &types.TextChunk{FinishReason: &stop}
+		if event.Usage != nil {
+			chunk.Usage = &types.Usage{
+				PromptTokens:     event.Usage.InputTokens,
+				CompletionTokens: event.Usage.OutputTokens,
+				TotalTokens:      event.Usage.TotalTokens,
+			}
+		}
+		return chunk, nil
+	default:
+		return nil, nil
+	}
+}
+
+func speechToTextPath(request types.AudioRequest) string {
+	if request.Type == types.AudioRequestTypeTranslation {
+		return "/audio/translations"
+	}
+	return "/audio/transcriptions"
+}
+
+// speechToTextForm validates a transcription or translation request and
+// builds its form fields.
+func (p *Provider) speechToTextForm(request types.AudioRequest) (audioFormData, error) {
 	audio, ok := request.Input.([]byte)
 	if !ok || len(audio) == 0 {
-		return nil, p.ValidationError("speech-to-text input must be non-empty []byte audio")
+		return audioFormData{}, p.ValidationError("speech-to-text input must be non-empty []byte audio")
+	}
+
+	format := request.ResponseFormat
+	var granularities []string
+	if len(request.TimestampGranularities) > 0 {
+		if request.Type == types.AudioRequestTypeTranslation {
+			return audioFormData{}, p.ValidationError("timestamp granularities are not supported for translations")
+		}
+		switch format {
+		case "":
+			format = "verbose_json"
+		case "verbose_json":
+		default:
+			return audioFormData{}, p.ValidationErrorf("timestamp granularities need the verbose_json response format, not %q", format)
+		}
+		for _, granularity := range request.TimestampGranularities {
+			granularities = append(granularities, string(granularity))
+		}
+	}
+	if request.Type == types.AudioRequestTypeTranslation && request.Language != "" {
+		return audioFormData{}, p.ValidationError("translations always produce English; language is not accepted")
 	}
 
-	// Build multipart form data
-	formData := audioFormData{
-		audio:       audio,
-		filename:    "audio.wav",
-		model:       request.Model,
-		language:    request.Language,
-		prompt:      request.Prompt,
-		temperature: request.Temperature,
+	options, err := p.formOptionFields(request.Model, request.ProviderOptions)
+	if err != nil {
+		return audioFormData{}, err
 	}
 
-	reader, contentType, err := buildAudioForm(formData)
+	var filename string // buildAudioForm defaults to audio.wav
+	if request.AudioFormat != "" {
+		filename = "audio." + strings.TrimPrefix(request.AudioFormat, ".")
+	}
+	return audioFormData{
+		audio:                  audio,
+		filename:               filename,
+		model:                  request.Model,
+		language:               request.Language,
+		prompt:                 request.Prompt,
+		temperature:            request.Temperature,
+		responseFormat:         format,
+		timestampGranularities: granularities,
+		options:                options,
+	}, nil
+}
+
+// postAudioForm posts a multipart audio form and returns the response body.
+func (p *Provider) postAudioForm(ctx context.Context, path string, form audioFormData) (io.ReadCloser, error) {
+	reader, contentType, err := buildAudioForm(form)
 	if err != nil {
 		return nil, p.RequestError("failed to build audio form", err)
 	}
+	return p.postMultipart(ctx, path, reader, contentType, form.stream)
+}
 
-	// Make request to OpenAI Whisper API
-	url := fmt.Sprintf("%s/audio/transcriptions", p.Config.BaseURL)
+// postMultipart posts a multipart form and returns the response body. Error
+// statuses are read and mapped here. The per-request timeout ends when the
+// body is closed.
+func (p *Provider) postMultipart(ctx context.Context, path string, reader io.Reader, contentType string, stream bool) (io.ReadCloser, error) {
 	reqCtx, cancel := p.RequestContext(ctx)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(reqCtx, "POST", url, reader)
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, p.GetBaseURL()+path, reader)
 	if err != nil {
+		cancel()
 		return nil, p.RequestError("failed to create request", err)
 	}
 
 	// Set headers
 	req.Header.Set(types.HeaderAuthorization, "Bearer "+p.Config.APIKey)
 	req.Header.Set(types.HeaderContentType, contentType)
+	if stream {
+		req.Header.Set(types.HeaderAccept, types.ContentTypeEventStream)
+	}
+	providers.SetUserAgent(req, p.Config)
+	for k, v := range p.Config.Headers {
+		req.Header.Set(k, v)
+	}
 
-	// Execute request
 	resp, err := p.GetHTTPClient().Do(req)
 	if err != nil {
+		cancel()
 		return nil, p.WrapError(types.ErrorCodeNetwork, "request failed", err)
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			slog.Warn("failed to close response body", "error", err)
-		}
-	}()
-
-	// Parse response
-	body, err := readLimited(resp.Body, maxSpeechToTextJSONBytes)
-	if err != nil {
-		return nil, types.Errorf("read response", err)
-	}
 
 	if resp.StatusCode != http.StatusOK {
-		err := types.HTTPStatusToError(resp.StatusCode, string(body))
-		err.Provider = p.Name()
-		return nil, err
-	}
-
-	var sttResponse struct {
-		Text     string  `json:"text"`
-		Language string  `json:"language,omitempty"`
-		Duration float64 `json:"duration,omitempty"`
+		defer cancel()
+		defer func() { _ = resp.Body.Close() }()
+		body, err := readLimited(resp.Body, maxSpeechToTextJSONBytes)
+		if err != nil {
+			return nil, types.Errorf("read response", err)
+		}
+		statusErr := types.HTTPStatusToError(resp.StatusCode, string(body))
+		statusErr.Provider = p.Name()
+		return nil, statusErr
 	}
+	return &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}, nil
+}
 
-	if err := json.Unmarshal(body, &sttResponse); err != nil {
-		return nil, types.Errorf("parse response", err)
-	}
+// cancelOnClose ends a request context when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
 
-	return &types.AudioResponse{
-		Text:   sttResponse.Text,
-		Format: "text",
-	}, nil
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
 }
 
 func readLimited(r io.Reader, limit int64) ([]byte, error) {
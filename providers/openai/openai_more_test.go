@@ -31,7 +31,7 @@ func TestProviderSupportedCapabilities(t *testing.T) {
 	provider := New(types.ProviderConfig{APIKey: "test-key"})
 	capabilities := provider.SupportedCapabilities()
 
-	require.Len(t, capabilities, 8)
+	require.Len(t, capabilities, 9)
 	assert.Contains(t, capabilities, types.CapabilityText)
 	assert.Contains(t, capabilities, types.CapabilityChat)
 	assert.Contains(t, capabilities, types.CapabilityStructured)
@@ -40,6 +40,7 @@ func TestProviderSupportedCapabilities(t *testing.T) {
 	assert.Contains(t, capabilities, types.CapabilityImages)
 	assert.Contains(t, capabilities, types.CapabilityStream)
 	assert.Contains(t, capabilities, types.CapabilityFunctions)
+	assert.Contains(t, capabilities, types.CapabilityWebSearch)
 }
 
 func TestImageCapabilityHasGenerateImageImplementation(t *testing.T) {
@@ -98,10 +99,11 @@ func TestGetMaxTokensParam(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name     string
-		config   types.ProviderConfig
-		model    string
-		expected string
+		name      string
+		config    types.ProviderConfig
+		model     string
+		requested string
+		expected  string
 	}{
 		{
 			name:     "default model uses max_tokens",
@@ -139,6 +141,23 @@ func TestGetMaxTokensParam(t *testing.T) {
 			model:    "vendor/reasoning-model-large",
 			expected: "max_completion_tokens",
 		},
+		{
+			name:      "model constraint renames parameter",
+			config:    types.ProviderConfig{APIKey: "test-key"},
+			model:     "my-reasoning-finetune",
+			requested: "max_completion_tokens",
+			expected:  "max_completion_tokens",
+		},
+		{
+			name: "endpoint policy wins over model constraint",
+			config: types.ProviderConfig{
+				APIKey:        "test-key",
+				RequestPolicy: types.ProviderRequestPolicy{MaxTokensParam: "max_tokens"},
+			},
+			model:     "gpt-5",
+			requested: "max_completion_tokens",
+			expected:  "max_tokens",
+		},
 	}
 
 	for _, tt := range tests {
@@ -146,7 +165,7 @@ func TestGetMaxTokensParam(t *testing.T) {
 			t.Parallel()
 
 			provider := New(tt.config)
-			assert.Equal(t, tt.expected, provider.getMaxTokensParam(tt.model))
+			assert.Equal(t, tt.expected, provider.getMaxTokensParam(tt.model, tt.requested))
 		})
 	}
 }
@@ -393,7 +412,14 @@ func TestStampProviderCtxGuard(t *testing.T) {
 		out := provider.stampProvider(ctx, in)
 
 		select {
-		case _, ok := <-out:
+		case chunk, ok := <-out:
+			if ok {
+				// A waiting receiver is told why the stream stopped; no
+				// data chunk is delivered after cancel.
+				assert.ErrorIs(t, chunk.Error, context.Canceled)
+				assert.Empty(t, chunk.Text)
+				_, ok = <-out
+			}
 			assert.False(t, ok)
 		case <-time.After(200 * time.Millisecond):
 			t.Fatalf("stampProvider did not return after context cancellation")
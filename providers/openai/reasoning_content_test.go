@@ -1,6 +1,7 @@
 package openai
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -18,11 +19,7 @@ func TestTransformTextResponseReasoningContent(t *testing.T) {
 		ID:      "rc-1",
 		Model:   "deepseek-v4-pro",
 		Created: time.Now().Unix(),
-		Choices: []struct {
-			Index        int     `json:"index"`
-			Message      message `json:"message"`
-			FinishReason string  `json:"finish_reason"`
-		}{
+		Choices: []chatChoice{
 			{
 				Message:      message{Content: "the answer", ReasoningContent: "chain of thought"},
 				FinishReason: "stop",
@@ -38,11 +35,7 @@ func TestTransformTextResponseReasoningContent(t *testing.T) {
 		ID:      "rc-2",
 		Model:   "deepseek-v4-pro",
 		Created: time.Now().Unix(),
-		Choices: []struct {
-			Index        int     `json:"index"`
-			Message      message `json:"message"`
-			FinishReason string  `json:"finish_reason"`
-		}{
+		Choices: []chatChoice{
 			{
 				Message:      message{Content: "the answer"},
 				FinishReason: "stop",
@@ -101,3 +94,56 @@ func TestConvertUsagePromptCacheHitTokens(t *testing.T) {
 	})
 	assert.Equal(t, 25, result.CacheReadTokens)
 }
+
+func TestTransformTextResponseThinkTags(t *testing.T) {
+	t.Parallel()
+	provider := New(types.ProviderConfig{RequestPolicy: types.ProviderRequestPolicy{ThinkTags: true}})
+
+	response := &chatCompletionResponse{
+		Model: "deepseek-r1-distill-llama-70b",
+		Choices: []chatChoice{
+			{Message: message{Content: "\n<think>\nweigh options\n</think>\n\n{\"answer\": 42}"}, FinishReason: "stop"},
+		},
+	}
+	result := provider.transformTextResponse(response)
+	require.NotNil(t, result.Thinking)
+	assert.Equal(t, "\nweigh options\n", result.Thinking.Content)
+	assert.Equal(t, `{"answer": 42}`, result.Text)
+
+	response.Choices[0].Message.Content = "Use <think> tags like this."
+	result = provider.transformTextResponse(response)
+	assert.Nil(t, result.Thinking, "only a leading block is reasoning")
+	assert.Equal(t, "Use <think> tags like this.", result.Text)
+
+	response.Choices[0].Message.Content = "<think>plan</think>answer"
+	result = New(types.ProviderConfig{}).transformTextResponse(response)
+	assert.Nil(t, result.Thinking, "without the ThinkTags policy content is left alone")
+	assert.Equal(t, "<think>plan</think>answer", result.Text)
+
+	thinking, text := splitThinkTags("<think>cut off mid-thou")
+	assert.Equal(t, "cut off mid-thou", thinking)
+	assert.Empty(t, text)
+}
+
+func TestAccumulatingStreamSplitsThinkTagsAcrossChunks(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan types.TextChunk, 8)
+	for _, piece := range []string{"<thi", "nk>step one, ", "step two</th", "ink>\n", "The answer", " is 4."} {
+		in <- types.TextChunk{Text: piece, Delta: &types.ChunkDelta{Content: piece}}
+	}
+	stop := types.FinishReasonStop
+	in <- types.TextChunk{FinishReason: &stop}
+	close(in)
+
+	var thinking, text string
+	provider := New(types.ProviderConfig{RequestPolicy: types.ProviderRequestPolicy{ThinkTags: true}})
+	for chunk := range provider.accumulatingStream(context.Background(), in) {
+		text += chunk.Content()
+		if chunk.Thinking != nil {
+			thinking += chunk.Thinking.Content
+		}
+	}
+	assert.Equal(t, "step one, step two", thinking)
+	assert.Equal(t, "The answer is 4.", text)
+}
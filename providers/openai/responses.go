@@ -31,6 +31,9 @@ func (p *Provider) responsesText(ctx context.Context, request types.TextRequest)
 	if err := p.validateResponsesSampling(request); err != nil {
 		return nil, err
 	}
+	if err := p.checkDocuments(request.Messages, responsesDocumentLimits); err != nil {
+		return nil, err
+	}
 	payload := p.buildResponsesPayload(&request)
 
 	var response responsesResponse
@@ -44,8 +47,8 @@ func (p *Provider) responsesText(ctx context.Context, request types.TextRequest)
 	textResponse := p.transformResponsesTextResponse(&response)
 	textResponse.Provider = p.Name()
 
-	if textResponse.Text == "" && len(textResponse.ToolCalls) == 0 {
-		return nil, p.ProviderError("received empty response from OpenAI Responses API", "no output text or tool calls returned")
+	if textResponse.IsEmpty() {
+		return nil, types.ErrEmptyResponse.WithProvider(p.Name()).WithModel(request.Model).WithDetails("no output text or tool calls returned")
 	}
 
 	return textResponse, nil
@@ -55,6 +58,9 @@ func (p *Provider) responsesStream(ctx context.Context, request types.TextReques
 	if err := p.validateResponsesSampling(request); err != nil {
 		return nil, err
 	}
+	if err := p.checkDocuments(request.Messages, responsesDocumentLimits); err != nil {
+		return nil, err
+	}
 	payload := p.buildResponsesPayload(&request)
 	payload["stream"] = true
 
@@ -95,6 +101,7 @@ func (p *Provider) buildResponsesPayload(request *types.TextRequest) map[string]
 	if err != nil {
 		messages = request.Messages // fall through; provider will surface the issue
 	}
+	messages, _ = p.Config.RequestPolicy.Roles.Apply(messages)
 	payload := map[string]any{
 		"model": request.Model,
 		"input": p.transformResponsesInput(messages),
@@ -112,6 +119,12 @@ func (p *Provider) buildResponsesPayload(request *types.TextRequest) map[string]
 	if request.ParallelToolCalls != nil {
 		payload["parallel_tool_calls"] = *request.ParallelToolCalls
 	}
+	if request.Logprobs {
+		payload["include"] = []string{"message.output_text.logprobs"}
+		if request.TopLogprobs > 0 {
+			payload["top_logprobs"] = request.TopLogprobs
+		}
+	}
 
 	if reasoning := reasoningPayload(request.Reasoning); len(reasoning) > 0 {
 		payload["reasoning"] = reasoning
@@ -123,6 +136,10 @@ func (p *Provider) buildResponsesPayload(request *types.TextRequest) map[string]
 			payload["tool_choice"] = p.transformResponsesToolChoice(request.ToolChoice)
 		}
 	}
+	if request.WebSearch != nil {
+		tools, _ := payload["tools"].([]map[string]any)
+		payload["tools"] = append(tools, responsesWebSearchTool(request.WebSearch))
+	}
 
 	if request.ResponseFormat != nil {
 		payload["text"] = map[string]any{
@@ -141,5 +158,11 @@ func (p *Provider) validateResponsesSampling(request types.TextRequest) error {
 	if request.FrequencyPenalty != nil || request.PresencePenalty != nil || request.Seed != nil {
 		return p.ValidationError("frequency_penalty, presence_penalty, and seed are not supported by the OpenAI Responses API")
 	}
-	return nil
+	if request.Audio != nil {
+		return p.ValidationError("audio output is not supported by the OpenAI Responses API; use Chat Completions")
+	}
+	if request.N > 1 {
+		return p.ValidationError("n above 1 is not supported by the OpenAI Responses API; use Chat Completions")
+	}
+	return p.CheckSamplingParams(request.BaseRequest, "the OpenAI Responses API")
 }
@@ -39,10 +39,13 @@ func (p *Provider) transformResponsesInput(messages []types.Message) []map[strin
 	return items
 }
 
-func responsesUserMessageContent(msg *types.UserMessage) []types.MessagePart {
-	parts := make([]types.MessagePart, 0, 1+len(msg.Media))
+func responsesUserMessageContent(msg *types.UserMessage) []map[string]any {
+	parts := make([]map[string]any, 0, 1+len(msg.Media))
 	if msg.Content != "" {
-		parts = append(parts, types.TextPart(msg.Content))
+		parts = append(parts, map[string]any{
+			"type": responsesContentInputText,
+			"text": msg.Content,
+		})
 	}
 	for _, media := range msg.Media {
 		if image, ok := media.(*types.ImageMedia); ok {
@@ -50,7 +53,18 @@ func responsesUserMessageContent(msg *types.UserMessage) []types.MessagePart {
 			if !ok {
 				continue
 			}
-			parts = append(parts, types.ImagePart(url))
+			parts = append(parts, map[string]any{
+				"type":      responsesContentInputImage,
+				"image_url": url,
+			})
+			continue
+		}
+		if doc, ok := media.(*types.DocumentMedia); ok {
+			parts = append(parts, responsesDocumentPart(doc))
+			continue
+		}
+		if part, ok := types.MarshalMedia(types.MediaFormatOpenAIResponses, media); ok {
+			parts = append(parts, part)
 		}
 	}
 	return parts
@@ -129,6 +143,17 @@ func responsesFunctionCallItem(tc types.ToolCall) map[string]any {
 	}
 }
 
+// responsesWebSearchTool builds the hosted web_search tool. The Responses API
+// filters by allowed domains only; MaxUses and BlockedDomains have no
+// equivalent.
+func responsesWebSearchTool(search *types.WebSearch) map[string]any {
+	tool := map[string]any{"type": "web_search"}
+	if len(search.AllowedDomains) > 0 {
+		tool["filters"] = map[string]any{"allowed_domains": search.AllowedDomains}
+	}
+	return tool
+}
+
 func (p *Provider) transformResponsesTools(tools []types.Tool) []map[string]any {
 	result := make([]map[string]any, 0, len(tools))
 	for _, tool := range tools {
@@ -61,3 +61,36 @@ func TestResponseFunctionCallToToolCall_EmptyArgsAndIDFallback(t *testing.T) {
 	assert.Empty(t, tc.ArgsParseError)
 	assert.Equal(t, map[string]any{}, tc.Arguments, "empty args -> empty map (emptyVal)")
 }
+
+func TestResponsesRawFinishReason(t *testing.T) {
+	t.Parallel()
+	p := &Provider{}
+
+	incomplete := &responsesResponse{Status: "incomplete"}
+	incomplete.IncompleteDetails = &struct {
+		Reason string `json:"reason"`
+	}{Reason: "max_output_tokens"}
+	resp := p.transformResponsesTextResponse(incomplete)
+	assert.Equal(t, types.FinishReasonLength, resp.FinishReason)
+	assert.Equal(t, "max_output_tokens", resp.RawFinishReason)
+
+	resp = p.transformResponsesTextResponse(&responsesResponse{Status: "completed"})
+	assert.Equal(t, types.FinishReasonStop, resp.FinishReason)
+	assert.Equal(t, "completed", resp.RawFinishReason)
+}
+
+func TestTransformResponsesTextResponse_RefusalPart(t *testing.T) {
+	t.Parallel()
+	p := &Provider{}
+
+	resp := p.transformResponsesTextResponse(&responsesResponse{
+		Status: "completed",
+		Output: []responsesOutputItem{{
+			Type:    responsesItemMessage,
+			Content: []responsesContentPart{{Type: responsesContentRefusal, Refusal: "I can't help with that."}},
+		}},
+	})
+	assert.Empty(t, resp.Text)
+	assert.Equal(t, "I can't help with that.", resp.Refusal)
+	assert.True(t, resp.IsRefusal())
+}
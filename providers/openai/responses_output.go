@@ -9,37 +9,81 @@ import (
 
 func (p *Provider) transformResponsesTextResponse(response *responsesResponse) *types.TextResponse {
 	text := response.OutputText
+	var refusal string
 	var toolCalls []types.ToolCall
+	var logprobs []types.TokenLogprob
+	var citations []types.Citation
 	for _, item := range response.Output {
 		switch item.Type {
 		case responsesItemMessage:
 			if text == "" {
 				text += responsesOutputText(item.Content)
 			}
+			for _, part := range item.Content {
+				if part.Type == responsesContentRefusal {
+					refusal += part.Refusal
+				}
+				logprobs = append(logprobs, part.Logprobs...)
+				citations = appendResponsesCitations(citations, part.Annotations)
+			}
 		case responsesItemFunctionCall:
 			toolCalls = append(toolCalls, responseFunctionCallToToolCall(item))
 		}
 	}
 
 	return &types.TextResponse{
-		ID:           response.ID,
-		Model:        response.Model,
-		Text:         text,
-		ToolCalls:    toolCalls,
-		FinishReason: responsesFinishReason(response, toolCalls),
-		Usage:        response.Usage.toUsage(),
-		Created:      time.Unix(response.CreatedAt, 0),
+		ID:              response.ID,
+		Model:           response.Model,
+		Text:            text,
+		Refusal:         refusal,
+		ToolCalls:       toolCalls,
+		FinishReason:    responsesFinishReason(response, toolCalls),
+		Usage:           response.Usage.toUsage(),
+		Logprobs:        logprobs,
+		Citations:       citations,
+		Created:         time.Unix(response.CreatedAt, 0),
+		RawFinishReason: responsesRawFinishReason(response),
+	}
+}
+
+func appendResponsesCitations(citations []types.Citation, annotations []responsesAnnotation) []types.Citation {
+	for _, a := range annotations {
+		switch a.Type {
+		case "url_citation":
+			citations = append(citations, types.Citation{
+				Type:       types.CitationTypeURL,
+				URL:        a.URL,
+				Title:      a.Title,
+				StartIndex: a.StartIndex,
+				EndIndex:   a.EndIndex,
+			})
+		case "file_citation":
+			// A file citation marks a single position rather than a span.
+			citations = append(citations, types.Citation{
+				Type:       types.CitationTypeFile,
+				FileID:     a.FileID,
+				Title:      a.Filename,
+				StartIndex: a.Index,
+				EndIndex:   a.Index,
+			})
+		case "container_file_citation":
+			citations = append(citations, types.Citation{
+				Type:       types.CitationTypeFile,
+				FileID:     a.FileID,
+				Title:      a.Filename,
+				StartIndex: a.StartIndex,
+				EndIndex:   a.EndIndex,
+			})
+		}
 	}
+	return citations
 }
 
 func responsesOutputText(parts []responsesContentPart) string {
 	var text string
 	for _, part := range parts {
-		switch part.Type {
-		case responsesContentOutputText:
+		if part.Type == responsesContentOutputText {
 			text += part.Text
-		case responsesContentRefusal:
-			text += part.Refusal
 		}
 	}
 	return text
@@ -67,6 +111,15 @@ func responseFunctionCallToToolCall(item responsesOutputItem) types.ToolCall {
 	return toolCall
 }
 
+// responsesRawFinishReason is the incomplete reason, such as
+// max_output_tokens, or else the response status.
+func responsesRawFinishReason(response *responsesResponse) string {
+	if response.IncompleteDetails != nil && response.IncompleteDetails.Reason != "" {
+		return response.IncompleteDetails.Reason
+	}
+	return response.Status
+}
+
 func responsesFinishReason(response *responsesResponse, toolCalls []types.ToolCall) types.FinishReason {
 	if len(toolCalls) > 0 {
 		return types.FinishReasonToolCalls
@@ -106,6 +159,7 @@ func (p *Provider) parseResponsesStreamChunk(data []byte) (*types.TextChunk, err
 			Delta: &types.ChunkDelta{
 				Content: event.Delta,
 			},
+			Logprobs: event.Logprobs,
 		}, nil
 	case responsesEventOutputItemAdded:
 		if event.Item == nil || event.Item.Type != responsesItemFunctionCall {
@@ -145,11 +199,12 @@ func (p *Provider) parseResponsesStreamChunk(data []byte) (*types.TextChunk, err
 		resp := p.transformResponsesTextResponse(event.Response)
 		reason := resp.FinishReason
 		return &types.TextChunk{
-			ID:           resp.ID,
-			Model:        resp.Model,
-			ToolCalls:    resp.ToolCalls,
-			FinishReason: &reason,
-			Usage:        resp.Usage,
+			ID:              resp.ID,
+			Model:           resp.Model,
+			ToolCalls:       resp.ToolCalls,
+			FinishReason:    &reason,
+			Usage:           resp.Usage,
+			RawFinishReason: resp.RawFinishReason,
 		}, nil
 	case responsesEventFailed:
 		if event.Response != nil && event.Response.Error != nil {
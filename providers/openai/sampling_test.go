@@ -0,0 +1,81 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func samplingRequest() types.TextRequest {
+	topK := 40
+	minP := float32(0.05)
+	repetition := float32(1.1)
+	return types.TextRequest{
+		BaseRequest: types.BaseRequest{
+			Model:             "llama-3",
+			TopK:              &topK,
+			MinP:              &minP,
+			RepetitionPenalty: &repetition,
+			LogitBias:         map[int]int{50256: -100},
+		},
+		Messages: []types.Message{types.NewUserMessage("hi")},
+	}
+}
+
+func TestChatSamplingParamsSentWithoutPolicy(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, float64(40), req["top_k"])
+		assert.InDelta(t, 0.05, req["min_p"], 1e-6)
+		assert.InDelta(t, 1.1, req["repetition_penalty"], 1e-6)
+		assert.Equal(t, map[string]any{"50256": float64(-100)}, req["logit_bias"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"c","model":"llama-3","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"ok"}}]}`))
+	})
+
+	resp, err := provider.Text(context.Background(), samplingRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Text)
+}
+
+func TestChatSamplingParamsRejectedByPolicy(t *testing.T) {
+	t.Parallel()
+	called := false
+	provider, _ := newOpenAITestProviderWithConfig(t, types.ProviderConfig{
+		APIKey:        "test-key",
+		RequestPolicy: types.ProviderRequestPolicy{SamplingParams: []types.SamplingParam{types.SamplingLogitBias}},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	_, err := provider.Text(context.Background(), samplingRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "top_k, min_p, repetition_penalty are not supported by openai")
+	_, err = provider.Stream(context.Background(), samplingRequest())
+	require.Error(t, err)
+	assert.False(t, called, "unsupported params must fail before the request is sent")
+
+	request := samplingRequest()
+	request.TopK, request.MinP, request.RepetitionPenalty = nil, nil, nil
+	assert.NoError(t, provider.validateChatSampling(request))
+}
+
+func TestResponsesAPIRejectsSamplingParams(t *testing.T) {
+	t.Parallel()
+	provider := New(types.ProviderConfig{APIKey: "test-key", UseResponsesAPI: true})
+	request := samplingRequest()
+	request.TopK, request.MinP, request.RepetitionPenalty = nil, nil, nil
+
+	_, err := provider.Text(context.Background(), request)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logit_bias is not supported by the OpenAI Responses API")
+}
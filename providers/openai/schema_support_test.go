@@ -0,0 +1,56 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/providers"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestStrictStructuredDowngradesWithSchemaSupport(t *testing.T) {
+	t.Parallel()
+
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"c1","model":"local","choices":[{"message":{"role":"assistant","content":"{\"n\":1}"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	schema := json.RawMessage(`{"type":"object","properties":{"n":{"anyOf":[{"type":"integer"},{"type":"null"}]}}}`)
+	request := types.StructuredRequest{
+		BaseRequest: types.BaseRequest{Model: "local"},
+		Messages:    []types.Message{types.NewUserMessage("n?")},
+		Schema:      schema,
+		Mode:        types.StructuredModeStrict,
+	}
+
+	p := New(types.ProviderConfig{APIKey: "k", BaseURL: server.URL, SchemaSupport: &types.SchemaSupport{}})
+	resp, err := p.Structured(context.Background(), request)
+	require.NoError(t, err)
+
+	sentSchema := sent["response_format"].(map[string]any)["json_schema"].(map[string]any)["schema"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "integer", "nullable": true}, sentSchema["properties"].(map[string]any)["n"])
+	assert.Equal(t, []string{
+		"response schema #/properties/n: anyOf rewritten as a plain schema",
+		"response schema #: additionalProperties dropped",
+	}, resp.Metadata[providers.SchemaWarningsKey])
+
+	// Without a profile the strict-mode schema is sent as is.
+	p = New(types.ProviderConfig{APIKey: "k", BaseURL: server.URL})
+	resp, err = p.Structured(context.Background(), request)
+	require.NoError(t, err)
+	sentSchema = sent["response_format"].(map[string]any)["json_schema"].(map[string]any)["schema"].(map[string]any)
+	assert.Contains(t, sentSchema["properties"].(map[string]any)["n"], "anyOf")
+	assert.Equal(t, []any{"n"}, sentSchema["required"])
+	assert.Equal(t, false, sentSchema["additionalProperties"])
+	assert.Nil(t, resp.Metadata, "n already accepts null, so strict mode changes nothing worth a warning")
+}
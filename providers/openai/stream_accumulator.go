@@ -17,13 +17,20 @@ type accumulatedToolCall struct {
 // tool-call argument fragments. It is the sole closer of its output channel and
 // guards every send with ctx so it exits when the consumer stops reading.
 // On the terminal chunk (FinishReason set), assembled tool calls are attached
-// to that chunk's ToolCalls before it is forwarded.
+// to that chunk's ToolCalls before it is forwarded. With the ThinkTags request
+// policy, a leading <think> block in the content is moved to the chunks'
+// Thinking (see thinkTagSplitter).
 func (p *Provider) accumulatingStream(ctx context.Context, in <-chan types.TextChunk) <-chan types.TextChunk {
 	out := make(chan types.TextChunk)
 	go func() {
 		defer close(out)
 		acc := newStreamFragmentAccumulator()
+		var think thinkTagSplitter
+		if !p.splitsThinkTags() {
+			think.state = thinkDone
+		}
 		for chunk := range in {
+			applyThinkSplit(&think, &chunk, chunk.IsDone() || chunk.Error != nil)
 			// Fold any tool-call fragments out of the delta; they are buffered,
 			// not forwarded mid-stream (a partial fragment is not a usable call).
 			if chunk.Delta != nil && len(chunk.Delta.ToolCalls) > 0 {
@@ -60,6 +67,15 @@ func (p *Provider) accumulatingStream(ctx context.Context, in <-chan types.TextC
 				return
 			}
 		}
+		// A stream cut off without a terminal chunk may leave a partial tag
+		// buffered; forward it rather than drop it.
+		var tail types.TextChunk
+		if applyThinkSplit(&think, &tail, true); tail.Text != "" || tail.Thinking != nil {
+			select {
+			case out <- tail:
+			case <-ctx.Done():
+			}
+		}
 	}()
 	return out
 }
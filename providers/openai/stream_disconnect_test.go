@@ -0,0 +1,77 @@
+package openai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func requireInterruptedStream(t *testing.T, stream <-chan types.TextChunk, wantText string) {
+	t.Helper()
+	chunks, closed := testutil.DrainStream(stream, 5*time.Second)
+	require.True(t, closed, "stream must close after the connection drops")
+	require.NotEmpty(t, chunks)
+
+	var text string
+	for _, chunk := range chunks[:len(chunks)-1] {
+		require.NoError(t, chunk.Error)
+		text += chunk.Content()
+	}
+	assert.Equal(t, wantText, text)
+
+	last := chunks[len(chunks)-1]
+	wormholeErr, ok := types.AsWormholeError(last.Error)
+	require.True(t, ok, "final chunk must carry a *types.WormholeError, got %v", last.Error)
+	assert.Equal(t, types.ErrorCodeNetwork, wormholeErr.Code)
+	assert.Equal(t, "openai", wormholeErr.Provider)
+	assert.True(t, wormholeErr.Retryable)
+}
+
+func TestStreamAbruptDisconnect(t *testing.T) {
+	t.Parallel()
+	for _, responses := range []bool{false, true} {
+		partial := `data: {"id":"c1","model":"gpt-4o","choices":[{"index":0,"delta":{"content":"Hel"}}]}` + "\n\n"
+		if responses {
+			partial = "event: response.output_text.delta\n" + `data: {"type":"response.output_text.delta","delta":"Hel"}` + "\n\n"
+		}
+		server := testutil.DisconnectingServer(t, "text/event-stream", partial)
+
+		provider := New(types.ProviderConfig{APIKey: "test-key", BaseURL: server.URL})
+		provider.Config.UseResponsesAPI = responses
+		stream, err := provider.Stream(context.Background(), types.TextRequest{
+			BaseRequest: types.BaseRequest{Model: "gpt-4o"},
+			Messages:    []types.Message{types.NewUserMessage("hi")},
+		})
+		require.NoError(t, err)
+		requireInterruptedStream(t, stream, "Hel")
+	}
+}
+
+func TestStreamClosesPromptlyOnCancel(t *testing.T) {
+	t.Parallel()
+	server := testutil.StallingServer(t, "text/event-stream",
+		`data: {"id":"c1","model":"gpt-4o","choices":[{"index":0,"delta":{"content":"Hel"}}]}`+"\n\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	provider := New(types.ProviderConfig{APIKey: "test-key", BaseURL: server.URL})
+	stream, err := provider.Stream(ctx, types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+
+	first := <-stream
+	require.NoError(t, first.Error)
+	assert.Equal(t, "Hel", first.Content())
+
+	cancel()
+	_, closed := testutil.DrainStream(stream, 2*time.Second)
+	assert.True(t, closed, "stream must close once ctx is canceled, even while the body is stalled")
+}
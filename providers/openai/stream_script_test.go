@@ -0,0 +1,71 @@
+package openai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func streamScript(t *testing.T, script testutil.StreamScript) []types.TextChunk {
+	t.Helper()
+	server := testutil.StreamServer(t, testutil.OpenAIStream, script)
+	provider := New(types.ProviderConfig{APIKey: "test-key", BaseURL: server.URL})
+	stream, err := provider.Stream(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o"},
+		Messages:    []types.Message{types.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+	chunks, closed := testutil.DrainStream(stream, 5*time.Second)
+	require.True(t, closed, "stream must close")
+	return chunks
+}
+
+func TestStreamScriptedToolCallAndUsage(t *testing.T) {
+	t.Parallel()
+	chunks := streamScript(t, testutil.StreamScript{
+		Text:      []string{"Hel", "lo"},
+		ToolCalls: []testutil.StreamToolCall{{ID: "call_1", Name: "get_weather", Arguments: []string{`{"city":`, `"Paris"}`}}},
+		Usage:     &types.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	})
+	for _, chunk := range chunks {
+		require.NoError(t, chunk.Error)
+	}
+
+	merged := testutil.MergeTextChunks(chunks)
+	assert.Equal(t, "Hello", merged.Text)
+	assert.Equal(t, types.FinishReasonToolCalls, merged.FinishReason)
+	require.Len(t, merged.ToolCalls, 1)
+	assert.Equal(t, "get_weather", merged.ToolCalls[0].Name)
+	assert.Equal(t, map[string]any{"city": "Paris"}, merged.ToolCalls[0].Arguments)
+	require.NotNil(t, merged.Usage)
+	assert.Equal(t, 15, merged.Usage.TotalTokens)
+}
+
+func TestStreamScriptedFailures(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		script testutil.StreamScript
+		code   types.ErrorCode
+	}{
+		{"in-band error", testutil.StreamScript{Text: []string{"Hel"}, Error: "overloaded"}, types.ErrorCodeProvider},
+		{"truncated", testutil.StreamScript{Text: []string{"Hel"}, Truncate: true}, types.ErrorCodeNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chunks := streamScript(t, tt.script)
+			require.NotEmpty(t, chunks)
+			assert.Equal(t, "Hel", testutil.MergeTextChunks(chunks[:len(chunks)-1]).Text)
+			wormholeErr, ok := types.AsWormholeError(chunks[len(chunks)-1].Error)
+			require.True(t, ok, "final chunk must carry a *types.WormholeError")
+			assert.Equal(t, tt.code, wormholeErr.Code)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"github.com/garyblankenship/wormhole/v2/internal/pool"
+	"github.com/garyblankenship/wormhole/v2/providers"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
@@ -18,6 +19,7 @@ func (p *Provider) Structured(ctx context.Context, request types.StructuredReque
 	}
 
 	// Determine the best method for structured output
+	var strictWarnings []string
 	switch request.Mode {
 	case types.StructuredModeJSON:
 		textRequest.ResponseFormat = map[string]string{"type": "json_object"}
@@ -29,6 +31,7 @@ func (p *Provider) Structured(ctx context.Context, request types.StructuredReque
 		if err != nil {
 			return nil, err
 		}
+		schemaMap, strictWarnings = types.StrictSchema(schemaMap)
 		name := request.SchemaName
 		if name == "" {
 			name = "structured_output"
@@ -58,19 +61,26 @@ func (p *Provider) Structured(ctx context.Context, request types.StructuredReque
 	if err != nil {
 		return nil, err
 	}
+	if err := response.RefusalError(); err != nil {
+		return nil, err
+	}
 
 	data, err := p.extractStructuredData(request.Mode, response)
 	if err != nil {
 		return nil, err
 	}
 
-	return &types.StructuredResponse{
+	structured := &types.StructuredResponse{
 		ID:      response.ID,
 		Model:   response.Model,
 		Data:    data,
 		Usage:   response.Usage,
 		Created: response.Created,
-	}, nil
+	}
+	warnings, _ := response.Metadata[providers.SchemaWarningsKey].([]string)
+	warnings = append(providers.PrefixSchemaWarnings("strict schema", strictWarnings), warnings...)
+	structured.Metadata = providers.AddSchemaWarnings(nil, warnings)
+	return structured, nil
 }
 
 // extractStructuredData decodes the model response into structured data per the
@@ -0,0 +1,141 @@
+package openai
+
+import (
+	"strings"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+type thinkState int
+
+const (
+	thinkStart thinkState = iota // deciding whether content opens with <think>
+	thinkInside
+	thinkAfter // skipping whitespace after </think>
+	thinkDone  // plain content
+)
+
+// thinkTagSplitter moves a leading <think>...</think> block out of content
+// and into thinking. DeepSeek-R1 and its distills return reasoning this way
+// on hosts that do not translate it to reasoning_content (Groq, vLLM, LM
+// Studio, Ollama's OpenAI endpoint); it runs only when the provider's
+// ThinkTags request policy is set, as those profiles do. Only a block at the
+// very start of the content counts; later tags are ordinary text. It is fed
+// streamed deltas in order and buffers just enough to recognize tags split
+// across chunks.
+type thinkTagSplitter struct {
+	state thinkState
+	buf   string
+}
+
+// splitsThinkTags reports whether the provider's request policy asks for
+// inline think blocks to be split out.
+func (p *Provider) splitsThinkTags() bool {
+	return p.BaseProvider != nil && p.Config.RequestPolicy.ThinkTags
+}
+
+// feed consumes the next piece of content and returns the thinking and
+// content it completes.
+func (s *thinkTagSplitter) feed(text string) (thinking, content string) {
+	switch s.state {
+	case thinkStart:
+		s.buf += text
+		trimmed := strings.TrimLeft(s.buf, " \t\r\n")
+		if strings.HasPrefix(thinkOpenTag, trimmed) && len(trimmed) < len(thinkOpenTag) {
+			return "", ""
+		}
+		if !strings.HasPrefix(trimmed, thinkOpenTag) {
+			s.state = thinkDone
+			content, s.buf = s.buf, ""
+			return "", content
+		}
+		s.state = thinkInside
+		s.buf = ""
+		return s.feed(trimmed[len(thinkOpenTag):])
+	case thinkInside:
+		s.buf += text
+		if i := strings.Index(s.buf, thinkCloseTag); i >= 0 {
+			thinking, rest := s.buf[:i], s.buf[i+len(thinkCloseTag):]
+			s.state = thinkAfter
+			s.buf = ""
+			_, content = s.feed(rest)
+			return thinking, content
+		}
+		keep := partialSuffix(s.buf, thinkCloseTag)
+		thinking = s.buf[:len(s.buf)-keep]
+		s.buf = s.buf[len(s.buf)-keep:]
+		return thinking, ""
+	case thinkAfter:
+		text = strings.TrimLeft(text, " \t\r\n")
+		if text != "" {
+			s.state = thinkDone
+		}
+		return "", text
+	default:
+		return "", text
+	}
+}
+
+// flush returns whatever is still buffered at the end of the content. An
+// unterminated block (a truncated response) is all thinking.
+func (s *thinkTagSplitter) flush() (thinking, content string) {
+	buf := s.buf
+	s.buf = ""
+	if s.state == thinkInside {
+		return buf, ""
+	}
+	return "", buf
+}
+
+// partialSuffix returns the length of the longest suffix of s that is a
+// proper prefix of tag.
+func partialSuffix(s, tag string) int {
+	for n := min(len(s), len(tag)-1); n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// splitThinkTags separates a complete response's leading think block.
+func splitThinkTags(content string) (thinking, text string) {
+	var s thinkTagSplitter
+	thinking, text = s.feed(content)
+	restThinking, restText := s.flush()
+	return thinking + restThinking, text + restText
+}
+
+// applyThinkSplit routes a stream chunk's content through s, replacing it
+// with the content part and appending the thinking part to the chunk's
+// thinking.
+func applyThinkSplit(s *thinkTagSplitter, chunk *types.TextChunk, flush bool) {
+	if s.state == thinkDone {
+		return
+	}
+	thinking, content := s.feed(chunk.Content())
+	if flush {
+		moreThinking, moreContent := s.flush()
+		thinking += moreThinking
+		content += moreContent
+	}
+	chunk.Text = content
+	if chunk.Delta != nil {
+		chunk.Delta.Content = content
+	}
+	if thinking == "" {
+		return
+	}
+	if chunk.Thinking == nil {
+		chunk.Thinking = &types.Thinking{}
+	}
+	chunk.Thinking.Content += thinking
+	if chunk.Delta != nil {
+		chunk.Delta.Thinking = chunk.Thinking
+	}
+}
@@ -0,0 +1,140 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/internal/testutil"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestSpeechToTextVerboseJSONTimings(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/audio/transcriptions", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, []string{"verbose_json"}, r.MultipartForm.Value["response_format"])
+		assert.Equal(t, []string{"segment", "word"}, r.MultipartForm.Value["timestamp_granularities[]"])
+		assert.Equal(t, "audio.mp3", r.MultipartForm.File["file"][0].Filename)
+		_, _ = io.WriteString(w, `{"task":"transcribe","language":"english","duration":1.5,"text":"Hello there.",
+			"segments":[{"id":0,"seek":0,"start":0.0,"end":1.5,"text":" Hello there.","tokens":[1,2],"temperature":0.0,"avg_logprob":-0.2,"compression_ratio":0.9,"no_speech_prob":0.01}],
+			"words":[{"word":"Hello","start":0.0,"end":0.6},{"word":"there","start":0.7,"end":1.2}]}`)
+	})
+
+	resp, err := provider.Audio(context.Background(), types.AudioRequest{
+		Type:        types.AudioRequestTypeSTT,
+		Model:       "whisper-1",
+		Input:       []byte("audio"),
+		AudioFormat: "mp3",
+		TimestampGranularities: []types.TimestampGranularity{
+			types.TimestampGranularitySegment,
+			types.TimestampGranularityWord,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello there.", resp.Text)
+	assert.Equal(t, "english", resp.Language)
+	assert.InDelta(t, 1.5, resp.Duration, 1e-9)
+	assert.Equal(t, []types.TranscriptSegment{{
+		Start: 0, End: 1.5, Text: " Hello there.", AvgLogprob: -0.2, CompressionRatio: 0.9, NoSpeechProb: 0.01,
+	}}, resp.Segments)
+	assert.Equal(t, []types.TranscriptWord{
+		{Word: "Hello", Start: 0, End: 0.6},
+		{Word: "there", Start: 0.7, End: 1.2},
+	}, resp.Words)
+}
+
+func TestSpeechToTextSubtitleFormatIsVerbatim(t *testing.T) {
+	t.Parallel()
+	const srt = "1\n00:00:00,000 --> 00:00:01,500\nHello there.\n"
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, srt)
+	})
+
+	resp, err := provider.Audio(context.Background(), types.AudioRequest{
+		Type:           types.AudioRequestTypeSTT,
+		Model:          "whisper-1",
+		Input:          []byte("audio"),
+		ResponseFormat: "srt",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, srt, resp.Text)
+	assert.Equal(t, "srt", resp.Format)
+}
+
+func TestTranslationEndpointAndValidation(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/audio/translations", r.URL.Path)
+		_, _ = io.WriteString(w, `{"text":"Good morning."}`)
+	})
+	request := types.AudioRequest{
+		Type:  types.AudioRequestTypeTranslation,
+		Model: "whisper-1",
+		Input: []byte("audio"),
+	}
+
+	resp, err := provider.Audio(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "Good morning.", resp.Text)
+
+	withLanguage := request
+	withLanguage.Language = "de"
+	_, err = provider.Audio(context.Background(), withLanguage)
+	assert.ErrorContains(t, err, "always produce English")
+
+	withTimings := request
+	withTimings.TimestampGranularities = []types.TimestampGranularity{types.TimestampGranularityWord}
+	_, err = provider.Audio(context.Background(), withTimings)
+	assert.ErrorContains(t, err, "not supported for translations")
+
+	wrongFormat := types.AudioRequest{
+		Type:                   types.AudioRequestTypeSTT,
+		Model:                  "whisper-1",
+		Input:                  []byte("audio"),
+		ResponseFormat:         "json",
+		TimestampGranularities: []types.TimestampGranularity{types.TimestampGranularityWord},
+	}
+	_, err = provider.Audio(context.Background(), wrongFormat)
+	assert.ErrorContains(t, err, "verbose_json")
+}
+
+func TestStreamTranscription(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, []string{"true"}, r.MultipartForm.Value["stream"])
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\"Hello\"}\n\n"+
+			"data: {\"type\":\"transcript.text.delta\",\"delta\":\" there.\"}\n\n"+
+			"data: {\"type\":\"transcript.text.done\",\"text\":\"Hello there.\",\"usage\":{\"type\":\"tokens\",\"input_tokens\":14,\"output_tokens\":3,\"total_tokens\":17}}\n\n")
+	})
+
+	stream, err := provider.StreamTranscription(context.Background(), types.AudioRequest{
+		Type:  types.AudioRequestTypeSTT,
+		Model: "gpt-4o-mini-transcribe",
+		Input: []byte("audio"),
+	})
+	require.NoError(t, err)
+	chunks, ok := testutil.DrainStream(stream, 5*time.Second)
+	require.True(t, ok, "stream did not close")
+	assert.Equal(t, "Hello there.", testutil.MergeTextChunks(chunks).Text)
+	last := chunks[len(chunks)-1]
+	require.True(t, last.IsDone())
+	require.NotNil(t, last.Usage)
+	assert.Equal(t, 17, last.Usage.TotalTokens)
+	assert.Equal(t, "openai", last.Provider)
+
+	_, err = provider.StreamTranscription(context.Background(), types.AudioRequest{
+		Type:  types.AudioRequestTypeTranslation,
+		Model: "whisper-1",
+		Input: []byte("audio"),
+	})
+	assert.ErrorContains(t, err, "cannot be streamed")
+}
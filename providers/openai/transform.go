@@ -18,6 +18,7 @@ func (p *Provider) buildChatPayload(request *types.TextRequest) map[string]any {
 	if err != nil {
 		prepared = request.Messages // fall through; provider will surface the issue
 	}
+	prepared, _ = p.Config.RequestPolicy.Roles.Apply(prepared)
 	payload := map[string]any{
 		"model":    request.Model,
 		"messages": p.transformMessages(prepared),
@@ -31,6 +32,20 @@ func (p *Provider) buildChatPayload(request *types.TextRequest) map[string]any {
 	// Add tools if present
 	p.addToolsParams(payload, request)
 
+	// Chat Completions has no domain or use limits for search; the options
+	// object only turns it on for search models.
+	if request.WebSearch != nil {
+		payload["web_search_options"] = map[string]any{}
+	}
+
+	if request.Audio != nil {
+		payload["modalities"] = []string{"text", "audio"}
+		payload["audio"] = map[string]any{
+			"voice":  request.Audio.Voice,
+			"format": request.Audio.Format,
+		}
+	}
+
 	// Add response format if specified
 	if request.ResponseFormat != nil {
 		payload["response_format"] = request.ResponseFormat
@@ -51,7 +66,7 @@ func (p *Provider) addGenerationParams(payload map[string]any, request *types.Te
 
 	// OpenAI-specific: adjust max tokens parameter name for GPT-5 models
 	if request.MaxTokens != nil && *request.MaxTokens > 0 {
-		paramName := p.getMaxTokensParam(request.Model)
+		paramName := p.getMaxTokensParam(request.Model, request.MaxTokensParam)
 		maxTokens := p.maxTokensValue(*request.MaxTokens)
 		if paramName != "max_tokens" {
 			// Remove the generic max_tokens added by shared utility
@@ -73,6 +88,37 @@ func (p *Provider) addGenerationParams(payload map[string]any, request *types.Te
 	if request.ParallelToolCalls != nil {
 		payload["parallel_tool_calls"] = *request.ParallelToolCalls
 	}
+	if request.TopK != nil {
+		payload["top_k"] = *request.TopK
+	}
+	if request.MinP != nil {
+		payload["min_p"] = *request.MinP
+	}
+	if request.RepetitionPenalty != nil {
+		payload["repetition_penalty"] = *request.RepetitionPenalty
+	}
+	if len(request.LogitBias) > 0 {
+		payload["logit_bias"] = request.LogitBias
+	}
+	if request.Logprobs {
+		payload["logprobs"] = true
+		if request.TopLogprobs > 0 {
+			payload["top_logprobs"] = request.TopLogprobs
+		}
+	}
+	if request.N > 1 {
+		payload["n"] = request.N
+	}
+}
+
+// validateChatSampling rejects extended sampling controls the endpoint's
+// request policy does not list. Endpoints without a policy accept them all.
+func (p *Provider) validateChatSampling(request types.TextRequest) error {
+	supported := p.Config.RequestPolicy.SamplingParams
+	if supported == nil {
+		return nil
+	}
+	return p.CheckSamplingParams(request.BaseRequest, p.Name(), supported...)
 }
 
 func (p *Provider) addReasoningParams(payload map[string]any, request *types.TextRequest) {
@@ -105,8 +151,10 @@ func (p *Provider) maxTokensValue(value int) int {
 	return value
 }
 
-// getMaxTokensParam returns the appropriate max tokens parameter name for the model
-func (p *Provider) getMaxTokensParam(model string) string {
+// getMaxTokensParam returns the appropriate max tokens parameter name for the
+// model. Endpoint configuration wins over the name the request carries from
+// the model's parameter constraints.
+func (p *Provider) getMaxTokensParam(model, requested string) string {
 	// Check for provider-specific parameter configuration
 	if p.Config.Params != nil {
 		if param, ok := p.Config.Params["max_tokens_param"].(string); ok {
@@ -121,6 +169,9 @@ func (p *Provider) getMaxTokensParam(model string) string {
 	if p.Config.RequestPolicy.MaxTokensParam != "" {
 		return p.Config.RequestPolicy.MaxTokensParam
 	}
+	if requested != "" {
+		return requested
+	}
 	// GPT-5 models require max_completion_tokens instead of deprecated max_tokens
 	if isGPT5Model(model) {
 		return "max_completion_tokens"
@@ -151,6 +202,10 @@ func (p *Provider) transformMessages(messages []types.Message) []map[string]any
 		if userMsg, ok := msg.(*types.UserMessage); ok && len(userMsg.Media) > 0 {
 			openAIMsg["content"] = p.transformUserMessageContent(userMsg)
 		}
+		// A spoken reply is replayed by ID; its transcript is not resent.
+		if assistantMsg, ok := msg.(*types.AssistantMessage); ok && assistantMsg.AudioID != "" {
+			openAIMsg["audio"] = map[string]any{"id": assistantMsg.AudioID}
+		}
 
 		// Transform content if it's multi-modal ([]types.MessagePart)
 		// OpenAI requires specific format for multi-modal content
@@ -200,6 +255,24 @@ func (p *Provider) transformUserMessageContent(msg *types.UserMessage) any {
 					"url": url,
 				},
 			})
+			continue
+		}
+		if doc, ok := media.(*types.DocumentMedia); ok && len(doc.Data) > 0 {
+			parts = append(parts, chatDocumentPart(doc))
+			continue
+		}
+		if audio, ok := media.(*types.AudioMedia); ok {
+			parts = append(parts, map[string]any{
+				"type": "input_audio",
+				"input_audio": map[string]any{
+					"data":   base64.StdEncoding.EncodeToString(audio.Data),
+					"format": audio.Format,
+				},
+			})
+			continue
+		}
+		if part, ok := types.MarshalMedia(types.MediaFormatOpenAI, media); ok {
+			parts = append(parts, part)
 		}
 	}
 
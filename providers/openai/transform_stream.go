@@ -45,6 +45,10 @@ func (p *Provider) parseStreamChunk(data []byte) (*types.TextChunk, error) {
 		chunk.Delta.Thinking = thinking
 	}
 
+	if choice.Logprobs != nil {
+		chunk.Logprobs = choice.Logprobs.Content
+	}
+
 	if len(choice.Delta.ToolCalls) > 0 {
 		chunk.ToolCalls = p.convertToolCalls(choice.Delta.ToolCalls)
 	}
@@ -52,6 +56,7 @@ func (p *Provider) parseStreamChunk(data []byte) (*types.TextChunk, error) {
 	if choice.FinishReason != "" {
 		reason := p.mapFinishReason(choice.FinishReason)
 		chunk.FinishReason = &reason
+		chunk.RawFinishReason = choice.FinishReason
 	}
 
 	if response.Usage != nil {
@@ -1,6 +1,7 @@
 package openai
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -72,11 +73,7 @@ func TestTransformTextResponseWithJSONCleaning(t *testing.T) {
 		ID:      "test-id",
 		Model:   "claude-opus-4.1",
 		Created: time.Now().Unix(),
-		Choices: []struct {
-			Index        int     `json:"index"`
-			Message      message `json:"message"`
-			FinishReason string  `json:"finish_reason"`
-		}{
+		Choices: []chatChoice{
 			{
 				Message: message{
 					Content: "```json\n{\"variations\": [{\"strategy\": \"test\"}]}\n```",
@@ -104,11 +101,7 @@ func TestTransformTextResponseModelAgnosticCleaning(t *testing.T) {
 		ID:      "test-id",
 		Model:   "gpt-4",
 		Created: time.Now().Unix(),
-		Choices: []struct {
-			Index        int     `json:"index"`
-			Message      message `json:"message"`
-			FinishReason string  `json:"finish_reason"`
-		}{
+		Choices: []chatChoice{
 			{
 				Message: message{
 					Content: "```json\n{\"key\": \"value\"}\n```",
@@ -165,11 +158,7 @@ func TestTransformTextResponsePlainTextUnchanged(t *testing.T) {
 		ID:      "test-id",
 		Model:   "gpt-4",
 		Created: time.Now().Unix(),
-		Choices: []struct {
-			Index        int     `json:"index"`
-			Message      message `json:"message"`
-			FinishReason string  `json:"finish_reason"`
-		}{
+		Choices: []chatChoice{
 			{
 				Message: message{
 					Content: "Just plain text, no JSON here.",
@@ -201,6 +190,29 @@ func TestBuildChatPayloadKeepsTextOnlyUserContentString(t *testing.T) {
 	assert.Equal(t, "plain text", messages[0]["content"])
 }
 
+func TestBuildChatPayloadDeveloperRole(t *testing.T) {
+	t.Parallel()
+
+	request := &types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "o3"},
+		Messages: []types.Message{
+			types.NewDeveloperMessage("Answer in French."),
+			types.NewUserMessage("hello"),
+		},
+	}
+	messages := New(types.ProviderConfig{APIKey: "test-key"}).buildChatPayload(request)["messages"].([]map[string]any)
+	assert.Equal(t, "developer", messages[0]["role"])
+	assert.Equal(t, "Answer in French.", messages[0]["content"])
+
+	legacy := New(types.ProviderConfig{APIKey: "test-key", RequestPolicy: types.ProviderRequestPolicy{
+		Roles: types.RoleMap{types.RoleDeveloper: types.RoleSystem},
+	}})
+	messages = legacy.buildChatPayload(request)["messages"].([]map[string]any)
+	assert.Equal(t, "system", messages[0]["role"])
+	input := legacy.buildResponsesPayload(request)["input"].([]map[string]any)
+	assert.Equal(t, "system", input[0]["role"])
+}
+
 func TestBuildChatPayloadSerializesUserMediaAsImageURLParts(t *testing.T) {
 	t.Parallel()
 
@@ -229,6 +241,35 @@ func TestBuildChatPayloadSerializesUserMediaAsImageURLParts(t *testing.T) {
 	assert.Equal(t, map[string]any{"url": "https://example.test/image.jpg"}, parts[2]["image_url"])
 }
 
+type cadMedia struct{ Snippet string }
+
+func (m *cadMedia) GetType() string { return "openai-test-cad" }
+
+func TestBuildChatPayloadSerializesRegisteredCustomMedia(t *testing.T) {
+	t.Parallel()
+	types.RegisterMediaCodec("openai-test-cad", types.MediaCodec{
+		Marshal: func(format string, media types.Media) (map[string]any, bool) {
+			return map[string]any{"type": "text", "text": format + ":" + media.(*cadMedia).Snippet}, true
+		},
+	})
+	t.Cleanup(func() { types.UnregisterMediaCodec("openai-test-cad") })
+
+	provider := New(types.ProviderConfig{APIKey: "test-key"})
+	payload := provider.buildChatPayload(&types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages: []types.Message{
+			&types.UserMessage{
+				Content: "review this part",
+				Media:   []types.Media{&cadMedia{Snippet: "cube(10);"}},
+			},
+		},
+	})
+
+	parts := payload["messages"].([]map[string]any)[0]["content"].([]map[string]any)
+	require.Len(t, parts, 2)
+	assert.Equal(t, map[string]any{"type": "text", "text": "openai:cube(10);"}, parts[1])
+}
+
 func TestTransform_MalformedToolCallArgs_FlaggedNotSwallowed(t *testing.T) {
 	t.Parallel()
 
@@ -239,11 +280,7 @@ func TestTransform_MalformedToolCallArgs_FlaggedNotSwallowed(t *testing.T) {
 		ID:      "malformed-tool-args",
 		Model:   "gpt-4o-mini",
 		Created: time.Now().Unix(),
-		Choices: []struct {
-			Index        int     `json:"index"`
-			Message      message `json:"message"`
-			FinishReason string  `json:"finish_reason"`
-		}{
+		Choices: []chatChoice{
 			{
 				Message: message{
 					Role: "assistant",
@@ -311,3 +348,36 @@ func TestTransformEmbeddingsResponseBackfillsModel(t *testing.T) {
 		assert.Equal(t, "prov-y", result.Model)
 	})
 }
+
+func TestDocumentInputs(t *testing.T) {
+	t.Parallel()
+
+	pdf := types.NewDocument([]byte("%PDF-1.7"), types.MimeTypePDF)
+	request := &types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-mini"},
+		Messages:    []types.Message{&types.UserMessage{Content: "summarize", Media: []types.Media{pdf}}},
+	}
+	provider := New(types.ProviderConfig{APIKey: "test-key"})
+
+	parts := provider.buildChatPayload(request)["messages"].([]map[string]any)[0]["content"].([]map[string]any)
+	require.Len(t, parts, 2)
+	assert.Equal(t, map[string]any{
+		"type": "file",
+		"file": map[string]any{"filename": "document.pdf", "file_data": "data:application/pdf;base64,JVBERi0xLjc="},
+	}, parts[1])
+
+	responseParts := responsesUserMessageContent(&types.UserMessage{Media: []types.Media{
+		&types.DocumentMedia{URL: "https://example.com/a.pdf", MimeType: types.MimeTypePDF},
+	}})
+	assert.Equal(t, []map[string]any{{"type": "input_file", "file_url": "https://example.com/a.pdf"}}, responseParts)
+
+	// Chat Completions cannot fetch URLs, and only PDFs are accepted.
+	_, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: request.BaseRequest,
+		Messages: []types.Message{&types.UserMessage{Content: "x", Media: []types.Media{
+			&types.DocumentMedia{URL: "https://example.com/a.pdf", MimeType: types.MimeTypePDF},
+		}}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported document input")
+}
@@ -1,9 +1,12 @@
 package openai
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"maps"
 	"time"
 
+	"github.com/garyblankenship/wormhole/v2/providers"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
@@ -30,6 +33,32 @@ func (p *Provider) transformTools(tools []types.Tool) []map[string]any {
 	return baseTools
 }
 
+// downgradeSchemas fits tool parameters and a json_schema response format to
+// Config.SchemaSupport, for compatible endpoints that only accept a subset of
+// JSON Schema. Without a profile the request is returned unchanged.
+func (p *Provider) downgradeSchemas(request types.TextRequest) (types.TextRequest, []string) {
+	if p.Config.SchemaSupport == nil {
+		return request, nil
+	}
+	support := *p.Config.SchemaSupport
+	var warnings []string
+	request.Tools, warnings = providers.DowngradeToolSchemas(request.Tools, support)
+	if format, ok := request.ResponseFormat.(map[string]any); ok {
+		if spec, ok := format["json_schema"].(map[string]any); ok {
+			if schema, ok := spec["schema"].(map[string]any); ok {
+				schema, w := types.DowngradeSchema(schema, support)
+				spec = maps.Clone(spec)
+				spec["schema"] = schema
+				format = maps.Clone(format)
+				format["json_schema"] = spec
+				request.ResponseFormat = format
+				warnings = append(warnings, providers.PrefixSchemaWarnings("response schema", w)...)
+			}
+		}
+	}
+	return request, warnings
+}
+
 // cleanJSONResponse removes markdown code blocks from JSON responses
 func cleanJSONResponse(content string) string {
 	return extractJSONFromMarkdown(content)
@@ -45,8 +74,43 @@ func (p *Provider) transformTextResponse(response *chatCompletionResponse) *type
 		}
 	}
 
-	choice := response.Choices[0]
+	first := p.transformChoice(response.Choices[0])
+	resp := &types.TextResponse{
+		ID:              response.ID,
+		Model:           response.Model,
+		Text:            first.Text,
+		Refusal:         first.Refusal,
+		Thinking:        first.Thinking,
+		ToolCalls:       first.ToolCalls,
+		FinishReason:    first.FinishReason,
+		Usage:           p.convertUsage(response.Usage),
+		Logprobs:        first.Logprobs,
+		Created:         time.Unix(response.Created, 0),
+		RawFinishReason: first.RawFinishReason,
+	}
+	if len(response.Choices) > 1 {
+		resp.Choices = make([]types.Choice, len(response.Choices))
+		resp.Choices[0] = first
+		for i, choice := range response.Choices[1:] {
+			resp.Choices[i+1] = p.transformChoice(choice)
+		}
+	}
+
+	resp.Citations = chatCitations(response.Choices[0].Message.Annotations, response.Citations)
+	if len(response.Citations) > 0 {
+		resp.Metadata = map[string]any{"citations": response.Citations}
+	}
+
+	return resp
+}
+
+// transformChoice converts one chat completion choice.
+func (p *Provider) transformChoice(choice chatChoice) types.Choice {
 	content := choice.Message.Content
+	reasoning := choice.Message.ReasoningContent
+	if reasoning == "" && p.splitsThinkTags() {
+		reasoning, content = splitThinkTags(content)
+	}
 
 	// Strip markdown code fences from JSON responses regardless of model.
 	// cleanJSONResponse is a no-op when there are no backticks and only
@@ -54,22 +118,43 @@ func (p *Provider) transformTextResponse(response *chatCompletionResponse) *type
 	// safe for every provider/model and avoids brittle model-name sniffing.
 	content = cleanJSONResponse(content)
 
-	resp := &types.TextResponse{
-		ID:           response.ID,
-		Model:        response.Model,
-		Text:         content,
-		Refusal:      choice.Message.Refusal,
-		ToolCalls:    p.convertToolCalls(choice.Message.ToolCalls),
-		FinishReason: p.mapFinishReason(choice.FinishReason),
-		Usage:        p.convertUsage(response.Usage),
-		Created:      time.Unix(response.Created, 0),
+	out := types.Choice{
+		Index:           choice.Index,
+		Text:            content,
+		Refusal:         choice.Message.Refusal,
+		ToolCalls:       p.convertToolCalls(choice.Message.ToolCalls),
+		FinishReason:    p.mapFinishReason(choice.FinishReason),
+		RawFinishReason: choice.FinishReason,
 	}
-
-	if choice.Message.ReasoningContent != "" {
-		resp.Thinking = &types.Thinking{Content: choice.Message.ReasoningContent}
+	if reasoning != "" {
+		out.Thinking = &types.Thinking{Content: reasoning}
+	}
+	if choice.Logprobs != nil {
+		out.Logprobs = choice.Logprobs.Content
 	}
+	return out
+}
 
-	return resp
+// chatCitations collects url_citation annotations, then any xAI Live Search
+// source URLs, which carry no title or span.
+func chatCitations(annotations []annotation, sources []string) []types.Citation {
+	var citations []types.Citation
+	for _, a := range annotations {
+		if a.Type != "url_citation" {
+			continue
+		}
+		citations = append(citations, types.Citation{
+			Type:       types.CitationTypeURL,
+			URL:        a.URLCitation.URL,
+			Title:      a.URLCitation.Title,
+			StartIndex: a.URLCitation.StartIndex,
+			EndIndex:   a.URLCitation.EndIndex,
+		})
+	}
+	for _, url := range sources {
+		citations = append(citations, types.Citation{Type: types.CitationTypeURL, URL: url})
+	}
+	return citations
 }
 
 // transformEmbeddingsResponse converts OpenAI embeddings response
@@ -102,19 +187,29 @@ func (p *Provider) transformEmbeddingsResponse(response *embeddingsResponse, req
 }
 
 // transformRerankResponse converts an OpenAI-compatible rerank response.
-func (p *Provider) transformRerankResponse(response *rerankResponse, requestModel string) *types.RerankResponse {
-	results := make([]types.RerankResult, len(response.Results))
-	for i, r := range response.Results {
+func (p *Provider) transformRerankResponse(response *rerankResponse, request types.RerankRequest) *types.RerankResponse {
+	raw := response.Results
+	if len(raw) == 0 {
+		raw = response.Data
+	}
+	results := make([]types.RerankResult, len(raw))
+	for i, r := range raw {
+		document := r.Document.Text
+		// Cohere and Voyage omit document text by default; echo it back from
+		// the request so results look the same on every provider.
+		if document == "" && r.Index >= 0 && r.Index < len(request.Documents) {
+			document = request.Documents[r.Index]
+		}
 		results[i] = types.RerankResult{
 			Index:          r.Index,
 			RelevanceScore: r.RelevanceScore,
-			Document:       r.Document.Text,
+			Document:       document,
 		}
 	}
 
 	model := response.Model
 	if model == "" {
-		model = requestModel
+		model = request.Model
 	}
 
 	return &types.RerankResponse{
@@ -142,3 +237,24 @@ func (p *Provider) transformImageResponse(response *imageResponse) *types.Images
 		Created: time.Unix(response.Created, 0),
 	}
 }
+
+// responseAudio decodes a spoken reply. The API does not echo the format, so
+// it is taken from the request.
+func (p *Provider) responseAudio(audio *messageAudio, output *types.AudioOutput) (*types.ResponseAudio, error) {
+	data, err := base64.StdEncoding.DecodeString(audio.Data)
+	if err != nil {
+		return nil, p.RequestError("failed to decode response audio", err)
+	}
+	result := &types.ResponseAudio{
+		ID:         audio.ID,
+		Data:       data,
+		Transcript: audio.Transcript,
+	}
+	if output != nil {
+		result.Format = output.Format
+	}
+	if audio.ExpiresAt > 0 {
+		result.ExpiresAt = time.Unix(audio.ExpiresAt, 0)
+	}
+	return result, nil
+}
@@ -1,20 +1,36 @@
 package openai
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
 
 // OpenAI API response types
 
 type chatCompletionResponse struct {
-	ID      string `json:"id"`
-	Object  string `json:"object"`
-	Created int64  `json:"created"`
-	Model   string `json:"model"`
-	Choices []struct {
-		Index        int     `json:"index"`
-		Message      message `json:"message"`
-		FinishReason string  `json:"finish_reason"`
-	} `json:"choices"`
-	Usage usage `json:"usage"`
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   usage        `json:"usage"`
+	// Citations lists Live Search sources (xAI).
+	Citations []string `json:"citations,omitempty"`
+	// RequestID identifies a deferred completion (xAI "deferred": true).
+	RequestID string `json:"request_id,omitempty"`
+}
+
+type chatChoice struct {
+	Index        int             `json:"index"`
+	Message      message         `json:"message"`
+	FinishReason string          `json:"finish_reason"`
+	Logprobs     *choiceLogprobs `json:"logprobs,omitempty"`
+}
+
+// choiceLogprobs carries per-token log probabilities for a choice's content.
+type choiceLogprobs struct {
+	Content []types.TokenLogprob `json:"content"`
 }
 
 type message struct {
@@ -23,6 +39,31 @@ type message struct {
 	Refusal          string     `json:"refusal,omitempty"`
 	ReasoningContent string     `json:"reasoning_content,omitempty"`
 	ToolCalls        []toolCall `json:"tool_calls,omitempty"`
+	// Annotations carries url_citation entries from web search.
+	Annotations []annotation `json:"annotations,omitempty"`
+	// Audio is the spoken reply when the request asked for audio output.
+	Audio *messageAudio `json:"audio,omitempty"`
+}
+
+// messageAudio is a spoken reply: base64 audio in the requested format and
+// its transcript, referenced by ID in later turns until ExpiresAt.
+type messageAudio struct {
+	ID         string `json:"id"`
+	Data       string `json:"data"`
+	Transcript string `json:"transcript"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
+// annotation is a Chat Completions message annotation. Only url_citation is
+// defined; the offsets index into the message content.
+type annotation struct {
+	Type        string `json:"type"`
+	URLCitation struct {
+		URL        string `json:"url"`
+		Title      string `json:"title"`
+		StartIndex int    `json:"start_index"`
+		EndIndex   int    `json:"end_index"`
+	} `json:"url_citation"`
 }
 
 type toolCall struct {
@@ -101,9 +142,25 @@ func (i *responsesOutputItem) UnmarshalJSON(data []byte) error {
 }
 
 type responsesContentPart struct {
-	Type    string `json:"type"`
-	Text    string `json:"text,omitempty"`
-	Refusal string `json:"refusal,omitempty"`
+	Type        string                `json:"type"`
+	Text        string                `json:"text,omitempty"`
+	Refusal     string                `json:"refusal,omitempty"`
+	Logprobs    []types.TokenLogprob  `json:"logprobs,omitempty"`
+	Annotations []responsesAnnotation `json:"annotations,omitempty"`
+}
+
+// responsesAnnotation is an output_text annotation: url_citation from the
+// web_search tool, file_citation from file_search, or
+// container_file_citation from code interpreter.
+type responsesAnnotation struct {
+	Type       string `json:"type"`
+	URL        string `json:"url,omitempty"`
+	Title      string `json:"title,omitempty"`
+	FileID     string `json:"file_id,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	Index      int    `json:"index"`
+	StartIndex int    `json:"start_index"`
+	EndIndex   int    `json:"end_index"`
 }
 
 type responsesUsage struct {
@@ -116,6 +173,7 @@ type responsesStreamEvent struct {
 	Type     string               `json:"type"`
 	Response *responsesResponse   `json:"response,omitempty"`
 	Delta    string               `json:"delta,omitempty"`
+	Logprobs []types.TokenLogprob `json:"logprobs,omitempty"`
 	ItemID   string               `json:"item_id,omitempty"`
 	Item     *responsesOutputItem `json:"item,omitempty"`
 }
@@ -128,9 +186,10 @@ func (e responsesStreamEvent) responseModel() string {
 }
 
 type streamChoice struct {
-	Index        int          `json:"index"`
-	Delta        messageDelta `json:"delta"`
-	FinishReason string       `json:"finish_reason,omitempty"`
+	Index        int             `json:"index"`
+	Delta        messageDelta    `json:"delta"`
+	FinishReason string          `json:"finish_reason,omitempty"`
+	Logprobs     *choiceLogprobs `json:"logprobs,omitempty"`
 }
 
 type messageDelta struct {
@@ -162,17 +221,12 @@ type embeddingsResponse struct {
 }
 
 type rerankResponse struct {
-	ID       string `json:"id"`
-	Provider string `json:"provider,omitempty"`
-	Model    string `json:"model"`
-	Results  []struct {
-		Index          int     `json:"index"`
-		RelevanceScore float64 `json:"relevance_score"`
-		Document       struct {
-			Text  string `json:"text,omitempty"`
-			Image string `json:"image,omitempty"`
-		} `json:"document"`
-	} `json:"results"`
+	ID       string         `json:"id"`
+	Provider string         `json:"provider,omitempty"`
+	Model    string         `json:"model"`
+	Results  []rerankResult `json:"results"`
+	// Data carries the results on APIs that use a list envelope (Voyage AI).
+	Data  []rerankResult `json:"data,omitempty"`
 	Usage struct {
 		SearchUnits int     `json:"search_units"`
 		TotalTokens int     `json:"total_tokens"`
@@ -180,6 +234,15 @@ type rerankResponse struct {
 	} `json:"usage"`
 }
 
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+	Document       struct {
+		Text  string `json:"text,omitempty"`
+		Image string `json:"image,omitempty"`
+	} `json:"document"`
+}
+
 type imageResponse struct {
 	Created int64 `json:"created"`
 	Data    []struct {
@@ -187,3 +250,13 @@ type imageResponse struct {
 		B64JSON string `json:"b64_json,omitempty"`
 	} `json:"data"`
 }
+
+type moderationResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		Categories     map[string]bool    `json:"categories"`
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
@@ -0,0 +1,89 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestChatWebSearchOptionsAndURLCitations(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, map[string]any{}, payload["web_search_options"])
+		assert.NotContains(t, payload, "tools")
+		_, _ = w.Write([]byte(`{"id":"c1","model":"gpt-4o-search-preview","choices":[{"message":{"role":"assistant","content":"Go 1.25 shipped in August.","annotations":[{"type":"url_citation","url_citation":{"url":"https://go.dev/doc/go1.25","title":"Go 1.25 Release Notes","start_index":0,"end_index":26}}]},"finish_reason":"stop"}]}`))
+	})
+
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-4o-search-preview"},
+		Messages:    []types.Message{types.NewUserMessage("When did Go 1.25 ship?")},
+		WebSearch:   &types.WebSearch{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []types.Citation{{
+		Type:     types.CitationTypeURL,
+		URL:      "https://go.dev/doc/go1.25",
+		Title:    "Go 1.25 Release Notes",
+		EndIndex: 26,
+	}}, resp.Citations)
+}
+
+func TestResponsesWebSearchToolAndCitations(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		tools := payload["tools"].([]any)
+		require.Len(t, tools, 2)
+		assert.Equal(t, "function", tools[0].(map[string]any)["type"])
+		assert.Equal(t, map[string]any{
+			"type":    "web_search",
+			"filters": map[string]any{"allowed_domains": []any{"go.dev"}},
+		}, tools[1])
+		_, _ = w.Write([]byte(`{"id":"resp-1","model":"gpt-5","status":"completed","output":[
+			{"id":"ws-1","type":"web_search_call","status":"completed"},
+			{"id":"msg-1","type":"message","role":"assistant","content":[{"type":"output_text","text":"Go 1.25 shipped.","annotations":[{"type":"url_citation","url":"https://go.dev/doc/go1.25","title":"Go 1.25","start_index":0,"end_index":16}]}]}
+		]}`))
+	})
+	provider.Config.UseResponsesAPI = true
+
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-5"},
+		Messages:    []types.Message{types.NewUserMessage("When did Go 1.25 ship?")},
+		Tools:       []types.Tool{*types.NewTool("lookup", "Look up a release", map[string]any{"type": "object"})},
+		WebSearch:   &types.WebSearch{AllowedDomains: []string{"go.dev"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Go 1.25 shipped.", resp.Text)
+	assert.Equal(t, []types.Citation{{Type: types.CitationTypeURL, URL: "https://go.dev/doc/go1.25", Title: "Go 1.25", EndIndex: 16}}, resp.Citations)
+}
+
+func TestResponsesFileCitations(t *testing.T) {
+	t.Parallel()
+
+	out := (&Provider{}).transformResponsesTextResponse(&responsesResponse{
+		Output: []responsesOutputItem{{
+			Type: responsesItemMessage,
+			Content: []responsesContentPart{{
+				Type: responsesContentOutputText,
+				Text: "The handbook allows remote work.",
+				Annotations: []responsesAnnotation{
+					{Type: "file_citation", FileID: "file-123", Filename: "handbook.pdf", Index: 31},
+					{Type: "container_file_citation", FileID: "cfile-9", Filename: "chart.png", StartIndex: 4, EndIndex: 12},
+				},
+			}},
+		}},
+	})
+	assert.Equal(t, []types.Citation{
+		{Type: types.CitationTypeFile, FileID: "file-123", Title: "handbook.pdf", StartIndex: 31, EndIndex: 31},
+		{Type: types.CitationTypeFile, FileID: "cfile-9", Title: "chart.png", StartIndex: 4, EndIndex: 12},
+	}, out.Citations)
+}
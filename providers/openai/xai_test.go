@@ -0,0 +1,86 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestXAILiveSearchPayloadAndCitations(t *testing.T) {
+	t.Parallel()
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		search, ok := payload["search_parameters"].(map[string]any)
+		require.True(t, ok, "search_parameters = %#v", payload["search_parameters"])
+		assert.Equal(t, "on", search["mode"])
+		assert.Equal(t, true, search["return_citations"])
+		sources := search["sources"].([]any)
+		assert.Equal(t, "x", sources[0].(map[string]any)["type"])
+		_, _ = w.Write([]byte(`{"id":"c1","model":"grok-4","choices":[{"message":{"role":"assistant","content":"Go 1.25 shipped."},"finish_reason":"stop"}],"citations":["https://go.dev/doc/go1.25"]}`))
+	})
+
+	resp, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{
+			Model: "grok-4",
+			ProviderOptions: types.XAIOptions{Search: &types.XAISearch{
+				Mode:            "on",
+				ReturnCitations: true,
+				Sources:         []types.XAISearchSource{{Type: "x", IncludedXHandles: []string{"golang"}}},
+			}}.ProviderOptions(),
+		},
+		Messages: []types.Message{types.NewUserMessage("What changed in Go 1.25?")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Go 1.25 shipped.", resp.Text)
+	assert.Equal(t, []string{"https://go.dev/doc/go1.25"}, resp.Metadata["citations"])
+	assert.Equal(t, []types.Citation{{Type: types.CitationTypeURL, URL: "https://go.dev/doc/go1.25"}}, resp.Citations)
+}
+
+func TestXAIDeferredCompletion(t *testing.T) {
+	t.Parallel()
+	var polls atomic.Int32
+	provider, _ := newOpenAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/chat/completions":
+			var payload map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, true, payload["deferred"])
+			_, _ = w.Write([]byte(`{"request_id":"req-1"}`))
+		case "/chat/deferred-completion/req-1":
+			assert.Equal(t, http.MethodGet, r.Method)
+			if polls.Add(1) == 1 {
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"req-1","model":"grok-4","choices":[{"message":{"role":"assistant","content":"done"},"finish_reason":"stop"}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	queued, err := provider.Text(context.Background(), types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "grok-4", ProviderOptions: types.XAIOptions{Deferred: true}.ProviderOptions()},
+		Messages:    []types.Message{types.NewUserMessage("Write a long report")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", queued.ID)
+	assert.Equal(t, true, queued.Metadata["deferred"])
+
+	_, err = provider.DeferredCompletion(context.Background(), queued.ID)
+	require.ErrorIs(t, err, types.ErrDeferredPending)
+
+	resp, err := provider.DeferredCompletion(context.Background(), queued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "done", resp.Text)
+
+	_, err = provider.DeferredCompletion(context.Background(), "")
+	assert.Error(t, err)
+}
@@ -73,6 +73,11 @@ func (b *RequestBuilder) TransformMessage(msg any) map[string]any {
 			"role":    "system",
 			"content": m.Content,
 		}
+	case *types.DeveloperMessage:
+		return map[string]any{
+			"role":    "developer",
+			"content": m.Content,
+		}
 	case *types.ToolMessage:
 		return map[string]any{
 			"role":         "tool",
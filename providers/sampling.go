@@ -0,0 +1,29 @@
+package providers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// CheckSamplingParams rejects a request that sets extended sampling controls
+// outside supported, naming each one. api names the endpoint in the error,
+// e.g. "Anthropic" or "the OpenAI Responses API".
+func (p *BaseProvider) CheckSamplingParams(request types.BaseRequest, api string, supported ...types.SamplingParam) error {
+	var unsupported []string
+	for _, param := range request.SamplingParams() {
+		if !slices.Contains(supported, param) {
+			unsupported = append(unsupported, string(param))
+		}
+	}
+	switch len(unsupported) {
+	case 0:
+		return nil
+	case 1:
+		return p.ValidationError(fmt.Sprintf("%s is not supported by %s", unsupported[0], api))
+	default:
+		return p.ValidationError(fmt.Sprintf("%s are not supported by %s", strings.Join(unsupported, ", "), api))
+	}
+}
@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// SchemaWarningsKey is the response Metadata key holding the warnings produced
+// when a request schema was downgraded to fit the provider.
+const SchemaWarningsKey = "schema_warnings"
+
+// DowngradeToolSchemas returns copies of tools whose parameter schemas fit
+// support, plus warnings prefixed with the tool name. Tools without schemas
+// are returned unchanged.
+func DowngradeToolSchemas(tools []types.Tool, support types.SchemaSupport) ([]types.Tool, []string) {
+	if len(tools) == 0 {
+		return tools, nil
+	}
+	var warnings []string
+	out := make([]types.Tool, len(tools))
+	for i, tool := range tools {
+		if tool.InputSchema != nil {
+			var w []string
+			tool.InputSchema, w = types.DowngradeSchema(tool.InputSchema, support)
+			warnings = append(warnings, PrefixSchemaWarnings("tool "+tool.Name, w)...)
+		}
+		if tool.Function != nil && tool.Function.Parameters != nil {
+			function := *tool.Function
+			function.Parameters, _ = types.DowngradeSchema(function.Parameters, support)
+			tool.Function = &function
+		}
+		out[i] = tool
+	}
+	return out, warnings
+}
+
+// PrefixSchemaWarnings prefixes each warning with the schema it came from.
+func PrefixSchemaWarnings(prefix string, warnings []string) []string {
+	out := make([]string, len(warnings))
+	for i, warning := range warnings {
+		out[i] = prefix + " " + warning
+	}
+	return out
+}
+
+// AddSchemaWarnings records warnings under metadata[SchemaWarningsKey],
+// allocating metadata when needed. It returns metadata unchanged when there
+// are no warnings.
+func AddSchemaWarnings(metadata map[string]any, warnings []string) map[string]any {
+	if len(warnings) == 0 {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]any, 1)
+	}
+	metadata[SchemaWarningsKey] = warnings
+	return metadata
+}
@@ -73,6 +73,8 @@ func prepareMessageCopies(messages []types.Message) ([]types.Message, map[string
 			message.Content = strings.ToValidUTF8(message.Content, "")
 		case *types.SystemMessage:
 			message.Content = strings.ToValidUTF8(message.Content, "")
+		case *types.DeveloperMessage:
+			message.Content = strings.ToValidUTF8(message.Content, "")
 		}
 	}
 	return prepared, normalizedIDs, nil
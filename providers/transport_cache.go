@@ -15,9 +15,11 @@ type cachedTransport struct {
 	lastUsedNano atomic.Int64
 }
 
-// TransportCache is an instance-scoped, bounded LRU cache of *http.Transport
-// keyed by transport-config fingerprint. Each HTTPClientWrapper owns one, so
-// transports are NOT shared across wrapper instances or differing TLS configs.
+// TransportCache is a bounded LRU cache of *http.Transport keyed by base URL
+// host plus transport-config fingerprint. HTTPClientWrapper uses the shared
+// process-wide cache, so providers pointed at the same host with the same TLS
+// and pool settings reuse one connection pool. Separate caches built with
+// NewTransportCache never share transports with each other.
 type TransportCache struct {
 	mu         sync.RWMutex
 	transports map[string]*cachedTransport
@@ -25,6 +27,15 @@ type TransportCache struct {
 	misses     atomic.Int64
 }
 
+// sharedTransports backs every HTTPClientWrapper that builds its own client.
+var sharedTransports = NewTransportCache()
+
+// SharedTransportCacheMetrics reports hit/miss statistics for the transport
+// cache shared by all providers.
+func SharedTransportCacheMetrics() TransportCacheMetrics {
+	return sharedTransports.Metrics()
+}
+
 // NewTransportCache returns an empty, ready-to-use TransportCache.
 func NewTransportCache() *TransportCache {
 	return &TransportCache{
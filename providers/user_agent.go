@@ -0,0 +1,67 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+const modulePath = "github.com/garyblankenship/wormhole/v2"
+
+var (
+	defaultUserAgent     string
+	defaultUserAgentOnce sync.Once
+)
+
+// DefaultUserAgent returns the User-Agent Wormhole sends when a provider does
+// not configure one, e.g. "wormhole/v2.3.0 (go1.24.1; linux/amd64)". The
+// version comes from the build info of the binary; local builds report "dev".
+func DefaultUserAgent() string {
+	defaultUserAgentOnce.Do(func() {
+		defaultUserAgent = fmt.Sprintf("wormhole/%s (%s; %s/%s)",
+			moduleVersion(), runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	})
+	return defaultUserAgent
+}
+
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	version := ""
+	if info.Main.Path == modulePath {
+		version = info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			version = dep.Version
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				version = dep.Replace.Version
+			}
+		}
+	}
+	if version == "" || version == "(devel)" {
+		return "dev"
+	}
+	return version
+}
+
+// SetUserAgent applies config's User-Agent policy to req: the configured
+// UserAgent, DefaultUserAgent, or no header at all when DisableUserAgent is
+// set. Callers apply Config.Headers afterwards so an explicit header wins.
+func SetUserAgent(req *http.Request, config types.ProviderConfig) {
+	switch {
+	case config.DisableUserAgent:
+		// An empty value stops net/http from adding its Go-http-client default.
+		req.Header[types.HeaderUserAgent] = []string{""}
+	case config.UserAgent != "":
+		req.Header.Set(types.HeaderUserAgent, config.UserAgent)
+	default:
+		req.Header.Set(types.HeaderUserAgent, DefaultUserAgent())
+	}
+}
@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestDefaultUserAgent(t *testing.T) {
+	t.Parallel()
+	assert.Regexp(t, regexp.MustCompile(`^wormhole/\S+ \(go\S+; \w+/\w+\)$`), DefaultUserAgent())
+}
+
+func TestHTTPClientWrapperUserAgent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config types.ProviderConfig
+		want   string
+	}{
+		{name: "default", want: DefaultUserAgent()},
+		{name: "custom", config: types.ProviderConfig{}.WithUserAgent("acme-bot/1.0"), want: "acme-bot/1.0"},
+		{name: "disabled", config: types.ProviderConfig{DisableUserAgent: true}, want: ""},
+		{
+			name:   "header wins",
+			config: types.ProviderConfig{UserAgent: "acme-bot/1.0"}.WithHeader("User-Agent", "gateway/2"),
+			want:   "gateway/2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Values(types.HeaderUserAgent)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			t.Cleanup(server.Close)
+
+			wrapper := NewHTTPClientWrapper("test", tt.config, nil, &NoAuthStrategy{}, server.Client())
+			var out map[string]any
+			require.NoError(t, wrapper.DoRequest(context.Background(), http.MethodPost, server.URL, map[string]string{}, &out))
+			if tt.want == "" {
+				assert.Empty(t, got, "no User-Agent header, not even Go's default")
+				return
+			}
+			assert.Equal(t, []string{tt.want}, got)
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 const (
@@ -29,6 +31,9 @@ func executeTrackedRequest[T any](ctx context.Context, p *Wormhole, operation st
 	}
 	defer p.untrackRequest()
 
+	if p.sendsIdempotencyHeaders() && types.IdempotencyKeyFromContext(ctx) == "" {
+		ctx = types.ContextWithIdempotencyKey(ctx, requestFingerprint(request))
+	}
 	if !p.hasIdempotency() {
 		return fn(ctx)
 	}
@@ -103,20 +108,42 @@ func (p *Wormhole) idempotencyTTL() time.Duration {
 	return p.config.Idempotency.TTL
 }
 
+// sendsIdempotencyHeaders reports whether any provider forwards idempotency
+// keys upstream, so requests that would never use one skip the hashing.
+func (p *Wormhole) sendsIdempotencyHeaders() bool {
+	for _, config := range p.config.Providers {
+		if config.IdempotencyHeader != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Wormhole) idempotencyCacheKey(operation string, request any) (string, bool) {
 	if !p.hasIdempotency() {
 		return "", false
 	}
+	fingerprint := requestFingerprint(request)
+	if fingerprint == "" {
+		return "", false
+	}
+	return p.config.Idempotency.Key + ":" + operation + ":" + fingerprint, true
+}
+
+// requestFingerprint returns the request's Fingerprint when it has one.
+// Other requests hash their JSON plus ProviderOptions, which carries
+// json:"-" and would otherwise let requests differing only in
+// provider-specific options collide.
+func requestFingerprint(request any) string {
+	if fp, ok := request.(interface{ Fingerprint() string }); ok {
+		return fp.Fingerprint()
+	}
 	payload, err := json.Marshal(request)
 	if err != nil {
-		return "", false
+		return ""
 	}
 	h := sha256.New()
 	h.Write(payload)
-	// ProviderOptions carries json:"-" so json.Marshal(request) above excludes it;
-	// fold it in separately so requests differing only in provider-specific options
-	// don't collide on the same idempotency key. Mirrors DefaultCacheKeyGenerator
-	// (middleware/cache.go).
 	if po, ok := request.(interface{ GetProviderOptions() map[string]any }); ok {
 		if opts := po.GetProviderOptions(); len(opts) > 0 {
 			if ob, err := json.Marshal(opts); err == nil {
@@ -124,7 +151,7 @@ func (p *Wormhole) idempotencyCacheKey(operation string, request any) (string, b
 			}
 		}
 	}
-	return p.config.Idempotency.Key + ":" + operation + ":" + hex.EncodeToString(h.Sum(nil)), true
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 func (p *Wormhole) loadOrCreateIdempotencyEntry(cacheKey string, now time.Time, ttl time.Duration) (*idempotencyEntry, bool) {
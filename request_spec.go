@@ -0,0 +1,344 @@
+package wormhole
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// RequestSpecVersion is the RequestSpec format this package writes. FromSpec
+// rejects specs written by a newer version.
+const RequestSpecVersion = 1
+
+// RequestKind names the builder a RequestSpec restores.
+type RequestKind string
+
+const (
+	RequestKindText       RequestKind = "text"
+	RequestKindStructured RequestKind = "structured"
+)
+
+// RequestSpec is the declarative form of a configured text or structured
+// request: everything needed to rebuild the builder, so requests can be
+// stored, queued, audited and replayed. Write one with ToSpec and rebuild the
+// builder with FromSpec or StructuredFromSpec.
+//
+// Unlike ToJSON, which renders the request's wire-like shape, a spec keeps the
+// system prompt, every message field, media, tool definitions, provider
+// options, routing and builder settings. It holds no functions: tool handlers
+// stay registered on the client, and middleware and experiments are not part
+// of a spec.
+//
+// JSON is the canonical encoding. Like FileConfig, the spec carries no YAML
+// decoder of its own; convert YAML to JSON with the library of your choice
+// and pass the result to ParseRequestSpec.
+type RequestSpec struct {
+	Version int         `json:"version"`
+	Kind    RequestKind `json:"kind"`
+
+	Provider string `json:"provider,omitempty"`
+	BaseURL  string `json:"base_url,omitempty"`
+
+	// BaseRequest holds the model and sampling parameters.
+	types.BaseRequest
+	ProviderOptions       map[string]any            `json:"provider_options,omitempty"`
+	ScopedProviderOptions map[string]map[string]any `json:"scoped_provider_options,omitempty"`
+
+	SystemPrompt string                `json:"system_prompt,omitempty"`
+	Messages     []types.MessageRecord `json:"messages,omitempty"`
+
+	Tags     map[string]string `json:"tags,omitempty"`
+	Priority *Priority         `json:"priority,omitempty"`
+	Cache    *RequestSpecCache `json:"cache,omitempty"`
+
+	// Text requests.
+	Tools             []types.Tool           `json:"tools,omitempty"`
+	ToolChoice        *types.ToolChoice      `json:"tool_choice,omitempty"`
+	ToolExecution     *bool                  `json:"tool_execution,omitempty"`
+	MaxToolIterations int                    `json:"max_tool_iterations,omitempty"`
+	ResponseFormat    any                    `json:"response_format,omitempty"`
+	Logprobs          bool                   `json:"logprobs,omitempty"`
+	TopLogprobs       int                    `json:"top_logprobs,omitempty"`
+	WebSearch         *types.WebSearch       `json:"web_search,omitempty"`
+	Audio             *types.AudioOutput     `json:"audio,omitempty"`
+	N                 int                    `json:"n,omitempty"`
+	FallbackModels    []string               `json:"fallback_models,omitempty"`
+	ProviderFallbacks []TextRoute            `json:"provider_fallbacks,omitempty"`
+	Documents         []*types.DocumentMedia `json:"documents,omitempty"`
+	CiteDocuments     bool                   `json:"cite_documents,omitempty"`
+	PreferWarm        bool                   `json:"prefer_warm,omitempty"`
+	AutoContinue      int                    `json:"auto_continue,omitempty"`
+
+	// Structured requests.
+	Schema               json.RawMessage      `json:"schema,omitempty"`
+	SchemaName           string               `json:"schema_name,omitempty"`
+	Mode                 types.StructuredMode `json:"mode,omitempty"`
+	RepairAttempts       int                  `json:"repair_attempts,omitempty"`
+	SkipSchemaValidation bool                 `json:"skip_schema_validation,omitempty"`
+}
+
+// RequestSpecCache is the per-request cache policy set by Cache or NoCache.
+type RequestSpecCache struct {
+	TTL    Duration `json:"ttl,omitempty"`
+	Bypass bool     `json:"bypass,omitempty"`
+}
+
+// ParseRequestSpec decodes a JSON request spec. Unknown fields are rejected,
+// so a spec from a newer version or with a typo fails instead of replaying a
+// different request.
+func ParseRequestSpec(data []byte) (*RequestSpec, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var spec RequestSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("request spec: %w", err)
+	}
+	return &spec, nil
+}
+
+// ToSpec returns the builder's declarative form. It fails when the builder
+// holds a deferred error, such as an unreadable Document or an unknown
+// UsePrompt reference, that Generate would return.
+//
+// Example:
+//
+//	spec, err := client.Text().Model("gpt-5-mini").SystemPrompt(policy).Prompt(ticket).ToSpec()
+//	data, _ := json.Marshal(spec)
+//	// ... store or enqueue data, then later:
+//	spec, err = wormhole.ParseRequestSpec(data)
+//	builder, err := client.FromSpec(spec)
+//	resp, err := builder.Generate(ctx)
+func (b *TextRequestBuilder) ToSpec() (*RequestSpec, error) {
+	if b.documentErr != nil {
+		return nil, b.documentErr
+	}
+	spec, err := b.commonSpec(RequestKindText, b.request.BaseRequest, b.request.Messages)
+	if err != nil {
+		return nil, err
+	}
+	request := cloneTextRequest(b.request)
+	spec.SystemPrompt = request.SystemPrompt
+	spec.Tools = request.Tools
+	spec.ToolChoice = request.ToolChoice
+	spec.ToolExecution = cloneBoolPtr(b.toolExecutionOverride)
+	spec.MaxToolIterations = b.maxToolIterations
+	spec.ResponseFormat = request.ResponseFormat
+	spec.Logprobs = request.Logprobs
+	spec.TopLogprobs = request.TopLogprobs
+	spec.WebSearch = request.WebSearch
+	spec.Audio = request.Audio
+	spec.N = request.N
+	spec.FallbackModels = append([]string(nil), b.fallbackModels...)
+	spec.ProviderFallbacks = append([]TextRoute(nil), b.providerFallbacks...)
+	for _, document := range b.documents {
+		copied := *document
+		spec.Documents = append(spec.Documents, &copied)
+	}
+	spec.CiteDocuments = b.citeDocuments
+	spec.PreferWarm = b.preferWarm
+	spec.AutoContinue = b.autoContinueRounds
+	return spec, nil
+}
+
+// ToSpec returns the builder's declarative form. It fails when the builder
+// holds a deferred error, such as a schema that could not be marshaled.
+func (b *StructuredRequestBuilder) ToSpec() (*RequestSpec, error) {
+	if b.schemaErr != nil {
+		return nil, b.schemaErr
+	}
+	spec, err := b.commonSpec(RequestKindStructured, b.request.BaseRequest, b.request.Messages)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := structuredSchemaJSON(b.request.Schema)
+	if err != nil {
+		return nil, err
+	}
+	spec.SystemPrompt = b.request.SystemPrompt
+	spec.Schema = schema
+	spec.SchemaName = b.request.SchemaName
+	spec.Mode = b.request.Mode
+	spec.RepairAttempts = b.repairAttempts
+	spec.SkipSchemaValidation = b.skipSchemaCheck
+	return spec, nil
+}
+
+// FromSpec rebuilds a text request builder from spec. The builder runs on
+// this client, so the spec's provider must be registered here.
+func (p *Wormhole) FromSpec(spec *RequestSpec) (*TextRequestBuilder, error) {
+	if err := checkSpec(spec, RequestKindText); err != nil {
+		return nil, err
+	}
+	messages, err := types.RestoreMessages(spec.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("request spec: %w", err)
+	}
+	b := p.Text()
+	b.applyCommonSpec(spec)
+	b.request.BaseRequest = specBaseRequest(spec)
+	b.request.Messages = messages
+	b.request.SystemPrompt = spec.SystemPrompt
+	b.request.Tools = types.CloneTools(spec.Tools)
+	if spec.ToolChoice != nil {
+		choice := *spec.ToolChoice
+		b.request.ToolChoice = &choice
+	}
+	b.request.ResponseFormat = types.CloneValue(spec.ResponseFormat)
+	b.request.Logprobs = spec.Logprobs
+	b.request.TopLogprobs = spec.TopLogprobs
+	b.request.N = spec.N
+	b.request.WebSearch = spec.WebSearch.Clone()
+	b.request.Audio = spec.Audio.Clone()
+	b.toolExecutionOverride = cloneBoolPtr(spec.ToolExecution)
+	b.maxToolIterations = spec.MaxToolIterations
+	b.fallbackModels = append([]string(nil), spec.FallbackModels...)
+	b.providerFallbacks = append([]TextRoute(nil), spec.ProviderFallbacks...)
+	for _, document := range spec.Documents {
+		if document != nil {
+			copied := *document
+			b.documents = append(b.documents, &copied)
+		}
+	}
+	b.citeDocuments = spec.CiteDocuments
+	b.preferWarm = spec.PreferWarm
+	b.autoContinueRounds = spec.AutoContinue
+	return b, nil
+}
+
+// StructuredFromSpec rebuilds a structured request builder from spec.
+func (p *Wormhole) StructuredFromSpec(spec *RequestSpec) (*StructuredRequestBuilder, error) {
+	if err := checkSpec(spec, RequestKindStructured); err != nil {
+		return nil, err
+	}
+	messages, err := types.RestoreMessages(spec.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("request spec: %w", err)
+	}
+	b := p.Structured()
+	b.applyCommonSpec(spec)
+	b.request.BaseRequest = specBaseRequest(spec)
+	b.request.Messages = messages
+	b.request.SystemPrompt = spec.SystemPrompt
+	if len(spec.Schema) > 0 {
+		b.request.Schema = []byte(bytes.Clone(spec.Schema))
+	}
+	b.request.SchemaName = spec.SchemaName
+	b.request.Mode = spec.Mode
+	b.repairAttempts = spec.RepairAttempts
+	b.skipSchemaCheck = spec.SkipSchemaValidation
+	return b, nil
+}
+
+// commonSpec fills the fields every request kind shares.
+func (cb *CommonBuilder) commonSpec(kind RequestKind, base types.BaseRequest, messages []types.Message) (*RequestSpec, error) {
+	if cb.promptErr != nil {
+		return nil, cb.promptErr
+	}
+	if cb.experimentErr != nil {
+		return nil, cb.experimentErr
+	}
+	records, err := types.RecordMessages(messages)
+	if err != nil {
+		return nil, fmt.Errorf("request spec: %w", err)
+	}
+	spec := &RequestSpec{
+		Version:     RequestSpecVersion,
+		Kind:        kind,
+		Provider:    cb.provider,
+		BaseURL:     cb.baseURL,
+		BaseRequest: types.BaseRequest{Model: base.Model},
+		Messages:    records,
+		Tags:        maps.Clone(cb.tags),
+	}
+	cloneBaseRequestFields(&spec.BaseRequest, &base)
+	spec.ProviderOptions, spec.BaseRequest.ProviderOptions = spec.BaseRequest.ProviderOptions, nil
+	if len(cb.scopedOptions) > 0 {
+		spec.ScopedProviderOptions = make(map[string]map[string]any, len(cb.scopedOptions))
+		for name, options := range cb.scopedOptions {
+			spec.ScopedProviderOptions[name] = types.CloneMap(options)
+		}
+	}
+	if cb.priority != nil {
+		priority := *cb.priority
+		spec.Priority = &priority
+	}
+	if cb.cache != nil {
+		spec.Cache = &RequestSpecCache{TTL: Duration(cb.cache.TTL), Bypass: cb.cache.Bypass}
+	}
+	return spec, nil
+}
+
+// applyCommonSpec restores the fields commonSpec records.
+func (cb *CommonBuilder) applyCommonSpec(spec *RequestSpec) {
+	if spec.Provider != "" {
+		cb.setProvider(spec.Provider)
+	}
+	if spec.BaseURL != "" {
+		cb.setBaseURL(spec.BaseURL)
+	}
+	if len(spec.Tags) > 0 {
+		cb.addTags(spec.Tags)
+	}
+	for name, options := range spec.ScopedProviderOptions {
+		for key, value := range options {
+			cb.setProviderOption(name, key, value)
+		}
+	}
+	if spec.Priority != nil {
+		cb.setPriority(*spec.Priority)
+	}
+	if spec.Cache != nil {
+		cb.setCacheControl(middleware.CacheControl{TTL: time.Duration(spec.Cache.TTL), Bypass: spec.Cache.Bypass})
+	}
+}
+
+func checkSpec(spec *RequestSpec, kind RequestKind) error {
+	if spec == nil {
+		return types.ErrInvalidRequest.WithDetails("request spec is nil")
+	}
+	if spec.Version > RequestSpecVersion {
+		return types.ErrInvalidRequest.WithDetails(fmt.Sprintf("request spec version %d is newer than supported version %d", spec.Version, RequestSpecVersion))
+	}
+	if spec.Kind != kind {
+		return types.ErrInvalidRequest.WithDetails(fmt.Sprintf("request spec is %q, not %q", spec.Kind, kind))
+	}
+	return nil
+}
+
+func specBaseRequest(spec *RequestSpec) types.BaseRequest {
+	base := types.BaseRequest{Model: spec.Model}
+	cloneBaseRequestFields(&base, &spec.BaseRequest)
+	base.ProviderOptions = cloneProviderOptions(spec.ProviderOptions)
+	return base
+}
+
+// structuredSchemaJSON returns the request schema as JSON. Schema stores the
+// marshaled bytes; a schema set on the request directly is marshaled here.
+func structuredSchemaJSON(schema types.Schema) (json.RawMessage, error) {
+	switch s := schema.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return bytes.Clone(s), nil
+	case json.RawMessage:
+		return bytes.Clone(s), nil
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return data, nil
+}
+
+func cloneBoolPtr(v *bool) *bool {
+	if v == nil {
+		return nil
+	}
+	copied := *v
+	return &copied
+}
@@ -0,0 +1,127 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestTextRequestSpecRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	provider := &requestCapturingProvider{BaseProvider: types.NewBaseProvider("mock")}
+	client := constraintsTestClient(provider)
+	toolResult := types.NewToolResultMessage("call_1", "timed out").WithError("upstream timeout")
+	toolResult.FunctionName = "lookup"
+	builder := client.Text().
+		Model("gpt-4o").
+		SystemPrompt("Answer from the order record.").
+		Messages(
+			types.NewUserMessage("Where is order 42?"),
+			&types.AssistantMessage{ToolCalls: []types.ToolCall{{ID: "call_1", Name: "lookup", Arguments: map[string]any{"order": "42"}}}},
+			toolResult,
+		).
+		Tools(*types.NewTool("lookup", "Look up an order", map[string]any{"type": "object"})).
+		ToolChoice("auto").
+		WithToolsDisabled().
+		Temperature(0.2).
+		MaxTokens(300).
+		Stop("END").
+		LogitBias(map[int]int{50256: -100}).
+		ProviderOptions(map[string]any{"user": "ops"}).
+		ProviderOption("openrouter", "transforms", []any{"middle-out"}).
+		Metadata(map[string]string{"tenant": "acme"}).
+		Priority(High).
+		Cache(time.Hour).
+		WithFallback("gpt-4o-mini").
+		WithProviderFallback(TextRoute{Provider: "openrouter", Model: "openai/gpt-4o"}).
+		AutoContinue(2)
+
+	spec, err := builder.ToSpec()
+	require.NoError(t, err)
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	parsed, err := ParseRequestSpec(data)
+	require.NoError(t, err)
+	restored, err := client.FromSpec(parsed)
+	require.NoError(t, err)
+	respec, err := restored.ToSpec()
+	require.NoError(t, err)
+	redata, err := json.Marshal(respec)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(redata), "a replayed spec writes the same spec")
+
+	assert.Equal(t, *builder.request, *restored.request)
+	assert.Equal(t, builder.tags, restored.tags)
+	assert.Equal(t, builder.scopedOptions, restored.scopedOptions)
+	assert.Equal(t, *builder.priority, *restored.priority)
+	assert.Equal(t, *builder.cache, *restored.cache)
+	assert.Equal(t, builder.fallbackModels, restored.fallbackModels)
+	assert.Equal(t, builder.providerFallbacks, restored.providerFallbacks)
+	assert.Equal(t, builder.toolExecutionOverride, restored.toolExecutionOverride)
+	assert.Equal(t, 2, restored.autoContinueRounds)
+
+	_, err = restored.Generate(context.Background())
+	require.NoError(t, err)
+	sent := provider.last()
+	require.Len(t, sent.Messages, 4)
+	assert.Equal(t, types.RoleSystem, sent.Messages[0].GetRole())
+	assert.Equal(t, "upstream timeout", sent.Messages[3].(*types.ToolResultMessage).Error)
+	assert.Equal(t, "ops", sent.ProviderOptions["user"])
+}
+
+func TestStructuredRequestSpecRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	client := New(WithDefaultProvider("openai"), WithOpenAI("test-key"), WithModelValidation(false), WithDiscovery(false))
+	schema := map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}}
+	builder := client.Structured().Model("gpt-5-mini").SystemPrompt("Extract the person.").Prompt("Ada Lovelace, 1815").
+		Schema(schema).SchemaName("person").Strict(true).RepairAttempts(2).Temperature(0)
+
+	spec, err := builder.ToSpec()
+	require.NoError(t, err)
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"schema":{"properties"`, "the schema is stored as JSON, not base64")
+
+	parsed, err := ParseRequestSpec(data)
+	require.NoError(t, err)
+	restored, err := client.StructuredFromSpec(parsed)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(builder.request.Schema.([]byte)), string(restored.request.Schema.([]byte)))
+	original, replayed := *builder.request, *restored.request
+	original.Schema, replayed.Schema = nil, nil
+	assert.Equal(t, original, replayed)
+	assert.Equal(t, 2, restored.repairAttempts)
+	assert.Equal(t, "openai", restored.getProvider())
+
+	_, err = client.FromSpec(parsed)
+	require.Error(t, err, "a structured spec is not a text request")
+}
+
+func TestRequestSpecErrors(t *testing.T) {
+	t.Parallel()
+
+	client := New(WithDefaultProvider("openai"), WithOpenAI("test-key"), WithModelValidation(false), WithDiscovery(false))
+
+	_, err := client.Text().Model("gpt-4o").Document("/nonexistent/report.pdf").ToSpec()
+	require.Error(t, err, "a deferred builder error fails ToSpec")
+
+	_, err = client.Text().Model("gpt-4o").Messages(types.BaseMessage{Role: types.RoleUser, Content: 42}).ToSpec()
+	require.Error(t, err)
+
+	_, err = ParseRequestSpec([]byte(`{"version":1,"kind":"text","modle":"gpt-4o"}`))
+	require.Error(t, err, "unknown fields are rejected")
+
+	_, err = client.FromSpec(&RequestSpec{Version: RequestSpecVersion + 1, Kind: RequestKindText})
+	require.Error(t, err)
+	_, err = client.FromSpec(nil)
+	require.Error(t, err)
+}
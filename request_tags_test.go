@@ -0,0 +1,77 @@
+package wormhole_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestBuilderMetadataReachesMiddleware(t *testing.T) {
+	t.Parallel()
+
+	collector := middleware.NewEnhancedMetricsCollector(nil)
+	provider := mocktesting.NewMockProvider("mock").
+		WithTextResponse(types.TextResponse{Text: "ok", Model: "m"}).
+		WithEmbeddings([]types.Embedding{{Index: 0, Embedding: []float64{1}}})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithProviderMiddleware(middleware.NewTypedEnhancedMetricsMiddleware(collector)),
+		wormhole.WithDiscovery(false),
+	)
+	defer client.Close()
+	ctx := context.Background()
+
+	conv := types.NewConversation().User("hi")
+	_, err := client.Text().Model("m").
+		Metadata(map[string]string{"tenant": "acme"}).
+		Metadata(map[string]string{"feature": "chat"}).
+		GenerateTurn(ctx, conv)
+	require.NoError(t, err)
+	_, err = client.Embeddings().Model("e").Input("x").Metadata(map[string]string{"tenant": "globex"}).Generate(ctx)
+	require.NoError(t, err)
+
+	byTenant := collector.GetTagStats("tenant")
+	assert.Equal(t, int64(1), byTenant["acme"]["requests"])
+	assert.Equal(t, int64(1), byTenant["globex"]["requests"])
+	assert.Equal(t, int64(1), collector.GetTagStats("feature")["chat"]["requests"])
+
+	turns := conv.Turns()
+	require.Len(t, turns, 1)
+	assert.Equal(t, map[string]string{"tenant": "acme", "feature": "chat"}, turns[0].Tags)
+}
+
+func TestBuilderPriorityReachesScheduler(t *testing.T) {
+	t.Parallel()
+
+	provider := mocktesting.NewMockProvider("mock").
+		WithTextResponse(types.TextResponse{Text: "ok", Model: "m"}).
+		WithEmbeddings([]types.Embedding{{Index: 0, Embedding: []float64{1}}})
+	scheduler := middleware.NewScheduler(middleware.SchedulerConfig{MaxConcurrent: 4})
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithMiddleware(scheduler.Middleware()),
+		wormhole.WithDiscovery(false),
+	)
+	defer client.Close()
+	ctx := middleware.WithPriority(context.Background(), wormhole.Low)
+
+	_, err := client.Text().Model("m").Prompt("hi").Priority(wormhole.High).Generate(ctx)
+	require.NoError(t, err)
+	_, err = client.Embeddings().Model("e").Input("x").Clone().Generate(ctx)
+	require.NoError(t, err)
+
+	stats := scheduler.Stats()
+	assert.Equal(t, map[middleware.Priority]int64{wormhole.High: 1, wormhole.Low: 1}, stats.Admitted)
+	assert.Equal(t, 0, stats.InFlight)
+}
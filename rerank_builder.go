@@ -2,7 +2,9 @@ package wormhole
 
 import (
 	"context"
+	"time"
 
+	"github.com/garyblankenship/wormhole/v2/middleware"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
@@ -64,6 +66,51 @@ func (b *RerankRequestBuilder) ProviderOptions(options map[string]any) *RerankRe
 	return b
 }
 
+// ProviderOption sets one provider-specific option sent only to provider, as
+// TextRequestBuilder.ProviderOption does.
+func (b *RerankRequestBuilder) ProviderOption(provider, key string, value any) *RerankRequestBuilder {
+	b.setProviderOption(provider, key, value)
+	return b
+}
+
+// Metadata tags the request for metrics and logging, as
+// TextRequestBuilder.Metadata does.
+func (b *RerankRequestBuilder) Metadata(tags map[string]string) *RerankRequestBuilder {
+	b.addTags(tags)
+	return b
+}
+
+// Priority sets the request's scheduling priority, as
+// TextRequestBuilder.Priority does.
+func (b *RerankRequestBuilder) Priority(p Priority) *RerankRequestBuilder {
+	b.setPriority(p)
+	return b
+}
+
+// Cache overrides CacheMiddleware's TTL for this request, as
+// TextRequestBuilder.Cache does.
+func (b *RerankRequestBuilder) Cache(ttl time.Duration) *RerankRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{TTL: ttl})
+	return b
+}
+
+// NoCache sends the request past the cache middleware, as
+// TextRequestBuilder.NoCache does.
+func (b *RerankRequestBuilder) NoCache() *RerankRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{Bypass: true})
+	return b
+}
+
+// CacheKey returns the key CacheMiddleware uses for this request, as
+// TextRequestBuilder.CacheKey does.
+func (b *RerankRequestBuilder) CacheKey() (string, error) {
+	return b.cacheKey(func(provider types.Provider) any {
+		scoped := *b.request
+		scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
+		return scoped
+	})
+}
+
 // Validate checks the request configuration for errors before calling Generate().
 func (b *RerankRequestBuilder) Validate() error {
 	var errs types.ValidationErrors
@@ -86,6 +133,7 @@ func (b *RerankRequestBuilder) Validate() error {
 // Shutdown, idempotency) exactly like Text/Embeddings, so Shutdown can no
 // longer tear down connections out from under an in-flight rerank call.
 func (b *RerankRequestBuilder) Generate(ctx context.Context) (*types.RerankResponse, error) {
+	ctx = b.taggedContext(ctx)
 	if err := b.Validate(); err != nil {
 		return nil, err
 	}
@@ -109,10 +157,12 @@ func (b *RerankRequestBuilder) executeRerank(ctx context.Context, request *types
 	defer release()
 
 	ctx = contextWithProviderOperation(ctx, provider, "rerank")
+	scoped := *request
+	scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
 	if b.getWormhole().providerMiddleware != nil {
 		handler := b.getWormhole().providerMiddleware.ApplyRerank(provider.Rerank)
-		return handler(ctx, *request)
+		return handler(ctx, scoped)
 	}
 
-	return provider.Rerank(ctx, *request)
+	return provider.Rerank(ctx, scoped)
 }
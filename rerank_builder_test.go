@@ -2,6 +2,9 @@ package wormhole_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -87,3 +90,47 @@ func TestRerankBuilderGenerate(t *testing.T) {
 		assert.Contains(t, err.Error(), "rerank provider error")
 	})
 }
+
+func TestRerankProviders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		option func(key string, config ...types.ProviderConfig) wormhole.Option
+		limit  string
+	}{
+		{name: "cohere", option: wormhole.WithCohere, limit: "top_n"},
+		{name: "jina", option: wormhole.WithJina, limit: "top_n"},
+		{name: "voyage", option: wormhole.WithVoyage, limit: "top_k"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/rerank", r.URL.Path)
+				assert.Equal(t, "Bearer key-"+tt.name, r.Header.Get("Authorization"))
+				var req map[string]any
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, float64(1), req[tt.limit])
+				_, _ = w.Write([]byte(`{"results":[{"index":1,"relevance_score":0.9}]}`))
+			}))
+			t.Cleanup(server.Close)
+
+			client := wormhole.New(tt.option("key-"+tt.name, types.ProviderConfig{BaseURL: server.URL}))
+			resp, err := client.Rerank().
+				Using(tt.name).
+				Model("rerank-model").
+				Query("capital of France").
+				Documents("Berlin", "Paris").
+				TopN(1).
+				Generate(context.Background())
+			require.NoError(t, err)
+			require.Len(t, resp.Results, 1)
+			assert.Equal(t, "Paris", resp.Results[0].Document)
+		})
+	}
+
+	profile, ok := wormhole.ProviderProfileByName("cohere")
+	require.True(t, ok)
+	assert.Equal(t, "https://api.cohere.com/v2", profile.DefaultBaseURL)
+}
@@ -0,0 +1,383 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/garyblankenship/wormhole/v2/internal/schemavalidation"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ErrResponseInvalid reports that a response failed its validators under every
+// attempt the validation strategy allowed. The returned error is a
+// *ResponseValidationError carrying the full report.
+var ErrResponseInvalid = errors.New("response failed validation")
+
+// ValidationStrategy decides what GenerateValidated does when a response fails
+// validation.
+type ValidationStrategy string
+
+const (
+	// ValidationFail returns ErrResponseInvalid on the first failure.
+	ValidationFail ValidationStrategy = "fail"
+	// ValidationRetry asks the same model again, up to MaxAttempts times.
+	ValidationRetry ValidationStrategy = "retry"
+	// ValidationEscalate tries the builder's model, then each EscalateTo route
+	// in order, MaxAttempts times each.
+	ValidationEscalate ValidationStrategy = "escalate"
+)
+
+// ResponseValidator checks one response. Returning an error rejects it; the
+// error text is recorded in the validation report.
+type ResponseValidator interface {
+	ValidateResponse(ctx context.Context, in ValidationInput) error
+}
+
+// ResponseValidatorFunc adapts a function to ResponseValidator.
+type ResponseValidatorFunc func(ctx context.Context, in ValidationInput) error
+
+// ValidateResponse calls f.
+func (f ResponseValidatorFunc) ValidateResponse(ctx context.Context, in ValidationInput) error {
+	return f(ctx, in)
+}
+
+// ValidationInput is the response under validation.
+type ValidationInput struct {
+	// Client is the client that produced the response, for validators that
+	// make their own requests (see JudgeValidator).
+	Client   *Wormhole
+	Provider string
+	Model    string
+	// Text is the response text. For structured requests it is the raw JSON.
+	Text string
+}
+
+// ResponseValidation is a declarative validation policy for GenerateValidated.
+// It carries json tags so policies can live in configuration files.
+type ResponseValidation struct {
+	// Validators names validators registered with WithResponseValidator.
+	Validators []string `json:"validators,omitempty"`
+	// Custom validators run after the named ones.
+	Custom []ResponseValidator `json:"-"`
+	// Strategy defaults to ValidationFail.
+	Strategy ValidationStrategy `json:"strategy,omitempty"`
+	// MaxAttempts bounds attempts per route: the builder's route for
+	// ValidationRetry, every route for ValidationEscalate. Zero means 3 for
+	// retry and 1 for escalate.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// EscalateTo lists stronger routes for ValidationEscalate. An empty
+	// Provider keeps the builder's provider.
+	EscalateTo []TextRoute `json:"escalate_to,omitempty"`
+}
+
+// ValidationReport records how GenerateValidated resolved a request. It is
+// stored under Metadata["validation"] on successful responses and on
+// *ResponseValidationError otherwise.
+type ValidationReport struct {
+	Strategy ValidationStrategy `json:"strategy"`
+	// ResolvedBy is "first_attempt", "retry", or "escalation" on success and
+	// empty on failure.
+	ResolvedBy string              `json:"resolved_by,omitempty"`
+	Attempts   int                 `json:"attempts"`
+	Provider   string              `json:"provider,omitempty"`
+	Model      string              `json:"model,omitempty"`
+	Failures   []ValidationFailure `json:"failures,omitempty"`
+}
+
+// ValidationFailure is one rejected response.
+type ValidationFailure struct {
+	Attempt   int    `json:"attempt"`
+	Provider  string `json:"provider,omitempty"`
+	Model     string `json:"model"`
+	Validator string `json:"validator"`
+	Error     string `json:"error"`
+}
+
+// ResponseValidationError is returned when every allowed attempt failed
+// validation. It matches ErrResponseInvalid with errors.Is.
+type ResponseValidationError struct {
+	Report ValidationReport
+}
+
+func (e *ResponseValidationError) Error() string {
+	msg := fmt.Sprintf("%s after %d attempt(s)", ErrResponseInvalid, e.Report.Attempts)
+	if n := len(e.Report.Failures); n > 0 {
+		last := e.Report.Failures[n-1]
+		msg += fmt.Sprintf(": %s: %s", last.Validator, last.Error)
+	}
+	return msg
+}
+
+// Is reports whether target is ErrResponseInvalid.
+func (e *ResponseValidationError) Is(target error) bool {
+	return target == ErrResponseInvalid
+}
+
+// RegexValidator accepts responses that match re.
+func RegexValidator(re *regexp.Regexp) ResponseValidator {
+	return ResponseValidatorFunc(func(_ context.Context, in ValidationInput) error {
+		if !re.MatchString(in.Text) {
+			return fmt.Errorf("response does not match %s", re)
+		}
+		return nil
+	})
+}
+
+// JSONSchemaValidator accepts responses that are a JSON object valid against
+// schema. Surrounding markdown code fences are ignored.
+func JSONSchemaValidator(schema map[string]any) ResponseValidator {
+	return ResponseValidatorFunc(func(_ context.Context, in ValidationInput) error {
+		var data map[string]any
+		if err := json.Unmarshal([]byte(stripCodeFence(in.Text)), &data); err != nil {
+			return fmt.Errorf("response is not a JSON object: %w", err)
+		}
+		return schemavalidation.ValidateAgainstSchema(data, schema)
+	})
+}
+
+// JudgeValidator asks judge to grade each response against criteria. The judge
+// must answer PASS or FAIL followed by a reason; anything else is a failure.
+// An empty judge.Provider uses the client's default provider.
+func JudgeValidator(judge TextRoute, criteria string) ResponseValidator {
+	return ResponseValidatorFunc(func(ctx context.Context, in ValidationInput) error {
+		if in.Client == nil {
+			return errors.New("judge validator needs a client")
+		}
+		builder := in.Client.Text().Model(judge.Model)
+		if judge.Provider != "" {
+			builder.Using(judge.Provider)
+		}
+		resp, err := builder.
+			SystemPrompt("You grade AI responses. Reply with PASS or FAIL on the first line, then one sentence explaining why.").
+			Prompt(fmt.Sprintf("Criteria:\n%s\n\nResponse:\n%s", criteria, in.Text)).
+			Generate(ctx)
+		if err != nil {
+			return fmt.Errorf("judge request failed: %w", err)
+		}
+		verdict := strings.TrimSpace(resp.Text)
+		if strings.HasPrefix(strings.ToUpper(verdict), "PASS") {
+			return nil
+		}
+		return fmt.Errorf("judge rejected response: %s", verdict)
+	})
+}
+
+// stripCodeFence removes a surrounding ``` or ```json fence.
+func stripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	text = strings.TrimPrefix(text, "```")
+	if nl := strings.IndexByte(text, '\n'); nl >= 0 {
+		text = text[nl+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+}
+
+type namedValidator struct {
+	name      string
+	validator ResponseValidator
+}
+
+// validationAttempt is one generation under a validation policy.
+type validationAttempt struct {
+	route TextRoute
+	retry bool
+}
+
+// resolve looks up the policy's named validators and expands its strategy
+// into the ordered list of attempts. base is the builder's own route.
+func (v ResponseValidation) resolve(client *Wormhole, base TextRoute) ([]namedValidator, []validationAttempt, error) {
+	validators := make([]namedValidator, 0, len(v.Validators)+len(v.Custom))
+	for _, name := range v.Validators {
+		validator, ok := client.config.ResponseValidators[name]
+		if !ok {
+			return nil, nil, types.ErrInvalidRequest.WithDetails(fmt.Sprintf("response validator %q is not registered", name))
+		}
+		validators = append(validators, namedValidator{name: name, validator: validator})
+	}
+	for i, validator := range v.Custom {
+		validators = append(validators, namedValidator{name: fmt.Sprintf("custom[%d]", i), validator: validator})
+	}
+	if len(validators) == 0 {
+		return nil, nil, types.ErrInvalidRequest.WithDetails("response validation has no validators")
+	}
+
+	var attempts []validationAttempt
+	switch v.Strategy {
+	case "", ValidationFail:
+		attempts = []validationAttempt{{route: base}}
+	case ValidationRetry:
+		n := v.MaxAttempts
+		if n <= 0 {
+			n = 3
+		}
+		for i := 0; i < n; i++ {
+			attempts = append(attempts, validationAttempt{route: base, retry: i > 0})
+		}
+	case ValidationEscalate:
+		n := v.MaxAttempts
+		if n <= 0 {
+			n = 1
+		}
+		routes := append([]TextRoute{base}, v.EscalateTo...)
+		for r, route := range routes {
+			if route.Provider == "" {
+				route.Provider = base.Provider
+			}
+			for i := 0; i < n; i++ {
+				attempts = append(attempts, validationAttempt{route: route, retry: r == 0 && i > 0})
+			}
+		}
+	default:
+		return nil, nil, types.ErrInvalidRequest.WithDetails(fmt.Sprintf("unknown validation strategy %q", v.Strategy))
+	}
+	return validators, attempts, nil
+}
+
+// runValidated drives generate through the policy's attempts until one
+// response passes every validator. Generation errors end the run immediately;
+// provider failures are the job of fallbacks, not validation.
+func runValidated[R any](
+	ctx context.Context,
+	client *Wormhole,
+	policy ResponseValidation,
+	base TextRoute,
+	generate func(ctx context.Context, route TextRoute) (R, string, error),
+	record func(R, ValidationReport),
+) (R, error) {
+	var zero R
+	validators, attempts, err := policy.resolve(client, base)
+	if err != nil {
+		return zero, err
+	}
+	strategy := policy.Strategy
+	if strategy == "" {
+		strategy = ValidationFail
+	}
+
+	report := ValidationReport{Strategy: strategy}
+	for i, attempt := range attempts {
+		resp, text, err := generate(ctx, attempt.route)
+		if err != nil {
+			return zero, err
+		}
+		report.Attempts = i + 1
+
+		in := ValidationInput{Client: client, Provider: attempt.route.Provider, Model: attempt.route.Model, Text: text}
+		failure := runValidators(ctx, validators, in)
+		if failure == nil {
+			report.Provider = attempt.route.Provider
+			report.Model = attempt.route.Model
+			switch {
+			case i == 0:
+				report.ResolvedBy = "first_attempt"
+			case attempt.retry:
+				report.ResolvedBy = "retry"
+			default:
+				report.ResolvedBy = "escalation"
+			}
+			record(resp, report)
+			return resp, nil
+		}
+		failure.Attempt = i + 1
+		report.Failures = append(report.Failures, *failure)
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+	}
+	return zero, &ResponseValidationError{Report: report}
+}
+
+func runValidators(ctx context.Context, validators []namedValidator, in ValidationInput) *ValidationFailure {
+	for _, v := range validators {
+		if err := v.validator.ValidateResponse(ctx, in); err != nil {
+			return &ValidationFailure{Provider: in.Provider, Model: in.Model, Validator: v.name, Error: err.Error()}
+		}
+	}
+	return nil
+}
+
+// GenerateValidated runs Generate and checks the response against policy,
+// retrying or escalating to stronger models as the strategy allows. The
+// returned response carries a ValidationReport under Metadata["validation"];
+// if no attempt passes, the error is a *ResponseValidationError.
+//
+// Example:
+//
+//	resp, err := client.Text().
+//	    Model("gpt-5-mini").
+//	    Prompt("Reply with an order ID like ORD-1234").
+//	    GenerateValidated(ctx, wormhole.ResponseValidation{
+//	        Custom:     []wormhole.ResponseValidator{wormhole.RegexValidator(orderID)},
+//	        Strategy:   wormhole.ValidationEscalate,
+//	        EscalateTo: []wormhole.TextRoute{{Model: "gpt-5.2"}},
+//	    })
+func (b *TextRequestBuilder) GenerateValidated(ctx context.Context, policy ResponseValidation) (*types.TextResponse, error) {
+	base := TextRoute{Provider: b.getProvider(), Model: b.resolveModel(b.request.Model)}
+	return runValidated(ctx, b.getWormhole(), policy, base,
+		func(ctx context.Context, route TextRoute) (*types.TextResponse, string, error) {
+			attempt := b.Clone()
+			if route.Provider != b.getProvider() {
+				attempt.provider = route.Provider
+				attempt.baseURL = ""
+			}
+			attempt.request.Model = route.Model
+			resp, err := attempt.Generate(ctx)
+			if err != nil {
+				return nil, "", err
+			}
+			return resp, resp.Text, nil
+		},
+		func(resp *types.TextResponse, report ValidationReport) {
+			if resp.Metadata == nil {
+				resp.Metadata = make(map[string]any)
+			}
+			resp.Metadata["validation"] = report
+		})
+}
+
+// GenerateValidated runs Generate and checks the raw JSON response against
+// policy, as TextRequestBuilder.GenerateValidated does.
+func (b *StructuredRequestBuilder) GenerateValidated(ctx context.Context, policy ResponseValidation) (*types.StructuredResponse, error) {
+	base := TextRoute{Provider: b.getProvider(), Model: b.resolveModel(b.request.Model)}
+	return runValidated(ctx, b.getWormhole(), policy, base,
+		func(ctx context.Context, route TextRoute) (*types.StructuredResponse, string, error) {
+			attempt := &StructuredRequestBuilder{
+				CommonBuilder:   b.CommonBuilder,
+				request:         cloneStructuredRequest(b.request),
+				schemaErr:       b.schemaErr,
+				skipSchemaCheck: b.skipSchemaCheck,
+				repairAttempts:  b.repairAttempts,
+			}
+			if route.Provider != b.getProvider() {
+				attempt.provider = route.Provider
+				attempt.baseURL = ""
+			}
+			attempt.request.Model = route.Model
+			resp, err := attempt.Generate(ctx)
+			if err != nil {
+				return nil, "", err
+			}
+			text := resp.Raw
+			if text == "" {
+				data, err := json.Marshal(resp.Data)
+				if err != nil {
+					return nil, "", fmt.Errorf("failed to marshal response data: %w", err)
+				}
+				text = string(data)
+			}
+			return resp, text, nil
+		},
+		func(resp *types.StructuredResponse, report ValidationReport) {
+			if resp.Metadata == nil {
+				resp.Metadata = make(map[string]any)
+			}
+			resp.Metadata["validation"] = report
+		})
+}
@@ -0,0 +1,167 @@
+package wormhole_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+// scriptedProvider answers each model with its queued replies in order,
+// repeating the last one.
+type scriptedProvider struct {
+	*mocktesting.MockProvider
+	mu      sync.Mutex
+	replies map[string][]string
+	calls   []string
+}
+
+func (p *scriptedProvider) Text(_ context.Context, request types.TextRequest) (*types.TextResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, request.Model)
+	queue := p.replies[request.Model]
+	if len(queue) == 0 {
+		return nil, errors.New("no reply for " + request.Model)
+	}
+	reply := queue[0]
+	if len(queue) > 1 {
+		p.replies[request.Model] = queue[1:]
+	}
+	return &types.TextResponse{Model: request.Model, Text: reply}, nil
+}
+
+func newValidationClient(provider types.Provider, opts ...wormhole.Option) *wormhole.Wormhole {
+	return wormhole.New(append([]wormhole.Option{
+		wormhole.WithDefaultProvider("scripted"),
+		wormhole.WithCustomProvider("scripted", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+		wormhole.WithProviderConfig("scripted", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+	}, opts...)...)
+}
+
+var orderID = regexp.MustCompile(`^ORD-\d{4}$`)
+
+func TestGenerateValidatedRetriesSameModel(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{
+		MockProvider: mocktesting.NewMockProvider("scripted"),
+		replies:      map[string][]string{"small": {"sure! ORD-1", "ORD-1234"}},
+	}
+	client := newValidationClient(provider, wormhole.WithResponseValidator("order_id", wormhole.RegexValidator(orderID)))
+	defer client.Close()
+
+	resp, err := client.Text().Model("small").Prompt("order?").GenerateValidated(context.Background(), wormhole.ResponseValidation{
+		Validators: []string{"order_id"},
+		Strategy:   wormhole.ValidationRetry,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ORD-1234", resp.Text)
+
+	report, ok := resp.Metadata["validation"].(wormhole.ValidationReport)
+	require.True(t, ok)
+	assert.Equal(t, wormhole.ValidationRetry, report.Strategy)
+	assert.Equal(t, "retry", report.ResolvedBy)
+	assert.Equal(t, 2, report.Attempts)
+	assert.Equal(t, "small", report.Model)
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, "order_id", report.Failures[0].Validator)
+}
+
+func TestGenerateValidatedEscalatesToStrongerModel(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{
+		MockProvider: mocktesting.NewMockProvider("scripted"),
+		replies:      map[string][]string{"small": {"no idea"}, "big": {"ORD-9876"}},
+	}
+	client := newValidationClient(provider)
+	defer client.Close()
+
+	resp, err := client.Text().Model("small").Prompt("order?").GenerateValidated(context.Background(), wormhole.ResponseValidation{
+		Custom:     []wormhole.ResponseValidator{wormhole.RegexValidator(orderID)},
+		Strategy:   wormhole.ValidationEscalate,
+		EscalateTo: []wormhole.TextRoute{{Model: "big"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ORD-9876", resp.Text)
+	assert.Equal(t, []string{"small", "big"}, provider.calls)
+
+	report := resp.Metadata["validation"].(wormhole.ValidationReport)
+	assert.Equal(t, "escalation", report.ResolvedBy)
+	assert.Equal(t, "big", report.Model)
+}
+
+func TestGenerateValidatedFailStrategyReturnsReport(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{
+		MockProvider: mocktesting.NewMockProvider("scripted"),
+		replies:      map[string][]string{"small": {"nope", "ORD-1234"}},
+	}
+	client := newValidationClient(provider)
+	defer client.Close()
+
+	_, err := client.Text().Model("small").Prompt("order?").GenerateValidated(context.Background(), wormhole.ResponseValidation{
+		Custom: []wormhole.ResponseValidator{wormhole.RegexValidator(orderID)},
+	})
+	require.ErrorIs(t, err, wormhole.ErrResponseInvalid)
+
+	var validationErr *wormhole.ResponseValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, wormhole.ValidationFail, validationErr.Report.Strategy)
+	assert.Equal(t, 1, validationErr.Report.Attempts)
+	assert.Len(t, provider.calls, 1)
+}
+
+func TestGenerateValidatedRejectsUnknownValidator(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{MockProvider: mocktesting.NewMockProvider("scripted")}
+	client := newValidationClient(provider)
+	defer client.Close()
+
+	_, err := client.Text().Model("small").Prompt("order?").GenerateValidated(context.Background(), wormhole.ResponseValidation{
+		Validators: []string{"missing"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"missing" is not registered`)
+	assert.Empty(t, provider.calls)
+}
+
+func TestJSONSchemaAndJudgeValidators(t *testing.T) {
+	t.Parallel()
+
+	provider := &scriptedProvider{
+		MockProvider: mocktesting.NewMockProvider("scripted"),
+		replies:      map[string][]string{"judge": {"FAIL: too vague", "PASS: cites a source"}},
+	}
+	client := newValidationClient(provider)
+	defer client.Close()
+	ctx := context.Background()
+
+	schema := wormhole.JSONSchemaValidator(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"name": map[string]any{"type": "string"}},
+		"required":   []any{"name"},
+	})
+	require.NoError(t, schema.ValidateResponse(ctx, wormhole.ValidationInput{Text: "```json\n{\"name\": \"Ada\"}\n```"}))
+	require.Error(t, schema.ValidateResponse(ctx, wormhole.ValidationInput{Text: `{"age": 3}`}))
+	require.Error(t, schema.ValidateResponse(ctx, wormhole.ValidationInput{Text: "not json"}))
+
+	judge := wormhole.JudgeValidator(wormhole.TextRoute{Model: "judge"}, "must cite a source")
+	in := wormhole.ValidationInput{Client: client, Text: "Paris is the capital."}
+	err := judge.ValidateResponse(ctx, in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too vague")
+	require.NoError(t, judge.ValidateResponse(ctx, in))
+}
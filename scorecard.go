@@ -0,0 +1,320 @@
+package wormhole
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// scorecardLatencySamples caps the latencies kept per provider and bucket.
+// Beyond it, samples are reservoir-sampled so percentiles stay unbiased.
+const scorecardLatencySamples = 256
+
+// ScorecardConfig configures the rolling provider scorecard enabled by
+// WithScorecard.
+type ScorecardConfig struct {
+	// Window is the longest period scores cover (default 5m).
+	Window time.Duration
+	// Buckets is how many slices Window is divided into (default 10). Scores
+	// age out one slice at a time, and ScoresWithin resolves to a slice.
+	Buckets int
+}
+
+// ProviderScore summarizes one provider's text and structured requests over
+// a rolling window.
+type ProviderScore struct {
+	Provider string
+	Window   time.Duration
+	Requests int64
+	Errors   int64
+	// ErrorMix counts failed requests by types.ClassifyError class.
+	ErrorMix map[types.ErrorClass]int64
+	// P50Latency and P95Latency cover successful requests only, so fast
+	// failures do not flatter a provider.
+	P50Latency   time.Duration
+	P95Latency   time.Duration
+	InputTokens  int64
+	OutputTokens int64
+	// Cost is the estimated spend of requests whose model is priced in the
+	// model registry (see WithModels); PricedTokens are their tokens.
+	Cost         float64
+	PricedTokens int64
+}
+
+// SuccessRate returns the fraction of requests that succeeded, or 0 when
+// there were none.
+func (s ProviderScore) SuccessRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Requests-s.Errors) / float64(s.Requests)
+}
+
+// CostPer1KTokens returns the estimated cost per 1,000 priced tokens, or 0
+// when no request was priced.
+func (s ProviderScore) CostPer1KTokens() float64 {
+	if s.PricedTokens == 0 {
+		return 0
+	}
+	return s.Cost / float64(s.PricedTokens) * 1000
+}
+
+// Scorecard aggregates per-provider success rate, latency percentiles, cost
+// and error mix over a rolling window. It observes every text and structured
+// request the client sends to a provider, fallback attempts included, so
+// dashboards and routing code can compare providers on live traffic.
+// Requests the caller canceled are not counted. See WithScorecard.
+type Scorecard struct {
+	config      ScorecardConfig
+	bucketWidth time.Duration
+	now         func() time.Time
+	models      types.ModelCatalog // prices usage; nil uses types.DefaultModelRegistry
+
+	mu      sync.Mutex
+	buckets []scorecardBucket
+}
+
+type scorecardBucket struct {
+	start     time.Time
+	providers map[string]*scorecardTally
+}
+
+// scorecardTally is one provider's counts within a bucket.
+type scorecardTally struct {
+	requests     int64
+	errors       int64
+	errorMix     map[types.ErrorClass]int64
+	inputTokens  int64
+	outputTokens int64
+	cost         float64
+	pricedTokens int64
+	latencies    []time.Duration
+	successes    int64 // latencies offered, for reservoir sampling
+}
+
+func newScorecard(config ScorecardConfig) *Scorecard {
+	if config.Window <= 0 {
+		config.Window = 5 * time.Minute
+	}
+	if config.Buckets <= 0 {
+		config.Buckets = 10
+	}
+	bucketWidth := config.Window / time.Duration(config.Buckets)
+	if bucketWidth <= 0 {
+		bucketWidth = config.Window
+		config.Buckets = 1
+	}
+	return &Scorecard{
+		config:      config,
+		bucketWidth: bucketWidth,
+		now:         time.Now,
+		buckets:     make([]scorecardBucket, config.Buckets),
+	}
+}
+
+// Scores returns every provider's score over the full window, sorted by
+// provider name.
+func (s *Scorecard) Scores() []ProviderScore {
+	return s.ScoresWithin(s.config.Window)
+}
+
+// ScoresWithin returns every provider's score over the most recent window,
+// rounded up to whole buckets and capped at the configured Window.
+func (s *Scorecard) ScoresWithin(window time.Duration) []ProviderScore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	window = s.clampWindow(window)
+	cutoff := s.now().Truncate(s.bucketWidth).Add(-window)
+	byProvider := make(map[string]*ProviderScore)
+	latencies := make(map[string][]time.Duration)
+	for _, bucket := range s.buckets {
+		if !bucket.start.After(cutoff) {
+			continue
+		}
+		for provider, tally := range bucket.providers {
+			score, ok := byProvider[provider]
+			if !ok {
+				score = &ProviderScore{Provider: provider, Window: window, ErrorMix: make(map[types.ErrorClass]int64)}
+				byProvider[provider] = score
+			}
+			score.Requests += tally.requests
+			score.Errors += tally.errors
+			for class, n := range tally.errorMix {
+				score.ErrorMix[class] += n
+			}
+			score.InputTokens += tally.inputTokens
+			score.OutputTokens += tally.outputTokens
+			score.Cost += tally.cost
+			score.PricedTokens += tally.pricedTokens
+			latencies[provider] = append(latencies[provider], tally.latencies...)
+		}
+	}
+
+	scores := make([]ProviderScore, 0, len(byProvider))
+	for provider, score := range byProvider {
+		samples := latencies[provider]
+		slices.Sort(samples)
+		score.P50Latency = percentile(samples, 0.50)
+		score.P95Latency = percentile(samples, 0.95)
+		scores = append(scores, *score)
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Provider < scores[j].Provider })
+	return scores
+}
+
+// Score returns provider's score over the full window. It reports false when
+// the provider served no request within the window.
+func (s *Scorecard) Score(provider string) (ProviderScore, bool) {
+	for _, score := range s.Scores() {
+		if score.Provider == provider {
+			return score, true
+		}
+	}
+	return ProviderScore{}, false
+}
+
+// clampWindow rounds window up to whole buckets within the configured Window.
+func (s *Scorecard) clampWindow(window time.Duration) time.Duration {
+	if window <= 0 || window > s.config.Window {
+		return s.config.Window
+	}
+	buckets := (window + s.bucketWidth - 1) / s.bucketWidth
+	return buckets * s.bucketWidth
+}
+
+// percentile returns the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.999999) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+// observe records one completed request.
+func (s *Scorecard) observe(ctx context.Context, model string, usage *types.Usage, latency time.Duration, err error) {
+	provider, _ := ctx.Value(middleware.CtxKeyProvider).(string)
+	if provider == "" || errors.Is(err, context.Canceled) {
+		return
+	}
+	// Priced before locking: a read-through registry may fetch on lookup.
+	var cost float64
+	if err == nil && usage != nil {
+		models := s.models
+		if models == nil {
+			models = types.DefaultModelRegistry
+		}
+		cost, _ = models.EstimateCost(model, usage.PromptTokens, usage.CompletionTokens)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tally := s.currentTally(provider)
+	tally.requests++
+	if err != nil {
+		tally.errors++
+		if tally.errorMix == nil {
+			tally.errorMix = make(map[types.ErrorClass]int64)
+		}
+		tally.errorMix[types.ClassifyError(err)]++
+		return
+	}
+
+	tally.successes++
+	if len(tally.latencies) < scorecardLatencySamples {
+		tally.latencies = append(tally.latencies, latency)
+	} else if i := rand.Int64N(tally.successes); i < scorecardLatencySamples {
+		tally.latencies[i] = latency
+	}
+	if usage == nil {
+		return
+	}
+	tally.inputTokens += int64(usage.PromptTokens)
+	tally.outputTokens += int64(usage.CompletionTokens)
+	if cost > 0 {
+		tally.cost += cost
+		tally.pricedTokens += int64(usage.PromptTokens + usage.CompletionTokens)
+	}
+}
+
+// currentTally returns provider's tally in the current bucket, resetting
+// the bucket when it has rolled over. s.mu must be held.
+func (s *Scorecard) currentTally(provider string) *scorecardTally {
+	start := s.now().Truncate(s.bucketWidth)
+	bucket := &s.buckets[(start.UnixNano()/int64(s.bucketWidth))%int64(len(s.buckets))]
+	if !bucket.start.Equal(start) {
+		*bucket = scorecardBucket{start: start, providers: make(map[string]*scorecardTally)}
+	}
+	tally, ok := bucket.providers[provider]
+	if !ok {
+		tally = &scorecardTally{}
+		bucket.providers[provider] = tally
+	}
+	return tally
+}
+
+// scorecardMiddleware feeds text and structured request outcomes to a
+// Scorecard.
+type scorecardMiddleware struct {
+	card *Scorecard
+}
+
+func (m scorecardMiddleware) ApplyText(next types.TextHandler) types.TextHandler {
+	return func(ctx context.Context, request types.TextRequest) (*types.TextResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, request)
+		model, usage := request.Model, (*types.Usage)(nil)
+		if resp != nil {
+			model, usage = cmp.Or(resp.Model, model), resp.Usage
+		}
+		m.card.observe(ctx, model, usage, time.Since(start), err)
+		return resp, err
+	}
+}
+
+func (m scorecardMiddleware) ApplyStream(next types.StreamHandler) types.StreamHandler {
+	return next
+}
+
+func (m scorecardMiddleware) ApplyStructured(next types.StructuredHandler) types.StructuredHandler {
+	return func(ctx context.Context, request types.StructuredRequest) (*types.StructuredResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, request)
+		model, usage := request.Model, (*types.Usage)(nil)
+		if resp != nil {
+			model, usage = cmp.Or(resp.Model, model), resp.Usage
+		}
+		m.card.observe(ctx, model, usage, time.Since(start), err)
+		return resp, err
+	}
+}
+
+func (m scorecardMiddleware) ApplyEmbeddings(next types.EmbeddingsHandler) types.EmbeddingsHandler {
+	return next
+}
+
+func (m scorecardMiddleware) ApplyAudio(next types.AudioHandler) types.AudioHandler {
+	return next
+}
+
+func (m scorecardMiddleware) ApplyImage(next types.ImageHandler) types.ImageHandler {
+	return next
+}
+
+func (m scorecardMiddleware) ApplyRerank(next types.RerankHandler) types.RerankHandler {
+	return next
+}
+
+// Scorecard returns the client's provider scorecard, or nil without
+// WithScorecard.
+func (p *Wormhole) Scorecard() *Scorecard {
+	return p.scorecard
+}
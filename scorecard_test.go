@@ -0,0 +1,121 @@
+package wormhole
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestScorecardAggregatesProviderOutcomes(t *testing.T) {
+	t.Parallel()
+
+	good := mocktesting.NewMockProvider("good").WithTextResponse(types.TextResponse{
+		Text:  "ok",
+		Usage: &types.Usage{PromptTokens: 4, CompletionTokens: 1, TotalTokens: 5},
+	})
+	bad := mocktesting.NewMockProvider("bad").WithError("boom")
+	client := New(
+		WithDefaultProvider("good"),
+		WithCustomProvider("good", mocktesting.MockProviderFactory(good)),
+		WithProviderConfig("good", types.ProviderConfig{}),
+		WithCustomProvider("bad", mocktesting.MockProviderFactory(bad)),
+		WithProviderConfig("bad", types.ProviderConfig{}),
+		WithDiscovery(false),
+		WithModelValidation(false),
+		WithScorecard(ScorecardConfig{}),
+	)
+	defer client.Close()
+
+	for range 3 {
+		_, err := client.Text().Model("m").Prompt("hi").Generate(context.Background())
+		require.NoError(t, err)
+	}
+	_, err := client.Text().Using("bad").Model("m").Prompt("hi").Generate(context.Background())
+	require.Error(t, err)
+
+	scores := client.Scorecard().Scores()
+	require.Len(t, scores, 2)
+	assert.Equal(t, "bad", scores[0].Provider)
+	assert.Equal(t, int64(1), scores[0].Errors)
+	assert.Zero(t, scores[0].SuccessRate())
+	assert.Equal(t, int64(1), scores[0].ErrorMix[types.ClassifyError(err)])
+	assert.Zero(t, scores[0].P95Latency, "failures carry no latency")
+
+	score, ok := client.Scorecard().Score("good")
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Minute, score.Window)
+	assert.Equal(t, int64(3), score.Requests)
+	assert.Equal(t, 1.0, score.SuccessRate())
+	assert.Equal(t, int64(12), score.InputTokens)
+	assert.Equal(t, int64(3), score.OutputTokens)
+	assert.Zero(t, score.CostPer1KTokens(), "unpriced models carry no cost")
+	assert.Positive(t, score.P95Latency)
+}
+
+func TestScorecardRollingWindow(t *testing.T) {
+	t.Parallel()
+
+	card := newScorecard(ScorecardConfig{Window: time.Minute, Buckets: 6})
+	now := time.Unix(1_700_000_000, 0)
+	card.now = func() time.Time { return now }
+	ctx := context.WithValue(context.Background(), middleware.CtxKeyProvider, "p")
+
+	for i := range 20 {
+		card.observe(ctx, "m", nil, time.Duration(i+1)*time.Millisecond, nil)
+	}
+	now = now.Add(30 * time.Second)
+	card.observe(ctx, "m", nil, 0, types.ErrRateLimited)
+	card.observe(ctx, "m", nil, 0, context.Canceled)
+
+	score, ok := card.Score("p")
+	require.True(t, ok)
+	assert.Equal(t, int64(21), score.Requests)
+	assert.Equal(t, 10*time.Millisecond, score.P50Latency)
+	assert.Equal(t, 19*time.Millisecond, score.P95Latency)
+	assert.Equal(t, int64(1), score.ErrorMix[types.ErrorClassRateLimit])
+
+	recent := card.ScoresWithin(15 * time.Second)
+	require.Len(t, recent, 1)
+	assert.Equal(t, 20*time.Second, recent[0].Window, "windows round up to whole buckets")
+	assert.Equal(t, int64(1), recent[0].Requests)
+
+	now = now.Add(time.Minute)
+	_, ok = card.Score("p")
+	assert.False(t, ok, "every bucket has aged out")
+}
+
+func TestScorecardPricesRegisteredModels(t *testing.T) {
+	registry := types.NewModelRegistry()
+	registry.Register(&types.ModelInfo{
+		ID:   "priced",
+		Cost: &types.ModelCost{InputTokens: 1, OutputTokens: 2, Currency: "USD"},
+	})
+
+	card := newScorecard(ScorecardConfig{})
+	card.models = registry
+	ctx := context.WithValue(context.Background(), middleware.CtxKeyProvider, "p")
+	card.observe(ctx, "priced", &types.Usage{PromptTokens: 400, CompletionTokens: 100}, time.Millisecond, nil)
+	card.observe(ctx, "unpriced", &types.Usage{PromptTokens: 1000, CompletionTokens: 1000}, time.Millisecond, nil)
+
+	score, ok := card.Score("p")
+	require.True(t, ok)
+	assert.Equal(t, int64(1400), score.InputTokens)
+	assert.Equal(t, int64(500), score.PricedTokens)
+	assert.InDelta(t, 0.6, score.Cost, 1e-9)
+	assert.InDelta(t, 1.2, score.CostPer1KTokens(), 1e-9)
+}
+
+func TestScorecardDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	client := New(WithDiscovery(false))
+	defer client.Close()
+	assert.Nil(t, client.Scorecard())
+}
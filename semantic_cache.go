@@ -0,0 +1,36 @@
+package wormhole
+
+import (
+	"context"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+)
+
+// Embedder returns a middleware.Embedder backed by this client's embeddings
+// API, for middleware.SemanticCacheMiddleware. An empty provider uses the
+// default provider. An empty vector makes the cache skip the request.
+//
+// Example:
+//
+//	embeddings := wormhole.New(wormhole.WithOpenAI(key))
+//	client := wormhole.New(
+//		wormhole.WithOpenAI(key),
+//		wormhole.WithMiddleware(middleware.SemanticCacheMiddleware(middleware.SemanticCacheConfig{
+//			Embed:     embeddings.Embedder("openai", "text-embedding-3-small"),
+//			Threshold: 0.92,
+//			TTL:       time.Hour,
+//		})),
+//	)
+func (p *Wormhole) Embedder(provider, model string) middleware.Embedder {
+	return func(ctx context.Context, text string) ([]float64, error) {
+		builder := p.Embeddings().Model(model).Input(text)
+		if provider != "" {
+			builder = builder.Using(provider)
+		}
+		resp, err := builder.Generate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Content(), nil
+	}
+}
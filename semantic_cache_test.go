@@ -0,0 +1,48 @@
+package wormhole_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestSemanticCacheWithClientEmbedder(t *testing.T) {
+	t.Parallel()
+
+	embeddings := wormhole.New(
+		wormhole.WithDefaultProvider("vectors"),
+		wormhole.WithCustomProvider("vectors", func(types.ProviderConfig) (types.Provider, error) {
+			return mocktesting.NewMockProvider("vectors").WithEmbeddings([]types.Embedding{{Embedding: []float64{0.6, 0.8}}}), nil
+		}),
+		wormhole.WithProviderConfig("vectors", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+	)
+	defer embeddings.Close()
+
+	provider := &scriptedProvider{
+		MockProvider: mocktesting.NewMockProvider("scripted"),
+		replies:      map[string][]string{"small": {"Paris", "Lyon"}},
+	}
+	client := newValidationClient(provider, wormhole.WithMiddleware(middleware.SemanticCacheMiddleware(middleware.SemanticCacheConfig{
+		Embed: embeddings.Embedder("", "embed-small"),
+	})))
+	defer client.Close()
+
+	ctx := context.Background()
+	first, err := client.Text().Model("small").Prompt("What is the capital of France?").Generate(ctx)
+	require.NoError(t, err)
+	second, err := client.Text().Model("small").Prompt("France's capital city?").Generate(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Paris", first.Text)
+	assert.Equal(t, "Paris", second.Text)
+	assert.InDelta(t, 1.0, second.Metadata[middleware.SemanticCacheScoreKey], 1e-9)
+	assert.Equal(t, []string{"small"}, provider.calls)
+}
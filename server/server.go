@@ -0,0 +1,61 @@
+// Package server exposes a configured Wormhole client as an OpenAI-compatible
+// HTTP gateway.
+//
+// It serves the same routes as the `wormhole serve` proxy — chat completions
+// (including SSE streaming), responses, embeddings, rerank, and model listing —
+// but runs every request through the caller's client, so its middleware,
+// provider routing, and fallbacks apply. Services and UIs that speak the
+// OpenAI API (for example LibreChat) can point at the gateway unchanged.
+//
+// Example:
+//
+//	client := wormhole.New(
+//	    wormhole.WithOpenAI(os.Getenv("OPENAI_API_KEY")),
+//	    wormhole.WithAnthropic(os.Getenv("ANTHROPIC_API_KEY")),
+//	    wormhole.WithDefaultProvider("openai"),
+//	)
+//	defer client.Close()
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/", server.Handler(client, server.Config{APIKey: os.Getenv("GATEWAY_KEY")}))
+//	log.Fatal(http.ListenAndServe("127.0.0.1:8080", mux))
+package server
+
+import (
+	"log/slog"
+	"net/http"
+
+	wormhole "github.com/garyblankenship/wormhole/v2"
+	internalserver "github.com/garyblankenship/wormhole/v2/internal/server"
+)
+
+// Config controls how the gateway serves a client.
+type Config struct {
+	// DefaultProvider routes models without a "provider/" prefix. When empty,
+	// the client's single configured provider (if exactly one) is used.
+	DefaultProvider string
+	// APIKey, when set, is required as a Bearer token on every /v1/ route.
+	// When empty, /v1/ routes only answer clients on a loopback address, so a
+	// handler mounted on a public listener does not spend provider credits
+	// for anyone who can reach it.
+	APIKey string
+	// AllowUnauthenticated serves /v1/ routes to every client when APIKey is
+	// empty, for deployments that authenticate in front of the handler.
+	AllowUnauthenticated bool
+	// Logger receives request and error logs. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// Handler returns an http.Handler serving client over the OpenAI-compatible
+// API. The caller keeps ownership of client and must close it when done;
+// the handler holds no other resources.
+func Handler(client *wormhole.Wormhole, cfg Config) http.Handler {
+	return internalserver.New(internalserver.Config{
+		Client:          client,
+		DefaultProvider: cfg.DefaultProvider,
+		ProxyAPIKey:     cfg.APIKey,
+		APIKeySetting:   "server.Config.APIKey",
+		LoopbackOnly:    !cfg.AllowUnauthenticated,
+		Logger:          cfg.Logger,
+	}).Handler()
+}
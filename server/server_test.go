@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wormhole "github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	wmtest "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func newTestClient(mock *wmtest.MockProvider) *wormhole.Wormhole {
+	return wormhole.New(
+		wormhole.WithCustomProvider("openai", wmtest.MockProviderFactory(mock)),
+		wormhole.WithProviderConfig("openai", types.ProviderConfig{}),
+		wormhole.WithDefaultProvider("openai"),
+		wormhole.WithDiscovery(false),
+	)
+}
+
+func quietConfig() Config {
+	return Config{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+}
+
+func post(h http.Handler, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.RemoteAddr = "127.0.0.1:40000"
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerServesChatCompletions(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(wmtest.NewMockProvider("openai").WithTextResponse(wmtest.TextResponseWith("pong")))
+	defer client.Close()
+
+	rec := post(Handler(client, quietConfig()), "/v1/chat/completions",
+		`{"model":"gpt-test","messages":[{"role":"user","content":"ping"}]}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var out struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+	require.Len(t, out.Choices, 1)
+	assert.Equal(t, "pong", out.Choices[0].Message.Content)
+}
+
+func TestHandlerStreamsServerSentEvents(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(wmtest.NewMockProvider("openai").WithStreamChunks(wmtest.StreamChunksFrom("hello", " world")))
+	defer client.Close()
+
+	rec := post(Handler(client, quietConfig()), "/v1/chat/completions",
+		`{"model":"gpt-test","stream":true,"messages":[{"role":"user","content":"hi"}]}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "world")
+	assert.Contains(t, rec.Body.String(), "data: [DONE]")
+}
+
+func TestHandlerRequiresAPIKeyWhenConfigured(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(wmtest.NewMockProvider("openai").WithTextResponse(wmtest.TextResponseWith("pong")))
+	defer client.Close()
+
+	cfg := quietConfig()
+	cfg.APIKey = "secret"
+	h := Handler(client, cfg)
+	body := `{"model":"gpt-test","messages":[{"role":"user","content":"ping"}]}`
+
+	assert.Equal(t, http.StatusUnauthorized, post(h, "/v1/chat/completions", body).Code)
+	assert.Equal(t, http.StatusOK, post(h, "/v1/chat/completions", body, "Authorization", "Bearer secret").Code)
+}
+
+func TestHandlerWithoutAPIKeyServesOnlyLoopbackClients(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(wmtest.NewMockProvider("openai").WithTextResponse(wmtest.TextResponseWith("pong")))
+	defer client.Close()
+
+	body := `{"model":"gpt-test","messages":[{"role":"user","content":"ping"}]}`
+	remote := func(h http.Handler) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body))
+		req.RemoteAddr = "203.0.113.7:40000"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	h := Handler(client, quietConfig())
+	assert.Equal(t, http.StatusForbidden, remote(h))
+	assert.Equal(t, http.StatusOK, post(h, "/v1/chat/completions", body).Code)
+
+	cfg := quietConfig()
+	cfg.AllowUnauthenticated = true
+	assert.Equal(t, http.StatusOK, remote(Handler(client, cfg)))
+}
@@ -4,16 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/garyblankenship/wormhole/v2/internal/pool"
+	"github.com/garyblankenship/wormhole/v2/middleware"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 // StructuredRequestBuilder builds structured output requests
 type StructuredRequestBuilder struct {
 	CommonBuilder
-	request   *types.StructuredRequest
-	schemaErr error
+	request         *types.StructuredRequest
+	schemaErr       error
+	skipSchemaCheck bool // Return responses without checking them against the schema (see SkipSchemaValidation)
+	repairAttempts  int  // Re-prompts allowed after a schema mismatch (see RepairAttempts)
 }
 
 // Using sets the provider to use
@@ -60,6 +64,36 @@ func (b *StructuredRequestBuilder) SystemPrompt(prompt string) *StructuredReques
 	return b
 }
 
+// UsePrompt renders a registered prompt, as TextRequestBuilder.UsePrompt does.
+func (b *StructuredRequestBuilder) UsePrompt(ref string, vars map[string]any) *StructuredRequestBuilder {
+	prompt, system, user, ok := b.usePrompt(ref, vars)
+	if !ok {
+		return b
+	}
+	if system != "" {
+		b.request.SystemPrompt = system
+	}
+	b.request.Messages = []types.Message{types.NewUserMessage(user)}
+	if b.request.Model == "" {
+		b.request.Model = prompt.Model
+	}
+	return b
+}
+
+// Experiment assigns the request a variant of the named experiment, as
+// TextRequestBuilder.Experiment does.
+func (b *StructuredRequestBuilder) Experiment(name, key string, vars map[string]any) *StructuredRequestBuilder {
+	variant, ok := b.useExperiment(name, key)
+	if !ok {
+		return b
+	}
+	if variant.Prompt != "" {
+		b.UsePrompt(variant.Prompt, vars)
+	}
+	applyExperimentVariant(&b.request.BaseRequest, variant)
+	return b
+}
+
 // Schema sets the JSON schema for the response
 func (b *StructuredRequestBuilder) Schema(schema any) *StructuredRequestBuilder {
 	schemaBytes, err := pool.Marshal(schema)
@@ -91,6 +125,22 @@ func (b *StructuredRequestBuilder) Mode(mode types.StructuredMode) *StructuredRe
 	return b
 }
 
+// Strict turns strict JSON schema decoding on or off. When on, the provider
+// constrains generation to the schema: OpenAI uses a json_schema
+// response_format with strict:true and Gemini its responseSchema. The schema
+// is rewritten to fit strict-mode rules first (see types.StrictSchema), so
+// optional properties come back as null rather than missing. Turning it off
+// restores the default mode.
+func (b *StructuredRequestBuilder) Strict(enabled bool) *StructuredRequestBuilder {
+	switch {
+	case enabled:
+		b.request.Mode = types.StructuredModeStrict
+	case b.request.Mode == types.StructuredModeStrict:
+		b.request.Mode = ""
+	}
+	return b
+}
+
 // Temperature sets the temperature
 func (b *StructuredRequestBuilder) Temperature(temp float32) *StructuredRequestBuilder {
 	b.request.Temperature = &temp
@@ -103,13 +153,90 @@ func (b *StructuredRequestBuilder) MaxTokens(tokens int) *StructuredRequestBuild
 	return b
 }
 
+// ProviderOptions sets provider-specific options
+func (b *StructuredRequestBuilder) ProviderOptions(options map[string]any) *StructuredRequestBuilder {
+	b.request.ProviderOptions = types.CloneMap(options)
+	return b
+}
+
+// ProviderOption sets one provider-specific option sent only to provider, as
+// TextRequestBuilder.ProviderOption does.
+func (b *StructuredRequestBuilder) ProviderOption(provider, key string, value any) *StructuredRequestBuilder {
+	b.setProviderOption(provider, key, value)
+	return b
+}
+
+// Metadata tags the request for metrics and logging, as
+// TextRequestBuilder.Metadata does.
+func (b *StructuredRequestBuilder) Metadata(tags map[string]string) *StructuredRequestBuilder {
+	b.addTags(tags)
+	return b
+}
+
+// Priority sets the request's scheduling priority, as
+// TextRequestBuilder.Priority does.
+func (b *StructuredRequestBuilder) Priority(p Priority) *StructuredRequestBuilder {
+	b.setPriority(p)
+	return b
+}
+
+// Cache overrides CacheMiddleware's TTL for this request, as
+// TextRequestBuilder.Cache does.
+func (b *StructuredRequestBuilder) Cache(ttl time.Duration) *StructuredRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{TTL: ttl})
+	return b
+}
+
+// NoCache sends the request past the cache middleware, as
+// TextRequestBuilder.NoCache does.
+func (b *StructuredRequestBuilder) NoCache() *StructuredRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{Bypass: true})
+	return b
+}
+
+// CacheKey returns the key CacheMiddleware uses for this request, as
+// TextRequestBuilder.CacheKey does.
+func (b *StructuredRequestBuilder) CacheKey() (string, error) {
+	for _, err := range []error{b.schemaErr, b.promptErr, b.experimentErr} {
+		if err != nil {
+			return "", err
+		}
+	}
+	request := cloneStructuredRequest(b.request)
+	request.Model = b.resolveModel(request.Model)
+	prepareStructuredExecutionRequest(request)
+	return b.cacheKey(func(provider types.Provider) any {
+		scoped := *request
+		scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
+		return scoped
+	})
+}
+
 // Generate executes the request and returns a structured response
 func (b *StructuredRequestBuilder) Generate(ctx context.Context) (*types.StructuredResponse, error) {
+	start := time.Now()
+	resp, err := b.generate(b.taggedContext(ctx))
+	var usage *types.Usage
+	if resp != nil {
+		usage = resp.Usage
+	}
+	b.recordExperiment(start, usage, err)
+	return resp, err
+}
+
+func (b *StructuredRequestBuilder) generate(ctx context.Context) (*types.StructuredResponse, error) {
 	if b.schemaErr != nil {
 		return nil, b.schemaErr
 	}
+	if b.promptErr != nil {
+		return nil, b.promptErr
+	}
+	if b.experimentErr != nil {
+		return nil, b.experimentErr
+	}
 
 	request := cloneStructuredRequest(b.request)
+	request.Model = b.resolveModel(request.Model)
 	prepareStructuredExecutionRequest(request)
 
 	if len(request.Messages) == 0 {
@@ -121,10 +248,19 @@ func (b *StructuredRequestBuilder) Generate(ctx context.Context) (*types.Structu
 	if request.Schema == nil {
 		return nil, fmt.Errorf("no schema provided")
 	}
-	if err := b.getWormhole().validateModelAttempt(b.getProvider(), request.Model, nil, []types.ModelCapability{types.CapabilityStructured}); err != nil {
+	if err := b.getWormhole().validateModelAttempt(b.getProvider(), request.Model, nil, structuredRequiredCapabilities(request)); err != nil {
 		return nil, err
 	}
 
+	resp, err := b.generateOnce(ctx, request)
+	if err != nil || b.skipSchemaCheck {
+		return resp, err
+	}
+	return b.checkAndRepair(ctx, request, resp)
+}
+
+// generateOnce sends one prepared request through the provider.
+func (b *StructuredRequestBuilder) generateOnce(ctx context.Context, request *types.StructuredRequest) (*types.StructuredResponse, error) {
 	return executeTrackedRequest(ctx, b.getWormhole(), b.idempotencyScope("structured.generate"), request, func(ctx context.Context) (*types.StructuredResponse, error) {
 		provider, release, err := b.getProviderWithBaseURL()
 		if err != nil {
@@ -133,12 +269,14 @@ func (b *StructuredRequestBuilder) Generate(ctx context.Context) (*types.Structu
 		defer release()
 
 		ctx = contextWithProviderOperation(ctx, provider, "structured")
+		scoped := *request
+		scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
 		if b.getWormhole().providerMiddleware != nil {
 			handler := b.getWormhole().providerMiddleware.ApplyStructured(provider.Structured)
-			return handler(ctx, *request)
+			return handler(ctx, scoped)
 		}
 
-		return provider.Structured(ctx, *request)
+		return provider.Structured(ctx, scoped)
 	})
 }
 
@@ -171,6 +309,8 @@ func (b *StructuredRequestBuilder) GenerateAs(ctx context.Context, result any) e
 //   - Schema is provided
 //   - Temperature is in valid range (0.0-2.0) if specified
 //   - MaxTokens is positive if specified
+//   - With model validation on, the model supports structured output, and
+//     strict schema decoding in strict mode
 //
 // Example:
 //
@@ -200,7 +340,23 @@ func (b *StructuredRequestBuilder) Validate() error {
 		errs.Add("max_tokens", "positive", *b.request.MaxTokens, "must be a positive integer")
 	}
 
-	return errs.Error()
+	if err := errs.Error(); err != nil {
+		return err
+	}
+	if wormhole := b.getWormhole(); wormhole != nil {
+		return wormhole.validateModelAttempt(b.getProvider(), b.resolveModel(b.request.Model), nil, structuredRequiredCapabilities(b.request))
+	}
+	return nil
+}
+
+// structuredRequiredCapabilities lists what the model must support for
+// request: structured output, plus strict schema decoding in strict mode.
+func structuredRequiredCapabilities(request *types.StructuredRequest) []types.ModelCapability {
+	required := []types.ModelCapability{types.CapabilityStructured}
+	if request.Mode == types.StructuredModeStrict {
+		required = append(required, types.CapabilityJSONSchemaStrict)
+	}
+	return required
 }
 
 // MustValidate calls Validate() and panics if validation fails.
@@ -211,6 +367,43 @@ func (b *StructuredRequestBuilder) MustValidate() *StructuredRequestBuilder {
 	return b
 }
 
+// Clone creates a deep copy of the builder with all settings preserved,
+// including its schema. Any number of goroutines may Clone a configured
+// builder at once, provided none of them modifies it.
+//
+// Example:
+//
+//	extract := client.Structured().Model("gpt-5-mini").Schema(invoiceSchema).Strict(true)
+//	var invoice Invoice
+//	err := extract.Clone().Prompt(doc).GenerateAs(ctx, &invoice)
+func (b *StructuredRequestBuilder) Clone() *StructuredRequestBuilder {
+	return &StructuredRequestBuilder{
+		CommonBuilder: CommonBuilder{
+			wormhole:      b.wormhole,
+			provider:      b.provider,
+			baseURL:       b.baseURL,
+			tags:          b.tags,
+			priority:      b.priority,
+			cache:         b.cache,
+			scopedOptions: b.scopedOptions,
+
+			promptErr:     b.promptErr,
+			experiment:    b.experiment,
+			experimentErr: b.experimentErr,
+		},
+		request:         cloneStructuredRequest(b.request),
+		schemaErr:       b.schemaErr,
+		skipSchemaCheck: b.skipSchemaCheck,
+		repairAttempts:  b.repairAttempts,
+	}
+}
+
+// Request returns a copy of the request configured so far. Save it and pass
+// it to Wormhole.StructuredFrom to start new builders from the same settings.
+func (b *StructuredRequestBuilder) Request() types.StructuredRequest {
+	return *cloneStructuredRequest(b.request)
+}
+
 func cloneStructuredRequest(src *types.StructuredRequest) *types.StructuredRequest {
 	if src == nil {
 		return &types.StructuredRequest{}
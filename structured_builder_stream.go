@@ -0,0 +1,342 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/garyblankenship/wormhole/v2/internal/partialjson"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// Stream executes the request as a text stream constrained to the schema and
+// emits progressively decoded partial objects as JSON arrives, so callers can
+// render structured results before generation finishes.
+//
+// The request is sent with a JSON response_format (json_schema, or json_object
+// in StructuredModeJSON), so it requires a provider that honors response_format
+// while streaming, such as OpenAI and OpenAI-compatible endpoints. Anthropic,
+// Gemini and Hugging Face, and Ollama outside StructuredModeJSON, would stream
+// unconstrained text, so Stream returns types.ErrStreamSchemaUnsupported for
+// them instead of starting the request. A chunk is
+// emitted only when the decoded partial value changes. The final chunk has
+// Done and Complete set and carries the fully decoded document. If the stream
+// fails, disconnects, or ends before the document is valid JSON, the last
+// chunk carries the error and the best-effort partial object, with Complete
+// false.
+//
+// Example:
+//
+//	stream, err := client.Structured().
+//	    Model("gpt-5.2").
+//	    Schema(recipeSchema).
+//	    Prompt("Invent a recipe").
+//	    Stream(ctx)
+//	for chunk := range stream {
+//	    if chunk.Error != nil {
+//	        return chunk.Error
+//	    }
+//	    render(chunk.Partial)
+//	}
+func (b *StructuredRequestBuilder) Stream(ctx context.Context) (<-chan types.StructuredChunk, error) {
+	textStream, err := b.streamText(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var check func(any) error
+	if !b.skipSchemaCheck {
+		if schema, err := structuredCheckSchema(b.request); err == nil {
+			strict := b.request.Mode == types.StructuredModeStrict
+			check = func(data any) error { return schemaMismatch(data, schema, strict, 1) }
+		}
+	}
+	out := make(chan types.StructuredChunk)
+	go forwardStructuredStream(ctx, textStream, out, check)
+	return out, nil
+}
+
+// StreamAs runs Stream to the end and decodes the result into out. If the
+// stream fails or disconnects midway, out holds the fields parsed so far and
+// complete is false alongside the error, so long extraction jobs over flaky
+// networks keep the progress they made.
+//
+// Example:
+//
+//	var report Report
+//	complete, err := client.Structured().
+//	    Model("gpt-5.2").
+//	    Schema(reportSchema).
+//	    Prompt(longDocument).
+//	    StreamAs(ctx, &report)
+//	if err != nil && !complete {
+//	    savePartial(report)
+//	}
+func (b *StructuredRequestBuilder) StreamAs(ctx context.Context, out any) (complete bool, err error) {
+	stream, err := b.Stream(ctx)
+	if err != nil {
+		return false, err
+	}
+	var last types.StructuredChunk
+	for chunk := range stream {
+		last = chunk
+	}
+	if decodeErr := last.ContentAs(out); decodeErr != nil && last.Error == nil {
+		return false, fmt.Errorf("failed to decode structured stream: %w", decodeErr)
+	}
+	if last.Error != nil {
+		return false, last.Error
+	}
+	if !last.Done {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return false, ctxErr
+		}
+		return false, types.NewWormholeError(types.ErrorCodeProvider, "structured stream ended without a final chunk", false)
+	}
+	return last.Complete, nil
+}
+
+// StreamItems executes the request like Stream but yields each element of the
+// generated JSON array as soon as it is complete, so bulk generation ("write
+// 100 product descriptions") can feed downstream work while the model is still
+// writing. The array is the document root or, for an object schema, its first
+// array-valued property; wrap the array in an object ({"items": [...]}) for
+// providers whose strict schema mode requires an object root.
+//
+// Items carry sequential indexes. The final item has Done set; if the stream
+// ended before the array closed, it carries an error instead, after any
+// elements that did complete.
+//
+// Example:
+//
+//	items, err := client.Structured().
+//	    Model("gpt-5.2").
+//	    Schema(productListSchema).
+//	    Prompt("Write 100 product descriptions").
+//	    StreamItems(ctx)
+//	for item := range items {
+//	    if item.Error != nil {
+//	        return item.Error
+//	    }
+//	    var product Product
+//	    if err := item.ContentAs(&product); err == nil {
+//	        queue <- product
+//	    }
+//	}
+func (b *StructuredRequestBuilder) StreamItems(ctx context.Context) (<-chan types.StructuredItem, error) {
+	textStream, err := b.streamText(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan types.StructuredItem)
+	go forwardStructuredItems(ctx, textStream, out)
+	return out, nil
+}
+
+// streamText validates the request and starts the underlying text stream with
+// a JSON response_format and tool execution disabled.
+func (b *StructuredRequestBuilder) streamText(ctx context.Context) (<-chan types.StreamChunk, error) {
+	if b.schemaErr != nil {
+		return nil, b.schemaErr
+	}
+	if b.promptErr != nil {
+		return nil, b.promptErr
+	}
+	if b.experimentErr != nil {
+		return nil, b.experimentErr
+	}
+	if b.request.Schema == nil {
+		return nil, fmt.Errorf("no schema provided")
+	}
+	model := b.resolveModel(b.request.Model)
+	if err := b.getWormhole().validateModelAttempt(b.getProvider(), model, nil, structuredRequiredCapabilities(b.request)); err != nil {
+		return nil, err
+	}
+
+	if err := b.checkStreamSchemaSupport(); err != nil {
+		return nil, err
+	}
+
+	responseFormat, err := structuredStreamResponseFormat(b.request)
+	if err != nil {
+		return nil, err
+	}
+
+	source := cloneStructuredRequest(b.request)
+	source.Model = model
+	disabled := false
+	textBuilder := &TextRequestBuilder{
+		CommonBuilder: b.CommonBuilder,
+		request: &types.TextRequest{
+			BaseRequest:    source.BaseRequest,
+			Messages:       source.Messages,
+			SystemPrompt:   source.SystemPrompt,
+			ResponseFormat: responseFormat,
+		},
+		toolExecutionOverride: &disabled,
+		modelResolved:         true,
+	}
+	return textBuilder.Stream(ctx)
+}
+
+// checkStreamSchemaSupport fails fast when the builder's provider ignores the
+// response_format streamText relies on, rather than letting the stream run
+// unconstrained.
+func (b *StructuredRequestBuilder) checkStreamSchemaSupport() error {
+	provider, release, err := b.getWormhole().leaseProvider(b.getProvider())
+	if err != nil {
+		return err
+	}
+	name := provider.Name()
+	release()
+	switch name {
+	case "anthropic", "gemini", "huggingface":
+	case "ollama":
+		// Ollama maps json_object to its JSON mode but has no json_schema.
+		if b.request.Mode == types.StructuredModeJSON {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w: %s ignores response_format when streaming; use Generate for schema-constrained output", types.ErrStreamSchemaUnsupported, name)
+}
+
+func structuredStreamResponseFormat(request *types.StructuredRequest) (any, error) {
+	if request.Mode == types.StructuredModeJSON {
+		return map[string]string{"type": "json_object"}, nil
+	}
+	schema, err := structuredSchemaMap(request.Schema)
+	if err != nil {
+		return nil, err
+	}
+	strict := request.Mode == types.StructuredModeStrict
+	if strict {
+		schema, _ = types.StrictSchema(schema)
+	}
+	name := request.SchemaName
+	if name == "" {
+		name = "structured_output"
+	}
+	return map[string]any{
+		"type": "json_schema",
+		"json_schema": map[string]any{
+			"name":   name,
+			"strict": strict,
+			"schema": schema,
+		},
+	}, nil
+}
+
+// forwardStructuredStream accumulates text deltas, re-parses the document after
+// each one, and forwards a chunk whenever the recovered value changes.
+func forwardStructuredStream(ctx context.Context, in <-chan types.StreamChunk, out chan<- types.StructuredChunk, check func(any) error) {
+	defer close(out)
+
+	send := func(chunk types.StructuredChunk) bool {
+		select {
+		case out <- chunk:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var raw strings.Builder
+	var lastRepaired string
+	var lastPartial any
+	var usage *types.Usage
+	for chunk := range in {
+		if chunk.Error != nil {
+			send(types.StructuredChunk{Partial: lastPartial, Raw: raw.String(), Usage: usage, Error: chunk.Error})
+			go drainStream(ctx, in)
+			return
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		delta := chunk.Content()
+		if delta == "" {
+			continue
+		}
+		raw.WriteString(delta)
+		repaired, ok := partialjson.Repair(raw.String())
+		if !ok || repaired == lastRepaired {
+			continue
+		}
+		lastRepaired = repaired
+		var partial any
+		if err := json.Unmarshal([]byte(repaired), &partial); err != nil {
+			continue
+		}
+		lastPartial = partial
+		if !send(types.StructuredChunk{Partial: partial, Raw: raw.String()}) {
+			go drainStream(ctx, in)
+			return
+		}
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	final := types.StructuredChunk{Raw: raw.String(), Done: true, Complete: true, Usage: usage}
+	if err := json.Unmarshal([]byte(final.Raw), &final.Partial); err != nil {
+		final.Partial = lastPartial
+		final.Complete = false
+		message := "failed to parse structured stream"
+		if lastPartial != nil {
+			message = "structured stream ended before the JSON document was complete"
+		}
+		final.Error = types.NewWormholeError(types.ErrorCodeProvider, message, false).WithCause(err)
+	} else if check != nil {
+		final.Error = check(final.Partial)
+	}
+	send(final)
+}
+
+// forwardStructuredItems feeds text deltas to an array scanner and forwards
+// each completed element.
+func forwardStructuredItems(ctx context.Context, in <-chan types.StreamChunk, out chan<- types.StructuredItem) {
+	defer close(out)
+
+	send := func(item types.StructuredItem) bool {
+		select {
+		case out <- item:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	scanner := partialjson.NewArrayScanner()
+	index := 0
+	var usage *types.Usage
+	for chunk := range in {
+		if chunk.Error != nil {
+			send(types.StructuredItem{Index: index, Usage: usage, Error: chunk.Error, Done: true})
+			go drainStream(ctx, in)
+			return
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, value := range scanner.Write(chunk.Content()) {
+			if !send(types.StructuredItem{Index: index, Value: value}) {
+				go drainStream(ctx, in)
+				return
+			}
+			index++
+		}
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	final := types.StructuredItem{Index: index, Done: true, Usage: usage}
+	if !scanner.Done() {
+		final.Error = types.NewWormholeError(types.ErrorCodeProvider, "structured stream ended before the JSON array closed", false)
+	}
+	send(final)
+}
@@ -0,0 +1,168 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/garyblankenship/wormhole/v2/internal/schemavalidation"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// SkipSchemaValidation returns responses as the provider parsed them, without
+// checking them against the schema. By default Generate and Stream reject a
+// response that parses but does not match with a *types.SchemaValidationError.
+func (b *StructuredRequestBuilder) SkipSchemaValidation() *StructuredRequestBuilder {
+	b.skipSchemaCheck = true
+	return b
+}
+
+// RepairAttempts lets Generate re-prompt up to n times when a response does
+// not match the schema. Each repair sends the rejected JSON back with the
+// list of violations and asks for a corrected document; the usage of every
+// attempt is summed into the final response, and Metadata["schema_repairs"]
+// records how many were needed. Zero, the default, fails on the first
+// mismatch. Streams are checked but never repaired.
+//
+// Example:
+//
+//	var invoice Invoice
+//	err := client.Structured().
+//	    Model("gpt-5-mini").
+//	    Schema(invoiceSchema).
+//	    Prompt(scan).
+//	    RepairAttempts(2).
+//	    GenerateAs(ctx, &invoice)
+//	var mismatch *types.SchemaValidationError
+//	if errors.As(err, &mismatch) {
+//	    log.Println(mismatch.Summary())
+//	}
+func (b *StructuredRequestBuilder) RepairAttempts(n int) *StructuredRequestBuilder {
+	b.repairAttempts = max(n, 0)
+	return b
+}
+
+// checkAndRepair validates resp against the request schema and, when repairs
+// are allowed, re-prompts until a response matches or they run out.
+func (b *StructuredRequestBuilder) checkAndRepair(ctx context.Context, request *types.StructuredRequest, resp *types.StructuredResponse) (*types.StructuredResponse, error) {
+	schema, err := structuredCheckSchema(request)
+	if err != nil {
+		// A schema that is not a JSON object cannot be checked here; the
+		// provider already accepted it.
+		return resp, nil
+	}
+	usage := mergeUsage(nil, resp.Usage)
+	for attempt := 1; ; attempt++ {
+		err := schemaMismatch(resp.Data, schema, request.Mode == types.StructuredModeStrict, attempt)
+		if err == nil {
+			if attempt > 1 {
+				resp.Usage = usage
+				if resp.Metadata == nil {
+					resp.Metadata = make(map[string]any)
+				}
+				resp.Metadata["schema_repairs"] = attempt - 1
+			}
+			return resp, nil
+		}
+		if attempt > b.repairAttempts {
+			return nil, err
+		}
+		request = schemaRepairRequest(request, resp, err.(*types.SchemaValidationError))
+		if resp, err = b.generateOnce(ctx, request); err != nil {
+			return nil, err
+		}
+		usage = mergeUsage(usage, resp.Usage)
+	}
+}
+
+// schemaMismatch returns a *types.SchemaValidationError when data does not
+// match schema. With nullsAsAbsent, a null optional property counts as left
+// out: strict mode makes providers that enforce it send every property, with
+// null for the optional ones they have no value for.
+func schemaMismatch(data any, schema map[string]any, nullsAsAbsent bool, attempt int) error {
+	normalized := data
+	if encoded, err := json.Marshal(data); err == nil {
+		var decoded any
+		if json.Unmarshal(encoded, &decoded) == nil {
+			normalized = decoded
+			if nullsAsAbsent {
+				dropNullOptionals(normalized, schema)
+			}
+		}
+	}
+	violations := schemavalidation.Check(normalized, schema)
+	if len(violations) == 0 {
+		return nil
+	}
+	return &types.SchemaValidationError{Violations: violations, Data: data, Attempts: attempt}
+}
+
+// schemaRepairRequest continues the conversation with the rejected response
+// and the violations it has to fix.
+func schemaRepairRequest(request *types.StructuredRequest, resp *types.StructuredResponse, mismatch *types.SchemaValidationError) *types.StructuredRequest {
+	rejected := resp.Raw
+	if rejected == "" {
+		encoded, _ := json.Marshal(resp.Data)
+		rejected = string(encoded)
+	}
+	repaired := cloneStructuredRequest(request)
+	repaired.Messages = append(repaired.Messages,
+		types.NewAssistantMessage(rejected),
+		types.NewUserMessage("That JSON does not match the required schema:\n"+mismatch.Summary()+
+			"\nReply with the corrected JSON document only."),
+	)
+	return repaired
+}
+
+// dropNullOptionals deletes, in place, the null values of properties schema
+// does not require, following properties and items down the document.
+func dropNullOptionals(data any, schema map[string]any) {
+	switch data := data.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for name, value := range data {
+			sub, _ := props[name].(map[string]any)
+			if value == nil && !slices.Contains(required, any(name)) {
+				delete(data, name)
+				continue
+			}
+			dropNullOptionals(value, sub)
+		}
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		for _, item := range data {
+			dropNullOptionals(item, items)
+		}
+	}
+}
+
+// structuredCheckSchema is the schema responses are checked against: the
+// caller's own, in every mode. Only some providers are sent the strict
+// rewrite, and the others may leave optional properties out.
+func structuredCheckSchema(request *types.StructuredRequest) (map[string]any, error) {
+	return structuredSchemaMap(request.Schema)
+}
+
+// structuredSchemaMap decodes a request schema into a JSON object.
+func structuredSchemaMap(raw types.Schema) (map[string]any, error) {
+	var schemaBytes []byte
+	switch raw := raw.(type) {
+	case []byte:
+		schemaBytes = raw
+	case json.RawMessage:
+		schemaBytes = raw
+	default:
+		marshaled, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal schema: %w", err)
+		}
+		schemaBytes = marshaled
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(schemaBytes, &schema); err != nil {
+		return nil, fmt.Errorf("schema must be a JSON object: %w", err)
+	}
+	return schema, nil
+}
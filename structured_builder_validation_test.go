@@ -0,0 +1,186 @@
+package wormhole_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+// sequenceStructuredProvider answers each Structured call with the next
+// response in line and records the requests it saw.
+type sequenceStructuredProvider struct {
+	*mocktesting.MockProvider
+	mu        sync.Mutex
+	responses []types.StructuredResponse
+	requests  []types.StructuredRequest
+}
+
+func (p *sequenceStructuredProvider) Structured(_ context.Context, request types.StructuredRequest) (*types.StructuredResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, request)
+	resp := p.responses[min(len(p.requests), len(p.responses))-1]
+	return &resp, nil
+}
+
+func newSequenceStructuredClient(provider *sequenceStructuredProvider) *wormhole.Wormhole {
+	return wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+	)
+}
+
+var personSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"name": map[string]any{"type": "string"},
+		"age":  map[string]any{"type": "integer", "minimum": 0},
+	},
+	"required":             []any{"name", "age"},
+	"additionalProperties": false,
+}
+
+func TestStructuredGenerateRejectsSchemaMismatch(t *testing.T) {
+	t.Parallel()
+
+	provider := &sequenceStructuredProvider{
+		MockProvider: mocktesting.NewMockProvider("mock"),
+		responses:    []types.StructuredResponse{{Data: map[string]any{"age": -1, "extra": true}}},
+	}
+	client := newSequenceStructuredClient(provider)
+	defer client.Close()
+
+	_, err := client.Structured().Model("gpt-5").Prompt("Describe Ada").Schema(personSchema).Generate(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, types.ErrSchemaMismatch))
+
+	var mismatch *types.SchemaValidationError
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, []string{"", "/age"}, mismatch.Paths())
+	assert.Equal(t, 1, mismatch.Attempts)
+	assert.Contains(t, mismatch.Summary(), `- /: missing required property "name"`)
+	assert.Contains(t, mismatch.Summary(), "- /age: ")
+
+	resp, err := client.Structured().Model("gpt-5").Prompt("Describe Ada").Schema(personSchema).
+		SkipSchemaValidation().Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"age": -1, "extra": true}, resp.Data)
+}
+
+func TestStructuredStrictChecksAgainstTheCallerSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+			"nick": map[string]any{"type": "string"},
+		},
+		"required": []any{"name"},
+	}
+	for _, data := range []map[string]any{
+		{"name": "Ada"},
+		{"name": "Ada", "nick": nil},
+	} {
+		provider := &sequenceStructuredProvider{
+			MockProvider: mocktesting.NewMockProvider("mock"),
+			responses:    []types.StructuredResponse{{Data: data}},
+		}
+		client := newSequenceStructuredClient(provider)
+		_, err := client.Structured().Model("gpt-5").Prompt("Describe Ada").Schema(schema).Strict(true).Generate(context.Background())
+		require.NoError(t, err, "data %v", data)
+		_ = client.Close()
+	}
+
+	provider := &sequenceStructuredProvider{
+		MockProvider: mocktesting.NewMockProvider("mock"),
+		responses:    []types.StructuredResponse{{Data: map[string]any{"name": nil}}},
+	}
+	client := newSequenceStructuredClient(provider)
+	defer client.Close()
+	_, err := client.Structured().Model("gpt-5").Prompt("Describe Ada").Schema(schema).Strict(true).Generate(context.Background())
+	require.ErrorIs(t, err, types.ErrSchemaMismatch, "a null required property is still a mismatch")
+}
+
+func TestStructuredGenerateRepairsSchemaMismatch(t *testing.T) {
+	t.Parallel()
+
+	provider := &sequenceStructuredProvider{
+		MockProvider: mocktesting.NewMockProvider("mock"),
+		responses: []types.StructuredResponse{
+			{Raw: `{"name":"Ada","age":"36"}`, Data: map[string]any{"name": "Ada", "age": "36"}, Usage: &types.Usage{TotalTokens: 10}},
+			{Raw: `{"name":"Ada","age":36}`, Data: map[string]any{"name": "Ada", "age": 36}, Usage: &types.Usage{TotalTokens: 12}},
+		},
+	}
+	client := newSequenceStructuredClient(provider)
+	defer client.Close()
+
+	var person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	resp, err := client.Structured().Model("gpt-5").Prompt("Describe Ada").Schema(personSchema).
+		RepairAttempts(2).Generate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, resp.ContentAs(&person))
+	assert.Equal(t, 36, person.Age)
+	assert.Equal(t, 1, resp.Metadata["schema_repairs"])
+	assert.Equal(t, 22, resp.Usage.TotalTokens)
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	require.Len(t, provider.requests, 2)
+	repair := provider.requests[1].Messages
+	require.Len(t, repair, 3)
+	assert.Equal(t, types.RoleAssistant, repair[1].GetRole())
+	assert.Equal(t, `{"name":"Ada","age":"36"}`, repair[1].GetContent())
+	assert.Contains(t, repair[2].GetContent(), "- /age: must be integer, got string")
+}
+
+func TestStructuredGenerateStopsAfterRepairAttempts(t *testing.T) {
+	t.Parallel()
+
+	provider := &sequenceStructuredProvider{
+		MockProvider: mocktesting.NewMockProvider("mock"),
+		responses:    []types.StructuredResponse{{Data: map[string]any{"name": "Ada"}}},
+	}
+	client := newSequenceStructuredClient(provider)
+	defer client.Close()
+
+	_, err := client.Structured().Model("gpt-5").Prompt("Describe Ada").Schema(personSchema).
+		RepairAttempts(2).Generate(context.Background())
+	var mismatch *types.SchemaValidationError
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, 3, mismatch.Attempts)
+	assert.Equal(t, []string{""}, mismatch.Paths())
+}
+
+func TestStructuredStreamReportsSchemaMismatch(t *testing.T) {
+	t.Parallel()
+
+	provider := &structuredStreamProvider{MockProvider: mocktesting.NewMockProvider("mock").
+		WithStreamChunks(mocktesting.StreamChunksFrom(`{"name":"Ada",`, `"age":"old"}`))}
+	client := newStructuredStreamClient(provider)
+	defer client.Close()
+
+	stream, err := client.Structured().Model("gpt-5").Prompt("Describe Ada").Schema(personSchema).Stream(context.Background())
+	require.NoError(t, err)
+
+	var final types.StructuredChunk
+	for chunk := range stream {
+		final = chunk
+	}
+	assert.True(t, final.Done)
+	assert.True(t, errors.Is(final.Error, types.ErrSchemaMismatch))
+	assert.Equal(t, map[string]any{"name": "Ada", "age": "old"}, final.Partial)
+}
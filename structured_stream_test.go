@@ -0,0 +1,322 @@
+package wormhole_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+type structuredStreamProvider struct {
+	*mocktesting.MockProvider
+	mu      sync.Mutex
+	request types.TextRequest
+}
+
+func (p *structuredStreamProvider) Stream(ctx context.Context, request types.TextRequest) (<-chan types.TextChunk, error) {
+	p.mu.Lock()
+	p.request = request
+	p.mu.Unlock()
+	return p.MockProvider.Stream(ctx, request)
+}
+
+func newStructuredStreamClient(provider *structuredStreamProvider) *wormhole.Wormhole {
+	return wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+	)
+}
+
+func TestStructuredStreamEmitsProgressivePartials(t *testing.T) {
+	t.Parallel()
+
+	provider := &structuredStreamProvider{MockProvider: mocktesting.NewMockProvider("mock").
+		WithStreamChunks(mocktesting.StreamChunksFrom(`{"name":"A`, `da","tags":["x`, `","y"]}`))}
+	client := newStructuredStreamClient(provider)
+	defer client.Close()
+
+	schema := map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}}
+	stream, err := client.Structured().Model("gpt-5").Prompt("Describe Ada").Schema(schema).SchemaName("person").Stream(context.Background())
+	require.NoError(t, err)
+
+	var chunks []types.StructuredChunk
+	for chunk := range stream {
+		require.NoError(t, chunk.Error)
+		chunks = append(chunks, chunk)
+	}
+
+	require.Len(t, chunks, 4)
+	assert.Equal(t, map[string]any{"name": "A"}, chunks[0].Partial)
+	assert.Equal(t, map[string]any{"name": "Ada", "tags": []any{"x"}}, chunks[1].Partial)
+	final := chunks[len(chunks)-1]
+	assert.True(t, final.Done)
+	assert.True(t, final.Complete)
+	assert.False(t, chunks[0].Complete)
+	assert.Equal(t, map[string]any{"name": "Ada", "tags": []any{"x", "y"}}, final.Partial)
+
+	var person struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	require.NoError(t, final.ContentAs(&person))
+	assert.Equal(t, "Ada", person.Name)
+
+	provider.mu.Lock()
+	format := provider.request.ResponseFormat.(map[string]any)
+	provider.mu.Unlock()
+	assert.Equal(t, "json_schema", format["type"])
+	assert.Equal(t, "person", format["json_schema"].(map[string]any)["name"])
+}
+
+func TestStructuredStreamStrictSanitizesSchema(t *testing.T) {
+	t.Parallel()
+
+	provider := &structuredStreamProvider{MockProvider: mocktesting.NewMockProvider("mock").
+		WithStreamChunks(mocktesting.StreamChunksFrom(`{"name":"Ada","nickname":null}`))}
+	client := newStructuredStreamClient(provider)
+	defer client.Close()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":     map[string]any{"type": "string"},
+			"nickname": map[string]any{"type": "string"},
+		},
+		"required": []string{"name"},
+	}
+	builder := client.Structured().Model("gpt-5").Prompt("Describe Ada").Schema(schema).Strict(true)
+	stream, err := builder.Stream(context.Background())
+	require.NoError(t, err)
+	for chunk := range stream {
+		require.NoError(t, chunk.Error)
+	}
+
+	provider.mu.Lock()
+	spec := provider.request.ResponseFormat.(map[string]any)["json_schema"].(map[string]any)
+	provider.mu.Unlock()
+	assert.Equal(t, true, spec["strict"])
+	sent := spec["schema"].(map[string]any)
+	assert.Equal(t, false, sent["additionalProperties"])
+	assert.Equal(t, []any{"name", "nickname"}, sent["required"])
+	assert.Equal(t, []any{"string", "null"}, sent["properties"].(map[string]any)["nickname"].(map[string]any)["type"])
+
+	provider.mu.Lock()
+	provider.request = types.TextRequest{}
+	provider.mu.Unlock()
+	stream, err = builder.Strict(false).Stream(context.Background())
+	require.NoError(t, err)
+	for range stream {
+	}
+	provider.mu.Lock()
+	spec = provider.request.ResponseFormat.(map[string]any)["json_schema"].(map[string]any)
+	provider.mu.Unlock()
+	assert.Equal(t, false, spec["strict"])
+	assert.NotContains(t, spec["schema"], "additionalProperties")
+}
+
+func TestStructuredStreamReportsInvalidFinalJSON(t *testing.T) {
+	t.Parallel()
+
+	provider := &structuredStreamProvider{MockProvider: mocktesting.NewMockProvider("mock").
+		WithStreamChunks(mocktesting.StreamChunksFrom(`{"name":`, `oops`))}
+	client := newStructuredStreamClient(provider)
+	defer client.Close()
+
+	stream, err := client.Structured().Model("gpt-5").Prompt("x").Schema(map[string]any{"type": "object"}).Mode(types.StructuredModeJSON).Stream(context.Background())
+	require.NoError(t, err)
+
+	var last types.StructuredChunk
+	for chunk := range stream {
+		last = chunk
+	}
+	assert.True(t, last.Done)
+	require.Error(t, last.Error)
+}
+
+func TestStructuredStreamKeepsPartialOnDisconnect(t *testing.T) {
+	t.Parallel()
+
+	dropped := types.ErrNetworkError.WithDetails("connection reset")
+	tests := []struct {
+		name   string
+		chunks []types.TextChunk
+	}{
+		{name: "error chunk", chunks: append(mocktesting.StreamChunksFrom(`{"name":"Ada",`, `"tags":["x`), types.TextChunk{Error: dropped})},
+		{name: "truncated", chunks: mocktesting.StreamChunksFrom(`{"name":"Ada",`, `"tags":["x`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			provider := &structuredStreamProvider{MockProvider: mocktesting.NewMockProvider("mock").WithStreamChunks(tt.chunks)}
+			client := newStructuredStreamClient(provider)
+			defer client.Close()
+
+			stream, err := client.Structured().Model("gpt-5").Prompt("x").Schema(map[string]any{"type": "object"}).Stream(context.Background())
+			require.NoError(t, err)
+			var last types.StructuredChunk
+			for chunk := range stream {
+				last = chunk
+			}
+			require.Error(t, last.Error)
+			assert.False(t, last.Complete)
+			assert.Equal(t, map[string]any{"name": "Ada", "tags": []any{"x"}}, last.Partial)
+		})
+	}
+}
+
+func TestStructuredStreamAs(t *testing.T) {
+	t.Parallel()
+
+	type person struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	run := func(t *testing.T, chunks []types.TextChunk) (person, bool, error) {
+		t.Helper()
+		provider := &structuredStreamProvider{MockProvider: mocktesting.NewMockProvider("mock").WithStreamChunks(chunks)}
+		client := newStructuredStreamClient(provider)
+		defer client.Close()
+		var out person
+		complete, err := client.Structured().Model("gpt-5").Prompt("x").Schema(map[string]any{"type": "object"}).StreamAs(context.Background(), &out)
+		return out, complete, err
+	}
+
+	t.Run("complete", func(t *testing.T) {
+		t.Parallel()
+		out, complete, err := run(t, mocktesting.StreamChunksFrom(`{"name":"Ada",`, `"tags":["x"]}`))
+		require.NoError(t, err)
+		assert.True(t, complete)
+		assert.Equal(t, person{Name: "Ada", Tags: []string{"x"}}, out)
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		t.Parallel()
+		out, complete, err := run(t, append(mocktesting.StreamChunksFrom(`{"name":"Ada",`, `"tags":[`), types.TextChunk{Error: types.ErrNetworkError}))
+		require.Error(t, err)
+		assert.True(t, types.IsNetworkError(err))
+		assert.False(t, complete)
+		assert.Equal(t, "Ada", out.Name, "fields parsed before the disconnect survive")
+	})
+}
+
+func TestStructuredStreamRequiresSchema(t *testing.T) {
+	t.Parallel()
+
+	client := newStructuredStreamClient(&structuredStreamProvider{MockProvider: mocktesting.NewMockProvider("mock")})
+	defer client.Close()
+
+	_, err := client.Structured().Model("gpt-5").Prompt("x").Stream(context.Background())
+	require.Error(t, err)
+}
+
+func TestStructuredStreamRejectsProvidersWithoutResponseFormat(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}}
+	tests := []struct {
+		provider string
+		mode     types.StructuredMode
+		wantErr  bool
+	}{
+		{provider: "anthropic", wantErr: true},
+		{provider: "gemini", wantErr: true},
+		{provider: "ollama", wantErr: true},
+		{provider: "ollama", mode: types.StructuredModeJSON},
+		{provider: "openai"},
+	}
+	for _, tt := range tests {
+		name := tt.provider
+		if tt.mode != "" {
+			name += "/" + string(tt.mode)
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			provider := &structuredStreamProvider{MockProvider: mocktesting.NewMockProvider(tt.provider).
+				WithStreamChunks(mocktesting.StreamChunksFrom(`{"name":"Ada"}`))}
+			client := wormhole.New(
+				wormhole.WithDefaultProvider(tt.provider),
+				wormhole.WithCustomProvider(tt.provider, func(types.ProviderConfig) (types.Provider, error) { return provider, nil }),
+				wormhole.WithProviderConfig(tt.provider, types.ProviderConfig{}),
+				wormhole.WithDiscovery(false),
+			)
+			defer client.Close()
+
+			builder := client.Structured().Model("test-model").Prompt("Describe Ada").Schema(schema)
+			if tt.mode != "" {
+				builder = builder.Mode(tt.mode)
+			}
+			stream, err := builder.Stream(context.Background())
+			if tt.wantErr {
+				require.ErrorIs(t, err, types.ErrStreamSchemaUnsupported)
+				provider.mu.Lock()
+				defer provider.mu.Unlock()
+				assert.Empty(t, provider.request.Model, "the request is never sent")
+				return
+			}
+			require.NoError(t, err)
+			for range stream {
+			}
+		})
+	}
+}
+
+func TestStructuredStreamItemsYieldsArrayElements(t *testing.T) {
+	t.Parallel()
+
+	provider := &structuredStreamProvider{MockProvider: mocktesting.NewMockProvider("mock").
+		WithStreamChunks(mocktesting.StreamChunksFrom(`{"items":[{"name":"A`, `da"},{"name":"Bo`, `b"}`, `]}`))}
+	client := newStructuredStreamClient(provider)
+	defer client.Close()
+
+	schema := map[string]any{"type": "object", "properties": map[string]any{"items": map[string]any{"type": "array"}}}
+	items, err := client.Structured().Model("gpt-5").Prompt("Name two people").Schema(schema).StreamItems(context.Background())
+	require.NoError(t, err)
+
+	var names []string
+	var last types.StructuredItem
+	for item := range items {
+		require.NoError(t, item.Error)
+		last = item
+		if item.Done {
+			break
+		}
+		var person struct {
+			Name string `json:"name"`
+		}
+		require.NoError(t, item.ContentAs(&person))
+		assert.Equal(t, len(names), item.Index)
+		names = append(names, person.Name)
+	}
+	assert.Equal(t, []string{"Ada", "Bob"}, names)
+	assert.True(t, last.Done)
+	assert.Equal(t, 2, last.Index)
+}
+
+func TestStructuredStreamItemsReportsUnclosedArray(t *testing.T) {
+	t.Parallel()
+
+	provider := &structuredStreamProvider{MockProvider: mocktesting.NewMockProvider("mock").
+		WithStreamChunks(mocktesting.StreamChunksFrom(`[1,2,`, `3`))}
+	client := newStructuredStreamClient(provider)
+	defer client.Close()
+
+	items, err := client.Structured().Model("gpt-5").Prompt("x").Schema(map[string]any{"type": "array"}).StreamItems(context.Background())
+	require.NoError(t, err)
+
+	var collected []types.StructuredItem
+	for item := range items {
+		collected = append(collected, item)
+	}
+	require.Len(t, collected, 3)
+	assert.JSONEq(t, `2`, string(collected[1].Value))
+	assert.True(t, collected[2].Done)
+	require.Error(t, collected[2].Error)
+}
@@ -0,0 +1,76 @@
+package wormhole
+
+import (
+	"context"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// continuePrompt asks the model to resume a reply cut off at the token limit.
+const continuePrompt = "Your previous reply was cut off. Continue exactly where it stopped, without repeating any of it and without a preamble."
+
+// AutoContinue makes Generate re-prompt the model when a reply stops at the
+// token limit (FinishReason length), up to maxRounds times. Each round sends
+// the reply so far as an assistant message and asks the model to carry on;
+// the segments are joined into one response whose Usage sums every round and
+// whose FinishReason is the last round's. Metadata["continuations"] records
+// how many rounds ran. Replies that stop for tool calls are not continued.
+// AutoContinue applies to Generate and GenerateTurn, not Stream.
+//
+// Example:
+//
+//	resp, err := client.Text().
+//	    Model("gpt-5-mini").
+//	    MaxTokens(1024).
+//	    Prompt("Write the full migration guide.").
+//	    AutoContinue(3).
+//	    Generate(ctx)
+//	if resp.WasTruncated() {
+//	    // still cut off after three continuations
+//	}
+func (b *TextRequestBuilder) AutoContinue(maxRounds int) *TextRequestBuilder {
+	b.autoContinueRounds = max(maxRounds, 0)
+	return b
+}
+
+// continueTruncated runs the AutoContinue rounds for resp.
+func (b *TextRequestBuilder) continueTruncated(ctx context.Context, resp *types.TextResponse) (*types.TextResponse, error) {
+	if b.autoContinueRounds == 0 || resp == nil || !resp.WasTruncated() || len(resp.ToolCalls) > 0 {
+		return resp, nil
+	}
+	// Documents go with the original user message, not the continue prompt.
+	request := cloneTextRequest(b.request)
+	attachDocuments(request, b.documents, b.citeDocuments)
+
+	text := resp.Text
+	usage := mergeUsage(nil, resp.Usage)
+	rounds := 0
+	for rounds < b.autoContinueRounds && resp.WasTruncated() && len(resp.ToolCalls) == 0 {
+		next := b.Clone()
+		next.request.Messages = append(types.CloneMessages(request.Messages),
+			types.NewAssistantMessage(text),
+			types.NewUserMessage(continuePrompt),
+		)
+		next.documents = nil
+		next.autoContinueRounds = 0
+
+		segment, err := next.generate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rounds++
+		text += segment.Text
+		usage = mergeUsage(usage, segment.Usage)
+		resp = segment
+	}
+
+	stitched := *resp
+	stitched.Text = text
+	stitched.Usage = usage
+	stitched.Metadata = make(map[string]any, len(resp.Metadata)+1)
+	for key, value := range resp.Metadata {
+		stitched.Metadata[key] = value
+	}
+	stitched.Metadata["continuations"] = rounds
+	return &stitched, nil
+}
@@ -0,0 +1,111 @@
+package wormhole_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func newContinueClient(t *testing.T, responses ...types.TextResponse) (*wormhole.Wormhole, func() []types.TextRequest) {
+	t.Helper()
+	provider := mocktesting.NewMockProvider("mock")
+	for _, response := range responses {
+		provider.WithTextResponse(response)
+	}
+
+	var mu sync.Mutex
+	var requests []types.TextRequest
+	capture := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if request, ok := req.(*types.TextRequest); ok {
+				mu.Lock()
+				requests = append(requests, *request)
+				mu.Unlock()
+			}
+			return next(ctx, req)
+		}
+	}
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithMiddleware(capture),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+	)
+	t.Cleanup(func() { _ = client.Close() })
+	return client, func() []types.TextRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]types.TextRequest(nil), requests...)
+	}
+}
+
+func segment(text string, finish types.FinishReason, completion int) types.TextResponse {
+	return types.TextResponse{
+		Text:         text,
+		FinishReason: finish,
+		Usage:        &types.Usage{PromptTokens: 10, CompletionTokens: completion, TotalTokens: 10 + completion},
+	}
+}
+
+func TestAutoContinueStitchesTruncatedSegments(t *testing.T) {
+	t.Parallel()
+	client, requests := newContinueClient(t,
+		segment("The quick brown", types.FinishReasonLength, 3),
+		segment(" fox jumps", types.FinishReasonLength, 2),
+		segment(" over the dog.", types.FinishReasonStop, 4),
+	)
+
+	resp, err := client.Text().Model("m").SystemPrompt("be terse").Prompt("Tell the fable.").AutoContinue(3).Generate(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "The quick brown fox jumps over the dog.", resp.Text)
+	assert.Equal(t, types.FinishReasonStop, resp.FinishReason)
+	assert.Equal(t, &types.Usage{PromptTokens: 30, CompletionTokens: 9, TotalTokens: 39}, resp.Usage)
+	assert.Equal(t, 2, resp.Metadata["continuations"])
+
+	sent := requests()
+	require.Len(t, sent, 3)
+	last := sent[2].Messages
+	require.Len(t, last, 4, "system prompt, prompt, stitched reply, continue prompt")
+	assert.Equal(t, types.RoleSystem, last[0].GetRole())
+	assert.Equal(t, "Tell the fable.", last[1].GetContent())
+	assert.Equal(t, types.RoleAssistant, last[2].GetRole())
+	assert.Equal(t, "The quick brown fox jumps", last[2].GetContent())
+	assert.Equal(t, types.RoleUser, last[3].GetRole())
+}
+
+func TestAutoContinueStopsAtMaxRounds(t *testing.T) {
+	t.Parallel()
+	client, requests := newContinueClient(t,
+		segment("a", types.FinishReasonLength, 1),
+		segment("b", types.FinishReasonLength, 1),
+		segment("c", types.FinishReasonLength, 1),
+	)
+
+	resp, err := client.Text().Model("m").Prompt("go").AutoContinue(1).Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ab", resp.Text)
+	assert.True(t, resp.WasTruncated())
+	assert.Len(t, requests(), 2)
+}
+
+func TestAutoContinueIsOptIn(t *testing.T) {
+	t.Parallel()
+	client, requests := newContinueClient(t, segment("cut", types.FinishReasonLength, 1))
+
+	resp, err := client.Text().Model("m").Prompt("go").Generate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "cut", resp.Text)
+	assert.Nil(t, resp.Metadata)
+	assert.Len(t, requests(), 1)
+}
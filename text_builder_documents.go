@@ -0,0 +1,91 @@
+package wormhole
+
+import (
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// Document attaches the file at path (typically a PDF) to the request. It is
+// sent with the last user message as an Anthropic document block, a Gemini
+// file part, or an OpenAI file input. The file is read immediately; a read
+// error is returned by Generate or Stream. Each provider checks size and MIME
+// type against its own limits before sending.
+//
+// Example:
+//
+//	resp, err := client.Text().
+//	    Model("claude-sonnet-4-5").
+//	    Prompt("Summarize the termination clauses.").
+//	    Document("contract.pdf").
+//	    Generate(ctx)
+func (b *TextRequestBuilder) Document(path string) *TextRequestBuilder {
+	doc, err := types.NewDocumentFromFile(path)
+	if err != nil {
+		if b.documentErr == nil {
+			b.documentErr = types.ErrInvalidRequest.WithDetails(err.Error()).WithCause(err)
+		}
+		return b
+	}
+	b.documents = append(b.documents, doc)
+	return b
+}
+
+// DocumentBytes attaches an in-memory document. An empty mimeType is detected
+// from the content. See Document.
+func (b *TextRequestBuilder) DocumentBytes(data []byte, mimeType string) *TextRequestBuilder {
+	b.documents = append(b.documents, types.NewDocument(data, mimeType))
+	return b
+}
+
+// CiteDocuments asks the provider to cite passages of the attached documents.
+// Citations come back in TextResponse.Citations with the document's index
+// and the cited passage. Anthropic only cites documents that opt in; OpenAI
+// and Gemini ignore the flag.
+func (b *TextRequestBuilder) CiteDocuments() *TextRequestBuilder {
+	b.citeDocuments = true
+	return b
+}
+
+// executionRequest returns a detached copy of the request with the model
+// resolved and pending documents attached, ready for execution.
+func (b *TextRequestBuilder) executionRequest() (*types.TextRequest, error) {
+	if b.documentErr != nil {
+		return nil, b.documentErr
+	}
+	if b.promptErr != nil {
+		return nil, b.promptErr
+	}
+	if b.experimentErr != nil {
+		return nil, b.experimentErr
+	}
+	request := cloneTextRequest(b.request)
+	if !b.modelResolved {
+		request.Model = b.resolveModel(request.Model)
+	}
+	attachDocuments(request, b.documents, b.citeDocuments)
+	prepareTextExecutionRequest(request)
+	return request, nil
+}
+
+// attachDocuments adds documents to the last user message, starting one when
+// the request has none. cite turns on citations for each attached copy.
+func attachDocuments(request *types.TextRequest, documents []*types.DocumentMedia, cite bool) {
+	if len(documents) == 0 {
+		return
+	}
+	var target *types.UserMessage
+	for i := len(request.Messages) - 1; i >= 0; i-- {
+		if user, ok := request.Messages[i].(*types.UserMessage); ok {
+			target = user
+			break
+		}
+	}
+	if target == nil {
+		target = &types.UserMessage{}
+		request.Messages = append(request.Messages, target)
+	}
+	for _, doc := range documents {
+		clone := types.CloneMedia(doc).(*types.DocumentMedia)
+		clone.Citations = clone.Citations || cite
+		target.Media = append(target.Media, clone)
+	}
+}
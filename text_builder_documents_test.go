@@ -0,0 +1,48 @@
+package wormhole
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func TestTextBuilderDocumentsAttachToLastUserMessage(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "contract.pdf")
+	require.NoError(t, os.WriteFile(path, []byte("%PDF-1.7"), 0o600))
+
+	client := New(WithDiscovery(false))
+	defer client.Close()
+
+	// Documents may be added before Prompt replaces the messages.
+	builder := client.Text().Model("gpt-5").Document(path).Prompt("Summarize").DocumentBytes([]byte("notes"), "text/plain")
+	request, err := builder.executionRequest()
+	require.NoError(t, err)
+
+	require.Len(t, request.Messages, 1)
+	user := request.Messages[0].(*types.UserMessage)
+	assert.Equal(t, "Summarize", user.Content)
+	require.Len(t, user.Media, 2)
+	assert.Equal(t, "contract.pdf", user.Media[0].(*types.DocumentMedia).Filename)
+	assert.Equal(t, "text/plain", user.Media[1].(*types.DocumentMedia).MimeType)
+	assert.Empty(t, builder.request.Messages[0].(*types.UserMessage).Media, "builder state is not mutated")
+	assert.False(t, user.Media[0].(*types.DocumentMedia).Citations)
+
+	request, err = builder.CiteDocuments().executionRequest()
+	require.NoError(t, err)
+	for _, media := range request.Messages[0].(*types.UserMessage).Media {
+		assert.True(t, media.(*types.DocumentMedia).Citations)
+	}
+	assert.False(t, builder.documents[0].Citations, "attached documents are not mutated")
+
+	_, err = client.Text().Model("gpt-5").Prompt("x").Document(filepath.Join(t.TempDir(), "missing.pdf")).Generate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.pdf")
+}
@@ -3,14 +3,33 @@ package wormhole
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
 // Generate executes the request and returns a response
 func (b *TextRequestBuilder) Generate(ctx context.Context) (*types.TextResponse, error) {
-	baseRequest := cloneTextRequest(b.request)
-	prepareTextExecutionRequest(baseRequest)
+	b = b.warmRouted()
+	start := time.Now()
+	ctx = b.taggedContext(ctx)
+	resp, err := b.generate(ctx)
+	if err == nil {
+		resp, err = b.continueTruncated(ctx, resp)
+	}
+	var usage *types.Usage
+	if resp != nil {
+		usage = resp.Usage
+	}
+	b.recordExperiment(start, usage, err)
+	return resp, err
+}
+
+func (b *TextRequestBuilder) generate(ctx context.Context) (*types.TextResponse, error) {
+	baseRequest, err := b.executionRequest()
+	if err != nil {
+		return nil, err
+	}
 
 	if len(baseRequest.Messages) == 0 {
 		return nil, types.ErrInvalidRequest.WithDetails("no messages provided")
@@ -23,12 +42,13 @@ func (b *TextRequestBuilder) Generate(ctx context.Context) (*types.TextResponse,
 	modelsToTry := make([]string, 0, 1+len(b.fallbackModels))
 	modelsToTry = append(modelsToTry, baseRequest.Model)
 	modelsToTry = append(modelsToTry, b.fallbackModels...)
+	wormhole := b.getWormhole()
+	providerFallbacks := wormhole.resolveTextRoutes(b.providerFallbacks, baseRequest.Model)
 	idempotencyRequest := textIdempotencyRequest{
 		Request:           baseRequest,
 		FallbackModels:    append([]string(nil), b.fallbackModels...),
-		ProviderFallbacks: append([]TextRoute(nil), b.providerFallbacks...),
+		ProviderFallbacks: providerFallbacks,
 	}
-	wormhole := b.getWormhole()
 	toolsEnabled := b.shouldAutoExecuteTools(wormhole)
 	if len(b.fallbackModels) == 0 && len(b.providerFallbacks) == 0 {
 		if err := wormhole.validateModelAttempt(b.getProvider(), baseRequest.Model, textModelCapabilities, textRequiredCapabilities(baseRequest, toolsEnabled, false)); err != nil {
@@ -92,7 +112,7 @@ func (b *TextRequestBuilder) Generate(ctx context.Context) (*types.TextResponse,
 			return nil, lastErr
 		}
 
-		for routeIndex, route := range b.providerFallbacks {
+		for routeIndex, route := range providerFallbacks {
 			attempt := len(modelsToTry) + routeIndex + 1
 			wormhole.emitAttempt(ctx, AttemptEvent{
 				Operation: "text.generate",
@@ -147,11 +167,38 @@ func (b *TextRequestBuilder) Generate(ctx context.Context) (*types.TextResponse,
 	})
 }
 
+// GenerateTurn sends conv, then records the reply on conv as a new turn with
+// its model, usage, estimated cost, and latency (see types.Conversation.Turns).
+// conv is left unchanged when generation fails.
+//
+// Example:
+//
+//	conv := types.NewConversation().User("What is Go?")
+//	resp, err := client.Text().Model("gpt-5.2").GenerateTurn(ctx, conv)
+//	conv.User("And channels?")
+//	resp, err = client.Text().Model("gpt-5.2").GenerateTurn(ctx, conv)
+//	spend := conv.TotalCost()
+func (b *TextRequestBuilder) GenerateTurn(ctx context.Context, conv *types.Conversation) (*types.TextResponse, error) {
+	if conv == nil {
+		return nil, types.ErrInvalidRequest.WithDetails("no conversation provided")
+	}
+	start := time.Now()
+	resp, err := b.Conversation(conv).Generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conv.Record(resp, time.Since(start), b.tags)
+	return resp, nil
+}
+
 // executeGenerate performs the actual generation with the current request settings
 func (b *TextRequestBuilder) executeGenerate(ctx context.Context, provider types.Provider, request *types.TextRequest) (*types.TextResponse, error) {
 	// Check if we should enable automatic tool execution
 	wormhole := b.getWormhole()
 	ctx = contextWithProviderOperation(ctx, provider, "text")
+	scoped := *request
+	scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
+	request = &scoped
 	shouldAutoExecuteTools := b.shouldAutoExecuteTools(wormhole)
 	handler := types.TextHandler(provider.Text)
 	if wormhole.providerMiddleware != nil {
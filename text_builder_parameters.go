@@ -1,6 +1,9 @@
 package wormhole
 
 import (
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
@@ -67,6 +70,41 @@ func (b *TextRequestBuilder) Seed(seed int) *TextRequestBuilder {
 	return b
 }
 
+// TopK limits sampling to the k most likely tokens at each step. Supported by
+// Anthropic, Gemini, Ollama, Hugging Face and OpenAI-compatible backends such
+// as OpenRouter and vLLM; OpenAI itself rejects it before any request is sent.
+func (b *TextRequestBuilder) TopK(k int) *TextRequestBuilder {
+	b.request.TopK = &k
+	return b
+}
+
+// MinP drops tokens whose probability is below p times that of the most likely
+// token (0.0-1.0). Supported by Ollama, OpenRouter and vLLM.
+func (b *TextRequestBuilder) MinP(p float32) *TextRequestBuilder {
+	b.request.MinP = &p
+	return b
+}
+
+// RepetitionPenalty scales down the logits of tokens that already appeared;
+// 1.0 disables it and values above 1.0 discourage repetition. Unlike
+// FrequencyPenalty it is multiplicative. Supported by Ollama, Hugging Face,
+// OpenRouter and vLLM.
+func (b *TextRequestBuilder) RepetitionPenalty(penalty float32) *TextRequestBuilder {
+	b.request.RepetitionPenalty = &penalty
+	return b
+}
+
+// LogitBias adds a bias (-100 to 100) to the logits of the given token IDs.
+// Token IDs are model-specific. Supported by OpenAI, OpenRouter and vLLM.
+//
+// Example:
+//
+//	builder.LogitBias(map[int]int{50256: -100}) // never emit <|endoftext|>
+func (b *TextRequestBuilder) LogitBias(bias map[int]int) *TextRequestBuilder {
+	b.request.LogitBias = bias
+	return b
+}
+
 // ParallelToolCalls controls whether a provider may emit multiple tool calls
 // in one model turn.
 func (b *TextRequestBuilder) ParallelToolCalls(enabled bool) *TextRequestBuilder {
@@ -82,6 +120,104 @@ func (b *TextRequestBuilder) Reasoning(reasoning types.Reasoning) *TextRequestBu
 	return b
 }
 
+// Logprobs asks for the log probability of each output token, returned in
+// TextResponse.Logprobs (and TextChunk.Logprobs when streaming). Useful for
+// confidence scoring and calibrating classifiers. Supported by OpenAI and
+// OpenAI-compatible backends that implement it; other providers reject it.
+func (b *TextRequestBuilder) Logprobs(enabled bool) *TextRequestBuilder {
+	b.request.Logprobs = enabled
+	return b
+}
+
+// TopLogprobs asks for the n most likely alternatives at each output token
+// position (0-20) and enables Logprobs.
+//
+// Example:
+//
+//	resp, _ := client.Text().
+//	    Model("gpt-4o-mini").
+//	    Prompt("Is this review positive? Answer yes or no.").
+//	    MaxTokens(1).
+//	    TopLogprobs(5).
+//	    Generate(ctx)
+//	for _, alt := range resp.Logprobs[0].TopLogprobs {
+//	    fmt.Printf("%s %.2f\n", alt.Token, alt.Probability())
+//	}
+func (b *TextRequestBuilder) TopLogprobs(n int) *TextRequestBuilder {
+	b.request.Logprobs = true
+	b.request.TopLogprobs = n
+	return b
+}
+
+// N samples n independent completions in one call, for reranking or
+// self-consistency voting. All of them are returned in TextResponse.Choices;
+// the top-level Text is the first. Supported by OpenAI Chat Completions and
+// compatible backends; streaming and other providers reject n above 1.
+//
+// Example:
+//
+//	resp, _ := client.Text().
+//	    Model("gpt-4o-mini").
+//	    Prompt("What is 17 * 24? Answer with the number only.").
+//	    Temperature(0.8).
+//	    N(5).
+//	    Generate(ctx)
+//	answer := majority(resp.Texts())
+func (b *TextRequestBuilder) N(n int) *TextRequestBuilder {
+	b.request.N = n
+	return b
+}
+
+// EnableWebSearch lets the model search the web with the provider's built-in
+// tool (OpenAI web search, Anthropic's web search tool, Gemini grounding with
+// Google Search). Sources come back in TextResponse.Citations. Providers
+// without a hosted search tool reject the request.
+//
+// Example:
+//
+//	resp, _ := client.Text().
+//	    Using("anthropic").
+//	    Model("claude-sonnet-4-5").
+//	    Prompt("What changed in the latest Go release?").
+//	    EnableWebSearch().
+//	    Generate(ctx)
+//	for _, c := range resp.Citations {
+//	    fmt.Println(c.Title, c.URL)
+//	}
+func (b *TextRequestBuilder) EnableWebSearch() *TextRequestBuilder {
+	if b.request.WebSearch == nil {
+		b.request.WebSearch = &types.WebSearch{}
+	}
+	return b
+}
+
+// WebSearch enables web search with limits such as a domain allow-list. See
+// types.WebSearch for which provider honors each field.
+func (b *TextRequestBuilder) WebSearch(search types.WebSearch) *TextRequestBuilder {
+	b.request.WebSearch = search.Clone()
+	return b
+}
+
+// AudioOutput asks an audio-capable chat model (OpenAI's gpt-4o-audio-preview
+// family) to speak its reply in voice, encoded as format. The audio and its
+// transcript come back in TextResponse.Audio. Other providers reject the
+// request, and audio replies cannot be streamed.
+//
+// Example:
+//
+//	msg := types.NewUserMessage("")
+//	msg.Media = append(msg.Media, types.NewAudio(question, "wav"))
+//	resp, _ := client.Text().
+//	    Model("gpt-4o-audio-preview").
+//	    Messages(msg).
+//	    AudioOutput("alloy", "wav").
+//	    Generate(ctx)
+//	_ = os.WriteFile("reply.wav", resp.Audio.Data, 0o644)
+func (b *TextRequestBuilder) AudioOutput(voice, format string) *TextRequestBuilder {
+	b.request.Audio = &types.AudioOutput{Voice: voice, Format: format}
+	return b
+}
+
 // Stop sets sequences that will halt generation when encountered.
 // The model stops generating when it produces any of these sequences.
 // Useful for controlling output format or preventing runaway generation.
@@ -122,6 +258,87 @@ func (b *TextRequestBuilder) ProviderOptions(options map[string]any) *TextReques
 	return b
 }
 
+// ProviderOption sets one provider-specific body field that is sent only when
+// the request runs on provider, named as registered (e.g. "anthropic",
+// "openrouter"). Use it for wire parameters the SDK has no typed method for,
+// or to give each route in a fallback chain its own options.
+//
+// Options are merged by top-level key, later sources winning: the provider's
+// DefaultProviderOptions, then its per-model options, then ProviderOptions,
+// then ProviderOption values for the provider that serves the request.
+// Repeated ProviderOption calls accumulate; ProviderOptions replaces the
+// earlier unscoped map. Values for other providers are dropped, never sent.
+//
+// Example:
+//
+//	client.Text().
+//	    Model("claude-sonnet-4-5").
+//	    ProviderOption("anthropic", "top_k", 40).
+//	    ProviderOption("openrouter", "transforms", []string{"middle-out"}).
+//	    WithProviderFallback(wormhole.TextRoute{Provider: "openrouter", Model: "anthropic/claude-sonnet-4.5"})
+func (b *TextRequestBuilder) ProviderOption(provider, key string, value any) *TextRequestBuilder {
+	b.setProviderOption(provider, key, value)
+	return b
+}
+
+// Metadata tags the request, e.g. {"tenant": "acme", "feature": "summarize"}.
+// Tags reach middleware through the context (see middleware.WithTags), where
+// metrics record them as labels and logging adds them as tag_<name>
+// attributes; GenerateTurn also copies them onto the conversation turn for
+// cost attribution. Repeated calls merge.
+func (b *TextRequestBuilder) Metadata(tags map[string]string) *TextRequestBuilder {
+	b.addTags(tags)
+	return b
+}
+
+// Priority sets the request's scheduling priority, which middleware reads
+// from the context: a middleware.Scheduler releases higher priorities first
+// and adaptive load shedding drops low priorities first. It overrides any
+// priority already on the context passed to Generate.
+//
+// Example:
+//
+//	client.Text().Model("gpt-5-mini").Prompt(question).Priority(wormhole.High).Generate(ctx)
+func (b *TextRequestBuilder) Priority(p Priority) *TextRequestBuilder {
+	b.setPriority(p)
+	return b
+}
+
+// Cache stores this request's response in CacheMiddleware for ttl instead of
+// the middleware's configured TTL. It only changes how long a response is
+// kept; without a cache middleware it does nothing.
+//
+// Example:
+//
+//	client.Text().Model("gpt-5-mini").Prompt(faq).Cache(24 * time.Hour).Generate(ctx)
+func (b *TextRequestBuilder) Cache(ttl time.Duration) *TextRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{TTL: ttl})
+	return b
+}
+
+// NoCache sends the request past CacheMiddleware and SemanticCacheMiddleware:
+// it neither reads a cached response nor stores its own.
+func (b *TextRequestBuilder) NoCache() *TextRequestBuilder {
+	b.setCacheControl(middleware.CacheControl{Bypass: true})
+	return b
+}
+
+// CacheKey returns the key CacheMiddleware uses for this request's first
+// provider call: the provider name and the request fingerprint (see
+// middleware.CacheKey). Two builders with the same key share a cache entry.
+// Tool-execution rounds after the first call have keys of their own.
+func (b *TextRequestBuilder) CacheKey() (string, error) {
+	request, err := b.executionRequest()
+	if err != nil {
+		return "", err
+	}
+	return b.cacheKey(func(provider types.Provider) any {
+		scoped := *request
+		scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
+		return scoped
+	})
+}
+
 // ==================== Tool Execution Configuration ====================
 
 // WithToolsEnabled enables automatic tool execution.
@@ -169,8 +386,31 @@ func (b *TextRequestBuilder) WithFallback(models ...string) *TextRequestBuilder
 }
 
 // WithProviderFallback sets provider/model routes to try after the primary
-// model and any same-provider models configured with WithFallback.
+// model and any same-provider models configured with WithFallback. A route
+// with an empty Model uses the primary model's equivalent on that provider
+// (see WithFallbackProviders).
 func (b *TextRequestBuilder) WithProviderFallback(routes ...TextRoute) *TextRequestBuilder {
 	b.providerFallbacks = routes
 	return b
 }
+
+// WithFallbackProviders falls back to other providers, translating the primary
+// model to its equivalent on each one via the model equivalence table
+// (built-in, overridable with WithModelEquivalents). A provider with no known
+// equivalent receives the primary model name unchanged.
+//
+// Example:
+//
+//	response, _ := client.Text().
+//	    Using("openai").
+//	    Model("gpt-4o").
+//	    WithFallbackProviders("anthropic", "gemini"). // claude-sonnet-4-5, then gemini-2.5-pro
+//	    Prompt("Complex task").
+//	    Generate(ctx)
+func (b *TextRequestBuilder) WithFallbackProviders(providers ...string) *TextRequestBuilder {
+	routes := make([]TextRoute, len(providers))
+	for i, provider := range providers {
+		routes[i] = TextRoute{Provider: provider}
+	}
+	return b.WithProviderFallback(routes...)
+}
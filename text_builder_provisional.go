@@ -0,0 +1,109 @@
+package wormhole
+
+import (
+	"context"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ProvisionalRace configures GenerateProvisional.
+type ProvisionalRace struct {
+	// Fast is the cheap model whose answer is shown first. An empty Provider
+	// keeps the builder's provider.
+	Fast TextRoute
+
+	// FinalTimeout bounds the builder's (expensive) request. Zero means only
+	// the caller's context applies.
+	FinalTimeout time.Duration
+
+	// OnFinal is called once, from another goroutine, when the expensive
+	// request finishes. It receives both answers, for swapping the displayed
+	// text and for recording the pair for evaluation.
+	OnFinal func(ProvisionalResult)
+}
+
+// ProvisionalResult records both sides of a GenerateProvisional call. Latencies
+// are measured from the start of the call.
+type ProvisionalResult struct {
+	Provisional        *types.TextResponse
+	ProvisionalErr     error
+	ProvisionalLatency time.Duration
+
+	Final        *types.TextResponse
+	FinalErr     error
+	FinalLatency time.Duration
+}
+
+// GenerateProvisional starts the builder's request and a cheaper race.Fast
+// request at the same time, returning the fast answer as soon as it arrives
+// for provisional display. The builder's answer is delivered to race.OnFinal
+// when it lands, so the UI can swap it in. If the fast request fails,
+// GenerateProvisional waits for and returns the builder's answer instead.
+//
+// Both requests share ctx; cancelling it abandons the pending upgrade. The
+// fast request does not use the builder's fallbacks.
+//
+// Example:
+//
+//	draft, err := client.Text().
+//	    Model("gpt-5.2").
+//	    Prompt(question).
+//	    GenerateProvisional(ctx, wormhole.ProvisionalRace{
+//	        Fast: wormhole.TextRoute{Model: "gpt-5-nano"},
+//	        OnFinal: func(r wormhole.ProvisionalResult) {
+//	            if r.FinalErr == nil {
+//	                ui.Replace(r.Final.Text)
+//	            }
+//	            evals.Record(r)
+//	        },
+//	    })
+//	ui.Show(draft.Text)
+func (b *TextRequestBuilder) GenerateProvisional(ctx context.Context, race ProvisionalRace) (*types.TextResponse, error) {
+	fastBuilder := b.Clone()
+	fastBuilder.fallbackModels = nil
+	fastBuilder.providerFallbacks = nil
+	if race.Fast.Provider != "" && race.Fast.Provider != b.provider {
+		fastBuilder.provider = race.Fast.Provider
+		fastBuilder.baseURL = ""
+	}
+	if race.Fast.Model != "" {
+		fastBuilder.request.Model = race.Fast.Model
+	}
+	finalBuilder := b.Clone()
+
+	start := time.Now()
+	var result ProvisionalResult
+	fastDone := make(chan struct{})
+	finalDone := make(chan struct{})
+
+	go func() {
+		defer close(finalDone)
+		finalCtx := ctx
+		if race.FinalTimeout > 0 {
+			var cancel context.CancelFunc
+			finalCtx, cancel = context.WithTimeout(ctx, race.FinalTimeout)
+			defer cancel()
+		}
+		resp, err := finalBuilder.Generate(finalCtx)
+		latency := time.Since(start)
+		<-fastDone
+		result.Final, result.FinalErr, result.FinalLatency = resp, err, latency
+		if race.OnFinal != nil {
+			race.OnFinal(result)
+		}
+	}()
+
+	resp, err := fastBuilder.Generate(ctx)
+	result.Provisional, result.ProvisionalErr, result.ProvisionalLatency = resp, err, time.Since(start)
+	close(fastDone)
+	if err == nil {
+		return resp, nil
+	}
+
+	<-finalDone
+	if result.FinalErr != nil {
+		return nil, result.FinalErr
+	}
+	return result.Final, nil
+}
@@ -0,0 +1,94 @@
+package wormhole_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+// gatedProvider holds Text responses until release is closed.
+type gatedProvider struct {
+	*mocktesting.MockProvider
+	release chan struct{}
+}
+
+func (p *gatedProvider) Text(ctx context.Context, request types.TextRequest) (*types.TextResponse, error) {
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return p.MockProvider.Text(ctx, request)
+}
+
+func newProvisionalClient(fast types.Provider, slow types.Provider) *wormhole.Wormhole {
+	return wormhole.New(
+		wormhole.WithDefaultProvider("slow"),
+		wormhole.WithCustomProvider("slow", func(types.ProviderConfig) (types.Provider, error) { return slow, nil }),
+		wormhole.WithProviderConfig("slow", types.ProviderConfig{}),
+		wormhole.WithCustomProvider("fast", func(types.ProviderConfig) (types.Provider, error) { return fast, nil }),
+		wormhole.WithProviderConfig("fast", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+	)
+}
+
+func TestGenerateProvisionalReturnsFastAnswerThenUpgrades(t *testing.T) {
+	t.Parallel()
+
+	slow := &gatedProvider{
+		MockProvider: mocktesting.NewMockProvider("slow").WithTextResponse(types.TextResponse{Text: "considered answer"}),
+		release:      make(chan struct{}),
+	}
+	fast := mocktesting.NewMockProvider("fast").WithTextResponse(types.TextResponse{Text: "quick draft"})
+	client := newProvisionalClient(fast, slow)
+	defer client.Close()
+
+	finals := make(chan wormhole.ProvisionalResult, 1)
+	draft, err := client.Text().Model("big-model").Prompt("Explain").GenerateProvisional(context.Background(), wormhole.ProvisionalRace{
+		Fast:    wormhole.TextRoute{Provider: "fast", Model: "small-model"},
+		OnFinal: func(r wormhole.ProvisionalResult) { finals <- r },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "quick draft", draft.Text)
+
+	close(slow.release)
+	select {
+	case result := <-finals:
+		require.NoError(t, result.FinalErr)
+		assert.Equal(t, "considered answer", result.Final.Text)
+		assert.Equal(t, "quick draft", result.Provisional.Text)
+		assert.GreaterOrEqual(t, result.FinalLatency, result.ProvisionalLatency)
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnFinal was not called")
+	}
+}
+
+func TestGenerateProvisionalFallsBackToFinalWhenFastFails(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	close(release)
+	slow := &gatedProvider{
+		MockProvider: mocktesting.NewMockProvider("slow").WithTextResponse(types.TextResponse{Text: "considered answer"}),
+		release:      release,
+	}
+	fast := mocktesting.NewMockProvider("fast").WithError("fast model down")
+	client := newProvisionalClient(fast, slow)
+	defer client.Close()
+
+	var got wormhole.ProvisionalResult
+	resp, err := client.Text().Model("big-model").Prompt("Explain").GenerateProvisional(context.Background(), wormhole.ProvisionalRace{
+		Fast:    wormhole.TextRoute{Provider: "fast"},
+		OnFinal: func(r wormhole.ProvisionalResult) { got = r },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "considered answer", resp.Text)
+	require.Error(t, got.ProvisionalErr, "OnFinal runs before the fallback answer is returned")
+}
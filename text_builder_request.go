@@ -1,6 +1,10 @@
 package wormhole
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
@@ -17,6 +21,24 @@ func (r textIdempotencyRequest) GetProviderOptions() map[string]any {
 	return r.Request.GetProviderOptions()
 }
 
+// Fingerprint extends the request fingerprint with the fallback routes, which
+// change which upstream calls a submission can make.
+func (r textIdempotencyRequest) Fingerprint() string {
+	fingerprint := r.Request.Fingerprint()
+	if fingerprint == "" || (len(r.FallbackModels) == 0 && len(r.ProviderFallbacks) == 0) {
+		return fingerprint
+	}
+	routes, err := json.Marshal(struct {
+		FallbackModels    []string    `json:"fallback_models,omitempty"`
+		ProviderFallbacks []TextRoute `json:"provider_fallbacks,omitempty"`
+	}{r.FallbackModels, r.ProviderFallbacks})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(append([]byte(fingerprint+"\n"), routes...))
+	return hex.EncodeToString(sum[:])
+}
+
 // TextRoute identifies a provider and model for a text generation attempt.
 type TextRoute struct {
 	Provider string `json:"provider"`
@@ -31,6 +53,12 @@ type TextRequestBuilder struct {
 	maxToolIterations     int      // Maximum number of tool execution rounds (default: 10)
 	fallbackModels        []string // Models to try in order if primary fails
 	providerFallbacks     []TextRoute
+	documents             []*types.DocumentMedia // Attached to the last user message at execution (see Document)
+	documentErr           error                  // First Document read failure, returned at execution
+	citeDocuments         bool                   // Ask the provider to cite attached documents (see CiteDocuments)
+	modelResolved         bool                   // Model already went through resolveModel (structured streaming)
+	preferWarm            bool                   // Route around a cold primary (see PreferWarm)
+	autoContinueRounds    int                    // Continuations allowed after a length stop (see AutoContinue)
 }
 
 // Using sets the provider to use
@@ -106,9 +134,52 @@ func (b *TextRequestBuilder) SystemPrompt(prompt string) *TextRequestBuilder {
 	return b
 }
 
+// UsePrompt renders a registered prompt ("name@version", or a bare name for
+// the latest version) with vars and uses it as the system and user prompt.
+// The prompt's model applies when none is set, and the request is tagged with
+// prompt and prompt_version for metrics and logs. A missing prompt or
+// variable fails the request at execution.
+func (b *TextRequestBuilder) UsePrompt(ref string, vars map[string]any) *TextRequestBuilder {
+	prompt, system, user, ok := b.usePrompt(ref, vars)
+	if !ok {
+		return b
+	}
+	if system != "" {
+		b.request.SystemPrompt = system
+	}
+	b.request.Messages = []types.Message{types.NewUserMessage(user)}
+	if b.request.Model == "" {
+		b.request.Model = prompt.Model
+	}
+	return b
+}
+
+// Experiment assigns the request a variant of the named experiment (see
+// Wormhole.Experiments) and applies its provider, model, prompt and
+// parameters. Requests with the same key, such as a user ID, always get the
+// same variant. vars render the variant's prompt, if it has one. Settings made
+// after Experiment override the variant's. The request is tagged with
+// experiment and experiment_variant, and Generate records its latency, tokens
+// and errors in the variant's stats. An unknown experiment fails the request
+// at execution.
+func (b *TextRequestBuilder) Experiment(name, key string, vars map[string]any) *TextRequestBuilder {
+	variant, ok := b.useExperiment(name, key)
+	if !ok {
+		return b
+	}
+	if variant.Prompt != "" {
+		b.UsePrompt(variant.Prompt, vars)
+	}
+	applyExperimentVariant(&b.request.BaseRequest, variant)
+	return b
+}
+
 // Clone creates a deep copy of the builder with all settings preserved.
 // This allows you to create variations from a base configuration.
 //
+// A configured builder works as a template: any number of goroutines may
+// Clone it at once, provided none of them modifies the template itself.
+//
 // Example:
 //
 //	base := client.Text().Model("gpt-4o").Temperature(0.7)
@@ -133,14 +204,34 @@ func (b *TextRequestBuilder) Clone() *TextRequestBuilder {
 
 	return &TextRequestBuilder{
 		CommonBuilder: CommonBuilder{
-			wormhole: b.wormhole,
-			provider: b.provider,
-			baseURL:  b.baseURL,
+			wormhole:      b.wormhole,
+			provider:      b.provider,
+			baseURL:       b.baseURL,
+			tags:          b.tags,
+			priority:      b.priority,
+			cache:         b.cache,
+			scopedOptions: b.scopedOptions,
+
+			promptErr:     b.promptErr,
+			experiment:    b.experiment,
+			experimentErr: b.experimentErr,
 		},
 		request:               clonedRequest,
 		toolExecutionOverride: clonedOverride,
 		maxToolIterations:     b.maxToolIterations,
 		fallbackModels:        clonedFallbacks,
 		providerFallbacks:     clonedProviderFallbacks,
+		documents:             append([]*types.DocumentMedia(nil), b.documents...),
+		documentErr:           b.documentErr,
+		citeDocuments:         b.citeDocuments,
+		preferWarm:            b.preferWarm,
+		autoContinueRounds:    b.autoContinueRounds,
 	}
 }
+
+// Request returns a copy of the request configured so far, before defaults,
+// aliases and attached documents are applied. Save it and pass it to
+// Wormhole.TextFrom to start new builders from the same settings.
+func (b *TextRequestBuilder) Request() types.TextRequest {
+	return *cloneTextRequest(b.request)
+}
@@ -11,8 +11,12 @@ import (
 
 // Stream executes the request and returns a streaming response
 func (b *TextRequestBuilder) Stream(ctx context.Context) (<-chan types.StreamChunk, error) {
-	baseRequest := cloneTextRequest(b.request)
-	prepareTextExecutionRequest(baseRequest)
+	b = b.warmRouted()
+	ctx = b.taggedContext(ctx)
+	baseRequest, err := b.executionRequest()
+	if err != nil {
+		return nil, err
+	}
 
 	if len(baseRequest.Messages) == 0 {
 		return nil, types.ErrInvalidRequest.WithDetails("no messages provided")
@@ -20,6 +24,9 @@ func (b *TextRequestBuilder) Stream(ctx context.Context) (<-chan types.StreamChu
 	if baseRequest.Model == "" {
 		return nil, types.ErrInvalidRequest.WithDetails("no model specified")
 	}
+	if baseRequest.N > 1 {
+		return nil, types.ErrInvalidRequest.WithDetails("n above 1 cannot be streamed; use Generate")
+	}
 
 	modelsToTry := make([]string, 0, 1+len(b.fallbackModels))
 	modelsToTry = append(modelsToTry, baseRequest.Model)
@@ -47,7 +54,7 @@ func (b *TextRequestBuilder) Stream(ctx context.Context) (<-chan types.StreamChu
 	// Let the provider handle model validation at request time
 	// Provider handles all model validation and constraints
 	stream := make(chan types.StreamChunk)
-	providerFallbacks := append([]TextRoute(nil), b.providerFallbacks...)
+	providerFallbacks := wormhole.resolveTextRoutes(b.providerFallbacks, baseRequest.Model)
 	go b.streamWithFallback(ctx, provider, release, b.getProvider(), baseRequest, modelsToTry, providerFallbacks, stream)
 	return stream, nil
 }
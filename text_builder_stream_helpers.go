@@ -12,11 +12,13 @@ func (b *TextRequestBuilder) openStream(ctx context.Context, cancel context.Canc
 	var err error
 
 	ctx = contextWithProviderOperation(ctx, provider, "stream")
+	scoped := *request
+	scoped.ProviderOptions = b.providerOptionsFor(provider, scoped.ProviderOptions)
 	if b.getWormhole().providerMiddleware != nil {
 		handler := b.getWormhole().providerMiddleware.ApplyStream(provider.Stream)
-		stream, err = handler(ctx, *request)
+		stream, err = handler(ctx, scoped)
 	} else {
-		stream, err = provider.Stream(ctx, *request)
+		stream, err = provider.Stream(ctx, scoped)
 	}
 	if err != nil {
 		return nil, err
@@ -90,6 +92,11 @@ func cloneTextRequest(src *types.TextRequest) *types.TextRequest {
 		},
 		SystemPrompt:   src.SystemPrompt,
 		ResponseFormat: types.CloneValue(src.ResponseFormat),
+		Logprobs:       src.Logprobs,
+		TopLogprobs:    src.TopLogprobs,
+		WebSearch:      src.WebSearch.Clone(),
+		Audio:          src.Audio.Clone(),
+		N:              src.N,
 	}
 
 	cloneBaseRequestFields(&cloned.BaseRequest, &src.BaseRequest)
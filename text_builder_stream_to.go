@@ -0,0 +1,121 @@
+package wormhole
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// StreamTo streams the response into w, writing each text delta as it
+// arrives, and returns the assembled response when the stream ends. If w
+// can flush (http.Flusher, or a Flush() error method such as bufio.Writer's),
+// it is flushed after every delta so browsers and terminals see text as it is
+// generated.
+//
+// When the stream or a write fails, StreamTo stops the upstream request and
+// returns the response assembled so far together with the error. Reasoning
+// and tool calls are collected into the response but never written to w.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+//	    resp, err := client.Text().Model("gpt-5-mini").Prompt(q).StreamTo(r.Context(), w)
+//	    ...
+//	}
+func (b *TextRequestBuilder) StreamTo(ctx context.Context, w io.Writer) (*types.TextResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := b.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flush := writerFlush(w)
+	resp := &types.TextResponse{Created: time.Now()}
+	var text, refusal strings.Builder
+	var firstErr error
+	for chunk := range stream {
+		if firstErr != nil {
+			continue // drain so the provider goroutine can exit
+		}
+		if chunk.Error != nil {
+			firstErr = chunk.Error
+			cancel()
+			continue
+		}
+		mergeStreamChunk(resp, &chunk, &refusal)
+		content := chunk.Content()
+		if content == "" {
+			continue
+		}
+		text.WriteString(content)
+		if _, err := io.WriteString(w, content); err != nil {
+			firstErr = err
+			cancel()
+			continue
+		}
+		if err := flush(); err != nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	resp.Text = text.String()
+	resp.Refusal = refusal.String()
+	return resp, firstErr
+}
+
+// mergeStreamChunk folds everything but the text delta of chunk into resp.
+func mergeStreamChunk(resp *types.TextResponse, chunk *types.StreamChunk, refusal *strings.Builder) {
+	if resp.ID == "" {
+		resp.ID = chunk.ID
+	}
+	if resp.Provider == "" {
+		resp.Provider = chunk.Provider
+	}
+	if resp.Model == "" {
+		resp.Model = chunk.Model
+	}
+	refusal.WriteString(chunk.Refusal)
+	if chunk.Thinking != nil {
+		if resp.Thinking == nil {
+			resp.Thinking = &types.Thinking{Provider: chunk.Thinking.Provider}
+		}
+		resp.Thinking.Content += chunk.Thinking.Content
+		if chunk.Thinking.Signature != "" {
+			resp.Thinking.Signature = chunk.Thinking.Signature
+		}
+	}
+	if chunk.ToolCall != nil {
+		resp.ToolCalls = append(resp.ToolCalls, *chunk.ToolCall)
+	}
+	resp.ToolCalls = append(resp.ToolCalls, chunk.ToolCalls...)
+	resp.Logprobs = append(resp.Logprobs, chunk.Logprobs...)
+	if chunk.FinishReason != nil {
+		resp.FinishReason = *chunk.FinishReason
+		resp.RawFinishReason = chunk.RawFinishReason
+	}
+	if chunk.Usage != nil {
+		resp.Usage = chunk.Usage
+	}
+}
+
+// writerFlush returns a function that flushes w if it buffers output.
+func writerFlush(w io.Writer) func() error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush
+	case interface{ Flush() }:
+		return func() error {
+			f.Flush()
+			return nil
+		}
+	default:
+		return func() error { return nil }
+	}
+}
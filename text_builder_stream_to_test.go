@@ -0,0 +1,83 @@
+package wormhole_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func newStreamToClient(t *testing.T, chunks []types.TextChunk) *wormhole.Wormhole {
+	t.Helper()
+	provider := mocktesting.NewMockProvider("mock").WithStreamChunks(chunks)
+	client := wormhole.New(
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+	)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestStreamToWritesDeltasAndAssemblesResponse(t *testing.T) {
+	t.Parallel()
+
+	chunks := mocktesting.StreamChunksFrom("Hello", ", ", "world")
+	chunks[2].Usage = &types.Usage{TotalTokens: 9}
+	client := newStreamToClient(t, chunks)
+
+	recorder := httptest.NewRecorder()
+	resp, err := client.Text().Model("test-model").Prompt("hi").StreamTo(context.Background(), recorder)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello, world", recorder.Body.String())
+	assert.True(t, recorder.Flushed, "http.Flusher is flushed as text arrives")
+	assert.Equal(t, "Hello, world", resp.Text)
+	assert.Equal(t, types.FinishReasonStop, resp.FinishReason)
+	assert.Equal(t, "test-model", resp.Model)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, 9, resp.Usage.TotalTokens)
+}
+
+type failingWriter struct{ writes int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("client went away")
+}
+
+func TestStreamToStopsOnWriteError(t *testing.T) {
+	t.Parallel()
+
+	client := newStreamToClient(t, mocktesting.StreamChunksFrom("one", "two", "three"))
+
+	writer := &failingWriter{}
+	resp, err := client.Text().Model("test-model").Prompt("hi").StreamTo(context.Background(), writer)
+	require.EqualError(t, err, "client went away")
+	assert.Equal(t, 1, writer.writes, "no writes after the first failure")
+	require.NotNil(t, resp)
+	assert.Equal(t, "one", resp.Text)
+}
+
+func TestStreamToReturnsStreamError(t *testing.T) {
+	t.Parallel()
+
+	client := newStreamToClient(t, []types.TextChunk{{Text: "partial"}, {Error: errors.New("stream broke")}})
+
+	var out strings.Builder
+	resp, err := client.Text().Model("test-model").Prompt("hi").StreamTo(context.Background(), &out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stream broke")
+	assert.Equal(t, "partial", out.String())
+	assert.Equal(t, "partial", resp.Text)
+}
@@ -2,11 +2,13 @@ package wormhole
 
 import (
 	"encoding/json"
+	"slices"
 
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
-// ToJSON returns the request as JSON
+// ToJSON returns the request as JSON for inspection. It cannot be read back;
+// use ToSpec for a form FromSpec can replay.
 func (b *TextRequestBuilder) ToJSON() (string, error) {
 	jsonBytes, err := json.MarshalIndent(b.request, "", "  ")
 	if err != nil {
@@ -24,6 +26,10 @@ func (b *TextRequestBuilder) ToJSON() (string, error) {
 //   - Temperature is in valid range (0.0-2.0)
 //   - TopP is in valid range (0.0-1.0)
 //   - MaxTokens is positive if specified
+//   - Penalties, TopK, MinP, LogitBias, TopLogprobs and N are in range
+//   - With model validation on, the model supports every feature the request
+//     uses: vision, audio input and output, tools, parallel tool calls,
+//     logprobs and seed (see CapabilityMatrix)
 //
 // Example:
 //
@@ -78,8 +84,49 @@ func (b *TextRequestBuilder) Validate() error {
 			errs.Add("presence_penalty", "range", pp, "must be between -2.0 and 2.0")
 		}
 	}
+	if b.request.TopK != nil && *b.request.TopK <= 0 {
+		errs.Add("top_k", "positive", *b.request.TopK, "must be positive")
+	}
+	if b.request.MinP != nil {
+		minP := *b.request.MinP
+		if minP < 0 || minP > 1 {
+			errs.Add("min_p", "range", minP, "must be between 0.0 and 1.0")
+		}
+	}
+	if b.request.RepetitionPenalty != nil && *b.request.RepetitionPenalty <= 0 {
+		errs.Add("repetition_penalty", "positive", *b.request.RepetitionPenalty, "must be positive")
+	}
+	for token, bias := range b.request.LogitBias {
+		if bias < -100 || bias > 100 {
+			errs.Add("logit_bias", "range", token, "biases must be between -100 and 100")
+			break
+		}
+	}
+	if n := b.request.TopLogprobs; n < 0 || n > 20 {
+		errs.Add("top_logprobs", "range", n, "must be between 0 and 20")
+	}
+	if n := b.request.N; n < 0 || n > 128 {
+		errs.Add("n", "range", n, "must be between 1 and 128")
+	}
+
+	if err := errs.Error(); err != nil {
+		return err
+	}
+	return b.validateModelFeatures()
+}
 
-	return errs.Error()
+// validateModelFeatures checks the model against the registry the same way
+// Generate will, so a feature the model lacks fails before any call.
+func (b *TextRequestBuilder) validateModelFeatures() error {
+	wormhole := b.getWormhole()
+	if wormhole == nil || b.request.Model == "" {
+		return nil
+	}
+	required := textRequiredCapabilities(b.request, false, false)
+	if len(b.documents) > 0 && !slices.Contains(required, types.CapabilityVision) {
+		required = append(required, types.CapabilityVision)
+	}
+	return wormhole.validateModelAttempt(b.getProvider(), b.resolveModel(b.request.Model), textModelCapabilities, required)
 }
 
 // MustValidate calls Validate() and panics if validation fails.
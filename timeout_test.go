@@ -165,4 +165,35 @@ func TestDefaultRetryConfiguration(t *testing.T) {
 	})
 }
 
+func TestDefaultUserAgentConfiguration(t *testing.T) {
+	t.Parallel()
+
+	capture := func(providerConfig types.ProviderConfig, opts ...Option) types.ProviderConfig {
+		var capturedConfig types.ProviderConfig
+		testFactory := func(config types.ProviderConfig) (types.Provider, error) {
+			capturedConfig = config
+			return mockpkg.NewMockProvider("test"), nil
+		}
+		wormhole := New(append(opts,
+			WithCustomProvider("test", testFactory),
+			WithProviderConfig("test", providerConfig),
+		)...)
+		_, err := wormhole.Provider("test")
+		require.NoError(t, err)
+		return capturedConfig
+	}
+
+	config := capture(types.ProviderConfig{APIKey: "test-key"}, WithUserAgent("acme-bot/1.0"))
+	assert.Equal(t, "acme-bot/1.0", config.UserAgent)
+	assert.False(t, config.DisableUserAgent)
+
+	config = capture(types.ProviderConfig{APIKey: "test-key"}, WithoutUserAgent())
+	assert.Empty(t, config.UserAgent)
+	assert.True(t, config.DisableUserAgent)
+
+	config = capture(types.ProviderConfig{APIKey: "test-key", UserAgent: "own/2"}, WithoutUserAgent())
+	assert.Equal(t, "own/2", config.UserAgent, "provider config wins over the client default")
+	assert.False(t, config.DisableUserAgent)
+}
+
 // Note: mockProvider is already defined in provider_registration_test.go
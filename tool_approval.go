@@ -0,0 +1,178 @@
+package wormhole
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// RequireApproval marks a tool whose calls wait for a human decision. Before
+// the handler runs, the call is posted to the Approvals attached to the
+// context and the tool loop pauses until Approve or Reject is called with its
+// PendingApproval.ID.
+// Without Approvals in the context the call is refused.
+//
+// Example:
+//
+//	client.RegisterTool("drop_table", "Drop a database table", schema, handler,
+//		wormhole.RequireApproval())
+func RequireApproval() ToolOption {
+	return func(definition *types.ToolDefinition) {
+		definition.RequiresApproval = true
+	}
+}
+
+// PendingApproval is a tool call waiting for a decision.
+type PendingApproval struct {
+	// ID identifies this approval to Approve and Reject. The Approvals
+	// queue assigns it, so it is unique even when the model sends calls
+	// with empty or repeated IDs.
+	ID string
+	// CallID is the model's tool call ID, which may be empty.
+	CallID    string
+	ToolName  string
+	Arguments map[string]any
+	Requested time.Time
+}
+
+type approvalDecision struct {
+	approved bool
+	reason   string
+}
+
+// Approvals holds tool calls waiting for a human decision. Attach it to an
+// agent with AgentBuilder.Approvals, or to any tool loop with
+// WithApprovals. It is safe for concurrent use: decisions usually arrive
+// from another goroutine, such as an HTTP handler behind a review UI.
+type Approvals struct {
+	onPending func(PendingApproval)
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[string]pendingCall // by PendingApproval.ID
+}
+
+type pendingCall struct {
+	call     PendingApproval
+	decision chan approvalDecision
+}
+
+// NewApprovals creates an approval queue. onPending, when set, is called for
+// each call that starts waiting; it may decide the call itself.
+func NewApprovals(onPending func(PendingApproval)) *Approvals {
+	return &Approvals{onPending: onPending, pending: make(map[string]pendingCall)}
+}
+
+// Approve lets the pending call with approval ID id run.
+func (a *Approvals) Approve(id string) error {
+	return a.decide(id, approvalDecision{approved: true})
+}
+
+// Reject refuses the pending call with approval ID id. The model receives
+// the call as failed, with reason when it is not empty.
+func (a *Approvals) Reject(id, reason string) error {
+	return a.decide(id, approvalDecision{reason: reason})
+}
+
+// Pending returns the calls waiting for a decision, oldest first.
+func (a *Approvals) Pending() []PendingApproval {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	calls := make([]PendingApproval, 0, len(a.pending))
+	for _, pending := range a.pending {
+		calls = append(calls, pending.call)
+	}
+	sort.Slice(calls, func(i, j int) bool { return calls[i].Requested.Before(calls[j].Requested) })
+	return calls
+}
+
+func (a *Approvals) decide(id string, decision approvalDecision) error {
+	a.mu.Lock()
+	pending, ok := a.pending[id]
+	delete(a.pending, id)
+	a.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no pending approval %q", id)
+	}
+	pending.decision <- decision
+	return nil
+}
+
+// wait posts the call and blocks until it is decided or ctx ends.
+func (a *Approvals) wait(ctx context.Context, toolCall types.ToolCall) (approvalDecision, error) {
+	pending := pendingCall{
+		call: PendingApproval{
+			CallID:    toolCall.ID,
+			ToolName:  toolCall.Name,
+			Arguments: types.CloneMap(toolCall.Arguments),
+			Requested: time.Now(),
+		},
+		decision: make(chan approvalDecision, 1),
+	}
+	a.mu.Lock()
+	a.nextID++
+	id := fmt.Sprintf("approval_%d", a.nextID)
+	pending.call.ID = id
+	a.pending[id] = pending
+	a.mu.Unlock()
+
+	if a.onPending != nil {
+		a.onPending(pending.call)
+	}
+
+	select {
+	case decision := <-pending.decision:
+		return decision, nil
+	case <-ctx.Done():
+		a.mu.Lock()
+		delete(a.pending, id)
+		a.mu.Unlock()
+		return approvalDecision{}, ctx.Err()
+	}
+}
+
+type approvalsContextKey struct{}
+
+// WithApprovals returns a context whose tool calls that require approval
+// wait on approvals.
+func WithApprovals(ctx context.Context, approvals *Approvals) context.Context {
+	return context.WithValue(ctx, approvalsContextKey{}, approvals)
+}
+
+// ApprovalsFromContext returns the approvals attached by WithApprovals, or
+// nil.
+func ApprovalsFromContext(ctx context.Context) *Approvals {
+	approvals, _ := ctx.Value(approvalsContextKey{}).(*Approvals)
+	return approvals
+}
+
+// awaitApproval holds a call to a tool that requires approval until it is
+// decided. It returns a failed result when the call may not run.
+func awaitApproval(ctx context.Context, toolCall types.ToolCall) (types.ToolResult, bool) {
+	approvals := ApprovalsFromContext(ctx)
+	if approvals == nil {
+		return types.ToolResult{
+			ToolCallID: toolCall.ID,
+			Error:      fmt.Sprintf("tool %q requires approval but no approver is attached", toolCall.Name),
+		}, false
+	}
+	decision, err := approvals.wait(ctx, toolCall)
+	if err != nil {
+		return types.ToolResult{
+			ToolCallID: toolCall.ID,
+			Error:      fmt.Sprintf("tool %q approval: %v", toolCall.Name, err),
+		}, false
+	}
+	if !decision.approved {
+		message := fmt.Sprintf("tool %q call was rejected by the reviewer", toolCall.Name)
+		if decision.reason != "" {
+			message += ": " + decision.reason
+		}
+		return types.ToolResult{ToolCallID: toolCall.ID, Error: message}, false
+	}
+	return types.ToolResult{}, true
+}
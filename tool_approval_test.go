@@ -0,0 +1,140 @@
+package wormhole
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func approvalRegistry(calls *atomic.Int32) *ToolRegistry {
+	registry := NewToolRegistry()
+	definition := types.NewToolDefinition(types.Tool{Name: "drop_table"}, func(context.Context, map[string]any) (any, error) {
+		calls.Add(1)
+		return "dropped", nil
+	})
+	applyToolOptions(definition, []ToolOption{RequireApproval()})
+	registry.Register("drop_table", definition)
+	return registry
+}
+
+func dropTableCall(id string) types.ToolCall {
+	return types.ToolCall{ID: id, Name: "drop_table", Arguments: map[string]any{"table": "sessions"}}
+}
+
+func TestAgentPausesForToolApproval(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockToolProvider{responses: []*types.TextResponse{
+		{ToolCalls: []types.ToolCall{dropTableCall("call_1")}},
+		{ToolCalls: []types.ToolCall{dropTableCall("call_2")}},
+		{Text: "done"},
+	}}
+	client := newAgentTestClient(provider)
+
+	var calls atomic.Int32
+	var approvals *Approvals
+	var seen []PendingApproval
+	approvals = NewApprovals(func(call PendingApproval) {
+		seen = append(seen, call)
+		// Decide from another goroutine, as a review UI would.
+		go func() {
+			if call.CallID == "call_1" {
+				assert.NoError(t, approvals.Approve(call.ID))
+				return
+			}
+			assert.NoError(t, approvals.Reject(call.ID, "not during business hours"))
+		}()
+	})
+
+	result, err := client.Agent().Using("mock").Model("mock-model").Approvals(approvals).
+		AddTool("drop_table", "Drop a table", map[string]any{"type": "object"}, func(context.Context, map[string]any) (any, error) {
+			calls.Add(1)
+			return "dropped", nil
+		}, RequireApproval()).
+		Run(context.Background(), "clean up")
+	require.NoError(t, err)
+
+	require.Len(t, seen, 2)
+	assert.Equal(t, "drop_table", seen[0].ToolName)
+	assert.Equal(t, map[string]any{"table": "sessions"}, seen[0].Arguments)
+	assert.Equal(t, int32(1), calls.Load(), "only the approved call ran")
+	assert.Equal(t, "dropped", result.Steps[0].ToolResults[0].Result)
+	assert.Contains(t, result.Steps[1].ToolResults[0].Error, "rejected by the reviewer: not during business hours")
+	assert.Empty(t, approvals.Pending())
+}
+
+func TestToolApprovalFailsClosed(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	executor := NewToolExecutor(approvalRegistry(&calls))
+
+	result := executor.Execute(context.Background(), dropTableCall("call_1"))
+	assert.Contains(t, result.Error, "no approver is attached")
+
+	approvals := NewApprovals(nil)
+	ctx, cancel := context.WithTimeout(WithApprovals(context.Background(), approvals), 20*time.Millisecond)
+	defer cancel()
+	result = executor.Execute(ctx, dropTableCall("call_2"))
+	assert.Contains(t, result.Error, "deadline exceeded")
+	assert.Empty(t, approvals.Pending(), "an abandoned call stops waiting")
+	assert.Error(t, approvals.Approve("approval_1"))
+	assert.Zero(t, calls.Load())
+}
+
+func TestApprovalsPendingListsWaitingCalls(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	executor := NewToolExecutor(approvalRegistry(&calls))
+	approvals := NewApprovals(nil)
+	ctx := WithApprovals(context.Background(), approvals)
+
+	done := make(chan types.ToolResult, 1)
+	go func() { done <- executor.Execute(ctx, dropTableCall("call_1")) }()
+
+	require.Eventually(t, func() bool { return len(approvals.Pending()) == 1 }, time.Second, time.Millisecond)
+	pending := approvals.Pending()[0]
+	assert.Equal(t, "call_1", pending.CallID)
+	require.NoError(t, approvals.Approve(pending.ID))
+
+	result := <-done
+	assert.Empty(t, result.Error)
+	assert.Equal(t, "dropped", result.Result)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestApprovalsKeepCallsWithEmptyOrRepeatedIDsApart(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	executor := NewToolExecutor(approvalRegistry(&calls))
+	approvals := NewApprovals(nil)
+	ctx := WithApprovals(context.Background(), approvals)
+
+	done := make(chan types.ToolResult, 4)
+	for _, id := range []string{"", "", "call_1", "call_1"} {
+		go func() { done <- executor.Execute(ctx, dropTableCall(id)) }()
+	}
+
+	require.Eventually(t, func() bool { return len(approvals.Pending()) == 4 }, time.Second, time.Millisecond)
+	ids := map[string]bool{}
+	for _, pending := range approvals.Pending() {
+		ids[pending.ID] = true
+	}
+	require.Len(t, ids, 4, "every waiting call has its own approval ID")
+
+	for id := range ids {
+		require.NoError(t, approvals.Approve(id))
+	}
+	for range 4 {
+		assert.Empty(t, (<-done).Error)
+	}
+	assert.Equal(t, int32(4), calls.Load())
+}
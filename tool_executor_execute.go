@@ -123,6 +123,26 @@ func (e *ToolExecutor) Execute(ctx context.Context, toolCall types.ToolCall) typ
 		}
 	}
 
+	// Idempotent tools answer repeated identical calls from the
+	// conversation's memory without running the handler again.
+	memory := ToolMemoryFromContext(ctx)
+	if definition.Idempotent && memory != nil {
+		if result, ok := memory.lookup(toolCall.Name, args); ok {
+			return types.ToolResult{
+				ToolCallID: toolCall.ID,
+				Result:     result,
+			}
+		}
+	}
+
+	// Approval waits before capacity and the timeout: a reviewer may take
+	// longer than any tool.
+	if definition.RequiresApproval {
+		if result, approved := awaitApproval(ctx, toolCall); !approved {
+			return result
+		}
+	}
+
 	// Acquire capacity immediately before starting user code. The permit is
 	// released by the execution goroutine, not by this caller, because a handler
 	// may ignore cancellation and continue after Execute returns.
@@ -136,9 +156,13 @@ func (e *ToolExecutor) Execute(ctx context.Context, toolCall types.ToolCall) typ
 
 	// ToolTimeout bounds handler execution, not time spent waiting for capacity.
 	// Derive it only after a permit is held, while preserving caller cancellation.
-	if e.safetyConfig.HasTimeout() {
+	timeout := e.safetyConfig.ToolTimeout
+	if definition.Timeout != 0 {
+		timeout = definition.Timeout
+	}
+	if timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, e.safetyConfig.ToolTimeout)
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
@@ -225,6 +249,9 @@ func (e *ToolExecutor) Execute(ctx context.Context, toolCall types.ToolCall) typ
 	if e.circuitBreaker != nil {
 		e.circuitBreaker.RecordSuccess()
 	}
+	if definition.Idempotent && memory != nil {
+		memory.store(toolCall.Name, args, result, definition.ResultTTL)
+	}
 
 	return types.ToolResult{
 		ToolCallID: toolCall.ID,
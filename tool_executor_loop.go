@@ -47,6 +47,7 @@ func (e *ToolExecutor) executeWithTools(
 	if maxIterations <= 0 {
 		maxIterations = 10 // Default safety limit
 	}
+	ctx = ensureToolMemory(ctx)
 
 	// Make a copy of the request to avoid modifying the original
 	currentRequest := request
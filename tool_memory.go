@@ -0,0 +1,145 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// ToolOption adjusts a tool at registration.
+type ToolOption func(*types.ToolDefinition)
+
+// IdempotentTool marks a tool whose result depends only on its arguments.
+// Models often repeat the same lookup within a conversation; identical calls
+// (same name and arguments) are answered from the conversation's ToolMemory
+// instead of re-hitting the external API. A zero ttl reuses results for the
+// whole conversation.
+//
+// Example:
+//
+//	client.RegisterTool("get_weather", "Current weather", schema, handler,
+//		wormhole.IdempotentTool(5*time.Minute))
+func IdempotentTool(ttl time.Duration) ToolOption {
+	return func(definition *types.ToolDefinition) {
+		definition.Idempotent = true
+		definition.ResultTTL = ttl
+	}
+}
+
+// ToolTimeout overrides the executor's per-tool timeout for one tool. A
+// negative d runs the tool without a timeout.
+func ToolTimeout(d time.Duration) ToolOption {
+	return func(definition *types.ToolDefinition) {
+		definition.Timeout = d
+	}
+}
+
+func applyToolOptions(definition *types.ToolDefinition, opts []ToolOption) {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(definition)
+		}
+	}
+}
+
+// ToolMemory remembers successful results of idempotent tools. Each tool
+// loop (Generate with tools enabled, or an agent run) gets a fresh memory;
+// attach one with WithToolMemory to share results across several calls that
+// belong to the same conversation.
+type ToolMemory struct {
+	mu      sync.Mutex
+	entries map[string]toolMemoryEntry
+	hits    int
+}
+
+type toolMemoryEntry struct {
+	result    any
+	expiresAt time.Time
+}
+
+// NewToolMemory creates an empty tool memory.
+func NewToolMemory() *ToolMemory {
+	return &ToolMemory{entries: make(map[string]toolMemoryEntry)}
+}
+
+// Hits returns how many tool calls were answered from memory.
+func (m *ToolMemory) Hits() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hits
+}
+
+// Len returns the number of remembered results, including expired ones.
+func (m *ToolMemory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+func (m *ToolMemory) lookup(name string, args map[string]any) (any, bool) {
+	key, ok := toolMemoryKey(name, args)
+	if !ok {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, found := m.entries[key]
+	if !found {
+		return nil, false
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	m.hits++
+	return entry.result, true
+}
+
+func (m *ToolMemory) store(name string, args map[string]any, result any, ttl time.Duration) {
+	key, ok := toolMemoryKey(name, args)
+	if !ok {
+		return
+	}
+	entry := toolMemoryEntry{result: result}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = entry
+}
+
+// toolMemoryKey identifies a call by tool name and arguments. encoding/json
+// sorts map keys, so argument order does not matter.
+func toolMemoryKey(name string, args map[string]any) (string, bool) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return name + "\x00" + string(data), true
+}
+
+type toolMemoryContextKey struct{}
+
+// WithToolMemory returns a context whose tool calls share memory.
+func WithToolMemory(ctx context.Context, memory *ToolMemory) context.Context {
+	return context.WithValue(ctx, toolMemoryContextKey{}, memory)
+}
+
+// ToolMemoryFromContext returns the memory attached by WithToolMemory, or nil.
+func ToolMemoryFromContext(ctx context.Context) *ToolMemory {
+	memory, _ := ctx.Value(toolMemoryContextKey{}).(*ToolMemory)
+	return memory
+}
+
+// ensureToolMemory gives a tool loop its own memory unless the caller
+// attached one.
+func ensureToolMemory(ctx context.Context) context.Context {
+	if ToolMemoryFromContext(ctx) != nil {
+		return ctx
+	}
+	return WithToolMemory(ctx, NewToolMemory())
+}
@@ -0,0 +1,120 @@
+package wormhole
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func weatherCall(id, city string) types.ToolCall {
+	return types.ToolCall{ID: id, Type: "function", Name: "get_weather", Arguments: map[string]any{"city": city}}
+}
+
+func TestIdempotentToolResultsAreReusedWithinConversation(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	registry := NewToolRegistry()
+	definition := types.NewToolDefinition(types.Tool{Name: "get_weather"}, func(_ context.Context, args map[string]any) (any, error) {
+		calls.Add(1)
+		return map[string]any{"city": args["city"], "temp": 72}, nil
+	})
+	applyToolOptions(definition, []ToolOption{IdempotentTool(0)})
+	registry.Register("get_weather", definition)
+
+	provider := &mockToolProvider{
+		responses: []*types.TextResponse{
+			{ToolCalls: []types.ToolCall{weatherCall("call_1", "SF")}},
+			{ToolCalls: []types.ToolCall{weatherCall("call_2", "SF"), weatherCall("call_3", "LA")}},
+			{Text: "done"},
+		},
+	}
+	request := types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "gpt-5"},
+		Messages:    []types.Message{types.NewUserMessage("weather?")},
+	}
+
+	executor := NewToolExecutor(registry)
+	resp, err := executor.ExecuteWithTools(context.Background(), request, provider, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "done", resp.Text)
+	assert.Equal(t, int32(2), calls.Load(), "the repeated SF lookup is served from memory")
+
+	// A new conversation starts with an empty memory.
+	provider.callCount = 0
+	_, err = executor.ExecuteWithTools(context.Background(), request, provider, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestToolMemorySharedAcrossCallsAndTTL(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	registry := NewToolRegistry()
+	handler := func(context.Context, map[string]any) (any, error) {
+		calls.Add(1)
+		return "sunny", nil
+	}
+	idempotent := types.NewToolDefinition(types.Tool{Name: "get_weather"}, handler)
+	applyToolOptions(idempotent, []ToolOption{IdempotentTool(20 * time.Millisecond)})
+	registry.Register("get_weather", idempotent)
+	registry.Register("send_email", types.NewToolDefinition(types.Tool{Name: "send_email"}, handler))
+
+	memory := NewToolMemory()
+	ctx := WithToolMemory(context.Background(), memory)
+	executor := NewToolExecutor(registry)
+
+	first := executor.Execute(ctx, weatherCall("a", "SF"))
+	second := executor.Execute(ctx, weatherCall("b", "SF"))
+	assert.Equal(t, "sunny", second.Result)
+	assert.Equal(t, "b", second.ToolCallID)
+	assert.Equal(t, first.Result, second.Result)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 1, memory.Hits())
+
+	// Tools registered without the flag always run.
+	email := types.ToolCall{ID: "c", Name: "send_email", Arguments: map[string]any{"to": "ops"}}
+	executor.Execute(ctx, email)
+	executor.Execute(ctx, email)
+	assert.Equal(t, int32(3), calls.Load())
+
+	time.Sleep(30 * time.Millisecond)
+	executor.Execute(ctx, weatherCall("d", "SF"))
+	assert.Equal(t, int32(4), calls.Load(), "expired results run the handler again")
+}
+
+func TestToolTimeoutOverridesExecutorTimeout(t *testing.T) {
+	t.Parallel()
+
+	slow := func(ctx context.Context, _ map[string]any) (any, error) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return "finished", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	registry := NewToolRegistry()
+	for name, timeout := range map[string]time.Duration{"short": 5 * time.Millisecond, "unbounded": -1} {
+		definition := types.NewToolDefinition(types.Tool{Name: name}, slow)
+		applyToolOptions(definition, []ToolOption{ToolTimeout(timeout)})
+		registry.Register(name, definition)
+	}
+	config := DefaultToolSafetyConfig()
+	config.ToolTimeout = 20 * time.Millisecond
+	executor := NewToolExecutorWithConfig(registry, config)
+
+	short := executor.Execute(context.Background(), types.ToolCall{ID: "1", Name: "short", Arguments: map[string]any{}})
+	assert.NotEmpty(t, short.Error)
+
+	unbounded := executor.Execute(context.Background(), types.ToolCall{ID: "2", Name: "unbounded", Arguments: map[string]any{}})
+	assert.Empty(t, unbounded.Error)
+	assert.Equal(t, "finished", unbounded.Result)
+}
@@ -115,8 +115,12 @@ func cloneToolDefinition(definition *types.ToolDefinition) *types.ToolDefinition
 		return nil
 	}
 	return &types.ToolDefinition{
-		Tool:    types.CloneTool(definition.Tool),
-		Handler: definition.Handler,
+		Tool:             types.CloneTool(definition.Tool),
+		Handler:          definition.Handler,
+		Idempotent:       definition.Idempotent,
+		ResultTTL:        definition.ResultTTL,
+		Timeout:          definition.Timeout,
+		RequiresApproval: definition.RequiresApproval,
 	}
 }
 
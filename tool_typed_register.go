@@ -43,6 +43,7 @@ func RegisterTypedTool[Args any, Result any](
 	name string,
 	description string,
 	handler func(ctx context.Context, args Args) (Result, error),
+	opts ...ToolOption,
 ) error {
 	// Generate schema from the Args type
 	var args Args
@@ -69,7 +70,7 @@ func RegisterTypedTool[Args any, Result any](
 	}
 
 	// Register with the existing registry
-	client.toolRegistry.Register(name, &types.ToolDefinition{
+	definition := &types.ToolDefinition{
 		Tool: types.Tool{
 			Type:        "function",
 			Name:        name,
@@ -77,7 +78,9 @@ func RegisterTypedTool[Args any, Result any](
 			InputSchema: schema,
 		},
 		Handler: wrappedHandler,
-	})
+	}
+	applyToolOptions(definition, opts)
+	client.toolRegistry.Register(name, definition)
 
 	return nil
 }
@@ -0,0 +1,45 @@
+package types
+
+import "time"
+
+// NewAudio wraps recorded audio for a user message. format names the
+// encoding, such as "wav" or "mp3".
+//
+// Example:
+//
+//	msg := types.NewUserMessage("What is the speaker asking for?")
+//	msg.Media = append(msg.Media, types.NewAudio(clip, "wav"))
+func NewAudio(data []byte, format string) *AudioMedia {
+	return &AudioMedia{Data: data, Format: format}
+}
+
+// AudioOutput asks a chat model that can speak, such as
+// gpt-4o-audio-preview, to reply in audio as well as text. The reply's
+// audio and its transcript come back in TextResponse.Audio.
+type AudioOutput struct {
+	// Voice is the provider's voice name, e.g. "alloy".
+	Voice string `json:"voice"`
+	// Format is the audio encoding, e.g. "wav", "mp3", or "pcm16".
+	Format string `json:"format"`
+}
+
+// Clone returns a copy of a.
+func (a *AudioOutput) Clone() *AudioOutput {
+	if a == nil {
+		return nil
+	}
+	clone := *a
+	return &clone
+}
+
+// ResponseAudio is the audio a model spoke in reply.
+type ResponseAudio struct {
+	// ID references the audio in later turns; set it as
+	// AssistantMessage.AudioID to continue the conversation.
+	ID         string `json:"id,omitempty"`
+	Data       []byte `json:"data,omitempty"`
+	Format     string `json:"format,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+	// ExpiresAt is when the provider forgets ID.
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
@@ -0,0 +1,90 @@
+package types
+
+// Response Metadata keys set by the caching middleware.
+const (
+	// CacheHitKey marks a response served by the exact-match cache middleware.
+	CacheHitKey = "cache_hit"
+	// SemanticCacheScoreKey holds the similarity score of a semantic cache hit.
+	SemanticCacheScoreKey = "semantic_cache_score"
+)
+
+// CacheSource says which cache, if any, served a response.
+type CacheSource string
+
+const (
+	// CacheSourceNone means the provider generated the response. Its prompt
+	// may still have been partly served from the provider's prompt cache.
+	CacheSourceNone CacheSource = ""
+	// CacheSourceLocal is an exact-match hit in the cache middleware.
+	CacheSourceLocal CacheSource = "local"
+	// CacheSourceSemantic is a hit in the semantic cache middleware.
+	CacheSourceSemantic CacheSource = "semantic"
+)
+
+// CacheInfo normalizes every kind of caching that touched a response:
+// provider prompt caching (OpenAI cached_tokens, Anthropic cache reads and
+// writes, Gemini cached content) and the local exact-match and semantic
+// caches. Get it with TextResponse.CacheInfo or StructuredResponse.CacheInfo.
+type CacheInfo struct {
+	Source CacheSource
+	// SimilarityScore is the cosine similarity of a semantic cache hit.
+	SimilarityScore float64
+	// PromptTokens, CachedTokens and CacheWriteTokens come from the response
+	// usage. On a local or semantic hit they describe the original request,
+	// whose tokens the hit did not spend again.
+	PromptTokens     int
+	CompletionTokens int
+	CachedTokens     int
+	CacheWriteTokens int
+}
+
+// Hit reports whether a local or semantic cache served the response.
+func (c CacheInfo) Hit() bool {
+	return c.Source != CacheSourceNone
+}
+
+// CachedRatio is the fraction of prompt tokens served from the provider's
+// prompt cache, in [0, 1].
+func (c CacheInfo) CachedRatio() float64 {
+	if c.PromptTokens <= 0 {
+		return 0
+	}
+	return min(float64(c.CachedTokens)/float64(c.PromptTokens), 1)
+}
+
+// SavedTokens is the number of tokens the request avoided paying full price
+// for: every token of a local or semantic hit, otherwise the provider-cached
+// prompt tokens.
+func (c CacheInfo) SavedTokens() int {
+	if c.Hit() {
+		return c.PromptTokens + c.CompletionTokens
+	}
+	return c.CachedTokens
+}
+
+// CacheInfo returns the response's normalized cache statistics.
+func (r *TextResponse) CacheInfo() CacheInfo {
+	return newCacheInfo(r.Usage, r.Metadata)
+}
+
+// CacheInfo returns the response's normalized cache statistics.
+func (r *StructuredResponse) CacheInfo() CacheInfo {
+	return newCacheInfo(r.Usage, r.Metadata)
+}
+
+func newCacheInfo(usage *Usage, metadata map[string]any) CacheInfo {
+	var info CacheInfo
+	if usage != nil {
+		info.PromptTokens = usage.PromptTokens
+		info.CompletionTokens = usage.CompletionTokens
+		info.CachedTokens = usage.CacheReadTokens
+		info.CacheWriteTokens = usage.CacheWriteTokens
+	}
+	if score, ok := metadata[SemanticCacheScoreKey].(float64); ok {
+		info.Source = CacheSourceSemantic
+		info.SimilarityScore = score
+	} else if hit, _ := metadata[CacheHitKey].(bool); hit {
+		info.Source = CacheSourceLocal
+	}
+	return info
+}
@@ -0,0 +1,31 @@
+package types
+
+import "testing"
+
+func TestCacheInfoNormalizesProviderAndLocalCaching(t *testing.T) {
+	t.Parallel()
+
+	fresh := (&TextResponse{Usage: &Usage{PromptTokens: 1000, CompletionTokens: 50, CacheReadTokens: 800, CacheWriteTokens: 100}}).CacheInfo()
+	if fresh.Hit() || fresh.CachedTokens != 800 || fresh.CacheWriteTokens != 100 {
+		t.Fatalf("fresh = %+v", fresh)
+	}
+	if fresh.CachedRatio() != 0.8 || fresh.SavedTokens() != 800 {
+		t.Fatalf("ratio = %v, saved = %d", fresh.CachedRatio(), fresh.SavedTokens())
+	}
+
+	local := (&StructuredResponse{
+		Usage:    &Usage{PromptTokens: 30, CompletionTokens: 12},
+		Metadata: map[string]any{CacheHitKey: true},
+	}).CacheInfo()
+	if local.Source != CacheSourceLocal || local.SavedTokens() != 42 {
+		t.Fatalf("local = %+v", local)
+	}
+
+	semantic := (&TextResponse{Metadata: map[string]any{SemanticCacheScoreKey: 0.97}}).CacheInfo()
+	if semantic.Source != CacheSourceSemantic || semantic.SimilarityScore != 0.97 {
+		t.Fatalf("semantic = %+v", semantic)
+	}
+	if (CacheInfo{}).CachedRatio() != 0 {
+		t.Fatal("ratio without prompt tokens should be 0")
+	}
+}
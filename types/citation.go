@@ -0,0 +1,61 @@
+package types
+
+// CitationType says what kind of source a Citation points at.
+type CitationType string
+
+const (
+	// CitationTypeURL is a web page, from web search or search grounding.
+	CitationTypeURL CitationType = "url"
+	// CitationTypeDocument is a document supplied with the request or
+	// retrieved by the provider's RAG store.
+	CitationTypeDocument CitationType = "document"
+	// CitationTypeFile is a file uploaded to the provider, cited by ID.
+	CitationTypeFile CitationType = "file"
+)
+
+// Citation is one source the provider attributed part of a response to. It
+// unifies OpenAI annotations, Anthropic citations, and Gemini grounding
+// metadata; fields a provider does not report are left zero.
+type Citation struct {
+	Type  CitationType `json:"type"`
+	URL   string       `json:"url,omitempty"`
+	Title string       `json:"title,omitempty"`
+	// FileID identifies a provider-hosted file (CitationTypeFile).
+	FileID string `json:"file_id,omitempty"`
+	// DocumentIndex is the position of the cited document among the
+	// documents in the request, for Anthropic document citations.
+	DocumentIndex int `json:"document_index,omitempty"`
+	// CitedText is the quoted source passage or the supported span of the
+	// response, when the provider reports one.
+	CitedText string `json:"cited_text,omitempty"`
+	// StartIndex and EndIndex bound the cited span of TextResponse.Text:
+	// character offsets for OpenAI and Anthropic, byte offsets for Gemini.
+	// Both are zero when the provider gives no span.
+	StartIndex int `json:"start_index,omitempty"`
+	EndIndex   int `json:"end_index,omitempty"`
+	// SourceStart and SourceEnd locate the passage inside the cited document:
+	// character offsets for plain text, page numbers for PDFs, or content
+	// block indices, as the provider reports them.
+	SourceStart int `json:"source_start,omitempty"`
+	SourceEnd   int `json:"source_end,omitempty"`
+	// Confidence is the provider's score for the attribution, from 0 to 1;
+	// zero when unscored.
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// CitedURLs returns the distinct URLs cited in r, in first-cited order.
+func (r *TextResponse) CitedURLs() []string {
+	if r == nil {
+		return nil
+	}
+	var urls []string
+	seen := make(map[string]bool)
+	for _, c := range r.Citations {
+		if c.URL == "" || seen[c.URL] {
+			continue
+		}
+		seen[c.URL] = true
+		urls = append(urls, c.URL)
+	}
+	return urls
+}
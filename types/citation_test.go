@@ -0,0 +1,23 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTextResponseCitedURLs(t *testing.T) {
+	t.Parallel()
+
+	resp := &TextResponse{Citations: []Citation{
+		{Type: CitationTypeURL, URL: "https://go.dev/a"},
+		{Type: CitationTypeFile, FileID: "file-1"},
+		{Type: CitationTypeURL, URL: "https://go.dev/b"},
+		{Type: CitationTypeURL, URL: "https://go.dev/a", StartIndex: 10},
+	}}
+	if got, want := resp.CitedURLs(), []string{"https://go.dev/a", "https://go.dev/b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("CitedURLs() = %v, want %v", got, want)
+	}
+	if got := (*TextResponse)(nil).CitedURLs(); got != nil {
+		t.Fatalf("nil CitedURLs() = %v", got)
+	}
+}
@@ -71,10 +71,15 @@ func CloneModelInfo(src *ModelInfo) *ModelInfo {
 	dst := *src
 	if src.Cost != nil {
 		cost := *src.Cost
+		if src.Cost.Images != nil {
+			images := *src.Cost.Images
+			cost.Images = &images
+		}
 		dst.Cost = &cost
 	}
 	dst.Capabilities = append([]ModelCapability(nil), src.Capabilities...)
 	dst.Constraints = CloneMap(src.Constraints)
+	dst.ParamConstraints = src.ParamConstraints.Clone()
 	return &dst
 }
 
@@ -95,6 +100,13 @@ func CloneMedia(src Media) Media {
 		dst := *media
 		dst.Data = append([]byte(nil), media.Data...)
 		return &dst
+	case *AudioMedia:
+		if media == nil {
+			return (*AudioMedia)(nil)
+		}
+		dst := *media
+		dst.Data = append([]byte(nil), media.Data...)
+		return &dst
 	default:
 		return src
 	}
@@ -109,6 +121,12 @@ func CloneMessage(src Message) Message {
 		}
 		dst := *message
 		return &dst
+	case *DeveloperMessage:
+		if message == nil {
+			return (*DeveloperMessage)(nil)
+		}
+		dst := *message
+		return &dst
 	case *UserMessage:
 		if message == nil {
 			return (*UserMessage)(nil)
@@ -8,8 +8,14 @@ const (
 
 // HTTP Header constants
 const (
-	HeaderContentType   = "Content-Type"
-	HeaderAuthorization = "Authorization"
-	HeaderCacheControl  = "Cache-Control"
-	HeaderAccept        = "Accept"
+	HeaderContentType     = "Content-Type"
+	HeaderAuthorization   = "Authorization"
+	HeaderCacheControl    = "Cache-Control"
+	HeaderAccept          = "Accept"
+	HeaderAcceptEncoding  = "Accept-Encoding"
+	HeaderContentEncoding = "Content-Encoding"
+	HeaderUserAgent       = "User-Agent"
+
+	HeaderOpenAIOrganization = "OpenAI-Organization"
+	HeaderOpenAIProject      = "OpenAI-Project"
 )
@@ -14,6 +14,7 @@ package types
 //	response, _ := client.Text().Conversation(conv).Generate(ctx)
 type Conversation struct {
 	messages []Message
+	turns    []ConversationTurn
 }
 
 // NewConversation creates a new empty conversation.
@@ -31,6 +32,13 @@ func (c *Conversation) System(content string) *Conversation {
 	return c
 }
 
+// Developer adds a developer message, the instruction role OpenAI reasoning
+// models use in place of system. Other providers treat it as system.
+func (c *Conversation) Developer(content string) *Conversation {
+	c.messages = append(c.messages, NewDeveloperMessage(content))
+	return c
+}
+
 // User adds a user message to the conversation.
 func (c *Conversation) User(content string) *Conversation {
 	c.messages = append(c.messages, NewUserMessage(content))
@@ -73,16 +81,17 @@ func (c *Conversation) IsEmpty() bool {
 	return len(c.messages) == 0
 }
 
-// Clear removes all messages from the conversation.
+// Clear removes all messages and recorded turns from the conversation.
 func (c *Conversation) Clear() *Conversation {
 	c.messages = make([]Message, 0)
+	c.turns = nil
 	return c
 }
 
 // Clone creates a deep copy of the conversation.
 // This is useful for branching conversations or testing variations.
 func (c *Conversation) Clone() *Conversation {
-	return &Conversation{messages: CloneMessages(c.messages)}
+	return &Conversation{messages: CloneMessages(c.messages), turns: c.Turns()}
 }
 
 // Last returns the last message in the conversation, or nil if empty.
@@ -113,12 +122,12 @@ func (c *Conversation) SystemMessage() Message {
 	return nil
 }
 
-// WithoutSystem returns a new conversation with system messages removed.
-// Useful when the provider doesn't support system messages natively.
+// WithoutSystem returns a new conversation with system and developer messages
+// removed. Useful when the provider doesn't support system messages natively.
 func (c *Conversation) WithoutSystem() *Conversation {
 	filtered := NewConversation()
 	for _, msg := range c.messages {
-		if msg.GetRole() != RoleSystem {
+		if !msg.GetRole().IsSystem() {
 			filtered.messages = append(filtered.messages, msg)
 		}
 	}
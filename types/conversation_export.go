@@ -0,0 +1,415 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// TranscriptOptions configures ExportMarkdown and ExportHTML.
+type TranscriptOptions struct {
+	// Title heads the transcript. Default "Conversation".
+	Title string
+	// Redact rewrites every piece of conversation text before it is
+	// rendered: message content, thinking, tool arguments and results, and
+	// media URLs. Use it to strip PII or secrets before sharing.
+	Redact func(text string) string
+	// IncludeThinking renders assistant reasoning. It is omitted by default.
+	IncludeThinking bool
+	// OmitSystem leaves out system messages.
+	OmitSystem bool
+}
+
+// transcriptEntry is one message prepared for rendering: redacted, with its
+// recorded turn and footnote number when it has one.
+type transcriptEntry struct {
+	role      Role
+	content   string
+	thinking  string
+	media     []transcriptMedia
+	toolCalls []transcriptToolCall
+	toolName  string
+	toolError string
+	turn      *ConversationTurn
+	footnote  int
+}
+
+type transcriptMedia struct {
+	kind  string // "image", "document", or "audio"
+	label string
+	url   string // http(s) only; inline data is described, not embedded
+	size  int
+}
+
+type transcriptToolCall struct {
+	id        string
+	name      string
+	arguments string
+}
+
+// ExportMarkdown renders the conversation as a Markdown transcript for
+// sharing and debugging. Tool calls and results are shown as code blocks,
+// images and documents as links (inline data is summarized, never embedded),
+// and each recorded turn's model, token usage, cost and latency as a
+// footnote on its reply.
+//
+// Example:
+//
+//	md := conv.ExportMarkdown(types.TranscriptOptions{
+//	    Title:  "Ticket 4821",
+//	    Redact: func(s string) string { return emailPattern.ReplaceAllString(s, "[email]") },
+//	})
+func (c *Conversation) ExportMarkdown(opts TranscriptOptions) string {
+	entries, turns := c.transcript(opts)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", transcriptTitle(opts))
+
+	for _, e := range entries {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "**%s**", roleLabel(e.role))
+		if e.role == RoleTool && e.toolName != "" {
+			fmt.Fprintf(&b, " `%s`", e.toolName)
+		}
+		if e.turn != nil && e.turn.Model != "" {
+			fmt.Fprintf(&b, " (%s)", e.turn.Model)
+		}
+		if e.footnote > 0 {
+			fmt.Fprintf(&b, "[^%d]", e.footnote)
+		}
+		b.WriteString("\n\n")
+
+		if e.thinking != "" {
+			for _, line := range strings.Split(e.thinking, "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+			b.WriteString("\n")
+		}
+		switch {
+		case e.role == RoleTool:
+			b.WriteString(markdownFence(e.content, ""))
+			if e.toolError != "" {
+				fmt.Fprintf(&b, "\nError: %s\n", e.toolError)
+			}
+		case e.content != "":
+			b.WriteString(e.content + "\n")
+		}
+		for _, m := range e.media {
+			if m.url != "" {
+				fmt.Fprintf(&b, "\n- %s: [%s](%s)", mediaKindLabel(m.kind), m.label, m.url)
+			} else {
+				fmt.Fprintf(&b, "\n- %s: %s (%s inline)", mediaKindLabel(m.kind), m.label, formatBytes(m.size))
+			}
+		}
+		if len(e.media) > 0 {
+			b.WriteString("\n")
+		}
+		for _, call := range e.toolCalls {
+			fmt.Fprintf(&b, "\nTool call `%s` (`%s`):\n\n", call.name, call.id)
+			b.WriteString(markdownFence(call.arguments, "json"))
+		}
+	}
+
+	if len(turns) > 0 {
+		b.WriteString("\n")
+		for _, e := range entries {
+			if e.footnote > 0 {
+				fmt.Fprintf(&b, "[^%d]: %s\n", e.footnote, turnSummary(*e.turn))
+			}
+		}
+	}
+	if total := c.totalSummary(); total != "" {
+		fmt.Fprintf(&b, "\n---\n\n%s\n", total)
+	}
+	return b.String()
+}
+
+// ExportHTML renders the conversation as a self-contained HTML page with the
+// same content as ExportMarkdown. All conversation text is escaped, and only
+// http and https media URLs become links.
+func (c *Conversation) ExportHTML(opts TranscriptOptions) string {
+	entries, turns := c.transcript(opts)
+	title := html.EscapeString(transcriptTitle(opts))
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", title)
+	b.WriteString("<style>\n" + transcriptCSS + "</style>\n</head>\n<body>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", title)
+
+	for _, e := range entries {
+		fmt.Fprintf(&b, "<section class=\"message %s\">\n<h2>%s", html.EscapeString(string(e.role)), html.EscapeString(roleLabel(e.role)))
+		if e.role == RoleTool && e.toolName != "" {
+			fmt.Fprintf(&b, " <code>%s</code>", html.EscapeString(e.toolName))
+		}
+		if e.turn != nil && e.turn.Model != "" {
+			fmt.Fprintf(&b, " <span class=\"model\">%s</span>", html.EscapeString(e.turn.Model))
+		}
+		if e.footnote > 0 {
+			fmt.Fprintf(&b, "<sup><a href=\"#usage-%d\">%d</a></sup>", e.footnote, e.footnote)
+		}
+		b.WriteString("</h2>\n")
+
+		if e.thinking != "" {
+			fmt.Fprintf(&b, "<blockquote class=\"thinking\">%s</blockquote>\n", html.EscapeString(e.thinking))
+		}
+		switch {
+		case e.role == RoleTool:
+			fmt.Fprintf(&b, "<pre><code>%s</code></pre>\n", html.EscapeString(e.content))
+			if e.toolError != "" {
+				fmt.Fprintf(&b, "<p class=\"error\">Error: %s</p>\n", html.EscapeString(e.toolError))
+			}
+		case e.content != "":
+			fmt.Fprintf(&b, "<div class=\"content\">%s</div>\n", html.EscapeString(e.content))
+		}
+		if len(e.media) > 0 {
+			b.WriteString("<ul class=\"media\">\n")
+			for _, m := range e.media {
+				if m.url != "" {
+					fmt.Fprintf(&b, "<li>%s: <a href=\"%s\">%s</a></li>\n", html.EscapeString(mediaKindLabel(m.kind)), html.EscapeString(m.url), html.EscapeString(m.label))
+				} else {
+					fmt.Fprintf(&b, "<li>%s: %s (%s inline)</li>\n", html.EscapeString(mediaKindLabel(m.kind)), html.EscapeString(m.label), formatBytes(m.size))
+				}
+			}
+			b.WriteString("</ul>\n")
+		}
+		for _, call := range e.toolCalls {
+			fmt.Fprintf(&b, "<p class=\"tool-call\">Tool call <code>%s</code> (<code>%s</code>):</p>\n<pre><code>%s</code></pre>\n",
+				html.EscapeString(call.name), html.EscapeString(call.id), html.EscapeString(call.arguments))
+		}
+		b.WriteString("</section>\n")
+	}
+
+	if len(turns) > 0 {
+		b.WriteString("<ol class=\"usage\">\n")
+		for _, e := range entries {
+			if e.footnote > 0 {
+				fmt.Fprintf(&b, "<li id=\"usage-%d\">%s</li>\n", e.footnote, html.EscapeString(turnSummary(*e.turn)))
+			}
+		}
+		b.WriteString("</ol>\n")
+	}
+	if total := c.totalSummary(); total != "" {
+		fmt.Fprintf(&b, "<footer>%s</footer>\n", html.EscapeString(total))
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+const transcriptCSS = `body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; }
+.message { border-left: 3px solid #ccc; margin: 1rem 0; padding: 0 1rem; }
+.message.user { border-color: #3b82f6; }
+.message.assistant { border-color: #10b981; }
+.message.tool { border-color: #f59e0b; }
+.message h2 { font-size: 1rem; }
+.model { color: #666; font-weight: normal; }
+.content { white-space: pre-wrap; }
+.thinking { color: #666; white-space: pre-wrap; }
+.error { color: #b91c1c; }
+pre { background: #f5f5f5; overflow-x: auto; padding: 0.5rem; }
+.usage, footer { color: #666; font-size: 0.875rem; }
+`
+
+// transcript prepares the messages for rendering and numbers the footnotes.
+func (c *Conversation) transcript(opts TranscriptOptions) ([]transcriptEntry, []ConversationTurn) {
+	redact := opts.Redact
+	if redact == nil {
+		redact = func(text string) string { return text }
+	}
+	turns := c.Turns()
+	byMessage := make(map[int]*ConversationTurn, len(turns))
+	for i := range turns {
+		byMessage[turns[i].MessageIndex] = &turns[i]
+	}
+
+	entries := make([]transcriptEntry, 0, len(c.messages))
+	footnotes := 0
+	for i, msg := range c.messages {
+		e := transcriptEntry{role: msg.GetRole()}
+		switch m := msg.(type) {
+		case *SystemMessage:
+			if opts.OmitSystem {
+				continue
+			}
+			e.content = redact(m.Content)
+		case *UserMessage:
+			e.content = redact(m.Content)
+			for _, media := range m.Media {
+				e.media = append(e.media, newTranscriptMedia(media, redact))
+			}
+		case *AssistantMessage:
+			e.content = redact(m.Content)
+			if opts.IncludeThinking && m.Thinking != nil {
+				e.thinking = redact(m.Thinking.Content)
+			}
+			for _, call := range m.ToolCalls {
+				e.toolCalls = append(e.toolCalls, transcriptToolCall{
+					id:        call.ID,
+					name:      call.Name,
+					arguments: redact(toolCallArguments(call)),
+				})
+			}
+		case *ToolResultMessage:
+			e.content = redact(m.Content)
+			e.toolName = m.FunctionName
+			e.toolError = redact(m.Error)
+		default:
+			if msg.GetRole().IsSystem() && opts.OmitSystem {
+				continue
+			}
+			if text, ok := msg.GetContent().(string); ok {
+				e.content = redact(text)
+			}
+		}
+		if turn, ok := byMessage[i]; ok {
+			footnotes++
+			e.turn, e.footnote = turn, footnotes
+		}
+		entries = append(entries, e)
+	}
+	return entries, turns
+}
+
+func newTranscriptMedia(media Media, redact func(string) string) transcriptMedia {
+	switch m := media.(type) {
+	case *ImageMedia:
+		tm := transcriptMedia{kind: "image", label: m.MimeType, size: len(m.Data) + len(m.Base64Data)*3/4}
+		if isHTTPURL(m.URL) {
+			tm.url = redact(m.URL)
+			tm.label = tm.url
+		}
+		if tm.label == "" {
+			tm.label = "image"
+		}
+		return tm
+	case *DocumentMedia:
+		tm := transcriptMedia{kind: "document", label: m.Filename, size: len(m.Data)}
+		if tm.label == "" {
+			tm.label = m.MimeType
+		}
+		if isHTTPURL(m.URL) {
+			tm.url = redact(m.URL)
+			if m.Filename == "" {
+				tm.label = tm.url
+			}
+		}
+		return tm
+	case *AudioMedia:
+		return transcriptMedia{kind: "audio", label: "audio/" + m.Format, size: len(m.Data)}
+	default:
+		return transcriptMedia{kind: media.GetType(), label: media.GetType()}
+	}
+}
+
+func toolCallArguments(call ToolCall) string {
+	if call.ArgsInvalid && call.Function != nil {
+		return call.Function.Arguments
+	}
+	data, err := json.MarshalIndent(call.Arguments, "", "  ")
+	if err != nil || call.Arguments == nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+func (c *Conversation) totalSummary() string {
+	if len(c.turns) == 0 {
+		return ""
+	}
+	usage := c.TotalUsage()
+	summary := fmt.Sprintf("Total: %d turns · %d prompt + %d completion tokens", len(c.turns), usage.PromptTokens, usage.CompletionTokens)
+	if cost := c.TotalCost(); cost > 0 {
+		summary += fmt.Sprintf(" · $%.6f", cost)
+	}
+	return summary
+}
+
+func turnSummary(turn ConversationTurn) string {
+	parts := []string{turn.Model}
+	if turn.Provider != "" {
+		parts[0] += " via " + turn.Provider
+	}
+	if turn.Usage != nil {
+		parts = append(parts, fmt.Sprintf("%d prompt + %d completion tokens", turn.Usage.PromptTokens, turn.Usage.CompletionTokens))
+		if turn.Usage.CacheReadTokens > 0 {
+			parts = append(parts, fmt.Sprintf("%d cached", turn.Usage.CacheReadTokens))
+		}
+	}
+	if turn.Cost != nil {
+		parts = append(parts, fmt.Sprintf("$%.6f", *turn.Cost))
+	}
+	if turn.Latency > 0 {
+		parts = append(parts, turn.Latency.Round(time.Millisecond).String())
+	}
+	if turn.FinishReason != "" && turn.FinishReason != FinishReasonStop {
+		parts = append(parts, "finish: "+string(turn.FinishReason))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func transcriptTitle(opts TranscriptOptions) string {
+	if opts.Title == "" {
+		return "Conversation"
+	}
+	return opts.Title
+}
+
+func roleLabel(role Role) string {
+	switch role {
+	case RoleSystem:
+		return "System"
+	case RoleDeveloper:
+		return "Developer"
+	case RoleUser:
+		return "User"
+	case RoleAssistant:
+		return "Assistant"
+	case RoleTool:
+		return "Tool"
+	default:
+		return string(role)
+	}
+}
+
+func mediaKindLabel(kind string) string {
+	switch kind {
+	case "image":
+		return "Image"
+	case "document":
+		return "Document"
+	case "audio":
+		return "Audio"
+	default:
+		return kind
+	}
+}
+
+// markdownFence wraps text in a code fence longer than any backtick run in it.
+func markdownFence(text, lang string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + lang + "\n" + strings.TrimRight(text, "\n") + "\n" + fence + "\n"
+}
+
+func isHTTPURL(url string) bool {
+	return strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://")
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
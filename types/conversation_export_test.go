@@ -0,0 +1,75 @@
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exportTestConversation() *Conversation {
+	conv := NewConversation().System("You are support.")
+	conv.Add(&UserMessage{
+		Content: "My email is ada@example.com. Weather in Paris? <script>",
+		Media: []Media{
+			&ImageMedia{URL: "https://example.com/screenshot.png", MimeType: "image/png"},
+			&ImageMedia{Data: make([]byte, 2048), MimeType: "image/jpeg"},
+		},
+	})
+	conv.Record(&TextResponse{
+		Provider:  "openai",
+		Model:     "gpt-5-mini",
+		ToolCalls: []ToolCall{{ID: "call_1", Name: "get_weather", Arguments: map[string]any{"city": "Paris"}}},
+		Thinking:  &Thinking{Content: "Need the weather tool."},
+		Usage:     &Usage{PromptTokens: 120, CompletionTokens: 30},
+	}, 1200*time.Millisecond, nil)
+	conv.Add(&ToolResultMessage{ToolCallID: "call_1", FunctionName: "get_weather", Content: "```sunny```"})
+	conv.Record(&TextResponse{Model: "gpt-5-mini", Text: "It is sunny.", Usage: &Usage{PromptTokens: 160, CompletionTokens: 8}}, 300*time.Millisecond, nil)
+	return conv
+}
+
+func TestConversationExportMarkdown(t *testing.T) {
+	t.Parallel()
+
+	md := exportTestConversation().ExportMarkdown(TranscriptOptions{
+		Title:  "Ticket 4821",
+		Redact: func(s string) string { return strings.ReplaceAll(s, "ada@example.com", "[email]") },
+	})
+
+	assert.True(t, strings.HasPrefix(md, "# Ticket 4821\n"))
+	assert.Contains(t, md, "**System**\n\nYou are support.")
+	assert.Contains(t, md, "My email is [email].")
+	assert.NotContains(t, md, "ada@example.com")
+	assert.Contains(t, md, "- Image: [https://example.com/screenshot.png](https://example.com/screenshot.png)")
+	assert.Contains(t, md, "- Image: image/jpeg (2.0 KB inline)")
+	assert.Contains(t, md, "**Assistant** (gpt-5-mini)[^1]")
+	assert.Contains(t, md, "Tool call `get_weather` (`call_1`):\n\n```json\n{\n  \"city\": \"Paris\"\n}\n```")
+	assert.Contains(t, md, "**Tool** `get_weather`\n\n````\n```sunny```\n````")
+	assert.NotContains(t, md, "Need the weather tool.", "thinking is opt-in")
+	assert.Contains(t, md, "[^1]: gpt-5-mini via openai · 120 prompt + 30 completion tokens · 1.2s")
+	assert.Contains(t, md, "[^2]: gpt-5-mini · 160 prompt + 8 completion tokens · 300ms")
+	assert.Contains(t, md, "Total: 2 turns · 280 prompt + 38 completion tokens")
+
+	withThinking := exportTestConversation().ExportMarkdown(TranscriptOptions{IncludeThinking: true, OmitSystem: true})
+	assert.Contains(t, withThinking, "> Need the weather tool.")
+	assert.NotContains(t, withThinking, "You are support.")
+	assert.True(t, strings.HasPrefix(withThinking, "# Conversation\n"))
+}
+
+func TestConversationExportHTMLEscapes(t *testing.T) {
+	t.Parallel()
+
+	page := exportTestConversation().ExportHTML(TranscriptOptions{Title: "<b>Ticket</b>"})
+
+	assert.Contains(t, page, "<title>&lt;b&gt;Ticket&lt;/b&gt;</title>")
+	assert.Contains(t, page, "Weather in Paris? &lt;script&gt;")
+	assert.NotContains(t, page, "<script>")
+	assert.Contains(t, page, `<a href="https://example.com/screenshot.png">`)
+	assert.Contains(t, page, `<sup><a href="#usage-2">2</a></sup>`)
+	assert.Contains(t, page, `<li id="usage-1">gpt-5-mini via openai · 120 prompt + 30 completion tokens · 1.2s</li>`)
+
+	unsafe := NewConversation()
+	unsafe.Add(&UserMessage{Content: "see", Media: []Media{&ImageMedia{URL: "javascript:alert(1)", MimeType: "image/png"}}})
+	assert.NotContains(t, unsafe.ExportHTML(TranscriptOptions{}), "javascript:")
+}
@@ -0,0 +1,133 @@
+package types
+
+import "time"
+
+// ConversationTurn annotates one assistant reply in a Conversation with the
+// model that produced it, its token usage, estimated cost, and latency, so
+// spend can be attributed per turn. Turns marshal to JSON for export.
+type ConversationTurn struct {
+	Index        int               `json:"index"`         // zero-based turn number
+	MessageIndex int               `json:"message_index"` // position of the assistant reply in Messages()
+	Provider     string            `json:"provider,omitempty"`
+	Model        string            `json:"model"`
+	Usage        *Usage            `json:"usage,omitempty"`
+	Cost         *float64          `json:"cost,omitempty"` // estimated from registry pricing; nil when the model is unpriced
+	Latency      time.Duration     `json:"latency"`
+	FinishReason FinishReason      `json:"finish_reason,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"` // caller labels such as feature name
+	Created      time.Time         `json:"created"`
+}
+
+// Record appends resp as an assistant message and annotates it as a turn.
+// latency is the caller-measured request duration; tags are optional labels
+// (for example {"feature": "search"}) copied onto the turn. A nil resp is
+// ignored.
+//
+// Example:
+//
+//	start := time.Now()
+//	resp, err := client.Text().Conversation(conv).Generate(ctx)
+//	if err == nil {
+//	    conv.Record(resp, time.Since(start), map[string]string{"feature": "support"})
+//	}
+func (c *Conversation) Record(resp *TextResponse, latency time.Duration, tags map[string]string) *Conversation {
+	if resp == nil {
+		return c
+	}
+
+	reply := NewAssistantMessage(resp.Text)
+	reply.ToolCalls = CloneToolCalls(resp.ToolCalls)
+	if resp.Thinking != nil {
+		thinking := *resp.Thinking
+		reply.Thinking = &thinking
+	}
+	c.messages = append(c.messages, reply)
+
+	turn := ConversationTurn{
+		Index:        len(c.turns),
+		MessageIndex: len(c.messages) - 1,
+		Provider:     resp.Provider,
+		Model:        resp.Model,
+		Latency:      latency,
+		FinishReason: resp.FinishReason,
+		Created:      resp.Created,
+	}
+	if turn.Created.IsZero() {
+		turn.Created = time.Now()
+	}
+	if resp.Usage != nil {
+		usage := *resp.Usage
+		turn.Usage = &usage
+		if cost, err := EstimateModelCost(resp.Model, usage.PromptTokens, usage.CompletionTokens); err == nil {
+			turn.Cost = &cost
+		}
+	}
+	if len(tags) > 0 {
+		turn.Tags = make(map[string]string, len(tags))
+		for k, v := range tags {
+			turn.Tags[k] = v
+		}
+	}
+	c.turns = append(c.turns, turn)
+	return c
+}
+
+// Turns returns a copy of the recorded turns in order.
+func (c *Conversation) Turns() []ConversationTurn {
+	if len(c.turns) == 0 {
+		return nil
+	}
+	turns := make([]ConversationTurn, len(c.turns))
+	for i, turn := range c.turns {
+		turns[i] = cloneConversationTurn(turn)
+	}
+	return turns
+}
+
+// TotalUsage sums token usage across recorded turns.
+func (c *Conversation) TotalUsage() Usage {
+	var total Usage
+	for _, turn := range c.turns {
+		if turn.Usage == nil {
+			continue
+		}
+		total.PromptTokens += turn.Usage.PromptTokens
+		total.CompletionTokens += turn.Usage.CompletionTokens
+		total.TotalTokens += turn.Usage.TotalTokens
+		total.CacheReadTokens += turn.Usage.CacheReadTokens
+		total.CacheWriteTokens += turn.Usage.CacheWriteTokens
+		total.ReasoningTokens += turn.Usage.ReasoningTokens
+	}
+	return total
+}
+
+// TotalCost sums the estimated cost of priced turns. Unpriced turns
+// contribute nothing.
+func (c *Conversation) TotalCost() float64 {
+	var total float64
+	for _, turn := range c.turns {
+		if turn.Cost != nil {
+			total += *turn.Cost
+		}
+	}
+	return total
+}
+
+func cloneConversationTurn(src ConversationTurn) ConversationTurn {
+	dst := src
+	if src.Usage != nil {
+		usage := *src.Usage
+		dst.Usage = &usage
+	}
+	if src.Cost != nil {
+		cost := *src.Cost
+		dst.Cost = &cost
+	}
+	if src.Tags != nil {
+		dst.Tags = make(map[string]string, len(src.Tags))
+		for k, v := range src.Tags {
+			dst.Tags[k] = v
+		}
+	}
+	return dst
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationRecordAnnotatesTurns(t *testing.T) {
+	t.Parallel()
+
+	DefaultModelRegistry.Register(&ModelInfo{
+		ID:       "conversation-turn-priced",
+		Provider: "test",
+		Cost:     &ModelCost{InputTokens: 1, OutputTokens: 2, Currency: "USD"},
+	})
+
+	tags := map[string]string{"feature": "support"}
+	conv := NewConversation().System("be brief").User("hi")
+	conv.Record(&TextResponse{
+		Provider: "openai",
+		Model:    "conversation-turn-priced",
+		Text:     "hello",
+		Usage:    &Usage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500},
+	}, 120*time.Millisecond, tags)
+	conv.User("more")
+	conv.Record(&TextResponse{Model: "conversation-turn-unpriced", Text: "sure", Usage: &Usage{PromptTokens: 10, TotalTokens: 10}}, time.Second, nil)
+	conv.Record(nil, time.Second, nil)
+	tags["feature"] = "mutated"
+
+	require.Equal(t, 5, conv.Len())
+	assert.Equal(t, "hello", conv.Messages()[2].(*AssistantMessage).Content)
+
+	turns := conv.Turns()
+	require.Len(t, turns, 2)
+	assert.Equal(t, 0, turns[0].Index)
+	assert.Equal(t, 2, turns[0].MessageIndex)
+	assert.Equal(t, "openai", turns[0].Provider)
+	assert.Equal(t, 120*time.Millisecond, turns[0].Latency)
+	assert.Equal(t, "support", turns[0].Tags["feature"])
+	require.NotNil(t, turns[0].Cost)
+	assert.InDelta(t, 2.0, *turns[0].Cost, 1e-9)
+	assert.False(t, turns[0].Created.IsZero())
+	assert.Equal(t, 4, turns[1].MessageIndex)
+	assert.Nil(t, turns[1].Cost)
+
+	assert.Equal(t, 1510, conv.TotalUsage().TotalTokens)
+	assert.InDelta(t, 2.0, conv.TotalCost(), 1e-9)
+
+	turns[0].Usage.TotalTokens = 0
+	assert.Equal(t, 1500, conv.Turns()[0].Usage.TotalTokens, "Turns must return copies")
+	assert.Len(t, conv.Clone().Turns(), 2)
+	assert.Empty(t, conv.Clear().Turns())
+
+	data, err := json.Marshal(turns[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"model":"conversation-turn-unpriced"`)
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MimeTypePDF is the MIME type of PDF documents.
+const MimeTypePDF = "application/pdf"
+
+// NewDocument wraps document bytes. An empty mimeType is detected from the
+// content.
+func NewDocument(data []byte, mimeType string) *DocumentMedia {
+	if mimeType == "" {
+		mimeType = baseMimeType(http.DetectContentType(data))
+	}
+	return &DocumentMedia{Data: data, MimeType: mimeType}
+}
+
+// NewDocumentFromFile reads a document from disk. The MIME type comes from
+// the file extension, falling back to content detection.
+func NewDocumentFromFile(path string) (*DocumentMedia, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read document: %w", err)
+	}
+	doc := NewDocument(data, baseMimeType(mime.TypeByExtension(filepath.Ext(path))))
+	doc.Filename = filepath.Base(path)
+	return doc, nil
+}
+
+// DocumentLimits describes the documents a provider accepts.
+type DocumentLimits struct {
+	// MaxBytes caps the inline size of one document; zero means unlimited.
+	MaxBytes int
+	// MimeTypes lists accepted types. An entry ending in "/*" accepts the
+	// whole family, e.g. "text/*".
+	MimeTypes []string
+	// AllowURL reports whether URL-only documents can be sent.
+	AllowURL bool
+}
+
+// Check reports why doc cannot be sent under these limits, or nil.
+func (l DocumentLimits) Check(doc *DocumentMedia) error {
+	if doc == nil {
+		return fmt.Errorf("document is nil")
+	}
+	if len(doc.Data) == 0 {
+		if doc.URL == "" {
+			return fmt.Errorf("document has no data or URL")
+		}
+		if !l.AllowURL {
+			return fmt.Errorf("URL-only documents are not supported; provide the document bytes")
+		}
+	}
+	if l.MaxBytes > 0 && len(doc.Data) > l.MaxBytes {
+		return fmt.Errorf("document is %d bytes, limit is %d", len(doc.Data), l.MaxBytes)
+	}
+	if !l.acceptsMimeType(doc.MimeType) {
+		return fmt.Errorf("document MIME type %q is not supported (accepted: %s)", doc.MimeType, strings.Join(l.MimeTypes, ", "))
+	}
+	return nil
+}
+
+// CheckDocuments applies Check to every document attached to a user message.
+func (l DocumentLimits) CheckDocuments(messages []Message) error {
+	for _, message := range messages {
+		user, ok := message.(*UserMessage)
+		if !ok {
+			continue
+		}
+		for _, media := range user.Media {
+			if doc, ok := media.(*DocumentMedia); ok {
+				if err := l.Check(doc); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (l DocumentLimits) acceptsMimeType(mimeType string) bool {
+	if len(l.MimeTypes) == 0 {
+		return true
+	}
+	mimeType = baseMimeType(mimeType)
+	for _, accepted := range l.MimeTypes {
+		if family, ok := strings.CutSuffix(accepted, "/*"); ok {
+			if strings.HasPrefix(mimeType, family+"/") {
+				return true
+			}
+			continue
+		}
+		if mimeType == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// baseMimeType drops parameters such as "; charset=utf-8".
+func baseMimeType(mimeType string) string {
+	base, _, _ := strings.Cut(mimeType, ";")
+	return strings.ToLower(strings.TrimSpace(base))
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocumentFromFileDetectsMimeType(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pdfPath := filepath.Join(dir, "report.pdf")
+	require.NoError(t, os.WriteFile(pdfPath, []byte("%PDF-1.7\n"), 0o600))
+	doc, err := NewDocumentFromFile(pdfPath)
+	require.NoError(t, err)
+	assert.Equal(t, MimeTypePDF, doc.MimeType)
+	assert.Equal(t, "report.pdf", doc.Filename)
+
+	noExt := filepath.Join(dir, "upload")
+	require.NoError(t, os.WriteFile(noExt, []byte("%PDF-1.4\n"), 0o600))
+	doc, err = NewDocumentFromFile(noExt)
+	require.NoError(t, err)
+	assert.Equal(t, MimeTypePDF, doc.MimeType, "content sniffing covers missing extensions")
+
+	_, err = NewDocumentFromFile(filepath.Join(dir, "missing.pdf"))
+	require.Error(t, err)
+}
+
+func TestDocumentLimitsCheck(t *testing.T) {
+	t.Parallel()
+
+	limits := DocumentLimits{MaxBytes: 8, MimeTypes: []string{MimeTypePDF, "text/*"}}
+	assert.NoError(t, limits.Check(NewDocument([]byte("%PDF"), MimeTypePDF)))
+	assert.NoError(t, limits.Check(NewDocument([]byte("notes"), "text/markdown; charset=utf-8")))
+	assert.ErrorContains(t, limits.Check(NewDocument([]byte("%PDF-1.7 long"), MimeTypePDF)), "limit is 8")
+	assert.ErrorContains(t, limits.Check(NewDocument([]byte("PK"), "application/zip")), "not supported")
+	assert.ErrorContains(t, limits.Check(&DocumentMedia{URL: "https://example.com/a.pdf", MimeType: MimeTypePDF}), "URL-only")
+
+	limits.AllowURL = true
+	assert.NoError(t, limits.Check(&DocumentMedia{URL: "https://example.com/a.pdf", MimeType: MimeTypePDF}))
+
+	messages := []Message{&UserMessage{Content: "read", Media: []Media{NewDocument([]byte("PK"), "application/zip")}}}
+	assert.Error(t, limits.CheckDocuments(messages))
+}
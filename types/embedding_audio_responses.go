@@ -83,13 +83,16 @@ type ImageResponse = ImagesResponse
 
 // SpeechToTextResponse represents a speech-to-text response
 type SpeechToTextResponse struct {
-	ID       string         `json:"id,omitempty"`
-	Model    string         `json:"model,omitempty"`
-	Text     string         `json:"text"`
-	Language string         `json:"language,omitempty"`
-	Duration *float64       `json:"duration,omitempty"`
-	Created  time.Time      `json:"created,omitempty"`
-	Metadata map[string]any `json:"metadata,omitempty"`
+	ID       string   `json:"id,omitempty"`
+	Model    string   `json:"model,omitempty"`
+	Text     string   `json:"text"`
+	Language string   `json:"language,omitempty"`
+	Duration *float64 `json:"duration,omitempty"`
+	// Segments and Words carry timings when the request asked for them.
+	Segments []TranscriptSegment `json:"segments,omitempty"`
+	Words    []TranscriptWord    `json:"words,omitempty"`
+	Created  time.Time           `json:"created,omitempty"`
+	Metadata map[string]any      `json:"metadata,omitempty"`
 }
 
 // TextToSpeechResponse represents a text-to-speech response
@@ -104,11 +107,17 @@ type TextToSpeechResponse struct {
 
 // AudioResponse represents an audio response
 type AudioResponse struct {
-	ID       string         `json:"id,omitempty"`
-	Model    string         `json:"model,omitempty"`
-	Audio    []byte         `json:"audio,omitempty"` // For TTS
-	Text     string         `json:"text,omitempty"`  // For STT
-	Format   string         `json:"format,omitempty"`
-	Created  time.Time      `json:"created,omitempty"`
-	Metadata map[string]any `json:"metadata,omitempty"`
+	ID    string `json:"id,omitempty"`
+	Model string `json:"model,omitempty"`
+	Audio []byte `json:"audio,omitempty"` // For TTS
+	Text  string `json:"text,omitempty"`  // For STT
+	// Language, Duration (seconds), Segments, and Words come from detailed
+	// (verbose_json) transcriptions.
+	Language string              `json:"language,omitempty"`
+	Duration float64             `json:"duration,omitempty"`
+	Segments []TranscriptSegment `json:"segments,omitempty"`
+	Words    []TranscriptWord    `json:"words,omitempty"`
+	Format   string              `json:"format,omitempty"`
+	Created  time.Time           `json:"created,omitempty"`
+	Metadata map[string]any      `json:"metadata,omitempty"`
 }
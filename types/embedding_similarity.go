@@ -0,0 +1,92 @@
+package types
+
+import (
+	"math"
+	"sort"
+)
+
+// SimilarityMatch is one result of a nearest-neighbor search.
+type SimilarityMatch struct {
+	Index int     `json:"index"` // position of the candidate in the searched slice
+	Score float64 `json:"score"` // cosine similarity to the query, in [-1, 1]
+}
+
+// CosineSimilarity returns the cosine similarity of a and b. It returns 0
+// when the vectors differ in length or either has zero magnitude.
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// Normalize returns a copy of v scaled to unit L2 length. A zero vector is
+// returned unchanged (as a copy) because it has no direction.
+func Normalize(v []float64) []float64 {
+	out := make([]float64, len(v))
+	copy(out, v)
+
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	if sum == 0 {
+		return out
+	}
+	norm := math.Sqrt(sum)
+	for i := range out {
+		out[i] /= norm
+	}
+	return out
+}
+
+// TopK returns the k candidates most similar to query, ordered by descending
+// cosine similarity. Ties keep candidate order. k <= 0 or k larger than the
+// candidate count returns every candidate ranked.
+func TopK(query []float64, candidates [][]float64, k int) []SimilarityMatch {
+	matches := make([]SimilarityMatch, len(candidates))
+	for i, candidate := range candidates {
+		matches[i] = SimilarityMatch{Index: i, Score: CosineSimilarity(query, candidate)}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if k > 0 && k < len(matches) {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+// CosineSimilarity returns the cosine similarity between the embeddings at
+// positions i and j. Out-of-range indexes yield 0.
+func (r *EmbeddingsResponse) CosineSimilarity(i, j int) float64 {
+	return CosineSimilarity(r.Vector(i), r.Vector(j))
+}
+
+// Normalize scales every embedding vector in the response to unit L2 length
+// in place, so dot products equal cosine similarity.
+func (r *EmbeddingsResponse) Normalize() {
+	for i := range r.Embeddings {
+		r.Embeddings[i].Embedding = Normalize(r.Embeddings[i].Embedding)
+	}
+}
+
+// Nearest returns the k embeddings in the response most similar to query.
+// SimilarityMatch.Index is the position in r.Embeddings.
+func (r *EmbeddingsResponse) Nearest(query []float64, k int) []SimilarityMatch {
+	candidates := make([][]float64, len(r.Embeddings))
+	for i, embedding := range r.Embeddings {
+		candidates[i] = embedding.Embedding
+	}
+	return TopK(query, candidates, k)
+}
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 1.0, CosineSimilarity([]float64{1, 2}, []float64{2, 4}), 1e-9)
+	assert.InDelta(t, 0.0, CosineSimilarity([]float64{1, 0}, []float64{0, 1}), 1e-9)
+	assert.InDelta(t, -1.0, CosineSimilarity([]float64{1, 0}, []float64{-3, 0}), 1e-9)
+	assert.Zero(t, CosineSimilarity([]float64{1, 2}, []float64{1}))
+	assert.Zero(t, CosineSimilarity([]float64{0, 0}, []float64{1, 1}))
+	assert.Zero(t, CosineSimilarity(nil, nil))
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	in := []float64{3, 4}
+	out := Normalize(in)
+	assert.InDeltaSlice(t, []float64{0.6, 0.8}, out, 1e-9)
+	assert.Equal(t, []float64{3, 4}, in, "input must not be modified")
+	assert.Equal(t, []float64{0, 0}, Normalize([]float64{0, 0}))
+}
+
+func TestEmbeddingsResponseSimilarityHelpers(t *testing.T) {
+	t.Parallel()
+
+	resp := &EmbeddingsResponse{Embeddings: []Embedding{
+		{Index: 0, Embedding: []float64{1, 0}},
+		{Index: 1, Embedding: []float64{0, 2}},
+		{Index: 2, Embedding: []float64{3, 3}},
+	}}
+
+	assert.InDelta(t, 0.0, resp.CosineSimilarity(0, 1), 1e-9)
+	assert.Zero(t, resp.CosineSimilarity(0, 9))
+
+	matches := resp.Nearest([]float64{1, 0.1}, 2)
+	require.Len(t, matches, 2)
+	assert.Equal(t, 0, matches[0].Index)
+	assert.Equal(t, 2, matches[1].Index)
+	assert.Greater(t, matches[0].Score, matches[1].Score)
+
+	assert.Len(t, resp.Nearest([]float64{1, 0}, 0), 3)
+
+	resp.Normalize()
+	assert.InDeltaSlice(t, []float64{0, 1}, resp.Vector(1), 1e-9)
+}
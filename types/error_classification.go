@@ -44,12 +44,14 @@ func ClassifyError(err error) ErrorClass {
 				return ErrorClassQuota
 			}
 			return ErrorClassRateLimit
-		case ErrorCodeRequest, ErrorCodeModel, ErrorCodeValidation:
+		case ErrorCodeRequest, ErrorCodeModel, ErrorCodeValidation, ErrorCodeContentBlocked, ErrorCodeRefusal:
 			return ErrorClassConfig
 		case ErrorCodeTimeout:
 			return ErrorClassTimeout
 		case ErrorCodeNetwork:
 			return ErrorClassNetwork
+		case ErrorCodeEmptyResponse:
+			return ErrorClassTransient
 		case ErrorCodeProvider:
 			if !wormholeErr.Retryable {
 				return ErrorClassConfig
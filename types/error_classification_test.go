@@ -16,6 +16,7 @@ func TestClassifyErrorUsesTypedErrors(t *testing.T) {
 		{ErrQuotaExceeded, ErrorClassQuota},
 		{ErrInvalidAPIKey, ErrorClassAuth},
 		{ErrInvalidRequest, ErrorClassConfig},
+		{ErrContentBlocked, ErrorClassConfig},
 		{ErrTimeout, ErrorClassTimeout},
 		{ErrNetworkError, ErrorClassNetwork},
 	}
@@ -89,6 +89,30 @@ func IsMiddlewareError(err error) bool {
 	return false
 }
 
+// IsContentBlockedError checks if a guardrail rejected the prompt or response.
+func IsContentBlockedError(err error) bool {
+	if wormholeErr, ok := AsWormholeError(err); ok {
+		return wormholeErr.Code == ErrorCodeContentBlocked
+	}
+	return false
+}
+
+// IsEmptyResponseError checks if a provider returned an empty completion.
+func IsEmptyResponseError(err error) bool {
+	if wormholeErr, ok := AsWormholeError(err); ok {
+		return wormholeErr.Code == ErrorCodeEmptyResponse
+	}
+	return false
+}
+
+// IsRefusalError checks if the model declined to answer.
+func IsRefusalError(err error) bool {
+	if wormholeErr, ok := AsWormholeError(err); ok {
+		return wormholeErr.Code == ErrorCodeRefusal
+	}
+	return false
+}
+
 // GetRetryAfter returns a suggested retry delay for retryable errors.
 // Returns 0 if the error is not retryable or has no retry hint.
 //
@@ -23,6 +23,13 @@ const (
 	ErrorCodeValidation ErrorCode = "VALIDATION_ERROR"
 	ErrorCodeMiddleware ErrorCode = "MIDDLEWARE_ERROR"
 	ErrorCodeUnknown    ErrorCode = "UNKNOWN_ERROR"
+	// ErrorCodeContentBlocked marks a prompt or response rejected by a guardrail.
+	ErrorCodeContentBlocked ErrorCode = "CONTENT_BLOCKED"
+	// ErrorCodeEmptyResponse marks a successful call that returned no text and
+	// no tool calls.
+	ErrorCodeEmptyResponse ErrorCode = "EMPTY_RESPONSE"
+	// ErrorCodeRefusal marks a model that declined to answer.
+	ErrorCodeRefusal ErrorCode = "REFUSAL"
 )
 
 var (
@@ -48,6 +55,11 @@ var (
 	ErrProviderNotFound        = NewWormholeError(ErrorCodeProvider, "provider not configured", false)
 	ErrProviderUnavailable     = NewWormholeError(ErrorCodeProvider, "provider service unavailable", true)
 	ErrProviderConstraintError = NewWormholeError(ErrorCodeProvider, "provider constraint violation", false)
+	// ErrDeferredPending means a deferred completion is still running; poll again later.
+	ErrDeferredPending = NewWormholeError(ErrorCodeProvider, "deferred completion not ready", true)
+	// ErrStreamSchemaUnsupported means a structured stream was requested from a
+	// provider whose streaming API cannot constrain output to the schema.
+	ErrStreamSchemaUnsupported = NewWormholeError(ErrorCodeProvider, "provider cannot enforce a schema while streaming", false)
 
 	// Network errors
 	ErrNetworkError       = NewWormholeError(ErrorCodeNetwork, "network connection failed", true)
@@ -60,6 +72,21 @@ var (
 	ErrCircuitOpen        = NewWormholeError(ErrorCodeMiddleware, "circuit breaker is open", true)
 	ErrRateLimitExceeded  = NewWormholeError(ErrorCodeMiddleware, "rate limit exceeded", true)
 	ErrNoHealthyProviders = NewWormholeError(ErrorCodeMiddleware, "no healthy providers available", true)
+	// ErrRetryBudgetExceeded is not retryable: retrying it is exactly the load the budget sheds.
+	ErrRetryBudgetExceeded = NewWormholeError(ErrorCodeMiddleware, "retry budget exceeded", false)
+	// ErrLoadShed is returned when a low-priority request is dropped while the
+	// adaptive limiter sees its provider degraded. Like the retry budget it is
+	// not retryable: shed traffic should back off, not come straight back.
+	ErrLoadShed = NewWormholeError(ErrorCodeMiddleware, "request shed: provider degraded", false)
+
+	// Content errors
+	ErrContentBlocked = NewWormholeError(ErrorCodeContentBlocked, "content blocked by guardrail", false)
+	// ErrEmptyResponse is a 200 response with empty or whitespace-only text
+	// and no tool calls. It is retryable: the same request usually succeeds.
+	ErrEmptyResponse = NewWormholeError(ErrorCodeEmptyResponse, "provider returned an empty response", true)
+	// ErrRefusal is a model declining the request. It is not retryable: the
+	// same prompt draws the same refusal.
+	ErrRefusal = NewWormholeError(ErrorCodeRefusal, "model refused the request", false)
 )
 
 // WormholeError provides structured error information
@@ -145,6 +172,10 @@ func safeErrorMessage(code ErrorCode) string {
 		return "validation failed"
 	case ErrorCodeMiddleware:
 		return "middleware request failed"
+	case ErrorCodeContentBlocked:
+		return "content blocked"
+	case ErrorCodeRefusal:
+		return "model refused the request"
 	default:
 		return "request failed"
 	}
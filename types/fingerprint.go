@@ -0,0 +1,108 @@
+package types
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// fingerprintVersion prefixes every fingerprint hash so the normalization can
+// change later without new keys colliding with old ones.
+const fingerprintVersion = "v1"
+
+// Fingerprint returns a stable SHA-256 hash of the normalized request: its
+// JSON form (map keys sorted) plus the fields JSON omits, SystemPrompt and
+// ProviderOptions. Equal requests hash equally across processes and restarts,
+// so the fingerprint works as an idempotency or cache key. It returns "" when
+// the request cannot be serialized.
+func (r TextRequest) Fingerprint() string {
+	return fingerprint("text", r, map[string]any{
+		"system_prompt":    r.SystemPrompt,
+		"provider_options": r.ProviderOptions,
+	})
+}
+
+// Fingerprint returns a stable hash of the normalized request. See
+// TextRequest.Fingerprint.
+func (r StructuredRequest) Fingerprint() string {
+	return fingerprint("structured", r, map[string]any{
+		"system_prompt":    r.SystemPrompt,
+		"provider_options": r.ProviderOptions,
+	})
+}
+
+// Fingerprint returns a stable hash of the normalized request. See
+// TextRequest.Fingerprint.
+func (r EmbeddingsRequest) Fingerprint() string {
+	return fingerprint("embeddings", r, map[string]any{"provider_options": r.ProviderOptions})
+}
+
+// Fingerprint returns a stable hash of the normalized request. See
+// TextRequest.Fingerprint.
+func (r RerankRequest) Fingerprint() string {
+	return fingerprint("rerank", r, map[string]any{"provider_options": r.ProviderOptions})
+}
+
+// Fingerprint returns a stable hash of the normalized request. See
+// TextRequest.Fingerprint.
+func (r ImagesRequest) Fingerprint() string {
+	return fingerprint("images", r, map[string]any{"provider_options": r.ProviderOptions})
+}
+
+func fingerprint(kind string, request any, hidden map[string]any) string {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return ""
+	}
+	for key, value := range hidden {
+		if isEmptyFingerprintValue(value) {
+			delete(hidden, key)
+		}
+	}
+	extra, err := json.Marshal(hidden)
+	if err != nil {
+		return ""
+	}
+
+	h := sha256.New()
+	h.Write([]byte(fingerprintVersion + ":" + kind + "\n"))
+	h.Write(data)
+	h.Write([]byte("\n"))
+	h.Write(extra)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isEmptyFingerprintValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+type idempotencyKeyContextKey struct{}
+
+// ContextWithIdempotencyKey returns a context carrying key. Providers
+// configured with ProviderConfig.IdempotencyHeader send it with each HTTP
+// request so the upstream API can deduplicate resubmissions.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the key set by ContextWithIdempotencyKey,
+// or "".
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextRequestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func() TextRequest {
+		return TextRequest{
+			BaseRequest: BaseRequest{
+				Model:           "gpt-5",
+				ProviderOptions: map[string]any{"b": 2, "a": 1},
+			},
+			Messages:     []Message{NewUserMessage("hello")},
+			SystemPrompt: "be brief",
+		}
+	}
+
+	base := newRequest().Fingerprint()
+	require.Len(t, base, 64)
+	assert.Equal(t, base, newRequest().Fingerprint(), "equal requests hash equally")
+
+	reordered := newRequest()
+	reordered.ProviderOptions = map[string]any{"a": 1, "b": 2}
+	assert.Equal(t, base, reordered.Fingerprint(), "map key order does not matter")
+
+	tests := []struct {
+		name   string
+		mutate func(*TextRequest)
+	}{
+		{name: "model", mutate: func(r *TextRequest) { r.Model = "gpt-5-mini" }},
+		{name: "prompt", mutate: func(r *TextRequest) { r.Messages = []Message{NewUserMessage("bye")} }},
+		{name: "system prompt", mutate: func(r *TextRequest) { r.SystemPrompt = "be verbose" }},
+		{name: "provider options", mutate: func(r *TextRequest) { r.ProviderOptions = map[string]any{"a": 1} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := newRequest()
+			tt.mutate(&req)
+			assert.NotEqual(t, base, req.Fingerprint())
+		})
+	}
+}
+
+func TestFingerprintSeparatesRequestKinds(t *testing.T) {
+	t.Parallel()
+
+	text := TextRequest{BaseRequest: BaseRequest{Model: "m"}}
+	structured := StructuredRequest{BaseRequest: BaseRequest{Model: "m"}}
+	embeddings := EmbeddingsRequest{Model: "m"}
+	rerank := RerankRequest{Model: "m"}
+	images := ImagesRequest{Model: "m"}
+
+	seen := map[string]bool{}
+	for _, fp := range []string{text.Fingerprint(), structured.Fingerprint(), embeddings.Fingerprint(), rerank.Fingerprint(), images.Fingerprint()} {
+		require.NotEmpty(t, fp)
+		assert.False(t, seen[fp], "fingerprints of different request kinds collide")
+		seen[fp] = true
+	}
+
+	withOptions := EmbeddingsRequest{Model: "m", ProviderOptions: map[string]any{"dimensions": 256}}
+	assert.NotEqual(t, embeddings.Fingerprint(), withOptions.Fingerprint())
+}
+
+func TestIdempotencyKeyContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Empty(t, IdempotencyKeyFromContext(ctx))
+	assert.Equal(t, ctx, ContextWithIdempotencyKey(ctx, ""), "empty key leaves the context untouched")
+	assert.Equal(t, "order-42", IdempotencyKeyFromContext(ContextWithIdempotencyKey(ctx, "order-42")))
+}
@@ -0,0 +1,31 @@
+package types
+
+import "strings"
+
+// NormalizeFinishReason maps a provider's raw finish reason to a
+// FinishReason, case-insensitively. Several raw reasons share a normalized
+// value, so responses and chunks keep the original in RawFinishReason; check
+// it to tell an Anthropic refusal from a Gemini SAFETY block, or a Gemini
+// RECITATION stop from either.
+//
+//	FinishReasonStop           stop, end_turn, stop_sequence, eos_token, completed
+//	FinishReasonLength         length, max_tokens, max_output_tokens,
+//	                           model_context_window_exceeded
+//	FinishReasonToolCalls      tool_calls, function_call, tool_use
+//	FinishReasonContentFilter  content_filter, refusal, safety, recitation,
+//	                           blocklist, prohibited_content, spii, image_safety
+//	FinishReasonOther          anything else, such as pause_turn,
+//	                           malformed_function_call, language, load, unload
+func NormalizeFinishReason(raw string) FinishReason {
+	switch strings.ToLower(raw) {
+	case "stop", "end_turn", "stop_sequence", "eos_token", "completed":
+		return FinishReasonStop
+	case "length", "max_tokens", "max_output_tokens", "model_context_window_exceeded":
+		return FinishReasonLength
+	case "tool_calls", "function_call", "tool_use":
+		return FinishReasonToolCalls
+	case "content_filter", "refusal", "safety", "recitation", "blocklist", "prohibited_content", "spii", "image_safety":
+		return FinishReasonContentFilter
+	}
+	return FinishReasonOther
+}
@@ -0,0 +1,45 @@
+package types
+
+import "context"
+
+// Image response formats. OpenAI's gpt-image models always return base64
+// data and reject the setting.
+const (
+	ImageResponseFormatURL    = "url"
+	ImageResponseFormatBase64 = "b64_json"
+)
+
+// ImageEditRequest changes existing images as Prompt describes.
+type ImageEditRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	// Images are the source images as PNG, JPEG, or WebP bytes. dall-e-2
+	// edits one square PNG; gpt-image-1 takes up to 16.
+	Images [][]byte `json:"-"`
+	// Mask is a PNG the size of the first image whose fully transparent
+	// pixels mark the area to change. Nil lets the model edit anywhere.
+	Mask            []byte         `json:"-"`
+	Size            string         `json:"size,omitempty"`
+	Quality         string         `json:"quality,omitempty"`
+	N               int            `json:"n,omitempty"`
+	ResponseFormat  string         `json:"response_format,omitempty"`
+	ProviderOptions map[string]any `json:"-"`
+}
+
+// ImageVariationRequest asks for variations of a square PNG image
+// (dall-e-2 on OpenAI).
+type ImageVariationRequest struct {
+	Model           string         `json:"model"`
+	Image           []byte         `json:"-"`
+	Size            string         `json:"size,omitempty"`
+	N               int            `json:"n,omitempty"`
+	ResponseFormat  string         `json:"response_format,omitempty"`
+	ProviderOptions map[string]any `json:"-"`
+}
+
+// ImageEditor is implemented by providers that edit images and make
+// variations of them, such as OpenAI and compatible APIs.
+type ImageEditor interface {
+	EditImage(ctx context.Context, request ImageEditRequest) (*ImagesResponse, error)
+	ImageVariations(ctx context.Context, request ImageVariationRequest) (*ImagesResponse, error)
+}
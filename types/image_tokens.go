@@ -0,0 +1,215 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	_ "image/gif"  // register GIF for DecodeConfig
+	_ "image/jpeg" // register JPEG for DecodeConfig
+	_ "image/png"  // register PNG for DecodeConfig
+	"math"
+)
+
+// ImageTokenRule names how a provider converts an input image to tokens.
+type ImageTokenRule string
+
+const (
+	// ImageTokensOpenAITiles fits the image in 2048x2048, scales the short
+	// side down to 768px, and bills BaseTokens plus TileTokens per 512px
+	// tile (85 + 170 per tile for GPT-4o). Low detail bills BaseTokens only.
+	ImageTokensOpenAITiles ImageTokenRule = "openai_tiles"
+	// ImageTokensAnthropicPixels fits the long edge in 1568px and bills one
+	// token per 750 pixels, about 1600 tokens at most.
+	ImageTokensAnthropicPixels ImageTokenRule = "anthropic_pixels"
+	// ImageTokensGeminiTiles bills TileTokens (258) for an image up to
+	// 384x384 and TileTokens per 768px tile for anything larger.
+	ImageTokensGeminiTiles ImageTokenRule = "gemini_tiles"
+)
+
+// ImageDetailLow is the OpenAI detail level billed at a flat BaseTokens.
+const ImageDetailLow = "low"
+
+const (
+	// defaultImageSide is assumed for images whose size cannot be read, such
+	// as URL images or formats the standard library does not decode.
+	defaultImageSide = 1024
+
+	openAIBaseTokens        = 85
+	openAITileTokens        = 170
+	anthropicMaxImageTokens = 1600
+	geminiTileTokens        = 258
+)
+
+// ImagePricing describes how a model bills input images. Images are billed
+// as input tokens under Rule at ModelCost.InputTokens, or at a flat PerImage
+// price when that is set. BaseTokens and TileTokens override the rule's
+// constants for models that scale them (GPT-4o mini bills 2833 + 5667 per
+// tile).
+type ImagePricing struct {
+	Rule       ImageTokenRule `json:"rule,omitempty"`
+	BaseTokens int            `json:"base_tokens,omitempty"`
+	TileTokens int            `json:"tile_tokens,omitempty"`
+	PerImage   float64        `json:"per_image,omitempty"` // Flat cost per image, replacing token billing
+}
+
+// ImageSize is an input image as pricing sees it. Zero dimensions mean
+// unknown and are estimated as 1024x1024.
+type ImageSize struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Detail string `json:"detail,omitempty"` // OpenAI detail level; "low" bills BaseTokens only
+}
+
+// ImageSizeOf reads the dimensions of an inline PNG, JPEG, or GIF image.
+// URL images and other formats come back with zero dimensions.
+func ImageSizeOf(m *ImageMedia) ImageSize {
+	if m == nil {
+		return ImageSize{}
+	}
+	data := m.Data
+	if len(data) == 0 && m.Base64Data != "" {
+		decoded, err := base64.StdEncoding.DecodeString(m.Base64Data)
+		if err != nil {
+			return ImageSize{}
+		}
+		data = decoded
+	}
+	if len(data) == 0 {
+		return ImageSize{}
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ImageSize{}
+	}
+	return ImageSize{Width: config.Width, Height: config.Height}
+}
+
+// MessageImages returns the size of every image attached to messages.
+func MessageImages(messages []Message) []ImageSize {
+	var sizes []ImageSize
+	for _, message := range messages {
+		user, ok := message.(*UserMessage)
+		if !ok {
+			continue
+		}
+		for _, media := range user.Media {
+			if img, ok := media.(*ImageMedia); ok {
+				sizes = append(sizes, ImageSizeOf(img))
+			}
+		}
+	}
+	return sizes
+}
+
+// Tokens returns the input tokens one image is billed as. It is zero for
+// flat-priced images and unknown rules.
+func (p ImagePricing) Tokens(size ImageSize) int {
+	if p.PerImage > 0 {
+		return 0
+	}
+	w, h := float64(size.Width), float64(size.Height)
+	if w <= 0 || h <= 0 {
+		w, h = defaultImageSide, defaultImageSide
+	}
+	switch p.Rule {
+	case ImageTokensOpenAITiles:
+		base := orDefault(p.BaseTokens, openAIBaseTokens)
+		if size.Detail == ImageDetailLow {
+			return base
+		}
+		w, h = fitWithin(w, h, 2048)
+		if short := math.Min(w, h); short > 768 {
+			w, h = w*768/short, h*768/short
+		}
+		tiles := math.Ceil(w/512) * math.Ceil(h/512)
+		return base + orDefault(p.TileTokens, openAITileTokens)*int(tiles)
+	case ImageTokensAnthropicPixels:
+		w, h = fitWithin(w, h, 1568)
+		return min(int(math.Ceil(w*h/750)), anthropicMaxImageTokens)
+	case ImageTokensGeminiTiles:
+		tile := orDefault(p.TileTokens, geminiTileTokens)
+		if w <= 384 && h <= 384 {
+			return tile
+		}
+		return tile * int(math.Ceil(w/768)*math.Ceil(h/768))
+	}
+	return 0
+}
+
+// imageRuleForProvider is the rule used for a vision model registered
+// without ImagePricing.
+func imageRuleForProvider(provider string) ImageTokenRule {
+	switch provider {
+	case "openai":
+		return ImageTokensOpenAITiles
+	case "anthropic":
+		return ImageTokensAnthropicPixels
+	case "gemini":
+		return ImageTokensGeminiTiles
+	}
+	return ""
+}
+
+// imagePricing returns the model's image pricing, falling back to its
+// provider's rule.
+func imagePricing(model *ModelInfo) ImagePricing {
+	if model.Cost != nil && model.Cost.Images != nil {
+		pricing := *model.Cost.Images
+		if pricing.Rule == "" && pricing.PerImage == 0 {
+			pricing.Rule = imageRuleForProvider(model.Provider)
+		}
+		return pricing
+	}
+	return ImagePricing{Rule: imageRuleForProvider(model.Provider)}
+}
+
+// EstimateImageTokens returns the input tokens modelID bills for images.
+// Unknown models and flat-priced images count zero.
+func (r *ModelRegistry) EstimateImageTokens(modelID string, images ...ImageSize) int {
+	model, exists := r.Get(modelID)
+	if !exists {
+		return 0
+	}
+	return imagePricing(model).total(images)
+}
+
+func (p ImagePricing) total(images []ImageSize) int {
+	tokens := 0
+	for _, size := range images {
+		tokens += p.Tokens(size)
+	}
+	return tokens
+}
+
+// imageCost prices images for model at its input token rate, or at the flat
+// per-image price.
+func imageCost(model *ModelInfo, images []ImageSize) float64 {
+	if len(images) == 0 || model.Cost == nil {
+		return 0
+	}
+	pricing := imagePricing(model)
+	if pricing.PerImage > 0 {
+		return pricing.PerImage * float64(len(images))
+	}
+	return float64(pricing.total(images)) / 1000.0 * model.Cost.InputTokens
+}
+
+// EstimateImageTokens returns the input tokens modelID bills for images,
+// using DefaultModelRegistry.
+func EstimateImageTokens(modelID string, images ...ImageSize) int {
+	return DefaultModelRegistry.EstimateImageTokens(modelID, images...)
+}
+
+func fitWithin(w, h, limit float64) (float64, float64) {
+	if long := math.Max(w, h); long > limit {
+		return w * limit / long, h * limit / long
+	}
+	return w, h
+}
+
+func orDefault(v, fallback int) int {
+	if v > 0 {
+		return v
+	}
+	return fallback
+}
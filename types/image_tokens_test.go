@@ -0,0 +1,81 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImagePricingTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pricing ImagePricing
+		size    ImageSize
+		want    int
+	}{
+		{"openai square", ImagePricing{Rule: ImageTokensOpenAITiles}, ImageSize{Width: 1024, Height: 1024}, 765},
+		{"openai tall", ImagePricing{Rule: ImageTokensOpenAITiles}, ImageSize{Width: 2048, Height: 4096}, 1105},
+		{"openai low detail", ImagePricing{Rule: ImageTokensOpenAITiles}, ImageSize{Width: 4096, Height: 4096, Detail: ImageDetailLow}, 85},
+		{"openai mini overrides", ImagePricing{Rule: ImageTokensOpenAITiles, BaseTokens: 2833, TileTokens: 5667}, ImageSize{Width: 512, Height: 512}, 8500},
+		{"openai unknown size", ImagePricing{Rule: ImageTokensOpenAITiles}, ImageSize{}, 765},
+		{"anthropic", ImagePricing{Rule: ImageTokensAnthropicPixels}, ImageSize{Width: 1000, Height: 1000}, 1334},
+		{"anthropic capped", ImagePricing{Rule: ImageTokensAnthropicPixels}, ImageSize{Width: 4000, Height: 3000}, 1600},
+		{"gemini small", ImagePricing{Rule: ImageTokensGeminiTiles}, ImageSize{Width: 300, Height: 300}, 258},
+		{"gemini tiled", ImagePricing{Rule: ImageTokensGeminiTiles}, ImageSize{Width: 1024, Height: 1024}, 1032},
+		{"flat price", ImagePricing{PerImage: 0.01}, ImageSize{Width: 1024, Height: 1024}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.pricing.Tokens(tt.size))
+		})
+	}
+}
+
+func TestModelRegistryEstimateCostWithImages(t *testing.T) {
+	t.Parallel()
+
+	registry := NewModelRegistry()
+	registry.Register(&ModelInfo{ID: "gpt-4o", Provider: "openai", Cost: &ModelCost{InputTokens: 0.0025, OutputTokens: 0.01}})
+	registry.Register(&ModelInfo{ID: "flat", Provider: "custom", Cost: &ModelCost{InputTokens: 0.001, Images: &ImagePricing{PerImage: 0.002}}})
+
+	img := ImageSize{Width: 1024, Height: 1024}
+	cost, err := registry.EstimateCost("gpt-4o", 1000, 0, img)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.0025+0.765*0.0025, cost, 1e-12)
+	assert.Equal(t, 765, registry.EstimateImageTokens("gpt-4o", img))
+
+	cost, err = registry.EstimateCost("flat", 1000, 0, img, img)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.001+0.004, cost, 1e-12)
+	assert.Zero(t, registry.EstimateImageTokens("flat", img))
+
+	cost, err = registry.EstimateCost("gpt-4o", 1000, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.0025, cost, 1e-12, "no images, no image cost")
+}
+
+func TestMessageImagesReadsInlineDimensions(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+
+	sizes := MessageImages([]Message{
+		NewSystemMessage("describe"),
+		&UserMessage{Media: []Media{
+			&ImageMedia{Data: buf.Bytes(), MimeType: "image/png"},
+			&ImageMedia{Base64Data: base64.StdEncoding.EncodeToString(buf.Bytes()), MimeType: "image/png"},
+			&ImageMedia{URL: "https://example.com/cat.jpg"},
+			NewDocument([]byte("notes"), "text/plain"),
+		}},
+	})
+	assert.Equal(t, []ImageSize{{Width: 40, Height: 20}, {Width: 40, Height: 20}, {}}, sizes)
+}
@@ -0,0 +1,30 @@
+package types
+
+import "math"
+
+// TokenLogprob is the log probability of one generated token. TopLogprobs
+// lists the most likely alternatives at that position when the request asked
+// for them, most likely first. Field names follow the OpenAI wire format.
+type TokenLogprob struct {
+	Token       string       `json:"token"`
+	Logprob     float64      `json:"logprob"`
+	Bytes       []int        `json:"bytes,omitempty"`
+	TopLogprobs []TopLogprob `json:"top_logprobs,omitempty"`
+}
+
+// Probability returns the token's probability, between 0 and 1.
+func (l TokenLogprob) Probability() float64 {
+	return math.Exp(l.Logprob)
+}
+
+// TopLogprob is one candidate token at a position, with its log probability.
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes,omitempty"`
+}
+
+// Probability returns the candidate's probability, between 0 and 1.
+func (l TopLogprob) Probability() float64 {
+	return math.Exp(l.Logprob)
+}
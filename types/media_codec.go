@@ -0,0 +1,121 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Request formats a MediaCodec can render custom media into. OpenAI-compatible
+// providers (OpenRouter, Groq, vLLM, ...) share MediaFormatOpenAI.
+const (
+	MediaFormatOpenAI          = "openai"           // Chat Completions content parts
+	MediaFormatOpenAIResponses = "openai-responses" // Responses API input content
+	MediaFormatAnthropic       = "anthropic"        // Messages API content blocks
+	MediaFormatGemini          = "gemini"           // generateContent parts
+)
+
+// MediaCodec teaches Wormhole to send a custom Media type without forking the
+// message types. Register one per Media.GetType() value with
+// RegisterMediaCodec.
+//
+// Example:
+//
+//	types.RegisterMediaCodec("protein", types.MediaCodec{
+//	    Marshal: func(format string, m types.Media) (map[string]any, bool) {
+//	        seq := m.(*ProteinMedia).Sequence
+//	        switch format {
+//	        case types.MediaFormatGemini:
+//	            return map[string]any{"text": "FASTA:\n" + seq}, true
+//	        case types.MediaFormatOpenAI, types.MediaFormatAnthropic:
+//	            return map[string]any{"type": "text", "text": "FASTA:\n" + seq}, true
+//	        }
+//	        return nil, false
+//	    },
+//	})
+type MediaCodec struct {
+	// Marshal renders media as one content part in the given request format.
+	// Returning false means the format is unsupported; the provider then
+	// treats the media as it treats any unknown media type.
+	Marshal func(format string, media Media) (part map[string]any, ok bool)
+
+	// Unmarshal rebuilds the media from its JSON encoding, for callers that
+	// persist messages. Optional.
+	Unmarshal func(data []byte) (Media, error)
+}
+
+var mediaCodecs struct {
+	mu     sync.RWMutex
+	codecs map[string]MediaCodec
+}
+
+// RegisterMediaCodec registers codec for media whose GetType() returns
+// mediaType, replacing any earlier registration. The built-in "image" and
+// "document" types keep their native handling in every provider.
+func RegisterMediaCodec(mediaType string, codec MediaCodec) {
+	mediaCodecs.mu.Lock()
+	defer mediaCodecs.mu.Unlock()
+	if mediaCodecs.codecs == nil {
+		mediaCodecs.codecs = make(map[string]MediaCodec)
+	}
+	mediaCodecs.codecs[mediaType] = codec
+}
+
+// UnregisterMediaCodec removes the codec for mediaType.
+func UnregisterMediaCodec(mediaType string) {
+	mediaCodecs.mu.Lock()
+	defer mediaCodecs.mu.Unlock()
+	delete(mediaCodecs.codecs, mediaType)
+}
+
+func lookupMediaCodec(mediaType string) (MediaCodec, bool) {
+	mediaCodecs.mu.RLock()
+	defer mediaCodecs.mu.RUnlock()
+	codec, ok := mediaCodecs.codecs[mediaType]
+	return codec, ok
+}
+
+// MarshalMedia renders media in format using its registered codec. It
+// returns false when no codec is registered or the codec declines the format.
+func MarshalMedia(format string, media Media) (map[string]any, bool) {
+	if media == nil {
+		return nil, false
+	}
+	codec, ok := lookupMediaCodec(media.GetType())
+	if !ok || codec.Marshal == nil {
+		return nil, false
+	}
+	return codec.Marshal(format, media)
+}
+
+// UnmarshalMedia decodes data as media of mediaType. Built-in "image",
+// "document", and "audio" types decode without registration; other types
+// need a codec with Unmarshal set.
+func UnmarshalMedia(mediaType string, data []byte) (Media, error) {
+	switch mediaType {
+	case "image":
+		var m ImageMedia
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		return &m, nil
+	case "document":
+		var m DocumentMedia
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		return &m, nil
+	case "audio":
+		var m AudioMedia
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		return &m, nil
+	}
+
+	codec, ok := lookupMediaCodec(mediaType)
+	if !ok || codec.Unmarshal == nil {
+		return nil, ErrInvalidRequest.WithDetails(fmt.Sprintf("no media codec registered for %q", mediaType))
+	}
+	return codec.Unmarshal(data)
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sequenceMedia struct {
+	Sequence string `json:"sequence"`
+}
+
+func (m *sequenceMedia) GetType() string { return "test-sequence" }
+
+func TestMediaCodecRoundTrip(t *testing.T) {
+	t.Parallel()
+	RegisterMediaCodec("test-sequence", MediaCodec{
+		Marshal: func(format string, media Media) (map[string]any, bool) {
+			if format != MediaFormatOpenAI {
+				return nil, false
+			}
+			return map[string]any{"type": "text", "text": "SEQ " + media.(*sequenceMedia).Sequence}, true
+		},
+		Unmarshal: func(data []byte) (Media, error) {
+			var m sequenceMedia
+			err := json.Unmarshal(data, &m)
+			return &m, err
+		},
+	})
+	t.Cleanup(func() { UnregisterMediaCodec("test-sequence") })
+
+	media := &sequenceMedia{Sequence: "MKT"}
+	part, ok := MarshalMedia(MediaFormatOpenAI, media)
+	require.True(t, ok)
+	assert.Equal(t, "SEQ MKT", part["text"])
+
+	_, ok = MarshalMedia(MediaFormatGemini, media)
+	assert.False(t, ok, "codec declined the format")
+
+	data, err := json.Marshal(media)
+	require.NoError(t, err)
+	decoded, err := UnmarshalMedia("test-sequence", data)
+	require.NoError(t, err)
+	assert.Equal(t, media, decoded)
+}
+
+func TestMediaCodecUnregisteredAndBuiltins(t *testing.T) {
+	t.Parallel()
+
+	_, ok := MarshalMedia(MediaFormatOpenAI, &ImageMedia{URL: "https://example.test/cat.png"})
+	assert.False(t, ok, "built-in media is handled natively, not by codecs")
+	_, ok = MarshalMedia(MediaFormatOpenAI, nil)
+	assert.False(t, ok)
+
+	image, err := UnmarshalMedia("image", []byte(`{"url":"https://example.test/cat.png","mime_type":"image/png"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &ImageMedia{URL: "https://example.test/cat.png", MimeType: "image/png"}, image)
+
+	_, err = UnmarshalMedia("unknown-kind", []byte(`{}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MessageRecord is the lossless JSON form of a Message, for storing and
+// replaying requests. A message's own MarshalJSON renders the OpenAI wire
+// shape and cannot be decoded back into a Message; a record keeps every field
+// and tags each media item with its type.
+type MessageRecord struct {
+	Role         Role          `json:"role"`
+	Content      string        `json:"content,omitempty"`
+	Media        []MediaRecord `json:"media,omitempty"`
+	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`
+	Thinking     *Thinking     `json:"thinking,omitempty"`
+	AudioID      string        `json:"audio_id,omitempty"`
+	ToolCallID   string        `json:"tool_call_id,omitempty"`
+	FunctionName string        `json:"function_name,omitempty"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// MediaRecord is one media item of a MessageRecord: its GetType() value and
+// its JSON encoding, decoded with UnmarshalMedia.
+type MediaRecord struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// RecordMessage converts msg to its record. Messages of unknown types, and
+// BaseMessage values whose content is not a string, cannot be recorded.
+func RecordMessage(msg Message) (MessageRecord, error) {
+	switch m := msg.(type) {
+	case *SystemMessage:
+		return MessageRecord{Role: RoleSystem, Content: m.Content}, nil
+	case *DeveloperMessage:
+		return MessageRecord{Role: RoleDeveloper, Content: m.Content}, nil
+	case *UserMessage:
+		record := MessageRecord{Role: RoleUser, Content: m.Content}
+		for _, media := range m.Media {
+			data, err := json.Marshal(media)
+			if err != nil {
+				return MessageRecord{}, fmt.Errorf("record %s media: %w", media.GetType(), err)
+			}
+			record.Media = append(record.Media, MediaRecord{Type: media.GetType(), Data: data})
+		}
+		return record, nil
+	case *AssistantMessage:
+		return MessageRecord{
+			Role:      RoleAssistant,
+			Content:   m.Content,
+			ToolCalls: CloneToolCalls(m.ToolCalls),
+			Thinking:  recordThinking(m.Thinking),
+			AudioID:   m.AudioID,
+		}, nil
+	case *ToolResultMessage:
+		return MessageRecord{
+			Role:         RoleTool,
+			Content:      m.Content,
+			ToolCallID:   m.ToolCallID,
+			FunctionName: m.FunctionName,
+			Error:        m.Error,
+		}, nil
+	case BaseMessage:
+		content, ok := m.Content.(string)
+		if !ok {
+			return MessageRecord{}, ErrInvalidRequest.WithDetails(fmt.Sprintf("cannot record %s message with %T content", m.Role, m.Content))
+		}
+		return MessageRecord{Role: m.Role, Content: content}, nil
+	case *BaseMessage:
+		return RecordMessage(*m)
+	}
+	return MessageRecord{}, ErrInvalidRequest.WithDetails(fmt.Sprintf("cannot record message of type %T", msg))
+}
+
+// RecordMessages converts every message to its record.
+func RecordMessages(messages []Message) ([]MessageRecord, error) {
+	if messages == nil {
+		return nil, nil
+	}
+	records := make([]MessageRecord, 0, len(messages))
+	for i, msg := range messages {
+		record, err := RecordMessage(msg)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Message rebuilds the message the record was made from.
+func (r MessageRecord) Message() (Message, error) {
+	switch r.Role {
+	case RoleSystem:
+		return NewSystemMessage(r.Content), nil
+	case RoleDeveloper:
+		return NewDeveloperMessage(r.Content), nil
+	case RoleUser:
+		msg := NewUserMessage(r.Content)
+		for _, record := range r.Media {
+			media, err := UnmarshalMedia(record.Type, record.Data)
+			if err != nil {
+				return nil, fmt.Errorf("restore %s media: %w", record.Type, err)
+			}
+			msg.Media = append(msg.Media, media)
+		}
+		return msg, nil
+	case RoleAssistant:
+		return &AssistantMessage{
+			Content:   r.Content,
+			ToolCalls: CloneToolCalls(r.ToolCalls),
+			Thinking:  recordThinking(r.Thinking),
+			AudioID:   r.AudioID,
+		}, nil
+	case RoleTool:
+		return &ToolResultMessage{
+			Content:      r.Content,
+			ToolCallID:   r.ToolCallID,
+			FunctionName: r.FunctionName,
+			Error:        r.Error,
+		}, nil
+	}
+	return nil, ErrInvalidRequest.WithDetails(fmt.Sprintf("unknown message role %q", r.Role))
+}
+
+// RestoreMessages rebuilds the messages of records.
+func RestoreMessages(records []MessageRecord) ([]Message, error) {
+	if records == nil {
+		return nil, nil
+	}
+	messages := make([]Message, 0, len(records))
+	for i, record := range records {
+		msg, err := record.Message()
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func recordThinking(thinking *Thinking) *Thinking {
+	if thinking == nil {
+		return nil
+	}
+	dst := *thinking
+	return &dst
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageRecordRoundTrip(t *testing.T) {
+	t.Parallel()
+	user := NewUserMessage("What is in this picture?")
+	user.Media = []Media{
+		&ImageMedia{URL: "https://example.test/cat.png", MimeType: "image/png"},
+		&DocumentMedia{Data: []byte("%PDF-1.7"), MimeType: "application/pdf", Filename: "spec.pdf", Citations: true},
+	}
+	assistant := &AssistantMessage{
+		Content:   "Looking it up.",
+		ToolCalls: []ToolCall{{ID: "call_1", Name: "lookup", Arguments: map[string]any{"q": "cat"}, ThoughtSignature: "sig"}},
+		Thinking:  &Thinking{Content: "need a lookup", Signature: "abc", Provider: "anthropic"},
+		AudioID:   "audio_1",
+	}
+	toolResult := NewToolResultMessage("call_1", "no results").WithError("upstream timeout")
+	toolResult.FunctionName = "lookup"
+	messages := []Message{NewSystemMessage("Be brief."), NewDeveloperMessage("Cite sources."), user, assistant, toolResult}
+
+	records, err := RecordMessages(messages)
+	require.NoError(t, err)
+	data, err := json.Marshal(records)
+	require.NoError(t, err)
+
+	var decoded []MessageRecord
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	restored, err := RestoreMessages(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, messages, restored)
+}
+
+func TestMessageRecordRejectsUnrecordable(t *testing.T) {
+	t.Parallel()
+	_, err := RecordMessages([]Message{BaseMessage{Role: RoleUser, Content: []MessagePart{TextPart("hi")}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "message 0")
+
+	record, err := RecordMessage(BaseMessage{Role: RoleAssistant, Content: "hello"})
+	require.NoError(t, err)
+	restored, err := record.Message()
+	require.NoError(t, err)
+	assert.Equal(t, NewAssistantMessage("hello"), restored)
+
+	_, err = MessageRecord{Role: "narrator"}.Message()
+	require.Error(t, err)
+	_, err = MessageRecord{Role: RoleUser, Media: []MediaRecord{{Type: "hologram", Data: []byte(`{}`)}}}.Message()
+	require.Error(t, err)
+}
@@ -12,8 +12,18 @@ const (
 	RoleUser      Role = "user"
 	RoleAssistant Role = "assistant"
 	RoleTool      Role = "tool"
+	// RoleDeveloper is OpenAI's instruction role for reasoning models, which
+	// outranks user messages the way system does. Providers without it treat
+	// developer messages as system messages.
+	RoleDeveloper Role = "developer"
 )
 
+// IsSystem reports whether r carries instructions rather than conversation:
+// RoleSystem or RoleDeveloper.
+func (r Role) IsSystem() bool {
+	return r == RoleSystem || r == RoleDeveloper
+}
+
 // Message represents a single message in a conversation
 type Message interface {
 	GetRole() Role
@@ -74,6 +84,37 @@ func NewSystemMessage(content string) *SystemMessage {
 	}
 }
 
+// DeveloperMessage represents a developer message, OpenAI's replacement for
+// system messages on reasoning models.
+type DeveloperMessage struct {
+	Content string `json:"content"`
+}
+
+func (m *DeveloperMessage) GetRole() Role {
+	return RoleDeveloper
+}
+
+func (m *DeveloperMessage) GetContent() any {
+	return m.Content
+}
+
+func (m *DeveloperMessage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Role    Role   `json:"role"`
+		Content string `json:"content"`
+	}{
+		Role:    RoleDeveloper,
+		Content: m.Content,
+	})
+}
+
+// NewDeveloperMessage creates a new developer message
+func NewDeveloperMessage(content string) *DeveloperMessage {
+	return &DeveloperMessage{
+		Content: content,
+	}
+}
+
 // UserMessage represents a user message
 type UserMessage struct {
 	Content string  `json:"content"`
@@ -115,6 +156,9 @@ type AssistantMessage struct {
 	// turn. Anthropic requires the signed thinking block echoed back when
 	// extended thinking is interleaved with tool_use; nil = nothing replayed.
 	Thinking *Thinking `json:"thinking,omitempty"`
+	// AudioID references audio the model spoke on a prior turn
+	// (TextResponse.Audio.ID), so OpenAI can replay it without the bytes.
+	AudioID string `json:"audio_id,omitempty"`
 }
 
 func (m *AssistantMessage) GetRole() Role {
@@ -131,11 +175,13 @@ func (m *AssistantMessage) MarshalJSON() ([]byte, error) {
 		Content   string     `json:"content"`
 		ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 		Thinking  *Thinking  `json:"thinking,omitempty"`
+		AudioID   string     `json:"audio_id,omitempty"`
 	}{
 		Role:      RoleAssistant,
 		Content:   m.Content,
 		ToolCalls: m.ToolCalls,
 		Thinking:  m.Thinking,
+		AudioID:   m.AudioID,
 	})
 }
 
@@ -240,13 +286,31 @@ func (m *ImageMedia) GetType() string {
 	return "image"
 }
 
-// DocumentMedia represents a document in a message
+// DocumentMedia represents a document, such as a PDF, in a message. Build one
+// with NewDocument or NewDocumentFromFile; providers check it against their
+// DocumentLimits before sending.
 type DocumentMedia struct {
 	URL      string `json:"url,omitempty"`
 	Data     []byte `json:"data,omitempty"`
 	MimeType string `json:"mime_type"`
+	Filename string `json:"filename,omitempty"`
+	// Citations asks the provider to cite passages of this document in
+	// TextResponse.Citations. Anthropic needs it per document; others ignore it.
+	Citations bool `json:"citations,omitempty"`
 }
 
 func (m *DocumentMedia) GetType() string {
 	return "document"
 }
+
+// AudioMedia is a recorded audio clip in a message, for models that listen,
+// such as gpt-4o-audio-preview. Build one with NewAudio.
+type AudioMedia struct {
+	Data []byte `json:"data"`
+	// Format is the encoding, e.g. "wav" or "mp3".
+	Format string `json:"format"`
+}
+
+func (m *AudioMedia) GetType() string {
+	return "audio"
+}
@@ -16,7 +16,7 @@ type ProviderMiddleware interface {
 	ApplyEmbeddings(next EmbeddingsHandler) EmbeddingsHandler
 	// ApplyAudio wraps audio calls
 	ApplyAudio(next AudioHandler) AudioHandler
-	// ApplyImage wraps image generation calls
+	// ApplyImage wraps image generation calls, and image edits and variations
 	ApplyImage(next ImageHandler) ImageHandler
 	// ApplyRerank wraps rerank calls
 	ApplyRerank(next RerankHandler) RerankHandler
@@ -30,6 +30,14 @@ type EmbeddingsHandler func(ctx context.Context, request EmbeddingsRequest) (*Em
 type AudioHandler func(ctx context.Context, request AudioRequest) (*AudioResponse, error)
 type ImageHandler func(ctx context.Context, request ImageRequest) (*ImageResponse, error)
 type RerankHandler func(ctx context.Context, request RerankRequest) (*RerankResponse, error)
+type AudioStreamHandler func(ctx context.Context, request AudioRequest) (<-chan TextChunk, error)
+
+// AudioStreamMiddleware is implemented by provider middleware that also wraps
+// streamed transcriptions. It is optional so existing middleware keeps
+// compiling; a chain skips middleware without it on those streams.
+type AudioStreamMiddleware interface {
+	ApplyAudioStream(next AudioStreamHandler) AudioStreamHandler
+}
 
 // ProviderMiddlewareChain manages provider-level middleware
 type ProviderMiddlewareChain struct {
@@ -91,3 +99,14 @@ func (c *ProviderMiddlewareChain) ApplyImage(handler ImageHandler) ImageHandler
 func (c *ProviderMiddlewareChain) ApplyRerank(handler RerankHandler) RerankHandler {
 	return applyChain(c.middlewares, handler, func(mw ProviderMiddleware, h RerankHandler) RerankHandler { return mw.ApplyRerank(h) })
 }
+
+// ApplyAudioStream applies the middlewares that implement
+// AudioStreamMiddleware to a streamed transcription handler.
+func (c *ProviderMiddlewareChain) ApplyAudioStream(handler AudioStreamHandler) AudioStreamHandler {
+	return applyChain(c.middlewares, handler, func(mw ProviderMiddleware, h AudioStreamHandler) AudioStreamHandler {
+		if streaming, ok := mw.(AudioStreamMiddleware); ok {
+			return streaming.ApplyAudioStream(h)
+		}
+		return h
+	})
+}
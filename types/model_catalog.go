@@ -0,0 +1,250 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ModelCatalog is a model registry backend: the lookups a client makes for
+// model validation, context sizing, and cost estimates. *ModelRegistry is the
+// in-memory implementation and ReadThroughRegistry fills one from provider
+// model listings. Pass one to a client with wormhole.WithModelRegistry.
+type ModelCatalog interface {
+	Get(modelID string) (*ModelInfo, bool)
+	// Count reports how many models are known. Clients skip model validation
+	// while it is zero.
+	Count() int
+	ValidateModel(modelID string, requiredCapabilities []ModelCapability) error
+	EstimateCost(modelID string, inputTokens, outputTokens int, images ...ImageSize) (float64, error)
+}
+
+// ModelRegistrar is implemented by catalogs that accept models directly;
+// wormhole.WithModels loads into the client's catalog through it.
+type ModelRegistrar interface {
+	Register(model *ModelInfo)
+}
+
+// ModelSource lists the models a backend offers. The provider fetchers in
+// the discovery/fetchers package implement it.
+type ModelSource interface {
+	FetchModels(ctx context.Context) ([]*ModelInfo, error)
+}
+
+// ModelSourceFunc adapts a function to ModelSource.
+type ModelSourceFunc func(ctx context.Context) ([]*ModelInfo, error)
+
+// FetchModels calls f.
+func (f ModelSourceFunc) FetchModels(ctx context.Context) ([]*ModelInfo, error) {
+	return f(ctx)
+}
+
+// ReadThroughConfig configures a ReadThroughRegistry.
+type ReadThroughConfig struct {
+	// TTL is how long fetched models are used before the next lookup fetches
+	// again. Zero means one hour.
+	TTL time.Duration
+	// RetryInterval is how long to wait after a failed fetch before trying
+	// again; lookups meanwhile use the last good models. Zero means 30 seconds.
+	RetryInterval time.Duration
+	// Timeout bounds one fetch across all sources. Zero means 30 seconds.
+	Timeout time.Duration
+}
+
+// ReadThroughRegistry is a ModelCatalog that fetches its models from one or
+// more sources on first use and again whenever they are older than the TTL.
+// A source that fails keeps serving the models it returned last time. Models
+// added with Register are kept across fetches and win over fetched entries
+// with the same ID. It is safe for concurrent use: concurrent lookups that
+// find the cache stale share one fetch, and the lock is not held while it
+// runs.
+//
+// Example:
+//
+//	registry := types.NewReadThroughRegistry(types.ReadThroughConfig{},
+//	    fetchers.NewOpenAIFetcher(openaiKey),
+//	    fetchers.NewAnthropicFetcher(anthropicKey),
+//	)
+//	client := wormhole.New(wormhole.WithOpenAI(openaiKey), wormhole.WithModelRegistry(registry))
+type ReadThroughRegistry struct {
+	sources []ModelSource
+	config  ReadThroughConfig
+	now     func() time.Time
+
+	mu       sync.Mutex
+	cache    *ModelRegistry
+	pinned   []*ModelInfo
+	lastGood [][]*ModelInfo // per source, the models of its last successful fetch
+	loading  *readThroughLoad
+	nextLoad time.Time
+	lastErr  error
+}
+
+// readThroughLoad is a fetch in flight; callers that arrive while it runs
+// wait on done and share err.
+type readThroughLoad struct {
+	done chan struct{}
+	err  error
+}
+
+// NewReadThroughRegistry returns a registry backed by sources. Nothing is
+// fetched until the first lookup.
+func NewReadThroughRegistry(config ReadThroughConfig, sources ...ModelSource) *ReadThroughRegistry {
+	if config.TTL <= 0 {
+		config.TTL = time.Hour
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = 30 * time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &ReadThroughRegistry{
+		sources:  append([]ModelSource(nil), sources...),
+		config:   config,
+		now:      time.Now,
+		cache:    NewModelRegistry(),
+		lastGood: make([][]*ModelInfo, len(sources)),
+	}
+}
+
+// Register pins model in the registry.
+func (r *ReadThroughRegistry) Register(model *ModelInfo) {
+	if model == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pinned = append(r.pinned, CloneModelInfo(model))
+	r.cache.Register(model)
+}
+
+// Refresh fetches from every source now, or joins a fetch already in flight.
+// A source that fails keeps its last good models; the failures are returned
+// joined.
+func (r *ReadThroughRegistry) Refresh(ctx context.Context) error {
+	return r.load(ctx, true)
+}
+
+// LastError returns the error of the most recent fetch, or nil.
+func (r *ReadThroughRegistry) LastError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastErr
+}
+
+// Get returns a model by ID, fetching first when the cache is stale.
+func (r *ReadThroughRegistry) Get(modelID string) (*ModelInfo, bool) {
+	return r.current().Get(modelID)
+}
+
+// Count returns the number of known models, fetching first when the cache is
+// stale.
+func (r *ReadThroughRegistry) Count() int {
+	return r.current().Count()
+}
+
+// List returns all known models, fetching first when the cache is stale.
+func (r *ReadThroughRegistry) List() []*ModelInfo {
+	return r.current().List()
+}
+
+// ValidateModel checks modelID against the fetched models.
+func (r *ReadThroughRegistry) ValidateModel(modelID string, requiredCapabilities []ModelCapability) error {
+	return r.current().ValidateModel(modelID, requiredCapabilities)
+}
+
+// EstimateCost prices a request with the fetched model's cost.
+func (r *ReadThroughRegistry) EstimateCost(modelID string, inputTokens, outputTokens int, images ...ImageSize) (float64, error) {
+	return r.current().EstimateCost(modelID, inputTokens, outputTokens, images...)
+}
+
+// current returns the cache, fetching first when it is stale.
+func (r *ReadThroughRegistry) current() *ModelRegistry {
+	r.mu.Lock()
+	stale := !r.now().Before(r.nextLoad)
+	r.mu.Unlock()
+	if stale {
+		ctx, cancel := context.WithTimeout(context.Background(), r.config.Timeout)
+		_ = r.load(ctx, false) // kept in lastErr; stale models keep serving
+		cancel()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cache
+}
+
+// load fetches every source into a fresh cache without holding r.mu. Only one
+// fetch runs at a time; a caller that finds one in flight waits for it. Unless
+// force is set, a cache that another caller refreshed meanwhile is kept.
+func (r *ReadThroughRegistry) load(ctx context.Context, force bool) error {
+	r.mu.Lock()
+	if !force && r.now().Before(r.nextLoad) {
+		r.mu.Unlock()
+		return nil
+	}
+	if call := r.loading; call != nil {
+		r.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	call := &readThroughLoad{done: make(chan struct{})}
+	r.loading = call
+	r.mu.Unlock()
+
+	fetched := make([][]*ModelInfo, 0, len(r.sources))
+	errs := make([]error, 0, len(r.sources))
+	defer func() {
+		// Deferred so a panicking source still releases waiters; only the
+		// sources that returned are applied.
+		r.mu.Lock()
+		call.err = r.apply(fetched, errs)
+		r.loading = nil
+		r.mu.Unlock()
+		close(call.done)
+	}()
+	for _, source := range r.sources {
+		models, err := source.FetchModels(ctx)
+		fetched = append(fetched, models)
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// apply rebuilds the cache from one fetch's results, indexed like r.sources,
+// keeping the last good models of every source that failed. r.mu must be
+// held.
+func (r *ReadThroughRegistry) apply(fetched [][]*ModelInfo, errs []error) error {
+	r.lastErr = errors.Join(errs...)
+	succeeded := false
+	for i, err := range errs {
+		if err == nil {
+			r.lastGood[i] = fetched[i]
+			succeeded = true
+		}
+	}
+	if !succeeded && len(r.sources) > 0 {
+		r.nextLoad = r.now().Add(r.config.RetryInterval)
+		return r.lastErr
+	}
+	cache := NewModelRegistry()
+	for _, models := range r.lastGood {
+		cache.LoadModelsFromConfig(models)
+	}
+	cache.LoadModelsFromConfig(r.pinned)
+	r.cache = cache
+	r.nextLoad = r.now().Add(r.config.TTL)
+	return r.lastErr
+}
+
+var (
+	_ ModelCatalog   = (*ModelRegistry)(nil)
+	_ ModelCatalog   = (*ReadThroughRegistry)(nil)
+	_ ModelRegistrar = (*ModelRegistry)(nil)
+	_ ModelRegistrar = (*ReadThroughRegistry)(nil)
+)
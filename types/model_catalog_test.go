@@ -0,0 +1,160 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadThroughRegistryFetchesLazilyAndRefreshesAfterTTL(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+	source := ModelSourceFunc(func(context.Context) ([]*ModelInfo, error) {
+		n := fetches.Add(1)
+		models := []*ModelInfo{{ID: "gpt-4o", Provider: "openai", Capabilities: []ModelCapability{CapabilityChat}}}
+		if n > 1 {
+			models = append(models, &ModelInfo{ID: "gpt-5", Provider: "openai", Capabilities: []ModelCapability{CapabilityChat}})
+		}
+		return models, nil
+	})
+	now := time.Unix(0, 0)
+	registry := NewReadThroughRegistry(ReadThroughConfig{TTL: time.Minute}, source)
+	registry.now = func() time.Time { return now }
+
+	assert.Zero(t, fetches.Load(), "nothing is fetched before the first lookup")
+	assert.Equal(t, 1, registry.Count())
+	_, ok := registry.Get("gpt-5")
+	assert.False(t, ok)
+	assert.Equal(t, int32(1), fetches.Load(), "lookups within the TTL use the cache")
+
+	now = now.Add(time.Minute)
+	_, ok = registry.Get("gpt-5")
+	assert.True(t, ok)
+	assert.Equal(t, int32(2), fetches.Load())
+	require.NoError(t, registry.ValidateModel("gpt-5", []ModelCapability{CapabilityChat}))
+}
+
+func TestReadThroughRegistryKeepsModelsWhenFetchFails(t *testing.T) {
+	t.Parallel()
+
+	var fail atomic.Bool
+	var fetches atomic.Int32
+	source := ModelSourceFunc(func(context.Context) ([]*ModelInfo, error) {
+		fetches.Add(1)
+		if fail.Load() {
+			return nil, errors.New("models endpoint down")
+		}
+		return []*ModelInfo{{ID: "claude-sonnet-4-5", Provider: "anthropic"}}, nil
+	})
+	now := time.Unix(0, 0)
+	registry := NewReadThroughRegistry(ReadThroughConfig{TTL: time.Minute, RetryInterval: 10 * time.Second}, source)
+	registry.now = func() time.Time { return now }
+
+	require.Equal(t, 1, registry.Count())
+	fail.Store(true)
+	now = now.Add(time.Minute)
+
+	_, ok := registry.Get("claude-sonnet-4-5")
+	assert.True(t, ok, "stale models keep serving")
+	assert.EqualError(t, registry.LastError(), "models endpoint down")
+	registry.Get("claude-sonnet-4-5")
+	assert.Equal(t, int32(2), fetches.Load(), "a failed fetch waits RetryInterval before retrying")
+
+	fail.Store(false)
+	now = now.Add(10 * time.Second)
+	registry.Get("claude-sonnet-4-5")
+	assert.Equal(t, int32(3), fetches.Load())
+	assert.NoError(t, registry.LastError())
+}
+
+func TestReadThroughRegistryRegisteredModelsSurviveRefresh(t *testing.T) {
+	t.Parallel()
+
+	source := ModelSourceFunc(func(context.Context) ([]*ModelInfo, error) {
+		return []*ModelInfo{{ID: "shared", Provider: "openai", Cost: &ModelCost{InputTokens: 1}}}, nil
+	})
+	failing := ModelSourceFunc(func(context.Context) ([]*ModelInfo, error) {
+		return nil, errors.New("unreachable")
+	})
+	registry := NewReadThroughRegistry(ReadThroughConfig{}, source, failing)
+	registry.Register(&ModelInfo{ID: "local", Provider: "ollama"})
+	registry.Register(&ModelInfo{ID: "shared", Provider: "openai", Cost: &ModelCost{InputTokens: 2}})
+
+	assert.EqualError(t, registry.Refresh(context.Background()), "unreachable", "one failing source does not discard the others")
+	_, ok := registry.Get("local")
+	assert.True(t, ok)
+	cost, err := registry.EstimateCost("shared", 1000, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, cost, 1e-12, "registered models win over fetched ones")
+}
+
+func TestReadThroughRegistryKeepsLastGoodModelsOfFailedSource(t *testing.T) {
+	t.Parallel()
+
+	var fail atomic.Bool
+	openai := ModelSourceFunc(func(context.Context) ([]*ModelInfo, error) {
+		return []*ModelInfo{{ID: "gpt-4o", Provider: "openai"}}, nil
+	})
+	anthropic := ModelSourceFunc(func(context.Context) ([]*ModelInfo, error) {
+		if fail.Load() {
+			return nil, errors.New("models endpoint down")
+		}
+		return []*ModelInfo{{ID: "claude-sonnet-4-5", Provider: "anthropic"}}, nil
+	})
+	registry := NewReadThroughRegistry(ReadThroughConfig{}, openai, anthropic)
+
+	require.NoError(t, registry.Refresh(context.Background()))
+	fail.Store(true)
+	assert.EqualError(t, registry.Refresh(context.Background()), "models endpoint down")
+
+	_, ok := registry.Get("claude-sonnet-4-5")
+	assert.True(t, ok, "a failed source keeps its last good models")
+	_, ok = registry.Get("gpt-4o")
+	assert.True(t, ok)
+}
+
+func TestReadThroughRegistrySharesOneFetchAndDoesNotBlockRegister(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	source := ModelSourceFunc(func(context.Context) ([]*ModelInfo, error) {
+		if fetches.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return []*ModelInfo{{ID: "gpt-4o", Provider: "openai"}}, nil
+	})
+	registry := NewReadThroughRegistry(ReadThroughConfig{}, source)
+
+	counts := make(chan int, 4)
+	for range 4 {
+		go func() { counts <- registry.Count() }()
+	}
+	<-started
+
+	registered := make(chan struct{})
+	go func() {
+		registry.Register(&ModelInfo{ID: "local", Provider: "ollama"})
+		assert.NoError(t, registry.LastError())
+		close(registered)
+	}()
+	select {
+	case <-registered:
+	case <-time.After(time.Second):
+		t.Fatal("Register blocked behind an in-flight fetch")
+	}
+
+	close(release)
+	for range 4 {
+		assert.Equal(t, 2, <-counts)
+	}
+	assert.Equal(t, int32(1), fetches.Load(), "concurrent stale lookups share one fetch")
+}
@@ -18,8 +18,15 @@ type ModelInfo struct {
 	MaxTokens     int               `json:"max_tokens,omitempty"`
 	Cost          *ModelCost        `json:"cost,omitempty"`
 	Capabilities  []ModelCapability `json:"capabilities"`
-	Constraints   map[string]any    `json:"constraints,omitempty"`
-	Deprecated    bool              `json:"deprecated,omitempty"`
+	// Constraints is free-form model metadata. Entries named after a request
+	// parameter, such as "temperature": 1.0, are the legacy form of
+	// ParamConstraints and are applied as ParamConstraintsFromMap reads them
+	// when ParamConstraints is nil.
+	Constraints map[string]any `json:"constraints,omitempty"`
+	// ParamConstraints overrides the built-in request parameter rules for
+	// this model; see ParamConstraints.
+	ParamConstraints *ParamConstraints `json:"param_constraints,omitempty"`
+	Deprecated       bool              `json:"deprecated,omitempty"`
 }
 
 // ModelCost represents the cost of using a model
@@ -27,6 +34,9 @@ type ModelCost struct {
 	InputTokens  float64 `json:"input_tokens"`  // Cost per 1K input tokens
 	OutputTokens float64 `json:"output_tokens"` // Cost per 1K output tokens
 	Currency     string  `json:"currency"`      // USD, EUR, etc.
+	// Images prices input images. Nil uses the provider's token rule for
+	// openai, anthropic, and gemini models; see ImagePricing.
+	Images *ImagePricing `json:"images,omitempty"`
 }
 
 // ModelCapability represents what a model can do
@@ -43,10 +53,34 @@ const (
 	CapabilityFunctions  ModelCapability = "functions"
 	CapabilityStream     ModelCapability = "stream"
 	CapabilityRerank     ModelCapability = "rerank"
+	// CapabilityWebSearch marks a provider-hosted web search tool
+	// (TextRequest.WebSearch).
+	CapabilityWebSearch ModelCapability = "web_search"
+	// CapabilityTokenCounting marks a provider that implements TokenCounter.
+	CapabilityTokenCounting ModelCapability = "token_counting"
+
+	// Request features a text model may or may not honor. Builders require
+	// them when the request sets the matching field.
+
+	// CapabilityAudioInput marks a model that accepts AudioMedia input.
+	CapabilityAudioInput ModelCapability = "audio_input"
+	// CapabilityAudioOutput marks a model that can reply with audio
+	// (TextRequest.Audio).
+	CapabilityAudioOutput ModelCapability = "audio_output"
+	// CapabilityJSONSchemaStrict marks a model that enforces a JSON schema
+	// while decoding (StructuredModeStrict).
+	CapabilityJSONSchemaStrict ModelCapability = "json_schema_strict"
+	// CapabilityParallelToolCalls marks a model that honors
+	// TextRequest.ParallelToolCalls.
+	CapabilityParallelToolCalls ModelCapability = "parallel_tool_calls"
+	// CapabilityLogprobs marks a model that returns token log probabilities.
+	CapabilityLogprobs ModelCapability = "logprobs"
+	// CapabilitySeed marks a model that accepts a sampling seed.
+	CapabilitySeed ModelCapability = "seed"
 )
 
 // ModelRegistry manages available models across providers.
-// It is safe for concurrent use: the global DefaultModelRegistry is mutated at
+// It is safe for concurrent use: a registry is mutated at
 // wormhole.New(WithModels(...)) time and read by validation helpers.
 type ModelRegistry struct {
 	mu         sync.RWMutex
@@ -189,8 +223,11 @@ func (r *ModelRegistry) ValidateModel(modelID string, requiredCapabilities []Mod
 	return nil
 }
 
-// EstimateCost calculates the estimated cost for a request
-func (r *ModelRegistry) EstimateCost(modelID string, inputTokens, outputTokens int) (float64, error) {
+// EstimateCost calculates the estimated cost for a request. Images add the
+// cost of input images not already counted in inputTokens (see
+// ImagePricing); leave them out when inputTokens is provider-reported usage,
+// which includes token-billed images.
+func (r *ModelRegistry) EstimateCost(modelID string, inputTokens, outputTokens int, images ...ImageSize) (float64, error) {
 	model, exists := r.Get(modelID)
 	if !exists {
 		return 0, ErrModelNotFound.WithModel(modelID)
@@ -203,10 +240,13 @@ func (r *ModelRegistry) EstimateCost(modelID string, inputTokens, outputTokens i
 	inputCost := (float64(inputTokens) / 1000.0) * model.Cost.InputTokens
 	outputCost := (float64(outputTokens) / 1000.0) * model.Cost.OutputTokens
 
-	return inputCost + outputCost, nil
+	return inputCost + outputCost + imageCost(model, images), nil
 }
 
 // GetConstraints returns model-specific constraints
+//
+// Deprecated: use ModelParamConstraints, which resolves the rules a client
+// applies to the model's requests.
 func (r *ModelRegistry) GetConstraints(modelID string) (map[string]any, error) {
 	model, exists := r.Get(modelID)
 	if !exists {
@@ -227,6 +267,10 @@ func (r *ModelRegistry) LoadModelsFromConfig(models []*ModelInfo) {
 // (opt-in): populate it with LoadModelsFromConfig, or pass models via
 // wormhole.WithModels(...) to wormhole.New. Until populated, model-validation
 // helpers have no models to validate against.
+//
+// Deprecated: give each client its own registry with
+// wormhole.WithModelRegistry(NewModelRegistry()) or a ReadThroughRegistry.
+// Clients built without one still use this global.
 var DefaultModelRegistry = NewModelRegistry()
 
 // Helper functions for model operations
@@ -242,11 +286,14 @@ func ValidateModelForCapability(modelID string, capability ModelCapability) erro
 }
 
 // GetModelConstraints returns constraints for a model
+//
+// Deprecated: use ModelParamConstraints.
 func GetModelConstraints(modelID string) (map[string]any, error) {
 	return DefaultModelRegistry.GetConstraints(modelID)
 }
 
-// EstimateModelCost calculates cost for input/output tokens
-func EstimateModelCost(modelID string, inputTokens, outputTokens int) (float64, error) {
-	return DefaultModelRegistry.EstimateCost(modelID, inputTokens, outputTokens)
+// EstimateModelCost calculates cost for input/output tokens and any input
+// images, using DefaultModelRegistry.
+func EstimateModelCost(modelID string, inputTokens, outputTokens int, images ...ImageSize) (float64, error) {
+	return DefaultModelRegistry.EstimateCost(modelID, inputTokens, outputTokens, images...)
 }
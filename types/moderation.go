@@ -0,0 +1,84 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// ModerationCategory is a provider-neutral harm category.
+type ModerationCategory string
+
+const (
+	ModerationHarassment   ModerationCategory = "harassment"
+	ModerationHate         ModerationCategory = "hate"
+	ModerationIllicit      ModerationCategory = "illicit"
+	ModerationSelfHarm     ModerationCategory = "self_harm"
+	ModerationSexual       ModerationCategory = "sexual"
+	ModerationSexualMinors ModerationCategory = "sexual_minors"
+	ModerationViolence     ModerationCategory = "violence"
+)
+
+// ModerationRequest asks whether each input is harmful.
+type ModerationRequest struct {
+	Model           string         `json:"model,omitempty"`
+	Input           []string       `json:"input"`
+	ProviderOptions map[string]any `json:"-"`
+}
+
+// ModerationResult classifies one input.
+type ModerationResult struct {
+	// Flagged reports whether the moderator considers the input harmful.
+	Flagged bool `json:"flagged"`
+	// Categories marks the unified categories the moderator flagged.
+	Categories map[ModerationCategory]bool `json:"categories"`
+	// Scores holds a 0-1 score per unified category: the highest score among
+	// the provider categories that map to it.
+	Scores map[ModerationCategory]float64 `json:"scores"`
+	// Raw holds the provider's own category scores, keyed by its names.
+	Raw map[string]float64 `json:"raw,omitempty"`
+}
+
+// Exceeds returns the categories scoring at least threshold, or the flagged
+// categories when threshold is zero.
+func (r ModerationResult) Exceeds(threshold float64) []ModerationCategory {
+	var categories []ModerationCategory
+	if threshold <= 0 {
+		for category, flagged := range r.Categories {
+			if flagged {
+				categories = append(categories, category)
+			}
+		}
+		return categories
+	}
+	for category, score := range r.Scores {
+		if score >= threshold {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// ModerationResponse holds one result per input, in input order.
+type ModerationResponse struct {
+	ID       string             `json:"id,omitempty"`
+	Provider string             `json:"provider,omitempty"`
+	Model    string             `json:"model,omitempty"`
+	Results  []ModerationResult `json:"results"`
+	Created  time.Time          `json:"created"`
+}
+
+// Flagged reports whether any input was flagged.
+func (r *ModerationResponse) Flagged() bool {
+	for _, result := range r.Results {
+		if result.Flagged {
+			return true
+		}
+	}
+	return false
+}
+
+// Moderator is implemented by providers and local models that classify text
+// for harmful content, such as OpenAI's moderation endpoint.
+type Moderator interface {
+	Moderate(ctx context.Context, request ModerationRequest) (*ModerationResponse, error)
+}
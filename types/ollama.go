@@ -0,0 +1,95 @@
+package types
+
+import (
+	"maps"
+	"time"
+)
+
+// OllamaOptions are Ollama runtime options that have no portable
+// equivalent. Pass them with ProviderOptions:
+//
+//	keepAlive := 30 * time.Minute
+//	client.Text().
+//	    Using("ollama").
+//	    Model("llama3.2").
+//	    ProviderOptions(types.OllamaOptions{
+//	        KeepAlive: &keepAlive,
+//	        NumCtx:    16384,
+//	        Mirostat:  2,
+//	    }.ProviderOptions()).
+//	    Prompt("Summarize this log").
+//	    Generate(ctx)
+//
+// Zero fields are left to the model's Modelfile defaults.
+type OllamaOptions struct {
+	// KeepAlive is how long the model stays loaded after the request. A
+	// negative duration keeps it loaded indefinitely; zero unloads it
+	// immediately.
+	KeepAlive *time.Duration
+	// NumCtx is the context window size in tokens.
+	NumCtx int
+	// NumGPU is the number of layers offloaded to the GPU; 0 runs on CPU.
+	NumGPU *int
+	// NumThread is the number of CPU threads used for generation.
+	NumThread int
+	// NumBatch is the prompt processing batch size.
+	NumBatch int
+	// TopK limits sampling to the K most likely tokens.
+	TopK int
+	// MinP discards tokens less likely than MinP times the most likely one.
+	MinP float64
+	// RepeatLastN is how far back RepeatPenalty looks, in tokens.
+	RepeatLastN int
+	// RepeatPenalty penalizes repeated tokens; 1.0 disables it.
+	RepeatPenalty float64
+	// Mirostat enables Mirostat sampling: 1 for Mirostat, 2 for Mirostat 2.0.
+	Mirostat int
+	// MirostatTau is the target entropy; lower is more focused.
+	MirostatTau float64
+	// MirostatEta is the learning rate of Mirostat's feedback.
+	MirostatEta float64
+	// Options passes any other runtime option through verbatim, keyed by its
+	// Ollama name (e.g. "num_keep", "tfs_z"). The typed fields win on
+	// conflict.
+	Options map[string]any
+}
+
+// ProviderOptions returns the options as request fields for ProviderOptions.
+func (o OllamaOptions) ProviderOptions() map[string]any {
+	runtime := maps.Clone(o.Options)
+	if runtime == nil {
+		runtime = make(map[string]any)
+	}
+	setPositive := func(key string, value float64) {
+		if value > 0 {
+			runtime[key] = value
+		}
+	}
+	setPositiveInt := func(key string, value int) {
+		if value > 0 {
+			runtime[key] = value
+		}
+	}
+	setPositiveInt("num_ctx", o.NumCtx)
+	if o.NumGPU != nil {
+		runtime["num_gpu"] = *o.NumGPU
+	}
+	setPositiveInt("num_thread", o.NumThread)
+	setPositiveInt("num_batch", o.NumBatch)
+	setPositiveInt("top_k", o.TopK)
+	setPositive("min_p", o.MinP)
+	setPositiveInt("repeat_last_n", o.RepeatLastN)
+	setPositive("repeat_penalty", o.RepeatPenalty)
+	setPositiveInt("mirostat", o.Mirostat)
+	setPositive("mirostat_tau", o.MirostatTau)
+	setPositive("mirostat_eta", o.MirostatEta)
+
+	options := make(map[string]any, 2)
+	if len(runtime) > 0 {
+		options["options"] = runtime
+	}
+	if o.KeepAlive != nil {
+		options["keep_alive"] = o.KeepAlive.String()
+	}
+	return options
+}
@@ -0,0 +1,324 @@
+package types
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ParamConstraints declares the request parameters a model rejects or pins.
+// Clients apply them to every request for the model just before the provider
+// call, so callers can set temperature on a fallback chain that includes a
+// reasoning model without the reasoning attempt failing. Parameters use their
+// JSON names: temperature, top_p, max_tokens, stop, presence_penalty,
+// frequency_penalty, seed, parallel_tool_calls, top_k, min_p,
+// repetition_penalty, logit_bias, and logprobs.
+//
+// Set ModelInfo.ParamConstraints to declare them for a registered model; an
+// empty value turns off the built-in rules for that model. See
+// wormhole.WithoutParamConstraints to turn them off for a client.
+type ParamConstraints struct {
+	// Forbidden parameters are dropped from requests.
+	Forbidden []string `json:"forbidden,omitempty"`
+	// Forced parameters are set to the given value whatever the request says.
+	Forced map[string]any `json:"forced,omitempty"`
+	// MaxOutputTokens caps max_tokens. Zero means no cap.
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+	// MaxTokensParam is the wire name for max_tokens, such as
+	// "max_completion_tokens". OpenAI-compatible providers honor it.
+	MaxTokensParam string `json:"max_tokens_param,omitempty"`
+	// Roles rewrites message roles the model rejects, such as system
+	// messages for OpenAI reasoning models; see RoleMap.
+	Roles RoleMap `json:"roles,omitempty"`
+}
+
+// reasoningSamplingParams are rejected by OpenAI reasoning models.
+var reasoningSamplingParams = []string{"temperature", "top_p", "presence_penalty", "frequency_penalty", "logit_bias", "logprobs"}
+
+// BuiltinParamConstraints returns the constraints wormhole knows for modelID
+// without a registry entry, or nil. The original GPT-5 family and the o1, o3,
+// and o4 reasoning models reject sampling parameters; every GPT-5 and o-series
+// model takes max_completion_tokens. The o-series models take developer
+// messages in place of system ones, except o1-mini and o1-preview, which
+// accept neither and get them as user messages. A provider prefix such as
+// "openai/" is ignored.
+func BuiltinParamConstraints(modelID string) *ParamConstraints {
+	name := strings.ToLower(modelID)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	switch {
+	case strings.HasPrefix(name, "o1-mini") || strings.HasPrefix(name, "o1-preview"):
+		return &ParamConstraints{
+			Forbidden:      slices.Clone(reasoningSamplingParams),
+			MaxTokensParam: "max_completion_tokens",
+			Roles:          RoleMap{RoleSystem: RoleUser, RoleDeveloper: RoleUser},
+		}
+	case isOSeriesModel(name):
+		return &ParamConstraints{
+			Forbidden:      slices.Clone(reasoningSamplingParams),
+			MaxTokensParam: "max_completion_tokens",
+			Roles:          RoleMap{RoleSystem: RoleDeveloper},
+		}
+	case name == "gpt-5" || (strings.HasPrefix(name, "gpt-5-") && !strings.Contains(name, "chat")):
+		return &ParamConstraints{
+			Forbidden:      slices.Clone(reasoningSamplingParams),
+			MaxTokensParam: "max_completion_tokens",
+		}
+	case strings.HasPrefix(name, "gpt-5"):
+		return &ParamConstraints{MaxTokensParam: "max_completion_tokens"}
+	}
+	return nil
+}
+
+func isOSeriesModel(name string) bool {
+	for _, prefix := range []string{"o1", "o3", "o4"} {
+		if name == prefix || strings.HasPrefix(name, prefix+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// ModelParamConstraints returns the constraints for modelID: the registry
+// entry's when it declares them, else the built-in ones. Parameter entries in
+// the entry's legacy Constraints map are added to the built-in rules. It
+// returns nil when none apply.
+func ModelParamConstraints(catalog ModelCatalog, modelID string) *ParamConstraints {
+	var legacy *ParamConstraints
+	if catalog != nil {
+		if model, ok := catalog.Get(modelID); ok {
+			if model.ParamConstraints != nil {
+				return model.ParamConstraints
+			}
+			legacy = ParamConstraintsFromMap(model.Constraints)
+		}
+	}
+	rules := BuiltinParamConstraints(modelID)
+	if legacy == nil {
+		return rules
+	}
+	if rules == nil {
+		return legacy
+	}
+	// The built-in rules never force values or cap output.
+	rules.Forced, rules.MaxOutputTokens = legacy.Forced, legacy.MaxOutputTokens
+	return rules
+}
+
+// forceableParams are the parameters ParamConstraints.Forced can set.
+var forceableParams = []string{
+	"temperature", "top_p", "presence_penalty", "frequency_penalty", "min_p",
+	"repetition_penalty", "seed", "top_k", "parallel_tool_calls", "logprobs",
+}
+
+// ParamConstraintsFromMap reads the parameter entries of a legacy
+// ModelInfo.Constraints map such as {"temperature": 1.0, "max_tokens": 4096}:
+// max_tokens caps the output and the other parameters are forced to their
+// values. Entries that name no parameter are model metadata and are skipped.
+// It returns nil when no entry applies.
+func ParamConstraintsFromMap(constraints map[string]any) *ParamConstraints {
+	var c ParamConstraints
+	for name, value := range constraints {
+		switch {
+		case name == "max_tokens":
+			if limit, ok := numberValue(value); ok && limit > 0 {
+				c.MaxOutputTokens = int(limit)
+			}
+		case slices.Contains(forceableParams, name):
+			if c.Forced == nil {
+				c.Forced = make(map[string]any)
+			}
+			c.Forced[name] = value
+		}
+	}
+	if c.Forced == nil && c.MaxOutputTokens == 0 {
+		return nil
+	}
+	return &c
+}
+
+// Clone returns a detached copy of c.
+func (c *ParamConstraints) Clone() *ParamConstraints {
+	if c == nil {
+		return nil
+	}
+	dst := *c
+	dst.Forbidden = slices.Clone(c.Forbidden)
+	dst.Forced = CloneMap(c.Forced)
+	dst.Roles = maps.Clone(c.Roles)
+	return &dst
+}
+
+// ApplyText applies c to request in place and returns the names of the
+// parameters it changed.
+func (c *ParamConstraints) ApplyText(request *TextRequest) []string {
+	if c == nil {
+		return nil
+	}
+	changed := c.Apply(&request.BaseRequest)
+	if messages, ok := c.Roles.Apply(request.Messages); ok {
+		request.Messages = messages
+		changed = append(changed, "roles")
+	}
+	if slices.Contains(c.Forbidden, "logprobs") && request.Logprobs {
+		request.Logprobs, request.TopLogprobs = false, 0
+		changed = append(changed, "logprobs")
+	}
+	if value, ok := c.Forced["logprobs"].(bool); ok && request.Logprobs != value {
+		request.Logprobs = value
+		changed = append(changed, "logprobs")
+	}
+	slices.Sort(changed)
+	return slices.Compact(changed)
+}
+
+// ApplyStructured applies c to request in place and returns the names of the
+// parameters it changed.
+func (c *ParamConstraints) ApplyStructured(request *StructuredRequest) []string {
+	if c == nil {
+		return nil
+	}
+	changed := c.Apply(&request.BaseRequest)
+	if messages, ok := c.Roles.Apply(request.Messages); ok {
+		request.Messages = messages
+		changed = append(changed, "roles")
+		slices.Sort(changed)
+	}
+	return changed
+}
+
+// Apply applies c to the parameters every request carries and returns the
+// names of the ones it changed. Unknown parameter names are ignored.
+func (c *ParamConstraints) Apply(request *BaseRequest) []string {
+	if c == nil {
+		return nil
+	}
+	var changed []string
+	for _, name := range c.Forbidden {
+		if clearParam(request, name) {
+			changed = append(changed, name)
+		}
+	}
+	for name, value := range c.Forced {
+		if forceParam(request, name, value) {
+			changed = append(changed, name)
+		}
+	}
+	if c.MaxOutputTokens > 0 && request.MaxTokens != nil && *request.MaxTokens > c.MaxOutputTokens {
+		limit := c.MaxOutputTokens
+		request.MaxTokens = &limit
+		changed = append(changed, "max_tokens")
+	}
+	if c.MaxTokensParam != "" && request.MaxTokensParam == "" {
+		request.MaxTokensParam = c.MaxTokensParam
+	}
+	slices.Sort(changed)
+	return slices.Compact(changed)
+}
+
+func clearParam(request *BaseRequest, name string) bool {
+	switch name {
+	case "temperature":
+		return clearField(&request.Temperature)
+	case "top_p":
+		return clearField(&request.TopP)
+	case "max_tokens":
+		return clearField(&request.MaxTokens)
+	case "presence_penalty":
+		return clearField(&request.PresencePenalty)
+	case "frequency_penalty":
+		return clearField(&request.FrequencyPenalty)
+	case "seed":
+		return clearField(&request.Seed)
+	case "parallel_tool_calls":
+		return clearField(&request.ParallelToolCalls)
+	case "top_k":
+		return clearField(&request.TopK)
+	case "min_p":
+		return clearField(&request.MinP)
+	case "repetition_penalty":
+		return clearField(&request.RepetitionPenalty)
+	case "stop":
+		set := len(request.Stop) > 0
+		request.Stop = nil
+		return set
+	case "logit_bias":
+		set := len(request.LogitBias) > 0
+		request.LogitBias = nil
+		return set
+	}
+	return false
+}
+
+func clearField[T any](field **T) bool {
+	set := *field != nil
+	*field = nil
+	return set
+}
+
+func forceParam(request *BaseRequest, name string, value any) bool {
+	switch name {
+	case "temperature":
+		return forceFloat(&request.Temperature, value)
+	case "top_p":
+		return forceFloat(&request.TopP, value)
+	case "presence_penalty":
+		return forceFloat(&request.PresencePenalty, value)
+	case "frequency_penalty":
+		return forceFloat(&request.FrequencyPenalty, value)
+	case "min_p":
+		return forceFloat(&request.MinP, value)
+	case "repetition_penalty":
+		return forceFloat(&request.RepetitionPenalty, value)
+	case "max_tokens":
+		return forceInt(&request.MaxTokens, value)
+	case "seed":
+		return forceInt(&request.Seed, value)
+	case "top_k":
+		return forceInt(&request.TopK, value)
+	case "parallel_tool_calls":
+		v, ok := value.(bool)
+		if !ok || request.ParallelToolCalls != nil && *request.ParallelToolCalls == v {
+			return false
+		}
+		request.ParallelToolCalls = &v
+		return true
+	}
+	return false
+}
+
+func forceFloat(field **float32, value any) bool {
+	f, ok := numberValue(value)
+	if !ok || *field != nil && **field == float32(f) {
+		return false
+	}
+	v := float32(f)
+	*field = &v
+	return true
+}
+
+func forceInt(field **int, value any) bool {
+	f, ok := numberValue(value)
+	if !ok || *field != nil && **field == int(f) {
+		return false
+	}
+	v := int(f)
+	*field = &v
+	return true
+}
+
+// numberValue reads numbers as written in Go or decoded from JSON or YAML.
+func numberValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinParamConstraints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		model     string
+		forbidden bool
+		rename    bool
+	}{
+		{"gpt-5", true, true},
+		{"openai/gpt-5-mini", true, true},
+		{"gpt-5-chat-latest", false, true},
+		{"gpt-5.2", false, true},
+		{"o1", true, true},
+		{"o4-mini", true, true},
+		{"gpt-4o", false, false},
+		{"omni-moderation-latest", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			t.Parallel()
+			got := BuiltinParamConstraints(tt.model)
+			if !tt.forbidden && !tt.rename {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tt.forbidden, len(got.Forbidden) > 0)
+			assert.Equal(t, tt.rename, got.MaxTokensParam == "max_completion_tokens")
+		})
+	}
+}
+
+func TestParamConstraintsApplyText(t *testing.T) {
+	t.Parallel()
+
+	temperature, topP := float32(0.4), float32(0.9)
+	maxTokens, topK := 8000, 40
+	request := TextRequest{
+		BaseRequest: BaseRequest{Temperature: &temperature, TopP: &topP, MaxTokens: &maxTokens, TopK: &topK, Stop: []string{"END"}},
+		Logprobs:    true,
+		TopLogprobs: 3,
+	}
+	constraints := &ParamConstraints{
+		Forbidden:       []string{"top_p", "stop", "logprobs", "unknown"},
+		Forced:          map[string]any{"temperature": 1.0, "top_k": 40, "parallel_tool_calls": false},
+		MaxOutputTokens: 4096,
+		MaxTokensParam:  "max_completion_tokens",
+	}
+
+	changed := constraints.ApplyText(&request)
+	assert.Equal(t, []string{"logprobs", "max_tokens", "parallel_tool_calls", "stop", "temperature", "top_p"}, changed)
+	assert.InDelta(t, 1.0, *request.Temperature, 1e-6)
+	assert.Nil(t, request.TopP)
+	assert.Nil(t, request.Stop)
+	assert.Equal(t, 4096, *request.MaxTokens)
+	assert.Equal(t, 40, *request.TopK)
+	assert.False(t, *request.ParallelToolCalls)
+	assert.False(t, request.Logprobs)
+	assert.Zero(t, request.TopLogprobs)
+	assert.Equal(t, "max_completion_tokens", request.MaxTokensParam)
+	assert.InDelta(t, 0.4, temperature, 1e-6, "the caller's values are not written through")
+}
+
+func TestParamConstraintsRoles(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, RoleMap{RoleSystem: RoleDeveloper}, BuiltinParamConstraints("openai/o3-mini").Roles)
+	assert.Equal(t, RoleUser, BuiltinParamConstraints("o1-preview").Roles[RoleDeveloper])
+	assert.Nil(t, BuiltinParamConstraints("gpt-5").Roles)
+
+	image := &UserMessage{Content: "look", Media: []Media{&ImageMedia{URL: "https://example.test/a.png"}}}
+	messages := []Message{NewSystemMessage("rules"), NewDeveloperMessage("more rules"), image, NewAssistantMessage("ok")}
+	request := StructuredRequest{Messages: messages}
+	constraints := &ParamConstraints{Roles: RoleMap{RoleSystem: RoleUser, RoleDeveloper: RoleSystem, RoleUser: RoleSystem}}
+
+	assert.Equal(t, []string{"roles"}, constraints.ApplyStructured(&request))
+	assert.Equal(t, NewUserMessage("rules"), request.Messages[0])
+	assert.Equal(t, NewSystemMessage("more rules"), request.Messages[1])
+	assert.Same(t, image, request.Messages[2], "user messages with media keep their role")
+	assert.Equal(t, RoleSystem, messages[0].GetRole(), "the caller's messages are not written through")
+
+	unchanged, ok := RoleMap{RoleAssistant: RoleUser}.Apply(messages)
+	assert.False(t, ok)
+	assert.Equal(t, messages, unchanged)
+}
+
+func TestModelParamConstraintsReadsLegacyConstraints(t *testing.T) {
+	t.Parallel()
+
+	registry := NewModelRegistry()
+	registry.Register(&ModelInfo{ID: "gpt-5", Constraints: map[string]any{"temperature": 1.0, "max_tokens": 4096}})
+	registry.Register(&ModelInfo{ID: "local-model", Constraints: map[string]any{"loaded": true, "seed": 7}})
+	registry.Register(&ModelInfo{ID: "gpt-4o", Constraints: map[string]any{"loaded": true}})
+
+	gpt5 := ModelParamConstraints(registry, "gpt-5")
+	assert.Equal(t, map[string]any{"temperature": 1.0}, gpt5.Forced)
+	assert.Equal(t, 4096, gpt5.MaxOutputTokens)
+	assert.Equal(t, "max_completion_tokens", gpt5.MaxTokensParam, "the built-in rules still apply")
+	assert.NotSame(t, gpt5, ModelParamConstraints(registry, "gpt-5"))
+
+	assert.Equal(t, &ParamConstraints{Forced: map[string]any{"seed": 7}}, ModelParamConstraints(registry, "local-model"))
+	assert.Nil(t, ModelParamConstraints(registry, "gpt-4o"), "metadata entries are not rules")
+}
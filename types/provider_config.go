@@ -1,6 +1,7 @@
 package types
 
 import (
+	"net/http"
 	"time"
 )
 
@@ -19,6 +20,13 @@ type ProviderConfig struct {
 	ProviderOptionsByModel map[string]map[string]any `json:"provider_options_by_model,omitempty"`
 	RequestPolicy          ProviderRequestPolicy     `json:"request_policy,omitempty"`
 
+	// Organization and Project attribute usage to an OpenAI organization and
+	// project (sent as OpenAI-Organization and OpenAI-Project). They take
+	// precedence over the same headers in Headers. Anthropic has no
+	// equivalent: workspace attribution follows the API key.
+	Organization string `json:"organization,omitempty"`
+	Project      string `json:"project,omitempty"`
+
 	// ChatPath overrides the chat-completions path appended to BaseURL.
 	// Empty means the provider's default ("/chat/completions" for OpenAI).
 	ChatPath string `json:"chat_path,omitempty"`
@@ -36,6 +44,44 @@ type ProviderConfig struct {
 	// Empty means the provider's default ("/images/generations" for OpenAI).
 	ImagePath string `json:"image_path,omitempty"`
 
+	// IdempotencyHeader names the HTTP header (for example "Idempotency-Key")
+	// that carries each request's idempotency key, for APIs and gateways that
+	// deduplicate on it. The key defaults to the request fingerprint. Empty
+	// sends no header.
+	IdempotencyHeader string `json:"idempotency_header,omitempty"`
+
+	// SchemaSupport overrides the JSON Schema profile used to downgrade
+	// response schemas and tool parameters before they are sent. Nil keeps
+	// the provider default: Gemini downgrades to its own profile, other
+	// providers send schemas unchanged.
+	SchemaSupport *SchemaSupport `json:"schema_support,omitempty"`
+
+	// Compression controls gzip on request and response bodies.
+	Compression CompressionConfig `json:"compression,omitempty"`
+
+	// UserAgent replaces the default "wormhole/<version> (<go version>; ...)"
+	// User-Agent. DisableUserAgent sends no User-Agent header at all, not even
+	// Go's default. A User-Agent entry in Headers takes precedence over both.
+	UserAgent        string `json:"user_agent,omitempty"`
+	DisableUserAgent bool   `json:"disable_user_agent,omitempty"`
+
+	// Transport tunes the connection pool of the SDK's client. It has no
+	// effect when HTTPClient is set.
+	Transport TransportConfig `json:"transport,omitempty"`
+
+	// HTTPClient, when set, sends this provider's requests in place of the
+	// SDK's pooled client, for proxies, custom TLS, or instrumented clients
+	// you already run. Its Timeout and Transport are used as-is; ProviderConfig
+	// TLS settings do not apply to it.
+	HTTPClient *http.Client `json:"-"`
+
+	// WrapTransport, when set, wraps the transport of this provider's client
+	// (HTTPClient's, or the SDK's default) once, at provider creation. The
+	// returned RoundTripper sees every request after auth, compression and
+	// headers are applied, and every retry, so it suits request signing,
+	// egress headers, and logging raw traffic.
+	WrapTransport func(next http.RoundTripper) http.RoundTripper `json:"-"`
+
 	// APIKeys, when it holds more than one entry, enables round-robin key
 	// rotation on HTTP 429 within the retry path. Requires MaxRetries > 0.
 	// A single key here (or only APIKey set) behaves identically to before.
@@ -66,6 +112,42 @@ type ProviderRequestPolicy struct {
 	MaxTokensParam      string               `json:"max_tokens_param,omitempty"`
 	MaxTokensParamRules []MaxTokensParamRule `json:"max_tokens_param_rules,omitempty"`
 	MaxTokensCap        int                  `json:"max_tokens_cap,omitempty"`
+	// RerankTopNParam names the rerank result-limit parameter; empty means "top_n".
+	RerankTopNParam string `json:"rerank_top_n_param,omitempty"`
+	// SamplingParams lists the extended sampling controls an OpenAI-compatible
+	// endpoint accepts. Nil sends any the request sets, for endpoints whose
+	// support is unknown; an empty list rejects them all.
+	SamplingParams []SamplingParam `json:"sampling_params,omitempty"`
+	// Roles rewrites message roles the endpoint rejects, such as
+	// RoleMap{RoleDeveloper: RoleSystem} for OpenAI-compatible servers that
+	// predate the developer role. Model rules (ParamConstraints.Roles) run
+	// first.
+	Roles RoleMap `json:"roles,omitempty"`
+	// ThinkTags moves a leading <think>...</think> block out of response
+	// content and into Thinking, for endpoints that return reasoning inline
+	// rather than in reasoning_content, as DeepSeek-R1 distills do on Groq,
+	// vLLM, Ollama and LM Studio.
+	ThinkTags bool `json:"think_tags,omitempty"`
+}
+
+// CompressionConfig controls HTTP body compression for one provider.
+//
+// By default non-streaming requests negotiate gzip responses and decode them
+// transparently, while streaming requests ask for identity encoding so SSE
+// events are not held back in a compression buffer. Zstandard is never
+// negotiated: the standard library has no decoder for it.
+type CompressionConfig struct {
+	// GzipRequests gzips JSON request bodies and sends Content-Encoding: gzip.
+	// Enable it only for endpoints that accept compressed bodies, typically
+	// self-hosted gateways; hosted provider APIs generally reject them.
+	GzipRequests bool `json:"gzip_requests,omitempty"`
+
+	// MinRequestBytes leaves smaller bodies uncompressed. Zero means 1 KiB.
+	MinRequestBytes int `json:"min_request_bytes,omitempty"`
+
+	// DisableResponseCompression sends Accept-Encoding: identity on every
+	// request, for gateways that mishandle compressed responses.
+	DisableResponseCompression bool `json:"disable_response_compression,omitempty"`
 }
 
 // MaxTokensParamRule selects a request parameter name when ModelContains is
@@ -170,6 +252,20 @@ func (c ProviderConfig) WithHTTPTimeout(timeout time.Duration) ProviderConfig {
 	return c
 }
 
+// WithGzipRequests gzips request bodies of at least minBytes (zero means
+// 1 KiB). Only use it with endpoints that accept Content-Encoding: gzip.
+func (c ProviderConfig) WithGzipRequests(minBytes int) ProviderConfig {
+	c.Compression.GzipRequests = true
+	c.Compression.MinRequestBytes = minBytes
+	return c
+}
+
+// WithUserAgent sets the User-Agent sent with every request to this provider.
+func (c ProviderConfig) WithUserAgent(userAgent string) ProviderConfig {
+	c.UserAgent = userAgent
+	return c
+}
+
 // WithDynamicModels enables dynamic model discovery for this provider.
 // When enabled, the provider can use any model name without local validation.
 func (c ProviderConfig) WithDynamicModels() ProviderConfig {
@@ -198,6 +294,29 @@ func (c ProviderConfig) WithParams(params map[string]any) ProviderConfig {
 	return c
 }
 
+// WithHTTPClient sends this provider's requests through client.
+func (c ProviderConfig) WithHTTPClient(client *http.Client) ProviderConfig {
+	c.HTTPClient = client
+	return c
+}
+
+// WithTransportWrapper sets WrapTransport. Calling it again composes: the
+// new wrapper runs outside the earlier one.
+//
+// Example:
+//
+//	config := types.NewProviderConfig(key).WithTransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+//	    return &requestSigner{next: next, secret: secret}
+//	})
+func (c ProviderConfig) WithTransportWrapper(wrap func(next http.RoundTripper) http.RoundTripper) ProviderConfig {
+	if inner := c.WrapTransport; inner != nil {
+		c.WrapTransport = func(next http.RoundTripper) http.RoundTripper { return wrap(inner(next)) }
+	} else {
+		c.WrapTransport = wrap
+	}
+	return c
+}
+
 // WithDefaultProviderOptions sets provider-specific body fields included on
 // every request for this provider unless overridden by model or request options.
 func (c ProviderConfig) WithDefaultProviderOptions(options map[string]any) ProviderConfig {
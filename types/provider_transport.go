@@ -0,0 +1,39 @@
+package types
+
+import "time"
+
+// TransportConfig tunes the HTTP connection pool behind one provider. Zero
+// fields keep the defaults: 100 idle connections, 10 per host, no per-host
+// cap, a 90s idle timeout, HTTP/2 when the server offers it, and the proxy
+// from HTTP_PROXY/HTTPS_PROXY.
+//
+// Providers whose base URL host and transport settings match share one
+// pooled transport, so raising MaxIdleConnsPerHost for a busy host helps
+// every provider pointed at it rather than opening a pool per provider.
+type TransportConfig struct {
+	MaxIdleConns        int           `json:"max_idle_conns,omitempty"`
+	MaxIdleConnsPerHost int           `json:"max_idle_conns_per_host,omitempty"`
+	MaxConnsPerHost     int           `json:"max_conns_per_host,omitempty"`
+	IdleConnTimeout     time.Duration `json:"idle_conn_timeout,omitempty"`
+
+	// DisableHTTP2 keeps connections on HTTP/1.1, for gateways with broken
+	// HTTP/2 or when many parallel connections beat one multiplexed one.
+	DisableHTTP2 bool `json:"disable_http2,omitempty"`
+
+	// ProxyURL sends requests through this proxy, e.g.
+	// "http://proxy.corp:3128", instead of the environment's proxy.
+	ProxyURL string `json:"proxy_url,omitempty"`
+}
+
+// WithTransport sets the provider's connection pool tuning.
+//
+// Example:
+//
+//	config := types.NewProviderConfig(key).WithTransport(types.TransportConfig{
+//	    MaxIdleConnsPerHost: 64,
+//	    IdleConnTimeout:     2 * time.Minute,
+//	})
+func (c ProviderConfig) WithTransport(transport TransportConfig) ProviderConfig {
+	c.Transport = transport
+	return c
+}
@@ -0,0 +1,82 @@
+package types
+
+import "strings"
+
+// maxHeuristicRefusalLength bounds the text DetectRefusal inspects. Refusals
+// are a sentence or two; a long answer that opens with "I can't" is usually
+// a real answer with a caveat.
+const maxHeuristicRefusalLength = 400
+
+// refusalPrefixes are the openings DetectRefusal treats as a refusal, in
+// lower case with straight apostrophes.
+var refusalPrefixes = []string{
+	"i can't help with",
+	"i cannot help with",
+	"i can't assist with",
+	"i cannot assist with",
+	"i can't provide",
+	"i cannot provide",
+	"i can't comply",
+	"i cannot comply",
+	"i'm sorry, but i can't",
+	"i'm sorry, but i cannot",
+	"i am sorry, but i cannot",
+	"i'm sorry, i can't",
+	"sorry, but i can't",
+	"sorry, i can't",
+	"i'm unable to help",
+	"i am unable to help",
+	"i'm unable to assist",
+	"i am unable to assist",
+	"i'm not able to help",
+	"i won't be able to help",
+}
+
+// DetectRefusal reports whether text reads like a model declining to answer.
+// It is a heuristic for providers without a refusal signal: it only matches
+// short text that opens with a stock refusal such as "I can't help with" or
+// "I'm sorry, but I can't".
+func DetectRefusal(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > maxHeuristicRefusalLength {
+		return false
+	}
+	text = strings.ToLower(strings.ReplaceAll(text, "’", "'"))
+	for _, prefix := range refusalPrefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRefusal reports whether the model declined to answer. It is true when
+// the provider returned a refusal message (OpenAI's refusal field), stopped
+// with a refusal stop reason (Anthropic's "refusal"), or, failing both, when
+// the text without tool calls matches DetectRefusal.
+func (r *TextResponse) IsRefusal() bool {
+	if r == nil {
+		return false
+	}
+	if r.Refusal != "" || r.RawFinishReason == "refusal" {
+		return true
+	}
+	return len(r.ToolCalls) == 0 && DetectRefusal(r.Text)
+}
+
+// RefusalError returns ErrRefusal carrying the response's provider, model,
+// and refusal message, or nil when IsRefusal is false.
+func (r *TextResponse) RefusalError() error {
+	if !r.IsRefusal() {
+		return nil
+	}
+	message := r.Refusal
+	if message == "" {
+		message = r.Text
+	}
+	err := ErrRefusal.WithProvider(r.Provider).WithModel(r.Model)
+	if message != "" {
+		err = err.WithDetails(message)
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectRefusal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"plain refusal", "I can't help with that request.", true},
+		{"apology", "I'm sorry, but I cannot provide instructions for that.", true},
+		{"curly apostrophe", "I’m sorry, but I can’t assist with this.", true},
+		{"leading whitespace", "\n  I'm unable to help with that.", true},
+		{"answer with caveat", "I can't be certain, but the capital is most likely Canberra.", false},
+		{"answer", "The capital of Australia is Canberra.", false},
+		{"empty", "", false},
+		{"long answer", "I can't help with every detail, but " + strings.Repeat("here is a long answer. ", 40), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, DetectRefusal(tt.text))
+		})
+	}
+}
+
+func TestTextResponseIsRefusal(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, (&TextResponse{Refusal: "No."}).IsRefusal(), "refusal field")
+	assert.True(t, (&TextResponse{RawFinishReason: "refusal"}).IsRefusal(), "refusal stop reason")
+	assert.True(t, (&TextResponse{Text: "I cannot help with that."}).IsRefusal(), "heuristic")
+	assert.False(t, (&TextResponse{
+		Text:      "I cannot help with that.",
+		ToolCalls: []ToolCall{{ID: "call-1", Name: "escalate"}},
+	}).IsRefusal(), "tool calls are not a refusal")
+	assert.False(t, (&TextResponse{Text: "Sure, here it is."}).IsRefusal())
+
+	var nilResponse *TextResponse
+	assert.False(t, nilResponse.IsRefusal())
+}
+
+func TestTextResponseRefusalError(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&TextResponse{Text: "Sure."}).RefusalError())
+
+	err := (&TextResponse{Provider: "anthropic", Model: "claude-sonnet-4-5", Text: "I can't help with that.", RawFinishReason: "refusal"}).RefusalError()
+	require.Error(t, err)
+	assert.True(t, IsRefusalError(err))
+	assert.False(t, IsRetryableError(err))
+	assert.Equal(t, ErrorClassConfig, ClassifyError(err))
+
+	wormholeErr, ok := AsWormholeError(err)
+	require.True(t, ok)
+	assert.Equal(t, "anthropic", wormholeErr.Provider)
+	assert.Equal(t, "claude-sonnet-4-5", wormholeErr.Model)
+	assert.Equal(t, "I can't help with that.", wormholeErr.Details)
+	assert.Equal(t, "model refused the request", safeErrorMessage(wormholeErr.Code))
+}
@@ -13,6 +13,44 @@ type BaseRequest struct {
 	ParallelToolCalls *bool          `json:"parallel_tool_calls,omitempty"`
 	ProviderOptions   map[string]any `json:"-"`
 	Reasoning         *Reasoning     `json:"reasoning,omitempty"`
+	// TopK, MinP, RepetitionPenalty and LogitBias are extended sampling
+	// controls that only some providers accept; see SamplingParam.
+	TopK              *int        `json:"top_k,omitempty"`
+	MinP              *float32    `json:"min_p,omitempty"`
+	RepetitionPenalty *float32    `json:"repetition_penalty,omitempty"`
+	LogitBias         map[int]int `json:"logit_bias,omitempty"`
+	// MaxTokensParam overrides the wire name of MaxTokens on OpenAI-compatible
+	// providers. Model parameter constraints set it; see ParamConstraints.
+	MaxTokensParam string `json:"-"`
+}
+
+// SamplingParam names an extended sampling control. Providers reject a
+// request that sets one they do not support instead of dropping it.
+type SamplingParam string
+
+const (
+	SamplingTopK              SamplingParam = "top_k"
+	SamplingMinP              SamplingParam = "min_p"
+	SamplingRepetitionPenalty SamplingParam = "repetition_penalty"
+	SamplingLogitBias         SamplingParam = "logit_bias"
+)
+
+// SamplingParams returns the extended sampling controls the request sets.
+func (b BaseRequest) SamplingParams() []SamplingParam {
+	var params []SamplingParam
+	if b.TopK != nil {
+		params = append(params, SamplingTopK)
+	}
+	if b.MinP != nil {
+		params = append(params, SamplingMinP)
+	}
+	if b.RepetitionPenalty != nil {
+		params = append(params, SamplingRepetitionPenalty)
+	}
+	if len(b.LogitBias) > 0 {
+		params = append(params, SamplingLogitBias)
+	}
+	return params
 }
 
 // GetProviderOptions returns the provider-specific options. It exists so cache
@@ -48,6 +86,18 @@ type TextRequest struct {
 	Tools          []Tool      `json:"tools,omitempty"`
 	ToolChoice     *ToolChoice `json:"tool_choice,omitempty"`
 	ResponseFormat any         `json:"response_format,omitempty"`
+	// Logprobs asks for the log probability of each output token, and
+	// TopLogprobs for that many likely alternatives per token (0-20).
+	Logprobs    bool `json:"logprobs,omitempty"`
+	TopLogprobs int  `json:"top_logprobs,omitempty"`
+	// WebSearch enables the provider's built-in web search tool.
+	WebSearch *WebSearch `json:"web_search,omitempty"`
+	// Audio asks the model to speak its reply as well as write it.
+	Audio *AudioOutput `json:"audio,omitempty"`
+	// N asks for that many independent completions in one call; they come
+	// back in TextResponse.Choices. Zero and one both mean a single
+	// completion.
+	N int `json:"n,omitempty"`
 }
 
 // StructuredRequest represents a structured output request
@@ -110,12 +160,17 @@ type ImagesRequest struct {
 
 // SpeechToTextRequest represents a speech-to-text request
 type SpeechToTextRequest struct {
-	Model       string   `json:"model"`
-	Audio       []byte   `json:"-"`
-	AudioFormat string   `json:"audio_format"`
-	Language    string   `json:"language,omitempty"`
-	Prompt      string   `json:"prompt,omitempty"`
-	Temperature *float32 `json:"temperature,omitempty"`
+	Model                  string                 `json:"model"`
+	Audio                  []byte                 `json:"-"`
+	AudioFormat            string                 `json:"audio_format"`
+	Language               string                 `json:"language,omitempty"`
+	Prompt                 string                 `json:"prompt,omitempty"`
+	Temperature            *float32               `json:"temperature,omitempty"`
+	ResponseFormat         string                 `json:"response_format,omitempty"`
+	TimestampGranularities []TimestampGranularity `json:"timestamp_granularities,omitempty"`
+	// Translate produces English text whatever the spoken language.
+	Translate       bool           `json:"translate,omitempty"`
+	ProviderOptions map[string]any `json:"-"`
 }
 
 // AudioRequestType represents the type of audio request
@@ -124,6 +179,8 @@ type AudioRequestType string
 const (
 	AudioRequestTypeTTS AudioRequestType = "tts"
 	AudioRequestTypeSTT AudioRequestType = "stt"
+	// AudioRequestTypeTranslation transcribes speech into English text.
+	AudioRequestTypeTranslation AudioRequestType = "translation"
 )
 
 // TextToSpeechRequest represents a text-to-speech request
@@ -141,14 +198,18 @@ type ImageRequest = ImagesRequest
 
 // AudioRequest represents a unified audio request
 type AudioRequest struct {
-	Type            AudioRequestType `json:"type"`
-	Model           string           `json:"model"`
-	Input           any              `json:"input,omitempty"`    // string for TTS, []byte for STT
-	Voice           string           `json:"voice,omitempty"`    // TTS only
-	Speed           float32          `json:"speed,omitempty"`    // TTS only
-	Language        string           `json:"language,omitempty"` // STT only
-	Prompt          string           `json:"prompt,omitempty"`   // STT only
-	Temperature     *float32         `json:"temperature,omitempty"`
-	ResponseFormat  string           `json:"response_format,omitempty"`
-	ProviderOptions map[string]any   `json:"-"`
+	Type           AudioRequestType `json:"type"`
+	Model          string           `json:"model"`
+	Input          any              `json:"input,omitempty"`        // string for TTS, []byte for STT
+	AudioFormat    string           `json:"audio_format,omitempty"` // STT only: encoding of Input, e.g. "mp3"
+	Voice          string           `json:"voice,omitempty"`        // TTS only
+	Speed          float32          `json:"speed,omitempty"`        // TTS only
+	Language       string           `json:"language,omitempty"`     // STT only
+	Prompt         string           `json:"prompt,omitempty"`       // STT only
+	Temperature    *float32         `json:"temperature,omitempty"`
+	ResponseFormat string           `json:"response_format,omitempty"`
+	// TimestampGranularities asks a transcription for segment and/or word
+	// timings (STT only).
+	TimestampGranularities []TimestampGranularity `json:"timestamp_granularities,omitempty"`
+	ProviderOptions        map[string]any         `json:"-"`
 }
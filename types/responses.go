@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/garyblankenship/wormhole/v2/internal/pool"
@@ -47,17 +48,44 @@ func (u Usage) IsZero() bool {
 
 // TextResponse represents a text generation response
 type TextResponse struct {
-	ID           string         `json:"id"`
-	Provider     string         `json:"provider,omitempty"`
-	Model        string         `json:"model"`
-	Text         string         `json:"text"`
-	Refusal      string         `json:"refusal,omitempty"`
-	Thinking     *Thinking      `json:"thinking,omitempty"`
-	ToolCalls    []ToolCall     `json:"tool_calls,omitempty"`
-	FinishReason FinishReason   `json:"finish_reason"`
-	Usage        *Usage         `json:"usage,omitempty"`
-	Created      time.Time      `json:"created"`
-	Metadata     map[string]any `json:"metadata,omitempty"`
+	ID           string       `json:"id"`
+	Provider     string       `json:"provider,omitempty"`
+	Model        string       `json:"model"`
+	Text         string       `json:"text"`
+	Refusal      string       `json:"refusal,omitempty"`
+	Thinking     *Thinking    `json:"thinking,omitempty"`
+	ToolCalls    []ToolCall   `json:"tool_calls,omitempty"`
+	FinishReason FinishReason `json:"finish_reason"`
+	// RawFinishReason is the provider's own finish reason, before
+	// NormalizeFinishReason collapsed it into FinishReason.
+	RawFinishReason string `json:"raw_finish_reason,omitempty"`
+	Usage           *Usage `json:"usage,omitempty"`
+	// Logprobs holds per-token log probabilities when the request set
+	// Logprobs and the provider returns them.
+	Logprobs []TokenLogprob `json:"logprobs,omitempty"`
+	// Citations lists the sources the provider attributed the response to,
+	// such as web search results.
+	Citations []Citation `json:"citations,omitempty"`
+	// Audio is the spoken reply when the request set Audio.
+	Audio *ResponseAudio `json:"audio,omitempty"`
+	// Choices holds every completion, in index order, when the request set N
+	// above 1. Text, ToolCalls and the other top-level fields repeat
+	// Choices[0]; Usage covers all of them.
+	Choices  []Choice       `json:"choices,omitempty"`
+	Created  time.Time      `json:"created"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// Choice is one of several completions sampled for the same request.
+type Choice struct {
+	Index           int            `json:"index"`
+	Text            string         `json:"text"`
+	Refusal         string         `json:"refusal,omitempty"`
+	Thinking        *Thinking      `json:"thinking,omitempty"`
+	ToolCalls       []ToolCall     `json:"tool_calls,omitempty"`
+	FinishReason    FinishReason   `json:"finish_reason"`
+	RawFinishReason string         `json:"raw_finish_reason,omitempty"`
+	Logprobs        []TokenLogprob `json:"logprobs,omitempty"`
 }
 
 // Content returns the text content of the response.
@@ -72,6 +100,12 @@ func (r *TextResponse) HasToolCalls() bool {
 	return len(r.ToolCalls) > 0
 }
 
+// IsEmpty returns true if the response has no usable output: its text is
+// empty or whitespace-only and it carries no tool calls, refusal, or audio.
+func (r *TextResponse) IsEmpty() bool {
+	return strings.TrimSpace(r.Text) == "" && len(r.ToolCalls) == 0 && !r.IsRefusal() && r.Audio == nil
+}
+
 // IsComplete returns true if generation finished normally (not truncated).
 func (r *TextResponse) IsComplete() bool {
 	return r.FinishReason == FinishReasonStop
@@ -82,6 +116,19 @@ func (r *TextResponse) WasTruncated() bool {
 	return r.FinishReason == FinishReasonLength
 }
 
+// Texts returns the text of every choice, or just Text when the response
+// carries a single completion.
+func (r *TextResponse) Texts() []string {
+	if len(r.Choices) == 0 {
+		return []string{r.Text}
+	}
+	texts := make([]string, len(r.Choices))
+	for i, choice := range r.Choices {
+		texts[i] = choice.Text
+	}
+	return texts
+}
+
 // StructuredResponse represents a structured output response
 type StructuredResponse struct {
 	ID       string         `json:"id"`
@@ -100,6 +147,12 @@ func (r *StructuredResponse) Content() any {
 	return r.Data
 }
 
+// IsEmpty returns true if the response has neither parsed data nor raw
+// output beyond whitespace.
+func (r *StructuredResponse) IsEmpty() bool {
+	return r.Data == nil && strings.TrimSpace(r.Raw) == ""
+}
+
 // ContentAs unmarshals the response data into the provided target.
 // This is a convenience method equivalent to json.Unmarshal(json.Marshal(r.Data), target).
 //
@@ -124,6 +177,65 @@ func (r *StructuredResponse) ContentAs(target any) error {
 	return json.Unmarshal(jsonBytes, target)
 }
 
+// StructuredChunk is one progressive update of a streamed structured response.
+// Partial holds the best-effort decode of the JSON generated so far; the final
+// chunk has Done and Complete set and Partial holds the fully decoded document.
+//
+// When the stream fails or disconnects midway, the last chunk carries the
+// error together with the fields parsed before the failure in Partial, and
+// Complete is false.
+type StructuredChunk struct {
+	Partial  any    `json:"partial,omitempty"`
+	Raw      string `json:"raw,omitempty"`
+	Done     bool   `json:"done,omitempty"`
+	Complete bool   `json:"complete,omitempty"`
+	Usage    *Usage `json:"usage,omitempty"`
+	Error    error  `json:"-"`
+}
+
+// HasError returns true if the chunk contains an error.
+func (c *StructuredChunk) HasError() bool {
+	return c.Error != nil
+}
+
+// ContentAs unmarshals the partial document into the provided target.
+// Fields the model has not produced yet keep their zero values.
+func (c *StructuredChunk) ContentAs(target any) error {
+	if c.Partial == nil {
+		return nil
+	}
+	jsonBytes, err := pool.Marshal(c.Partial)
+	if err != nil {
+		return err
+	}
+	defer pool.Return(jsonBytes)
+	return json.Unmarshal(jsonBytes, target)
+}
+
+// StructuredItem is one element of a streamed JSON array. Value holds the
+// element's JSON as soon as it is complete; the final item has Done set, no
+// Value, and carries usage or the error that ended the stream.
+type StructuredItem struct {
+	Index int             `json:"index"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Done  bool            `json:"done,omitempty"`
+	Usage *Usage          `json:"usage,omitempty"`
+	Error error           `json:"-"`
+}
+
+// HasError returns true if the item contains an error.
+func (i *StructuredItem) HasError() bool {
+	return i.Error != nil
+}
+
+// ContentAs unmarshals the element into the provided target.
+func (i *StructuredItem) ContentAs(target any) error {
+	if len(i.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(i.Value, target)
+}
+
 // StreamChunk represents a streaming response chunk (alias for TextChunk)
 type StreamChunk = TextChunk
 
@@ -139,8 +251,11 @@ type TextChunk struct {
 	ToolCall     *ToolCall     `json:"tool_call,omitempty"`
 	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"` // For multi-tool calls
 	FinishReason *FinishReason `json:"finish_reason,omitempty"`
-	Usage        *Usage        `json:"usage,omitempty"`
-	Error        error         `json:"-"`
+	// RawFinishReason is the provider's own finish reason on the final chunk.
+	RawFinishReason string         `json:"raw_finish_reason,omitempty"`
+	Usage           *Usage         `json:"usage,omitempty"`
+	Logprobs        []TokenLogprob `json:"logprobs,omitempty"` // For the tokens in Text
+	Error           error          `json:"-"`
 }
 
 // Content returns the text content of the chunk.
@@ -0,0 +1,62 @@
+package types
+
+// RoleMap rewrites the role of instruction and user messages, for models and
+// endpoints that reject a role: {RoleSystem: RoleDeveloper} for OpenAI
+// reasoning models, {RoleSystem: RoleUser} for models with no instruction
+// role at all. Only system, developer and user roles can be mapped, and only
+// to one another; user messages carrying media keep their role.
+type RoleMap map[Role]Role
+
+// Apply returns messages with every mapped role rewritten, and whether any
+// message changed. messages itself is not modified.
+func (m RoleMap) Apply(messages []Message) ([]Message, bool) {
+	if len(m) == 0 {
+		return messages, false
+	}
+	var out []Message
+	for i, msg := range messages {
+		to, ok := m[msg.GetRole()]
+		if !ok || to == msg.GetRole() {
+			continue
+		}
+		remapped, ok := remapMessage(msg, to)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = append([]Message(nil), messages...)
+		}
+		out[i] = remapped
+	}
+	if out == nil {
+		return messages, false
+	}
+	return out, true
+}
+
+// remapMessage rebuilds a text-only instruction or user message under role.
+func remapMessage(msg Message, role Role) (Message, bool) {
+	var content string
+	switch m := msg.(type) {
+	case *SystemMessage:
+		content = m.Content
+	case *DeveloperMessage:
+		content = m.Content
+	case *UserMessage:
+		if len(m.Media) > 0 {
+			return nil, false
+		}
+		content = m.Content
+	default:
+		return nil, false
+	}
+	switch role {
+	case RoleSystem:
+		return NewSystemMessage(content), true
+	case RoleDeveloper:
+		return NewDeveloperMessage(content), true
+	case RoleUser:
+		return NewUserMessage(content), true
+	}
+	return nil, false
+}
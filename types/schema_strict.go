@@ -0,0 +1,149 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// StrictSchema returns a copy of schema that satisfies the rules of strict
+// JSON schema decoding (OpenAI structured outputs), plus one warning per
+// property whose meaning it had to change. The input is never modified.
+//
+// Rewrites, applied at every level of the schema:
+//   - every object gets additionalProperties:false
+//   - every property of an object is listed in required
+//   - a property that was optional becomes nullable, unless it already
+//     accepts null, so the model can still leave it out by answering null
+func StrictSchema(schema map[string]any) (map[string]any, []string) {
+	if schema == nil {
+		return nil, nil
+	}
+	out := CloneMap(schema)
+	s := &schemaStrictener{}
+	s.rewrite(out, "#")
+	return out, s.warnings
+}
+
+type schemaStrictener struct {
+	warnings []string
+}
+
+func (s *schemaStrictener) rewrite(m map[string]any, path string) {
+	props, hasProps := m["properties"].(map[string]any)
+	if m["type"] == "object" || hasProps {
+		switch extra := m["additionalProperties"].(type) {
+		case nil:
+		case bool:
+			if extra {
+				s.warn(path, "additionalProperties:true replaced with false")
+			}
+		default:
+			s.warn(path, "additionalProperties schema replaced with false")
+		}
+		m["additionalProperties"] = false
+	}
+
+	if hasProps {
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		wasRequired := mergeRequired(m["required"], nil)
+		for _, name := range names {
+			sub, ok := props[name].(map[string]any)
+			if !ok {
+				continue
+			}
+			s.rewrite(sub, path+"/properties/"+name)
+			if !slices.Contains(wasRequired, any(name)) && !acceptsNull(sub) {
+				props[name] = nullableSchema(sub)
+				s.warn(path+"/properties/"+name, "optional property made required and nullable")
+			}
+		}
+		required := make([]any, len(names))
+		for i, name := range names {
+			required[i] = name
+		}
+		m["required"] = required
+	}
+
+	switch items := m["items"].(type) {
+	case map[string]any:
+		s.rewrite(items, path+"/items")
+	case []any:
+		s.rewriteList(items, path+"/items")
+	}
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		if branches, ok := m[key].([]any); ok {
+			s.rewriteList(branches, path+"/"+key)
+		}
+	}
+	for _, key := range []string{"$defs", "definitions"} {
+		if defs, ok := m[key].(map[string]any); ok {
+			for name, def := range defs {
+				if sub, ok := def.(map[string]any); ok {
+					s.rewrite(sub, path+"/"+key+"/"+name)
+				}
+			}
+		}
+	}
+}
+
+func (s *schemaStrictener) rewriteList(schemas []any, path string) {
+	for i, schema := range schemas {
+		if sub, ok := schema.(map[string]any); ok {
+			s.rewrite(sub, fmt.Sprintf("%s/%d", path, i))
+		}
+	}
+}
+
+func (s *schemaStrictener) warn(path, message string) {
+	s.warnings = append(s.warnings, path+": "+message)
+}
+
+// acceptsNull reports whether schema already matches null, so an optional
+// property using it can become required without changing its meaning.
+func acceptsNull(schema map[string]any) bool {
+	switch t := schema["type"].(type) {
+	case string:
+		return t == "null"
+	case []any:
+		return slices.Contains(t, any("null"))
+	}
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		return true
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		branches, _ := schema[key].([]any)
+		for _, branch := range branches {
+			if sub, ok := branch.(map[string]any); ok && acceptsNull(sub) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nullableSchema lets schema also match null: "null" joins its type (and its
+// enum) when it has one, and otherwise the schema becomes one branch of an
+// anyOf.
+func nullableSchema(schema map[string]any) map[string]any {
+	switch t := schema["type"].(type) {
+	case string:
+		if t != "null" {
+			schema["type"] = []any{t, "null"}
+		}
+	case []any:
+		if !slices.Contains(t, any("null")) {
+			schema["type"] = append(slices.Clone(t), "null")
+		}
+	default:
+		return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, nil) {
+		schema["enum"] = append(slices.Clone(enum), nil)
+	}
+	return schema
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":     map[string]any{"type": "string"},
+			"nickname": map[string]any{"type": "string"},
+			"mood":     map[string]any{"type": "string", "enum": []any{"happy", "sad"}},
+			"tags": map[string]any{"type": "array", "items": map[string]any{
+				"type":                 "object",
+				"properties":           map[string]any{"label": map[string]any{"type": "string"}},
+				"additionalProperties": true,
+			}},
+			"pet":  map[string]any{"$ref": "#/$defs/pet"},
+			"note": map[string]any{"anyOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "null"}}},
+		},
+		"required": []any{"name", "tags"},
+		"$defs": map[string]any{
+			"pet": map[string]any{"type": "object", "properties": map[string]any{"kind": map[string]any{"type": "string"}}},
+		},
+	}
+
+	strict, warnings := StrictSchema(schema)
+	require.NotNil(t, strict)
+	assert.NotContains(t, schema, "additionalProperties", "input is not modified")
+
+	assert.Equal(t, false, strict["additionalProperties"])
+	assert.Equal(t, []any{"mood", "name", "nickname", "note", "pet", "tags"}, strict["required"])
+
+	props := strict["properties"].(map[string]any)
+	assert.Equal(t, "string", props["name"].(map[string]any)["type"])
+	assert.Equal(t, []any{"string", "null"}, props["nickname"].(map[string]any)["type"])
+	assert.Equal(t, []any{"happy", "sad", nil}, props["mood"].(map[string]any)["enum"])
+	assert.Equal(t, map[string]any{"anyOf": []any{
+		map[string]any{"$ref": "#/$defs/pet"},
+		map[string]any{"type": "null"},
+	}}, props["pet"])
+
+	item := props["tags"].(map[string]any)["items"].(map[string]any)
+	assert.Equal(t, false, item["additionalProperties"])
+	assert.Equal(t, []any{"label"}, item["required"])
+
+	pet := strict["$defs"].(map[string]any)["pet"].(map[string]any)
+	assert.Equal(t, false, pet["additionalProperties"])
+	assert.Equal(t, []any{"kind"}, pet["required"])
+
+	assert.Contains(t, warnings, "#/properties/nickname: optional property made required and nullable")
+	assert.Contains(t, warnings, "#/properties/tags/items: additionalProperties:true replaced with false")
+	assert.NotContains(t, warnings, "#/properties/name: optional property made required and nullable")
+	assert.NotContains(t, warnings, "#/properties/note: optional property made required and nullable", "note already accepts null")
+
+	again, warnings := StrictSchema(strict)
+	assert.Equal(t, strict, again)
+	assert.Empty(t, warnings, "a strict schema needs no changes")
+}
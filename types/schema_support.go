@@ -0,0 +1,301 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SchemaSupport describes which JSON Schema constructs a provider accepts in
+// response schemas and tool parameters. DowngradeSchema rewrites everything a
+// profile does not allow. The zero value allows only plain type, properties,
+// items, required, enum and description-style keywords.
+type SchemaSupport struct {
+	AnyOf                bool `json:"any_of,omitempty"`
+	OneOf                bool `json:"one_of,omitempty"`
+	AllOf                bool `json:"all_of,omitempty"`
+	Refs                 bool `json:"refs,omitempty"` // $ref with $defs/definitions
+	Const                bool `json:"const,omitempty"`
+	AdditionalProperties bool `json:"additional_properties,omitempty"`
+
+	// Formats lists the accepted "format" values; nil accepts any format.
+	Formats []string `json:"formats,omitempty"`
+	// UnsupportedKeywords are removed wherever they appear.
+	UnsupportedKeywords []string `json:"unsupported_keywords,omitempty"`
+}
+
+// DowngradeSchema returns a copy of schema rewritten to fit support, plus one
+// warning per construct it had to change. The input is never modified.
+//
+// Rewrites, applied at every level of the schema:
+//   - $ref is inlined from $defs/definitions; recursive refs become an
+//     unconstrained object
+//   - allOf branches are merged into the parent
+//   - oneOf becomes anyOf when anyOf is supported
+//   - anyOf/oneOf collapse to the first non-null branch, with nullable:true
+//     when a null branch was present
+//   - const becomes a single-value enum
+//   - unsupported formats move into the description
+//   - additionalProperties and UnsupportedKeywords are dropped
+func DowngradeSchema(schema map[string]any, support SchemaSupport) (map[string]any, []string) {
+	if schema == nil {
+		return nil, nil
+	}
+	out := CloneMap(schema)
+	d := &schemaDowngrader{support: support, root: out, resolving: map[string]bool{}}
+	d.rewrite(out, "#")
+	if !support.Refs {
+		delete(out, "$defs")
+		delete(out, "definitions")
+	}
+	return out, d.warnings
+}
+
+type schemaDowngrader struct {
+	support   SchemaSupport
+	root      map[string]any
+	resolving map[string]bool
+	warnings  []string
+}
+
+func (d *schemaDowngrader) warn(path, format string, args ...any) {
+	d.warnings = append(d.warnings, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (d *schemaDowngrader) rewrite(m map[string]any, path string) {
+	if ref, ok := m["$ref"].(string); ok && !d.support.Refs {
+		if d.resolving[ref] {
+			d.warn(path, "recursive $ref %q replaced with an unconstrained object", ref)
+			clear(m)
+			m["type"] = "object"
+			return
+		}
+		if d.inlineRef(m, ref, path) {
+			d.resolving[ref] = true
+			defer delete(d.resolving, ref)
+		}
+	}
+
+	d.rewriteChildren(m, path)
+
+	if branches, ok := m["allOf"].([]any); ok && !d.support.AllOf {
+		delete(m, "allOf")
+		for _, branch := range branches {
+			if sub, ok := branch.(map[string]any); ok {
+				mergeSchemaInto(m, sub)
+			}
+		}
+		d.warn(path, "allOf merged into the parent schema")
+	}
+
+	if branches, ok := m["oneOf"].([]any); ok && !d.support.OneOf {
+		delete(m, "oneOf")
+		if d.support.AnyOf {
+			m["anyOf"] = branches
+			d.warn(path, "oneOf rewritten as anyOf; exclusivity is not enforced")
+		} else {
+			d.collapseUnion(m, branches, "oneOf", path)
+		}
+	}
+	if branches, ok := m["anyOf"].([]any); ok && !d.support.AnyOf {
+		delete(m, "anyOf")
+		d.collapseUnion(m, branches, "anyOf", path)
+	}
+
+	if value, ok := m["const"]; ok && !d.support.Const {
+		delete(m, "const")
+		m["enum"] = []any{value}
+		d.warn(path, "const rewritten as a single-value enum")
+	}
+
+	if format, ok := m["format"].(string); ok && d.support.Formats != nil && !slices.Contains(d.support.Formats, format) {
+		delete(m, "format")
+		appendSchemaDescription(m, "format: "+format)
+		d.warn(path, "unsupported format %q moved into the description", format)
+	}
+
+	if _, ok := m["additionalProperties"]; ok && !d.support.AdditionalProperties {
+		delete(m, "additionalProperties")
+		d.warn(path, "additionalProperties dropped")
+	}
+
+	for _, keyword := range d.support.UnsupportedKeywords {
+		if _, ok := m[keyword]; ok {
+			delete(m, keyword)
+			d.warn(path, "unsupported keyword %q dropped", keyword)
+		}
+	}
+}
+
+// inlineRef replaces m's $ref with the referenced definition. Keywords set
+// next to the $ref win over the definition's. It reports whether the
+// definition was found.
+func (d *schemaDowngrader) inlineRef(m map[string]any, ref, path string) bool {
+	var def map[string]any
+	for _, prefix := range []string{"#/$defs/", "#/definitions/"} {
+		if name, ok := strings.CutPrefix(ref, prefix); ok {
+			defs, _ := d.root[strings.TrimSuffix(prefix[2:], "/")].(map[string]any)
+			def, _ = defs[name].(map[string]any)
+		}
+	}
+	delete(m, "$ref")
+	if def == nil {
+		d.warn(path, "unresolvable $ref %q replaced with an unconstrained object", ref)
+		if _, ok := m["type"]; !ok {
+			m["type"] = "object"
+		}
+		return false
+	}
+	for k, v := range CloneMap(def) {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+	d.warn(path, "$ref %q inlined", ref)
+	return true
+}
+
+func (d *schemaDowngrader) rewriteChildren(m map[string]any, path string) {
+	if props, ok := m["properties"].(map[string]any); ok {
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if sub, ok := props[name].(map[string]any); ok {
+				d.rewrite(sub, path+"/properties/"+name)
+			}
+		}
+	}
+	switch items := m["items"].(type) {
+	case map[string]any:
+		d.rewrite(items, path+"/items")
+	case []any:
+		d.rewriteList(items, path+"/items")
+	}
+	if sub, ok := m["additionalProperties"].(map[string]any); ok {
+		d.rewrite(sub, path+"/additionalProperties")
+	}
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		if branches, ok := m[key].([]any); ok {
+			d.rewriteList(branches, path+"/"+key)
+		}
+	}
+	if d.support.Refs {
+		for _, key := range []string{"$defs", "definitions"} {
+			if defs, ok := m[key].(map[string]any); ok {
+				for name, def := range defs {
+					if sub, ok := def.(map[string]any); ok {
+						d.rewrite(sub, path+"/"+key+"/"+name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (d *schemaDowngrader) rewriteList(schemas []any, path string) {
+	for i, schema := range schemas {
+		if sub, ok := schema.(map[string]any); ok {
+			d.rewrite(sub, fmt.Sprintf("%s/%d", path, i))
+		}
+	}
+}
+
+// collapseUnion keeps the first non-null branch of a union. A {"type":"null"}
+// branch becomes nullable:true.
+func (d *schemaDowngrader) collapseUnion(m map[string]any, branches []any, keyword, path string) {
+	var chosen map[string]any
+	nullable := false
+	for _, branch := range branches {
+		sub, ok := branch.(map[string]any)
+		if !ok {
+			continue
+		}
+		if sub["type"] == "null" {
+			nullable = true
+			continue
+		}
+		if chosen == nil {
+			chosen = sub
+		}
+	}
+	if chosen != nil {
+		mergeSchemaInto(m, chosen)
+	}
+	if nullable {
+		m["nullable"] = true
+	}
+	nonNull := len(branches)
+	if nullable {
+		nonNull--
+	}
+	if nonNull > 1 {
+		d.warn(path, "%s reduced to its first branch", keyword)
+	} else {
+		d.warn(path, "%s rewritten as a plain schema", keyword)
+	}
+}
+
+// mergeSchemaInto copies src's keywords into dst without overriding dst,
+// combining properties and required lists.
+func mergeSchemaInto(dst, src map[string]any) {
+	for k, v := range src {
+		switch k {
+		case "properties":
+			props, _ := dst["properties"].(map[string]any)
+			if props == nil {
+				props = map[string]any{}
+				dst["properties"] = props
+			}
+			if srcProps, ok := v.(map[string]any); ok {
+				for name, prop := range srcProps {
+					if _, exists := props[name]; !exists {
+						props[name] = prop
+					}
+				}
+			}
+		case "required":
+			dst["required"] = mergeRequired(dst["required"], v)
+		default:
+			if _, exists := dst[k]; !exists {
+				dst[k] = v
+			}
+		}
+	}
+}
+
+func mergeRequired(a, b any) []any {
+	var merged []any
+	seen := map[string]bool{}
+	for _, list := range []any{a, b} {
+		var names []string
+		switch l := list.(type) {
+		case []string:
+			names = l
+		case []any:
+			for _, v := range l {
+				if s, ok := v.(string); ok {
+					names = append(names, s)
+				}
+			}
+		}
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				merged = append(merged, name)
+			}
+		}
+	}
+	return merged
+}
+
+func appendSchemaDescription(m map[string]any, note string) {
+	if desc, ok := m["description"].(string); ok && desc != "" {
+		m["description"] = desc + " (" + note + ")"
+		return
+	}
+	m["description"] = note
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDowngradeSchemaRewritesUnsupportedConstructs(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type": "object",
+		"$defs": map[string]any{
+			"address": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"city": map[string]any{"type": "string"}},
+			},
+		},
+		"properties": map[string]any{
+			"home":  map[string]any{"$ref": "#/$defs/address", "description": "Home address"},
+			"email": map[string]any{"type": "string", "format": "email", "description": "Contact"},
+			"kind":  map[string]any{"const": "person"},
+			"age": map[string]any{"anyOf": []any{
+				map[string]any{"type": "integer"},
+				map[string]any{"type": "null"},
+			}},
+			"id": map[string]any{"oneOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "integer"},
+			}},
+		},
+		"allOf": []any{
+			map[string]any{"required": []any{"email"}},
+			map[string]any{"required": []any{"kind"}},
+		},
+		"additionalProperties": false,
+	}
+
+	out, warnings := DowngradeSchema(schema, SchemaSupport{Formats: []string{"date-time"}})
+
+	props := out["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type":        "object",
+		"description": "Home address",
+		"properties":  map[string]any{"city": map[string]any{"type": "string"}},
+	}, props["home"])
+	assert.Equal(t, map[string]any{"type": "string", "description": "Contact (format: email)"}, props["email"])
+	assert.Equal(t, map[string]any{"enum": []any{"person"}}, props["kind"])
+	assert.Equal(t, map[string]any{"type": "integer", "nullable": true}, props["age"])
+	assert.Equal(t, map[string]any{"type": "string"}, props["id"])
+	assert.Equal(t, []any{"email", "kind"}, out["required"])
+	assert.NotContains(t, out, "allOf")
+	assert.NotContains(t, out, "$defs")
+	assert.NotContains(t, out, "additionalProperties")
+
+	assert.Contains(t, warnings, "#/properties/id: oneOf reduced to its first branch")
+	assert.Contains(t, warnings, `#/properties/email: unsupported format "email" moved into the description`)
+	assert.Contains(t, warnings, "#: allOf merged into the parent schema")
+	assert.Len(t, warnings, 7)
+
+	// The caller's schema is untouched.
+	assert.Contains(t, schema, "$defs")
+	assert.Equal(t, "#/$defs/address", schema["properties"].(map[string]any)["home"].(map[string]any)["$ref"])
+}
+
+func TestDowngradeSchemaKeepsSupportedConstructs(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"oneOf":  []any{map[string]any{"type": "string"}, map[string]any{"type": "number"}},
+		"format": "uuid",
+	}
+	out, warnings := DowngradeSchema(schema, SchemaSupport{AnyOf: true})
+	assert.Equal(t, map[string]any{
+		"anyOf":  []any{map[string]any{"type": "string"}, map[string]any{"type": "number"}},
+		"format": "uuid",
+	}, out)
+	assert.Equal(t, []string{"#: oneOf rewritten as anyOf; exclusivity is not enforced"}, warnings)
+
+	out, warnings = DowngradeSchema(schema, SchemaSupport{OneOf: true})
+	assert.Equal(t, schema, out)
+	assert.Empty(t, warnings)
+}
+
+func TestDowngradeSchemaRecursiveRef(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"$ref": "#/definitions/node",
+		"definitions": map[string]any{
+			"node": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"children": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/definitions/node"}},
+				},
+			},
+		},
+	}
+	out, warnings := DowngradeSchema(schema, SchemaSupport{})
+	children := out["properties"].(map[string]any)["children"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "object"}, children["items"])
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[1], "recursive $ref")
+}
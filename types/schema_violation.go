@@ -0,0 +1,78 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSchemaMismatch matches a *SchemaValidationError with errors.Is.
+var ErrSchemaMismatch = errors.New("structured response does not match its schema")
+
+// SchemaViolation is one place where a value breaks its JSON schema.
+type SchemaViolation struct {
+	// Path is a JSON Pointer to the offending value; "" is the document root.
+	Path string `json:"path"`
+	// Keyword is the schema keyword that failed, such as "required" or "type".
+	Keyword string `json:"keyword"`
+	Message string `json:"message"`
+}
+
+// String renders the violation as "path: message", with "/" for the root.
+func (v SchemaViolation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + ": " + v.Message
+}
+
+// SchemaValidationError reports a structured response that parsed as JSON but
+// does not match the request schema. It matches ErrSchemaMismatch with
+// errors.Is.
+type SchemaValidationError struct {
+	Violations []SchemaViolation `json:"violations"`
+	// Data is the decoded response that failed.
+	Data any `json:"-"`
+	// Attempts counts the responses checked, including repair attempts.
+	Attempts int `json:"attempts"`
+}
+
+func (e *SchemaValidationError) Error() string {
+	msg := ErrSchemaMismatch.Error()
+	switch n := len(e.Violations); n {
+	case 0:
+		return msg
+	case 1:
+		return msg + ": " + e.Violations[0].String()
+	default:
+		return fmt.Sprintf("%s: %s (and %d more)", msg, e.Violations[0], n-1)
+	}
+}
+
+// Is reports whether target is ErrSchemaMismatch.
+func (e *SchemaValidationError) Is(target error) bool {
+	return target == ErrSchemaMismatch
+}
+
+// Paths returns the path of every violation, in order, without duplicates.
+func (e *SchemaValidationError) Paths() []string {
+	var paths []string
+	seen := map[string]bool{}
+	for _, v := range e.Violations {
+		if !seen[v.Path] {
+			seen[v.Path] = true
+			paths = append(paths, v.Path)
+		}
+	}
+	return paths
+}
+
+// Summary lists every violation, one per line.
+func (e *SchemaValidationError) Summary() string {
+	lines := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		lines[i] = "- " + v.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,18 @@
+package types
+
+import "context"
+
+// TokenCount is a provider's own count of the input tokens a request would
+// use.
+type TokenCount struct {
+	Provider    string `json:"provider,omitempty"`
+	Model       string `json:"model,omitempty"`
+	InputTokens int    `json:"input_tokens"`
+}
+
+// TokenCounter is implemented by providers that count a request's input
+// tokens server-side, such as Anthropic's count_tokens endpoint. Providers
+// that implement it advertise CapabilityTokenCounting.
+type TokenCounter interface {
+	CountTokens(ctx context.Context, request TextRequest) (*TokenCount, error)
+}
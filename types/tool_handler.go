@@ -1,6 +1,9 @@
 package types
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ToolHandler is a function that executes a tool with the given arguments.
 // It receives a context for cancellation and the arguments as a map.
@@ -30,6 +33,23 @@ type ToolDefinition struct {
 
 	// Handler is the function that executes the tool when called by the model
 	Handler ToolHandler
+
+	// Idempotent marks results as reusable: within one tool conversation, a
+	// repeated call with the same arguments is answered from memory instead
+	// of running Handler again. Failed calls are never reused.
+	Idempotent bool
+
+	// ResultTTL bounds how long an idempotent result is reused. Zero reuses
+	// it for the rest of the conversation.
+	ResultTTL time.Duration
+
+	// Timeout overrides the executor's ToolTimeout for this tool. Zero keeps
+	// the executor's; a negative value runs the tool without a timeout.
+	Timeout time.Duration
+
+	// RequiresApproval holds each call until a human approves it. See
+	// wormhole.RequireApproval.
+	RequiresApproval bool
 }
 
 // NewToolDefinition creates a new ToolDefinition with the given tool and handler.
@@ -62,6 +62,25 @@ func (tc *ToolChoice) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// UnmarshalJSON accepts both forms MarshalJSON writes: a bare type string or
+// an object with a tool name.
+func (tc *ToolChoice) UnmarshalJSON(data []byte) error {
+	var choiceType string
+	if err := json.Unmarshal(data, &choiceType); err == nil {
+		*tc = ToolChoice{Type: ToolChoiceType(choiceType)}
+		return nil
+	}
+	var choice struct {
+		Type     ToolChoiceType `json:"type"`
+		ToolName string         `json:"tool_name,omitempty"`
+	}
+	if err := json.Unmarshal(data, &choice); err != nil {
+		return err
+	}
+	*tc = ToolChoice{Type: choice.Type, ToolName: choice.ToolName}
+	return nil
+}
+
 // Tool represents a function that can be called by the model
 type Tool struct {
 	Type         string         `json:"type,omitempty"` // For OpenAI compatibility ("function")
@@ -0,0 +1,42 @@
+package types
+
+import "context"
+
+// TimestampGranularity selects the timings a transcription returns.
+type TimestampGranularity string
+
+const (
+	TimestampGranularitySegment TimestampGranularity = "segment"
+	TimestampGranularityWord    TimestampGranularity = "word"
+)
+
+// TranscriptSegment is a stretch of transcribed speech. Times are seconds
+// from the start of the audio.
+type TranscriptSegment struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+	// AvgLogprob and NoSpeechProb help spot hallucinated text: a low average
+	// log probability or a high no-speech probability marks a doubtful
+	// segment.
+	AvgLogprob       float64 `json:"avg_logprob,omitempty"`
+	CompressionRatio float64 `json:"compression_ratio,omitempty"`
+	NoSpeechProb     float64 `json:"no_speech_prob,omitempty"`
+}
+
+// TranscriptWord is one transcribed word. Times are seconds from the start
+// of the audio.
+type TranscriptWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// TranscriptionStreamer is implemented by providers that stream transcript
+// text as the audio is processed, such as OpenAI's gpt-4o-transcribe models.
+// Each chunk carries a text delta; the final chunk has a finish reason and
+// usage.
+type TranscriptionStreamer interface {
+	StreamTranscription(ctx context.Context, request AudioRequest) (<-chan TextChunk, error)
+}
@@ -0,0 +1,34 @@
+package types
+
+import "slices"
+
+// WebSearch turns on the provider's built-in web search tool: OpenAI's
+// web_search (web_search_options on Chat Completions, which needs a search
+// model such as gpt-4o-search-preview), Anthropic's web search server tool,
+// or Gemini's grounding with Google Search. The provider runs the searches
+// itself; sources come back in TextResponse.Citations as CitationTypeURL
+// entries.
+//
+// Zero fields leave the provider's defaults. Providers without a matching
+// control ignore a field: MaxUses and BlockedDomains apply to Anthropic
+// only, AllowedDomains to Anthropic and the OpenAI Responses API.
+type WebSearch struct {
+	// MaxUses caps the searches the model may run for one response.
+	MaxUses int `json:"max_uses,omitempty"`
+	// AllowedDomains restricts results to these domains.
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+	// BlockedDomains excludes results from these domains.
+	BlockedDomains []string `json:"blocked_domains,omitempty"`
+}
+
+// Clone returns a deep copy of w.
+func (w *WebSearch) Clone() *WebSearch {
+	if w == nil {
+		return nil
+	}
+	return &WebSearch{
+		MaxUses:        w.MaxUses,
+		AllowedDomains: slices.Clone(w.AllowedDomains),
+		BlockedDomains: slices.Clone(w.BlockedDomains),
+	}
+}
@@ -0,0 +1,71 @@
+package types
+
+import "context"
+
+// XAIOptions are xAI Grok request options that have no portable equivalent.
+// Pass them with ProviderOptions:
+//
+//	client.Text().
+//	    Using("xai").
+//	    Model("grok-4").
+//	    ProviderOptions(types.XAIOptions{
+//	        Search: &types.XAISearch{Mode: "auto", ReturnCitations: true},
+//	    }.ProviderOptions()).
+//	    Prompt("What changed in Go 1.25?").
+//	    Generate(ctx)
+//
+// Citations returned by Live Search are in the response metadata under
+// "citations".
+type XAIOptions struct {
+	// Search enables Live Search over web, news, X and RSS sources.
+	Search *XAISearch
+	// Deferred queues the completion instead of waiting for it. The response
+	// carries only the request ID; fetch the result with
+	// Wormhole.DeferredCompletion.
+	Deferred bool
+}
+
+// XAISearch configures Live Search (the search_parameters request field).
+type XAISearch struct {
+	// Mode is "auto" (the model decides), "on" or "off".
+	Mode             string            `json:"mode,omitempty"`
+	Sources          []XAISearchSource `json:"sources,omitempty"`
+	ReturnCitations  bool              `json:"return_citations,omitempty"`
+	MaxSearchResults int               `json:"max_search_results,omitempty"`
+	// FromDate and ToDate bound results, as YYYY-MM-DD.
+	FromDate string `json:"from_date,omitempty"`
+	ToDate   string `json:"to_date,omitempty"`
+}
+
+// XAISearchSource is one Live Search source. Type is "web", "news", "x" or
+// "rss"; the other fields apply to the types xAI documents them for.
+type XAISearchSource struct {
+	Type             string   `json:"type"`
+	Country          string   `json:"country,omitempty"`
+	AllowedWebsites  []string `json:"allowed_websites,omitempty"`
+	ExcludedWebsites []string `json:"excluded_websites,omitempty"`
+	SafeSearch       *bool    `json:"safe_search,omitempty"`
+	IncludedXHandles []string `json:"included_x_handles,omitempty"`
+	ExcludedXHandles []string `json:"excluded_x_handles,omitempty"`
+	Links            []string `json:"links,omitempty"`
+}
+
+// ProviderOptions returns the options as request fields for ProviderOptions.
+func (o XAIOptions) ProviderOptions() map[string]any {
+	options := make(map[string]any, 2)
+	if o.Search != nil {
+		options["search_parameters"] = o.Search
+	}
+	if o.Deferred {
+		options["deferred"] = true
+	}
+	return options
+}
+
+// DeferredCompleter is implemented by providers that can queue a completion
+// and return its result later, such as xAI with XAIOptions.Deferred.
+type DeferredCompleter interface {
+	// DeferredCompletion fetches the result of a deferred request. It
+	// returns ErrDeferredPending while the completion is still running.
+	DeferredCompletion(ctx context.Context, requestID string) (*TextResponse, error)
+}
@@ -0,0 +1,385 @@
+package wormhole
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/middleware"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// WarmPoolConfig configures the background pinger enabled by WithWarmPool.
+type WarmPoolConfig struct {
+	// Routes are the provider/model pairs to keep warm.
+	Routes []TextRoute
+	// Interval between pings (default 1m). A route that served traffic other
+	// than pings within the interval is not pinged.
+	Interval time.Duration
+	// ColdAfter is how long a route may go without a successful request before
+	// it counts as cold (default 5m).
+	ColdAfter time.Duration
+	// TokenBudget caps the tokens pings may spend per BudgetWindow; zero means
+	// no cap. Pings stop for the rest of the window once it is spent.
+	TokenBudget int
+	// BudgetWindow is the period TokenBudget applies to (default 1h).
+	BudgetWindow time.Duration
+	// Prompt is the ping message (default "ping"). Pings ask for one token.
+	Prompt string
+}
+
+// WarmRouteStats describes one route's warmth and latency. A request counts
+// as a cold start when the route had no successful request, ping or not,
+// within ColdAfter.
+type WarmRouteStats struct {
+	Provider     string
+	Model        string
+	Warm         bool
+	LastUsed     time.Time // last successful request other than a ping
+	LastPing     time.Time // last successful ping
+	Pings        int64
+	PingErrors   int64
+	PingTokens   int64
+	ColdStarts   int64
+	ColdLatency  time.Duration // total over ColdStarts
+	WarmRequests int64
+	WarmLatency  time.Duration // total over WarmRequests
+}
+
+// lastActive returns when the route last served a request, ping or not.
+func (s WarmRouteStats) lastActive() time.Time {
+	if s.LastPing.After(s.LastUsed) {
+		return s.LastPing
+	}
+	return s.LastUsed
+}
+
+// AverageColdLatency returns the mean latency of cold starts.
+func (s WarmRouteStats) AverageColdLatency() time.Duration {
+	if s.ColdStarts == 0 {
+		return 0
+	}
+	return s.ColdLatency / time.Duration(s.ColdStarts)
+}
+
+// AverageWarmLatency returns the mean latency of requests to a warm route.
+func (s WarmRouteStats) AverageWarmLatency() time.Duration {
+	if s.WarmRequests == 0 {
+		return 0
+	}
+	return s.WarmLatency / time.Duration(s.WarmRequests)
+}
+
+// WarmPool keeps selected routes warm with tiny periodic requests, so free
+// and serverless tiers that spin models down between requests do not charge
+// interactive traffic the cold-start latency. It observes every text and
+// structured request the client sends, pings included, to track which routes
+// are warm and how slow cold starts are. Routes are keyed by provider name as
+// reported by the provider. See WithWarmPool and TextRequestBuilder.PreferWarm.
+type WarmPool struct {
+	client *Wormhole
+	config WarmPoolConfig
+
+	mu           sync.Mutex
+	routes       map[ProviderKey]*WarmRouteStats
+	windowStart  time.Time
+	windowTokens int
+
+	done     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newWarmPool(client *Wormhole, config WarmPoolConfig) *WarmPool {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.ColdAfter <= 0 {
+		config.ColdAfter = 5 * time.Minute
+	}
+	if config.BudgetWindow <= 0 {
+		config.BudgetWindow = time.Hour
+	}
+	if config.Prompt == "" {
+		config.Prompt = "ping"
+	}
+	config.Routes = append([]TextRoute(nil), config.Routes...)
+	return &WarmPool{
+		client: client,
+		config: config,
+		routes: make(map[ProviderKey]*WarmRouteStats),
+		done:   make(chan struct{}),
+	}
+}
+
+// start launches the ping loop. It exits on Close or client shutdown.
+func (w *WarmPool) start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+		for {
+			w.pingAll()
+			select {
+			case <-ticker.C:
+			case <-w.done:
+				return
+			case <-w.client.shutdownChan:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the ping loop. It is called by the client's Shutdown.
+func (w *WarmPool) Close() error {
+	w.stopOnce.Do(func() { close(w.done) })
+	w.wg.Wait()
+	return nil
+}
+
+func (w *WarmPool) pingAll() {
+	for _, route := range w.config.Routes {
+		select {
+		case <-w.done:
+			return
+		default:
+		}
+		if w.servedTrafficWithin(route, w.config.Interval) || !w.budgetLeft() {
+			continue
+		}
+		w.ping(route)
+	}
+}
+
+// warmPingKey marks a ping's context, so observe does not count it as
+// traffic.
+type warmPingKey struct{}
+
+func (w *WarmPool) ping(route TextRoute) {
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), warmPingKey{}, true), w.config.Interval)
+	defer cancel()
+	resp, err := w.client.Text().
+		Using(route.Provider).
+		Model(route.Model).
+		Prompt(w.config.Prompt).
+		MaxTokens(1).
+		Metadata(map[string]string{"warm_pool": "ping"}).
+		Generate(ctx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stats := w.route(route.Provider, route.Model)
+	stats.Pings++
+	if err != nil {
+		stats.PingErrors++
+		return
+	}
+	if resp.Usage != nil {
+		stats.PingTokens += int64(resp.Usage.TotalTokens)
+		w.windowTokens += resp.Usage.TotalTokens
+	}
+}
+
+// budgetLeft reports whether pings may still spend tokens in this window.
+func (w *WarmPool) budgetLeft() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.config.TokenBudget <= 0 {
+		return true
+	}
+	if now := time.Now(); now.Sub(w.windowStart) >= w.config.BudgetWindow {
+		w.windowStart = now
+		w.windowTokens = 0
+	}
+	return w.windowTokens < w.config.TokenBudget
+}
+
+// servedTrafficWithin reports whether route served a request other than a
+// ping within d. Pings are left out, or each ping would skip the next tick.
+func (w *WarmPool) servedTrafficWithin(route TextRoute, d time.Duration) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stats, ok := w.routes[ProviderKey{Provider: route.Provider, Model: route.Model}]
+	return ok && within(stats.LastUsed, d)
+}
+
+// within reports whether t is set and less than d ago.
+func within(t time.Time, d time.Duration) bool {
+	return !t.IsZero() && time.Since(t) < d
+}
+
+// route returns the stats for provider/model, creating them. w.mu must be held.
+func (w *WarmPool) route(provider, model string) *WarmRouteStats {
+	key := ProviderKey{Provider: provider, Model: model}
+	stats, ok := w.routes[key]
+	if !ok {
+		stats = &WarmRouteStats{Provider: provider, Model: model}
+		w.routes[key] = stats
+	}
+	return stats
+}
+
+// IsWarm reports whether provider/model served a successful request or ping
+// within ColdAfter.
+func (w *WarmPool) IsWarm(provider, model string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stats, ok := w.routes[ProviderKey{Provider: provider, Model: model}]
+	return ok && within(stats.lastActive(), w.config.ColdAfter)
+}
+
+// Stats returns the stats of every configured or observed route.
+func (w *WarmPool) Stats() []WarmRouteStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, route := range w.config.Routes {
+		w.route(route.Provider, route.Model)
+	}
+	stats := make([]WarmRouteStats, 0, len(w.routes))
+	for _, route := range w.routes {
+		snapshot := *route
+		snapshot.Warm = within(snapshot.lastActive(), w.config.ColdAfter)
+		stats = append(stats, snapshot)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Provider != stats[j].Provider {
+			return stats[i].Provider < stats[j].Provider
+		}
+		return stats[i].Model < stats[j].Model
+	})
+	return stats
+}
+
+// observe records a successful request's latency as a cold start or a warm
+// request.
+func (w *WarmPool) observe(ctx context.Context, model string, start time.Time) {
+	provider, _ := ctx.Value(middleware.CtxKeyProvider).(string)
+	if provider == "" {
+		return
+	}
+	now := time.Now()
+	latency := now.Sub(start)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stats := w.route(provider, model)
+	if last := stats.lastActive(); last.IsZero() || start.Sub(last) >= w.config.ColdAfter {
+		stats.ColdStarts++
+		stats.ColdLatency += latency
+	} else {
+		stats.WarmRequests++
+		stats.WarmLatency += latency
+	}
+	if ctx.Value(warmPingKey{}) != nil {
+		stats.LastPing = now
+	} else {
+		stats.LastUsed = now
+	}
+}
+
+// warmPoolMiddleware feeds request latencies to a WarmPool.
+type warmPoolMiddleware struct {
+	pool *WarmPool
+}
+
+func (m warmPoolMiddleware) ApplyText(next types.TextHandler) types.TextHandler {
+	return func(ctx context.Context, request types.TextRequest) (*types.TextResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, request)
+		if err == nil {
+			m.pool.observe(ctx, request.Model, start)
+		}
+		return resp, err
+	}
+}
+
+// ApplyStream records the time to open the stream.
+func (m warmPoolMiddleware) ApplyStream(next types.StreamHandler) types.StreamHandler {
+	return func(ctx context.Context, request types.TextRequest) (<-chan types.TextChunk, error) {
+		start := time.Now()
+		stream, err := next(ctx, request)
+		if err == nil {
+			m.pool.observe(ctx, request.Model, start)
+		}
+		return stream, err
+	}
+}
+
+func (m warmPoolMiddleware) ApplyStructured(next types.StructuredHandler) types.StructuredHandler {
+	return func(ctx context.Context, request types.StructuredRequest) (*types.StructuredResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, request)
+		if err == nil {
+			m.pool.observe(ctx, request.Model, start)
+		}
+		return resp, err
+	}
+}
+
+func (m warmPoolMiddleware) ApplyEmbeddings(next types.EmbeddingsHandler) types.EmbeddingsHandler {
+	return next
+}
+
+func (m warmPoolMiddleware) ApplyAudio(next types.AudioHandler) types.AudioHandler {
+	return next
+}
+
+func (m warmPoolMiddleware) ApplyImage(next types.ImageHandler) types.ImageHandler {
+	return next
+}
+
+func (m warmPoolMiddleware) ApplyRerank(next types.RerankHandler) types.RerankHandler {
+	return next
+}
+
+// WarmPool returns the client's warm pool, or nil without WithWarmPool.
+func (p *Wormhole) WarmPool() *WarmPool {
+	return p.warmPool
+}
+
+// PreferWarm routes the request to the first warm route among its primary
+// model, fallback models and provider fallbacks when the primary is cold, so
+// interactive traffic skips a cold start. The remaining routes stay as
+// fallbacks in their original order. It has no effect without WithWarmPool or
+// with a BaseURL override.
+func (b *TextRequestBuilder) PreferWarm() *TextRequestBuilder {
+	b.preferWarm = true
+	return b
+}
+
+// warmRouted returns the builder to execute: b itself, or a clone whose
+// primary route is the first warm one (see PreferWarm).
+func (b *TextRequestBuilder) warmRouted() *TextRequestBuilder {
+	wormhole := b.getWormhole()
+	if !b.preferWarm || wormhole.warmPool == nil || b.getBaseURL() != "" {
+		return b
+	}
+	provider, err := wormhole.resolveProviderName(b.getProvider())
+	if err != nil {
+		return b
+	}
+	model := b.resolveModel(b.request.Model)
+	routes := []TextRoute{{Provider: provider, Model: model}}
+	for _, fallback := range b.fallbackModels {
+		routes = append(routes, TextRoute{Provider: provider, Model: fallback})
+	}
+	routes = append(routes, wormhole.resolveTextRoutes(b.providerFallbacks, model)...)
+	if wormhole.warmPool.IsWarm(provider, model) {
+		return b
+	}
+	for i, route := range routes[1:] {
+		if !wormhole.warmPool.IsWarm(route.Provider, route.Model) {
+			continue
+		}
+		clone := b.Clone()
+		clone.setProvider(route.Provider)
+		clone.request.Model = route.Model
+		clone.fallbackModels = nil
+		clone.providerFallbacks = append(append([]TextRoute(nil), routes[:i+1]...), routes[i+2:]...)
+		return clone
+	}
+	return b
+}
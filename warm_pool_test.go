@@ -0,0 +1,123 @@
+package wormhole_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func newWarmPoolClient(config wormhole.WarmPoolConfig, opts ...wormhole.Option) *wormhole.Wormhole {
+	provider := mocktesting.NewMockProvider("mock").WithTextResponse(types.TextResponse{
+		Text:  "pong",
+		Usage: &types.Usage{PromptTokens: 4, CompletionTokens: 1, TotalTokens: 5},
+	})
+	return wormhole.New(append([]wormhole.Option{
+		wormhole.WithDefaultProvider("mock"),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(provider)),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+		wormhole.WithWarmPool(config),
+	}, opts...)...)
+}
+
+func TestWarmPoolPingsRoutesAndTracksColdStarts(t *testing.T) {
+	t.Parallel()
+
+	client := newWarmPoolClient(wormhole.WarmPoolConfig{
+		Routes:   []wormhole.TextRoute{{Provider: "mock", Model: "free-model"}},
+		Interval: time.Hour,
+	})
+	defer client.Close()
+	pool := client.WarmPool()
+	require.NotNil(t, pool)
+
+	require.Eventually(t, func() bool { return pool.IsWarm("mock", "free-model") }, time.Second, 5*time.Millisecond)
+
+	_, err := client.Text().Model("free-model").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+
+	stats := pool.Stats()
+	require.Len(t, stats, 1)
+	assert.True(t, stats[0].Warm)
+	assert.Equal(t, int64(1), stats[0].Pings)
+	assert.Equal(t, int64(5), stats[0].PingTokens)
+	assert.Equal(t, int64(1), stats[0].ColdStarts, "the first ping is the cold start")
+	assert.Equal(t, int64(1), stats[0].WarmRequests)
+	assert.False(t, pool.IsWarm("mock", "other-model"))
+}
+
+func TestWarmPoolPingsDoNotCountAsTraffic(t *testing.T) {
+	t.Parallel()
+
+	client := newWarmPoolClient(wormhole.WarmPoolConfig{
+		Routes:   []wormhole.TextRoute{{Provider: "mock", Model: "free-model"}},
+		Interval: 20 * time.Millisecond,
+	})
+	defer client.Close()
+	pool := client.WarmPool()
+
+	require.Eventually(t, func() bool { return pool.Stats()[0].Pings >= 3 }, time.Second, 5*time.Millisecond)
+	stats := pool.Stats()[0]
+	assert.True(t, stats.LastUsed.IsZero(), "pings are not traffic")
+	assert.False(t, stats.LastPing.IsZero())
+	assert.True(t, stats.Warm, "pings keep the route warm")
+
+	_, err := client.Text().Model("free-model").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	assert.False(t, pool.Stats()[0].LastUsed.IsZero())
+}
+
+func TestWarmPoolStopsPingingWhenBudgetIsSpent(t *testing.T) {
+	t.Parallel()
+
+	client := newWarmPoolClient(wormhole.WarmPoolConfig{
+		Routes:      []wormhole.TextRoute{{Provider: "mock", Model: "a"}, {Provider: "mock", Model: "b"}},
+		Interval:    time.Hour,
+		TokenBudget: 5,
+	})
+	pool := client.WarmPool()
+	require.Eventually(t, func() bool { return pool.IsWarm("mock", "a") }, time.Second, 5*time.Millisecond)
+	require.NoError(t, client.Close())
+
+	stats := pool.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, int64(1), stats[0].Pings)
+	assert.Equal(t, int64(0), stats[1].Pings, "budget was spent on the first route")
+}
+
+func TestPreferWarmRoutesAroundColdPrimary(t *testing.T) {
+	t.Parallel()
+
+	capture := mocktesting.CaptureRequests()
+	client := newWarmPoolClient(wormhole.WarmPoolConfig{
+		Routes:   []wormhole.TextRoute{{Provider: "mock", Model: "warm-model"}},
+		Interval: time.Hour,
+	}, wormhole.WithProviderMiddleware(capture))
+	defer client.Close()
+	require.Eventually(t, func() bool { return client.WarmPool().IsWarm("mock", "warm-model") }, time.Second, 5*time.Millisecond)
+	capture.Reset()
+
+	_, err := client.Text().Model("cold-model").WithFallback("warm-model").Prompt("hi").PreferWarm().Generate(context.Background())
+	require.NoError(t, err)
+	capture.LastText(t).Model("warm-model")
+
+	_, err = client.Text().Model("cold-model").WithFallback("warm-model").Prompt("hi").Generate(context.Background())
+	require.NoError(t, err)
+	capture.LastText(t).Model("cold-model")
+}
+
+func TestWarmPoolDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	client := wormhole.New(wormhole.WithDiscovery(false))
+	defer client.Close()
+	assert.Nil(t, client.WarmPool())
+}
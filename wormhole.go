@@ -19,8 +19,10 @@ type Wormhole struct {
 	config             Config
 	providerMiddleware *types.ProviderMiddlewareChain // Type-safe middleware chain
 	toolRegistry       *ToolRegistry                  // Registry of available tools for function calling
-	modelRegistry      *types.ModelRegistry           // Registry instance pinned at client construction
+	modelRegistry      types.ModelCatalog             // Registry instance pinned at client construction
 	discoveryService   *discovery.DiscoveryService    // Dynamic model discovery service
+	unknownModelsMu    sync.Mutex                     // Guards unknownModels
+	unknownModels      map[string]struct{}            // provider/model pairs already warned about in Permissive mode
 
 	// Cache metrics
 	cacheHits      atomic.Int64
@@ -47,6 +49,14 @@ type Wormhole struct {
 
 	// Closers registered by options, closed in Shutdown
 	closers []io.Closer
+
+	prompts        *PromptRegistry             // Versioned prompts for UsePrompt
+	experiments    *ExperimentRegistry         // Traffic splits and variant stats for Experiment
+	warmPool       *WarmPool                   // Background pinger for WithWarmPool; nil when disabled
+	jobs           *JobQueue                   // Background job workers for WithJobQueue; nil when disabled
+	scorecard      *Scorecard                  // Rolling provider stats for WithScorecard; nil when disabled
+	contextManager *ContextManager             // Fits prompts to context windows for WithContextManager; nil when disabled
+	circuits       *middleware.CircuitBreakers // Per-provider breakers for WithCircuitBreaker; nil when disabled
 }
 
 // IdempotencyConfig holds configuration for idempotent request handling
@@ -59,38 +69,56 @@ type IdempotencyConfig struct {
 
 // Config holds the configuration for Wormhole
 type Config struct {
-	DefaultProvider      string
-	Providers            map[string]types.ProviderConfig
-	CustomFactories      map[string]types.ProviderFactory
-	ProviderMiddlewares  []types.ProviderMiddleware // Type-safe middleware
-	Middleware           []middleware.Middleware    // DEPRECATED: use ProviderMiddlewares instead
-	DebugLogging         bool
-	Logger               types.Logger
-	DefaultTimeout       time.Duration
-	DefaultTimeoutSet    bool
-	DefaultRetries       int
-	DefaultRetriesSet    bool
-	DefaultRetryDelay    time.Duration
-	DefaultRetryDelaySet bool
-	ModelValidation      bool                      // Whether to validate models against registry (default: true)
-	DiscoveryConfig      discovery.DiscoveryConfig // Dynamic model discovery configuration
-	EnableDiscovery      bool                      // Whether to enable dynamic model discovery (default: true)
-	Idempotency          *IdempotencyConfig        // Idempotency configuration for duplicate prevention
-	Models               []*types.ModelInfo        // Models to load into the registry (opt-in; see WithModels)
-	AttemptTrace         AttemptTraceFunc          // Optional per-attempt tracing callback
-	StreamIdleTimeout    time.Duration             // Per-chunk idle timeout for streaming (0 = disabled)
-	StreamTrace          StreamTraceFunc           // Optional stream lifecycle tracing callback
-	Closers              []io.Closer               // Closers to invoke during Shutdown
+	DefaultProvider         string
+	Providers               map[string]types.ProviderConfig
+	CustomFactories         map[string]types.ProviderFactory
+	ProviderMiddlewares     []types.ProviderMiddleware // Type-safe middleware
+	Middleware              []middleware.Middleware    // DEPRECATED: use ProviderMiddlewares instead
+	DebugLogging            bool
+	Logger                  types.Logger
+	DefaultTimeout          time.Duration
+	DefaultTimeoutSet       bool
+	DefaultRetries          int
+	DefaultRetriesSet       bool
+	DefaultRetryDelay       time.Duration
+	DefaultRetryDelaySet    bool
+	UserAgent               string                           // Default User-Agent for providers that do not set one (see WithUserAgent)
+	DisableUserAgent        bool                             // Send no User-Agent header (see WithoutUserAgent)
+	ModelValidation         bool                             // Whether to validate models against registry (default: true)
+	ModelValidationMode     ModelValidationMode              // How validation treats unknown models (default: Strict)
+	DisableParamConstraints bool                             // Send request parameters as written (see WithoutParamConstraints)
+	DiscoveryConfig         discovery.DiscoveryConfig        // Dynamic model discovery configuration
+	EnableDiscovery         bool                             // Whether to enable dynamic model discovery (default: true)
+	Idempotency             *IdempotencyConfig               // Idempotency configuration for duplicate prevention
+	Models                  []*types.ModelInfo               // Models to load into the registry (opt-in; see WithModels)
+	ModelRegistry           types.ModelCatalog               // Registry backend for this client (see WithModelRegistry); nil uses types.DefaultModelRegistry
+	AttemptTrace            AttemptTraceFunc                 // Optional per-attempt tracing callback
+	StreamIdleTimeout       time.Duration                    // Per-chunk idle timeout for streaming (0 = disabled)
+	StreamTrace             StreamTraceFunc                  // Optional stream lifecycle tracing callback
+	Closers                 []io.Closer                      // Closers to invoke during Shutdown
+	ModelEquivalents        []ModelEquivalenceClass          // Caller overrides for fallback model translation
+	ModelAliases            map[string]string                // Model name aliases resolved at request time (see WithModelAliases)
+	DefaultModels           map[string]string                // Per-provider model used when a request sets none (see WithDefaultModel)
+	ResponseValidators      map[string]ResponseValidator     // Named validators for GenerateValidated (see WithResponseValidator)
+	Prompts                 *PromptRegistry                  // Shared prompt registry (see WithPromptRegistry); nil gives the client its own
+	Experiments             *ExperimentRegistry              // Shared experiment registry (see WithExperimentRegistry); nil gives the client its own
+	WarmPool                *WarmPoolConfig                  // Routes to keep warm (see WithWarmPool)
+	JobQueue                *JobQueueConfig                  // Background job workers (see WithJobQueue)
+	Scorecard               *ScorecardConfig                 // Rolling per-provider stats (see WithScorecard)
+	ContextManager          *ContextManagerConfig            // Context window trimming and summarization (see WithContextManager)
+	Moderation              *ModerationConfig                // Prompt moderation before generation (see WithModeration)
+	CircuitBreaker          *middleware.CircuitBreakerConfig // Per-provider circuit breaking (see WithCircuitBreaker)
 }
 
 // New creates a new Wormhole instance using functional options.
 //
-// Model registry is opt-in: the global types.DefaultModelRegistry starts empty.
-// Callers who enable model validation (the default) must populate the registry,
-// either by passing WithModels(...) here or by calling
-// types.DefaultModelRegistry.LoadModelsFromConfig(models) before issuing requests.
-// Without a populated registry, model-validation helpers have nothing to validate
-// against. To skip validation entirely, use WithModelValidation(false).
+// Model registry is opt-in: a client validates against the registry given by
+// WithModelRegistry, falling back to the deprecated global
+// types.DefaultModelRegistry, and either starts empty. Callers who enable model
+// validation (the default) populate it with WithModels(...) or use a
+// provider-backed types.ReadThroughRegistry. While the registry is empty,
+// validation is skipped. To skip validation entirely, use
+// WithModelValidation(false).
 //
 // Example:
 //
@@ -116,9 +144,15 @@ func New(opts ...Option) *Wormhole {
 		opt(&config)
 	}
 
+	registry := config.ModelRegistry
+	if registry == nil {
+		registry = types.DefaultModelRegistry
+	}
 	// Populate the opt-in model registry with any caller-supplied models.
-	if len(config.Models) > 0 {
-		types.DefaultModelRegistry.LoadModelsFromConfig(config.Models)
+	if registrar, ok := registry.(types.ModelRegistrar); ok {
+		for _, model := range config.Models {
+			registrar.Register(model)
+		}
 	}
 
 	// Create client with final, immutable config
@@ -127,10 +161,18 @@ func New(opts ...Option) *Wormhole {
 		providers:         make(map[string]*cachedProvider),
 		config:            config,
 		toolRegistry:      NewToolRegistry(),
-		modelRegistry:     types.DefaultModelRegistry,
+		modelRegistry:     registry,
 		shutdownChan:      make(chan struct{}),
 		idempotencyCache:  make(map[string]*idempotencyEntry),
 		closers:           config.Closers,
+		prompts:           config.Prompts,
+		experiments:       config.Experiments,
+	}
+	if p.prompts == nil {
+		p.prompts = NewPromptRegistry()
+	}
+	if p.experiments == nil {
+		p.experiments = NewExperimentRegistry()
 	}
 
 	// Start the sweeper only when idempotency can actually retain entries.
@@ -170,6 +212,44 @@ func New(opts ...Option) *Wormhole {
 	// Add user-provided provider middlewares
 	providerMiddlewares = append(providerMiddlewares, config.ProviderMiddlewares...)
 
+	// Moderation checks prompts as the caller wrote them, before anything
+	// downstream rewrites or sends them
+	if config.Moderation != nil {
+		providerMiddlewares = append(providerMiddlewares, newModerationMiddleware(p, *config.Moderation))
+	}
+
+	// Parameter constraints run before the context manager so it sizes the
+	// reply from the capped max tokens
+	if !config.DisableParamConstraints {
+		providerMiddlewares = append(providerMiddlewares, &paramConstraintsMiddleware{client: p})
+	}
+
+	// The context manager runs after user middleware so logging and caching
+	// see the request as written
+	if config.ContextManager != nil {
+		p.contextManager = newContextManager(p, *config.ContextManager)
+		providerMiddlewares = append(providerMiddlewares, contextManagerMiddleware{manager: p.contextManager})
+	}
+
+	// Circuit breakers sit below moderation and context trimming so only
+	// provider failures count toward tripping
+	if config.CircuitBreaker != nil {
+		p.circuits = middleware.NewCircuitBreakers(*config.CircuitBreaker)
+		providerMiddlewares = append(providerMiddlewares, middleware.NewLegacyAdapter(p.circuits.Middleware()))
+	}
+
+	// The warm pool and scorecard sit innermost so they time the provider call alone
+	if config.WarmPool != nil {
+		p.warmPool = newWarmPool(p, *config.WarmPool)
+		providerMiddlewares = append(providerMiddlewares, warmPoolMiddleware{pool: p.warmPool})
+		p.closers = append(p.closers, p.warmPool)
+	}
+	if config.Scorecard != nil {
+		p.scorecard = newScorecard(*config.Scorecard)
+		p.scorecard.models = registry
+		providerMiddlewares = append(providerMiddlewares, scorecardMiddleware{card: p.scorecard})
+	}
+
 	if len(providerMiddlewares) > 0 {
 		p.providerMiddleware = types.NewProviderChain(providerMiddlewares...)
 	}
@@ -179,6 +259,18 @@ func New(opts ...Option) *Wormhole {
 	// via WithMiddleware() option. The middlewareChain is no longer created
 	// as all middleware execution happens through providerMiddleware.
 
+	if config.JobQueue != nil {
+		p.jobs = newJobQueue(p, *config.JobQueue)
+		p.closers = append(p.closers, p.jobs)
+	}
+
+	if p.warmPool != nil {
+		p.warmPool.start()
+	}
+	if p.jobs != nil {
+		p.jobs.start()
+	}
+
 	return p
 }
 
@@ -202,6 +294,28 @@ func (p *Wormhole) Structured() *StructuredRequestBuilder {
 	}
 }
 
+// TextFrom creates a text request builder preconfigured from template, such
+// as a request saved with TextRequestBuilder.Request. The builder works on a
+// copy, so one template can seed many requests concurrently.
+//
+// Example:
+//
+//	template := client.Text().Model("gpt-4o").SystemPrompt(rules).Temperature(0.2).Request()
+//	resp, err := client.TextFrom(template).Prompt(question).Generate(ctx)
+func (p *Wormhole) TextFrom(template types.TextRequest) *TextRequestBuilder {
+	builder := p.Text()
+	builder.request = cloneTextRequest(&template)
+	return builder
+}
+
+// StructuredFrom creates a structured request builder preconfigured from
+// template, such as a request saved with StructuredRequestBuilder.Request.
+func (p *Wormhole) StructuredFrom(template types.StructuredRequest) *StructuredRequestBuilder {
+	builder := p.Structured()
+	builder.request = cloneStructuredRequest(&template)
+	return builder
+}
+
 // Embeddings creates a new embeddings request builder
 func (p *Wormhole) Embeddings() *EmbeddingsRequestBuilder {
 	return &EmbeddingsRequestBuilder{
@@ -7,6 +7,7 @@ package wormhole
 //   - Step hooks for observability
 //   - Conversation accumulation across the loop
 //   - Step history in the result
+//   - Optional memory and transcript summarization
 //
 // Tools registered on the client via RegisterTypedTool are automatically
 // available to the agent. Agent-scoped tools (via AddTool/AgentAddTool)
@@ -24,8 +25,9 @@ package wormhole
 //	    Run(ctx, "What's the weather in San Francisco?")
 func (p *Wormhole) Agent() *AgentBuilder {
 	return &AgentBuilder{
-		wormhole: p,
-		tools:    NewToolRegistry(),
-		maxSteps: 10,
+		wormhole:    p,
+		tools:       NewToolRegistry(),
+		maxSteps:    10,
+		recallLimit: defaultRecallLimit,
 	}
 }
@@ -3,6 +3,7 @@ package wormhole
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/garyblankenship/wormhole/v2/types"
 )
@@ -20,6 +21,16 @@ const (
 	CapabilityStreaming     Capability = "streaming"
 	CapabilityVision        Capability = "vision"
 	CapabilityCodeExecution Capability = "code_execution"
+	CapabilityWebSearch     Capability = "web_search"
+	CapabilityTokenCounting Capability = "token_counting"
+
+	// Per-feature support for text requests.
+	CapabilityAudioInput        Capability = "audio_input"
+	CapabilityAudioOutput       Capability = "audio_output"
+	CapabilityJSONSchemaStrict  Capability = "json_schema_strict"
+	CapabilityParallelToolCalls Capability = "parallel_tool_calls"
+	CapabilityLogprobs          Capability = "logprobs"
+	CapabilitySeed              Capability = "seed"
 )
 
 // ProviderCapabilities returns the capabilities supported by a provider.
@@ -36,6 +47,8 @@ func (p *Wormhole) ProviderCapabilities(provider string) *Capabilities {
 }
 
 // ModelCapabilities returns capabilities for a specific provider/model pair.
+// A model in the client's registry answers from its registered capabilities;
+// otherwise discovery is consulted, then the provider as a whole.
 func (p *Wormhole) ModelCapabilities(provider, model string) (*Capabilities, error) {
 	if provider == "" {
 		return nil, fmt.Errorf("provider must be specified")
@@ -44,6 +57,12 @@ func (p *Wormhole) ModelCapabilities(provider, model string) (*Capabilities, err
 		return nil, fmt.Errorf("model must be specified")
 	}
 
+	if p.modelRegistry != nil {
+		if info, ok := p.modelRegistry.Get(model); ok && (info.Provider == "" || info.Provider == provider) {
+			return capabilitiesFromModelCapabilities(provider, info.Capabilities), nil
+		}
+	}
+
 	if p.discoveryService != nil {
 		models, err := p.discoveryService.GetModels(context.Background(), provider)
 		if err == nil {
@@ -79,12 +98,87 @@ func capabilitiesFromModelCapabilities(provider string, modelCaps []types.ModelC
 			caps.caps[CapabilityStreaming] = true
 		case types.CapabilityVision:
 			caps.caps[CapabilityVision] = true
+		case types.CapabilityWebSearch:
+			caps.caps[CapabilityWebSearch] = true
+		case types.CapabilityTokenCounting:
+			caps.caps[CapabilityTokenCounting] = true
+		case types.CapabilityAudioInput:
+			caps.caps[CapabilityAudioInput] = true
+		case types.CapabilityAudioOutput:
+			caps.caps[CapabilityAudioOutput] = true
+		case types.CapabilityJSONSchemaStrict:
+			caps.caps[CapabilityJSONSchemaStrict] = true
+		case types.CapabilityParallelToolCalls:
+			caps.caps[CapabilityParallelToolCalls] = true
+		case types.CapabilityLogprobs:
+			caps.caps[CapabilityLogprobs] = true
+		case types.CapabilitySeed:
+			caps.caps[CapabilitySeed] = true
 		}
 	}
 
 	return caps
 }
 
+// CapabilityMatrix maps model IDs to what each model supports.
+type CapabilityMatrix map[string]*Capabilities
+
+// CapabilityMatrix returns the per-model capabilities of a provider's models
+// in the client's model registry. Models registered without a provider are
+// included for every provider. The matrix is empty when the registry cannot
+// list its models or holds none for the provider.
+//
+// Example:
+//
+//	matrix := client.CapabilityMatrix("openai")
+//	if matrix.Supports("gpt-4o", wormhole.CapabilityVision, wormhole.CapabilitySeed) {
+//	    // send images with a fixed seed
+//	}
+//	strict := matrix.Models(wormhole.CapabilityJSONSchemaStrict)
+func (p *Wormhole) CapabilityMatrix(provider string) CapabilityMatrix {
+	if resolved, err := p.resolveProviderName(provider); err == nil {
+		provider = resolved
+	}
+	matrix := CapabilityMatrix{}
+	lister, ok := p.modelRegistry.(interface{ List() []*types.ModelInfo })
+	if !ok {
+		return matrix
+	}
+	for _, info := range lister.List() {
+		if info.Provider == "" || info.Provider == provider {
+			matrix[info.ID] = capabilitiesFromModelCapabilities(provider, info.Capabilities)
+		}
+	}
+	return matrix
+}
+
+// Supports reports whether model is in the matrix and supports every one of
+// caps.
+func (m CapabilityMatrix) Supports(model string, caps ...Capability) bool {
+	modelCaps, ok := m[model]
+	if !ok {
+		return false
+	}
+	for _, capability := range caps {
+		if !modelCaps.Has(capability) {
+			return false
+		}
+	}
+	return true
+}
+
+// Models returns the sorted IDs of the models that support every one of caps.
+func (m CapabilityMatrix) Models(caps ...Capability) []string {
+	var models []string
+	for model := range m {
+		if m.Supports(model, caps...) {
+			models = append(models, model)
+		}
+	}
+	sort.Strings(models)
+	return models
+}
+
 func conservativeProviderCapabilities(provider string) *Capabilities {
 	caps := &Capabilities{provider: provider, caps: make(map[Capability]bool)}
 
@@ -130,7 +224,18 @@ func (c *Capabilities) SupportsEmbeddings() bool { return c.Has(CapabilityEmbedd
 func (c *Capabilities) SupportsToolCalling() bool {
 	return c.Has(CapabilityToolCalling)
 }
-func (c *Capabilities) SupportsStreaming() bool { return c.Has(CapabilityStreaming) }
-func (c *Capabilities) SupportsVision() bool    { return c.Has(CapabilityVision) }
-func (c *Capabilities) SupportsImages() bool    { return c.Has(CapabilityImages) }
-func (c *Capabilities) SupportsAudio() bool     { return c.Has(CapabilityAudio) }
+func (c *Capabilities) SupportsStreaming() bool     { return c.Has(CapabilityStreaming) }
+func (c *Capabilities) SupportsVision() bool        { return c.Has(CapabilityVision) }
+func (c *Capabilities) SupportsImages() bool        { return c.Has(CapabilityImages) }
+func (c *Capabilities) SupportsAudio() bool         { return c.Has(CapabilityAudio) }
+func (c *Capabilities) SupportsTokenCounting() bool { return c.Has(CapabilityTokenCounting) }
+func (c *Capabilities) SupportsAudioInput() bool    { return c.Has(CapabilityAudioInput) }
+func (c *Capabilities) SupportsAudioOutput() bool   { return c.Has(CapabilityAudioOutput) }
+func (c *Capabilities) SupportsJSONSchemaStrict() bool {
+	return c.Has(CapabilityJSONSchemaStrict)
+}
+func (c *Capabilities) SupportsParallelToolCalls() bool {
+	return c.Has(CapabilityParallelToolCalls)
+}
+func (c *Capabilities) SupportsLogprobs() bool { return c.Has(CapabilityLogprobs) }
+func (c *Capabilities) SupportsSeed() bool     { return c.Has(CapabilitySeed) }
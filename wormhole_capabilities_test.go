@@ -45,6 +45,7 @@ func TestCapabilitiesHelperMethods(t *testing.T) {
 		types.CapabilityFunctions,
 		types.CapabilityStream,
 		types.CapabilityVision,
+		types.CapabilityTokenCounting,
 	}
 
 	caps := capabilitiesFromModelCapabilities("test-provider", modelCaps)
@@ -56,6 +57,7 @@ func TestCapabilitiesHelperMethods(t *testing.T) {
 	assert.True(t, caps.SupportsToolCalling())
 	assert.True(t, caps.SupportsStreaming())
 	assert.True(t, caps.SupportsVision())
+	assert.True(t, caps.SupportsTokenCounting())
 }
 
 type discoveryMockFetcher struct{}
@@ -126,3 +128,39 @@ func TestCapabilitiesNilReceiver(t *testing.T) {
 	assert.False(t, caps.SupportsText())
 	assert.False(t, caps.SupportsVision())
 }
+
+func TestCapabilityMatrixFromRegistry(t *testing.T) {
+	t.Parallel()
+	client := validationTestClient(types.ProviderConfig{}, withTestModels(
+		&types.ModelInfo{ID: "full", Provider: "mock", Capabilities: []types.ModelCapability{
+			types.CapabilityText,
+			types.CapabilityVision,
+			types.CapabilityAudioInput,
+			types.CapabilityAudioOutput,
+			types.CapabilityJSONSchemaStrict,
+			types.CapabilityParallelToolCalls,
+			types.CapabilityLogprobs,
+			types.CapabilitySeed,
+		}},
+		&types.ModelInfo{ID: "basic", Capabilities: []types.ModelCapability{types.CapabilityText, types.CapabilitySeed}},
+		&types.ModelInfo{ID: "elsewhere", Provider: "other", Capabilities: []types.ModelCapability{types.CapabilityText}},
+	))
+
+	matrix := client.CapabilityMatrix("mock")
+	require.Len(t, matrix, 2)
+	assert.True(t, matrix.Supports("full", CapabilityVision, CapabilityAudioInput, CapabilityAudioOutput,
+		CapabilityJSONSchemaStrict, CapabilityParallelToolCalls, CapabilityLogprobs, CapabilitySeed))
+	assert.False(t, matrix.Supports("basic", CapabilityLogprobs))
+	assert.False(t, matrix.Supports("elsewhere"))
+	assert.Equal(t, []string{"basic", "full"}, matrix.Models(CapabilitySeed))
+	assert.Equal(t, []string{"full"}, matrix.Models(CapabilityJSONSchemaStrict))
+
+	caps, err := client.ModelCapabilities("mock", "full")
+	require.NoError(t, err)
+	assert.True(t, caps.SupportsAudioOutput())
+	assert.True(t, caps.SupportsParallelToolCalls())
+	caps, err = client.ModelCapabilities("mock", "basic")
+	require.NoError(t, err)
+	assert.True(t, caps.SupportsSeed())
+	assert.False(t, caps.SupportsLogprobs())
+}
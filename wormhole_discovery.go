@@ -21,6 +21,7 @@ func (p *Wormhole) initializeDiscoveryService() {
 			discoveryKind = profile.Discovery
 		}
 
+		fetcherCount := len(modelFetchers)
 		switch discoveryKind {
 		case discoveryOpenAI:
 			if apiKey != "" {
@@ -36,6 +37,12 @@ func (p *Wormhole) initializeDiscoveryService() {
 				baseURL = configuredBaseURL(profile)
 			}
 			modelFetchers = append(modelFetchers, fetchers.NewOllamaFetcher(baseURL))
+		case discoveryLMStudio:
+			baseURL := providerConfig.BaseURL
+			if baseURL == "" && known {
+				baseURL = configuredBaseURL(profile)
+			}
+			modelFetchers = append(modelFetchers, fetchers.NewLMStudioFetcher(baseURL, apiKey))
 		case discoveryOpenRouter:
 			modelFetchers = append(modelFetchers, fetchers.NewOpenRouterFetcher())
 		case discoveryGemini:
@@ -52,6 +59,9 @@ func (p *Wormhole) initializeDiscoveryService() {
 				))
 			}
 		}
+		if len(modelFetchers) > fetcherCount {
+			fetchers.ApplyUserAgent(modelFetchers[fetcherCount], p.applyDefaultUserAgent(providerConfig))
+		}
 	}
 
 	p.discoveryService = discovery.NewDiscoveryService(p.config.DiscoveryConfig, modelFetchers...)
@@ -6,8 +6,9 @@ import (
 	"github.com/garyblankenship/wormhole/v2/types"
 )
 
-// RegisterTool registers a new tool that can be called by LLMs.
-func (p *Wormhole) RegisterTool(name string, description string, schema types.Schema, handler types.ToolHandler) {
+// RegisterTool registers a new tool that can be called by LLMs. Options such
+// as IdempotentTool adjust how the tool executes.
+func (p *Wormhole) RegisterTool(name string, description string, schema types.Schema, handler types.ToolHandler, opts ...ToolOption) {
 	var schemaMap map[string]any
 
 	if m, ok := schema.(map[string]any); ok {
@@ -34,6 +35,7 @@ func (p *Wormhole) RegisterTool(name string, description string, schema types.Sc
 	}
 
 	definition := types.NewToolDefinition(tool, handler)
+	applyToolOptions(definition, opts)
 	p.toolRegistry.Register(name, definition)
 }
 
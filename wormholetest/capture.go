@@ -0,0 +1,325 @@
+package wormholetest
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// RequestCapture records every typed request a client sends to its providers,
+// after the builders have applied defaults, aliases, prompts and tools, so
+// application tests can check prompt construction without an HTTP server.
+// It is a types.ProviderMiddleware; install it when building the client:
+//
+//	capture := wormholetest.CaptureRequests()
+//	client := wormhole.New(
+//	    wormhole.WithCustomProvider("openai", wormholetest.MockProviderFactory(mock)),
+//	    wormhole.WithProviderConfig("openai", types.ProviderConfig{}),
+//	    wormhole.WithDefaultProvider("openai"),
+//	    wormhole.WithProviderMiddleware(capture),
+//	)
+//	summarize(ctx, client, doc)
+//	capture.LastText(t).
+//	    Model("gpt-5-mini").
+//	    Temperature(0.2).
+//	    MessageContains(types.RoleUser, doc).
+//	    HasTool("lookup_account")
+//
+// Requests pass through unchanged.
+type RequestCapture struct {
+	mu       sync.Mutex
+	requests []any
+}
+
+var (
+	_ types.ProviderMiddleware    = (*RequestCapture)(nil)
+	_ types.AudioStreamMiddleware = (*RequestCapture)(nil)
+)
+
+// CaptureRequests returns an empty request capture. It takes no client: a
+// client's provider middleware is fixed by wormhole.New, so pass the capture
+// to wormhole.WithProviderMiddleware when building the client under test.
+// (wormholetest cannot import the wormhole package either, since wormhole's
+// own tests import wormholetest.)
+func CaptureRequests() *RequestCapture {
+	return &RequestCapture{}
+}
+
+func captureRequest[Req any, Resp any](c *RequestCapture, next func(context.Context, Req) (Resp, error)) func(context.Context, Req) (Resp, error) {
+	return func(ctx context.Context, request Req) (Resp, error) {
+		c.mu.Lock()
+		c.requests = append(c.requests, request)
+		c.mu.Unlock()
+		return next(ctx, request)
+	}
+}
+
+// ApplyText records text requests.
+func (c *RequestCapture) ApplyText(next types.TextHandler) types.TextHandler {
+	return captureRequest(c, next)
+}
+
+// ApplyStream records streaming text requests.
+func (c *RequestCapture) ApplyStream(next types.StreamHandler) types.StreamHandler {
+	return captureRequest(c, next)
+}
+
+// ApplyStructured records structured requests.
+func (c *RequestCapture) ApplyStructured(next types.StructuredHandler) types.StructuredHandler {
+	return captureRequest(c, next)
+}
+
+// ApplyEmbeddings records embeddings requests.
+func (c *RequestCapture) ApplyEmbeddings(next types.EmbeddingsHandler) types.EmbeddingsHandler {
+	return captureRequest(c, next)
+}
+
+// ApplyAudio records audio requests.
+func (c *RequestCapture) ApplyAudio(next types.AudioHandler) types.AudioHandler {
+	return captureRequest(c, next)
+}
+
+// ApplyAudioStream records streamed transcription requests.
+func (c *RequestCapture) ApplyAudioStream(next types.AudioStreamHandler) types.AudioStreamHandler {
+	return captureRequest(c, next)
+}
+
+// ApplyImage records image requests.
+func (c *RequestCapture) ApplyImage(next types.ImageHandler) types.ImageHandler {
+	return captureRequest(c, next)
+}
+
+// ApplyRerank records rerank requests.
+func (c *RequestCapture) ApplyRerank(next types.RerankHandler) types.RerankHandler {
+	return captureRequest(c, next)
+}
+
+// Requests returns every captured request in order. Elements are request
+// values such as types.TextRequest and types.EmbeddingsRequest.
+func (c *RequestCapture) Requests() []any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.requests)
+}
+
+// Reset forgets the captured requests.
+func (c *RequestCapture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = nil
+}
+
+// TextRequests returns the captured text and streaming requests.
+func (c *RequestCapture) TextRequests() []types.TextRequest {
+	return capturedOf[types.TextRequest](c)
+}
+
+// StructuredRequests returns the captured structured requests.
+func (c *RequestCapture) StructuredRequests() []types.StructuredRequest {
+	return capturedOf[types.StructuredRequest](c)
+}
+
+// EmbeddingsRequests returns the captured embeddings requests.
+func (c *RequestCapture) EmbeddingsRequests() []types.EmbeddingsRequest {
+	return capturedOf[types.EmbeddingsRequest](c)
+}
+
+func capturedOf[T any](c *RequestCapture) []T {
+	var out []T
+	for _, request := range c.Requests() {
+		if typed, ok := request.(T); ok {
+			out = append(out, typed)
+		}
+	}
+	return out
+}
+
+// LastText returns assertions on the most recent text request, failing the
+// test when there is none.
+func (c *RequestCapture) LastText(t testing.TB) *TextRequestAssertion {
+	t.Helper()
+	requests := c.TextRequests()
+	if len(requests) == 0 {
+		t.Fatalf("request capture: no text request was sent")
+	}
+	return &TextRequestAssertion{t: t, Request: requests[len(requests)-1]}
+}
+
+// LastStructured returns assertions on the most recent structured request,
+// failing the test when there is none.
+func (c *RequestCapture) LastStructured(t testing.TB) *StructuredRequestAssertion {
+	t.Helper()
+	requests := c.StructuredRequests()
+	if len(requests) == 0 {
+		t.Fatalf("request capture: no structured request was sent")
+	}
+	return &StructuredRequestAssertion{t: t, Request: requests[len(requests)-1]}
+}
+
+// TextRequestAssertion checks one captured text request. Each method reports
+// a mismatch with t.Errorf and returns the assertion for chaining.
+type TextRequestAssertion struct {
+	t       testing.TB
+	Request types.TextRequest
+}
+
+// Model asserts the request's model.
+func (a *TextRequestAssertion) Model(want string) *TextRequestAssertion {
+	a.t.Helper()
+	checkModel(a.t, a.Request.BaseRequest, want)
+	return a
+}
+
+// Temperature asserts the request's temperature.
+func (a *TextRequestAssertion) Temperature(want float32) *TextRequestAssertion {
+	a.t.Helper()
+	checkTemperature(a.t, a.Request.BaseRequest, want)
+	return a
+}
+
+// MaxTokens asserts the request's max tokens.
+func (a *TextRequestAssertion) MaxTokens(want int) *TextRequestAssertion {
+	a.t.Helper()
+	checkMaxTokens(a.t, a.Request.BaseRequest, want)
+	return a
+}
+
+// MessageContains asserts that a message with role contains substr. The
+// system prompt counts as a system message.
+func (a *TextRequestAssertion) MessageContains(role types.Role, substr string) *TextRequestAssertion {
+	a.t.Helper()
+	checkMessageContains(a.t, a.Request.SystemPrompt, a.Request.Messages, role, substr)
+	return a
+}
+
+// MessageCount asserts the number of messages sent. The client sends a system
+// prompt as a leading system message, so it counts.
+func (a *TextRequestAssertion) MessageCount(want int) *TextRequestAssertion {
+	a.t.Helper()
+	if got := len(a.Request.Messages); got != want {
+		a.t.Errorf("request capture: got %d messages, want %d", got, want)
+	}
+	return a
+}
+
+// HasTool asserts that a tool named name was offered to the model.
+func (a *TextRequestAssertion) HasTool(name string) *TextRequestAssertion {
+	a.t.Helper()
+	if !slices.ContainsFunc(a.Request.Tools, func(tool types.Tool) bool { return toolName(tool) == name }) {
+		a.t.Errorf("request capture: tool %q not offered; tools: %v", name, toolNames(a.Request.Tools))
+	}
+	return a
+}
+
+// NoTools asserts that no tools were offered to the model.
+func (a *TextRequestAssertion) NoTools() *TextRequestAssertion {
+	a.t.Helper()
+	if len(a.Request.Tools) > 0 {
+		a.t.Errorf("request capture: want no tools, got %v", toolNames(a.Request.Tools))
+	}
+	return a
+}
+
+// StructuredRequestAssertion checks one captured structured request, as
+// TextRequestAssertion does.
+type StructuredRequestAssertion struct {
+	t       testing.TB
+	Request types.StructuredRequest
+}
+
+// Model asserts the request's model.
+func (a *StructuredRequestAssertion) Model(want string) *StructuredRequestAssertion {
+	a.t.Helper()
+	checkModel(a.t, a.Request.BaseRequest, want)
+	return a
+}
+
+// Temperature asserts the request's temperature.
+func (a *StructuredRequestAssertion) Temperature(want float32) *StructuredRequestAssertion {
+	a.t.Helper()
+	checkTemperature(a.t, a.Request.BaseRequest, want)
+	return a
+}
+
+// MaxTokens asserts the request's max tokens.
+func (a *StructuredRequestAssertion) MaxTokens(want int) *StructuredRequestAssertion {
+	a.t.Helper()
+	checkMaxTokens(a.t, a.Request.BaseRequest, want)
+	return a
+}
+
+// MessageContains asserts that a message with role contains substr.
+func (a *StructuredRequestAssertion) MessageContains(role types.Role, substr string) *StructuredRequestAssertion {
+	a.t.Helper()
+	checkMessageContains(a.t, a.Request.SystemPrompt, a.Request.Messages, role, substr)
+	return a
+}
+
+// SchemaName asserts the request's schema name.
+func (a *StructuredRequestAssertion) SchemaName(want string) *StructuredRequestAssertion {
+	a.t.Helper()
+	if a.Request.SchemaName != want {
+		a.t.Errorf("request capture: schema name %q, want %q", a.Request.SchemaName, want)
+	}
+	return a
+}
+
+func checkModel(t testing.TB, request types.BaseRequest, want string) {
+	t.Helper()
+	if request.Model != want {
+		t.Errorf("request capture: model %q, want %q", request.Model, want)
+	}
+}
+
+func checkTemperature(t testing.TB, request types.BaseRequest, want float32) {
+	t.Helper()
+	if request.Temperature == nil {
+		t.Errorf("request capture: temperature not set, want %v", want)
+	} else if *request.Temperature != want {
+		t.Errorf("request capture: temperature %v, want %v", *request.Temperature, want)
+	}
+}
+
+func checkMaxTokens(t testing.TB, request types.BaseRequest, want int) {
+	t.Helper()
+	if request.MaxTokens == nil {
+		t.Errorf("request capture: max tokens not set, want %d", want)
+	} else if *request.MaxTokens != want {
+		t.Errorf("request capture: max tokens %d, want %d", *request.MaxTokens, want)
+	}
+}
+
+func checkMessageContains(t testing.TB, systemPrompt string, messages []types.Message, role types.Role, substr string) {
+	t.Helper()
+	if role == types.RoleSystem && strings.Contains(systemPrompt, substr) {
+		return
+	}
+	for _, msg := range messages {
+		if msg.GetRole() != role {
+			continue
+		}
+		if text, ok := msg.GetContent().(string); ok && strings.Contains(text, substr) {
+			return
+		}
+	}
+	t.Errorf("request capture: no %s message contains %q", role, substr)
+}
+
+func toolName(tool types.Tool) string {
+	if tool.Function != nil && tool.Function.Name != "" {
+		return tool.Function.Name
+	}
+	return tool.Name
+}
+
+func toolNames(tools []types.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, toolName(tool))
+	}
+	return names
+}
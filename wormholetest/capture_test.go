@@ -0,0 +1,103 @@
+package wormholetest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	"github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func newCapturingClient(capture *wormholetest.RequestCapture) *wormhole.Wormhole {
+	mock := wormholetest.NewMockProvider("openai").
+		WithTextResponse(wormholetest.TextResponseWith("ok")).
+		WithStructuredData(map[string]any{"name": "Ada"})
+	return wormhole.New(
+		wormhole.WithCustomProvider("openai", wormholetest.MockProviderFactory(mock)),
+		wormhole.WithProviderConfig("openai", types.ProviderConfig{}),
+		wormhole.WithDefaultProvider("openai"),
+		wormhole.WithProviderMiddleware(capture),
+	)
+}
+
+func TestCaptureRequestsRecordsBuiltTextRequest(t *testing.T) {
+	t.Parallel()
+
+	capture := wormholetest.CaptureRequests()
+	client := newCapturingClient(capture)
+
+	_, err := client.Text().
+		Model("gpt-5-mini").
+		SystemPrompt("You summarize documents.").
+		Prompt("Summarize: quarterly report").
+		Temperature(0.2).
+		MaxTokens(200).
+		Tools(*types.NewTool("lookup_account", "Look up an account", map[string]any{"type": "object"})).
+		Generate(context.Background())
+	require.NoError(t, err)
+
+	capture.LastText(t).
+		Model("gpt-5-mini").
+		Temperature(0.2).
+		MaxTokens(200).
+		MessageContains(types.RoleSystem, "summarize documents").
+		MessageContains(types.RoleUser, "quarterly report").
+		MessageCount(2).
+		HasTool("lookup_account")
+	assert.Len(t, capture.Requests(), 1)
+}
+
+func TestCaptureRequestsRecordsStructuredRequest(t *testing.T) {
+	t.Parallel()
+
+	capture := wormholetest.CaptureRequests()
+	client := newCapturingClient(capture)
+
+	_, err := client.Structured().
+		Model("gpt-5-mini").
+		Prompt("Extract the person").
+		Schema(map[string]any{"type": "object"}).
+		SchemaName("person").
+		Generate(context.Background())
+	require.NoError(t, err)
+
+	capture.LastStructured(t).
+		Model("gpt-5-mini").
+		MessageContains(types.RoleUser, "Extract the person").
+		SchemaName("person")
+	assert.Empty(t, capture.TextRequests())
+
+	capture.Reset()
+	assert.Empty(t, capture.Requests())
+}
+
+func TestTextRequestAssertionReportsMismatches(t *testing.T) {
+	t.Parallel()
+
+	capture := wormholetest.CaptureRequests()
+	client := newCapturingClient(capture)
+
+	_, err := client.Text().Model("gpt-5-mini").Prompt("hello").Generate(context.Background())
+	require.NoError(t, err)
+
+	recorder := &errorRecorder{TB: t}
+	capture.LastText(recorder).
+		Model("gpt-5").
+		Temperature(0.5).
+		MessageContains(types.RoleUser, "goodbye").
+		HasTool("lookup_account").
+		NoTools()
+	assert.Equal(t, 4, recorder.errors)
+}
+
+// errorRecorder counts Errorf calls instead of failing the test.
+type errorRecorder struct {
+	testing.TB
+	errors int
+}
+
+func (r *errorRecorder) Errorf(string, ...any) { r.errors++ }
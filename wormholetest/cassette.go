@@ -0,0 +1,348 @@
+package wormholetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RecordCassettesEnv is the environment variable that switches NewCassette
+// from replay to record mode. Set it to "1" while running tests with live
+// provider keys to refresh fixtures.
+const RecordCassettesEnv = "WORMHOLE_RECORD_CASSETTES"
+
+// redactedValue replaces credentials in recorded fixtures.
+const redactedValue = "REDACTED"
+
+// credentialHeaders are never written to fixtures.
+var credentialHeaders = []string{"Authorization", "X-Api-Key", "X-Goog-Api-Key", "Api-Key", "Openai-Organization", "Openai-Project"}
+
+// credentialQueryParams are stripped from recorded request URLs.
+var credentialQueryParams = []string{"key", "api_key"}
+
+// CassetteInteraction is one recorded HTTP request/response pair.
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is the redacted request used to match replays.
+type CassetteRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded provider response. Streaming responses are
+// stored whole and replayed in one write, which SSE parsers handle unchanged.
+type CassetteResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+type cassetteFile struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// Cassette records real provider HTTP traffic to a fixture file and replays it
+// deterministically, so integration tests run without live API keys.
+//
+// The cassette is a local HTTP server: point a provider's BaseURL at URL and
+// requests are forwarded to the upstream base URL (record mode) or answered
+// from the fixture (replay mode). Credential headers, credential query
+// parameters, and any credential values echoed in bodies are redacted before
+// the fixture is written.
+type Cassette struct {
+	// URL is the base URL to configure as the provider's BaseURL.
+	URL string
+
+	t         testing.TB
+	path      string
+	upstream  string
+	recording bool
+	server    *httptest.Server
+	client    *http.Client
+
+	mu           sync.Mutex
+	interactions []CassetteInteraction
+	used         []bool
+	secrets      map[string]struct{}
+}
+
+// NewCassette opens the fixture at path, recording against upstream when
+// RecordCassettesEnv is "1" and replaying otherwise. The server is closed and
+// (in record mode) the fixture written during t.Cleanup.
+//
+// Example:
+//
+//	cassette := wormholetest.NewCassette(t, "testdata/chat.json", "https://api.openai.com/v1")
+//	client := wormhole.New(
+//	    wormhole.WithOpenAI(os.Getenv("OPENAI_API_KEY"), types.ProviderConfig{BaseURL: cassette.URL}),
+//	)
+func NewCassette(t testing.TB, path, upstream string) *Cassette {
+	t.Helper()
+	if os.Getenv(RecordCassettesEnv) == "1" {
+		return RecordCassette(t, path, upstream)
+	}
+	return ReplayCassette(t, path)
+}
+
+// RecordCassette forwards every request to upstream and writes the redacted
+// interactions to path when the test finishes.
+func RecordCassette(t testing.TB, path, upstream string) *Cassette {
+	t.Helper()
+	c := &Cassette{
+		t:         t,
+		path:      path,
+		upstream:  strings.TrimRight(upstream, "/"),
+		recording: true,
+		client:    &http.Client{},
+		secrets:   make(map[string]struct{}),
+	}
+	c.start()
+	t.Cleanup(func() {
+		if err := c.save(); err != nil {
+			t.Errorf("write cassette %s: %v", path, err)
+		}
+	})
+	return c
+}
+
+// ReplayCassette serves the interactions stored at path. Each recorded
+// interaction answers at most one matching request; an unmatched request fails
+// the test and receives a 599 response.
+func ReplayCassette(t testing.TB, path string) *Cassette {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cassette %s: %v (set %s=1 to record it)", path, err, RecordCassettesEnv)
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("decode cassette %s: %v", path, err)
+	}
+	c := &Cassette{
+		t:            t,
+		path:         path,
+		interactions: file.Interactions,
+		used:         make([]bool, len(file.Interactions)),
+	}
+	c.start()
+	return c
+}
+
+// Recording reports whether the cassette forwards to the real upstream.
+func (c *Cassette) Recording() bool {
+	return c.recording
+}
+
+// Interactions returns a copy of the recorded or loaded interactions.
+func (c *Cassette) Interactions() []CassetteInteraction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]CassetteInteraction(nil), c.interactions...)
+}
+
+func (c *Cassette) start() {
+	c.server = httptest.NewServer(http.HandlerFunc(c.serve))
+	c.URL = c.server.URL
+	c.t.Cleanup(c.server.Close)
+}
+
+func (c *Cassette) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if c.recording {
+		c.record(w, r, body)
+		return
+	}
+	c.replay(w, r, body)
+}
+
+func (c *Cassette) record(w http.ResponseWriter, r *http.Request, body []byte) {
+	target := c.upstream + r.URL.Path
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	upstreamReq, err := http.NewRequestWithContext(r.Context(), r.Method, target, bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	upstreamReq.Header = r.Header.Clone()
+	// Let the transport negotiate compression so the recorded body is plain text.
+	upstreamReq.Header.Del("Accept-Encoding")
+
+	resp, err := c.client.Do(upstreamReq)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	c.mu.Lock()
+	c.collectSecrets(r)
+	c.interactions = append(c.interactions, CassetteInteraction{
+		Request:  c.redactRequest(r, body),
+		Response: CassetteResponse{Status: resp.StatusCode, Headers: recordedHeaders(resp.Header), Body: c.redact(string(respBody))},
+	})
+	c.mu.Unlock()
+
+	writeCassetteResponse(w, resp.StatusCode, recordedHeaders(resp.Header), respBody)
+}
+
+func (c *Cassette) replay(w http.ResponseWriter, r *http.Request, body []byte) {
+	want := CassetteRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  redactQuery(r.URL.Query()),
+		Body:   canonicalBody(body),
+	}
+
+	c.mu.Lock()
+	index := -1
+	for i, interaction := range c.interactions {
+		if !c.used[i] && requestsMatch(interaction.Request, want) {
+			index = i
+			c.used[i] = true
+			break
+		}
+	}
+	c.mu.Unlock()
+
+	if index < 0 {
+		c.t.Errorf("cassette %s: no recorded interaction for %s %s", c.path, r.Method, r.URL.Path)
+		http.Error(w, "no recorded interaction", 599)
+		return
+	}
+	recorded := c.interactions[index].Response
+	writeCassetteResponse(w, recorded.Status, recorded.Headers, []byte(recorded.Body))
+}
+
+func (c *Cassette) save() error {
+	c.mu.Lock()
+	file := cassetteFile{Interactions: append([]CassetteInteraction(nil), c.interactions...)}
+	c.mu.Unlock()
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o600)
+}
+
+// collectSecrets remembers credential values so they can be scrubbed from
+// bodies that echo them back. Callers must hold c.mu.
+func (c *Cassette) collectSecrets(r *http.Request) {
+	for _, header := range credentialHeaders {
+		value := strings.TrimSpace(strings.TrimPrefix(r.Header.Get(header), "Bearer "))
+		if len(value) >= 8 {
+			c.secrets[value] = struct{}{}
+		}
+	}
+	query := r.URL.Query()
+	for _, param := range credentialQueryParams {
+		if value := query.Get(param); len(value) >= 8 {
+			c.secrets[value] = struct{}{}
+		}
+	}
+}
+
+// redactRequest returns the stored form of a request. Callers must hold c.mu.
+func (c *Cassette) redactRequest(r *http.Request, body []byte) CassetteRequest {
+	return CassetteRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  redactQuery(r.URL.Query()),
+		Body:   c.redact(canonicalBody(body)),
+	}
+}
+
+// redact replaces known credential values. Callers must hold c.mu.
+func (c *Cassette) redact(s string) string {
+	for secret := range c.secrets {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	return s
+}
+
+func redactQuery(query url.Values) string {
+	for _, param := range credentialQueryParams {
+		if query.Has(param) {
+			query.Set(param, redactedValue)
+		}
+	}
+	return query.Encode()
+}
+
+// canonicalBody re-encodes JSON bodies so key order and whitespace do not
+// affect replay matching. Non-JSON bodies are kept verbatim.
+func canonicalBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return string(body)
+	}
+	canonical, err := json.Marshal(decoded)
+	if err != nil {
+		return string(body)
+	}
+	return string(canonical)
+}
+
+func requestsMatch(recorded, actual CassetteRequest) bool {
+	return recorded.Method == actual.Method &&
+		recorded.Path == actual.Path &&
+		recorded.Query == actual.Query &&
+		recorded.Body == actual.Body
+}
+
+func recordedHeaders(header http.Header) map[string]string {
+	keep := []string{"Content-Type", "Retry-After"}
+	out := make(map[string]string)
+	for _, key := range keep {
+		if value := header.Get(key); value != "" {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func writeCassetteResponse(w http.ResponseWriter, status int, headers map[string]string, body []byte) {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		w.Header().Set(key, headers[key])
+	}
+	w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
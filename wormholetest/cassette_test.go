@@ -0,0 +1,79 @@
+package wormholetest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	"github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+const cassetteAPIKey = "sk-cassette-secret-key"
+
+func cassetteClient(baseURL string) *wormhole.Wormhole {
+	return wormhole.New(
+		wormhole.WithOpenAI(cassetteAPIKey, types.ProviderConfig{BaseURL: baseURL}),
+		wormhole.WithDefaultProvider("openai"),
+		wormhole.WithModelValidation(false),
+	)
+}
+
+func TestCassetteRecordsAndReplays(t *testing.T) {
+	t.Parallel()
+
+	fixture := filepath.Join(t.TempDir(), "cassettes", "chat.json")
+	upstreamCalls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls++
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer "+cassetteAPIKey, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-4o-mini","choices":[{"index":0,"message":{"role":"assistant","content":"recorded hello"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`))
+	}))
+
+	t.Run("record", func(t *testing.T) {
+		cassette := wormholetest.RecordCassette(t, fixture, upstream.URL)
+		require.True(t, cassette.Recording())
+
+		resp, err := cassetteClient(cassette.URL).Text().Model("gpt-4o-mini").Prompt("hi").Generate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "recorded hello", resp.Text)
+		require.Len(t, cassette.Interactions(), 1)
+	})
+	upstream.Close()
+
+	data, err := os.ReadFile(fixture)
+	require.NoError(t, err)
+	assert.False(t, strings.Contains(string(data), cassetteAPIKey), "fixture must not contain the API key")
+
+	t.Run("replay", func(t *testing.T) {
+		cassette := wormholetest.ReplayCassette(t, fixture)
+		require.False(t, cassette.Recording())
+
+		resp, err := cassetteClient(cassette.URL).Text().Model("gpt-4o-mini").Prompt("hi").Generate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "recorded hello", resp.Text)
+		require.NotNil(t, resp.Usage)
+		assert.Equal(t, 5, resp.Usage.TotalTokens)
+	})
+	assert.Equal(t, 1, upstreamCalls)
+}
+
+func TestNewCassetteReplaysByDefault(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "chat.json")
+	require.NoError(t, os.WriteFile(fixture, []byte(`{"interactions":[]}`), 0o600))
+	t.Setenv(wormholetest.RecordCassettesEnv, "")
+
+	cassette := wormholetest.NewCassette(t, fixture, "https://api.openai.com/v1")
+	assert.False(t, cassette.Recording())
+	assert.Empty(t, cassette.Interactions())
+}
@@ -2,6 +2,8 @@ package wormholetest
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	stdtesting "testing"
 	"time"
 
@@ -16,9 +18,27 @@ type ProviderConformanceConfig struct {
 	EmbeddingsModel string
 	StreamModel     string
 	Timeout         time.Duration
+	// SkipUsage turns off the token usage checks, for backends that report
+	// no usage at all. Budgets and cost tracking see such a provider as free.
+	SkipUsage bool
+}
+
+// RunConformance runs the provider contract checks with default models and
+// timeouts. Call it from a custom provider's tests:
+//
+//	func TestConformance(t *testing.T) {
+//	    wormholetest.RunConformance(t, myprovider.New(config))
+//	}
+func RunConformance(t *stdtesting.T, provider types.Provider) {
+	t.Helper()
+	RunProviderConformance(t, ProviderConformanceConfig{Provider: provider})
 }
 
 // RunProviderConformance runs reusable contract checks for custom providers.
+// Besides a successful call for each advertised capability, it checks that
+// text responses report token usage, that calls under a canceled context fail
+// with an error wrapping context.Canceled, and that streams close once their
+// context is canceled.
 //
 //nolint:gocyclo // One table-like public conformance harness keeps provider contract failures in one place.
 func RunProviderConformance(t *stdtesting.T, cfg ProviderConformanceConfig) {
@@ -66,6 +86,16 @@ func RunProviderConformance(t *stdtesting.T, cfg ProviderConformanceConfig) {
 			if resp == nil || resp.Content() == "" {
 				t.Fatal("Text returned empty response")
 			}
+			if !cfg.SkipUsage {
+				if err := checkUsage(resp.Usage); err != nil {
+					t.Fatalf("Text %v", err)
+				}
+			}
+		})
+		t.Run("text canceled", func(t *stdtesting.T) {
+			if err := checkCanceledText(cfg); err != nil {
+				t.Fatal(err)
+			}
 		})
 	}
 	if caps[types.CapabilityStream] {
@@ -87,6 +117,11 @@ func RunProviderConformance(t *stdtesting.T, cfg ProviderConformanceConfig) {
 				t.Fatal("Stream returned no text")
 			}
 		})
+		t.Run("stream canceled", func(t *stdtesting.T) {
+			if err := checkCanceledStream(cfg); err != nil {
+				t.Fatal(err)
+			}
+		})
 	}
 	if caps[types.CapabilityStructured] {
 		t.Run("structured", func(t *stdtesting.T) {
@@ -128,6 +163,113 @@ func RunProviderConformance(t *stdtesting.T, cfg ProviderConformanceConfig) {
 	})
 }
 
+// checkUsage reports whether usage has the token counts budgets rely on.
+func checkUsage(usage *types.Usage) error {
+	if usage == nil {
+		return errors.New("returned no usage; set TextResponse.Usage from the provider's token counts")
+	}
+	if usage.PromptTokens < 0 || usage.CompletionTokens < 0 || usage.TotalTokens < 0 {
+		return fmt.Errorf("returned negative token counts: %+v", *usage)
+	}
+	if usage.TotalTokens == 0 && usage.PromptTokens+usage.CompletionTokens == 0 {
+		return errors.New("returned usage with no token counts")
+	}
+	if usage.TotalTokens > 0 && usage.TotalTokens < usage.PromptTokens+usage.CompletionTokens {
+		return fmt.Errorf("returned total_tokens %d below prompt plus completion tokens %d", usage.TotalTokens, usage.PromptTokens+usage.CompletionTokens)
+	}
+	return nil
+}
+
+// checkCanceledText calls Text under an already canceled context. It must
+// return promptly with an error that wraps context.Canceled, which is how
+// retries, fallbacks, and callers tell a cancellation from a provider failure.
+func checkCanceledText(cfg ProviderConformanceConfig) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := cfg.Provider.Text(ctx, types.TextRequest{
+			BaseRequest: types.BaseRequest{Model: cfg.TextModel},
+			Messages:    []types.Message{types.NewUserMessage("hello")},
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return checkCanceledError("Text", err)
+	case <-time.After(cfg.Timeout):
+		return fmt.Errorf("Text ignored its canceled context for %s", cfg.Timeout)
+	}
+}
+
+// checkCanceledStream checks that a stream whose context is canceled after
+// the first chunk closes its channel, and that a stream opened under a
+// canceled context fails or closes with an error wrapping context.Canceled.
+func checkCanceledStream(cfg ProviderConformanceConfig) error {
+	request := types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: cfg.StreamModel},
+		Messages:    []types.Message{types.NewUserMessage("hello")},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := cfg.Provider.Stream(ctx, request)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("Stream returned error for advertised capability: %w", err)
+	}
+	select {
+	case <-stream:
+	case <-time.After(cfg.Timeout):
+		cancel()
+		return fmt.Errorf("Stream sent no chunk within %s", cfg.Timeout)
+	}
+	cancel()
+	if _, closed := drainStream(stream, cfg.Timeout); !closed {
+		return fmt.Errorf("Stream channel still open %s after its context was canceled; close it when the context ends", cfg.Timeout)
+	}
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	stream, err = cfg.Provider.Stream(canceled, request)
+	if err != nil {
+		return checkCanceledError("Stream", err)
+	}
+	chunkErr, closed := drainStream(stream, cfg.Timeout)
+	if !closed {
+		return fmt.Errorf("Stream channel opened under a canceled context still open after %s", cfg.Timeout)
+	}
+	return checkCanceledError("Stream", chunkErr)
+}
+
+// drainStream reads stream until it closes or timeout passes, returning the
+// first chunk error and whether the channel closed.
+func drainStream(stream <-chan types.TextChunk, timeout time.Duration) (chunkErr error, closed bool) {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case chunk, ok := <-stream:
+			if !ok {
+				return chunkErr, true
+			}
+			if chunkErr == nil {
+				chunkErr = chunk.Error
+			}
+		case <-deadline:
+			return chunkErr, false
+		}
+	}
+}
+
+func checkCanceledError(method string, err error) error {
+	if err == nil {
+		return fmt.Errorf("%s succeeded under a canceled context", method)
+	}
+	if !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("%s error under a canceled context does not wrap context.Canceled (wrap with %%w): %v", method, err)
+	}
+	return nil
+}
+
 func capabilitySet(capabilities []types.ModelCapability) map[types.ModelCapability]bool {
 	set := make(map[types.ModelCapability]bool, len(capabilities))
 	for _, capability := range capabilities {
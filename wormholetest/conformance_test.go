@@ -1,10 +1,89 @@
 package wormholetest
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
 
 func TestRunProviderConformanceWithMockProvider(t *testing.T) {
 	t.Parallel()
+	response := TextResponseWith("hello")
+	response.Usage = &types.Usage{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4}
 	RunProviderConformance(t, ProviderConformanceConfig{
-		Provider: NewMockProvider("mock").WithTextResponse(TextResponseWith("hello")),
+		Provider: NewMockProvider("mock").WithTextResponse(response),
 	})
 }
+
+func TestRunConformanceWithDefaultMockProvider(t *testing.T) {
+	t.Parallel()
+	RunConformance(t, NewMockProvider("mock"))
+}
+
+func TestRunProviderConformanceSkipUsage(t *testing.T) {
+	t.Parallel()
+	RunProviderConformance(t, ProviderConformanceConfig{
+		Provider:  NewMockProvider("mock").WithTextResponse(TextResponseWith("hello")),
+		SkipUsage: true,
+	})
+}
+
+// sloppyProvider breaks the contract: it ignores cancellation, flattens
+// errors into strings, and never closes a canceled stream.
+type sloppyProvider struct {
+	*types.BaseProvider
+	flatten bool
+	block   chan struct{}
+}
+
+func (p *sloppyProvider) Text(ctx context.Context, _ types.TextRequest) (*types.TextResponse, error) {
+	if p.flatten && ctx.Err() != nil {
+		return nil, fmt.Errorf("request failed: %v", ctx.Err())
+	}
+	return &types.TextResponse{Text: "ok"}, nil
+}
+
+func (p *sloppyProvider) Stream(context.Context, types.TextRequest) (<-chan types.TextChunk, error) {
+	stream := make(chan types.TextChunk, 1)
+	stream <- types.TextChunk{Text: "ok"}
+	go func() {
+		<-p.block
+		close(stream)
+	}()
+	return stream, nil
+}
+
+func TestConformanceChecksCatchContractBreaks(t *testing.T) {
+	t.Parallel()
+
+	block := make(chan struct{})
+	defer close(block)
+	cfg := ProviderConformanceConfig{
+		Provider: &sloppyProvider{BaseProvider: types.NewBaseProvider("sloppy"), block: block},
+		Timeout:  20 * time.Millisecond,
+	}
+
+	assert.ErrorContains(t, checkCanceledText(cfg), "succeeded under a canceled context")
+	cfg.Provider = &sloppyProvider{BaseProvider: types.NewBaseProvider("sloppy"), flatten: true, block: block}
+	assert.ErrorContains(t, checkCanceledText(cfg), "does not wrap context.Canceled")
+	assert.ErrorContains(t, checkCanceledStream(cfg), "still open")
+
+	assert.ErrorContains(t, checkUsage(nil), "returned no usage")
+	assert.ErrorContains(t, checkUsage(&types.Usage{}), "no token counts")
+	assert.ErrorContains(t, checkUsage(&types.Usage{PromptTokens: 5, CompletionTokens: 5, TotalTokens: 3}), "below prompt plus completion")
+	assert.NoError(t, checkUsage(&types.Usage{PromptTokens: 5, CompletionTokens: 5}), "a missing total is derived")
+}
+
+func TestMockProviderHonorsCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewMockProvider("mock").Text(ctx, types.TextRequest{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,138 @@
+package wormholetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGoldenPayload
+// rewrite fixtures instead of comparing against them. Set it to "1" after an
+// intentional wire-format change and review the fixture diff.
+const UpdateGoldenEnv = "WORMHOLE_UPDATE_GOLDEN"
+
+// WirePayload is the provider HTTP request captured for a golden fixture.
+// Headers are not stored, so fixtures never contain credentials; credential
+// query parameters are redacted.
+type WirePayload struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// CapturePayload starts a local server, calls send with its base URL, and
+// returns the first request the provider made. The server answers with HTTP
+// 400, so send should configure the provider under test with that base URL,
+// issue one call, and ignore the resulting error.
+func CapturePayload(t testing.TB, send func(baseURL string)) WirePayload {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		captured *WirePayload
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		if captured == nil {
+			captured = &WirePayload{
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Query:  redactQuery(r.URL.Query()),
+				Body:   indentJSON(body),
+			}
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"golden payload captured"}}`))
+	}))
+	defer server.Close()
+
+	send(server.URL)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if captured == nil {
+		t.Fatalf("golden capture: provider made no HTTP request")
+		return WirePayload{}
+	}
+	return *captured
+}
+
+// AssertGoldenPayload captures the request produced by send (see
+// CapturePayload) and compares it with the fixture at path. JSON bodies are
+// compared structurally, so key order and whitespace do not matter. With
+// UpdateGoldenEnv set to "1" the fixture is rewritten instead.
+//
+// Example:
+//
+//	wormholetest.AssertGoldenPayload(t, "testdata/golden/chat_basic.json", func(baseURL string) {
+//	    p := openai.New(types.ProviderConfig{APIKey: "test", BaseURL: baseURL})
+//	    _, _ = p.Text(ctx, request)
+//	})
+func AssertGoldenPayload(t testing.TB, path string, send func(baseURL string)) {
+	t.Helper()
+	got := CapturePayload(t, send)
+	gotJSON := marshalGolden(t, got)
+
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, gotJSON, 0o600); err != nil {
+			t.Fatalf("write golden %s: %v", path, err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden %s: %v (set %s=1 to create it)", path, err, UpdateGoldenEnv)
+	}
+	var want WirePayload
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("decode golden %s: %v", path, err)
+	}
+	if !bytes.Equal(marshalGolden(t, want), gotJSON) {
+		t.Errorf("wire payload differs from %s (set %s=1 to accept)\n--- want\n%s--- got\n%s",
+			path, UpdateGoldenEnv, marshalGolden(t, want), gotJSON)
+	}
+}
+
+// marshalGolden renders p in the canonical fixture form: indented, with JSON
+// object keys sorted.
+func marshalGolden(t testing.TB, p WirePayload) []byte {
+	t.Helper()
+	p.Body = indentJSON(p.Body)
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		t.Fatalf("encode golden payload: %v", err)
+	}
+	return append(data, '\n')
+}
+
+// indentJSON sorts object keys by round-tripping through any. Non-JSON bodies
+// are stored as a JSON string so the fixture stays valid JSON.
+func indentJSON(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		quoted, _ := json.Marshal(string(body))
+		return quoted
+	}
+	canonical, err := json.Marshal(decoded)
+	if err != nil {
+		return json.RawMessage(body)
+	}
+	return canonical
+}
@@ -0,0 +1,59 @@
+package wormholetest
+
+import "github.com/garyblankenship/wormhole/v2/types"
+
+// GoldenTextRequests returns the canonical text requests the bundled provider
+// fixtures (providers/*/testdata/golden) are generated from, keyed by fixture
+// name. Every provider renders the same requests, so fixtures can be compared
+// across providers as well as across versions.
+func GoldenTextRequests(model string) map[string]types.TextRequest {
+	temperature := float32(0.5)
+	maxTokens := 256
+
+	weatherTool := types.NewTool("get_weather", "Get the current weather for a city", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"city": map[string]any{"type": "string"},
+		},
+		"required": []string{"city"},
+	})
+
+	return map[string]types.TextRequest{
+		"text_basic": {
+			BaseRequest: types.BaseRequest{
+				Model:       model,
+				Temperature: &temperature,
+				MaxTokens:   &maxTokens,
+				Stop:        []string{"END"},
+			},
+			SystemPrompt: "You are concise.",
+			Messages: []types.Message{
+				types.NewUserMessage("Summarize Go interfaces."),
+			},
+		},
+		"tool_round_trip": {
+			BaseRequest: types.BaseRequest{
+				Model:     model,
+				MaxTokens: &maxTokens,
+			},
+			Messages: []types.Message{
+				types.NewUserMessage("What's the weather in Paris?"),
+				&types.AssistantMessage{
+					ToolCalls: []types.ToolCall{{
+						ID:        "call_weather_1",
+						Type:      "function",
+						Name:      "get_weather",
+						Arguments: map[string]any{"city": "Paris"},
+					}},
+				},
+				&types.ToolResultMessage{
+					ToolCallID:   "call_weather_1",
+					FunctionName: "get_weather",
+					Content:      `{"temp_c":18,"sky":"clear"}`,
+				},
+			},
+			Tools:      []types.Tool{*weatherTool},
+			ToolChoice: &types.ToolChoice{Type: types.ToolChoiceTypeAuto},
+		},
+	}
+}
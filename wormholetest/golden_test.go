@@ -0,0 +1,57 @@
+package wormholetest_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func postJSON(t *testing.T, url, body string) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestCapturePayloadRedactsCredentials(t *testing.T) {
+	t.Parallel()
+
+	got := wormholetest.CapturePayload(t, func(baseURL string) {
+		postJSON(t, baseURL+"/models/m:generateContent?key=secret-key-123", `{"b":1,"a":[true]}`)
+	})
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "/models/m:generateContent", got.Path)
+	assert.Equal(t, "key=REDACTED", got.Query)
+	assert.JSONEq(t, `{"a":[true],"b":1}`, string(got.Body))
+}
+
+func TestAssertGoldenPayloadIgnoresKeyOrderAndWhitespace(t *testing.T) {
+	t.Parallel()
+
+	fixture := filepath.Join(t.TempDir(), "golden.json")
+	require.NoError(t, os.WriteFile(fixture, []byte(`{"path":"/chat","method":"POST","body":{"b":1,   "a":"x"}}`), 0o600))
+
+	wormholetest.AssertGoldenPayload(t, fixture, func(baseURL string) {
+		postJSON(t, baseURL+"/chat", `{"a":"x","b":1}`)
+	})
+}
+
+func TestGoldenTextRequestsAreStable(t *testing.T) {
+	t.Parallel()
+
+	requests := wormholetest.GoldenTextRequests("m")
+	require.Contains(t, requests, "text_basic")
+	require.Contains(t, requests, "tool_round_trip")
+	for name, request := range requests {
+		assert.Equal(t, "m", request.Model, name)
+		assert.NotEmpty(t, request.Messages, name)
+	}
+}
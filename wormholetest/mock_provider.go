@@ -24,6 +24,13 @@ type MockProvider struct {
 	errorMessage   string
 	rerankResponse *types.RerankResponse
 	imageResponse  *types.ImageResponse
+	scenario       []ScenarioRule
+	scenarioCalls  []int // matches per rule, for ErrorAfter
+	sequence       []types.TextResponse
+	sequenceIndex  int
+	latency        time.Duration
+	errorRate      float64
+	calls          []MockCall
 }
 
 // NewMockProvider creates a new mock provider
@@ -111,6 +118,22 @@ func (m *MockProvider) SupportedCapabilities() []types.ModelCapability {
 
 // Text returns a mocked text response
 func (m *MockProvider) Text(ctx context.Context, request types.TextRequest) (*types.TextResponse, error) {
+	if err := m.begin(ctx, "Text", request); err != nil {
+		return nil, err
+	}
+	if rule, ok, err := m.scenarioReply(ctx, request.Model, request.SystemPrompt, request.Messages); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &types.TextResponse{
+			ID:           "mock-scenario",
+			Model:        request.Model,
+			Text:         rule.Respond,
+			FinishReason: types.FinishReasonStop,
+			Created:      time.Now(),
+		}, nil
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -118,12 +141,17 @@ func (m *MockProvider) Text(ctx context.Context, request types.TextRequest) (*ty
 		return nil, errors.New(m.errorMessage)
 	}
 
+	if response, ok := m.nextInSequence(); ok {
+		return response, nil
+	}
+
 	if len(m.textResponses) == 0 {
 		return &types.TextResponse{
 			ID:           "mock-" + fmt.Sprint(time.Now().Unix()),
 			Model:        request.Model,
 			Text:         "Mock response",
 			FinishReason: types.FinishReasonStop,
+			Usage:        &types.Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12},
 			Created:      time.Now(),
 		}, nil
 	}
@@ -135,6 +163,29 @@ func (m *MockProvider) Text(ctx context.Context, request types.TextRequest) (*ty
 
 // Stream returns a mocked streaming response
 func (m *MockProvider) Stream(ctx context.Context, request types.TextRequest) (<-chan types.TextChunk, error) {
+	if err := m.begin(ctx, "Stream", request); err != nil {
+		return nil, err
+	}
+	if rule, ok, err := m.scenarioReply(ctx, request.Model, request.SystemPrompt, request.Messages); ok {
+		if err != nil {
+			return nil, err
+		}
+		texts := rule.Stream
+		if len(texts) == 0 {
+			texts = []string{rule.Respond}
+		}
+		chunks := StreamChunksFrom(texts...)
+		for i := range chunks {
+			chunks[i].Model = request.Model
+		}
+		stream := make(chan types.TextChunk, len(chunks))
+		for _, chunk := range chunks {
+			stream <- chunk
+		}
+		close(stream)
+		return stream, nil
+	}
+
 	m.mu.Lock()
 	if m.shouldError {
 		err := errors.New(m.errorMessage)
@@ -181,6 +232,21 @@ func (m *MockProvider) Stream(ctx context.Context, request types.TextRequest) (<
 
 // Structured returns a mocked structured response
 func (m *MockProvider) Structured(ctx context.Context, request types.StructuredRequest) (*types.StructuredResponse, error) {
+	if err := m.begin(ctx, "Structured", request); err != nil {
+		return nil, err
+	}
+	if rule, ok, err := m.scenarioReply(ctx, request.Model, request.SystemPrompt, request.Messages); ok {
+		if err != nil {
+			return nil, err
+		}
+		return &types.StructuredResponse{
+			ID:      "mock-scenario",
+			Model:   request.Model,
+			Data:    rule.Structured,
+			Created: time.Now(),
+		}, nil
+	}
+
 	m.mu.Lock()
 	shouldError := m.shouldError
 	errorMessage := m.errorMessage
@@ -207,6 +273,9 @@ func (m *MockProvider) Structured(ctx context.Context, request types.StructuredR
 
 // Embeddings returns mocked embeddings
 func (m *MockProvider) Embeddings(ctx context.Context, request types.EmbeddingsRequest) (*types.EmbeddingsResponse, error) {
+	if err := m.begin(ctx, "Embeddings", request); err != nil {
+		return nil, err
+	}
 	m.mu.Lock()
 	shouldError := m.shouldError
 	errorMessage := m.errorMessage
@@ -237,6 +306,9 @@ func (m *MockProvider) Embeddings(ctx context.Context, request types.EmbeddingsR
 
 // Audio returns a mocked audio response
 func (m *MockProvider) Audio(ctx context.Context, request types.AudioRequest) (*types.AudioResponse, error) {
+	if err := m.begin(ctx, "Audio", request); err != nil {
+		return nil, err
+	}
 	m.mu.Lock()
 	shouldError := m.shouldError
 	errorMessage := m.errorMessage
@@ -265,6 +337,9 @@ func (m *MockProvider) Audio(ctx context.Context, request types.AudioRequest) (*
 
 // Images returns a mocked images response
 func (m *MockProvider) Images(ctx context.Context, request types.ImagesRequest) (*types.ImagesResponse, error) {
+	if err := m.begin(ctx, "Images", request); err != nil {
+		return nil, err
+	}
 	m.mu.Lock()
 	shouldError := m.shouldError
 	errorMessage := m.errorMessage
@@ -286,6 +361,9 @@ func (m *MockProvider) Images(ctx context.Context, request types.ImagesRequest)
 
 // Rerank returns a mocked rerank response
 func (m *MockProvider) Rerank(ctx context.Context, request types.RerankRequest) (*types.RerankResponse, error) {
+	if err := m.begin(ctx, "Rerank", request); err != nil {
+		return nil, err
+	}
 	m.mu.Lock()
 	shouldError := m.shouldError
 	errorMessage := m.errorMessage
@@ -318,6 +396,9 @@ func (m *MockProvider) Rerank(ctx context.Context, request types.RerankRequest)
 
 // GenerateImage returns a mocked single image response
 func (m *MockProvider) GenerateImage(ctx context.Context, request types.ImageRequest) (*types.ImageResponse, error) {
+	if err := m.begin(ctx, "GenerateImage", request); err != nil {
+		return nil, err
+	}
 	m.mu.Lock()
 	shouldError := m.shouldError
 	errorMessage := m.errorMessage
@@ -0,0 +1,118 @@
+package wormholetest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// MockCall records one call to a MockProvider.
+type MockCall struct {
+	// Method is the Provider method called: "Text", "Stream", "Structured",
+	// "Embeddings", "Rerank", "Audio", "Images", or "GenerateImage".
+	Method string
+	// Request is the request value, such as a types.TextRequest.
+	Request any
+	// Time is when the call started.
+	Time time.Time
+}
+
+// WithResponseSequence makes Text return responses in order, one per call,
+// and keep returning the last one once the sequence is used up. Unlike
+// WithTextResponse, which cycles, a sequence scripts a conversation whose
+// later turns differ from its first.
+//
+// Example:
+//
+//	mock := wormholetest.NewMockProvider("openai").WithResponseSequence(
+//	    types.TextResponse{ToolCalls: []types.ToolCall{call}},
+//	    wormholetest.TextResponseWith("done"),
+//	)
+func (m *MockProvider) WithResponseSequence(responses ...types.TextResponse) *MockProvider {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sequence = append([]types.TextResponse(nil), responses...)
+	m.sequenceIndex = 0
+	return m
+}
+
+// WithLatency delays every call by d. A call whose context ends first
+// returns the context's error.
+func (m *MockProvider) WithLatency(d time.Duration) *MockProvider {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latency = d
+	return m
+}
+
+// WithErrorRate fails the fraction p of calls (0 to 1) with a retryable 503
+// provider error. Failures are spread evenly rather than drawn at random, so
+// a test sees the same calls fail on every run: with p = 0.2, calls 5, 10,
+// 15, ... fail.
+func (m *MockProvider) WithErrorRate(p float64) *MockProvider {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errorRate = math.Min(math.Max(p, 0), 1)
+	return m
+}
+
+// Calls returns every call made to the provider, oldest first.
+func (m *MockProvider) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+// LastTextRequest returns the request of the latest Text or Stream call, or
+// nil when there has been none.
+func (m *MockProvider) LastTextRequest() *types.TextRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.calls) - 1; i >= 0; i-- {
+		if request, ok := m.calls[i].Request.(types.TextRequest); ok {
+			return &request
+		}
+	}
+	return nil
+}
+
+// begin records a call, then applies the scripted latency, the context, and
+// the error rate, in that order.
+func (m *MockProvider) begin(ctx context.Context, method string, request any) error {
+	m.mu.Lock()
+	m.calls = append(m.calls, MockCall{Method: method, Request: request, Time: time.Now()})
+	n := float64(len(m.calls))
+	latency, rate := m.latency, m.errorRate
+	m.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if rate > 0 && math.Floor(n*rate) > math.Floor((n-1)*rate) {
+		return types.HTTPStatusToError(http.StatusServiceUnavailable, fmt.Sprintf("mock: injected error on call %d", int(n))).WithProvider(m.name)
+	}
+	return nil
+}
+
+// nextInSequence returns the next scripted response, if a sequence is set.
+// The caller holds m.mu.
+func (m *MockProvider) nextInSequence() (*types.TextResponse, bool) {
+	if len(m.sequence) == 0 {
+		return nil, false
+	}
+	response := m.sequence[min(m.sequenceIndex, len(m.sequence)-1)]
+	m.sequenceIndex++
+	return &response, true
+}
@@ -0,0 +1,87 @@
+package wormholetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+func textRequest(prompt string) types.TextRequest {
+	return types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "mock-model"},
+		Messages:    []types.Message{types.NewUserMessage(prompt)},
+	}
+}
+
+func TestMockProviderResponseSequence(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockProvider("mock").WithResponseSequence(TextResponseWith("first"), TextResponseWith("second"))
+	var texts []string
+	for range 3 {
+		resp, err := mock.Text(context.Background(), textRequest("hi"))
+		require.NoError(t, err)
+		texts = append(texts, resp.Text)
+	}
+	assert.Equal(t, []string{"first", "second", "second"}, texts, "the last response repeats")
+}
+
+func TestMockProviderLatency(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockProvider("mock").WithLatency(30 * time.Millisecond)
+	start := time.Now()
+	_, err := mock.Text(context.Background(), textRequest("hi"))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = mock.Stream(ctx, textRequest("hi"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMockProviderErrorRate(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockProvider("mock").WithErrorRate(0.25)
+	var failed []int
+	for call := 1; call <= 12; call++ {
+		if _, err := mock.Text(context.Background(), textRequest("hi")); err != nil {
+			failed = append(failed, call)
+			var wormholeErr *types.WormholeError
+			require.ErrorAs(t, err, &wormholeErr)
+			assert.True(t, wormholeErr.Retryable)
+			assert.Equal(t, "mock", wormholeErr.Provider)
+		}
+	}
+	assert.Equal(t, []int{4, 8, 12}, failed)
+}
+
+func TestMockProviderRecordsCalls(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockProvider("mock")
+	assert.Nil(t, mock.LastTextRequest())
+
+	_, err := mock.Text(context.Background(), textRequest("first"))
+	require.NoError(t, err)
+	_, err = mock.Stream(context.Background(), textRequest("second"))
+	require.NoError(t, err)
+	_, err = mock.Embeddings(context.Background(), types.EmbeddingsRequest{Model: "embed", Input: []string{"x"}})
+	require.NoError(t, err)
+
+	calls := mock.Calls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, []string{"Text", "Stream", "Embeddings"}, []string{calls[0].Method, calls[1].Method, calls[2].Method})
+	assert.Equal(t, "embed", calls[2].Request.(types.EmbeddingsRequest).Model)
+
+	last := mock.LastTextRequest()
+	require.NotNil(t, last)
+	assert.Equal(t, "second", last.Messages[0].GetContent())
+}
@@ -0,0 +1,157 @@
+package wormholetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/garyblankenship/wormhole/v2/internal/configfile"
+	"github.com/garyblankenship/wormhole/v2/types"
+)
+
+// Scenario declares mock provider behavior as data, so scenarios can be
+// written without Go and shared between tests and `wormhole serve
+// --mock-scenario`. Rules are tried in order; the first whose conditions
+// match a request decides the reply. Requests no rule matches get the mock
+// provider's ordinary responses.
+//
+// Example scenario.yaml (a JSON file with the same fields works too):
+//
+//	name: checkout-bot
+//	rules:
+//	  - match: refund
+//	    respond: Refunds take 5 days.
+//	  - match: stream me
+//	    stream: ["Once ", "upon ", "a time"]
+//	  - match: invoice
+//	    structured: {total: 42}
+//	  - match: flaky
+//	    respond: ok
+//	    error: overloaded
+//	    status: 503
+//	    error_after: 2
+type Scenario struct {
+	Name  string         `json:"name,omitempty" yaml:"name,omitempty"`
+	Rules []ScenarioRule `json:"rules" yaml:"rules"`
+}
+
+// ScenarioRule matches requests and describes the reply.
+type ScenarioRule struct {
+	// Match is a substring of the prompt (system prompt and message text).
+	// Empty matches every request.
+	Match string `json:"match,omitempty" yaml:"match,omitempty"`
+	// Model, when set, must equal the request's model.
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+
+	// Respond is the text reply. Streams without Stream chunks send it as
+	// one chunk.
+	Respond string `json:"respond,omitempty" yaml:"respond,omitempty"`
+	// Stream is the chunk sequence for streaming requests.
+	Stream []string `json:"stream,omitempty" yaml:"stream,omitempty"`
+	// Structured is the data for structured requests.
+	Structured any `json:"structured,omitempty" yaml:"structured,omitempty"`
+
+	// Error fails matching requests with this message, after ErrorAfter
+	// successful calls. Status, when set, makes it the typed error for that
+	// HTTP status, so retries and fallbacks treat it like a real failure.
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+	Status     int    `json:"status,omitempty" yaml:"status,omitempty"`
+	ErrorAfter int    `json:"error_after,omitempty" yaml:"error_after,omitempty"`
+
+	// DelayMS delays the reply, for timeout and latency tests.
+	DelayMS int `json:"delay_ms,omitempty" yaml:"delay_ms,omitempty"`
+}
+
+// LoadScenarioFile reads and decodes a scenario file: YAML for a .yaml or .yml
+// extension, JSON otherwise. Unknown fields are rejected so typos fail the
+// test instead of being ignored.
+func LoadScenarioFile(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read scenario file: %w", err)
+	}
+	var scenario Scenario
+	if err := configfile.Decode(path, data, &scenario); err != nil {
+		return nil, fmt.Errorf("scenario file %s: %w", path, err)
+	}
+	for i, rule := range scenario.Rules {
+		if rule.Structured == nil {
+			continue
+		}
+		// Hand structured data back the way a provider's JSON would decode,
+		// whichever format the file used (YAML reads 42 as an int).
+		encoded, err := json.Marshal(rule.Structured)
+		if err != nil {
+			return nil, fmt.Errorf("scenario file %s: rule %d: %w", path, i, err)
+		}
+		if err := json.Unmarshal(encoded, &scenario.Rules[i].Structured); err != nil {
+			return nil, fmt.Errorf("scenario file %s: rule %d: %w", path, i, err)
+		}
+	}
+	return &scenario, nil
+}
+
+// WithScenario makes the provider answer text, streaming and structured
+// requests from scenario's rules. Call counts for ErrorAfter start at zero.
+func (m *MockProvider) WithScenario(scenario Scenario) *MockProvider {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scenario = append([]ScenarioRule(nil), scenario.Rules...)
+	m.scenarioCalls = make([]int, len(scenario.Rules))
+	return m
+}
+
+// scenarioReply finds the first matching rule and counts the call. It
+// returns the rule and the error it produces; ok is false when none match.
+func (m *MockProvider) scenarioReply(ctx context.Context, model, systemPrompt string, messages []types.Message) (rule ScenarioRule, ok bool, err error) {
+	prompt := promptText(systemPrompt, messages)
+
+	m.mu.Lock()
+	for i, candidate := range m.scenario {
+		if candidate.Model != "" && candidate.Model != model {
+			continue
+		}
+		if !strings.Contains(prompt, candidate.Match) {
+			continue
+		}
+		m.scenarioCalls[i]++
+		rule, ok = candidate, true
+		if rule.Error != "" && m.scenarioCalls[i] > rule.ErrorAfter {
+			err = scenarioError(rule)
+		}
+		break
+	}
+	m.mu.Unlock()
+
+	if ok && rule.DelayMS > 0 {
+		select {
+		case <-time.After(time.Duration(rule.DelayMS) * time.Millisecond):
+		case <-ctx.Done():
+			return rule, true, ctx.Err()
+		}
+	}
+	return rule, ok, err
+}
+
+func scenarioError(rule ScenarioRule) error {
+	if rule.Status != 0 {
+		return types.HTTPStatusToError(rule.Status, rule.Error)
+	}
+	return errors.New(rule.Error)
+}
+
+func promptText(systemPrompt string, messages []types.Message) string {
+	var b strings.Builder
+	b.WriteString(systemPrompt)
+	for _, msg := range messages {
+		if text, ok := msg.GetContent().(string); ok {
+			b.WriteString("\n")
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
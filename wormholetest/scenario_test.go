@@ -0,0 +1,131 @@
+package wormholetest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2/types"
+	"github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+const checkoutScenario = `{
+  "name": "checkout-bot",
+  "rules": [
+    {"match": "refund", "respond": "Refunds take 5 days."},
+    {"match": "story", "stream": ["Once ", "upon ", "a time"]},
+    {"match": "invoice", "structured": {"total": 42}},
+    {"match": "flaky", "respond": "ok", "error": "overloaded", "status": 503, "error_after": 1}
+  ]
+}`
+
+func loadCheckoutScenario(t *testing.T) *wormholetest.MockProvider {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	require.NoError(t, os.WriteFile(path, []byte(checkoutScenario), 0o600))
+	scenario, err := wormholetest.LoadScenarioFile(path)
+	require.NoError(t, err)
+	return wormholetest.NewMockProvider("mock").WithScenario(*scenario)
+}
+
+func textRequest(prompt string) types.TextRequest {
+	return types.TextRequest{
+		BaseRequest: types.BaseRequest{Model: "m"},
+		Messages:    []types.Message{types.NewUserMessage(prompt)},
+	}
+}
+
+func TestScenarioMatchesPromptSubstrings(t *testing.T) {
+	t.Parallel()
+
+	mock := loadCheckoutScenario(t)
+	ctx := context.Background()
+
+	resp, err := mock.Text(ctx, textRequest("How do I get a refund?"))
+	require.NoError(t, err)
+	assert.Equal(t, "Refunds take 5 days.", resp.Text)
+
+	stream, err := mock.Stream(ctx, textRequest("Tell me a story"))
+	require.NoError(t, err)
+	var text string
+	for chunk := range stream {
+		text += chunk.Text
+	}
+	assert.Equal(t, "Once upon a time", text)
+
+	structured, err := mock.Structured(ctx, types.StructuredRequest{Messages: []types.Message{types.NewUserMessage("Parse this invoice")}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"total": float64(42)}, structured.Data)
+
+	resp, err = mock.Text(ctx, textRequest("unmatched"))
+	require.NoError(t, err)
+	assert.Equal(t, "Mock response", resp.Text, "unmatched requests fall through to the default reply")
+}
+
+func TestScenarioErrorAfterCalls(t *testing.T) {
+	t.Parallel()
+
+	mock := loadCheckoutScenario(t)
+	ctx := context.Background()
+
+	_, err := mock.Text(ctx, textRequest("flaky call"))
+	require.NoError(t, err)
+
+	_, err = mock.Text(ctx, textRequest("flaky call"))
+	require.Error(t, err)
+	assert.True(t, types.IsRetryableError(err), "status 503 must be retryable")
+}
+
+func TestLoadScenarioFileYAML(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "checkout.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name: checkout-bot
+rules:
+  - match: refund
+    respond: Refunds take 5 days.
+  - match: invoice
+    structured: {total: 42, lines: [{sku: A1}]}
+  - match: flaky
+    error: overloaded
+    status: 503
+`), 0o600))
+	scenario, err := wormholetest.LoadScenarioFile(path)
+	require.NoError(t, err)
+	require.Len(t, scenario.Rules, 3)
+	mock := wormholetest.NewMockProvider("mock").WithScenario(*scenario)
+	ctx := context.Background()
+
+	resp, err := mock.Text(ctx, textRequest("refund please"))
+	require.NoError(t, err)
+	assert.Equal(t, "Refunds take 5 days.", resp.Text)
+
+	structured, err := mock.Structured(ctx, types.StructuredRequest{Messages: []types.Message{types.NewUserMessage("invoice")}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"total": float64(42), "lines": []any{map[string]any{"sku": "A1"}}}, structured.Data,
+		"YAML data decodes like a JSON scenario's")
+
+	_, err = mock.Text(ctx, textRequest("flaky"))
+	assert.True(t, types.IsRetryableError(err))
+}
+
+func TestLoadScenarioFileRejectsUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	typo := filepath.Join(dir, "typo.json")
+	require.NoError(t, os.WriteFile(typo, []byte(`{"rules": [{"mach": "x"}]}`), 0o600))
+	_, err := wormholetest.LoadScenarioFile(typo)
+	assert.Error(t, err)
+
+	typoYAML := filepath.Join(dir, "typo.yml")
+	require.NoError(t, os.WriteFile(typoYAML, []byte("rules:\n  - mach: x\n"), 0o600))
+	_, err = wormholetest.LoadScenarioFile(typoYAML)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mach")
+}
@@ -0,0 +1,43 @@
+package wormhole_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/garyblankenship/wormhole/v2"
+	"github.com/garyblankenship/wormhole/v2/types"
+	mocktesting "github.com/garyblankenship/wormhole/v2/wormholetest"
+)
+
+func TestWithXAIDeferredCompletion(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer xai-key", r.Header.Get("Authorization"))
+		assert.Equal(t, "/chat/deferred-completion/req-1", r.URL.Path)
+		_, _ = w.Write([]byte(`{"id":"req-1","model":"grok-4","choices":[{"message":{"role":"assistant","content":"done"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	client := wormhole.New(
+		wormhole.WithXAI("xai-key", types.ProviderConfig{BaseURL: server.URL}),
+		wormhole.WithCustomProvider("mock", mocktesting.MockProviderFactory(mocktesting.NewMockProvider("mock"))),
+		wormhole.WithProviderConfig("mock", types.ProviderConfig{}),
+		wormhole.WithDiscovery(false),
+		wormhole.WithModelValidation(false),
+	)
+	defer client.Close()
+
+	resp, err := client.DeferredCompletion(context.Background(), "xai", "req-1")
+	require.NoError(t, err)
+	assert.Equal(t, "done", resp.Text)
+	assert.Equal(t, "xai", resp.Provider)
+
+	_, err = client.DeferredCompletion(context.Background(), "mock", "req-1")
+	assert.ErrorContains(t, err, "does not support deferred completions")
+}